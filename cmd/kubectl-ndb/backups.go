@@ -0,0 +1,106 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// formatBytes returns the given number of bytes in a human-readable form
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+
+	value := float64(bytes)
+	for _, suffix := range []string{"KiB", "MiB", "GiB", "TiB"} {
+		value /= unit
+		if value < unit || suffix == "TiB" {
+			return fmt.Sprintf("%.1f%s", value, suffix)
+		}
+	}
+	return ""
+}
+
+// runBackups runs the given backups subcommand
+func runBackups(args []string) error {
+	if len(args) == 0 || args[0] != "list" {
+		fmt.Fprintf(os.Stderr, "Usage: kubectl ndb backups list <ndbcluster-name> [flags]\n")
+		return errors.New("a valid backups subcommand is required")
+	}
+	return runBackupsList(args[1:])
+}
+
+// runBackupsList prints the completed backups of a MySQL Cluster,
+// as cataloged by the operator from the cluster log, in a table.
+func runBackupsList(args []string) error {
+	flags := flag.NewFlagSet("backups list", flag.ExitOnError)
+	cf := addClientFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: kubectl ndb backups list <ndbcluster-name> [flags]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	name, err := parseNdbClusterName(flags, args)
+	if err != nil {
+		return err
+	}
+
+	kubeClient, _, namespace, err := cf.newClients()
+	if err != nil {
+		return err
+	}
+
+	nc := &v1.NdbCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	catalog, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(
+		context.Background(), nc.GetBackupCatalogConfigMapName(), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			fmt.Printf("No backups of the NdbCluster %s/%s have been cataloged\n", namespace, name)
+			return nil
+		}
+		return err
+	}
+
+	backups, err := resources.ParseBackupCatalog(catalog)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "BACKUP ID\tCOMPLETED\tSTOP GCP\tRECORDS\tSIZE\tGENERATION\tVERIFICATION\tLOCATION")
+	for _, backup := range backups {
+		verification := string(backup.Verification)
+		if verification == "" {
+			verification = "-"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%s\t%d\t%s\t%s\n",
+			backup.BackupID, backup.CompletionTime, backup.StopGCP, backup.Records,
+			formatBytes(backup.DataBytes+backup.LogBytes), backup.NdbClusterGeneration, verification, backup.Location)
+	}
+	if err = tw.Flush(); err != nil {
+		return err
+	}
+
+	// Print the reasons for the failed verifications below the table
+	for _, backup := range backups {
+		if backup.Verification == resources.BackupVerificationFailed {
+			fmt.Printf("\nBackup %d failed the verification : %s\n", backup.BackupID, backup.VerificationMessage)
+		}
+	}
+	return nil
+}
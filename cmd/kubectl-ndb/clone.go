@@ -0,0 +1,410 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	clientset "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+	"github.com/mysql/ndb-operator/pkg/portforward"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+const (
+	// cloneBackupMountPath is the path at which the PersistentVolumeClaim
+	// collecting the backup is mounted in the clone helper pod
+	cloneBackupMountPath = "/backup"
+	// dataNodeContainerName is the name of the data node container
+	dataNodeContainerName = constants.NdbNodeTypeNdbmtd + "-container"
+)
+
+// listBackupFilesScript lists the files of the backup in the current
+// directory, including the ones in the PART subdirectories of the
+// backups taken by multiple threads, relative to the directory.
+const listBackupFilesScript = `
+for f in BACKUP-* BACKUP-*/*; do
+  [ -f "${f}" ] && echo "${f}"
+done
+true
+`
+
+// getCloneBackupPVCName returns the name of the PersistentVolumeClaim
+// into which the backup of the source NdbCluster is collected
+func getCloneBackupPVCName(dstName string, backupId int) string {
+	return fmt.Sprintf("%s-clone-backup-%d", dstName, backupId)
+}
+
+// getCloneBackupDir returns the directory, relative to the root of the
+// PersistentVolumeClaim, into which the backup is collected. It is the
+// default path in which the restore expects the backup files.
+func getCloneBackupDir(backupId int) string {
+	return fmt.Sprintf("BACKUP/BACKUP-%d", backupId)
+}
+
+// newCloneBackupPVC returns the PersistentVolumeClaim into which the
+// backup files of all the data nodes of the source NdbCluster are collected
+func newCloneBackupPVC(namespace, name, storageClassName string, size resource.Quantity) *corev1.PersistentVolumeClaim {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: size,
+				},
+			},
+		},
+	}
+	if storageClassName != "" {
+		pvc.Spec.StorageClassName = &storageClassName
+	}
+	return pvc
+}
+
+// newCloneHelperPod returns the pod that mounts the given PersistentVolumeClaim
+// to receive the backup files streamed from the data node pods
+func newCloneHelperPod(src *v1.NdbCluster, name, pvcName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: src.Namespace,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:            "clone-backup",
+					Image:           src.GetImage(constants.NdbNodeTypeNdbmtd),
+					ImagePullPolicy: src.Spec.ImagePullPolicy,
+					Command:         []string{"/bin/bash", "-c", "trap exit TERM; while true; do sleep 1; done"},
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      "backup",
+							MountPath: cloneBackupMountPath,
+						},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "backup",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: pvcName,
+						},
+					},
+				},
+			},
+			RestartPolicy:    corev1.RestartPolicyNever,
+			ImagePullSecrets: src.GetImagePullSecrets(),
+		},
+	}
+}
+
+// newCloneNdbCluster returns the NdbCluster that clones the given source
+// NdbCluster, with the same spec, initialised from the given backup. The
+// backup is decrypted with the encryption password of the source backups.
+func newCloneNdbCluster(src *v1.NdbCluster, dstName string, backupId int, pvcName string) *v1.NdbCluster {
+	dst := &v1.NdbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        dstName,
+			Namespace:   src.Namespace,
+			Labels:      src.Labels,
+			Annotations: map[string]string{},
+		},
+		Spec: *src.Spec.DeepCopy(),
+	}
+
+	// Keep only the annotations that do not request any operation on the source
+	for key, value := range src.Annotations {
+		if !strings.HasPrefix(key, v1.SchemeGroupVersion.Group+"/") &&
+			key != corev1.LastAppliedConfigAnnotation {
+			dst.Annotations[key] = value
+		}
+	}
+
+	// The data is restored from the backup
+	// instead of the initial data of the source
+	dst.Spec.InitFromDump = nil
+	dst.Spec.InitFromBackup = &v1.NdbClusterInitFromBackupSpec{
+		BackupID:                     int32(backupId),
+		PersistentVolumeClaimName:    pvcName,
+		EncryptionPasswordSecretName: src.GetBackupEncryptionPasswordSecretName(),
+	}
+	return dst
+}
+
+// execInPod runs the given command in a container of a pod, streaming the
+// given stdin into and the stdout of the command into the given writers.
+func execInPod(ctx context.Context, cfg *rest.Config, kubeClient kubernetes.Interface,
+	namespace, podName, containerName string, command []string, stdin io.Reader, stdout io.Writer) error {
+	request := kubeClient.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(namespace).Name(podName).SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: containerName,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    stdout != nil,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(cfg, "POST", request.URL())
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	if err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: &stderr,
+	}); err != nil {
+		return fmt.Errorf("command %q failed in pod %s/%s : %s %s",
+			strings.Join(command, " "), namespace, podName, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// copyBackupFiles copies the backup files of a data node pod,
+// from the given directory, into the clone helper pod.
+func copyBackupFiles(ctx context.Context, cfg *rest.Config, kubeClient kubernetes.Interface,
+	namespace, dataNodePodName, srcDir, helperPodName, dstDir string) (int, error) {
+
+	// List the backup files of the data node
+	var listing bytes.Buffer
+	if err := execInPod(ctx, cfg, kubeClient, namespace, dataNodePodName, dataNodeContainerName,
+		[]string{"/bin/bash", "-ec", "cd \"$1\"\n" + listBackupFilesScript, "-", srcDir}, nil, &listing); err != nil {
+		return 0, err
+	}
+
+	files := strings.Fields(listing.String())
+	for _, file := range files {
+		// Stream the file from the data node pod into the helper pod
+		reader, writer := io.Pipe()
+		readErr := make(chan error, 1)
+		go func(file string) {
+			err := execInPod(ctx, cfg, kubeClient, namespace, dataNodePodName, dataNodeContainerName,
+				[]string{"cat", path.Join(srcDir, file)}, nil, writer)
+			_ = writer.CloseWithError(err)
+			readErr <- err
+		}(file)
+
+		dstFile := path.Join(dstDir, file)
+		err := execInPod(ctx, cfg, kubeClient, namespace, helperPodName, "clone-backup",
+			[]string{"/bin/bash", "-ec", "mkdir -p \"$(dirname \"$1\")\" && cat > \"$1\"", "-", dstFile}, reader, nil)
+		_ = reader.CloseWithError(err)
+		srcErr := <-readErr
+		if err != nil {
+			return 0, err
+		}
+		if srcErr != nil {
+			return 0, srcErr
+		}
+	}
+
+	return len(files), nil
+}
+
+// startBackup takes an NDB native backup of the given NdbCluster, encrypted
+// as specified in its spec, and returns the id of the completed backup.
+func startBackup(ctx context.Context, cfg *rest.Config, kubeClient kubernetes.Interface, nc *v1.NdbCluster) (int, error) {
+	encryptionPassword := ""
+	if secretName := nc.GetBackupEncryptionPasswordSecretName(); secretName != "" {
+		secret, err := kubeClient.CoreV1().Secrets(nc.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+		if err != nil {
+			return 0, fmt.Errorf("failed to retrieve the backup encryption password : %s", err)
+		}
+		encryptionPassword = string(secret.Data[corev1.BasicAuthPasswordKey])
+	}
+
+	// The Management Server is addressed by the DNS names of its pods,
+	// which might not be reachable from outside the K8s Cluster.
+	dialer := portforward.NewDialer(cfg, kubeClient)
+	defer dialer.Stop()
+	mgmapi.SetDialer(dialer.DialContext)
+
+	mgmClient, err := mgmapi.NewMgmClientWithContext(ctx, nc.GetConnectstring())
+	if err != nil {
+		return 0, err
+	}
+	defer mgmClient.Disconnect()
+
+	return mgmClient.StartBackup(encryptionPassword)
+}
+
+// collectBackup collects the backup files of all the data nodes of the
+// given NdbCluster into a new PersistentVolumeClaim with the given name.
+func collectBackup(ctx context.Context, cfg *rest.Config, kubeClient kubernetes.Interface,
+	src *v1.NdbCluster, dstName string, backupId int, pvc *corev1.PersistentVolumeClaim) error {
+	namespace := src.Namespace
+	if _, err := kubeClient.CoreV1().PersistentVolumeClaims(namespace).Create(
+		ctx, pvc, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create the PersistentVolumeClaim %s/%s : %s", namespace, pvc.Name, err)
+	}
+
+	// Start a helper pod that mounts the PersistentVolumeClaim
+	helperPod := newCloneHelperPod(src, dstName+"-clone-helper", pvc.Name)
+	podInterface := kubeClient.CoreV1().Pods(namespace)
+	if _, err := podInterface.Create(ctx, helperPod, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create the pod %s/%s : %s", namespace, helperPod.Name, err)
+	}
+	defer func() {
+		// Use a new context as the given one might have expired
+		if err := podInterface.Delete(context.Background(), helperPod.Name, metav1.DeleteOptions{}); err != nil &&
+			!apierrors.IsNotFound(err) {
+			fmt.Printf("Warning: failed to delete the pod %s/%s : %s\n", namespace, helperPod.Name, err)
+		}
+	}()
+
+	if err := wait.PollImmediateUntilWithContext(ctx, time.Second, func(ctx context.Context) (bool, error) {
+		pod, err := podInterface.Get(ctx, helperPod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		switch pod.Status.Phase {
+		case corev1.PodRunning:
+			return true, nil
+		case corev1.PodFailed, corev1.PodSucceeded:
+			return false, fmt.Errorf("pod %s/%s has stopped", namespace, helperPod.Name)
+		}
+		return false, nil
+	}); err != nil {
+		return fmt.Errorf("failed to wait for the pod %s/%s to run : %s", namespace, helperPod.Name, err)
+	}
+
+	// Copy the backup files of every data node
+	srcDir := path.Join(src.GetDataNodeBackupDataDir(), getCloneBackupDir(backupId))
+	dstDir := path.Join(cloneBackupMountPath, getCloneBackupDir(backupId))
+	for i := int32(0); i < src.Spec.DataNode.NodeCount; i++ {
+		dataNodePodName := fmt.Sprintf("%s-%d", src.GetWorkloadName(constants.NdbNodeTypeNdbmtd), i)
+		numFiles, err := copyBackupFiles(ctx, cfg, kubeClient, namespace, dataNodePodName, srcDir, helperPod.Name, dstDir)
+		if err != nil {
+			return fmt.Errorf("failed to copy the backup files of the pod %s/%s : %s", namespace, dataNodePodName, err)
+		}
+		fmt.Printf("Copied %d backup files from the pod %s/%s\n", numFiles, namespace, dataNodePodName)
+	}
+
+	return nil
+}
+
+// parseCloneNames parses the given arguments with the flags, allowing the
+// flags to be specified after the names, and returns the names of the
+// source and the destination NdbCluster resources.
+func parseCloneNames(flags *flag.FlagSet, args []string) (string, string, error) {
+	var names []string
+	for len(args) > 0 && len(names) < 2 && len(args[0]) > 0 && args[0][0] != '-' {
+		names = append(names, args[0])
+		args = args[1:]
+	}
+	if err := flags.Parse(append(args, names...)); err != nil {
+		return "", "", err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return "", "", errors.New("names of the source and the destination NdbCluster resources are required")
+	}
+	return flags.Arg(0), flags.Arg(1), nil
+}
+
+// runClone clones an NdbCluster by taking a backup of it, collecting the
+// backup files of all its data nodes into a new PersistentVolumeClaim and
+// creating a new NdbCluster, with the same spec, that restores the backup.
+func runClone(args []string) error {
+	flags := flag.NewFlagSet("clone", flag.ExitOnError)
+	cf := addClientFlags(flags)
+	storageSize := flags.String("backup-storage-size", "10Gi",
+		"Size of the PersistentVolumeClaim into which the backup is collected")
+	storageClassName := flags.String("backup-storage-class", "",
+		"StorageClass of the PersistentVolumeClaim into which the backup is collected")
+	timeout := flags.Duration("timeout", time.Hour, "Time to wait for the backup to be taken and collected")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: kubectl ndb clone <source-ndbcluster> <destination-ndbcluster> [flags]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	srcName, dstName, err := parseCloneNames(flags, args)
+	if err != nil {
+		return err
+	}
+
+	size, err := resource.ParseQuantity(*storageSize)
+	if err != nil {
+		return fmt.Errorf("invalid --backup-storage-size %q : %s", *storageSize, err)
+	}
+
+	cfg, namespace, err := cf.restConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	ndbClient, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	ndbInterface := ndbClient.MysqlV1().NdbClusters(namespace)
+	src, err := ndbInterface.Get(ctx, srcName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if _, err = ndbInterface.Get(ctx, dstName, metav1.GetOptions{}); err == nil {
+		return fmt.Errorf("NdbCluster %s/%s already exists", namespace, dstName)
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	// Take a backup of the source
+	fmt.Printf("Taking a backup of the NdbCluster %s/%s...\n", namespace, srcName)
+	backupId, err := startBackup(ctx, cfg, kubeClient, src)
+	if err != nil {
+		return fmt.Errorf("failed to take a backup of the NdbCluster %s/%s : %s", namespace, srcName, err)
+	}
+	fmt.Printf("Backup %d has been completed\n", backupId)
+
+	// Collect the backup files of all the data nodes
+	pvcName := getCloneBackupPVCName(dstName, backupId)
+	if err = collectBackup(ctx, cfg, kubeClient, src, dstName, backupId,
+		newCloneBackupPVC(namespace, pvcName, *storageClassName, size)); err != nil {
+		return err
+	}
+
+	// Create the clone
+	if _, err = ndbInterface.Create(
+		ctx, newCloneNdbCluster(src, dstName, backupId, pvcName), metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create the NdbCluster %s/%s : %s", namespace, dstName, err)
+	}
+
+	fmt.Printf("NdbCluster %s/%s has been created from the backup %d of the NdbCluster %s/%s.\n"+
+		"The PersistentVolumeClaim %s/%s holding the backup can be deleted once it is restored.\n",
+		namespace, dstName, backupId, namespace, srcName, namespace, pvcName)
+	return nil
+}
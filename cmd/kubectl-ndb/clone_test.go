@@ -0,0 +1,91 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package main
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+)
+
+func Test_parseCloneNames(t *testing.T) {
+	for _, tc := range []struct {
+		args          []string
+		expectedSrc   string
+		expectedDst   string
+		expectedFlag  string
+		expectedError bool
+	}{
+		{args: []string{"src", "dst"}, expectedSrc: "src", expectedDst: "dst"},
+		{args: []string{"src", "dst", "-n", "ns"}, expectedSrc: "src", expectedDst: "dst", expectedFlag: "ns"},
+		{args: []string{"-n", "ns", "src", "dst"}, expectedSrc: "src", expectedDst: "dst", expectedFlag: "ns"},
+		{args: []string{"src"}, expectedError: true},
+		{args: []string{"src", "dst", "extra"}, expectedError: true},
+	} {
+		flags := flag.NewFlagSet("clone", flag.ContinueOnError)
+		flags.Usage = func() {}
+		namespace := flags.String("n", "", "")
+
+		src, dst, err := parseCloneNames(flags, tc.args)
+		if tc.expectedError {
+			if err == nil {
+				t.Errorf("%v : expected an error but got none", tc.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v : unexpected error : %s", tc.args, err)
+			continue
+		}
+		if src != tc.expectedSrc || dst != tc.expectedDst || *namespace != tc.expectedFlag {
+			t.Errorf("%v : unexpected result src=%q dst=%q namespace=%q", tc.args, src, dst, *namespace)
+		}
+	}
+}
+
+func Test_newCloneNdbCluster(t *testing.T) {
+	src := testutils.NewTestNdb("ns", "src", 2)
+	src.Labels = map[string]string{"app": "example"}
+	src.Annotations = map[string]string{
+		"team":                       "db",
+		v1.StopReconcilingAnnotation: "true",
+	}
+	src.Spec.InitFromDump = &v1.NdbClusterInitFromDumpSpec{}
+	src.Spec.InitFromBackup = &v1.NdbClusterInitFromBackupSpec{BackupID: 1, PersistentVolumeClaimName: "old"}
+	src.Spec.Backup = &v1.NdbBackupSpec{EncryptionPasswordSecretName: "backup-password"}
+
+	pvcName := getCloneBackupPVCName("dst", 5)
+	dst := newCloneNdbCluster(src, "dst", 5, pvcName)
+	if dst.Name != "dst" || dst.Namespace != "ns" {
+		t.Errorf("Unexpected clone %s/%s", dst.Namespace, dst.Name)
+	}
+	if !reflect.DeepEqual(dst.Labels, src.Labels) {
+		t.Errorf("Expected the labels of the source but got %v", dst.Labels)
+	}
+	if !reflect.DeepEqual(dst.Annotations, map[string]string{"team": "db"}) {
+		t.Errorf("Expected only the annotations not requesting any operation but got %v", dst.Annotations)
+	}
+	if dst.Spec.InitFromDump != nil {
+		t.Error("Expected the initFromDump of the source to be removed")
+	}
+	expectedInitFromBackup := &v1.NdbClusterInitFromBackupSpec{
+		BackupID:                     5,
+		PersistentVolumeClaimName:    "dst-clone-backup-5",
+		EncryptionPasswordSecretName: "backup-password",
+	}
+	if !reflect.DeepEqual(dst.Spec.InitFromBackup, expectedInitFromBackup) {
+		t.Errorf("Expected initFromBackup %v but got %v", expectedInitFromBackup, dst.Spec.InitFromBackup)
+	}
+	if dst.Spec.DataNode.NodeCount != src.Spec.DataNode.NodeCount ||
+		dst.Spec.MysqlNode.NodeCount != src.Spec.MysqlNode.NodeCount {
+		t.Error("Expected the node counts of the source")
+	}
+	if src.Spec.InitFromBackup.BackupID != 1 {
+		t.Error("The spec of the source was modified")
+	}
+}
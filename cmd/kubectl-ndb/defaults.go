@@ -0,0 +1,92 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ndbClusterCRD is the NdbCluster CRD, copied from the helm chart by
+// hack/generate-manifests.sh. Its schema has the defaults that the K8s
+// Server applies to the NdbCluster resources.
+//
+//go:embed crds/mysql.oracle.com_ndbclusters.yaml
+var ndbClusterCRD []byte
+
+// getNdbClusterSchema returns the OpenAPI schema of the v1 NdbCluster from the CRD
+func getNdbClusterSchema() (map[string]interface{}, error) {
+	var crd struct {
+		Spec struct {
+			Versions []struct {
+				Name   string `json:"name"`
+				Schema struct {
+					OpenAPIV3Schema map[string]interface{} `json:"openAPIV3Schema"`
+				} `json:"schema"`
+			} `json:"versions"`
+		} `json:"spec"`
+	}
+	if err := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(ndbClusterCRD), 4096).Decode(&crd); err != nil {
+		return nil, fmt.Errorf("failed to decode the NdbCluster CRD : %s", err)
+	}
+
+	for _, version := range crd.Spec.Versions {
+		if version.Name == v1.SchemeGroupVersion.Version {
+			return version.Schema.OpenAPIV3Schema, nil
+		}
+	}
+	return nil, fmt.Errorf("the NdbCluster CRD does not have the %q version", v1.SchemeGroupVersion.Version)
+}
+
+// applySchemaDefaults sets the defaults of the given schema on the fields
+// missing from the given object, and on the fields of the nested objects
+// and the arrays of objects, like the K8s Server does when it stores a
+// custom resource.
+func applySchemaDefaults(obj map[string]interface{}, schema map[string]interface{}) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	for name, property := range properties {
+		propertySchema, _ := property.(map[string]interface{})
+		value, exists := obj[name]
+		if !exists {
+			defaultValue, hasDefault := propertySchema["default"]
+			if !hasDefault {
+				continue
+			}
+			value = runtime.DeepCopyJSONValue(defaultValue)
+			obj[name] = value
+		}
+
+		switch value := value.(type) {
+		case map[string]interface{}:
+			applySchemaDefaults(value, propertySchema)
+		case []interface{}:
+			itemSchema, _ := propertySchema["items"].(map[string]interface{})
+			for _, item := range value {
+				if itemObj, isObj := item.(map[string]interface{}); isObj {
+					applySchemaDefaults(itemObj, itemSchema)
+				}
+			}
+		}
+	}
+}
+
+// applyNdbClusterDefaults sets the defaults of the NdbCluster CRD on the
+// fields omitted from the given NdbCluster object, so that the configs are
+// rendered from the same spec that the operator would receive.
+func applyNdbClusterDefaults(obj map[string]interface{}) error {
+	schema, err := getNdbClusterSchema()
+	if err != nil {
+		return err
+	}
+
+	applySchemaDefaults(obj, schema)
+	return nil
+}
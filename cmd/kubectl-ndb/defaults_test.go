@@ -0,0 +1,68 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestEmbeddedNdbClusterCRD(t *testing.T) {
+	chartCRD, err := os.ReadFile("../../deploy/charts/ndb-operator/crds/mysql.oracle.com_ndbclusters.yaml")
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	if !bytes.Equal(chartCRD, ndbClusterCRD) {
+		t.Error("The embedded NdbCluster CRD is outdated. Run hack/generate-manifests.sh to update it.")
+	}
+}
+
+func Test_readNdbCluster(t *testing.T) {
+	manifest := filepath.Join(t.TempDir(), "ndb.yaml")
+	if err := os.WriteFile(manifest, []byte(`
+apiVersion: mysql.oracle.com/v1
+kind: NdbCluster
+metadata:
+  name: example-ndb
+spec:
+  dataNode:
+    nodeCount: 2
+  mysqlNode:
+    nodeCount: 2
+    rootHost: localhost
+`), 0o600); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	nc, err := readNdbCluster(manifest)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	// The omitted fields should have the defaults of the CRD
+	if nc.Spec.RedundancyLevel != 2 {
+		t.Errorf("Expected the default redundancyLevel 2 but got %d", nc.Spec.RedundancyLevel)
+	}
+	if nc.Spec.FreeAPISlots != 2 {
+		t.Errorf("Expected the default freeAPISlots 2 but got %d", nc.Spec.FreeAPISlots)
+	}
+	if nc.Spec.ImagePullPolicy != corev1.PullIfNotPresent {
+		t.Errorf("Expected the default imagePullPolicy %q but got %q", corev1.PullIfNotPresent, nc.Spec.ImagePullPolicy)
+	}
+
+	// The specified fields should be retained
+	if nc.Spec.DataNode.NodeCount != 2 || nc.Spec.MysqlNode.NodeCount != 2 {
+		t.Errorf("Expected the specified node counts to be retained but got %d data nodes and %d MySQL Servers",
+			nc.Spec.DataNode.NodeCount, nc.Spec.MysqlNode.NodeCount)
+	}
+	if nc.Spec.MysqlNode.RootHost != "localhost" {
+		t.Errorf("Expected the specified rootHost to be retained but got %q", nc.Spec.MysqlNode.RootHost)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// patchAnnotations merge patches the annotations of the given NdbCluster.
+// An annotation with a nil value is removed.
+func patchAnnotations(ctx context.Context, cf *clientFlags, name string, annotations map[string]*string) error {
+	ndbClient, namespace, err := cf.newClient()
+	if err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = ndbClient.MysqlV1().NdbClusters(namespace).Patch(
+		ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// runDump requests the operator to dump its view of a MySQL Cluster
+// into a ConfigMap, waits for the dump and prints it. The reconciliation
+// of the MySQL Cluster is also stopped if requested.
+func runDump(args []string) error {
+	flags := flag.NewFlagSet("dump", flag.ExitOnError)
+	cf := addClientFlags(flags)
+	stopReconciling := flags.Bool("stop-reconciling", false,
+		"Stop the reconciliation of the MySQL Cluster until it is resumed via the resume command")
+	timeout := flags.Duration("timeout", 2*time.Minute, "Time to wait for the operator to dump the state")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: kubectl ndb dump <ndbcluster-name> [flags]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	name, err := parseNdbClusterName(flags, args)
+	if err != nil {
+		return err
+	}
+
+	kubeClient, _, namespace, err := cf.newClients()
+	if err != nil {
+		return err
+	}
+
+	// Every new value of the annotation is a new dump request
+	ctx := context.Background()
+	request := time.Now().UTC().Format(time.RFC3339Nano)
+	annotations := map[string]*string{
+		v1.DumpStateAnnotation: &request,
+	}
+	if *stopReconciling {
+		stopped := "true"
+		annotations[v1.StopReconcilingAnnotation] = &stopped
+	}
+	if err = patchAnnotations(ctx, cf, name, annotations); err != nil {
+		return err
+	}
+
+	// Wait for the operator to serve the request
+	configMapName := name + "-state-dump"
+	var data map[string]string
+	if err = wait.PollImmediate(time.Second, *timeout, func() (bool, error) {
+		cm, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, configMapName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		data = cm.Data
+		return data["request"] == request, nil
+	}); err != nil {
+		return fmt.Errorf("failed to retrieve the state dump from the ConfigMap %s/%s : %s",
+			namespace, configMapName, err)
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("--- %s\n%s\n", key, data[key])
+	}
+
+	if *stopReconciling {
+		fmt.Printf("\nReconciliation of the NdbCluster %s/%s has been stopped. "+
+			"Run 'kubectl ndb resume %s' to resume it.\n", namespace, name, name)
+	}
+	return nil
+}
+
+// runResume resumes the reconciliation of a MySQL Cluster
+// by removing the annotation that stopped it.
+func runResume(args []string) error {
+	flags := flag.NewFlagSet("resume", flag.ExitOnError)
+	cf := addClientFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: kubectl ndb resume <ndbcluster-name> [flags]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	name, err := parseNdbClusterName(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if err = patchAnnotations(context.Background(), cf, name, map[string]*string{
+		v1.StopReconcilingAnnotation: nil,
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Reconciliation of the NdbCluster %s has been resumed\n", name)
+	return nil
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// kubectl-ndb is a kubectl plugin to manage the MySQL Clusters
+// run by the NDB Operator. Install it in the PATH and run it as
+// 'kubectl ndb <command>'.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	clientset "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// command is a kubectl ndb command
+type command struct {
+	// description is a one line description of the command
+	description string
+	// run runs the command with the given arguments
+	run func(args []string) error
+}
+
+var commands = map[string]command{
+	"restart": {
+		description: "Request a rolling restart of the MySQL Cluster nodes of a type",
+		run:         runRestart,
+	},
+	"dump": {
+		description: "Dump the operator's view of a MySQL Cluster, optionally stopping its reconciliation",
+		run:         runDump,
+	},
+	"render": {
+		description: "Render the configs the operator would generate for an NdbCluster manifest, without applying it",
+		run:         runRender,
+	},
+	"resume": {
+		description: "Resume the reconciliation of a MySQL Cluster stopped by the dump command",
+		run:         runResume,
+	},
+	"backups": {
+		description: "List the completed backups of a MySQL Cluster, as cataloged by the operator",
+		run:         runBackups,
+	},
+	"clone": {
+		description: "Clone a MySQL Cluster into a new NdbCluster restored from a backup of it",
+		run:         runClone,
+	},
+	"topology": {
+		description: "Show the node groups, pods, K8s worker nodes and zones of the MySQL Cluster nodes",
+		run:         runTopology,
+	},
+}
+
+// usage prints the usage of the plugin
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: kubectl ndb <command> [flags]\n\nCommands:\n")
+	for name, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, cmd.description)
+	}
+}
+
+// clientFlags are the flags to connect to the K8s Cluster
+type clientFlags struct {
+	kubeconfig string
+	namespace  string
+}
+
+// addClientFlags adds the flags to connect to the K8s Cluster to the given FlagSet
+func addClientFlags(flags *flag.FlagSet) *clientFlags {
+	cf := &clientFlags{}
+	flags.StringVar(&cf.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file")
+	flags.StringVar(&cf.namespace, "namespace", "", "Namespace of the NdbCluster resource")
+	flags.StringVar(&cf.namespace, "n", "", "Namespace of the NdbCluster resource (shorthand)")
+	return cf
+}
+
+// restConfig returns the config to connect to the K8s Cluster and the namespace to be used
+func (cf *clientFlags) restConfig() (*rest.Config, string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = cf.kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules, &clientcmd.ConfigOverrides{Context: clientcmdapi.Context{Namespace: cf.namespace}})
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, "", err
+	}
+
+	cfg, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return cfg, namespace, nil
+}
+
+// newClient returns a new NdbCluster clientset and the namespace to be used
+func (cf *clientFlags) newClient() (clientset.Interface, string, error) {
+	cfg, namespace, err := cf.restConfig()
+	if err != nil {
+		return nil, "", err
+	}
+
+	ndbClient, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return ndbClient, namespace, nil
+}
+
+// newClients returns a new K8s clientset, a new NdbCluster
+// clientset and the namespace to be used
+func (cf *clientFlags) newClients() (kubernetes.Interface, clientset.Interface, string, error) {
+	cfg, namespace, err := cf.restConfig()
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	ndbClient, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return kubeClient, ndbClient, namespace, nil
+}
+
+// parseNdbClusterName parses the given arguments with the flags, allowing
+// the flags to be specified after the name of the NdbCluster resource,
+// and returns the name.
+func parseNdbClusterName(flags *flag.FlagSet, args []string) (string, error) {
+	if len(args) > 0 && len(args[0]) > 0 && args[0][0] != '-' {
+		args = append(args[1:], args[0])
+	}
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return "", errors.New("name of the NdbCluster resource is required")
+	}
+	return flags.Arg(0), nil
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	cmd, exists := commands[os.Args[1]]
+	if !exists {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(1)
+	}
+
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
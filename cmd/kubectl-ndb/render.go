@@ -0,0 +1,199 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// readNdbCluster reads the NdbCluster resource from the given YAML or JSON
+// manifest and sets the defaults of the CRD on the fields omitted from it.
+func readNdbCluster(manifest string) (*v1.NdbCluster, error) {
+	file, err := os.Open(manifest)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var obj map[string]interface{}
+	if err = yaml.NewYAMLOrJSONDecoder(file, 4096).Decode(&obj); err != nil {
+		return nil, fmt.Errorf("failed to decode the NdbCluster from %q : %s", manifest, err)
+	}
+	if obj["kind"] != "NdbCluster" {
+		return nil, fmt.Errorf("%q does not have an NdbCluster resource", manifest)
+	}
+
+	// Apply the defaults of the CRD to the fields
+	// omitted from the manifest, like the K8s Server
+	if err = applyNdbClusterDefaults(obj); err != nil {
+		return nil, err
+	}
+
+	ncJSON, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	nc := &v1.NdbCluster{}
+	if err = json.Unmarshal(ncJSON, nc); err != nil {
+		return nil, fmt.Errorf("failed to decode the NdbCluster from %q : %s", manifest, err)
+	}
+	return nc, nil
+}
+
+// getExistingConfigMap returns the given NdbCluster and its config map
+// if the NdbCluster already exists in the K8s Cluster, or nil otherwise.
+func getExistingConfigMap(ctx context.Context, cf *clientFlags, nc *v1.NdbCluster) (
+	*v1.NdbCluster, *corev1.ConfigMap, error) {
+	kubeClient, ndbClient, namespace, err := cf.newClients()
+	if err != nil {
+		return nil, nil, err
+	}
+	if nc.Namespace == "" {
+		nc.Namespace = namespace
+	}
+
+	existingNc, err := ndbClient.MysqlV1().NdbClusters(nc.Namespace).Get(ctx, nc.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// A new NdbCluster
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	cm, err := kubeClient.CoreV1().ConfigMaps(nc.Namespace).Get(ctx, existingNc.GetConfigMapName(), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The operator is yet to create the config
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	return existingNc, cm, nil
+}
+
+// runRender renders the MySQL Cluster config (config.ini) and the MySQL
+// Server configs (my.cnf) that the operator would generate for the
+// NdbCluster in the given manifest, without applying anything. If the
+// NdbCluster already exists, the configs are rendered as an update to
+// its current config, and the changes are reported.
+func runRender(args []string) error {
+	flags := flag.NewFlagSet("render", flag.ExitOnError)
+	cf := addClientFlags(flags)
+	manifest := flags.String("f", "", "Path to the YAML or JSON manifest of the NdbCluster")
+	offline := flags.Bool("offline", false,
+		"Render the configs for a new NdbCluster without connecting to the K8s Cluster")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: kubectl ndb render -f <manifest> [flags]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *manifest == "" {
+		flags.Usage()
+		return errors.New("path to the manifest of the NdbCluster is required")
+	}
+
+	nc, err := readNdbCluster(*manifest)
+	if err != nil {
+		return err
+	}
+
+	var existingNc *v1.NdbCluster
+	var existingCm *corev1.ConfigMap
+	if *offline {
+		if nc.Namespace == "" {
+			nc.Namespace = cf.namespace
+		}
+		if nc.Namespace == "" {
+			nc.Namespace = metav1.NamespaceDefault
+		}
+	} else {
+		if existingNc, existingCm, err = getExistingConfigMap(context.Background(), cf, nc); err != nil {
+			return err
+		}
+	}
+
+	var cm *corev1.ConfigMap
+	if existingCm == nil {
+		if isValid, errList := nc.HasValidSpec(); !isValid {
+			return errList.ToAggregate()
+		}
+		if cm = resources.CreateConfigMap(nc); cm == nil {
+			return errors.New("failed to generate the config for the NdbCluster")
+		}
+	} else {
+		if isValid, errList := existingNc.IsValidSpecUpdate(nc); !isValid {
+			return errList.ToAggregate()
+		}
+		oldConfigSummary, err := ndbconfig.NewConfigSummary(existingCm.Data)
+		if err != nil {
+			return err
+		}
+		// Render the configs as the next generation of the existing NdbCluster
+		nc.Generation = existingNc.Generation + 1
+		nc.Status = existingNc.Status
+		if cm = resources.GetUpdatedConfigMap(nc, existingCm, oldConfigSummary); cm == nil {
+			return errors.New("failed to generate the config for the NdbCluster")
+		}
+	}
+
+	configKeys := []string{constants.ConfigIniKey, constants.MySQLConfigKey}
+	for _, serverGroup := range nc.GetMySQLServerGroups() {
+		configKeys = append(configKeys, ndbconfig.GetMySQLServerGroupConfigKey(serverGroup.Name))
+	}
+	for _, key := range configKeys {
+		config, exists := cm.Data[key]
+		if !exists || config == "" {
+			continue
+		}
+
+		state := ""
+		if existingCm != nil {
+			switch existingConfig, existed := existingCm.Data[key]; {
+			case !existed || existingConfig == "":
+				state = " (new)"
+			case existingConfig == config:
+				state = " (unchanged)"
+			default:
+				state = " (changed)"
+			}
+		}
+		fmt.Printf("### %s%s\n%s\n", key, state, config)
+	}
+
+	if existingCm != nil {
+		if changes, err := ndbconfig.GetConfigChanges(existingCm.Data, cm.Data); err != nil {
+			return err
+		} else if len(changes) != 0 {
+			fmt.Printf("### Changes\n%s\n\n", strings.Join(changes, "\n"))
+		}
+	}
+
+	if existingCm != nil && existingCm.Data[constants.ConfigIniKey] != cm.Data[constants.ConfigIniKey] {
+		fmt.Printf("Applying the new config.ini requires a %s of the data nodes\n",
+			cm.Data[constants.DataNodeRestartType])
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// getRestartAnnotation returns the NdbCluster annotation that requests
+// the given type of restart of the nodes, or of the data node with the
+// given node id if it is not 0.
+func getRestartAnnotation(nodeType string, initial bool, nodeId int) (string, error) {
+	if nodeId != 0 {
+		if nodeType != constants.NdbNodeTypeNdbmtd || initial {
+			return "", fmt.Errorf("--node-id can only be used to restart a single data node")
+		}
+		if nodeId < 0 {
+			return "", fmt.Errorf("invalid node id %d", nodeId)
+		}
+		return fmt.Sprintf("%s%d", v1.DataNodeRestartAnnotationPrefix, nodeId), nil
+	}
+
+	switch nodeType {
+	case constants.NdbNodeTypeMgmd, constants.NdbNodeTypeMySQLD:
+		if initial {
+			return "", fmt.Errorf("only the data nodes can be restarted with --initial")
+		}
+	case constants.NdbNodeTypeNdbmtd:
+		if initial {
+			return v1.InitialRestartAnnotation, nil
+		}
+	default:
+		return "", fmt.Errorf("unsupported node type %q, has to be one of mgmd, ndbmtd or mysqld", nodeType)
+	}
+
+	return v1.RestartAnnotationPrefix + nodeType, nil
+}
+
+// runRestart requests the operator to do a rolling restart of the
+// MySQL Cluster nodes of a type, or a restart of a single data node,
+// by annotating the NdbCluster resource.
+func runRestart(args []string) error {
+	flags := flag.NewFlagSet("restart", flag.ExitOnError)
+	cf := addClientFlags(flags)
+	nodeType := flags.String("node-type", constants.NdbNodeTypeNdbmtd,
+		"Type of the nodes to be restarted - mgmd, ndbmtd or mysqld")
+	initial := flags.Bool("initial", false,
+		"Restart the data nodes with an empty file system, making them copy their data from the other data nodes")
+	nodeId := flags.Int("node-id", 0,
+		"Node id of a single data node to be restarted, once the other data nodes of its node group are connected")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: kubectl ndb restart <ndbcluster-name> [flags]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	name, err := parseNdbClusterName(flags, args)
+	if err != nil {
+		return err
+	}
+
+	annotation, err := getRestartAnnotation(*nodeType, *initial, *nodeId)
+	if err != nil {
+		return err
+	}
+
+	ndbClient, namespace, err := cf.newClient()
+	if err != nil {
+		return err
+	}
+
+	// Every new value of the annotation is a new restart request
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				annotation: time.Now().UTC().Format(time.RFC3339Nano),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err = ndbClient.MysqlV1().NdbClusters(namespace).Patch(
+		context.Background(), name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return err
+	}
+
+	if *nodeId != 0 {
+		fmt.Printf("A restart of the data node with node id %d of the NdbCluster %s/%s has been requested\n",
+			*nodeId, namespace, name)
+		return nil
+	}
+
+	restartType := "rolling restart"
+	if *initial {
+		restartType = "rolling initial restart"
+	}
+	fmt.Printf("A %s of the %s nodes of the NdbCluster %s/%s has been requested\n",
+		restartType, *nodeType, namespace, name)
+	return nil
+}
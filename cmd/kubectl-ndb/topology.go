@@ -0,0 +1,202 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	clientset "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+	"github.com/mysql/ndb-operator/pkg/portforward"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// topologyRow is a row of the topology table,
+// describing where a MySQL Cluster node runs
+type topologyRow struct {
+	nodeId    int32
+	nodeType  string
+	nodeGroup string
+	podName   string
+	k8sNode   string
+	zone      string
+}
+
+// getTopology returns the rows of the topology table for the nodes in the
+// status of the given NdbCluster, sorted by their nodeIds. The node groups
+// of the data nodes are taken from the given cluster status, which can be
+// nil if the Management Server was unreachable. The pods and the K8s worker
+// nodes are mapped by their names, and the zone of a worker node is read
+// from its zoneLabel.
+func getTopology(nc *v1.NdbCluster, clusterStatus mgmapi.ClusterStatus,
+	pods map[string]*corev1.Pod, workerNodes map[string]*corev1.Node, zoneLabel string) []topologyRow {
+	rows := make([]topologyRow, 0, len(nc.Status.Nodes))
+	for _, node := range nc.Status.Nodes {
+		row := topologyRow{
+			nodeId:    node.NodeId,
+			nodeType:  node.NodeType,
+			nodeGroup: "-",
+			podName:   node.PodName,
+			k8sNode:   "-",
+			zone:      "-",
+		}
+
+		if nodeStatus, exists := clusterStatus[int(node.NodeId)]; exists && nodeStatus.IsDataNode() {
+			switch {
+			case nodeStatus.NodeGroup >= 0 && nodeStatus.NodeGroup < mgmapi.NodeGroupNewDisconnectedDataNode:
+				row.nodeGroup = strconv.Itoa(nodeStatus.NodeGroup)
+			case nodeStatus.NodeGroup == mgmapi.NodeGroupNewConnectedDataNode ||
+				nodeStatus.NodeGroup == mgmapi.NodeGroupNewDisconnectedDataNode:
+				// The data node is yet to be added to a node group
+				row.nodeGroup = "new"
+			}
+		}
+
+		if pod, exists := pods[node.PodName]; exists && pod.Spec.NodeName != "" {
+			row.k8sNode = pod.Spec.NodeName
+			if workerNode, exists := workerNodes[pod.Spec.NodeName]; exists && workerNode.Labels[zoneLabel] != "" {
+				row.zone = workerNode.Labels[zoneLabel]
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].nodeId < rows[j].nodeId
+	})
+	return rows
+}
+
+// printTopology prints the given rows as a table
+func printTopology(w io.Writer, rows []topologyRow) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NODE ID\tTYPE\tNODE GROUP\tPOD\tK8S NODE\tZONE")
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			row.nodeId, row.nodeType, row.nodeGroup, row.podName, row.k8sNode, row.zone)
+	}
+	return tw.Flush()
+}
+
+// getPodsAndWorkerNodes retrieves the pods of the nodes in the status
+// of the given NdbCluster and the K8s worker nodes they are scheduled
+// onto, mapped by their names. The pods that do not exist are skipped.
+func getPodsAndWorkerNodes(ctx context.Context, kubeClient kubernetes.Interface, nc *v1.NdbCluster) (
+	map[string]*corev1.Pod, map[string]*corev1.Node, error) {
+	pods := make(map[string]*corev1.Pod)
+	workerNodes := make(map[string]*corev1.Node)
+	for _, node := range nc.Status.Nodes {
+		pod, err := kubeClient.CoreV1().Pods(nc.Namespace).Get(ctx, node.PodName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// The pod is yet to be created or is being recreated
+				continue
+			}
+			return nil, nil, err
+		}
+		pods[pod.Name] = pod
+
+		workerNodeName := pod.Spec.NodeName
+		if _, exists := workerNodes[workerNodeName]; workerNodeName == "" || exists {
+			// Pod is yet to be scheduled or the worker node has already been retrieved
+			continue
+		}
+		workerNode, err := kubeClient.CoreV1().Nodes().Get(ctx, workerNodeName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, nil, err
+		}
+		workerNodes[workerNodeName] = workerNode
+	}
+
+	return pods, workerNodes, nil
+}
+
+// runTopology prints the nodeIds, the node groups, the pods, the K8s
+// worker nodes and the zones of the MySQL Cluster nodes as a table, to
+// review how the MySQL Cluster is spread across the failure domains.
+// The nodes and their pods are taken from the NdbCluster status and the
+// node groups are retrieved from the Management Server.
+func runTopology(args []string) error {
+	flags := flag.NewFlagSet("topology", flag.ExitOnError)
+	cf := addClientFlags(flags)
+	zoneLabel := flags.String("zone-label", corev1.LabelTopologyZone,
+		"Label of the K8s worker nodes that has their zones")
+	timeout := flags.Duration("timeout", 30*time.Second,
+		"Time to wait for the Management Server to report the node groups")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: kubectl ndb topology <ndbcluster-name> [flags]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	name, err := parseNdbClusterName(flags, args)
+	if err != nil {
+		return err
+	}
+
+	cfg, namespace, err := cf.restConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	ndbClient, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	nc, err := ndbClient.MysqlV1().NdbClusters(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if len(nc.Status.Nodes) == 0 {
+		return fmt.Errorf("the nodes of the NdbCluster %s/%s are yet to be reported in its status", namespace, name)
+	}
+
+	pods, workerNodes, err := getPodsAndWorkerNodes(ctx, kubeClient, nc)
+	if err != nil {
+		return err
+	}
+
+	// The Management Server is addressed by the DNS names of its pods,
+	// which might not be reachable from outside the K8s Cluster.
+	dialer := portforward.NewDialer(cfg, kubeClient)
+	defer dialer.Stop()
+	mgmapi.SetDialer(dialer.DialContext)
+
+	// The table is printed without the node groups if the Management Server is unavailable
+	var clusterStatus mgmapi.ClusterStatus
+	mgmCtx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+	mgmClient, err := mgmapi.NewMgmClientWithContext(mgmCtx, nc.GetConnectstring())
+	if err == nil {
+		clusterStatus, err = mgmClient.GetStatus()
+		mgmClient.Disconnect()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to retrieve the node groups from the Management Server : %s\n\n", err)
+	}
+
+	return printTopology(os.Stdout, getTopology(nc, clusterStatus, pods, workerNodes, *zoneLabel))
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	klog "k8s.io/klog/v2"
+)
+
+// newHealthServer returns a http server that serves the health
+// probes of the operator at the given address. The /healthz endpoint
+// replies OK as long as the operator is running, and the /readyz
+// endpoint replies OK only when isReady returns true. The /metrics
+// endpoint serves the metrics written by writeMetrics.
+func newHealthServer(addr string, isReady func() bool, writeMetrics func(io.Writer)) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(writer http.ResponseWriter, request *http.Request) {
+		// Handle liveness probe
+		klog.V(2).Infof("Replying OK to liveness probe")
+		writer.WriteHeader(http.StatusOK)
+		_, _ = writer.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/readyz", func(writer http.ResponseWriter, request *http.Request) {
+		// Handle readiness probe
+		if !isReady() {
+			klog.V(2).Infof("Replying not ready to readiness probe")
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = writer.Write([]byte("informer caches are not synced yet"))
+			return
+		}
+
+		klog.V(2).Infof("Replying OK to readiness probe")
+		writer.WriteHeader(http.StatusOK)
+		_, _ = writer.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/metrics", func(writer http.ResponseWriter, request *http.Request) {
+		// Serve the metrics in the Prometheus text exposition format
+		writer.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writer.WriteHeader(http.StatusOK)
+		writeMetrics(writer)
+	})
+
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// runHealthServer starts serving the health probes and the metrics in
+// a separate goroutine and shuts the server down when ctx is done.
+func runHealthServer(ctx context.Context, addr string, isReady func() bool, writeMetrics func(io.Writer)) {
+	server := newHealthServer(addr, isReady, writeMetrics)
+
+	go func() {
+		klog.Infof("Serving the health probes at %q", addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			klog.Fatalf("Failed to serve the health probes : %s", err)
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		if err := server.Shutdown(context.Background()); err != nil {
+			klog.Errorf("Failed to shutdown the health probe server : %s", err)
+		}
+	}()
+}
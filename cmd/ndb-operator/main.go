@@ -7,8 +7,11 @@ package main
 import (
 	"context"
 	"flag"
-	"time"
+	"os"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
@@ -16,15 +19,20 @@ import (
 	klog "k8s.io/klog/v2"
 
 	"github.com/mysql/ndb-operator/config"
+	"github.com/mysql/ndb-operator/pkg/constants"
 	"github.com/mysql/ndb-operator/pkg/controllers"
 	clientset "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
 	ndbinformers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
 	"github.com/mysql/ndb-operator/pkg/helpers"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+	"github.com/mysql/ndb-operator/pkg/portforward"
 	"github.com/mysql/ndb-operator/pkg/signals"
 )
 
 func main() {
 	flag.Parse()
+	config.SetupLogging()
 	config.ValidateFlags()
 
 	// set up signal handlers
@@ -57,6 +65,10 @@ func main() {
 		klog.Fatalf("Error getting kubeconfig: %s", err.Error())
 	}
 
+	// Set the client side rate limits
+	cfg.QPS = float32(config.KubeAPIQPS)
+	cfg.Burst = config.KubeAPIBurst
+
 	kubeClient, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
 		klog.Fatalf("Error building kubernetes clientset: %s", err.Error())
@@ -67,6 +79,11 @@ func main() {
 		klog.Fatalf("Error building ndb clientset: %s", err.Error())
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("Error building dynamic client: %s", err.Error())
+	}
+
 	if config.ClusterScoped {
 		klog.Info("Running NDB Operator with cluster-scope")
 	} else {
@@ -78,23 +95,105 @@ func main() {
 	// For cluster-scoped mode, config.WatchNamespace will be empty
 	// and the SharedInformer will not be limited to any namespace.
 	k8If := kubeinformers.NewSharedInformerFactoryWithOptions(
-		kubeClient, time.Second*30, kubeinformers.WithNamespace(config.WatchNamespace))
+		kubeClient, config.ResyncPeriod, kubeinformers.WithNamespace(config.WatchNamespace))
 	ndbIf := ndbinformers.NewSharedInformerFactoryWithOptions(
-		ndbClient, time.Second*30, ndbinformers.WithNamespace(config.WatchNamespace))
+		ndbClient, config.ResyncPeriod, ndbinformers.WithNamespace(config.WatchNamespace))
+
+	// The Secrets are watched only to recreate the ones owned by the NdbClusters
+	// when they are deleted and to apply the password changes. Limit the
+	// SharedInformerFactory to the Secrets labelled with the NdbCluster name
+	// to avoid caching all the Secrets of the watched namespaces.
+	ownedSecretsIf := kubeinformers.NewSharedInformerFactoryWithOptions(
+		kubeClient, config.ResyncPeriod, kubeinformers.WithNamespace(config.WatchNamespace),
+		kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = constants.ClusterLabel
+		}))
+
+	// The Gateway API resources are watched only to manage the ones
+	// exposing the MySQL Servers. Limit the DynamicSharedInformerFactory
+	// to the resources labelled with the NdbCluster name.
+	ownedGatewaysIf := dynamicinformer.NewFilteredDynamicSharedInformerFactory(
+		dynamicClient, config.ResyncPeriod, config.WatchNamespace,
+		func(options *metav1.ListOptions) {
+			options.LabelSelector = constants.ClusterLabel
+		})
+
+	// Bound the connections opened to the MySQL Cluster
+	// nodes by the syncs of all the NdbClusters
+	mgmapi.SetMaxConnections(config.MaxMgmConnections)
+	mysqlclient.SetMaxOpenConnsPerServer(config.MaxMySQLConnectionsPerServer)
+
+	if !runningInsideK8s {
+		// The MySQL Cluster nodes are addressed by the DNS names of their pods,
+		// which might not be reachable from outside the K8s Cluster. Fall back
+		// to port-forwarding via the K8s API Server to connect to them.
+		dialer := portforward.NewDialer(cfg, kubeClient)
+		defer dialer.Stop()
+		mgmapi.SetDialer(dialer.DialContext)
+		mysqlclient.SetDialer(dialer.DialContext)
+	}
+
+	controller := controllers.NewController(kubeClient, ndbClient, dynamicClient,
+		k8If, ndbIf, ownedGatewaysIf, ownedSecretsIf.Core().V1().Secrets())
+
+	// Serve the runtime profiling data if enabled
+	if config.EnablePprof {
+		runPprofServer(ctx, config.PprofBindAddress)
+	}
+
+	// Serve the liveness and readiness probes and the metrics of the operator
+	if config.HealthProbeBindAddress != "" {
+		runHealthServer(ctx, config.HealthProbeBindAddress, controller.IsReady, controller.WriteMetrics)
+	}
 
-	controller := controllers.NewController(kubeClient, ndbClient, k8If, ndbIf)
+	// Publish the status of the operator to the NdbOperator resource
+	go controller.PublishOperatorStatus(ctx, getOperatorInfo(runningInsideK8s))
 
 	// notice that there is no need to run Start methods in a separate goroutine. (i.e. go kubeInformerFactory.Start(stopCh)
 	// Start method is non-blocking and runs all registered informers in a dedicated goroutine.
 	k8If.Start(ctx.Done())
 	ndbIf.Start(ctx.Done())
+	ownedSecretsIf.Start(ctx.Done())
+	ownedGatewaysIf.Start(ctx.Done())
 
-	if err = controller.Run(ctx, 2); err != nil {
+	if err = controller.Run(ctx, config.Workers, config.ShutdownTimeout, config.SyncTimeout); err != nil {
 		klog.Fatalf("Error running controller: %s", err.Error())
 	}
 }
 
+// getOperatorInfo returns the details of the operator
+// to be published to the NdbOperator resource
+func getOperatorInfo(runningInsideK8s bool) controllers.OperatorInfo {
+	// The NdbOperator is named after the namespace the operator is
+	// deployed in, so that the operators deployed in different
+	// namespaces publish their status to different resources.
+	name := "ndb-operator"
+	if runningInsideK8s {
+		namespace, err := helpers.GetCurrentNamespace()
+		if err != nil {
+			klog.Fatalf("Could not get current namespace : %s", err)
+		}
+		name = namespace
+	}
+
+	// The hostname is the name of the operator pod
+	identity, err := os.Hostname()
+	if err != nil {
+		klog.Fatalf("Could not get the hostname : %s", err)
+	}
+
+	return controllers.OperatorInfo{
+		Name:            name,
+		Version:         config.GetBuildVersion(),
+		Identity:        identity,
+		ClusterScoped:   config.ClusterScoped,
+		WatchNamespace:  config.WatchNamespace,
+		EnabledFeatures: config.GetEnabledFeatures(),
+	}
+}
+
 func init() {
 	klog.InitFlags(nil)
+	config.InitLoggingFlags()
 	config.InitFlags()
 }
@@ -0,0 +1,51 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	klog "k8s.io/klog/v2"
+)
+
+// runPprofServer starts serving the runtime profiling data of the
+// operator at the given address, in a separate goroutine. The server
+// is shut down when ctx is done. The profiles can be retrieved using
+// 'go tool pprof', e.g. 'go tool pprof http://localhost:6060/debug/pprof/heap'.
+func runPprofServer(ctx context.Context, addr string) {
+	// Register the pprof handlers in a dedicated mux rather
+	// than in the http.DefaultServeMux to ensure that they are
+	// not accidentally exposed by any other server.
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		klog.Infof("Serving the runtime profiling data at %q", addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			klog.Errorf("Failed to serve the runtime profiling data : %s", err)
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		if err := server.Shutdown(context.Background()); err != nil {
+			klog.Errorf("Failed to shutdown the pprof server : %s", err)
+		}
+	}()
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2022, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -51,7 +51,13 @@ func isAllowedDNSError(dnsError *net.DNSError) bool {
 // isDnsUpdated checks if the DNS can resolve the
 // current pod hostname to the right IP address.
 func isDnsUpdated(ctx context.Context, hostname, expectedIP string) bool {
-	resolvedIPs, err := net.DefaultResolver.LookupIP(ctx, "ip4", hostname)
+	// Lookup only the addresses of the Pod IP's family, as the hostname
+	// resolves to an address of every family in a dual-stack K8s Cluster.
+	network := "ip4"
+	if ip := net.ParseIP(expectedIP); ip != nil && ip.To4() == nil {
+		network = "ip6"
+	}
+	resolvedIPs, err := net.DefaultResolver.LookupIP(ctx, network, hostname)
 
 	if err != nil {
 		var dnsError *net.DNSError
@@ -70,7 +76,7 @@ func isDnsUpdated(ctx context.Context, hostname, expectedIP string) bool {
 		return false
 	}
 
-	if resolvedIPs[0].String() != expectedIP {
+	if !resolvedIPs[0].Equal(net.ParseIP(expectedIP)) {
 		// Hostname resolved to wrong IP => DNS not updated yet
 		return false
 	}
@@ -78,6 +84,10 @@ func isDnsUpdated(ctx context.Context, hostname, expectedIP string) bool {
 	return true
 }
 
+// defaultDNSStabilizationPeriod is the period for which the hostname of
+// the pod should be resolvable continuously, if not specified in the spec.
+const defaultDNSStabilizationPeriod = 5 * time.Second
+
 // waitForPodDNSUpdate waits until the DNS can resolve the current hostname without any error.
 //
 // Note 1 : This is required as the K8s CoreDNS runs with a default cache time of 30s for
@@ -109,15 +119,21 @@ func waitForPodDNSUpdate(ctx context.Context, podHostname string) {
 
 	podIP := os.Getenv("NDB_POD_IP")
 
+	// The period for which the hostname should be resolvable continuously
+	stabilizationPeriod := defaultDNSStabilizationPeriod
+	if seconds, err := strconv.Atoi(os.Getenv("NDB_POD_DNS_STABILIZATION_SECONDS")); err == nil && seconds > 0 {
+		stabilizationPeriod = time.Duration(seconds) * time.Second
+	}
+
 	// Record the time the last failure occurred
 	lastFailureTime := time.Now()
 
 	log.Println("Waiting for Pod's hostname to be updated in DNS...")
 	for {
 		if isDnsUpdated(ctx, hostnameToResolve, podIP) {
-			// Consider the hostname resolvable only when all the queries sent
-			// in the last 5 seconds has succeeded.
-			if time.Since(lastFailureTime) > 5*time.Second {
+			// Consider the hostname resolvable only when all the queries
+			// sent during the stabilization period has succeeded.
+			if time.Since(lastFailureTime) > stabilizationPeriod {
 				log.Println("Done")
 				break
 			}
@@ -156,6 +172,10 @@ func writeNodeIDToFile(hostname, ndbConnectString string) (nodeId int, nodeIdPoo
 		numOfManagementNode := len(strings.Split(ndbConnectString, ",")) - 1
 		startNodeIdOfSameNodeType = numOfManagementNode + 1
 		nodeType = mgmapi.NodeTypeNDB
+	case constants.NdbNodeTypeArbitrator:
+		// The external arbitrator is a Management node with a fixed nodeId
+		startNodeIdOfSameNodeType = constants.ArbitratorNodeId
+		nodeType = mgmapi.NodeTypeMGM
 	case constants.NdbNodeTypeMySQLD:
 		nodeType = mgmapi.NodeTypeAPI
 	}
@@ -171,7 +191,14 @@ func writeNodeIDToFile(hostname, ndbConnectString string) (nodeId int, nodeIdPoo
 		// For MySQL Servers, if connection pool is enabled, successive
 		// nodeIds are assigned to a single MySQL Server
 		ndbConnectionPoolSize, _ := strconv.ParseInt(os.Getenv("NDB_CONNECTION_POOL_SIZE"), 10, 32)
-		startNodeId := constants.NdbNodeTypeAPIStartNodeId + int(ndbConnectionPoolSize)*int(podOrdinalIndex)
+		// The nodeIds of the MySQL Server groups start from the first nodeId reserved for the group
+		startNodeIdOfSameNodeType = constants.NdbNodeTypeAPIStartNodeId
+		if groupStartNodeId := os.Getenv("NDB_MYSQLD_START_NODE_ID"); groupStartNodeId != "" {
+			groupStartNodeIdValue, err := strconv.Atoi(groupStartNodeId)
+			failOnError(err, "Failed to parse NDB_MYSQLD_START_NODE_ID %q : %s", groupStartNodeId, err)
+			startNodeIdOfSameNodeType = groupStartNodeIdValue
+		}
+		startNodeId := startNodeIdOfSameNodeType + int(ndbConnectionPoolSize)*int(podOrdinalIndex)
 		endNodeId := startNodeId + int(ndbConnectionPoolSize)
 		for ; startNodeId < endNodeId; startNodeId++ {
 			nodeIdPool = append(nodeIdPool, startNodeId)
@@ -230,7 +257,7 @@ func isSystemRestart(mgmClient mgmapi.MgmClient) bool {
 	return true
 }
 
-func waitForDataNodeFailureHandlingToComplete(nodeId int, podHostname string) (success bool) {
+func waitForDataNodeFailureHandlingToComplete(ctx context.Context, nodeId int, podHostname string) (success bool) {
 
 	// Connect to the MySQL Server - use the StatefulSet's 0th pod
 	hostnameTokens := strings.Split(podHostname, "-")
@@ -240,8 +267,15 @@ func waitForDataNodeFailureHandlingToComplete(nodeId int, podHostname string) (s
 		strings.Join(hostnameTokens, "-"),
 		strings.Join(hostnameTokens[:len(hostnameTokens)-1], "-"))
 
+	// The MySQL Servers listen on the default port,
+	// unless another port is specified in the spec.
+	mysqldPort := constants.MySQLServerPort
+	if port, err := strconv.Atoi(os.Getenv("NDB_MYSQLD_PORT")); err == nil && port > 0 {
+		mysqldPort = port
+	}
+
 	operatorPassword := os.Getenv("NDB_OPERATOR_PASSWORD")
-	db, err := mysqlclient.Connect(mysqldHost, mysqlclient.DbNdbInfo, operatorPassword)
+	db, err := mysqlclient.Connect(ctx, mysqldHost, mysqldPort, mysqlclient.DbNdbInfo, operatorPassword)
 	if err != nil {
 		// MySQL Server unavailable
 		return false
@@ -256,7 +290,7 @@ func waitForDataNodeFailureHandlingToComplete(nodeId int, podHostname string) (s
 	var nodeRestartStatus string
 	for {
 		query := fmt.Sprintf("select node_restart_status from restart_info where node_id='%d'", nodeId)
-		err = db.QueryRow(query).Scan(&nodeRestartStatus)
+		err = db.QueryRowContext(ctx, query).Scan(&nodeRestartStatus)
 		if err != nil {
 			// NdbInfo database unavailable
 			log.Printf("Query on ndbinfo.restart_info table failed : %s", err)
@@ -301,7 +335,7 @@ func main() {
 		// Wait for the existing data nodes to finish
 		// handling previous data node failure
 		log.Println("Waiting for other data nodes to complete Node failure handling...")
-		if !waitForDataNodeFailureHandlingToComplete(nodeId, podHostname) {
+		if !waitForDataNodeFailureHandlingToComplete(ctx, nodeId, podHostname) {
 			// The method failed as either server or the ndbinfo database is not available.
 			// Fallback to waitForNodeIdAvailability logic. This is used only as a fallback
 			// as the nodeId is freed early in the node failure handling process and due
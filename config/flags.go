@@ -6,8 +6,12 @@ package config
 
 import (
 	"flag"
+	"net"
+	"strings"
+	"time"
 
 	"github.com/mysql/ndb-operator/pkg/helpers"
+	"k8s.io/apimachinery/pkg/util/validation"
 	klog "k8s.io/klog/v2"
 )
 
@@ -19,6 +23,46 @@ var (
 	WatchNamespace string
 	// ClusterScoped if set, operator will watch the entire cluster
 	ClusterScoped bool
+
+	// Workers is the number of workers that process the NdbCluster sync requests in parallel
+	Workers int
+	// ResyncPeriod is the interval at which the informers resync their caches
+	ResyncPeriod time.Duration
+	// KubeAPIQPS and KubeAPIBurst control the client side rate limiting of the requests sent to the K8s API Server
+	KubeAPIQPS   float64
+	KubeAPIBurst int
+
+	// MaxMgmConnections is the maximum number of concurrent connections to the Management Servers
+	MaxMgmConnections int
+	// MaxMySQLConnectionsPerServer is the maximum number of connections opened to a single MySQL Server
+	MaxMySQLConnectionsPerServer int
+
+	// ShutdownTimeout is the maximum time the operator waits for the in-flight syncs to complete during shutdown
+	ShutdownTimeout time.Duration
+	// SyncTimeout is the maximum time allowed for a single sync of an NdbCluster
+	SyncTimeout time.Duration
+
+	// HealthProbeBindAddress is the address at which the liveness and the readiness probes are served
+	HealthProbeBindAddress string
+
+	// EnablePprof if set, the operator serves the runtime profiling data at PprofBindAddress
+	EnablePprof      bool
+	PprofBindAddress string
+
+	// ClusterDomain is the DNS domain of the K8s Cluster. It is
+	// detected from the K8s Cluster's DNS if it is not specified.
+	ClusterDomain string
+
+	// StreamClusterLog if set, the operator streams the cluster logs of the
+	// MySQL Clusters from the console of their Management Nodes, to surface
+	// the important entries as Events and to catalog the completed backups.
+	// The backups are neither cataloged nor verified when it is not set.
+	StreamClusterLog bool
+
+	// OperatorCIDR is the CIDR from which an operator running outside the K8s
+	// Cluster connects to the MySQL Cluster nodes. It is allowed by the
+	// NetworkPolicies created by the operator to isolate the MySQL Clusters.
+	OperatorCIDR string
 )
 
 func ValidateFlags() {
@@ -34,6 +78,57 @@ func ValidateFlags() {
 		WatchNamespace = ""
 	}
 
+	if Workers < 1 {
+		klog.Fatalf("Invalid value %d for option 'workers' : should be atleast 1", Workers)
+	}
+
+	if MaxMgmConnections < 0 {
+		klog.Fatalf("Invalid value %d for option 'max-mgm-connections' : should not be negative", MaxMgmConnections)
+	}
+
+	if MaxMySQLConnectionsPerServer < 1 {
+		klog.Fatalf("Invalid value %d for option 'max-mysql-connections-per-server' : should be atleast 1",
+			MaxMySQLConnectionsPerServer)
+	}
+
+	if ResyncPeriod < 0 {
+		klog.Fatalf("Invalid value %s for option 'resync-period' : cannot be negative", ResyncPeriod)
+	}
+
+	if ShutdownTimeout < 0 {
+		klog.Fatalf("Invalid value %s for option 'shutdown-timeout' : cannot be negative", ShutdownTimeout)
+	}
+
+	if SyncTimeout <= 0 {
+		klog.Fatalf("Invalid value %s for option 'sync-timeout' : should be greater than 0", SyncTimeout)
+	}
+
+	if KubeAPIQPS <= 0 || KubeAPIBurst <= 0 {
+		klog.Fatal("Options 'kube-api-qps' and 'kube-api-burst' should be greater than 0")
+	}
+
+	if EnablePprof && PprofBindAddress == "" {
+		klog.Fatal("Option 'pprof-bind-address' cannot be empty when 'enable-pprof' is set")
+	}
+
+	if ClusterDomain != "" {
+		// Allow the domain to be specified as an FQDN
+		ClusterDomain = strings.TrimSuffix(ClusterDomain, ".")
+		if errs := validation.IsDNS1123Subdomain(ClusterDomain); len(errs) != 0 {
+			klog.Fatalf("Invalid value %q for option 'cluster-domain' : %s", ClusterDomain, strings.Join(errs, ", "))
+		}
+	}
+
+	if OperatorCIDR != "" {
+		if runningInsideK8s {
+			// The operator pods are allowed via their labels
+			klog.Warning("Ignoring option 'operator-cidr' as operator is running inside K8s Cluster")
+			OperatorCIDR = ""
+		} else if _, _, err := net.ParseCIDR(OperatorCIDR); err != nil {
+			klog.Fatalf("Invalid value %q for option 'operator-cidr' : %s", OperatorCIDR, err)
+		}
+	}
+
 	if !runningInsideK8s {
 		if Kubeconfig == "" && MasterURL == "" {
 			// Operator is running out of K8s Cluster but kubeconfig/masterURL are not specified.
@@ -50,6 +145,27 @@ func ValidateFlags() {
 
 }
 
+// GetEnabledFeatures returns the optional features
+// of the operator that are enabled by the flags
+func GetEnabledFeatures() (features []string) {
+	if HealthProbeBindAddress != "" {
+		features = append(features, "HealthProbes")
+	}
+	if EnablePprof {
+		features = append(features, "Pprof")
+	}
+	if MaxMgmConnections > 0 {
+		features = append(features, "MgmConnectionLimit")
+	}
+	if StreamClusterLog {
+		features = append(features, "ClusterLogStreaming")
+	}
+	if !helpers.IsAppRunningInsideK8s() {
+		features = append(features, "PortForwarding")
+	}
+	return features
+}
+
 func InitFlags() {
 	flag.StringVar(&Kubeconfig, "kubeconfig", "",
 		"Path to a kubeconfig. Only required if out-of-cluster.")
@@ -60,4 +176,45 @@ func InitFlags() {
 			"Only required if out-of-cluster.")
 	flag.BoolVar(&ClusterScoped, "cluster-scoped", true, ""+
 		"When enabled, operator looks for NdbCluster resource changes across K8s cluster.")
+	flag.IntVar(&Workers, "workers", 2,
+		"The number of workers that process the NdbCluster resource changes in parallel.")
+	flag.DurationVar(&ResyncPeriod, "resync-period", 30*time.Second,
+		"The interval at which the informers resync their caches and requeue all the NdbCluster resources.")
+	flag.Float64Var(&KubeAPIQPS, "kube-api-qps", 5,
+		"The maximum queries per second allowed from the operator to the K8s API Server.")
+	flag.IntVar(&KubeAPIBurst, "kube-api-burst", 10,
+		"The maximum burst of queries allowed from the operator to the K8s API Server.")
+	flag.IntVar(&MaxMgmConnections, "max-mgm-connections", 32,
+		"The maximum number of concurrent connections opened to the Management Servers of all the NdbClusters. "+
+			"The syncs wait for a free connection when the limit is reached. "+
+			"The connections are not limited if it is set to 0.")
+	flag.IntVar(&MaxMySQLConnectionsPerServer, "max-mysql-connections-per-server", 10,
+		"The maximum number of connections opened to a single MySQL Server of an NdbCluster.")
+	flag.DurationVar(&ShutdownTimeout, "shutdown-timeout", 25*time.Second,
+		"The maximum time the operator waits for the in-flight NdbCluster syncs to complete when it is shutting down. "+
+			"The syncs that do not complete within this time are cancelled. "+
+			"Should be less than the terminationGracePeriodSeconds of the operator pod.")
+	flag.DurationVar(&SyncTimeout, "sync-timeout", 10*time.Minute,
+		"The maximum time allowed for a single sync of an NdbCluster, including the requests sent to the "+
+			"Management and the MySQL Servers. A sync that does not complete within this time is cancelled and retried. "+
+			"Should be longer than the time taken to restart a data node.")
+	flag.StringVar(&HealthProbeBindAddress, "health-probe-bind-address", ":8081",
+		"The address at which the /healthz and /readyz probe endpoints are served. "+
+			"The endpoints are disabled if it is set to an empty string.")
+	flag.BoolVar(&EnablePprof, "enable-pprof", false,
+		"When enabled, operator serves the runtime profiling data via the net/http/pprof endpoints.")
+	flag.StringVar(&PprofBindAddress, "pprof-bind-address", "localhost:6060",
+		"The address at which the pprof endpoints are served, when enabled. "+
+			"Binding to localhost ensures that the profiling data can be accessed only via 'kubectl port-forward'.")
+	flag.StringVar(&ClusterDomain, "cluster-domain", "",
+		"The DNS domain of the K8s Cluster, used in the hostnames of the MySQL Cluster nodes. "+
+			"If not specified, the domain is detected from the CNAME of the kubernetes.default.svc Service.")
+	flag.BoolVar(&StreamClusterLog, "stream-cluster-log", false,
+		"When enabled, the Management Nodes also write the cluster log to their console, from which the operator "+
+			"streams it to surface the important entries as Events of the NdbCluster and to catalog the completed backups. "+
+			"The backups are cataloged, and verified when spec.verifyBackups is set, only when this is enabled.")
+	flag.StringVar(&OperatorCIDR, "operator-cidr", "",
+		"The CIDR from which the operator connects to the MySQL Cluster nodes when it is running out-of-cluster. "+
+			"The NetworkPolicies created to isolate the MySQL Clusters allow the traffic from this CIDR. "+
+			"If not specified, an out-of-cluster operator cannot reach the MySQL Clusters isolated by a NetworkPolicy.")
 }
@@ -0,0 +1,79 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr/funcr"
+	klog "k8s.io/klog/v2"
+)
+
+// Supported log formats
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// LogFormat is the format in which the logs are written
+var LogFormat string
+
+// InitLoggingFlags registers the logging related flags. The verbosity
+// of the logs can be controlled globally via the klog's '-v' flag and
+// per source file via the klog's '-vmodule' flag.
+func InitLoggingFlags() {
+	flag.StringVar(&LogFormat, "log-format", LogFormatText,
+		"The format of the logs. Allowed values are 'text' and 'json'.")
+}
+
+// getLogVerbosity returns the verbosity set via the klog's '-v' flag
+func getLogVerbosity() int {
+	verbosityFlag := flag.Lookup("v")
+	if verbosityFlag == nil {
+		return 0
+	}
+
+	verbosity, _ := strconv.Atoi(verbosityFlag.Value.String())
+	return verbosity
+}
+
+// SetupLogging configures klog to write the logs in the format
+// specified by the LogFormat. It should be called after the flags
+// are parsed and before any goroutines are started.
+func SetupLogging() {
+	switch LogFormat {
+	case LogFormatText:
+		// klog writes text logs by default
+	case LogFormatJSON:
+		// Write every log entry as a JSON object to stderr. The logger
+		// is also used directly by the contextual logging calls, which
+		// rely on the logger to filter out the entries by verbosity.
+		logger := funcr.NewJSON(func(obj string) {
+			fmt.Fprintln(os.Stderr, obj)
+		}, funcr.Options{
+			LogCaller:    funcr.All,
+			LogTimestamp: true,
+			Verbosity:    getLogVerbosity(),
+			// Strip the trailing newline that klog
+			// appends to the printf style messages.
+			RenderBuiltinsHook: func(kvList []interface{}) []interface{} {
+				for i := 0; i+1 < len(kvList); i += 2 {
+					if msg, ok := kvList[i+1].(string); ok && kvList[i] == "msg" {
+						kvList[i+1] = strings.TrimSuffix(msg, "\n")
+					}
+				}
+				return kvList
+			},
+		})
+		klog.SetLoggerWithOptions(logger, klog.ContextualLogger(true))
+	default:
+		klog.Fatalf("Invalid value %q for option 'log-format' : should be either %q or %q",
+			LogFormat, LogFormatText, LogFormatJSON)
+	}
+}
@@ -113,9 +113,11 @@ var _ = ndbtest.NewOrderedTestCase("Erroneous NdbCluster spec update", func(tc *
 
 	ginkgo.When("Erroneous Mysql node config is specified in NdbCluster spec", func() {
 		ginkgo.BeforeAll(func() {
-			// Generate my.cnf value
+			// Generate my.cnf value. The webhook rejects the unknown
+			// options, so use a known option with an invalid value
+			// that fails the startup of the MySQL Servers.
 			myCnfStr := "[mysqld]\n"
-			myCnfStr += "maqx-user-connections=1\n"
+			myCnfStr += "max-user-connections=invalid\n"
 			testNdb.Spec.MysqlNode.MyCnf = myCnfStr
 
 			ndbtest.KubectlApplyNdbObjNoWait(testNdb)
@@ -10,22 +10,23 @@
 CRD_GEN_INPUT_PATH="./pkg/apis/..."
 HELM_CHART_PATH="deploy/charts/ndb-operator"
 CRD_GEN_OUTPUT="${HELM_CHART_PATH}/crds"
-CRD_FULL_PATH="${CRD_GEN_OUTPUT}/mysql.oracle.com_ndbclusters.yaml"
 CONTROLLER_GEN_CMD="go run sigs.k8s.io/controller-tools/cmd/controller-gen"
 
-# Generate Ndb CRD
-echo "Generating Ndb CRD..."
+# Generate the CRDs
+echo "Generating CRDs..."
 ${CONTROLLER_GEN_CMD} "crd" paths=${CRD_GEN_INPUT_PATH} output:crd:artifacts:config=${CRD_GEN_OUTPUT}
 # creationTimestamp in the CRD is always generated as null
 # https://github.com/kubernetes-sigs/controller-tools/issues/402
 # remove it as a workaround
 sed -i.crd.bak "/\ \ creationTimestamp\:\ null/d" ${CRD_GEN_OUTPUT}/* && rm ${CRD_GEN_OUTPUT}/*.crd.bak
+# Copy the NdbCluster CRD to kubectl-ndb, which embeds it to apply its defaults
+cp ${CRD_GEN_OUTPUT}/mysql.oracle.com_ndbclusters.yaml cmd/kubectl-ndb/crds/
 
 # Generate a single ndb-operator yaml file for deploying the CRD and the ndb operator in namespace 'ndb-operator'
 INSTALL_ARTIFACT="deploy/manifests/ndb-operator.yaml"
 echo "Generating install artifact..."
-# Copy in the Ndb CRD
-cp ${CRD_FULL_PATH} ${INSTALL_ARTIFACT}
+# Copy in the CRDs
+cat ${CRD_GEN_OUTPUT}/*.yaml > ${INSTALL_ARTIFACT}
 # Copy yaml to create 'ndb-operator' namespace
 echo "---
 apiVersion: v1
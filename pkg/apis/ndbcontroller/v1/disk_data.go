@@ -0,0 +1,119 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// diskDataStoragePercent is the percentage of the storage of the data
+	// node PVCs that is filled by the automatically sized Disk Data files.
+	// The rest is left to the redo logs, the checkpoints and the backups.
+	diskDataStoragePercent = 50
+	// undoFilesStoragePercent is the percentage of the Disk Data storage
+	// that is given to the automatically sized undo files, when they are
+	// stored in the data node PVCs along with the data files.
+	undoFilesStoragePercent = 20
+	// mebibyte is the unit to which the automatic file sizes are rounded down
+	mebibyte = 1024 * 1024
+)
+
+// getStorageRequest returns the storage, in bytes, requested by the
+// given PersistentVolumeClaimSpec, or 0 if it doesn't request any.
+func getStorageRequest(pvcSpec *corev1.PersistentVolumeClaimSpec) int64 {
+	if pvcSpec == nil {
+		return 0
+	}
+
+	storage, exists := pvcSpec.Resources.Requests[corev1.ResourceStorage]
+	if !exists {
+		return 0
+	}
+	return storage.Value()
+}
+
+// getFileSizes splits the storage left by the files with a size, either
+// in the spec or in the existingFileSizes, evenly among the files without
+// one, and returns the sizes of all the given files keyed by their names.
+func getFileSizes(files []NdbDiskDataFileSpec, storage int64, existingFileSizes map[string]int64) map[string]int64 {
+	fileSizes := make(map[string]int64, len(files))
+	var unsizedFiles []string
+	for _, file := range files {
+		if size, exists := existingFileSizes[file.Name]; exists {
+			// Existing files cannot be resized
+			fileSizes[file.Name] = size
+		} else if file.Size != nil {
+			fileSizes[file.Name] = file.Size.Value()
+		} else {
+			unsizedFiles = append(unsizedFiles, file.Name)
+			continue
+		}
+		storage -= fileSizes[file.Name]
+	}
+
+	var share int64
+	if len(unsizedFiles) > 0 && storage > 0 {
+		share = storage / int64(len(unsizedFiles)) / mebibyte * mebibyte
+	}
+	for _, fileName := range unsizedFiles {
+		// The share is 0 if there is no storage left for the file
+		fileSizes[fileName] = share
+	}
+
+	return fileSizes
+}
+
+// GetFileSizes returns the sizes, in bytes, of all the files of the Disk
+// Data objects keyed by their names. The files without a size in the spec
+// are sized automatically : half of the storage requested by the data node
+// PVCs is made available to the Disk Data files, of which a fifth goes to
+// the undo files and the rest to the data files. The undo files get all
+// of the storage requested by the undoFilesPVCSpec instead, if they are
+// stored in separate volumes. The storage left by the files with a size,
+// in the spec or in the existingFileSizes, is then split evenly among the
+// files without one, rounded down to a multiple of 1MiB. The size of a
+// file is returned as 0 if there is no storage left for it.
+func (dd *NdbDiskDataSpec) GetFileSizes(pvcSpec, undoFilesPVCSpec *corev1.PersistentVolumeClaimSpec,
+	existingFileSizes map[string]int64) map[string]int64 {
+
+	dataFilesStorage := getStorageRequest(pvcSpec) * diskDataStoragePercent / 100
+	var undoFilesStorage int64
+	if undoFilesPVCSpec != nil {
+		undoFilesStorage = getStorageRequest(undoFilesPVCSpec)
+	} else {
+		undoFilesStorage = dataFilesStorage * undoFilesStoragePercent / 100
+		dataFilesStorage -= undoFilesStorage
+	}
+
+	fileSizes := getFileSizes(dd.LogfileGroup.UndoFiles, undoFilesStorage, existingFileSizes)
+
+	// The data files of all the tablespaces share the same storage
+	var dataFiles []NdbDiskDataFileSpec
+	for _, tablespace := range dd.Tablespaces {
+		dataFiles = append(dataFiles, tablespace.DataFiles...)
+	}
+	for fileName, size := range getFileSizes(dataFiles, dataFilesStorage, existingFileSizes) {
+		fileSizes[fileName] = size
+	}
+
+	return fileSizes
+}
+
+// GetDiskDataFileSizes returns the sizes, in bytes, of all the files
+// of the Disk Data objects of the NdbCluster keyed by their names.
+// See NdbDiskDataSpec.GetFileSizes for how the files are sized.
+func (nc *NdbCluster) GetDiskDataFileSizes(existingFileSizes map[string]int64) map[string]int64 {
+	dataNode := nc.Spec.DataNode
+	if dataNode == nil || dataNode.DiskData == nil {
+		return nil
+	}
+
+	var undoFilesPVCSpec *corev1.PersistentVolumeClaimSpec
+	if dataNode.SeparateVolumes != nil {
+		undoFilesPVCSpec = dataNode.SeparateVolumes.UndoFiles
+	}
+	return dataNode.DiskData.GetFileSizes(dataNode.PVCSpec, undoFilesPVCSpec, existingFileSizes)
+}
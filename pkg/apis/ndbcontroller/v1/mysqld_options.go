@@ -0,0 +1,121 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package v1
+
+import "strings"
+
+// knownMySQLServerOptionPrefixes are the prefixes of the families of
+// mysqld options, like the ones of the storage engines, the plugins and
+// the components, which are too many to be listed individually.
+var knownMySQLServerOptionPrefixes = []string{
+	"admin-", "audit-log", "authentication-", "binlog-", "caching-sha2-password-",
+	"character-set", "clone-", "collation-", "component-", "connection-control",
+	"daemon-memcached-", "default-", "ft-", "group-replication", "gtid-",
+	"innodb", "key-cache-", "keyring", "log-error", "log-slow-", "master-",
+	"max-", "myisam", "mysql-firewall", "mysqlx", "ndb", "optimizer-",
+	"password-", "performance-schema", "relay-log", "replica-", "replicate-",
+	"rpl-", "session-track-", "sha256-password-", "slave-", "source-", "ssl-",
+	"sync-", "table-", "temptable-", "thread-pool", "tls-", "transaction-",
+	"validate-password", "version-tokens",
+}
+
+// knownMySQLServerOptions are the individual mysqld options, i.e. the
+// command-line options and the system variables, of MySQL Server 8.0
+// that are not covered by the knownMySQLServerOptionPrefixes. The options
+// set by the operator, like port, are in disallowedMySQLServerArgs instead.
+var knownMySQLServerOptions = map[string]bool{
+	"activate-all-roles-on-login": true, "allow-suspicious-udfs": true, "ansi": true,
+	"auto-generate-certs": true, "auto-increment-increment": true, "auto-increment-offset": true,
+	"autocommit": true, "automatic-sp-privileges": true, "back-log": true, "basedir": true,
+	"big-tables": true, "bind-address": true, "block-encryption-mode": true,
+	"bulk-insert-buffer-size": true, "check-proxy-users": true, "chroot": true,
+	"completion-type": true, "concurrent-insert": true, "connect-timeout": true, "console": true,
+	"core-file": true, "create-admin-listener-thread": true, "cte-max-recursion-depth": true,
+	"delay-key-write": true, "delayed-insert-limit": true, "delayed-insert-timeout": true,
+	"delayed-queue-size": true, "disabled-storage-engines": true,
+	"disconnect-on-expired-password": true, "div-precision-increment": true,
+	"early-plugin-load": true, "enforce-gtid-consistency": true, "eq-range-index-dive-limit": true,
+	"event-scheduler": true, "exit-info": true, "gdb": true, "language": true, "expire-logs-days": true, "explain-format": true,
+	"explicit-defaults-for-timestamp": true, "external-locking": true, "flush": true,
+	"flush-time": true, "general-log": true, "general-log-file": true,
+	"generated-random-password-length": true, "global-connection-memory-limit": true,
+	"global-connection-memory-tracking": true, "group-concat-max-len": true,
+	"histogram-generation-max-mem-size": true, "host-cache-size": true,
+	"information-schema-stats-expiry": true, "init-connect": true, "init-file": true,
+	"init-replica": true, "init-slave": true, "interactive-timeout": true,
+	"internal-tmp-mem-storage-engine": true, "join-buffer-size": true,
+	"keep-files-on-create": true, "key-buffer-size": true, "large-pages": true,
+	"lc-messages": true, "lc-messages-dir": true, "lc-time-names": true, "local-infile": true,
+	"lock-wait-timeout": true, "log-bin": true, "log-bin-basename": true, "log-bin-index": true,
+	"log-bin-trust-function-creators": true, "log-isam": true, "log-output": true,
+	"log-queries-not-using-indexes": true, "log-raw": true, "log-replica-updates": true,
+	"log-short-format": true, "log-slave-updates": true, "log-statements-unsafe-for-binlog": true,
+	"log-tc": true, "log-tc-size": true, "log-throttle-queries-not-using-indexes": true,
+	"log-timestamps": true, "long-query-time": true, "low-priority-updates": true,
+	"lower-case-table-names": true, "mandatory-roles": true, "memlock": true,
+	"min-examined-row-limit": true, "mysql-native-password-proxy-users": true,
+	"named-pipe": true, "net-buffer-length": true, "net-read-timeout": true,
+	"net-retry-count": true, "net-write-timeout": true, "new": true, "ngram-token-size": true,
+	"offline-mode": true, "old": true, "old-alter-table": true, "old-style-user-limits": true,
+	"open-files-limit": true, "parser-max-mem-size": true, "partial-revokes": true,
+	"persist-only-admin-x509-subject": true, "persist-sensitive-variables-in-plaintext": true,
+	"persisted-globals-load": true, "pid-file": true, "plugin-load": true, "plugin-load-add": true,
+	"port-open-timeout": true, "preload-buffer-size": true,
+	"print-identified-with-as-hex": true, "profiling": true, "profiling-history-size": true,
+	"protocol-compression-algorithms": true, "query-alloc-block-size": true,
+	"query-prealloc-size": true, "range-alloc-block-size": true,
+	"range-optimizer-max-mem-size": true, "read-buffer-size": true, "read-only": true,
+	"read-rnd-buffer-size": true, "regexp-stack-limit": true, "regexp-time-limit": true,
+	"report-host": true, "report-password": true, "report-port": true, "report-user": true,
+	"require-secure-transport": true, "schema-definition-cache": true,
+	"secondary-engine-cost-threshold": true, "secure-file-priv": true,
+	"select-into-buffer-size": true, "select-into-disk-sync": true,
+	"select-into-disk-sync-delay": true, "server-id": true, "server-id-bits": true,
+	"shared-memory": true, "shared-memory-base-name": true, "show-create-table-verbosity": true,
+	"show-gipk-in-create-table-and-information-schema": true, "show-old-temporals": true,
+	"grant-tables": true, "host-cache": true, "name-resolve": true, "networking": true,
+	"show-database": true, "stack-trace": true, "slow-launch-time": true, "slow-query-log": true,
+	"slow-query-log-file": true, "sort-buffer-size": true,
+	"sql-generate-invisible-primary-key": true, "sql-mode": true, "sql-require-primary-key": true,
+	"ssl": true, "standalone": true, "stored-program-cache": true,
+	"stored-program-definition-cache": true, "super-read-only": true, "symbolic-links": true,
+	"sysdate-is-now": true, "tablespace-definition-cache": true, "tc-heuristic-recover": true,
+	"terminology-use-previous": true, "thread-cache-size": true, "thread-handling": true,
+	"thread-stack": true, "tmp-table-size": true, "tmpdir": true, "updatable-views-with-limit": true,
+	"upgrade": true, "validate-config": true, "validate-user-plugins": true, "verbose": true,
+	"wait-timeout": true, "windowing-use-high-precision": true, "xa-detach-on-prepare": true,
+}
+
+// normalizeMySQLServerOption returns the name of the given mysqld option
+// in lowercase, with the underscores replaced by dashes and without the
+// modifiers. loose is true if the option has the loose- modifier, with
+// which mysqld ignores the option if it is unknown.
+func normalizeMySQLServerOption(option string) (name string, loose bool) {
+	name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(option)), "_", "-")
+	if strings.HasPrefix(name, "loose-") {
+		loose = true
+		name = strings.TrimPrefix(name, "loose-")
+	}
+	for _, modifier := range []string{"maximum-", "skip-", "enable-", "disable-"} {
+		name = strings.TrimPrefix(name, modifier)
+	}
+	return name, loose
+}
+
+// isKnownMySQLServerOption returns true if the given normalized
+// name is a known option of the MySQL Server
+func isKnownMySQLServerOption(name string) bool {
+	if knownMySQLServerOptions[name] {
+		return true
+	}
+
+	for _, prefix := range knownMySQLServerOptionPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
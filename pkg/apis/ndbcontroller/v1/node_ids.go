@@ -0,0 +1,241 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package v1
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mysql/ndb-operator/pkg/constants"
+)
+
+// getNumOfAPISections returns the number of API sections required by the
+// MySQL Servers, the free API slots, the MySQL Server groups and the NDBAPI
+// applications. The section of the operator's dedicated API node is not included.
+func (nc *NdbCluster) getNumOfAPISections() int32 {
+	connectionPoolSize := nc.GetMySQLServerConnectionPoolSize()
+	numOfAPISections := nc.GetMySQLServerMaxNodeCount()*connectionPoolSize + nc.Spec.FreeAPISlots
+	for _, serverGroup := range nc.GetMySQLServerGroups() {
+		numOfAPISections += serverGroup.NodeCount * connectionPoolSize
+	}
+	return numOfAPISections + nc.getNumOfNdbAPIApplicationSections()
+}
+
+// getNumOfNdbAPIApplicationSections returns the number
+// of API sections reserved for the NDBAPI applications
+func (nc *NdbCluster) getNumOfNdbAPIApplicationSections() (numOfSections int32) {
+	for i := range nc.Spec.NdbAPIApplications {
+		app := &nc.Spec.NdbAPIApplications[i]
+		numOfSections += app.NodeCount * app.GetConnectionPoolSize()
+	}
+	return numOfSections
+}
+
+// GetNdbAPIApplicationHostnames returns the nodeIds reserved for the NDBAPI
+// applications, mapped to the hostnames of the application pods, of form
+// '<name>-<pod-index>.<name>.<namespace>'. The nodeIds are reserved from the
+// highest nodeId available to the API nodes downwards, in the order of the
+// applications, and every pod gets ConnectionPoolSize number of nodeIds. So,
+// adding applications or pods to the last application does not change the
+// nodeIds already reserved.
+func (nc *NdbCluster) GetNdbAPIApplicationHostnames() map[int32]string {
+	if len(nc.Spec.NdbAPIApplications) == 0 {
+		return nil
+	}
+
+	nodeId := int32(constants.MaxNumberOfNodes - 1)
+	if nc.HasArbitrator() {
+		nodeId = constants.ArbitratorNodeId - 1
+	}
+
+	nodeIdToHostname := make(map[int32]string)
+	for i := range nc.Spec.NdbAPIApplications {
+		app := &nc.Spec.NdbAPIApplications[i]
+		for podIdx := int32(0); podIdx < app.NodeCount; podIdx++ {
+			hostname := fmt.Sprintf("%s-%d.%s.%s", app.Name, podIdx, app.Name, app.Namespace)
+			for j := int32(0); j < app.GetConnectionPoolSize(); j++ {
+				nodeIdToHostname[nodeId] = hostname
+				nodeId--
+			}
+		}
+	}
+
+	return nodeIdToHostname
+}
+
+// GetMySQLServerGroupNodeIds returns the ranges of nodeIds reserved for
+// the MySQL Server groups, in the order of the groups. Every group needs
+// ConnectionPoolSize number of nodeIds for each of its MySQL Servers. A
+// group retains the range recorded for it in status.mysqlServerGroups as
+// long as the range can hold all its nodeIds and doesn't overlap the
+// nodeIds reserved for the MySQL Servers, the free API slots and the
+// NDBAPI applications, so that updating them or the other groups does not
+// move the MySQL Servers of the group to other nodeIds. The other groups
+// get the first range of free nodeIds, after the operator's dedicated API
+// node, that can hold all their nodeIds. A range that extends beyond the
+// API nodeIds available is rejected by the validation.
+func (nc *NdbCluster) GetMySQLServerGroupNodeIds() []NdbMysqldServerGroupStatus {
+	serverGroups := nc.GetMySQLServerGroups()
+	if len(serverGroups) == 0 {
+		return nil
+	}
+
+	// Mark the nodeIds reserved for the MySQL Servers, the free API
+	// slots and the NDBAPI applications as used
+	usedNodeIds := make(map[int32]bool)
+	connectionPoolSize := nc.GetMySQLServerConnectionPoolSize()
+	numOfSections := nc.GetMySQLServerMaxNodeCount()*connectionPoolSize + nc.Spec.FreeAPISlots
+	for i := int32(0); i < numOfSections; i++ {
+		usedNodeIds[constants.NdbNodeTypeAPIStartNodeId+i] = true
+	}
+	for nodeId := range nc.GetNdbAPIApplicationHostnames() {
+		usedNodeIds[nodeId] = true
+	}
+	if nc.HasArbitrator() {
+		usedNodeIds[constants.ArbitratorNodeId] = true
+	}
+
+	// isFree returns true if none of the nodeIds in the range are used
+	isFree := func(startNodeId, numOfNodeIds int32) bool {
+		for nodeId := startNodeId; nodeId < startNodeId+numOfNodeIds; nodeId++ {
+			if usedNodeIds[nodeId] {
+				return false
+			}
+		}
+		return true
+	}
+	// reserve marks the nodeIds in the range as used
+	reserve := func(startNodeId, numOfNodeIds int32) {
+		for nodeId := startNodeId; nodeId < startNodeId+numOfNodeIds; nodeId++ {
+			usedNodeIds[nodeId] = true
+		}
+	}
+
+	previousRanges := make(map[string]NdbMysqldServerGroupStatus)
+	for _, previousRange := range nc.Status.MySQLServerGroups {
+		previousRanges[previousRange.Name] = previousRange
+	}
+
+	// Retain the ranges of the groups that can still hold their nodeIds
+	serverGroupNodeIds := make([]NdbMysqldServerGroupStatus, len(serverGroups))
+	for i, serverGroup := range serverGroups {
+		serverGroupNodeIds[i].Name = serverGroup.Name
+		previousRange, exists := previousRanges[serverGroup.Name]
+		if exists && previousRange.NumOfNodeIds >= serverGroup.NodeCount*connectionPoolSize &&
+			isFree(previousRange.StartNodeId, previousRange.NumOfNodeIds) {
+			serverGroupNodeIds[i] = previousRange
+			reserve(previousRange.StartNodeId, previousRange.NumOfNodeIds)
+		}
+	}
+
+	// Allocate the first free range to the other groups
+	for i, serverGroup := range serverGroups {
+		if serverGroupNodeIds[i].NumOfNodeIds != 0 {
+			// The group has retained its range
+			continue
+		}
+
+		numOfNodeIds := serverGroup.NodeCount * connectionPoolSize
+		startNodeId := int32(constants.NdbNodeTypeAPIStartNodeId)
+		for !isFree(startNodeId, numOfNodeIds) {
+			startNodeId++
+		}
+		serverGroupNodeIds[i].StartNodeId = startNodeId
+		serverGroupNodeIds[i].NumOfNodeIds = numOfNodeIds
+		reserve(startNodeId, numOfNodeIds)
+	}
+
+	return serverGroupNodeIds
+}
+
+// getReservedNodeIds returns the nodeIds reserved by the MySQL Cluster
+// config generated from the spec, mapped to the nodes they are reserved
+// for. It follows the layout of the config generated by the ndbconfig
+// package : the Management and the Data nodes get the nodeIds starting
+// from 1 and the API sections get the ones following the operator's
+// dedicated API node, in the order of the MySQL Servers and the free API
+// slots, followed by the ranges of the MySQL Server groups. The free API
+// slots are not reserved for any pod and are mapped to nodes of type api,
+// as are the sections of the NDBAPI applications, which get the highest
+// nodeIds.
+func (nc *NdbCluster) getReservedNodeIds() map[int32]NdbClusterNodeStatus {
+	reservedNodeIds := make(map[int32]NdbClusterNodeStatus)
+	reserve := func(nodeId int32, nodeType constants.NdbNodeType, podName string) {
+		reservedNodeIds[nodeId] = NdbClusterNodeStatus{
+			NodeId:   nodeId,
+			NodeType: nodeType,
+			PodName:  podName,
+		}
+	}
+
+	// The Management nodes followed by the Data nodes
+	nodeId := int32(1)
+	for _, node := range []struct {
+		nodeType  constants.NdbNodeType
+		nodeCount int32
+	}{
+		{constants.NdbNodeTypeMgmd, nc.GetManagementNodeCount()},
+		{constants.NdbNodeTypeNdbmtd, nc.Spec.DataNode.NodeCount},
+	} {
+		for podIdx := int32(0); podIdx < node.nodeCount; podIdx++ {
+			reserve(nodeId, node.nodeType, fmt.Sprintf("%s-%d", nc.GetWorkloadName(node.nodeType), podIdx))
+			nodeId++
+		}
+	}
+
+	if nc.HasArbitrator() {
+		reserve(constants.ArbitratorNodeId, constants.NdbNodeTypeArbitrator,
+			nc.GetWorkloadName(constants.NdbNodeTypeArbitrator)+"-0")
+	}
+
+	// Every MySQL Server pod gets ConnectionPoolSize number of successive nodeIds
+	nodeId = constants.NdbNodeTypeAPIStartNodeId
+	connectionPoolSize := nc.GetMySQLServerConnectionPoolSize()
+	reserveMySQLServers := func(workloadName string, nodeCount int32) {
+		for podIdx := int32(0); podIdx < nodeCount; podIdx++ {
+			for i := int32(0); i < connectionPoolSize; i++ {
+				reserve(nodeId, constants.NdbNodeTypeMySQLD, fmt.Sprintf("%s-%d", workloadName, podIdx))
+				nodeId++
+			}
+		}
+	}
+
+	reserveMySQLServers(nc.GetWorkloadName(constants.NdbNodeTypeMySQLD), nc.GetMySQLServerMaxNodeCount())
+	for i := int32(0); i < nc.Spec.FreeAPISlots; i++ {
+		reserve(nodeId, constants.NdbNodeTypeAPI, "")
+		nodeId++
+	}
+	for i, serverGroupNodeIds := range nc.GetMySQLServerGroupNodeIds() {
+		nodeId = serverGroupNodeIds.StartNodeId
+		reserveMySQLServers(nc.GetMySQLServerGroupWorkloadName(serverGroupNodeIds.Name),
+			nc.GetMySQLServerGroups()[i].NodeCount)
+	}
+
+	// The NDBAPI applications get the nodeIds from the highest one downwards
+	for nodeId, hostname := range nc.GetNdbAPIApplicationHostnames() {
+		podName, _, _ := strings.Cut(hostname, ".")
+		reserve(nodeId, constants.NdbNodeTypeAPI, podName)
+	}
+
+	return reservedNodeIds
+}
+
+// getMySQLServerPodNames returns the names of the
+// pods to be run for the MySQL Servers and the groups
+func (nc *NdbCluster) getMySQLServerPodNames() map[string]bool {
+	podNames := make(map[string]bool)
+	addPodNames := func(workloadName string, nodeCount int32) {
+		for podIdx := int32(0); podIdx < nodeCount; podIdx++ {
+			podNames[fmt.Sprintf("%s-%d", workloadName, podIdx)] = true
+		}
+	}
+
+	addPodNames(nc.GetWorkloadName(constants.NdbNodeTypeMySQLD), nc.GetMySQLServerNodeCount())
+	for _, serverGroup := range nc.GetMySQLServerGroups() {
+		addPodNames(nc.GetMySQLServerGroupWorkloadName(serverGroup.Name), serverGroup.NodeCount)
+	}
+
+	return podNames
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=ndbop,categories=all
+//
+// Additional printer columns
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="Version of the NDB Operator"
+// +kubebuilder:printcolumn:name="Leader",type="string",JSONPath=".status.leaderIdentity",description="Identity of the NDB Operator instance reconciling the NdbClusters"
+// +kubebuilder:printcolumn:name="Last Update",type="date",JSONPath=".status.lastUpdateTime",description="Time since the NDB Operator last published its status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age of the NdbOperator resource"
+
+// NdbOperator is the resource in which a running NDB Operator publishes
+// its own status, giving a view of the operator itself. It is created
+// and maintained by the NDB Operator and should not be edited.
+type NdbOperator struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The status of the NDB Operator, as published by the operator.
+	// +optional
+	Status NdbOperatorStatus `json:"status,omitempty"`
+}
+
+// NdbOperatorNamespaceStatus is the summary of
+// the NdbClusters managed in a namespace
+type NdbOperatorNamespaceStatus struct {
+	// The namespace of the NdbClusters
+	Namespace string `json:"namespace"`
+	// The number of NdbClusters in the namespace
+	NdbClusters int32 `json:"ndbClusters"`
+	// The number of NdbClusters in the namespace whose MySQL
+	// Cluster is up-to-date with the latest NdbCluster spec
+	UpToDateNdbClusters int32 `json:"upToDateNdbClusters"`
+}
+
+// NdbOperatorStatus is the status published by a running NDB Operator
+type NdbOperatorStatus struct {
+	// The build version of the NDB Operator
+	// +optional
+	Version string `json:"version,omitempty"`
+	// LeaderIdentity is the identity, i.e. the pod name, of the
+	// NDB Operator instance that is reconciling the NdbClusters.
+	// +optional
+	LeaderIdentity string `json:"leaderIdentity,omitempty"`
+	// ClusterScoped is true if the NDB Operator watches the
+	// NdbClusters across the K8s Cluster.
+	// +optional
+	ClusterScoped bool `json:"clusterScoped,omitempty"`
+	// WatchNamespace is the namespace watched by a
+	// namespace-scoped NDB Operator.
+	// +optional
+	WatchNamespace string `json:"watchNamespace,omitempty"`
+	// EnabledFeatures is the list of the optional
+	// features enabled in the NDB Operator.
+	// +optional
+	// +listType=set
+	EnabledFeatures []string `json:"enabledFeatures,omitempty"`
+	// Namespaces has the summary of the NdbClusters
+	// managed by the NDB Operator in every namespace.
+	// +optional
+	// +listType=map
+	// +listMapKey=namespace
+	Namespaces []NdbOperatorNamespaceStatus `json:"namespaces,omitempty"`
+	// LastUpdateTime is the time at which the NDB Operator last
+	// published its status. The status is published periodically,
+	// so an old LastUpdateTime implies that the operator is not running.
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NdbOperatorList contains a list of NdbOperator resources
+type NdbOperatorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NdbOperator `json:"items"`
+}
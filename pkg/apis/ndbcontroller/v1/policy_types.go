@@ -0,0 +1,262 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package v1
+
+import (
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=ndbpolicy,categories=all
+//
+// Additional printer columns
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age of the NdbClusterPolicy resource"
+
+// NdbClusterPolicy defines the defaults and the constraints that are
+// applied by the NDB Operator webhook to every NdbCluster resource
+// created in the namespaces selected by the policy.
+type NdbClusterPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The defaults and the constraints of the policy.
+	Spec NdbClusterPolicySpec `json:"spec"`
+}
+
+// NdbClusterPolicyDefaults are the values set in the NdbCluster
+// resources that do not specify them.
+type NdbClusterPolicyDefaults struct {
+	// ImagePullSecrets is the list of secrets to be used for pulling the
+	// MySQL Cluster images when the NdbCluster specifies neither the
+	// imagePullSecretName nor the imagePullSecrets.
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// PodLabels are the labels to be added to the podLabels of the
+	// NdbCluster. Labels already specified by the NdbCluster are retained.
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+	// PodAnnotations are the annotations to be added to the podAnnotations of
+	// the NdbCluster. Annotations already specified by the NdbCluster are retained.
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+	// ServiceAnnotations are the annotations to be added to the serviceAnnotations
+	// of the NdbCluster. Annotations already specified by the NdbCluster are retained.
+	// +optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+}
+
+// NdbClusterPolicyConstraints are the rules that every
+// NdbCluster resource in the scope of the policy must satisfy.
+type NdbClusterPolicyConstraints struct {
+	// AllowedImages is the list of images that the MySQL Cluster nodes, and
+	// the additional init and sidecar containers specified for their pods,
+	// are allowed to use. Every entry is a pattern matched against the complete
+	// image name, and can use the wildcards supported by Go's path.Match,
+	// e.g. "container-registry.oracle.com/mysql/*". All images are allowed
+	// if the list is empty.
+	// +optional
+	AllowedImages []string `json:"allowedImages,omitempty"`
+	// RequireResources, when true, requires the NdbCluster to specify the
+	// CPU and memory requests of all the MySQL Cluster nodes via their
+	// ndbPodSpec.resources.
+	// +optional
+	RequireResources bool `json:"requireResources,omitempty"`
+}
+
+// NdbClusterPolicySpec defines the desired state of an NdbClusterPolicy
+type NdbClusterPolicySpec struct {
+	// NamespaceSelector selects the namespaces to which the policy applies.
+	// The policy applies to all the namespaces if it is not specified.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Defaults are the values set in the NdbCluster resources
+	// that are in scope of the policy and do not specify them.
+	// +optional
+	Defaults *NdbClusterPolicyDefaults `json:"defaults,omitempty"`
+	// Constraints are the rules that the NdbCluster resources in scope of
+	// the policy must satisfy. An update to an existing NdbCluster is
+	// rejected only for the violations introduced by it, so that the
+	// NdbClusters created before the policy can still be updated.
+	// +optional
+	Constraints *NdbClusterPolicyConstraints `json:"constraints,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NdbClusterPolicyList contains a list of NdbClusterPolicy resources
+// +kubebuilder:object:root=true
+type NdbClusterPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NdbClusterPolicy `json:"items"`
+}
+
+// AppliesToNamespace returns true if the policy
+// applies to a namespace with the given labels.
+func (policy *NdbClusterPolicy) AppliesToNamespace(namespaceLabels map[string]string) (bool, error) {
+	if policy.Spec.NamespaceSelector == nil {
+		// Policy applies to all namespaces
+		return true, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(policy.Spec.NamespaceSelector)
+	if err != nil {
+		return false, err
+	}
+
+	return selector.Matches(labels.Set(namespaceLabels)), nil
+}
+
+// getContainerImages returns the paths of the image fields
+// of the given containers along with the images they specify.
+func getContainerImages(containers []corev1.Container, containersPath *field.Path) (
+	imagePaths []*field.Path, images []string) {
+	for i := range containers {
+		imagePaths = append(imagePaths, containersPath.Index(i).Child("image"))
+		images = append(images, containers[i].Image)
+	}
+	return imagePaths, images
+}
+
+// getImages returns the paths of the image fields in the NdbCluster
+// spec along with the images they specify. This includes the images
+// of the MySQL Cluster nodes and the images of all the additional
+// containers, specified in the spec, that run in their pods.
+func getImages(nc *NdbCluster) (imagePaths []*field.Path, images []string) {
+	specPath := field.NewPath("spec")
+	imagePaths = append(imagePaths, specPath.Child("image"))
+	images = append(images, nc.Spec.Image)
+
+	// addImage adds the image, if specified, at the given path
+	addImage := func(image string, imagePath *field.Path) {
+		if image != "" {
+			imagePaths = append(imagePaths, imagePath)
+			images = append(images, image)
+		}
+	}
+	// addContainerImages adds the images of the given containers
+	addContainerImages := func(containers []corev1.Container, containersPath *field.Path) {
+		paths, containerImages := getContainerImages(containers, containersPath)
+		imagePaths = append(imagePaths, paths...)
+		images = append(images, containerImages...)
+	}
+	// addNdbPodSpecImages adds the images of the
+	// init and the sidecar containers of the ndbPodSpec
+	addNdbPodSpecImages := func(ndbPodSpec *NdbClusterPodSpec, nodeSpecPath *field.Path) {
+		if ndbPodSpec != nil {
+			ndbPodSpecPath := nodeSpecPath.Child("ndbPodSpec")
+			addContainerImages(ndbPodSpec.InitContainers, ndbPodSpecPath.Child("initContainers"))
+			addContainerImages(ndbPodSpec.SidecarContainers, ndbPodSpecPath.Child("sidecarContainers"))
+		}
+	}
+
+	if mgmdSpec := nc.Spec.ManagementNode; mgmdSpec != nil {
+		mgmdPath := specPath.Child("managementNode")
+		addImage(mgmdSpec.Image, mgmdPath.Child("image"))
+		addNdbPodSpecImages(mgmdSpec.NdbPodSpec, mgmdPath)
+	}
+	if dataNodeSpec := nc.Spec.DataNode; dataNodeSpec != nil {
+		dataNodePath := specPath.Child("dataNode")
+		addImage(dataNodeSpec.Image, dataNodePath.Child("image"))
+		addNdbPodSpecImages(dataNodeSpec.NdbPodSpec, dataNodePath)
+	}
+	if mysqldSpec := nc.Spec.MysqlNode; mysqldSpec != nil {
+		mysqldPath := specPath.Child("mysqlNode")
+		addImage(mysqldSpec.Image, mysqldPath.Child("image"))
+		addNdbPodSpecImages(mysqldSpec.NdbPodSpec, mysqldPath)
+		if mysqldSpec.Plugins != nil {
+			addContainerImages(mysqldSpec.Plugins.InitContainers,
+				mysqldPath.Child("plugins", "initContainers"))
+		}
+		if mysqldSpec.AuditLog != nil {
+			addContainerImages(mysqldSpec.AuditLog.SidecarContainers,
+				mysqldPath.Child("auditLog", "sidecarContainers"))
+		}
+	}
+	if arbitratorSpec := nc.Spec.Arbitrator; arbitratorSpec != nil {
+		addNdbPodSpecImages(arbitratorSpec.NdbPodSpec, specPath.Child("arbitrator"))
+	}
+
+	return imagePaths, images
+}
+
+// isImageAllowed returns true if the image matches any of the allowedImages patterns
+func isImageAllowed(image string, allowedImages []string) bool {
+	for _, pattern := range allowedImages {
+		if matched, _ := path.Match(pattern, image); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCPUAndMemoryRequests returns true if the ndbPodSpec
+// specifies both the cpu and the memory requests.
+func hasCPUAndMemoryRequests(ndbPodSpec *NdbClusterPodSpec) bool {
+	if ndbPodSpec == nil || ndbPodSpec.Resources == nil {
+		return false
+	}
+
+	requests := ndbPodSpec.Resources.Requests
+	return !requests.Cpu().IsZero() && !requests.Memory().IsZero()
+}
+
+// ValidateNdbCluster verifies that the given NdbCluster
+// satisfies the constraints defined by the policy.
+func (policy *NdbClusterPolicy) ValidateNdbCluster(nc *NdbCluster) (errList field.ErrorList) {
+	constraints := policy.Spec.Constraints
+	if constraints == nil {
+		// No constraints to enforce
+		return nil
+	}
+
+	if len(constraints.AllowedImages) != 0 {
+		imagePaths, images := getImages(nc)
+		for i, image := range images {
+			if !isImageAllowed(image, constraints.AllowedImages) {
+				errList = append(errList, field.Forbidden(imagePaths[i], fmt.Sprintf(
+					"image %q is not allowed by the NdbClusterPolicy %q", image, policy.Name)))
+			}
+		}
+	}
+
+	if constraints.RequireResources {
+		specPath := field.NewPath("spec")
+		requireResources := func(ndbPodSpec *NdbClusterPodSpec, nodeSpecPath *field.Path) {
+			if !hasCPUAndMemoryRequests(ndbPodSpec) {
+				errList = append(errList, field.Required(nodeSpecPath.Child("ndbPodSpec", "resources"), fmt.Sprintf(
+					"cpu and memory requests are required by the NdbClusterPolicy %q", policy.Name)))
+			}
+		}
+
+		var mgmdPodSpec, dataNodePodSpec *NdbClusterPodSpec
+		if nc.Spec.ManagementNode != nil {
+			mgmdPodSpec = nc.Spec.ManagementNode.NdbPodSpec
+		}
+		if nc.Spec.DataNode != nil {
+			dataNodePodSpec = nc.Spec.DataNode.NdbPodSpec
+		}
+		requireResources(mgmdPodSpec, specPath.Child("managementNode"))
+		requireResources(dataNodePodSpec, specPath.Child("dataNode"))
+		if nc.Spec.MysqlNode != nil && nc.Spec.MysqlNode.NodeCount != 0 {
+			requireResources(nc.Spec.MysqlNode.NdbPodSpec, specPath.Child("mysqlNode"))
+		}
+		if nc.HasArbitrator() {
+			requireResources(nc.Spec.Arbitrator.NdbPodSpec, specPath.Child("arbitrator"))
+		}
+	}
+
+	return errList
+}
@@ -8,10 +8,15 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller"
 	"github.com/mysql/ndb-operator/pkg/constants"
 	"github.com/mysql/ndb-operator/pkg/ndbconfig/configparser"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -22,6 +27,7 @@ import (
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.mysqlNode.nodeCount,statuspath=.status.mysqlServerReplicas,selectorpath=.status.mysqlServerSelector
 // +kubebuilder:resource:shortName=ndb;ndbc,categories=all
 //
 // Additional printer columns
@@ -29,8 +35,13 @@ import (
 // +kubebuilder:printcolumn:name="Management Nodes",type=string,JSONPath=`.status.readyManagementNodes`,description="Number of ready MySQL Cluster Management Nodes"
 // +kubebuilder:printcolumn:name="Data Nodes",type=string,JSONPath=`.status.readyDataNodes`,description="Number of ready MySQL Cluster Data Nodes"
 // +kubebuilder:printcolumn:name="MySQL Servers",type=string,JSONPath=`.status.readyMySQLServers`,description="Number of ready MySQL Servers"
+// +kubebuilder:printcolumn:name="Connected MySQL Servers",type=string,JSONPath=`.status.connectedMySQLServers`,description="Number of MySQL Servers connected to the NDB engine",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age of the NdbCluster resource"
 // +kubebuilder:printcolumn:name="Up-To-Date",type="string",JSONPath=".status.conditions[?(@.type=='UpToDate')].status",description="Indicates if the MySQL Cluster configuration is up-to-date with the spec specified in the NdbCluster resource"
+// +kubebuilder:printcolumn:name="Partitioned",type="string",JSONPath=".status.conditions[?(@.type=='Partitioned')].status",description="Indicates if any of the started data nodes have lost their connection to the MySQL Cluster",priority=1
+// +kubebuilder:printcolumn:name="Healthy",type="string",JSONPath=".status.conditions[?(@.type=='Healthy')].status",description="Indicates if the last health snapshot of the MySQL Cluster is within the thresholds specified in the spec",priority=1
+// +kubebuilder:printcolumn:name="Pending Restart",type="string",JSONPath=".status.pendingDataNodeRestart",description="The type of the restart pending for the data nodes to apply the latest spec",priority=1
+// +kubebuilder:printcolumn:name="Image",type="string",JSONPath=".spec.image",description="The MySQL Cluster image used by the nodes",priority=1
 
 // NdbCluster is the Schema for the Ndb CRD API
 type NdbCluster struct {
@@ -69,21 +80,337 @@ type NdbClusterPodSpec struct {
 	// If specified, the pod's tolerations.
 	// +optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// InitContainers is a list of additional init containers to be run
+	// in the pod, after the init containers added by the operator.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+	// SidecarContainers is a list of additional containers to be run
+	// alongside the MySQL Cluster node container in the pod.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	SidecarContainers []corev1.Container `json:"sidecarContainers,omitempty"`
+	// Volumes is a list of additional volumes to be added to the pod.
+	// These can be mounted into the containers via volumeMounts and
+	// the volumeMounts of the init and sidecar containers.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+	// VolumeMounts is a list of additional volumes to be mounted
+	// into the MySQL Cluster node container of the pod.
+	// +optional
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+}
+
+// NdbPodDisruptionBudgetSpec is the specification of the PodDisruptionBudget
+// created by the operator for a MySQL Cluster node type
+type NdbPodDisruptionBudgetSpec struct {
+	// MinAvailable is the number (or percentage) of pods of the node type
+	// that must remain available during a voluntary disruption, like a K8s
+	// worker node drain. If unspecified, the operator chooses a value that
+	// keeps the MySQL Cluster available.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+}
+
+// NdbLoadBalancerSpec specifies the additional configuration of the
+// LoadBalancer Service created to expose a MySQL Cluster node type
+// outside the K8s Cluster
+type NdbLoadBalancerSpec struct {
+	// LoadBalancerClass is the class of the load balancer implementation
+	// that should handle the Service, e.g. a MetalLB or an AWS load balancer
+	// controller class. If unspecified, the default load balancer
+	// implementation of the cloud provider is used. Cannot be updated.
+	// +optional
+	LoadBalancerClass *string `json:"loadBalancerClass,omitempty"`
+	// LoadBalancerSourceRanges are the CIDRs of the clients allowed to
+	// access the load balancer, if supported by the cloud provider.
+	// +optional
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+	// ExternalTrafficPolicy specifies how the external traffic is routed
+	// to the pods. Local preserves the client source IP and avoids a
+	// second hop, but only the K8s worker nodes running a pod receive the
+	// traffic. If unspecified, the K8s default Cluster policy is used.
+	// +kubebuilder:validation:Enum=Cluster;Local
+	// +optional
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
+	// Annotations are the cloud provider specific annotations to be added
+	// only to the LoadBalancer Service, like the ones selecting an AWS NLB or
+	// a GCP internal load balancer. These take precedence over the
+	// serviceAnnotations.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// NdbNetworkPolicySpec is the specification of the NetworkPolicy created by
+// the operator to isolate the MySQL Cluster. The NetworkPolicy allows only the
+// traffic between the MySQL Cluster nodes, the traffic from the NDB Operator
+// and the traffic from the clients specified here. All other incoming
+// traffic to the MySQL Cluster pods is denied.
+type NdbNetworkPolicySpec struct {
+	// MySQLClients is a list of sources allowed to
+	// connect to the MySQL Servers on port 3306.
+	// +optional
+	MySQLClients []networkingv1.NetworkPolicyPeer `json:"mysqlClients,omitempty"`
+	// NdbAPIClients is a list of sources allowed to connect to the Management
+	// and Data nodes on port 1186. This is required for any NDBAPI application
+	// running outside the NdbCluster to connect via the free API slots.
+	// +optional
+	NdbAPIClients []networkingv1.NetworkPolicyPeer `json:"ndbAPIClients,omitempty"`
+}
+
+// NdbServiceAccountSpec is the specification of the ServiceAccount the
+// MySQL Cluster pods run as. The ServiceAccount is required only by the
+// agents, i.e. the init and the sidecar containers, that access the K8s
+// API Server from the MySQL Cluster pods.
+type NdbServiceAccountSpec struct {
+	// Name of an existing ServiceAccount the MySQL Cluster pods should run
+	// as. The operator neither creates nor grants any permissions to this
+	// ServiceAccount. When not specified, the operator creates a
+	// ServiceAccount, a Role and a RoleBinding, all named
+	// <ndbcluster-name>-ndb-agent, and runs the MySQL Cluster pods as
+	// that ServiceAccount.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Rules are the permissions granted, within the namespace of the
+	// NdbCluster, to the ServiceAccount created by the operator. The
+	// operator can only grant the permissions it holds itself. When not
+	// specified, the ServiceAccount is allowed to list and watch the pods
+	// and to get and watch the NdbCluster resource.
+	// +optional
+	Rules []rbacv1.PolicyRule `json:"rules,omitempty"`
+}
+
+// NdbAPIApplicationSpec reserves API sections in the MySQL Cluster config
+// for an NDBAPI application. The application should be run by a StatefulSet
+// governed by a headless Service, both named after the application, as the
+// sections are restricted to the stable hostnames of the StatefulSet pods.
+type NdbAPIApplicationSpec struct {
+	// Name of the application. It is also the name of the StatefulSet
+	// running the application and the name of its headless Service.
+	// +kubebuilder:validation:Pattern="^[a-z0-9]([a-z0-9-]*[a-z0-9])?$"
+	Name string `json:"name"`
+	// Namespace is the namespace the application runs in
+	// +kubebuilder:validation:Pattern="^[a-z0-9]([a-z0-9-]*[a-z0-9])?$"
+	Namespace string `json:"namespace"`
+	// NodeCount is the number of pods run by the application
+	// +kubebuilder:validation:Minimum=1
+	NodeCount int32 `json:"nodeCount"`
+	// ConnectionPoolSize is the number of connections a single pod of the
+	// application uses to connect to the MySQL Cluster. As many API sections
+	// are reserved for every pod.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=63
+	// +optional
+	ConnectionPoolSize int32 `json:"connectionPoolSize,omitempty"`
+}
+
+// GetConnectionPoolSize returns the connection pool size of the application
+func (app *NdbAPIApplicationSpec) GetConnectionPoolSize() int32 {
+	if app.ConnectionPoolSize < 1 {
+		return 1
+	}
+	return app.ConnectionPoolSize
+}
+
+// NdbClusterInitFromBackupSpec specifies the NDB native backup
+// to be restored into the MySQL Cluster when it is created.
+type NdbClusterInitFromBackupSpec struct {
+	// BackupID is the id of the backup to be restored.
+	// +kubebuilder:validation:Minimum=1
+	BackupID int32 `json:"backupId"`
+	// PersistentVolumeClaimName is the name of the PersistentVolumeClaim
+	// holding the backup files. It should exist in the namespace of the
+	// NdbCluster.
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName"`
+	// Path is the directory, relative to the root of the volume, holding
+	// the BACKUP-<backupId>.<nodeId>.ctl, .Data and .log files of all the
+	// data nodes that took the backup. If not specified, the backup files
+	// are expected to be in "BACKUP/BACKUP-<backupId>", which is the
+	// directory the data nodes write the backup into.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Parallelism is the maximum number of parallel transactions ndb_restore
+	// uses to restore the data. A higher value speeds up the restore of large
+	// backups at the cost of more load on the data nodes. If not specified,
+	// the ndb_restore default of 128 is used.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1024
+	// +optional
+	Parallelism int32 `json:"parallelism,omitempty"`
+	// RestoreEpoch, when enabled, makes ndb_restore record the epoch of the
+	// backup in the mysql.ndb_apply_status table, so that the MySQL Cluster
+	// can be set up as a replica starting from the binary log position of
+	// the source MySQL Cluster that matches the backup.
+	// +optional
+	RestoreEpoch bool `json:"restoreEpoch,omitempty"`
+	// IncludeDatabases is the list of the databases to be restored. When
+	// either IncludeDatabases or IncludeTables is specified, only the
+	// databases and the tables listed in them are restored.
+	// +optional
+	IncludeDatabases []string `json:"includeDatabases,omitempty"`
+	// ExcludeDatabases is the list of the databases not to be restored.
+	// +optional
+	ExcludeDatabases []string `json:"excludeDatabases,omitempty"`
+	// IncludeTables is the list of the tables, in the <database>.<table>
+	// format, to be restored. When either IncludeDatabases or IncludeTables
+	// is specified, only the databases and the tables listed in them are
+	// restored.
+	// +optional
+	IncludeTables []string `json:"includeTables,omitempty"`
+	// ExcludeTables is the list of the tables, in the <database>.<table>
+	// format, not to be restored.
+	// +optional
+	ExcludeTables []string `json:"excludeTables,omitempty"`
+	// RewriteDatabases specifies the databases of the backup to be restored
+	// into databases with different names. The filters are applied to the
+	// database names in the backup.
+	// +optional
+	RewriteDatabases []NdbClusterDatabaseRewrite `json:"rewriteDatabases,omitempty"`
+	// EncryptionPasswordSecretName is the name of the Secret that holds the
+	// password with which the backup was encrypted. The Secret should have a
+	// 'password' key that holds the password. It is required to restore an
+	// encrypted backup, and ndb_restore decrypts the backup with it.
+	// +optional
+	EncryptionPasswordSecretName string `json:"encryptionPasswordSecretName,omitempty"`
+}
+
+// NdbBackupSpec specifies the options of the
+// NDB native backups of the MySQL Cluster.
+type NdbBackupSpec struct {
+	// EncryptionPasswordSecretName is the name of the Secret that holds the
+	// password with which the backups taken by the operator are encrypted.
+	// The Secret should have a 'password' key that holds the password. The
+	// backups are verified with the same password when spec.verifyBackups
+	// is enabled, and so, the backups started via the ndb_mgm client should
+	// be encrypted with it as well.
+	// +optional
+	EncryptionPasswordSecretName string `json:"encryptionPasswordSecretName,omitempty"`
+	// Compression, when enabled, makes the data nodes compress the backup
+	// files they write, by setting their CompressedBackup config. The
+	// compressed backups are restored by ndb_restore without any extra
+	// option. Changing it restarts the data nodes.
+	// +optional
+	Compression bool `json:"compression,omitempty"`
+}
+
+// NdbClusterDatabaseRewrite specifies a database of the backup
+// to be restored into a database with a different name.
+type NdbClusterDatabaseRewrite struct {
+	// From is the name of the database in the backup.
+	// +kubebuilder:validation:MinLength=1
+	From string `json:"from"`
+	// To is the name of the database to restore it into.
+	// +kubebuilder:validation:MinLength=1
+	To string `json:"to"`
+}
+
+// NdbClusterInitFromDumpSpec specifies the SQL dump
+// to be loaded into the MySQL Cluster when it is created.
+type NdbClusterInitFromDumpSpec struct {
+	// PersistentVolumeClaimName is the name of the PersistentVolumeClaim
+	// holding the SQL dump. It should exist in the namespace of the
+	// NdbCluster.
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName"`
+	// Path is the path of the SQL dump file, relative to the root of the volume.
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+}
+
+// NdbClusterHealthMonitoringSpec specifies how often the health of the
+// MySQL Cluster is sampled from the ndbinfo database, and the thresholds
+// beyond which the MySQL Cluster is reported as unhealthy.
+type NdbClusterHealthMonitoringSpec struct {
+	// IntervalSeconds is the minimum interval, in seconds, between
+	// two consecutive samples of the health of the MySQL Cluster.
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=10
+	// +optional
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+	// DataMemoryUsageThreshold is the percentage of the DataMemory
+	// used by any of the data nodes, above which the MySQL Cluster
+	// is reported as unhealthy.
+	// +kubebuilder:default=90
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	DataMemoryUsageThreshold int32 `json:"dataMemoryUsageThreshold,omitempty"`
+	// RedoLogSpaceUsageThreshold is the percentage of the redo log space
+	// used by any of the data nodes, above which the MySQL Cluster is
+	// reported as unhealthy.
+	// +kubebuilder:default=80
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	RedoLogSpaceUsageThreshold int32 `json:"redoLogSpaceUsageThreshold,omitempty"`
+	// LockWaitsThreshold is the number of operations waiting for a row
+	// lock, above which the MySQL Cluster is reported as unhealthy.
+	// +kubebuilder:default=100
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	LockWaitsThreshold int32 `json:"lockWaitsThreshold,omitempty"`
+}
+
+// NdbPodDNSSpec specifies how the MySQL Cluster pods wait for their per-pod
+// DNS records to be published before starting the MySQL Cluster nodes.
+type NdbPodDNSSpec struct {
+	// StabilizationSeconds is the period, in seconds, for which the hostname
+	// of a pod has to be resolved continuously to its own address before the
+	// MySQL Cluster node in it is started. The DNS records are cached, and
+	// served by multiple replicas, in most K8s Clusters, and a longer period
+	// makes it more likely that all the replicas resolve the hostname when
+	// the other nodes look it up during the initial start of the MySQL Cluster.
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=300
+	// +optional
+	StabilizationSeconds int32 `json:"stabilizationSeconds,omitempty"`
 }
 
 // NdbManagementNodeSpec is the specification of management node in MySQL Cluster
 type NdbManagementNodeSpec struct {
+	// NodeCount is the number of Management nodes, and can be either 1 or
+	// 2. If unspecified, a single Management node is run when the
+	// redundancyLevel is 1, and two otherwise. With a single Management
+	// node, the Data nodes lose their arbitrator whenever the Management
+	// node is restarted or unavailable, so two Management nodes, or an
+	// external arbitrator, are recommended when the redundancyLevel is
+	// more than 1. Two Management nodes are preferably run on different
+	// worker nodes and zones. Cannot be updated.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2
+	// +optional
+	NodeCount int32 `json:"nodeCount,omitempty"`
 	// Config is a map of default MySQL Cluster Management node configurations.
+	// Any change to them is applied by a rolling restart of the Management nodes.
 	//
 	// More info :
 	// https://dev.mysql.com/doc/refman/8.0/en/mysql-cluster-params-mgmd.html
 	// +optional
 	Config map[string]*intstr.IntOrString `json:"config,omitempty"`
+	// LogDestination specifies where the Management nodes write the cluster
+	// log and is set as the LogDestination of the Management nodes. If not
+	// specified, the cluster log is written to a file in the data directory
+	// of the Management nodes, and also to their console when the NDB
+	// Operator streams the cluster logs via its -stream-cluster-log flag.
+	//
+	// More info :
+	// https://dev.mysql.com/doc/refman/8.0/en/mysql-cluster-mgm-definition.html#ndbparam-mgmd-logdestination
+	// +optional
+	LogDestination string `json:"logDestination,omitempty"`
 	// NdbPodSpec contains a subset of PodSpec fields which when
 	// set will be copied into to the podSpec of Management node's
 	// statefulset definition.
 	// +optional
 	NdbPodSpec *NdbClusterPodSpec `json:"ndbPodSpec,omitempty"`
+	// Image is the name of the image to be used by the Management node containers.
+	// If not specified, spec.image will be used.
+	// +optional
+	Image string `json:"image,omitempty"`
 	// EnableLoadBalancer exposes the management servers externally using the
 	// kubernetes cloud provider's load balancer. By default, the operator creates a ClusterIP
 	// type service to expose the management server pods internally within the kubernetes cluster.
@@ -92,21 +419,342 @@ type NdbManagementNodeSpec struct {
 	// +kubebuilder:default=false
 	// +optional
 	EnableLoadBalancer bool `json:"enableLoadBalancer,omitempty"`
+	// LoadBalancer specifies the additional configuration of the LoadBalancer
+	// Service created when the enableLoadBalancer is set to true.
+	// +optional
+	LoadBalancer *NdbLoadBalancerSpec `json:"loadBalancer,omitempty"`
+	// PodDisruptionBudget specifies the PodDisruptionBudget to be created
+	// for the Management nodes. If unspecified, no Management node will be
+	// allowed to be evicted when there is only one Management node and one
+	// will be allowed to be evicted at a time when there are two.
+	// +optional
+	PodDisruptionBudget *NdbPodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+	// PodLabels are the additional labels to be added to the Management node pods.
+	// These are merged with, and take precedence over, spec.podLabels.
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+	// PodAnnotations are the additional annotations to be added to the Management node pods.
+	// These are merged with, and take precedence over, spec.podAnnotations.
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+	// ServiceAnnotations are the additional annotations to be added to the
+	// Services created for the Management nodes. These are merged with, and take
+	// precedence over, spec.serviceAnnotations.
+	// +optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+	// UpdateStrategy is the update strategy of the Management node StatefulSet.
+	// By default, a RollingUpdate is done by the StatefulSet controller. A
+	// partition can be set to stage the rollout, and the operator will wait
+	// for the rollout to complete before continuing with the sync. If set to
+	// OnDelete, the operator restarts the outdated Management nodes one by one.
+	// +optional
+	UpdateStrategy *appsv1.StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
+	// PodManagementPolicy is the pod management policy of the Management node
+	// StatefulSet. Defaults to OrderedReady. Cannot be updated.
+	// +kubebuilder:validation:Enum=OrderedReady;Parallel
+	// +optional
+	PodManagementPolicy appsv1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
+	// HostNetwork, when enabled, runs the Management nodes in the network
+	// of their K8s worker nodes to avoid the latency of the overlay network.
+	// Not more than one Management node is scheduled onto a worker node, as
+	// they all use the same port of the worker node. Cannot be updated.
+	// +optional
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+	// Port is the port on which the Management nodes, and the external
+	// arbitrator, listen for the connections from the other nodes and
+	// the clients. It is set as the PortNumber of the Management nodes
+	// and is used in the connectstring. Defaults to 1186. Cannot be updated.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// NdbArbitratorSpec is the specification of the external arbitrator
+// run by the operator to arbitrate between the data nodes when the
+// MySQL Cluster is partitioned.
+type NdbArbitratorSpec struct {
+	// Zone is the zone, as per the topology.kubernetes.io/zone label of
+	// the K8s worker nodes, in which the arbitrator has to run. It should
+	// be a failure domain different from the ones running the Data nodes,
+	// so that the arbitrator remains available when one of them is lost.
+	// If not specified, the arbitrator can be scheduled onto any worker
+	// node allowed by the ndbPodSpec.
+	// +optional
+	Zone string `json:"zone,omitempty"`
+	// NdbPodSpec contains a subset of PodSpec fields which when
+	// set will be copied into to the podSpec of the arbitrator's
+	// statefulset definition.
+	// +optional
+	NdbPodSpec *NdbClusterPodSpec `json:"ndbPodSpec,omitempty"`
+}
+
+// NdbStartupProbeSpec specifies the thresholds of the startup
+// probe used to detect if a MySQL Cluster node has started.
+type NdbStartupProbeSpec struct {
+	// PeriodSeconds specifies how often, in seconds, the probe is run.
+	// +kubebuilder:default=2
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+	// TimeoutSeconds is the number of seconds after which the probe times out.
+	// +kubebuilder:default=2
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// FailureThreshold is the number of consecutive failures of the probe
+	// after which the node is considered to be stuck and its container is
+	// restarted. The node is allowed periodSeconds * failureThreshold
+	// seconds to complete its start.
+	// +kubebuilder:default=450
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+}
+
+// NdbDataNodeRemediationSpec specifies how the operator
+// recovers the data nodes that fail to start repeatedly.
+type NdbDataNodeRemediationSpec struct {
+	// RestartThreshold is the number of consecutive times a data node
+	// container can be restarted by the kubelet, within 15 minutes and
+	// without becoming ready in between, before the operator recovers the
+	// data node. The data node is recovered only while the kubelet is
+	// backing off restarting the container, i.e. it is in CrashLoopBackOff,
+	// and the Management Server does not report the data node as starting.
+	// The operator first restarts the data node by deleting its pod, and if
+	// the data node reaches the threshold again within 15 minutes, performs
+	// an initial restart of the data node. An initial restart deletes the
+	// data node's pod along with its PersistentVolumeClaims, if any, and the
+	// data node recovers all its data from the other data nodes of its node
+	// group. The initial restart is performed only when another data node
+	// of the same node group is connected to the MySQL Cluster.
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RestartThreshold int32 `json:"restartThreshold,omitempty"`
+}
+
+// NdbDataNodeLogLevels specifies the levels of the events reported by the
+// data nodes to the cluster log, for each of the event categories. An event
+// is logged only if its priority is less than or equal to the level of its
+// category. The levels range from 0, which disables the logging of the
+// category, to 15.
+type NdbDataNodeLogLevels struct {
+	// Startup is the LogLevelStartup of the data nodes.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=15
+	// +optional
+	Startup *int32 `json:"startup,omitempty"`
+	// Shutdown is the LogLevelShutdown of the data nodes.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=15
+	// +optional
+	Shutdown *int32 `json:"shutdown,omitempty"`
+	// Statistic is the LogLevelStatistic of the data nodes.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=15
+	// +optional
+	Statistic *int32 `json:"statistic,omitempty"`
+	// Checkpoint is the LogLevelCheckpoint of the data nodes.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=15
+	// +optional
+	Checkpoint *int32 `json:"checkpoint,omitempty"`
+	// NodeRestart is the LogLevelNodeRestart of the data nodes.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=15
+	// +optional
+	NodeRestart *int32 `json:"nodeRestart,omitempty"`
+	// Connection is the LogLevelConnection of the data nodes.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=15
+	// +optional
+	Connection *int32 `json:"connection,omitempty"`
+	// Congestion is the LogLevelCongestion of the data nodes.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=15
+	// +optional
+	Congestion *int32 `json:"congestion,omitempty"`
+	// Error is the LogLevelError of the data nodes.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=15
+	// +optional
+	Error *int32 `json:"error,omitempty"`
+	// Info is the LogLevelInfo of the data nodes.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=15
+	// +optional
+	Info *int32 `json:"info,omitempty"`
+}
+
+// NdbDiskDataFileSpec is the specification of a file
+// used by the Disk Data objects of the MySQL Cluster
+type NdbDiskDataFileSpec struct {
+	// Name of the file. The file is created in the data
+	// directory of every data node of the MySQL Cluster.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_][a-zA-Z0-9_.-]*$`
+	Name string `json:"name"`
+	// Size is the initial size of the file. If not specified, the file is
+	// sized automatically from the storage requested by the data node PVCs.
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+}
+
+// NdbLogfileGroupSpec is the specification of
+// the logfile group of the MySQL Cluster
+type NdbLogfileGroupSpec struct {
+	// Name of the logfile group
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_]+$`
+	Name string `json:"name"`
+	// UndoBufferSize is the size of the buffer used by the logfile group
+	// to hold the undo log records before they are written to the undo
+	// log files. If not specified, the MySQL Cluster default is used.
+	// +optional
+	UndoBufferSize *resource.Quantity `json:"undoBufferSize,omitempty"`
+	// UndoFiles are the undo log files of the logfile group
+	// +kubebuilder:validation:MinItems=1
+	UndoFiles []NdbDiskDataFileSpec `json:"undoFiles"`
+}
+
+// NdbTablespaceSpec is the specification of a tablespace of the MySQL Cluster
+type NdbTablespaceSpec struct {
+	// Name of the tablespace
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_]+$`
+	Name string `json:"name"`
+	// DataFiles are the data files of the tablespace
+	// +kubebuilder:validation:MinItems=1
+	DataFiles []NdbDiskDataFileSpec `json:"dataFiles"`
+}
+
+// NdbDiskDataSpec is the specification of the Disk Data
+// objects to be created in the MySQL Cluster
+type NdbDiskDataSpec struct {
+	// LogfileGroup is the logfile group used by all the tablespaces.
+	// MySQL Cluster supports only one logfile group at a time.
+	LogfileGroup NdbLogfileGroupSpec `json:"logfileGroup"`
+	// Tablespaces are the tablespaces available to store
+	// the non-indexed columns of the NDB tables on disk.
+	// +optional
+	Tablespaces []NdbTablespaceSpec `json:"tablespaces,omitempty"`
+}
+
+// NdbHugePagesSpec is the specification of the huge pages
+// to be allocated to each of the data node pods
+type NdbHugePagesSpec struct {
+	// PageSize is the size of the huge pages to be used.
+	// The K8s worker nodes should have pre-allocated huge pages
+	// of this size for them to be requested by the data nodes.
+	// +kubebuilder:validation:Enum="2Mi";"1Gi"
+	PageSize string `json:"pageSize"`
+	// Size is the total amount of huge page memory to be requested by
+	// each data node. It should be a multiple of the pageSize.
+	Size resource.Quantity `json:"size"`
+}
+
+// NdbDataNodeVolumesSpec specifies the separate volumes to be used by
+// the data nodes for the different kinds of files, so that their IO does
+// not compete with the IO of the other files on the same volume. A PVC is
+// created for every data node from each of the specified PVC specs.
+type NdbDataNodeVolumesSpec struct {
+	// FileSystem is the PersistentVolumeClaimSpec of the volume to be used
+	// as the FileSystemPath of the data nodes, which holds their redo logs
+	// and local checkpoints. The data files of the tablespaces continue to
+	// be stored in the data directory.
+	// +optional
+	FileSystem *corev1.PersistentVolumeClaimSpec `json:"fileSystem,omitempty"`
+	// Backup is the PersistentVolumeClaimSpec of the
+	// volume to be used as the BackupDataDir of the data nodes.
+	// +optional
+	Backup *corev1.PersistentVolumeClaimSpec `json:"backup,omitempty"`
+	// UndoFiles is the PersistentVolumeClaimSpec of the volume to be used
+	// as the FileSystemPathUndoFiles of the data nodes, which holds the
+	// undo log files of the logfile group.
+	// +optional
+	UndoFiles *corev1.PersistentVolumeClaimSpec `json:"undoFiles,omitempty"`
+}
+
+// NdbDataNodeLocalVolumesSpec specifies how the operator handles the data
+// nodes whose PVCs are bound to local PersistentVolumes, which are lost
+// along with the worker nodes hosting them.
+type NdbDataNodeLocalVolumesSpec struct {
+	// NodeLossTimeoutSeconds is the time, in seconds, for which a worker
+	// node hosting the local PersistentVolume of a data node should not be
+	// ready, before it is considered lost for good. A worker node that has
+	// been deleted from the K8s Cluster is considered lost once it has been
+	// missing for this time, measured from when the operator first found it
+	// missing.
+	// +kubebuilder:default=600
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	NodeLossTimeoutSeconds int32 `json:"nodeLossTimeoutSeconds,omitempty"`
+	// Reschedule, when enabled, lets the operator reschedule a data node
+	// whose worker node is lost onto another worker node, by deleting its
+	// pod along with its PersistentVolumeClaims. The data node then starts
+	// with an initial restart on a new local PersistentVolume and recovers
+	// all its data from the other data nodes of its node group. A data
+	// node is rescheduled only when another data node of the same node
+	// group is connected to the MySQL Cluster, and only one data node is
+	// rescheduled at a time. When disabled, the lost worker nodes are only
+	// reported via the LocalVolumesAvailable condition. The local
+	// PersistentVolumes of the lost worker nodes are not deleted.
+	// +optional
+	Reschedule bool `json:"reschedule,omitempty"`
+}
+
+// NdbDataNodeSystemRestartSpec specifies if and how the operator applies
+// the changes to the data node parameters that require a system restart.
+type NdbDataNodeSystemRestartSpec struct {
+	// Allowed, when enabled, acknowledges that the MySQL Cluster will be
+	// unavailable while the operator applies a change that requires a
+	// system restart, by stopping all the data nodes together and starting
+	// them again with the new config. When disabled, such changes are not
+	// applied and the NdbCluster is reported to be pending a system restart.
+	// Disable it once the change is applied, to prevent any later change
+	// from restarting the whole MySQL Cluster unexpectedly. For a MySQL
+	// Cluster with a redundancy level of 1, where a data node cannot be
+	// restarted without making the MySQL Cluster unavailable anyway, this
+	// also allows its spec to be updated, and every restart of the data
+	// nodes is done via a system restart.
+	// +optional
+	Allowed bool `json:"allowed,omitempty"`
+	// BackupBeforeRestart, when enabled, makes the operator take an NDB
+	// native backup of the MySQL Cluster, and wait for it to complete,
+	// before stopping the data nodes. The backup is stored in the
+	// BackupDataDir of the data nodes, so spec.dataNode.separateVolumes.backup
+	// should be specified to retain the backup across an initial system
+	// restart, which deletes the data volumes of the data nodes.
+	// +optional
+	BackupBeforeRestart bool `json:"backupBeforeRestart,omitempty"`
 }
 
 // NdbDataNodeSpec is the specification of data node in MySQL Cluster
 type NdbDataNodeSpec struct {
 	// Config is a map of default MySQL Cluster Data node configurations.
+	// Any change to them is applied to the Management nodes by reloading
+	// their config, without restarting them, and then to the Data nodes
+	// by a rolling restart.
 	//
 	// More info :
 	// https://dev.mysql.com/doc/refman/8.0/en/mysql-cluster-params-ndbd.html
 	// +optional
 	Config map[string]*intstr.IntOrString `json:"config,omitempty"`
+	// LogLevels specifies the levels of the events reported by the data nodes
+	// to the cluster log. A change in the levels is applied to the MySQL
+	// Cluster through a rolling restart, like any other config change.
+	//
+	// More info :
+	// https://dev.mysql.com/doc/refman/8.0/en/mysql-cluster-logging-management-commands.html
+	// +optional
+	LogLevels *NdbDataNodeLogLevels `json:"logLevels,omitempty"`
 	// NdbPodSpec contains a subset of PodSpec fields which when
 	// set will be copied into to the podSpec of Data node's statefulset
 	// definition.
 	// +optional
 	NdbPodSpec *NdbClusterPodSpec `json:"ndbPodSpec,omitempty"`
+	// Image is the name of the image to be used by the Data node containers.
+	// If not specified, spec.image will be used.
+	// +optional
+	Image string `json:"image,omitempty"`
 	// The total number of data nodes in MySQL Cluster.
 	// The node count needs to be a multiple of the
 	// redundancyLevel. A maximum of 144 data nodes are
@@ -117,11 +765,410 @@ type NdbDataNodeSpec struct {
 	// PVCSpec is the PersistentVolumeClaimSpec to be used as the
 	// VolumeClaimTemplate of the data node statefulset. A PVC will be created
 	// for each data node by the statefulset controller and will be loaded into
-	// the data node pod and the container.
+	// the data node pod and the container. Only the storage request can be
+	// updated, and only increased, once the NdbCluster has been created. The
+	// operator then expands the existing PVCs, if their storage class allows
+	// it, and reports the progress via the StorageResized condition.
+	// +optional
+	PVCSpec *corev1.PersistentVolumeClaimSpec `json:"pvcSpec,omitempty"`
+	// SeparateVolumes specifies the separate volumes to be used for the
+	// FileSystemPath, the BackupDataDir and the undo log files of the data
+	// nodes, instead of storing them in the data directory. The config
+	// params set via these volumes should not be specified again in the
+	// spec.dataNode.config. This value is immutable.
+	// +optional
+	SeparateVolumes *NdbDataNodeVolumesSpec `json:"separateVolumes,omitempty"`
+	// LocalVolumes, when specified, enables the operator to detect the data
+	// nodes whose PVCs are bound to local PersistentVolumes on worker nodes
+	// that are lost for good, and to optionally reschedule them onto other
+	// worker nodes. The lost worker nodes are reported via the
+	// LocalVolumesAvailable condition. This requires spec.dataNode.pvcSpec
+	// to be specified.
+	// +optional
+	LocalVolumes *NdbDataNodeLocalVolumesSpec `json:"localVolumes,omitempty"`
+	// StartupProbe specifies the thresholds of the startup probe of the data
+	// nodes. Data nodes with a large DataMemory can take a long time to complete
+	// their start phases, and the probe has to allow for that. By default, a
+	// data node is allowed 15 minutes to start.
+	// +optional
+	StartupProbe *NdbStartupProbeSpec `json:"startupProbe,omitempty"`
+	// Remediation, when specified, enables the operator to recover the data
+	// nodes that are stuck in their start phases or are crash looping, by
+	// performing an initial restart of them. Any data stored in the local
+	// file system of such a data node is lost and is recovered from the
+	// other data nodes of its node group.
+	// +optional
+	Remediation *NdbDataNodeRemediationSpec `json:"remediation,omitempty"`
+	// SystemRestart, when specified, lets the operator apply the changes to
+	// the data node parameters, like NoOfReplicas or Diskless, that can only
+	// be changed by restarting all the data nodes together. Such changes
+	// are rejected by the operator if this is not specified.
+	// +optional
+	SystemRestart *NdbDataNodeSystemRestartSpec `json:"systemRestart,omitempty"`
+	// DiskData specifies the logfile group and the tablespaces to be created
+	// by the operator, through a MySQL Server, once the MySQL Cluster is
+	// ready. New files added to the spec are added to the existing objects.
+	// The objects and files removed from the spec are not dropped by the
+	// operator. Creating the Disk Data objects requires at least one MySQL
+	// Server, and the files are stored in the data node PVCs, if any.
+	// The files without a size are sized automatically : half of the
+	// storage requested by the data node PVCs is made available to the
+	// Disk Data files, of which a fifth is split evenly among the undo
+	// files and the rest among the data files. The undo files share all
+	// of the storage of the separate undo files volumes instead, if any.
+	//
+	// More info :
+	// https://dev.mysql.com/doc/refman/8.0/en/mysql-cluster-disk-data.html
+	// +optional
+	DiskData *NdbDiskDataSpec `json:"diskData,omitempty"`
+	// PodLabels are the additional labels to be added to the Data node pods.
+	// These are merged with, and take precedence over, spec.podLabels.
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+	// PodAnnotations are the additional annotations to be added to the Data node pods.
+	// These are merged with, and take precedence over, spec.podAnnotations.
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+	// ServiceAnnotations are the additional annotations to be added to the
+	// Services created for the Data nodes. These are merged with, and take
+	// precedence over, spec.serviceAnnotations.
+	// +optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+	// PodManagementPolicy is the pod management policy of the Data node
+	// StatefulSet. Defaults to Parallel. Cannot be updated. Note that the
+	// Data node StatefulSet always uses the OnDelete update strategy, as
+	// the operator restarts the Data nodes one node group at a time.
+	// +kubebuilder:validation:Enum=OrderedReady;Parallel
+	// +optional
+	PodManagementPolicy appsv1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
+	// HostNetwork, when enabled, runs the Data nodes in the network of
+	// their K8s worker nodes to avoid the latency of the overlay network
+	// between them. The Data nodes then use the port 11860 instead of
+	// 1186, unless a serverPort is specified, so that they can share a
+	// worker node with a Management node, but not more than one Data
+	// node is scheduled onto a worker node. Cannot be updated.
+	// +optional
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+	// ServerPort is the port on which the Data nodes listen for the
+	// connections from the other nodes. It is set as the ServerPort of
+	// all the Data nodes. Defaults to 1186, or to 11860 if the hostNetwork
+	// is enabled. Cannot be updated.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	ServerPort int32 `json:"serverPort,omitempty"`
+	// Zones, when specified, are the zones, as per the
+	// topology.kubernetes.io/zone label of the K8s worker nodes, across
+	// which the Data nodes have to be placed. The operator plans the
+	// placement by assigning the Data nodes to the zones in a round-robin
+	// order of their ordinals, so that the Data nodes of every node group
+	// are placed in distinct zones. The number of zones should be at least
+	// the redundancyLevel. The Data node pods are restricted to these zones,
+	// and every pod is pinned to the zone planned for its ordinal before it
+	// is scheduled, which requires K8s 1.27 or later. The actual placement
+	// is verified and reported via the ZoneRedundant condition in the status.
+	// This value is immutable.
+	// +listType=atomic
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+	// UseNdbd, when enabled, runs the single threaded data node binary
+	// ndbd instead of the default multi-threaded ndbmtd. A change in this
+	// value is applied to the MySQL Cluster through a rolling restart.
+	// +optional
+	UseNdbd bool `json:"useNdbd,omitempty"`
+	// ThreadConfig specifies the number, the types and the CPU bindings of
+	// the threads run by the multi-threaded data nodes. It is rendered as
+	// the ThreadConfig parameter of the data nodes and so, it should not
+	// be specified again in the spec.dataNode.config. This cannot be
+	// specified when spec.dataNode.useNdbd is enabled.
+	//
+	// More info :
+	// https://dev.mysql.com/doc/refman/8.0/en/mysql-cluster-ndbd-definition.html#ndbparam-ndbmtd-threadconfig
+	// +optional
+	ThreadConfig string `json:"threadConfig,omitempty"`
+	// HugePages, when specified, requests the given amount of huge pages
+	// for each data node pod and mounts them into the data node container
+	// at /dev/hugepages. Requesting huge pages also requires a cpu or a
+	// memory resource to be specified in the spec.dataNode.ndbPodSpec.
+	// +optional
+	HugePages *NdbHugePagesSpec `json:"hugePages,omitempty"`
+	// LockPagesInMainMemory is the LockPagesInMainMemory of the data
+	// nodes. When set to 1 or 2, the data nodes lock their memory to
+	// prevent it from being swapped out, and the data node container is
+	// given the IPC_LOCK capability required to do so. It should not be
+	// specified again in the spec.dataNode.config.
+	//
+	// More info :
+	// https://dev.mysql.com/doc/refman/8.0/en/mysql-cluster-ndbd-definition.html#ndbparam-ndbd-lockpagesinmainmemory
+	// +kubebuilder:validation:Enum=0;1;2
+	// +optional
+	LockPagesInMainMemory *int32 `json:"lockPagesInMainMemory,omitempty"`
+	// Numa, when specified, sets the Numa parameter of the data nodes,
+	// which controls whether the data nodes interleave their memory
+	// allocations across all the NUMA nodes of the K8s worker node. It
+	// should not be specified again in the spec.dataNode.config.
+	//
+	// More info :
+	// https://dev.mysql.com/doc/refman/8.0/en/mysql-cluster-ndbd-definition.html#ndbparam-ndbd-numa
+	// +optional
+	Numa *bool `json:"numa,omitempty"`
+}
+
+// NdbMysqldAutoscalingSpec is the specification of the HorizontalPodAutoscaler
+// to be created by the operator to scale the MySQL Servers
+type NdbMysqldAutoscalingSpec struct {
+	// MinNodeCount is the lower limit for the number of
+	// MySQL Servers that can be set by the autoscaler.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinNodeCount int32 `json:"minNodeCount,omitempty"`
+	// MaxNodeCount is the upper limit for the number of MySQL Servers
+	// that can be set by the autoscaler. This cannot exceed the
+	// spec.mysqlNode.maxNodeCount as scaling beyond the API slots
+	// reserved for the MySQL Servers requires a MySQL Cluster config
+	// update. If unspecified, spec.mysqlNode.maxNodeCount will be used.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxNodeCount int32 `json:"maxNodeCount,omitempty"`
+	// TargetCPUUtilizationPercentage is the target average CPU utilization
+	// of the MySQL Servers, represented as a percentage of the requested CPU.
+	// If neither this nor targetConnectionsPerServer is specified, a target
+	// CPU utilization of 80% will be used.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+	// TargetConnectionsPerServer is the target average number of client
+	// connections per MySQL Server. This requires a custom metrics API
+	// serving the pod metric specified by connectionsMetricName.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetConnectionsPerServer *int32 `json:"targetConnectionsPerServer,omitempty"`
+	// ConnectionsMetricName is the name of the pod metric that holds the
+	// number of client connections to a MySQL Server.
+	// +kubebuilder:default="mysql_global_status_threads_connected"
+	// +optional
+	ConnectionsMetricName string `json:"connectionsMetricName,omitempty"`
+}
+
+// NdbMysqldCanaryRolloutSpec specifies how an update to the MySQL Servers
+// is verified on a single canary MySQL Server before rolling it out to all
+// the MySQL Servers
+type NdbMysqldCanaryRolloutSpec struct {
+	// HealthCheckQueries are the SQL queries executed on the canary MySQL
+	// Server, once it is ready, to verify that it is healthy. A query is
+	// considered to have passed if it succeeds and returns at least one row.
+	// If unspecified, the canary is verified to be connected to the ready
+	// Data nodes of the MySQL Cluster.
+	// +optional
+	HealthCheckQueries []string `json:"healthCheckQueries,omitempty"`
+	// TimeoutSeconds is the time, in seconds, within which the canary MySQL
+	// Server should become ready and pass the health check. The update is
+	// rolled back if the canary doesn't become ready within this time or if
+	// it fails the health check. A health check that cannot be run, e.g. as
+	// the canary cannot be connected to yet, is retried until this time.
+	// +kubebuilder:default=600
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// NdbMysqldPasswordValidationSpec specifies the settings
+// of the validate_password component of the MySQL Servers.
+//
+// More info :
+// https://dev.mysql.com/doc/refman/8.0/en/validate-password-options-variables.html
+type NdbMysqldPasswordValidationSpec struct {
+	// Policy is the password policy enforced by the component.
+	// +kubebuilder:validation:Enum:={LOW, MEDIUM, STRONG}
+	// +kubebuilder:default="MEDIUM"
+	// +optional
+	Policy string `json:"policy,omitempty"`
+	// Length is the minimum number of characters in the passwords.
+	// +kubebuilder:default=8
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Length int32 `json:"length,omitempty"`
+	// MixedCaseCount is the minimum number of lowercase and uppercase
+	// characters the passwords must have, if the policy is MEDIUM or
+	// stronger.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MixedCaseCount int32 `json:"mixedCaseCount,omitempty"`
+	// NumberCount is the minimum number of numeric characters the
+	// passwords must have, if the policy is MEDIUM or stronger.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	NumberCount int32 `json:"numberCount,omitempty"`
+	// SpecialCharCount is the minimum number of nonalphanumeric characters
+	// the passwords must have, if the policy is MEDIUM or stronger.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SpecialCharCount int32 `json:"specialCharCount,omitempty"`
+}
+
+// NdbMysqldPlugin is a MySQL Server plugin to be
+// installed via the INSTALL PLUGIN statement.
+type NdbMysqldPlugin struct {
+	// Name of the plugin, like "audit_log".
+	// +kubebuilder:validation:Pattern="^[a-zA-Z0-9_]+$"
+	Name string `json:"name"`
+	// Library is the name of the shared library file, in the
+	// plugin directory, that contains the plugin, like "audit_log.so".
+	// +kubebuilder:validation:Pattern="^[a-zA-Z0-9_.-]+$"
+	Library string `json:"library"`
+}
+
+// NdbMysqldPluginsSpec specifies the custom plugins and
+// components to be installed in the MySQL Servers.
+type NdbMysqldPluginsSpec struct {
+	// InitContainers are the init containers that install the shared
+	// libraries of the plugins and the components into the MySQL Server
+	// plugin directory. The plugin directory is mounted into these
+	// containers at /var/lib/ndb/plugins, and already has the plugins
+	// and the components shipped with the MySQL Server image.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+	// Plugins are the plugins to be installed by the operator in the
+	// MySQL Servers once they are started. The plugins are registered
+	// in the data directories of the MySQL Servers, and removing them
+	// from the spec does not uninstall them.
+	// +optional
+	Plugins []NdbMysqldPlugin `json:"plugins,omitempty"`
+	// Components are the URNs of the components, like
+	// "file://component_audit_api_message_emit", to be installed by
+	// the operator in the MySQL Servers once they are started. Like
+	// the plugins, removing them from the spec does not uninstall them.
+	// +optional
+	Components []string `json:"components,omitempty"`
+}
+
+// NdbMysqldAuditLogSpec specifies the audit log of the MySQL Servers,
+// written by the MySQL Enterprise Audit plugin.
+//
+// More info :
+// https://dev.mysql.com/doc/refman/8.0/en/audit-log-reference.html
+type NdbMysqldAuditLogSpec struct {
+	// Format is the format of the audit log file.
+	// +kubebuilder:validation:Enum:={NEW, OLD, JSON}
+	// +kubebuilder:default="JSON"
+	// +optional
+	Format string `json:"format,omitempty"`
+	// Policy specifies the events written to the audit log file.
+	// +kubebuilder:validation:Enum:={ALL, LOGINS, QUERIES, NONE}
+	// +kubebuilder:default="ALL"
+	// +optional
+	Policy string `json:"policy,omitempty"`
+	// RotateOnSize is the size at which the audit log file is rotated.
+	// It should be at least 4Ki. If unspecified, the file is not rotated.
+	// +optional
+	RotateOnSize *resource.Quantity `json:"rotateOnSize,omitempty"`
+	// PVCSpec is the PersistentVolumeClaimSpec of the volumes storing the
+	// audit log files of the MySQL Servers. If unspecified, the files are
+	// stored in an emptyDir volume and are lost when the pods are deleted.
+	// Cannot be updated.
 	// +optional
 	PVCSpec *corev1.PersistentVolumeClaimSpec `json:"pvcSpec,omitempty"`
+	// SidecarContainers are the containers that ship the audit log files
+	// from the MySQL Server pods. The volume storing the audit log files
+	// is mounted into these containers at /var/lib/ndb/audit-log.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	SidecarContainers []corev1.Container `json:"sidecarContainers,omitempty"`
+}
+
+// NdbMysqldGatewaySpec specifies the Gateway API resources created by the
+// operator to expose the MySQL Servers outside the K8s Cluster, as an
+// alternative to a LoadBalancer Service. A TCPRoute forwarding the MySQL
+// traffic to the MySQL Servers is attached either to a Gateway created by
+// the operator or to an existing Gateway. The TLS connections of the MySQL
+// clients are passed through unchanged to the MySQL Servers.
+type NdbMysqldGatewaySpec struct {
+	// GatewayClassName is the name of the GatewayClass of the Gateway to be
+	// created by the operator. Either the gatewayClassName or the gatewayName
+	// must be specified.
+	// +optional
+	GatewayClassName string `json:"gatewayClassName,omitempty"`
+	// GatewayName is the name of an existing Gateway, in the namespace of the
+	// NdbCluster, to attach the TCPRoute to, instead of creating a new Gateway.
+	// The Gateway should have a listener with the TCP protocol that allows
+	// routes from the namespace of the NdbCluster.
+	// +optional
+	GatewayName string `json:"gatewayName,omitempty"`
+	// ListenerName is the name of the listener of the existing Gateway to
+	// attach the TCPRoute to. If unspecified, the TCPRoute is attached to
+	// all the compatible listeners of the Gateway.
+	// +optional
+	ListenerName string `json:"listenerName,omitempty"`
+	// Port is the port of the listener of the Gateway created by the operator
+	// +kubebuilder:default=3306
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// Annotations are the additional annotations to be
+	// added to the Gateway created by the operator
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// NdbMysqldServerGroupSpec is the specification of an additional group of
+// MySQL Servers, that are run by a separate StatefulSet with their own
+// my.cnf and Service, and connect to the same MySQL Cluster as the MySQL
+// Servers specified by the spec.mysqlNode.
+type NdbMysqldServerGroupSpec struct {
+	// Name of the MySQL Server group. The StatefulSet and the Service of
+	// the group are named "<ndb-resource-name>-<name>-mysqld".
+	// +kubebuilder:validation:MaxLength=20
+	// +kubebuilder:validation:Pattern="^[a-z0-9]([a-z0-9-]*[a-z0-9])?$"
+	Name string `json:"name"`
+	// NodeCount is the number of MySQL Servers to be run in the group.
+	// Unlike the spec.mysqlNode.nodeCount, no [mysqld] sections are
+	// reserved upfront for the group, so changing it updates the MySQL
+	// Cluster config.
+	// +kubebuilder:validation:Minimum=1
+	NodeCount int32 `json:"nodeCount"`
+	// Configuration options to pass to the MySQL Servers of the group
+	// when they are started.
+	// +optional
+	MyCnf string `json:"myCnf,omitempty"`
+	// EnableLoadBalancer exposes the MySQL Servers of the group externally
+	// using a LoadBalancer type Service.
+	// +optional
+	EnableLoadBalancer bool `json:"enableLoadBalancer,omitempty"`
+}
+
+// GetMySQLCnf returns the my.cnf specified for the MySQL Server group
+func (sg *NdbMysqldServerGroupSpec) GetMySQLCnf() string {
+	return getMySQLCnfWithSection(sg.MyCnf)
 }
 
+// NdbRootPasswordSecretPolicy specifies how the root password
+// Secret generated by the operator is handled when the MySQL
+// Servers are removed and when the NdbCluster is deleted
+type NdbRootPasswordSecretPolicy string
+
+const (
+	// NdbRootPasswordSecretRetain retains the generated Secret when the MySQL
+	// Servers are removed and when the NdbCluster is deleted. The Secret, and
+	// the password, are reused when the MySQL Servers are created again.
+	NdbRootPasswordSecretRetain NdbRootPasswordSecretPolicy = "Retain"
+	// NdbRootPasswordSecretDelete deletes the generated Secret when the
+	// MySQL Servers are removed and when the NdbCluster is deleted.
+	NdbRootPasswordSecretDelete NdbRootPasswordSecretPolicy = "Delete"
+	// NdbRootPasswordSecretRecreate deletes the generated Secret like the
+	// NdbRootPasswordSecretDelete, and also replaces any Secret left over by
+	// a previous NdbCluster, so that the MySQL Servers are always created
+	// with a new password.
+	NdbRootPasswordSecretRecreate NdbRootPasswordSecretPolicy = "Recreate"
+)
+
 // NdbMysqldSpec is the specification of MySQL Servers to be run as an SQL Frontend
 type NdbMysqldSpec struct {
 	// NodeCount is the number of MySQL Servers to be started by the Operator
@@ -130,6 +1177,10 @@ type NdbMysqldSpec struct {
 	NodeCount int32 `json:"nodeCount"`
 	// MaxNodeCount is the count up to which the MySQL Servers would be
 	// allowed to scale up without forcing a MySQL Cluster config update.
+	// The operator reserves [mysqld] sections for these many MySQL Servers
+	// upfront in the MySQL Cluster config, so that scaling the MySQL Servers
+	// within this limit only updates the MySQL Server StatefulSet and does
+	// not require a rolling restart of the Management and Data nodes.
 	// If unspecified, operator will define the MySQL Cluster config with
 	// API sections for two additional MySQL Servers.
 	// +optional
@@ -147,18 +1198,97 @@ type NdbMysqldSpec struct {
 	// root accounts. The Secret should have a 'password' key that holds the
 	// password.
 	// If unspecified, a Secret will be created by the operator with a generated
-	// name of format "<ndb-resource-name>-mysqld-root-password"
+	// name of format "<ndb-resource-name>-mysqld-root-password".
+	// The Secret can also be materialized from an external secret store, by
+	// an ExternalSecret or via the secretProviderClass. The operator waits
+	// for the Secret to be created, and any later change to the password is
+	// applied to the root accounts.
 	// +optional
 	RootPasswordSecretName string `json:"rootPasswordSecretName,omitempty"`
+	// RootPasswordSecretPolicy specifies how the root password Secret
+	// generated by the operator is handled. When set to Delete, the Secret
+	// is deleted when the MySQL Servers are scaled down to zero and when the
+	// NdbCluster is deleted. When set to Retain, the Secret is never deleted
+	// by the operator and is reused when the MySQL Servers, or an NdbCluster
+	// with the same name, are created again. When set to Recreate, the Secret
+	// is deleted like with Delete, and any Secret left over by a previous
+	// NdbCluster with the same name is replaced by a new one. It is ignored
+	// if the rootPasswordSecretName is specified.
+	// +kubebuilder:validation:Enum=Retain;Delete;Recreate
+	// +kubebuilder:default=Delete
+	// +optional
+	RootPasswordSecretPolicy NdbRootPasswordSecretPolicy `json:"rootPasswordSecretPolicy,omitempty"`
+	// The name of the Secret that holds the password of the MySQL user account
+	// used by the operator to manage the MySQL Cluster. The Secret should have
+	// a 'password' key that holds the password. It is required by all the
+	// MySQL Cluster nodes, and so, if it is materialized from an external
+	// secret store, it should be done by an ExternalSecret and not via the
+	// secretProviderClass. The operator waits for the Secret to be created,
+	// and any later change to the password is applied to the user account by
+	// connecting with the previous password. The MySQL Cluster nodes pick up
+	// the new password when they are restarted next. If the operator has been
+	// restarted since the previous password was last used, the user account is
+	// instead recovered by restarting the first MySQL Server.
+	// If unspecified, a Secret will be created by the operator with a generated
+	// name of format "<ndb-resource-name>-ndb-operator-password"
+	// +optional
+	NdbOperatorPasswordSecretName string `json:"ndbOperatorPasswordSecretName,omitempty"`
+	// SecretProviderClass is the name of a SecretProviderClass of the Secrets
+	// Store CSI Driver, to be mounted into the MySQL Servers at
+	// /var/lib/ndb/secrets-store. The SecretProviderClass is expected to sync
+	// the Secret specified by the rootPasswordSecretName from the external
+	// secret store, which requires the Secret to be mounted into a pod.
+	// +optional
+	SecretProviderClass string `json:"secretProviderClass,omitempty"`
 	// RootHost is the host or hosts from which the root user
 	// can connect to the MySQL Server. If unspecified, root user
 	// will be able to connect from any host that can access the MySQL Server.
 	// +kubebuilder:default="%"
 	// +optional
 	RootHost string `json:"rootHost,omitempty"`
+	// RootAuthenticationPlugin is the authentication plugin to be used by
+	// the root user. If unspecified, the default authentication plugin of
+	// the MySQL Server is used. Changing it makes the operator recreate
+	// the credentials of the existing root user with the new plugin.
+	// +kubebuilder:validation:Enum:={caching_sha2_password, mysql_native_password}
+	// +optional
+	RootAuthenticationPlugin string `json:"rootAuthenticationPlugin,omitempty"`
+	// PasswordValidation, when specified, makes the operator install the
+	// validate_password component in the MySQL Servers and configure it with
+	// the given settings, before creating or updating the root user. The
+	// root password should then satisfy the given policy, and so, a custom
+	// rootPasswordSecretName is required unless the policy is LOW. The
+	// settings are persisted in the data directories of the MySQL Servers,
+	// and removing them from the spec does not uninstall the component.
+	// +optional
+	PasswordValidation *NdbMysqldPasswordValidationSpec `json:"passwordValidation,omitempty"`
+	// Plugins specifies the custom plugins and components to be
+	// installed in the MySQL Servers, including the ones in the
+	// server groups.
+	// +optional
+	Plugins *NdbMysqldPluginsSpec `json:"plugins,omitempty"`
+	// AuditLog, when specified, enables the audit log in the MySQL
+	// Servers, including the ones in the server groups. The MySQL
+	// Enterprise Audit plugin should be available in the MySQL Server
+	// image or be installed via the spec.mysqlNode.plugins.
+	// +optional
+	AuditLog *NdbMysqldAuditLogSpec `json:"auditLog,omitempty"`
 	// Configuration options to pass to the MySQL Server when it is started.
+	// The options that are not known options of MySQL Server 8.0 are warned
+	// about, and should be prefixed with 'loose-' if they belong to a plugin
+	// or a component. The options managed by the operator, like
+	// ndb-connectstring and port, cannot be specified here.
 	// +optional
 	MyCnf string `json:"myCnf,omitempty"`
+	// ExtraArgs are the additional command-line arguments, like
+	// "--log-bin=binlog", to be appended to the mysqld command of the
+	// MySQL Servers, including the ones in the server groups. Every
+	// element is passed to the mysqld as a single argument, as it is,
+	// without any shell expansion. The options managed by the operator,
+	// like --ndbcluster, --ndb-connectstring and --port, cannot be
+	// specified here.
+	// +optional
+	ExtraArgs []string `json:"extraArgs,omitempty"`
 	// EnableLoadBalancer exposes the MySQL servers externally using the kubernetes cloud
 	// provider's load balancer. By default, the operator creates a ClusterIP type service
 	// to expose the MySQL server pods internally within the kubernetes cluster. If
@@ -167,10 +1297,28 @@ type NdbMysqldSpec struct {
 	// +kubebuilder:default=false
 	// +optional
 	EnableLoadBalancer bool `json:"enableLoadBalancer,omitempty"`
+	// LoadBalancer specifies the additional configuration of the LoadBalancer
+	// Services created when the enableLoadBalancer is set to true, including
+	// the ones created for the server groups.
+	// +optional
+	LoadBalancer *NdbLoadBalancerSpec `json:"loadBalancer,omitempty"`
+	// Gateway exposes the MySQL servers outside the kubernetes cluster using
+	// the Gateway API, for the platforms that standardize on the Gateway API
+	// for the external exposure. It requires the Gateway API TCPRoute to be
+	// installed in the kubernetes cluster, and cannot be used along with the
+	// enableLoadBalancer. A Warning event is recorded, and the Gateway is not
+	// created, if the Gateway API is not installed. The MySQL Servers of the
+	// server groups are not exposed via the Gateway.
+	// +optional
+	Gateway *NdbMysqldGatewaySpec `json:"gateway,omitempty"`
 	// NdbPodSpec contains a subset of K8s PodSpec fields which when set
 	// will be copied into to the podSpec of MySQL Server StatefulSet.
 	// +optional
 	NdbPodSpec *NdbClusterPodSpec `json:"ndbPodSpec,omitempty"`
+	// Image is the name of the image to be used by the MySQL Server containers.
+	// If not specified, spec.image will be used.
+	// +optional
+	Image string `json:"image,omitempty"`
 	// InitScripts is a map of configMap names from the same namespace and
 	// optionally an array of keys which store the SQL scripts to be executed
 	// during MySQL Server initialization. If key names are omitted, contents
@@ -185,8 +1333,90 @@ type NdbMysqldSpec struct {
 	// the mysql server pod and the container.
 	// +optional
 	PVCSpec *corev1.PersistentVolumeClaimSpec `json:"pvcSpec,omitempty"`
+	// Autoscaling, when specified, makes the operator create a
+	// HorizontalPodAutoscaler that scales the MySQL Servers via the
+	// NdbCluster scale subresource. The nodeCount will then be
+	// managed by the autoscaler.
+	// +optional
+	Autoscaling *NdbMysqldAutoscalingSpec `json:"autoscaling,omitempty"`
+	// PodDisruptionBudget specifies the PodDisruptionBudget to be created
+	// for the MySQL Servers. If unspecified, one MySQL Server will be
+	// allowed to be evicted at a time.
+	// +optional
+	PodDisruptionBudget *NdbPodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+	// PodLabels are the additional labels to be added to the MySQL Server pods.
+	// These are merged with, and take precedence over, spec.podLabels.
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+	// PodAnnotations are the additional annotations to be added to the MySQL Server pods.
+	// These are merged with, and take precedence over, spec.podAnnotations.
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+	// ServiceAnnotations are the additional annotations to be added to the
+	// Services created for the MySQL Servers. These are merged with, and take
+	// precedence over, spec.serviceAnnotations.
+	// +optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+	// UpdateStrategy is the update strategy of the MySQL Server StatefulSet.
+	// By default, a RollingUpdate is done by the StatefulSet controller. A
+	// partition can be set to stage the rollout, and the operator will wait
+	// for the rollout to complete before continuing with the sync. If set to
+	// OnDelete, the operator restarts the outdated MySQL Servers one by one.
+	// +optional
+	UpdateStrategy *appsv1.StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
+	// PodManagementPolicy is the pod management policy of the MySQL Server
+	// StatefulSet. Defaults to Parallel. Cannot be updated.
+	// +kubebuilder:validation:Enum=OrderedReady;Parallel
+	// +optional
+	PodManagementPolicy appsv1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
+	// HostNetwork, when enabled, runs the MySQL Servers, including the
+	// ones in the server groups, in the network of their K8s worker nodes
+	// to avoid the latency of the overlay network. Not more than one MySQL
+	// Server is scheduled onto a worker node, as they all use the same
+	// port of the worker node. Cannot be updated.
+	// +optional
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+	// Port is the port on which the MySQL Servers, including the ones in
+	// the server groups, listen for the client connections. It is exposed
+	// by the Services of the MySQL Servers and is used by the operator to
+	// connect to them. Defaults to 3306. Cannot be updated.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// CanaryRollout, when specified, makes the operator apply any update to
+	// the MySQL Server pods, like a my.cnf or an image change, first to a
+	// single MySQL Server. The update is rolled out to the rest of the
+	// MySQL Servers only after the canary passes the health check, and
+	// is rolled back otherwise. This cannot be specified along with
+	// the updateStrategy.
+	// +optional
+	CanaryRollout *NdbMysqldCanaryRolloutSpec `json:"canaryRollout,omitempty"`
+	// ServerGroups are the additional groups of MySQL Servers to be run
+	// along with the MySQL Servers specified above, like a group of
+	// MySQL Servers dedicated to analytic queries or binary logging. Every
+	// group runs with its own my.cnf, node count and Service, and shares
+	// the rest of the spec.mysqlNode with the other MySQL Servers.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	ServerGroups []NdbMysqldServerGroupSpec `json:"serverGroups,omitempty"`
 }
 
+// NdbRedundancyLevelUpdateStrategy specifies how
+// an update to the redundancyLevel is applied
+type NdbRedundancyLevelUpdateStrategy string
+
+const (
+	// NdbRedundancyLevelUpdateForbidden rejects any update to the redundancyLevel
+	NdbRedundancyLevelUpdateForbidden NdbRedundancyLevelUpdateStrategy = "Forbidden"
+	// NdbRedundancyLevelUpdateRecreate applies an update to the redundancyLevel
+	// by recreating the data nodes, with empty file systems, via an initial
+	// system restart. All the data stored in the MySQL Cluster is lost,
+	// and is not restored by the operator.
+	NdbRedundancyLevelUpdateRecreate NdbRedundancyLevelUpdateStrategy = "Recreate"
+)
+
 // NdbClusterSpec defines the desired state of a MySQL NDB Cluster
 type NdbClusterSpec struct {
 	// The number of copies of all data stored in MySQL Cluster.
@@ -200,7 +1430,10 @@ type NdbClusterSpec struct {
 	// configuration based on this value. For a redundancy level
 	// of 1, one Management node will be created. For 2 or
 	// higher, two Management nodes will be created.
-	// This value is immutable.
+	// This value can be updated only between 2, 3 and 4, and only
+	// if the redundancyLevelUpdateStrategy is set to Recreate.
+	// The spec of a MySQL Cluster with a redundancy level of 1 can
+	// be updated only if spec.dataNode.systemRestart allows it.
 	//
 	// More info :
 	// https://dev.mysql.com/doc/refman/8.0/en/mysql-cluster-ndbd-definition.html#ndbparam-ndbd-noofreplicas
@@ -209,6 +1442,25 @@ type NdbClusterSpec struct {
 	// +kubebuilder:validation:Maximum=4
 	// +optional
 	RedundancyLevel int32 `json:"redundancyLevel,omitempty"`
+	// RedundancyLevelUpdateStrategy specifies how an update to the
+	// redundancyLevel is applied to the MySQL Cluster. By default, the
+	// redundancyLevel cannot be updated. When set to Recreate, the update
+	// is applied by an initial system restart : all the data nodes are
+	// stopped together, their PersistentVolumeClaims are deleted, and they
+	// are started again with empty file systems and the new redundancy
+	// level. All the MySQL Servers are then restarted to resync with the
+	// recreated MySQL Cluster, and the root user and the ndb operator
+	// user are recreated. All the data stored in the MySQL Cluster,
+	// including any other user stored in it, is lost. If
+	// spec.dataNode.systemRestart.backupBeforeRestart is enabled, a backup
+	// is taken before the data nodes are stopped, and it is retained in the
+	// backup volumes of the data nodes. The operator does not restore the
+	// backup into the recreated MySQL Cluster, it has to be restored by the
+	// user with ndb_restore.
+	// +kubebuilder:validation:Enum=Forbidden;Recreate
+	// +kubebuilder:default=Forbidden
+	// +optional
+	RedundancyLevelUpdateStrategy NdbRedundancyLevelUpdateStrategy `json:"redundancyLevelUpdateStrategy,omitempty"`
 	// ManagementNode specifies the configuration of the management node running in MySQL Cluster.
 	// +optional
 	ManagementNode *NdbManagementNodeSpec `json:"managementNode,omitempty"`
@@ -220,6 +1472,16 @@ type NdbClusterSpec struct {
 	// default add one MySQL Server to the spec.
 	// +optional
 	MysqlNode *NdbMysqldSpec `json:"mysqlNode,omitempty"`
+	// Arbitrator, when specified, makes the operator run an additional
+	// Management node that acts only as the arbitrator of the MySQL
+	// Cluster. It is declared in the config with a higher ArbitrationRank
+	// than the other Management nodes and, when run in a third failure
+	// domain, lets the data nodes of a two zone deployment survive the
+	// loss of a zone without a split brain. The arbitrator uses the
+	// Management node image and nodeId 255. It cannot be added to or
+	// removed from an existing NdbCluster.
+	// +optional
+	Arbitrator *NdbArbitratorSpec `json:"arbitrator,omitempty"`
 	// The number of extra API sections declared in the MySQL Cluster
 	// config, in addition to the API sections declared implicitly
 	// by the NDB Operator for the MySQL Servers.
@@ -228,6 +1490,18 @@ type NdbClusterSpec struct {
 	// +kubebuilder:default=2
 	// +optional
 	FreeAPISlots int32 `json:"freeAPISlots,omitempty"`
+	// NdbAPIApplications reserve API sections in the MySQL Cluster config
+	// for the NDBAPI applications running in K8s. The sections are declared
+	// with the hostnames of the application pods, so that only the pods of
+	// the application can connect via them. When spec.networkPolicy is also
+	// specified, the NetworkPolicy allows the traffic from the application
+	// pods to the Management and the Data nodes. The sections are reserved
+	// from the highest available nodeId downwards, so that their nodeIds
+	// are not changed by the updates to the other nodes.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	NdbAPIApplications []NdbAPIApplicationSpec `json:"ndbAPIApplications,omitempty"`
 	// The name of the MySQL Ndb Cluster image to be used.
 	// If not specified, "container-registry.oracle.com/mysql/community-cluster:8.1.0" will be used.
 	// +kubebuilder:default="container-registry.oracle.com/mysql/community-cluster:8.1.0"
@@ -243,6 +1517,128 @@ type NdbClusterSpec struct {
 	// holds the credentials required for pulling the MySQL Cluster image.
 	// +optional
 	ImagePullSecretName string `json:"imagePullSecretName,omitempty"`
+	// ImagePullSecrets is an optional list of secrets holding the credentials
+	// required for pulling the MySQL Cluster images. These are used in addition
+	// to the secret specified via imagePullSecretName.
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// PodLabels are the additional labels to be added to all the
+	// MySQL Cluster pods. Labels set by the operator cannot be overridden.
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+	// PodAnnotations are the additional annotations to be added
+	// to all the MySQL Cluster pods.
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+	// ServiceAnnotations are the additional annotations to be added
+	// to all the Services created by the operator for the MySQL Cluster.
+	// +optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+	// IPFamilyPolicy is the IP family policy of all the Services created
+	// by the operator for the MySQL Cluster. If not specified, the
+	// default policy of the K8s Cluster is used. This value is immutable.
+	// +kubebuilder:validation:Enum=SingleStack;PreferDualStack;RequireDualStack
+	// +optional
+	IPFamilyPolicy *corev1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+	// IPFamilies are the IP families of all the Services created by the
+	// operator for the MySQL Cluster, in the order of preference. If the
+	// first family is IPv6, the MySQL Cluster nodes are configured to
+	// resolve the hostnames of the other nodes to IPv6 addresses. It has
+	// to be set to [IPv6] when running in an IPv6 only K8s Cluster and
+	// the first family should match the primary IP family of the pods in
+	// a dual-stack K8s Cluster. If not specified, the default families of
+	// the K8s Cluster are used. This value is immutable.
+	// +kubebuilder:validation:MaxItems=2
+	// +listType=atomic
+	// +optional
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
+	// LocationDomainTopologyKey, when specified, makes the MySQL Cluster
+	// aware of the topology of the K8s Cluster. The operator maps every
+	// distinct value of this label on the K8s worker nodes running the
+	// data nodes to a LocationDomainId, in a sorted order, and sets it in
+	// the config of the data nodes. The transaction coordinators then
+	// prefer the fragment replicas in their own location domain for reads.
+	// The Management and MySQL Server nodes, which can be rescheduled to
+	// any worker node, are not assigned to a location domain. The config
+	// is updated, and the nodes restarted, whenever the data nodes are
+	// moved to a different domain. Only up to 16 domains are supported.
+	// The well-known zone label, topology.kubernetes.io/zone, is usually
+	// used as the key.
+	// +optional
+	LocationDomainTopologyKey string `json:"locationDomainTopologyKey,omitempty"`
+	// NetworkPolicy, when specified, makes the operator create a NetworkPolicy
+	// that denies all incoming traffic to the MySQL Cluster pods except the
+	// traffic between the MySQL Cluster nodes and the traffic from the NDB
+	// Operator and the clients specified in it. An NDB Operator running
+	// outside the K8s Cluster is allowed only from the CIDR specified via
+	// its -operator-cidr flag.
+	// +optional
+	NetworkPolicy *NdbNetworkPolicySpec `json:"networkPolicy,omitempty"`
+	// ServiceAccount, when specified, makes the MySQL Cluster pods run as
+	// the given ServiceAccount, or as a ServiceAccount created and owned by
+	// the operator along with the Role and the RoleBinding granting it the
+	// given permissions. The MySQL Cluster pods run as the default
+	// ServiceAccount of the namespace when this is not specified.
+	// +optional
+	ServiceAccount *NdbServiceAccountSpec `json:"serviceAccount,omitempty"`
+	// InitFromBackup, when specified, makes the operator restore the given
+	// NDB native backup into the MySQL Cluster when it is started for the
+	// first time. The backup is restored by ndb_restore, running in a Job
+	// that connects to the MySQL Cluster via one of the free API slots,
+	// once the data nodes are ready and before the MySQL Servers are
+	// started. The NdbCluster is not marked as ready until the restore
+	// completes. A failed restore is not retried, and the NdbCluster has
+	// to be recreated to restore the backup again.
+	// This value is immutable.
+	// +optional
+	InitFromBackup *NdbClusterInitFromBackupSpec `json:"initFromBackup,omitempty"`
+	// InitFromDump, when specified, makes the operator load the given SQL
+	// dump into the MySQL Cluster when it is started for the first time.
+	// The dump is loaded by the mysql client, running in a Job that
+	// connects to the MySQL Servers via their Service as the root user,
+	// once all the MySQL Cluster nodes are ready. The root user should be
+	// allowed to connect from the Job pod, i.e. spec.mysqlNode.rootHost
+	// should match the pod's address. Tables without an explicit ENGINE
+	// clause are created in the NDBCLUSTER storage engine. If both
+	// initFromBackup and initFromDump are specified, the dump is loaded
+	// after the backup has been restored. The NdbCluster is not marked
+	// as ready until the dump has been loaded. A failed load is not
+	// retried, and the NdbCluster has to be recreated to load the dump
+	// again. This value is immutable.
+	// +optional
+	InitFromDump *NdbClusterInitFromDumpSpec `json:"initFromDump,omitempty"`
+	// VerifyBackups, when true, makes the operator verify every completed
+	// backup cataloged in the <ndbcluster-name>-backups ConfigMap. A backup
+	// is verified by reading the part of it taken by every data node, its
+	// metadata, data and log, with ndb_restore, in a Job per data node that
+	// mounts the PersistentVolumeClaims of that data node, and the result is
+	// recorded in the catalog. The backups are verified one at a time, and
+	// the data nodes should store the backups in a PersistentVolumeClaim,
+	// specified by either dataNode.pvcSpec or
+	// dataNode.separateVolumes.backup. The backups are cataloged only when
+	// the operator streams the cluster logs via its -stream-cluster-log flag.
+	// +optional
+	VerifyBackups bool `json:"verifyBackups,omitempty"`
+	// Backup specifies the options of the NDB native backups of the MySQL
+	// Cluster, i.e. of the ones taken by the data nodes, including the ones
+	// the operator takes before a system restart.
+	// +optional
+	Backup *NdbBackupSpec `json:"backup,omitempty"`
+	// HealthMonitoring, when specified, makes the operator periodically
+	// sample the memory usage, the redo log space usage, the transporters
+	// and the row locks of the data nodes from the ndbinfo database, via
+	// a MySQL Server. A condensed snapshot is published in the status and
+	// the Healthy condition is set to False if any of the thresholds are
+	// exceeded or if any of the data nodes are disconnected from each other.
+	// +optional
+	HealthMonitoring *NdbClusterHealthMonitoringSpec `json:"healthMonitoring,omitempty"`
+	// PodDNS specifies how the MySQL Cluster pods wait for their per-pod
+	// DNS records. The MySQL Cluster nodes resolve each other via these
+	// records before they can become ready, and so, all the Services
+	// created for the MySQL Cluster nodes always publish the addresses of
+	// the pods that are not ready yet.
+	// +optional
+	PodDNS *NdbPodDNSSpec `json:"podDNS,omitempty"`
 }
 
 // NdbClusterConditionType defines type for NdbCluster condition.
@@ -252,6 +1648,35 @@ const (
 	// NdbClusterUpToDate specifies if the spec of the MySQL Cluster
 	// is up-to-date with the NdbCluster resource spec
 	NdbClusterUpToDate NdbClusterConditionType = "UpToDate"
+	// NdbClusterPartitioned specifies if any of the started data
+	// nodes have lost their connection to the MySQL Cluster, which
+	// happens when the MySQL Cluster is split by a network partition.
+	NdbClusterPartitioned NdbClusterConditionType = "Partitioned"
+	// NdbClusterDegraded specifies if the operator has repeatedly
+	// failed to sync the MySQL Cluster with the NdbCluster resource.
+	NdbClusterDegraded NdbClusterConditionType = "Degraded"
+	// NdbClusterHealthy specifies if the last health snapshot of the
+	// MySQL Cluster is within the thresholds specified in the spec.
+	NdbClusterHealthy NdbClusterConditionType = "Healthy"
+	// NdbClusterZoneRedundant specifies if the data nodes of every node
+	// group are placed in distinct zones, as planned from spec.dataNode.zones.
+	NdbClusterZoneRedundant NdbClusterConditionType = "ZoneRedundant"
+	// NdbClusterStorageResized specifies if the PVCs of all the data
+	// nodes have the storage requested via spec.dataNode.pvcSpec.
+	NdbClusterStorageResized NdbClusterConditionType = "StorageResized"
+	// NdbClusterLocalVolumesAvailable specifies if the worker nodes hosting
+	// the local PersistentVolumes of all the data nodes are available.
+	NdbClusterLocalVolumesAvailable NdbClusterConditionType = "LocalVolumesAvailable"
+	// NdbClusterNodesConnected specifies if all the Management nodes,
+	// Data nodes and running MySQL Servers are started and connected to
+	// the MySQL Cluster. Unlike NdbClusterUpToDate, it reflects the live
+	// state of the MySQL Cluster and is updated even when the NdbCluster
+	// spec has already been applied.
+	NdbClusterNodesConnected NdbClusterConditionType = "NodesConnected"
+	// NdbClusterDataNodeRestartLoop specifies if the operator has stopped
+	// restarting the data nodes as their restarts have repeatedly failed
+	// to apply the latest pod definition and config.
+	NdbClusterDataNodeRestartLoop NdbClusterConditionType = "DataNodeRestartLoop"
 )
 
 const (
@@ -273,6 +1698,116 @@ const (
 	NdbClusterUptoDateReasonError string = "SyncError"
 )
 
+const (
+	// NdbClusterPartitionedReasonConnected is the reason used when the
+	// NdbClusterPartitioned condition is set to False as all the started
+	// data nodes are connected to the MySQL Cluster.
+	NdbClusterPartitionedReasonConnected string = "DataNodesConnected"
+	// NdbClusterPartitionedReasonDisconnected is the reason used when
+	// the NdbClusterPartitioned condition is set to True as some of the
+	// started data nodes have lost their connection to the MySQL Cluster.
+	NdbClusterPartitionedReasonDisconnected string = "DataNodesDisconnected"
+	// NdbClusterPartitionedReasonArbitrationLost is the reason used when
+	// the NdbClusterPartitioned condition is set to True as all the data
+	// nodes of a node group have lost their connection to the MySQL
+	// Cluster, which leads to a shutdown of the MySQL Cluster when the
+	// arbitration fails.
+	NdbClusterPartitionedReasonArbitrationLost string = "ArbitrationLost"
+)
+
+const (
+	// NdbClusterDegradedReasonSyncFailing is the reason used when the
+	// NdbClusterDegraded condition is set to True as the sync has failed
+	// consecutively for more than a threshold number of times.
+	NdbClusterDegradedReasonSyncFailing string = "SyncFailing"
+	// NdbClusterDegradedReasonSyncRecovered is the reason used when the
+	// NdbClusterDegraded condition is set to False as a sync has
+	// succeeded after the repeated failures.
+	NdbClusterDegradedReasonSyncRecovered string = "SyncRecovered"
+)
+
+const (
+	// NdbClusterHealthyReasonWithinThresholds is the reason used when
+	// the NdbClusterHealthy condition is set to True as the last health
+	// snapshot is within all the thresholds specified in the spec.
+	NdbClusterHealthyReasonWithinThresholds string = "WithinThresholds"
+	// NdbClusterHealthyReasonThresholdExceeded is the reason used when
+	// the NdbClusterHealthy condition is set to False as the last health
+	// snapshot has exceeded one or more thresholds specified in the spec.
+	NdbClusterHealthyReasonThresholdExceeded string = "ThresholdExceeded"
+)
+
+const (
+	// NdbClusterZoneRedundantReasonPlacementVerified is the reason used
+	// when the NdbClusterZoneRedundant condition is set to True as all
+	// the placed data nodes conform to the planned placement.
+	NdbClusterZoneRedundantReasonPlacementVerified string = "PlacementVerified"
+	// NdbClusterZoneRedundantReasonPlacementViolated is the reason used
+	// when the NdbClusterZoneRedundant condition is set to False as some
+	// of the data nodes are placed outside the planned zones or in the
+	// same zone as the other data nodes of their node group.
+	NdbClusterZoneRedundantReasonPlacementViolated string = "PlacementViolated"
+)
+
+const (
+	// NdbClusterStorageResizedReasonResizeComplete is the reason used when
+	// the NdbClusterStorageResized condition is set to True as the PVCs
+	// of all the data nodes have the requested storage capacity.
+	NdbClusterStorageResizedReasonResizeComplete string = "ResizeComplete"
+	// NdbClusterStorageResizedReasonResizeInProgress is the reason used
+	// when the NdbClusterStorageResized condition is set to False as the
+	// PVCs of some data nodes are being expanded.
+	NdbClusterStorageResizedReasonResizeInProgress string = "ResizeInProgress"
+	// NdbClusterStorageResizedReasonResizeNotAllowed is the reason used
+	// when the NdbClusterStorageResized condition is set to False as the
+	// storage class of some data node PVCs does not allow their expansion.
+	NdbClusterStorageResizedReasonResizeNotAllowed string = "ResizeNotAllowed"
+)
+
+const (
+	// NdbClusterLocalVolumesAvailableReasonWorkerNodesAvailable is the
+	// reason used when the NdbClusterLocalVolumesAvailable condition is set
+	// to True as the worker nodes hosting the local PersistentVolumes of
+	// all the data nodes are available.
+	NdbClusterLocalVolumesAvailableReasonWorkerNodesAvailable string = "WorkerNodesAvailable"
+	// NdbClusterLocalVolumesAvailableReasonWorkerNodeLost is the reason
+	// used when the NdbClusterLocalVolumesAvailable condition is set to
+	// False as the worker nodes hosting the local PersistentVolumes of some
+	// data nodes are lost, and the data nodes are not being rescheduled.
+	NdbClusterLocalVolumesAvailableReasonWorkerNodeLost string = "WorkerNodeLost"
+	// NdbClusterLocalVolumesAvailableReasonRescheduling is the reason used
+	// when the NdbClusterLocalVolumesAvailable condition is set to False as
+	// a data node of a lost worker node is being rescheduled.
+	NdbClusterLocalVolumesAvailableReasonRescheduling string = "Rescheduling"
+)
+
+const (
+	// NdbClusterNodesConnectedReasonAllConnected is the reason used when
+	// the NdbClusterNodesConnected condition is set to True as all the
+	// MySQL Cluster nodes are connected.
+	NdbClusterNodesConnectedReasonAllConnected string = "AllNodesConnected"
+	// NdbClusterNodesConnectedReasonNodesDisconnected is the reason used
+	// when the NdbClusterNodesConnected condition is set to False as some
+	// of the MySQL Cluster nodes are not started or not connected.
+	NdbClusterNodesConnectedReasonNodesDisconnected string = "NodesDisconnected"
+	// NdbClusterNodesConnectedReasonMgmdUnreachable is the reason used
+	// when the NdbClusterNodesConnected condition is set to False as none
+	// of the Management nodes could be reached to retrieve the node states.
+	NdbClusterNodesConnectedReasonMgmdUnreachable string = "ManagementNodesUnreachable"
+)
+
+const (
+	// NdbClusterDataNodeRestartLoopReasonNotConverging is the reason used
+	// when the NdbClusterDataNodeRestartLoop condition is set to True as a
+	// data node did not connect to the MySQL Cluster with the desired
+	// config version after being restarted repeatedly to apply it.
+	NdbClusterDataNodeRestartLoopReasonNotConverging string = "RestartsNotConverging"
+	// NdbClusterDataNodeRestartLoopReasonConverged is the reason used when
+	// the NdbClusterDataNodeRestartLoop condition is set to False as all
+	// the data nodes run the desired pod revision and config version.
+	NdbClusterDataNodeRestartLoopReasonConverged string = "RestartsConverged"
+)
+
 // NdbClusterCondition describes the state of a MySQL Cluster installation at a certain point.
 type NdbClusterCondition struct {
 	// Type of NdbCluster condition.
@@ -287,6 +1822,149 @@ type NdbClusterCondition struct {
 	Message string `json:"message,omitempty"`
 }
 
+// NdbClusterHealthSnapshot is a condensed snapshot
+// of the health of the MySQL Cluster data nodes.
+type NdbClusterHealthSnapshot struct {
+	// DataMemoryUsagePercent is the highest percentage
+	// of the DataMemory used by any of the data nodes.
+	DataMemoryUsagePercent int32 `json:"dataMemoryUsagePercent"`
+	// RedoLogSpaceUsagePercent is the highest percentage
+	// of the redo log space used by any of the data nodes.
+	RedoLogSpaceUsagePercent int32 `json:"redoLogSpaceUsagePercent"`
+	// DisconnectedTransporters is the number of transporters
+	// between the data nodes that are not connected.
+	DisconnectedTransporters int32 `json:"disconnectedTransporters"`
+	// LockWaits is the number of operations waiting for a row lock.
+	LockWaits int32 `json:"lockWaits"`
+	// LastUpdateTime is the time at which the snapshot was taken.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
+}
+
+// NdbClusterEpochStatus reports an epoch of the MySQL Cluster
+type NdbClusterEpochStatus struct {
+	// Epoch is the epoch as a 64-bit number, with the global checkpoint
+	// index (GCI) in the upper 32 bits and the micro GCI in the lower 32 bits.
+	Epoch int64 `json:"epoch"`
+	// GCI is the global checkpoint index of the epoch. The transactions
+	// committed in the epoch survive a crash of the whole MySQL Cluster
+	// only after this global checkpoint has been written to the disk.
+	GCI int64 `json:"gci"`
+	// LastUpdateTime is the time at which the epoch was observed.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
+}
+
+// NdbNodesConfigRolloutStatus reports the progress of applying the
+// latest config and pod definition to the MySQL Cluster nodes of a type
+type NdbNodesConfigRolloutStatus struct {
+	// ConfigGeneration is the oldest ConfigGenerationNumber of the
+	// MySQL Cluster config (config.ini) the nodes are running with.
+	ConfigGeneration int32 `json:"configGeneration"`
+	// PendingRestart has the names of the pods that are yet to be restarted
+	// to apply the latest config or pod definition to their nodes.
+	// +optional
+	PendingRestart []string `json:"pendingRestart,omitempty"`
+}
+
+// NdbClusterConfigRolloutStatus reports the progress of applying the
+// latest config and pod definitions to the MySQL Cluster nodes
+type NdbClusterConfigRolloutStatus struct {
+	// ConfigGeneration is the ConfigGenerationNumber of
+	// the latest MySQL Cluster config (config.ini).
+	ConfigGeneration int32 `json:"configGeneration"`
+	// ManagementNodes reports the rollout to the Management nodes.
+	// +optional
+	ManagementNodes *NdbNodesConfigRolloutStatus `json:"managementNodes,omitempty"`
+	// DataNodes reports the rollout to the data nodes.
+	// +optional
+	DataNodes *NdbNodesConfigRolloutStatus `json:"dataNodes,omitempty"`
+	// MySQLServers reports the rollout to the MySQL Servers.
+	// +optional
+	MySQLServers *NdbNodesConfigRolloutStatus `json:"mysqlServers,omitempty"`
+}
+
+// DataNodeRestartType is the type of the restart
+// required by the data nodes to apply a config change.
+// +kubebuilder:validation:Enum=NodeRestart;InitialNodeRestart;SystemRestart;InitialSystemRestart
+type DataNodeRestartType string
+
+const (
+	// DataNodeNodeRestart is the restart type of the config changes
+	// applied by restarting the data nodes one by one in every node group.
+	DataNodeNodeRestart DataNodeRestartType = "NodeRestart"
+	// DataNodeInitialNodeRestart is the restart type of the config changes
+	// applied by restarting the data nodes one by one with an empty file
+	// system. The restarted data nodes copy their data from their peers.
+	DataNodeInitialNodeRestart DataNodeRestartType = "InitialNodeRestart"
+	// DataNodeSystemRestart is the restart type of the config changes that
+	// can be applied only by stopping and starting all the data nodes together.
+	DataNodeSystemRestart DataNodeRestartType = "SystemRestart"
+	// DataNodeInitialSystemRestart is the restart type of the config changes
+	// that can be applied only by stopping all the data nodes together and
+	// starting them again with empty file systems, like a NoOfReplicas change.
+	DataNodeInitialSystemRestart DataNodeRestartType = "InitialSystemRestart"
+)
+
+// Annotations of the NdbCluster resource that request the operator to do a
+// rolling restart of the MySQL Cluster nodes. The value of the annotation
+// can be any string, like a timestamp, and every new value is a new request.
+// The restarts are done just like the restarts done to apply a new config.
+const (
+	// RestartAnnotationPrefix is the prefix of the annotations that request a
+	// rolling restart of the nodes of a type. It has to be followed by the
+	// node type - mgmd, ndbmtd or mysqld.
+	RestartAnnotationPrefix = ndbcontroller.GroupName + "/restart-"
+	// InitialRestartAnnotation is the annotation that requests a rolling
+	// initial restart of the data nodes. The data nodes are restarted one
+	// at a time with an empty file system, and they copy their data from
+	// the other data nodes of their node group.
+	InitialRestartAnnotation = ndbcontroller.GroupName + "/initial-restart-ndbmtd"
+	// DataNodeRestartAnnotationPrefix is the prefix of the annotations that
+	// request a restart of a single data node. It has to be followed by the
+	// node id of the data node, and the data node is restarted only if the
+	// other data nodes of its node group are connected to the MySQL Cluster.
+	DataNodeRestartAnnotationPrefix = RestartAnnotationPrefix + constants.NdbNodeTypeNdbmtd + "-"
+)
+
+// Annotations of the NdbCluster resource that help debugging the
+// MySQL Cluster and the operator when something goes wrong.
+const (
+	// StopReconcilingAnnotation, when set to "true", makes the operator
+	// stop reconciling the NdbCluster until the annotation is removed.
+	// The MySQL Cluster keeps running, but none of its resources are
+	// updated or recovered by the operator.
+	StopReconcilingAnnotation = ndbcontroller.GroupName + "/stop-reconciling"
+	// DumpStateAnnotation requests the operator to dump its view of the
+	// MySQL Cluster into a ConfigMap, to be attached to support bundles.
+	// The value of the annotation can be any string, and every new value
+	// is a new request.
+	DumpStateAnnotation = ndbcontroller.GroupName + "/dump-state"
+)
+
+// NdbClusterNodeStatus maps a MySQL Cluster node
+// declared in the config to the pod running it.
+type NdbClusterNodeStatus struct {
+	// NodeId is the nodeId of the node
+	NodeId int32 `json:"nodeId"`
+	// NodeType is the type of the node, i.e. mgmd, ndbmtd, mysqld or arbitrator
+	NodeType string `json:"nodeType"`
+	// PodName is the name of the pod running the node
+	PodName string `json:"podName"`
+	// PodIP is the IP address of the pod, if it has been assigned one
+	// +optional
+	PodIP string `json:"podIP,omitempty"`
+}
+
+// NdbMysqldServerGroupStatus is the range of
+// nodeIds reserved for a MySQL Server group
+type NdbMysqldServerGroupStatus struct {
+	// Name is the name of the MySQL Server group
+	Name string `json:"name"`
+	// StartNodeId is the first nodeId of the range
+	StartNodeId int32 `json:"startNodeId"`
+	// NumOfNodeIds is the number of nodeIds in the range
+	NumOfNodeIds int32 `json:"numOfNodeIds"`
+}
+
 // NdbClusterStatus is the status for a Ndb resource
 type NdbClusterStatus struct {
 	// ProcessedGeneration holds the latest generation of the
@@ -299,9 +1977,59 @@ type NdbClusterStatus struct {
 	ReadyDataNodes string `json:"readyDataNodes,omitempty"`
 	// The status of the MySQL Servers.
 	ReadyMySQLServers string `json:"readyMySQLServers,omitempty"`
+	// The number of ready MySQL Servers that are connected to the
+	// started data nodes and can serve queries on the NDB tables.
+	ConnectedMySQLServers string `json:"connectedMySQLServers,omitempty"`
+	// MySQLServerReplicas is the number of MySQL Server pods currently
+	// running. This is exposed via the scale subresource.
+	MySQLServerReplicas int32 `json:"mysqlServerReplicas,omitempty"`
+	// MySQLServerSelector is the label selector, in string form, matching
+	// the MySQL Server pods. This is exposed via the scale subresource and
+	// is used by the HorizontalPodAutoscalers to find the pods.
+	MySQLServerSelector string `json:"mysqlServerSelector,omitempty"`
+	// PendingDataNodeRestart is the type of the restart pending for the
+	// data nodes to apply the latest spec, if any. A pending SystemRestart
+	// is applied by the operator only if spec.dataNode.systemRestart allows
+	// it, and an InitialSystemRestart only if the redundancyLevelUpdateStrategy
+	// is Recreate. Otherwise, the spec has to be reverted.
+	// +optional
+	PendingDataNodeRestart DataNodeRestartType `json:"pendingDataNodeRestart,omitempty"`
+	// ConfigRollout reports the progress of applying the latest
+	// MySQL Cluster config and pod definitions to the nodes.
+	// +optional
+	ConfigRollout *NdbClusterConfigRolloutStatus `json:"configRollout,omitempty"`
+	// PendingChanges is a human-readable summary of the changes made by the
+	// latest spec to the MySQL Cluster config and the MySQL Server configs,
+	// like the parameters added, changed or removed and the changes in the
+	// number of nodes. It is reported while the spec is being applied.
+	// +optional
+	PendingChanges []string `json:"pendingChanges,omitempty"`
 	// Conditions represent the latest available
 	// observations of the MySQL Cluster's current state.
 	Conditions []NdbClusterCondition `json:"conditions,omitempty"`
+	// Health is the last health snapshot of the MySQL Cluster sampled
+	// from the ndbinfo database. It is set only when the health
+	// monitoring is enabled via spec.healthMonitoring.
+	Health *NdbClusterHealthSnapshot `json:"health,omitempty"`
+	// LatestEpoch is the latest epoch committed in the MySQL Cluster, as
+	// last observed by the operator through a MySQL Server. The changes
+	// committed after this epoch might not have been replicated yet, and
+	// it can be used to estimate the window of the changes that might be
+	// lost when failing over a replication channel to another cluster.
+	// +optional
+	LatestEpoch *NdbClusterEpochStatus `json:"latestEpoch,omitempty"`
+	// Nodes maps the nodeIds of all the MySQL Cluster nodes declared in
+	// the config, except the free API slots, to the names and the IP
+	// addresses of the pods running them, sorted by the nodeIds. The
+	// MySQL Servers with a connection pool have multiple nodeIds.
+	// +optional
+	Nodes []NdbClusterNodeStatus `json:"nodes,omitempty"`
+	// MySQLServerGroups are the ranges of nodeIds reserved for the MySQL
+	// Server groups in the config. A group retains its range across the
+	// updates to the spec, as long as the range can hold all its MySQL
+	// Servers, so that its MySQL Servers are not moved to other nodeIds.
+	// +optional
+	MySQLServerGroups []NdbMysqldServerGroupStatus `json:"mysqlServerGroups,omitempty"`
 	// GeneratedRootPasswordSecretName is the name of the secret generated by the
 	// operator to be used as the MySQL Server root account password. This will
 	// be set to nil if a secret has been already provided to the operator via
@@ -343,20 +2071,172 @@ func (nc *NdbCluster) GetConfigMapName() string {
 	return nc.ObjectMeta.Name + "-config"
 }
 
+// GetStateDumpConfigMapName returns the name of the ConfigMap
+// into which the operator dumps its view of the MySQL Cluster
+func (nc *NdbCluster) GetStateDumpConfigMapName() string {
+	return nc.ObjectMeta.Name + "-state-dump"
+}
+
+// GetBackupCatalogConfigMapName returns the name of the ConfigMap in
+// which the operator catalogs the completed backups of the MySQL Cluster
+func (nc *NdbCluster) GetBackupCatalogConfigMapName() string {
+	return nc.ObjectMeta.Name + "-backups"
+}
+
+// GetBackupEncryptionPasswordSecretName returns the name of the Secret
+// holding the password with which the backups of the MySQL Cluster are
+// encrypted, or an empty string if they are not to be encrypted.
+func (nc *NdbCluster) GetBackupEncryptionPasswordSecretName() string {
+	if nc.Spec.Backup == nil {
+		return ""
+	}
+	return nc.Spec.Backup.EncryptionPasswordSecretName
+}
+
+// GetDataNodeBackupDataDir returns the directory, inside the data node
+// pods, into which the data nodes write the backups. It is the
+// BackupDataDir of the data nodes, which defaults to their FileSystemPath.
+func (nc *NdbCluster) GetDataNodeBackupDataDir() string {
+	defaultDir := constants.DataDir + "/data"
+	if nc.Spec.DataNode == nil {
+		return defaultDir
+	}
+
+	if _, backupDataDir := nc.getDataNodeConfigParam("BackupDataDir"); backupDataDir != nil {
+		return backupDataDir.String()
+	}
+
+	separateVolumes := nc.Spec.DataNode.SeparateVolumes
+	if separateVolumes != nil && separateVolumes.Backup != nil {
+		return constants.DataNodeBackupDir
+	}
+
+	if _, fileSystemPath := nc.getDataNodeConfigParam("FileSystemPath"); fileSystemPath != nil {
+		return fileSystemPath.String()
+	}
+
+	if separateVolumes != nil && separateVolumes.FileSystem != nil {
+		return constants.DataNodeFileSystemDir
+	}
+
+	return defaultDir
+}
+
 // GetPodDisruptionBudgetName returns the PDB name of a given resource
 func (nc *NdbCluster) GetPodDisruptionBudgetName(resource string) string {
 	return fmt.Sprintf("%s-pdb-%s", nc.ObjectMeta.Name, resource)
 }
 
-// GetManagementNodeCount returns the number of
-// management servers based on the redundancy levels
+// GetHorizontalPodAutoscalerName returns the name of the
+// HorizontalPodAutoscaler that scales the MySQL Servers
+func (nc *NdbCluster) GetHorizontalPodAutoscalerName() string {
+	return fmt.Sprintf("%s-hpa-%s", nc.ObjectMeta.Name, constants.NdbNodeTypeMySQLD)
+}
+
+// MySQLServerAutoscalingEnabled returns true if the MySQL Servers
+// have to be scaled by a HorizontalPodAutoscaler
+func (nc *NdbCluster) MySQLServerAutoscalingEnabled() bool {
+	return nc.Spec.MysqlNode != nil && nc.Spec.MysqlNode.Autoscaling != nil
+}
+
+// GetNetworkPolicyName returns the name of the
+// NetworkPolicy that isolates the MySQL Cluster
+func (nc *NdbCluster) GetNetworkPolicyName() string {
+	return nc.ObjectMeta.Name + "-network-policy"
+}
+
+// GetGatewayName returns the name of the Gateway
+// created by the operator to expose the MySQL Servers
+func (nc *NdbCluster) GetGatewayName() string {
+	return fmt.Sprintf("%s-gateway-%s", nc.ObjectMeta.Name, constants.NdbNodeTypeMySQLD)
+}
+
+// GetTCPRouteName returns the name of the TCPRoute
+// that routes the MySQL traffic to the MySQL Servers
+func (nc *NdbCluster) GetTCPRouteName() string {
+	return fmt.Sprintf("%s-tcproute-%s", nc.ObjectMeta.Name, constants.NdbNodeTypeMySQLD)
+}
+
+// GatewayEnabled returns true if the MySQL Servers
+// have to be exposed via the Gateway API
+func (nc *NdbCluster) GatewayEnabled() bool {
+	return nc.Spec.MysqlNode != nil && nc.Spec.MysqlNode.Gateway != nil
+}
+
+// NetworkPolicyEnabled returns true if the
+// MySQL Cluster has to be isolated by a NetworkPolicy
+func (nc *NdbCluster) NetworkPolicyEnabled() bool {
+	return nc.Spec.NetworkPolicy != nil
+}
+
+// GetAgentServiceAccountName returns the name of the ServiceAccount,
+// the Role and the RoleBinding created by the operator for the agents
+// running in the MySQL Cluster pods
+func (nc *NdbCluster) GetAgentServiceAccountName() string {
+	return nc.ObjectMeta.Name + "-ndb-agent"
+}
+
+// AgentServiceAccountEnabled returns true if the operator has to
+// create the ServiceAccount the MySQL Cluster pods run as
+func (nc *NdbCluster) AgentServiceAccountEnabled() bool {
+	return nc.Spec.ServiceAccount != nil && nc.Spec.ServiceAccount.Name == ""
+}
+
+// GetServiceAccountName returns the name of the ServiceAccount the
+// MySQL Cluster pods run as. It is empty if the pods have to run as
+// the default ServiceAccount of the namespace.
+func (nc *NdbCluster) GetServiceAccountName() string {
+	if nc.Spec.ServiceAccount == nil {
+		return ""
+	}
+	if nc.Spec.ServiceAccount.Name != "" {
+		return nc.Spec.ServiceAccount.Name
+	}
+	return nc.GetAgentServiceAccountName()
+}
+
+// GetInitFromBackupJobName returns the name of the Job
+// that restores the backup specified in spec.initFromBackup
+func (nc *NdbCluster) GetInitFromBackupJobName() string {
+	return nc.ObjectMeta.Name + "-init-from-backup"
+}
+
+// GetBackupVerificationJobName returns the name of the Job that verifies
+// the part of the backup with the given id taken by the data node running
+// in the pod with the given ordinal
+func (nc *NdbCluster) GetBackupVerificationJobName(backupId int32, dataNodeOrdinal int) string {
+	return fmt.Sprintf("%s-verify-backup-%d-%d", nc.ObjectMeta.Name, backupId, dataNodeOrdinal)
+}
+
+// GetBackupListingJobName returns the name of the Job that lists
+// the backups stored by the data nodes, to reconcile the backup catalog
+func (nc *NdbCluster) GetBackupListingJobName() string {
+	return nc.ObjectMeta.Name + "-list-backups"
+}
+
+// GetInitFromDumpJobName returns the name of the Job
+// that loads the SQL dump specified in spec.initFromDump
+func (nc *NdbCluster) GetInitFromDumpJobName() string {
+	return nc.ObjectMeta.Name + "-init-from-dump"
+}
+
+// GetManagementNodeCount returns the number of management servers
+// specified in the spec or, if unspecified, based on the redundancy levels
 func (nc *NdbCluster) GetManagementNodeCount() int32 {
+	if nc.Spec.ManagementNode != nil && nc.Spec.ManagementNode.NodeCount != 0 {
+		return nc.Spec.ManagementNode.NodeCount
+	}
 	if nc.Spec.RedundancyLevel == 1 {
 		return 1
 	}
 	return 2
 }
 
+// HasArbitrator returns true if an external arbitrator has to be run
+func (nc *NdbCluster) HasArbitrator() bool {
+	return nc.Spec.Arbitrator != nil
+}
+
 // GetMySQLServerNodeCount returns the number MySQL Servers
 // connected to the NDB Cluster as an SQL frontend
 func (nc *NdbCluster) GetMySQLServerNodeCount() int32 {
@@ -376,6 +2256,14 @@ func (nc *NdbCluster) GetMySQLServerMaxNodeCount() int32 {
 	return nc.Spec.MysqlNode.MaxNodeCount
 }
 
+// GetMySQLServerSelector returns the label selector that
+// matches the MySQL Server pods of the NdbCluster
+func (nc *NdbCluster) GetMySQLServerSelector() labels.Selector {
+	return labels.SelectorFromSet(nc.GetCompleteLabels(map[string]string{
+		constants.ClusterNodeTypeLabel: constants.NdbNodeTypeMySQLD,
+	}))
+}
+
 // GetMySQLServerConnectionPoolSize returns the connection pool size
 func (nc *NdbCluster) GetMySQLServerConnectionPoolSize() int32 {
 	if nc.Spec.MysqlNode == nil {
@@ -385,9 +2273,35 @@ func (nc *NdbCluster) GetMySQLServerConnectionPoolSize() int32 {
 	return nc.Spec.MysqlNode.ConnectionPoolSize
 }
 
+// GetPrimaryIPFamily returns the IP family preferred for the MySQL
+// Cluster, or an empty string if no IP families are specified.
+func (nc *NdbCluster) GetPrimaryIPFamily() corev1.IPFamily {
+	if len(nc.Spec.IPFamilies) == 0 {
+		return ""
+	}
+
+	return nc.Spec.IPFamilies[0]
+}
+
+// GetManagementNodePort returns the port used by the Management nodes
+func (nc *NdbCluster) GetManagementNodePort() int32 {
+	if nc.Spec.ManagementNode != nil && nc.Spec.ManagementNode.Port != 0 {
+		return nc.Spec.ManagementNode.Port
+	}
+	return constants.ManagementNodePort
+}
+
+// GetMySQLServerPort returns the port used by the MySQL Servers
+func (nc *NdbCluster) GetMySQLServerPort() int32 {
+	if nc.Spec.MysqlNode != nil && nc.Spec.MysqlNode.Port != 0 {
+		return nc.Spec.MysqlNode.Port
+	}
+	return constants.MySQLServerPort
+}
+
 // GetConnectstring returns the connect string of cluster represented by Ndb resource
 func (nc *NdbCluster) GetConnectstring() string {
-	port := "1186"
+	port := nc.GetManagementNodePort()
 
 	connectstring := ""
 	mgmdPodNamePrefix := nc.ObjectMeta.Name + "-mgmd"
@@ -398,7 +2312,7 @@ func (nc *NdbCluster) GetConnectstring() string {
 			connectstring += ","
 		}
 		connectstring += fmt.Sprintf(
-			"%s-%d.%s.%s.svc:%s", mgmdPodNamePrefix, i, mgmdServiceName, ndbNameSpace, port)
+			"%s-%d.%s.%s.svc:%d", mgmdPodNamePrefix, i, mgmdServiceName, ndbNameSpace, port)
 	}
 
 	return connectstring
@@ -416,13 +2330,13 @@ func (nc *NdbCluster) GetOwnerReferences() []metav1.OwnerReference {
 			})}
 }
 
-// GetMySQLCnf returns any specified additional MySQL Server cnf
-func (nc *NdbCluster) GetMySQLCnf() string {
-	if nc.Spec.MysqlNode == nil || nc.Spec.MysqlNode.MyCnf == "" {
+// getMySQLCnfWithSection returns the given my.cnf with
+// the mysqld section header prepended if it is missing.
+func getMySQLCnfWithSection(myCnf string) string {
+	if myCnf == "" {
 		return ""
 	}
 
-	myCnf := nc.Spec.MysqlNode.MyCnf
 	_, err := configparser.ParseString(myCnf)
 	if err != nil && strings.Contains(err.Error(), "Non-empty line without section") {
 		// section header is missing as it is optional - prepend and return
@@ -432,11 +2346,247 @@ func (nc *NdbCluster) GetMySQLCnf() string {
 	return myCnf
 }
 
+// GetMySQLCnf returns any specified additional MySQL Server cnf
+func (nc *NdbCluster) GetMySQLCnf() string {
+	if nc.Spec.MysqlNode == nil {
+		return ""
+	}
+
+	return getMySQLCnfWithSection(nc.Spec.MysqlNode.MyCnf)
+}
+
+// GetMySQLServerGroups returns the additional MySQL Server groups
+func (nc *NdbCluster) GetMySQLServerGroups() []NdbMysqldServerGroupSpec {
+	if nc.Spec.MysqlNode == nil {
+		return nil
+	}
+
+	return nc.Spec.MysqlNode.ServerGroups
+}
+
+// GetMySQLServerGroup returns the MySQL Server group with
+// the given name or nil if no such group is specified.
+func (nc *NdbCluster) GetMySQLServerGroup(name string) *NdbMysqldServerGroupSpec {
+	serverGroups := nc.GetMySQLServerGroups()
+	for i := range serverGroups {
+		if serverGroups[i].Name == name {
+			return &serverGroups[i]
+		}
+	}
+
+	return nil
+}
+
+// GetMySQLServerGroupWorkloadName returns the name of the
+// StatefulSet and the Service of the given MySQL Server group
+func (nc *NdbCluster) GetMySQLServerGroupWorkloadName(group string) string {
+	return nc.GetWorkloadName(group + "-" + constants.NdbNodeTypeMySQLD)
+}
+
 // GetWorkloadName returns the name K8s workload that manages the given NdbNodeType
 func (nc *NdbCluster) GetWorkloadName(nodeType constants.NdbNodeType) string {
 	return nc.ObjectMeta.Name + "-" + nodeType
 }
 
+// GetImage returns the name of the image to be used by the given NdbNodeType
+func (nc *NdbCluster) GetImage(nodeType constants.NdbNodeType) string {
+	var image string
+	switch nodeType {
+	case constants.NdbNodeTypeMgmd, constants.NdbNodeTypeArbitrator:
+		// The arbitrator runs a Management node
+		if nc.Spec.ManagementNode != nil {
+			image = nc.Spec.ManagementNode.Image
+		}
+	case constants.NdbNodeTypeNdbmtd:
+		if nc.Spec.DataNode != nil {
+			image = nc.Spec.DataNode.Image
+		}
+	case constants.NdbNodeTypeMySQLD:
+		if nc.Spec.MysqlNode != nil {
+			image = nc.Spec.MysqlNode.Image
+		}
+	}
+
+	if image == "" {
+		// Use the image common to all node types
+		image = nc.Spec.Image
+	}
+	return image
+}
+
+// GetUpdateStrategy returns the StatefulSet update strategy specified for
+// the given NdbNodeType, or nil if none was specified. The Data nodes are
+// always updated by the operator and do not support a custom strategy.
+func (nc *NdbCluster) GetUpdateStrategy(nodeType constants.NdbNodeType) *appsv1.StatefulSetUpdateStrategy {
+	switch nodeType {
+	case constants.NdbNodeTypeMgmd:
+		if nc.Spec.ManagementNode != nil {
+			return nc.Spec.ManagementNode.UpdateStrategy
+		}
+	case constants.NdbNodeTypeMySQLD:
+		if nc.Spec.MysqlNode != nil {
+			return nc.Spec.MysqlNode.UpdateStrategy
+		}
+	}
+	return nil
+}
+
+// GetPodManagementPolicy returns the StatefulSet pod management policy of
+// the given NdbNodeType. If no policy was specified for the node type, the
+// default policy, OrderedReady for the Management nodes and Parallel for
+// the Data nodes and the MySQL Servers, is returned.
+func (nc *NdbCluster) GetPodManagementPolicy(nodeType constants.NdbNodeType) appsv1.PodManagementPolicyType {
+	switch nodeType {
+	case constants.NdbNodeTypeNdbmtd:
+		if nc.Spec.DataNode != nil && nc.Spec.DataNode.PodManagementPolicy != "" {
+			return nc.Spec.DataNode.PodManagementPolicy
+		}
+		return appsv1.ParallelPodManagement
+	case constants.NdbNodeTypeMySQLD:
+		if nc.Spec.MysqlNode != nil && nc.Spec.MysqlNode.PodManagementPolicy != "" {
+			return nc.Spec.MysqlNode.PodManagementPolicy
+		}
+		return appsv1.ParallelPodManagement
+	case constants.NdbNodeTypeMgmd:
+		if nc.Spec.ManagementNode != nil && nc.Spec.ManagementNode.PodManagementPolicy != "" {
+			return nc.Spec.ManagementNode.PodManagementPolicy
+		}
+	}
+	return appsv1.OrderedReadyPodManagement
+}
+
+// UsesHostNetwork returns true if the nodes of
+// the given type have to run in the host network
+func (nc *NdbCluster) UsesHostNetwork(nodeType constants.NdbNodeType) bool {
+	switch nodeType {
+	case constants.NdbNodeTypeMgmd:
+		return nc.Spec.ManagementNode != nil && nc.Spec.ManagementNode.HostNetwork
+	case constants.NdbNodeTypeNdbmtd:
+		return nc.Spec.DataNode != nil && nc.Spec.DataNode.HostNetwork
+	case constants.NdbNodeTypeMySQLD:
+		return nc.Spec.MysqlNode != nil && nc.Spec.MysqlNode.HostNetwork
+	}
+	return false
+}
+
+// GetDataNodeServerPort returns the port used by the Data nodes
+func (nc *NdbCluster) GetDataNodeServerPort() int32 {
+	if nc.Spec.DataNode != nil && nc.Spec.DataNode.ServerPort != 0 {
+		return nc.Spec.DataNode.ServerPort
+	}
+	if nc.UsesHostNetwork(constants.NdbNodeTypeNdbmtd) {
+		return constants.DataNodeHostNetworkServerPort
+	}
+	return constants.DataNodeServerPort
+}
+
+// GetImagePullSecrets returns all the secrets to be used for pulling the MySQL Cluster images
+func (nc *NdbCluster) GetImagePullSecrets() []corev1.LocalObjectReference {
+	var imagePullSecrets []corev1.LocalObjectReference
+	if nc.Spec.ImagePullSecretName != "" {
+		imagePullSecrets = append(imagePullSecrets, corev1.LocalObjectReference{
+			Name: nc.Spec.ImagePullSecretName,
+		})
+	}
+
+	for _, secret := range nc.Spec.ImagePullSecrets {
+		if secret.Name != nc.Spec.ImagePullSecretName {
+			imagePullSecrets = append(imagePullSecrets, secret)
+		}
+	}
+	return imagePullSecrets
+}
+
+// getNodeTypeMetadata returns the custom pod labels, pod annotations
+// and service annotations specified for the given NdbNodeType
+func (nc *NdbCluster) getNodeTypeMetadata(
+	nodeType constants.NdbNodeType) (podLabels, podAnnotations, serviceAnnotations map[string]string) {
+	switch nodeType {
+	case constants.NdbNodeTypeMgmd:
+		if mgmdSpec := nc.Spec.ManagementNode; mgmdSpec != nil {
+			return mgmdSpec.PodLabels, mgmdSpec.PodAnnotations, mgmdSpec.ServiceAnnotations
+		}
+	case constants.NdbNodeTypeNdbmtd:
+		if dataNodeSpec := nc.Spec.DataNode; dataNodeSpec != nil {
+			return dataNodeSpec.PodLabels, dataNodeSpec.PodAnnotations, dataNodeSpec.ServiceAnnotations
+		}
+	case constants.NdbNodeTypeMySQLD:
+		if mysqldSpec := nc.Spec.MysqlNode; mysqldSpec != nil {
+			return mysqldSpec.PodLabels, mysqldSpec.PodAnnotations, mysqldSpec.ServiceAnnotations
+		}
+	}
+	return nil, nil, nil
+}
+
+// GetCustomPodLabels returns the custom labels to be added to the pods of
+// the given NdbNodeType. The node type specific labels take precedence
+// over the labels specified for all the pods.
+func (nc *NdbCluster) GetCustomPodLabels(nodeType constants.NdbNodeType) map[string]string {
+	podLabels, _, _ := nc.getNodeTypeMetadata(nodeType)
+	return labels.Merge(nc.Spec.PodLabels, podLabels)
+}
+
+// GetCustomPodAnnotations returns the custom annotations to be added to the pods
+// of the given NdbNodeType. The node type specific annotations take precedence
+// over the annotations specified for all the pods.
+func (nc *NdbCluster) GetCustomPodAnnotations(nodeType constants.NdbNodeType) map[string]string {
+	_, podAnnotations, _ := nc.getNodeTypeMetadata(nodeType)
+	return labels.Merge(nc.Spec.PodAnnotations, podAnnotations)
+}
+
+// GetCustomServiceAnnotations returns the custom annotations to be added to the
+// Services of the given NdbNodeType. The node type specific annotations take
+// precedence over the annotations specified for all the Services.
+func (nc *NdbCluster) GetCustomServiceAnnotations(nodeType constants.NdbNodeType) map[string]string {
+	_, _, serviceAnnotations := nc.getNodeTypeMetadata(nodeType)
+	return labels.Merge(nc.Spec.ServiceAnnotations, serviceAnnotations)
+}
+
+// GetLoadBalancerSpec returns the additional configuration of the
+// LoadBalancer Service of the given NdbNodeType, if any.
+func (nc *NdbCluster) GetLoadBalancerSpec(nodeType constants.NdbNodeType) *NdbLoadBalancerSpec {
+	switch nodeType {
+	case constants.NdbNodeTypeMgmd:
+		if mgmdSpec := nc.Spec.ManagementNode; mgmdSpec != nil {
+			return mgmdSpec.LoadBalancer
+		}
+	case constants.NdbNodeTypeMySQLD:
+		if mysqldSpec := nc.Spec.MysqlNode; mysqldSpec != nil {
+			return mysqldSpec.LoadBalancer
+		}
+	}
+	return nil
+}
+
+// GetDataNodeLogLevelConfigs returns the LogLevel* config
+// parameters of the data nodes, as specified by the
+// spec.dataNode.logLevels, keyed by the parameter name.
+func (nc *NdbCluster) GetDataNodeLogLevelConfigs() map[string]int32 {
+	logLevels := nc.Spec.DataNode.LogLevels
+	if logLevels == nil {
+		return nil
+	}
+
+	logLevelConfigs := make(map[string]int32)
+	for configKey, level := range map[string]*int32{
+		"LogLevelStartup":     logLevels.Startup,
+		"LogLevelShutdown":    logLevels.Shutdown,
+		"LogLevelStatistic":   logLevels.Statistic,
+		"LogLevelCheckpoint":  logLevels.Checkpoint,
+		"LogLevelNodeRestart": logLevels.NodeRestart,
+		"LogLevelConnection":  logLevels.Connection,
+		"LogLevelCongestion":  logLevels.Congestion,
+		"LogLevelError":       logLevels.Error,
+		"LogLevelInfo":        logLevels.Info,
+	} {
+		if level != nil {
+			logLevelConfigs[configKey] = *level
+		}
+	}
+
+	return logLevelConfigs
+}
+
 // getCondition returns the NdbClusterCondition of condType from NdbCluster resource
 func (nc *NdbCluster) getCondition(condType NdbClusterConditionType) *NdbClusterCondition {
 	for _, condition := range nc.Status.Conditions {
@@ -448,6 +2598,104 @@ func (nc *NdbCluster) getCondition(condType NdbClusterConditionType) *NdbCluster
 	return nil
 }
 
+// GetUpToDateCondition returns the NdbClusterUpToDate condition
+// of the NdbCluster resource, or nil if it has not been set yet.
+func (nc *NdbCluster) GetUpToDateCondition() *NdbClusterCondition {
+	return nc.getCondition(NdbClusterUpToDate)
+}
+
+// GetPartitionedCondition returns the NdbClusterPartitioned condition
+// of the NdbCluster resource, or nil if it has not been set yet.
+func (nc *NdbCluster) GetPartitionedCondition() *NdbClusterCondition {
+	return nc.getCondition(NdbClusterPartitioned)
+}
+
+// GetZoneRedundantCondition returns the NdbClusterZoneRedundant condition
+// of the NdbCluster resource, or nil if it has not been set yet.
+func (nc *NdbCluster) GetZoneRedundantCondition() *NdbClusterCondition {
+	return nc.getCondition(NdbClusterZoneRedundant)
+}
+
+// GetStorageResizedCondition returns the NdbClusterStorageResized
+// condition of the NdbCluster resource, or nil if it has not been set yet.
+func (nc *NdbCluster) GetStorageResizedCondition() *NdbClusterCondition {
+	return nc.getCondition(NdbClusterStorageResized)
+}
+
+// GetLocalVolumesAvailableCondition returns the NdbClusterLocalVolumesAvailable
+// condition of the NdbCluster resource, or nil if it has not been set yet.
+func (nc *NdbCluster) GetLocalVolumesAvailableCondition() *NdbClusterCondition {
+	return nc.getCondition(NdbClusterLocalVolumesAvailable)
+}
+
+// GetHealthyCondition returns the NdbClusterHealthy condition
+// of the NdbCluster resource, or nil if it has not been set yet.
+func (nc *NdbCluster) GetHealthyCondition() *NdbClusterCondition {
+	return nc.getCondition(NdbClusterHealthy)
+}
+
+// GetNodesConnectedCondition returns the NdbClusterNodesConnected
+// condition of the NdbCluster resource, or nil if it has not been set yet.
+func (nc *NdbCluster) GetNodesConnectedCondition() *NdbClusterCondition {
+	return nc.getCondition(NdbClusterNodesConnected)
+}
+
+// GetDataNodeRestartLoopCondition returns the NdbClusterDataNodeRestartLoop
+// condition of the NdbCluster resource, or nil if it has not been set yet.
+func (nc *NdbCluster) GetDataNodeRestartLoopCondition() *NdbClusterCondition {
+	return nc.getCondition(NdbClusterDataNodeRestartLoop)
+}
+
+// GetDegradedCondition returns the NdbClusterDegraded condition
+// of the NdbCluster resource, or nil if it has not been set yet.
+func (nc *NdbCluster) GetDegradedCondition() *NdbClusterCondition {
+	return nc.getCondition(NdbClusterDegraded)
+}
+
+// IsDataNodeRestartAllowed returns true if the spec allows the
+// data nodes to be restarted with the given type of restart
+func (nc *NdbCluster) IsDataNodeRestartAllowed(restartType DataNodeRestartType) bool {
+	switch restartType {
+	case DataNodeSystemRestart:
+		return nc.Spec.DataNode.SystemRestart != nil && nc.Spec.DataNode.SystemRestart.Allowed
+	case DataNodeInitialSystemRestart:
+		return nc.Spec.RedundancyLevelUpdateStrategy == NdbRedundancyLevelUpdateRecreate
+	default:
+		return true
+	}
+}
+
+// GetRestartRequests returns the annotations of the NdbCluster resource
+// that request a rolling restart of the MySQL Cluster nodes or a restart
+// of a single data node.
+func (nc *NdbCluster) GetRestartRequests() map[string]string {
+	var restartRequests map[string]string
+	for key, value := range nc.Annotations {
+		switch key {
+		case RestartAnnotationPrefix + constants.NdbNodeTypeMgmd,
+			RestartAnnotationPrefix + constants.NdbNodeTypeNdbmtd,
+			RestartAnnotationPrefix + constants.NdbNodeTypeMySQLD,
+			InitialRestartAnnotation:
+		default:
+			if !strings.HasPrefix(key, DataNodeRestartAnnotationPrefix) {
+				continue
+			}
+		}
+
+		if restartRequests == nil {
+			restartRequests = make(map[string]string)
+		}
+		restartRequests[key] = value
+	}
+	return restartRequests
+}
+
+// ReconcilingStopped returns true if the reconciliation of
+// the NdbCluster has been stopped via its annotations
+func (nc *NdbCluster) ReconcilingStopped() bool {
+	return nc.Annotations[StopReconcilingAnnotation] == "true"
+}
+
 // HasSyncError returns if there is any error in the NdbClusterUpToDate condition
 func (nc *NdbCluster) HasSyncError() bool {
 	upToDateCond := nc.getCondition(NdbClusterUpToDate)
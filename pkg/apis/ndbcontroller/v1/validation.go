@@ -1,4 +1,4 @@
-// Copyright (c) 2021, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2021, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -8,13 +8,20 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/mysql/ndb-operator/pkg/constants"
 	"github.com/mysql/ndb-operator/pkg/ndbconfig/configparser"
+	"github.com/mysql/ndb-operator/pkg/placement"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -52,6 +59,488 @@ func validateConfigParams(config map[string]*intstr.IntOrString, specPath *field
 	return errList
 }
 
+// validateConfigParamsNotSetBySpec checks that the given config params,
+// which have been set via the fields of the spec, are not specified
+// again in the config.
+func validateConfigParamsNotSetBySpec(
+	config map[string]*intstr.IntOrString, configParamsSetBySpec []string,
+	specPath *field.Path, specFieldPath *field.Path) (errList field.ErrorList) {
+	for configKey := range config {
+		for _, configParam := range configParamsSetBySpec {
+			if strings.EqualFold(configKey, configParam) {
+				msg := fmt.Sprintf("config param %q is not allowed in %s as it is already specified via %s.",
+					configKey, specPath.String(), specFieldPath.String())
+				errList = append(errList, field.Forbidden(specPath.Child(configKey), msg))
+			}
+		}
+	}
+	return errList
+}
+
+// validThreadTypes are the types of the threads that can be specified in the ThreadConfig
+var validThreadTypes = map[string]bool{
+	"ldm": true, "query": true, "recover": true, "tc": true, "send": true, "recv": true,
+	"main": true, "rep": true, "io": true, "watchdog": true, "idxbld": true,
+}
+
+// splitThreadConfig splits the given ThreadConfig into its entries at
+// the commas outside the braces. It returns false if the braces in the
+// ThreadConfig are unbalanced or nested.
+func splitThreadConfig(threadConfig string) (entries []string, ok bool) {
+	depth, entryStart := 0, 0
+	for i, c := range threadConfig {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				entries = append(entries, threadConfig[entryStart:i])
+				entryStart = i + 1
+			}
+		}
+		if depth < 0 || depth > 1 {
+			return nil, false
+		}
+	}
+	if depth != 0 {
+		return nil, false
+	}
+	return append(entries, threadConfig[entryStart:]), true
+}
+
+// validateThreadConfig does a basic syntax check of the given
+// ThreadConfig, which is a comma separated list of thread types,
+// each optionally followed by its properties enclosed in braces,
+// e.g. "ldm={count=4},tc={count=2},main,recv".
+func validateThreadConfig(threadConfig string, specPath *field.Path) (errList field.ErrorList) {
+	entries, ok := splitThreadConfig(threadConfig)
+	if !ok {
+		return append(errList, field.Invalid(specPath, threadConfig, "has unbalanced or nested braces"))
+	}
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		threadType, properties, hasProperties := strings.Cut(entry, "=")
+		threadType = strings.TrimSpace(threadType)
+		if !validThreadTypes[strings.ToLower(threadType)] {
+			errList = append(errList, field.Invalid(specPath, threadConfig,
+				fmt.Sprintf("has an unknown thread type %q", threadType)))
+			continue
+		}
+
+		properties = strings.TrimSpace(properties)
+		if hasProperties && (!strings.HasPrefix(properties, "{") || !strings.HasSuffix(properties, "}")) {
+			errList = append(errList, field.Invalid(specPath, threadConfig,
+				fmt.Sprintf("should enclose the properties of the thread type %q in braces", threadType)))
+		}
+	}
+
+	return errList
+}
+
+// validateHugePages validates the huge pages requested for the data nodes
+func validateHugePages(dataNodeSpec *NdbDataNodeSpec, dataNodePath *field.Path) (errList field.ErrorList) {
+	hugePages := dataNodeSpec.HugePages
+	if hugePages == nil {
+		return nil
+	}
+
+	hugePagesPath := dataNodePath.Child("hugePages")
+	pageSize, err := resource.ParseQuantity(hugePages.PageSize)
+	if err != nil {
+		return append(errList, field.Invalid(hugePagesPath.Child("pageSize"), hugePages.PageSize, err.Error()))
+	}
+
+	if hugePages.Size.Sign() <= 0 {
+		errList = append(errList, field.Invalid(hugePagesPath.Child("size"),
+			hugePages.Size.String(), "should be greater than 0"))
+	} else if hugePages.Size.Value()%pageSize.Value() != 0 {
+		errList = append(errList, field.Invalid(hugePagesPath.Child("size"),
+			hugePages.Size.String(), fmt.Sprintf("should be a multiple of the pageSize %s", hugePages.PageSize)))
+	}
+
+	// K8s allows requesting huge pages only along with a cpu or a memory resource
+	hasCPUOrMemory := false
+	if ndbPodSpec := dataNodeSpec.NdbPodSpec; ndbPodSpec != nil && ndbPodSpec.Resources != nil {
+		for _, resourceList := range []corev1.ResourceList{ndbPodSpec.Resources.Requests, ndbPodSpec.Resources.Limits} {
+			_, hasCPU := resourceList[corev1.ResourceCPU]
+			_, hasMemory := resourceList[corev1.ResourceMemory]
+			hasCPUOrMemory = hasCPUOrMemory || hasCPU || hasMemory
+		}
+	}
+	if !hasCPUOrMemory {
+		errList = append(errList, field.Required(dataNodePath.Child("ndbPodSpec", "resources"),
+			"a cpu or a memory resource should be specified when requesting huge pages"))
+	}
+
+	return errList
+}
+
+// validatePodDisruptionBudgetSpec validates the minAvailable value of the given PodDisruptionBudget spec
+func validatePodDisruptionBudgetSpec(pdbSpec *NdbPodDisruptionBudgetSpec, specPath *field.Path) (errList field.ErrorList) {
+	if pdbSpec == nil || pdbSpec.MinAvailable == nil {
+		return nil
+	}
+
+	minAvailablePath := specPath.Child("minAvailable")
+	if value, err := intstr.GetScaledValueFromIntOrPercent(pdbSpec.MinAvailable, 100, false); err != nil {
+		errList = append(errList, field.Invalid(minAvailablePath, pdbSpec.MinAvailable.String(), err.Error()))
+	} else if value < 0 {
+		errList = append(errList, field.Invalid(minAvailablePath, pdbSpec.MinAvailable.String(), "cannot be negative"))
+	}
+	return errList
+}
+
+// validateUpdateStrategy validates the given StatefulSet update strategy
+func validateUpdateStrategy(updateStrategy *appsv1.StatefulSetUpdateStrategy, specPath *field.Path) (errList field.ErrorList) {
+	if updateStrategy == nil {
+		return nil
+	}
+
+	switch updateStrategy.Type {
+	case "", appsv1.RollingUpdateStatefulSetStrategyType:
+		rollingUpdate := updateStrategy.RollingUpdate
+		if rollingUpdate != nil && rollingUpdate.Partition != nil && *rollingUpdate.Partition < 0 {
+			errList = append(errList, field.Invalid(
+				specPath.Child("rollingUpdate", "partition"), *rollingUpdate.Partition, "cannot be negative"))
+		}
+	case appsv1.OnDeleteStatefulSetStrategyType:
+		if updateStrategy.RollingUpdate != nil {
+			errList = append(errList, field.Invalid(specPath.Child("rollingUpdate"), updateStrategy.RollingUpdate,
+				fmt.Sprintf("only allowed for %s.type %q", specPath.String(), appsv1.RollingUpdateStatefulSetStrategyType)))
+		}
+	default:
+		errList = append(errList, field.NotSupported(specPath.Child("type"), updateStrategy.Type,
+			[]string{string(appsv1.RollingUpdateStatefulSetStrategyType), string(appsv1.OnDeleteStatefulSetStrategyType)}))
+	}
+	return errList
+}
+
+// validateCustomMetadata validates the custom pod labels, pod
+// annotations and service annotations specified at the given specPath
+func validateCustomMetadata(
+	podLabels, podAnnotations, serviceAnnotations map[string]string, specPath *field.Path) (errList field.ErrorList) {
+	errList = append(errList, metav1validation.ValidateLabels(podLabels, specPath.Child("podLabels"))...)
+	errList = append(errList, apivalidation.ValidateAnnotations(podAnnotations, specPath.Child("podAnnotations"))...)
+	errList = append(errList, apivalidation.ValidateAnnotations(serviceAnnotations, specPath.Child("serviceAnnotations"))...)
+	return errList
+}
+
+// validateLoadBalancerSpec validates the additional configuration
+// specified for the LoadBalancer Service of a MySQL Cluster node type
+func validateLoadBalancerSpec(lbSpec *NdbLoadBalancerSpec, specPath *field.Path) (errList field.ErrorList) {
+	if lbSpec == nil {
+		return nil
+	}
+
+	if lbSpec.LoadBalancerClass != nil {
+		for _, err := range validation.IsQualifiedName(*lbSpec.LoadBalancerClass) {
+			errList = append(errList,
+				field.Invalid(specPath.Child("loadBalancerClass"), *lbSpec.LoadBalancerClass, err))
+		}
+	}
+
+	for i, sourceRange := range lbSpec.LoadBalancerSourceRanges {
+		if _, _, err := net.ParseCIDR(sourceRange); err != nil {
+			errList = append(errList, field.Invalid(specPath.Child("loadBalancerSourceRanges").Index(i),
+				sourceRange, "must be a valid CIDR, e.g. 10.0.0.0/8"))
+		}
+	}
+
+	errList = append(errList, apivalidation.ValidateAnnotations(lbSpec.Annotations, specPath.Child("annotations"))...)
+	return errList
+}
+
+// validateGatewaySpec validates the Gateway API
+// resources specified to expose the MySQL Servers
+func validateGatewaySpec(gateway *NdbMysqldGatewaySpec, specPath *field.Path) (errList field.ErrorList) {
+	switch {
+	case gateway.GatewayClassName == "" && gateway.GatewayName == "":
+		errList = append(errList, field.Required(specPath,
+			"either the gatewayClassName or the gatewayName should be specified"))
+	case gateway.GatewayClassName != "" && gateway.GatewayName != "":
+		errList = append(errList, field.Forbidden(specPath.Child("gatewayName"),
+			"gatewayName cannot be specified along with the gatewayClassName"))
+	}
+
+	if gateway.GatewayName != "" {
+		for _, err := range validation.IsDNS1123Subdomain(gateway.GatewayName) {
+			errList = append(errList, field.Invalid(specPath.Child("gatewayName"), gateway.GatewayName, err))
+		}
+	} else {
+		if gateway.ListenerName != "" {
+			errList = append(errList, field.Forbidden(specPath.Child("listenerName"),
+				"listenerName can be specified only along with the gatewayName"))
+		}
+		errList = append(errList,
+			apivalidation.ValidateAnnotations(gateway.Annotations, specPath.Child("annotations"))...)
+	}
+
+	if gateway.ListenerName != "" {
+		for _, err := range validation.IsDNS1123Subdomain(gateway.ListenerName) {
+			errList = append(errList, field.Invalid(specPath.Child("listenerName"), gateway.ListenerName, err))
+		}
+	}
+
+	return errList
+}
+
+// validateServiceAccountSpec validates the
+// ServiceAccount the MySQL Cluster pods run as
+func validateServiceAccountSpec(serviceAccount *NdbServiceAccountSpec, specPath *field.Path) (errList field.ErrorList) {
+	if serviceAccount.Name == "" {
+		return nil
+	}
+
+	for _, err := range validation.IsDNS1123Subdomain(serviceAccount.Name) {
+		errList = append(errList, field.Invalid(specPath.Child("name"), serviceAccount.Name, err))
+	}
+	if len(serviceAccount.Rules) != 0 {
+		errList = append(errList, field.Forbidden(specPath.Child("rules"),
+			"rules cannot be specified along with the name of an existing ServiceAccount"))
+	}
+
+	return errList
+}
+
+// validateInitFromBackupSpec validates the database and table names
+// specified in spec.initFromBackup to filter and rewrite the restore
+func validateInitFromBackupSpec(
+	initFromBackup *NdbClusterInitFromBackupSpec, specPath *field.Path) (errList field.ErrorList) {
+
+	// The names are passed to ndb_restore as comma-separated lists
+	validateName := func(namePath *field.Path, name string) {
+		if name == "" || strings.ContainsAny(name, ", \t\n") {
+			errList = append(errList, field.Invalid(namePath, name,
+				"should be non-empty and should not contain any comma or whitespace"))
+		}
+	}
+
+	for _, databases := range []struct {
+		name  string
+		names []string
+	}{
+		{"includeDatabases", initFromBackup.IncludeDatabases},
+		{"excludeDatabases", initFromBackup.ExcludeDatabases},
+	} {
+		for i, database := range databases.names {
+			validateName(specPath.Child(databases.name).Index(i), database)
+		}
+	}
+
+	for _, tables := range []struct {
+		name  string
+		names []string
+	}{
+		{"includeTables", initFromBackup.IncludeTables},
+		{"excludeTables", initFromBackup.ExcludeTables},
+	} {
+		for i, table := range tables.names {
+			tablePath := specPath.Child(tables.name).Index(i)
+			if database, tableName, found := strings.Cut(table, "."); !found || database == "" || tableName == "" {
+				errList = append(errList, field.Invalid(tablePath, table,
+					"should be in the <database>.<table> format"))
+				continue
+			}
+			validateName(tablePath, table)
+		}
+	}
+
+	rewritten := make(map[string]bool)
+	for i, rewrite := range initFromBackup.RewriteDatabases {
+		rewritePath := specPath.Child("rewriteDatabases").Index(i)
+		validateName(rewritePath.Child("from"), rewrite.From)
+		validateName(rewritePath.Child("to"), rewrite.To)
+		if rewritten[rewrite.From] {
+			errList = append(errList, field.Duplicate(rewritePath.Child("from"), rewrite.From))
+		}
+		rewritten[rewrite.From] = true
+	}
+
+	return errList
+}
+
+// validateNames verifies that the given names are valid DNS labels and are unique
+func validateNames(names []string, specPath *field.Path) (errList field.ErrorList) {
+	seen := make(map[string]bool)
+	for i, name := range names {
+		namePath := specPath.Index(i).Child("name")
+		for _, err := range validation.IsDNS1123Label(name) {
+			errList = append(errList, field.Invalid(namePath, name, err))
+		}
+		if seen[name] {
+			errList = append(errList, field.Duplicate(namePath, name))
+		}
+		seen[name] = true
+	}
+	return errList
+}
+
+// isReservedContainerName returns true if the given name
+// is used by a container created by the operator
+func isReservedContainerName(name string) bool {
+	switch name {
+	case "ndb-pod-init-container", "mysqld-plugin-dir-init":
+		return true
+	}
+	for _, nodeType := range []constants.NdbNodeType{constants.NdbNodeTypeMgmd,
+		constants.NdbNodeTypeNdbmtd, constants.NdbNodeTypeMySQLD, constants.NdbNodeTypeArbitrator} {
+		if name == nodeType+"-container" || name == nodeType+"-init-container" {
+			return true
+		}
+	}
+	return false
+}
+
+// isReservedVolumeName returns true if the given name
+// is used by a volume created by the operator
+func isReservedVolumeName(name string) bool {
+	switch name {
+	case "ndb-work-dir-vol", "helper-scripts-vol", "mgmd-config-volume",
+		"ndbmtd-hugepages", "ndbmtd-filesystem-vol", "ndbmtd-backup-vol", "ndbmtd-undo-vol",
+		"mysqld-init-scripts-vol", "mysqld-cnf-vol", "mysqld-plugin-dir-vol",
+		"mysqld-audit-log-vol", "mysqld-secrets-store-vol":
+		return true
+	}
+	for _, nodeType := range []constants.NdbNodeType{constants.NdbNodeTypeMgmd,
+		constants.NdbNodeTypeNdbmtd, constants.NdbNodeTypeMySQLD, constants.NdbNodeTypeArbitrator} {
+		if name == nodeType+"-data-vol" {
+			return true
+		}
+	}
+	return false
+}
+
+// validateNotReserved verifies that none of the given names
+// are reserved for the containers or volumes of the operator
+func validateNotReserved(names []string, isReserved func(string) bool, specPath *field.Path) (errList field.ErrorList) {
+	for i, name := range names {
+		if isReserved(name) {
+			errList = append(errList, field.Invalid(
+				specPath.Index(i).Child("name"), name, "name is reserved for use by the operator"))
+		}
+	}
+	return errList
+}
+
+// validateNdbPodSpecExtensions validates the names of the additional
+// containers and volumes specified via the given NdbClusterPodSpec
+func validateNdbPodSpecExtensions(ndbPodSpec *NdbClusterPodSpec, specPath *field.Path) (errList field.ErrorList) {
+	if ndbPodSpec == nil {
+		return nil
+	}
+
+	// Init and sidecar containers share the same namespace for names
+	var initContainerNames, containerNames []string
+	for _, container := range ndbPodSpec.InitContainers {
+		initContainerNames = append(initContainerNames, container.Name)
+	}
+	for _, container := range ndbPodSpec.SidecarContainers {
+		containerNames = append(containerNames, container.Name)
+	}
+	errList = append(errList, validateNames(initContainerNames, specPath.Child("initContainers"))...)
+	errList = append(errList, validateNames(containerNames, specPath.Child("sidecarContainers"))...)
+	errList = append(errList, validateNotReserved(
+		initContainerNames, isReservedContainerName, specPath.Child("initContainers"))...)
+	errList = append(errList, validateNotReserved(
+		containerNames, isReservedContainerName, specPath.Child("sidecarContainers"))...)
+	for i, name := range containerNames {
+		for _, initContainerName := range initContainerNames {
+			if name == initContainerName {
+				errList = append(errList, field.Duplicate(
+					specPath.Child("sidecarContainers").Index(i).Child("name"), name))
+			}
+		}
+	}
+
+	var volumeNames []string
+	for _, volume := range ndbPodSpec.Volumes {
+		volumeNames = append(volumeNames, volume.Name)
+	}
+	errList = append(errList, validateNames(volumeNames, specPath.Child("volumes"))...)
+	errList = append(errList, validateNotReserved(volumeNames, isReservedVolumeName, specPath.Child("volumes"))...)
+	return errList
+}
+
+// validateDiskDataSpec validates the Disk Data objects. The files of all the
+// objects are created in the same directory of the data nodes, so their names
+// need to be unique, and their total size should fit in the data node PVCs.
+// The undo files should instead fit in the separate undo files PVCs, if any.
+// The files without a size should have storage left to be sized automatically.
+func validateDiskDataSpec(
+	diskDataSpec *NdbDiskDataSpec, pvcSpec, undoFilesPVCSpec *corev1.PersistentVolumeClaimSpec,
+	specPath *field.Path) (errList field.ErrorList) {
+	if diskDataSpec == nil {
+		return nil
+	}
+
+	fileNames := make(map[string]bool)
+	fileSizes := diskDataSpec.GetFileSizes(pvcSpec, undoFilesPVCSpec, nil)
+	totalSize := resource.NewQuantity(0, resource.BinarySI)
+	validateFiles := func(files []NdbDiskDataFileSpec, filesPath *field.Path, size *resource.Quantity) {
+		for i, file := range files {
+			filePath := filesPath.Index(i)
+			if fileNames[file.Name] {
+				errList = append(errList, field.Duplicate(filePath.Child("name"), file.Name))
+			}
+			fileNames[file.Name] = true
+			if file.Size == nil {
+				if fileSizes[file.Name] == 0 {
+					errList = append(errList, field.Required(filePath.Child("size"),
+						"size is required as there is no storage left in the PVCs to size the file automatically"))
+				}
+				continue
+			}
+			if file.Size.Sign() <= 0 {
+				errList = append(errList, field.Invalid(filePath.Child("size"), file.Size.String(), "should be positive"))
+			}
+			size.Add(*file.Size)
+		}
+	}
+
+	// Validate the logfile group
+	logfileGroupPath := specPath.Child("logfileGroup")
+	undoFilesSize := totalSize
+	if undoFilesPVCSpec != nil {
+		// The undo files are stored in a separate volume
+		undoFilesSize = resource.NewQuantity(0, resource.BinarySI)
+	}
+	validateFiles(diskDataSpec.LogfileGroup.UndoFiles, logfileGroupPath.Child("undoFiles"), undoFilesSize)
+
+	// Validate the tablespaces
+	tablespaceNames := make(map[string]bool)
+	for i, tablespace := range diskDataSpec.Tablespaces {
+		tablespacePath := specPath.Child("tablespaces").Index(i)
+		if tablespaceNames[tablespace.Name] {
+			errList = append(errList, field.Duplicate(tablespacePath.Child("name"), tablespace.Name))
+		}
+		tablespaceNames[tablespace.Name] = true
+		validateFiles(tablespace.DataFiles, tablespacePath.Child("dataFiles"), totalSize)
+	}
+
+	// Verify that the files fit into the data node PVCs
+	if pvcSpec != nil {
+		if storage, exists := pvcSpec.Resources.Requests[corev1.ResourceStorage]; exists && totalSize.Cmp(storage) > 0 {
+			msg := fmt.Sprintf("total size of the Disk Data files (%s) exceeds the storage requested by the data node PVCs (%s)",
+				totalSize.String(), storage.String())
+			errList = append(errList, field.Invalid(specPath, totalSize.String(), msg))
+		}
+	}
+
+	// Verify that the undo files fit into the separate undo files PVCs
+	if undoFilesPVCSpec != nil {
+		if storage, exists := undoFilesPVCSpec.Resources.Requests[corev1.ResourceStorage]; exists && undoFilesSize.Cmp(storage) > 0 {
+			msg := fmt.Sprintf("total size of the undo files (%s) exceeds the storage requested by the undo files PVCs (%s)",
+				undoFilesSize.String(), storage.String())
+			errList = append(errList, field.Invalid(logfileGroupPath.Child("undoFiles"), undoFilesSize.String(), msg))
+		}
+	}
+
+	return errList
+}
+
 // HasValidSpec validates the spec of the NdbCluster object
 func (nc *NdbCluster) HasValidSpec() (bool, field.ErrorList) {
 	spec := nc.Spec
@@ -64,8 +553,12 @@ func (nc *NdbCluster) HasValidSpec() (bool, field.ErrorList) {
 
 	dataNodeCount := spec.DataNode.NodeCount
 	mysqlServerCount := nc.GetMySQLServerMaxNodeCount()
+	for _, serverGroup := range nc.GetMySQLServerGroups() {
+		mysqlServerCount += serverGroup.NodeCount
+	}
 	managementNodeCount := nc.GetManagementNodeCount()
 	numOfFreeApiSlots := spec.FreeAPISlots + 1
+	numOfNdbAPIApplicationSlots := nc.getNumOfNdbAPIApplicationSections()
 
 	// check if number of data nodes is a multiple of redundancy
 	if math.Mod(float64(dataNodeCount), float64(spec.RedundancyLevel)) != 0 {
@@ -74,27 +567,225 @@ func (nc *NdbCluster) HasValidSpec() (bool, field.ErrorList) {
 		errList = append(errList, field.Invalid(dataNodePath.Child("nodeCount"), dataNodeCount, msg))
 	}
 
+	// check if the data nodes can be placed in the given zones
+	if len(spec.DataNode.Zones) != 0 {
+		zonesPath := dataNodePath.Child("zones")
+		for i, zone := range spec.DataNode.Zones {
+			for _, err := range validation.IsValidLabelValue(zone) {
+				errList = append(errList, field.Invalid(zonesPath.Index(i), zone, err))
+			}
+		}
+		// The node count has already been verified
+		// to be a multiple of the redundancy level
+		if spec.RedundancyLevel > 0 && dataNodeCount%spec.RedundancyLevel == 0 {
+			if _, err := placement.NewPlan(dataNodeCount, spec.RedundancyLevel, spec.DataNode.Zones); err != nil {
+				errList = append(errList, field.Invalid(zonesPath, spec.DataNode.Zones, err.Error()))
+			}
+		}
+	}
+
 	// check if total number of nodes are not more than the allowed maximum
-	total := managementNodeCount + dataNodeCount + mysqlServerCount + numOfFreeApiSlots
+	total := managementNodeCount + dataNodeCount + mysqlServerCount + numOfFreeApiSlots + numOfNdbAPIApplicationSlots
 	if total > constants.MaxNumberOfNodes {
 		invalidValue := fmt.Sprintf(
-			"%d (= %d management, %d data, %d mysql nodes and %d free API nodes)",
-			total, managementNodeCount, dataNodeCount, mysqlServerCount, numOfFreeApiSlots)
+			"%d (= %d management, %d data, %d mysql nodes, %d free API nodes and %d NDBAPI application nodes)",
+			total, managementNodeCount, dataNodeCount, mysqlServerCount, numOfFreeApiSlots, numOfNdbAPIApplicationSlots)
 		msg := fmt.Sprintf(
 			"Total number of MySQL Cluster nodes should not exceed the allowed maximum of %d", constants.MaxNumberOfNodes)
 		errList = append(errList, field.Invalid(field.NewPath("Total Nodes"), invalidValue, msg))
 	}
 
+	// check if the NDBAPI applications have unique names and valid namespaces
+	ndbAPIApplicationsPath := specPath.Child("ndbAPIApplications")
+	var ndbAPIApplicationNames []string
+	for i, app := range spec.NdbAPIApplications {
+		ndbAPIApplicationNames = append(ndbAPIApplicationNames, app.Name)
+		for _, err := range validation.IsDNS1123Label(app.Namespace) {
+			errList = append(errList, field.Invalid(ndbAPIApplicationsPath.Index(i).Child("namespace"), app.Namespace, err))
+		}
+	}
+	errList = append(errList, validateNames(ndbAPIApplicationNames, ndbAPIApplicationsPath)...)
+
+	// check if the nodeIds of the API sections, which start after the operator's
+	// dedicated API node, do not exceed the maximum nodeId. The limit when an
+	// arbitrator is run is verified along with the arbitrator.
+	if !nc.HasArbitrator() {
+		numOfAPISections := nc.getNumOfAPISections()
+		maxNumOfAPISections := int32(constants.MaxNumberOfNodes - constants.NdbNodeTypeAPIStartNodeId)
+		if numOfAPISections > maxNumOfAPISections {
+			errList = append(errList, field.Invalid(specPath, numOfAPISections, fmt.Sprintf(
+				"the MySQL Servers, the free API slots and the NDBAPI applications, including their connection pools, "+
+					"require %d API sections, but only %d are available", numOfAPISections, maxNumOfAPISections)))
+		}
+	}
+
+	// check if the nodeIds allocated to the MySQL Server groups do not exceed
+	// the maximum nodeId, as the free nodeIds might not be successive
+	for i, serverGroupNodeIds := range nc.GetMySQLServerGroupNodeIds() {
+		lastNodeId := serverGroupNodeIds.StartNodeId + serverGroupNodeIds.NumOfNodeIds - 1
+		if lastNodeId >= constants.MaxNumberOfNodes {
+			errList = append(errList, field.Invalid(specPath.Child("mysqlNode", "serverGroups").Index(i),
+				serverGroupNodeIds.Name, fmt.Sprintf(
+					"no range of %d successive free nodeIds is available for the MySQL Server group",
+					serverGroupNodeIds.NumOfNodeIds)))
+		}
+	}
+
+	// check if the number of Management nodes is supported
+	if managementNodeCount < 1 || managementNodeCount > 2 {
+		msg := "spec.managementNode.nodeCount should be either 1 or 2"
+		errList = append(errList, field.Invalid(managementNodePath.Child("nodeCount"), managementNodeCount, msg))
+	}
+
+	// check if the Management and the Data nodes running in the host network
+	// use different ports, as they are allowed to share a worker node
+	if nc.UsesHostNetwork(constants.NdbNodeTypeMgmd) && nc.UsesHostNetwork(constants.NdbNodeTypeNdbmtd) &&
+		nc.GetManagementNodePort() == nc.GetDataNodeServerPort() {
+		msg := "spec.dataNode.serverPort should be different from the port of the Management nodes" +
+			" when both the Management and the Data nodes use the host network"
+		errList = append(errList, field.Invalid(dataNodePath.Child("serverPort"), nc.GetDataNodeServerPort(), msg))
+	}
+
+	// check if the image pull secret names have the expected format
+	for i, secret := range spec.ImagePullSecrets {
+		secretPath := specPath.Child("imagePullSecrets").Index(i).Child("name")
+		for _, err := range validation.IsDNS1123Subdomain(secret.Name) {
+			errList = append(errList, field.Invalid(secretPath, secret.Name, err))
+		}
+	}
+
+	// check if the custom labels and annotations are valid
+	errList = append(errList, validateCustomMetadata(
+		spec.PodLabels, spec.PodAnnotations, spec.ServiceAnnotations, specPath)...)
+	errList = append(errList, validateCustomMetadata(
+		spec.DataNode.PodLabels, spec.DataNode.PodAnnotations, spec.DataNode.ServiceAnnotations, dataNodePath)...)
+
+	// check if the additional containers and volumes are valid
+	errList = append(errList, validateNdbPodSpecExtensions(spec.DataNode.NdbPodSpec, dataNodePath.Child("ndbPodSpec"))...)
+	if spec.ManagementNode != nil {
+		errList = append(errList, validateNdbPodSpecExtensions(
+			spec.ManagementNode.NdbPodSpec, managementNodePath.Child("ndbPodSpec"))...)
+	}
+	if spec.MysqlNode != nil {
+		errList = append(errList, validateNdbPodSpecExtensions(spec.MysqlNode.NdbPodSpec, mysqldPath.Child("ndbPodSpec"))...)
+	}
+
+	// check if the Disk Data objects are valid
+	var undoFilesPVCSpec *corev1.PersistentVolumeClaimSpec
+	if spec.DataNode.SeparateVolumes != nil {
+		undoFilesPVCSpec = spec.DataNode.SeparateVolumes.UndoFiles
+	}
+	errList = append(errList, validateDiskDataSpec(
+		spec.DataNode.DiskData, spec.DataNode.PVCSpec, undoFilesPVCSpec, dataNodePath.Child("diskData"))...)
+
 	// check if there are any disallowed config params in dataNode's Configuration.
 	if err := validateConfigParams(nc.Spec.DataNode.Config, dataNodePath.Child("config")); err != nil {
 		errList = append(errList, err...)
 	}
 
+	// check if the data node log levels are specified only once
+	var logLevelConfigs []string
+	for configKey := range nc.GetDataNodeLogLevelConfigs() {
+		logLevelConfigs = append(logLevelConfigs, configKey)
+	}
+	errList = append(errList, validateConfigParamsNotSetBySpec(nc.Spec.DataNode.Config,
+		logLevelConfigs, dataNodePath.Child("config"), dataNodePath.Child("logLevels"))...)
+
+	// check if the thread config of the data nodes is valid
+	if threadConfig := nc.Spec.DataNode.ThreadConfig; threadConfig != "" {
+		threadConfigPath := dataNodePath.Child("threadConfig")
+		if nc.Spec.DataNode.UseNdbd {
+			errList = append(errList, field.Forbidden(threadConfigPath,
+				"threadConfig cannot be specified when spec.dataNode.useNdbd is enabled"))
+		}
+		errList = append(errList, validateThreadConfig(threadConfig, threadConfigPath)...)
+		errList = append(errList, validateConfigParamsNotSetBySpec(nc.Spec.DataNode.Config,
+			[]string{"ThreadConfig"}, dataNodePath.Child("config"), threadConfigPath)...)
+	}
+
+	// check if the memory related configs of the data nodes are specified only once
+	if nc.Spec.DataNode.LockPagesInMainMemory != nil {
+		errList = append(errList, validateConfigParamsNotSetBySpec(nc.Spec.DataNode.Config,
+			[]string{"LockPagesInMainMemory"}, dataNodePath.Child("config"), dataNodePath.Child("lockPagesInMainMemory"))...)
+	}
+	if nc.Spec.DataNode.Numa != nil {
+		errList = append(errList, validateConfigParamsNotSetBySpec(nc.Spec.DataNode.Config,
+			[]string{"Numa"}, dataNodePath.Child("config"), dataNodePath.Child("numa"))...)
+	}
+
+	// check if the config params set via the separate volumes are specified only once
+	if separateVolumes := nc.Spec.DataNode.SeparateVolumes; separateVolumes != nil {
+		separateVolumesPath := dataNodePath.Child("separateVolumes")
+		for _, volume := range []struct {
+			pvcSpec      *corev1.PersistentVolumeClaimSpec
+			name         string
+			configParams []string
+		}{
+			{separateVolumes.FileSystem, "fileSystem", []string{"FileSystemPath", "FileSystemPathDataFiles"}},
+			{separateVolumes.Backup, "backup", []string{"BackupDataDir"}},
+			{separateVolumes.UndoFiles, "undoFiles", []string{"FileSystemPathUndoFiles"}},
+		} {
+			if volume.pvcSpec != nil {
+				errList = append(errList, validateConfigParamsNotSetBySpec(nc.Spec.DataNode.Config,
+					volume.configParams, dataNodePath.Child("config"), separateVolumesPath.Child(volume.name))...)
+			}
+		}
+	}
+
+	// check if the backup taken before a system restart is stored in a separate volume,
+	// as the data volumes are deleted by an initial system restart
+	if systemRestart := nc.Spec.DataNode.SystemRestart; systemRestart != nil && systemRestart.BackupBeforeRestart &&
+		(nc.Spec.DataNode.SeparateVolumes == nil || nc.Spec.DataNode.SeparateVolumes.Backup == nil) {
+		errList = append(errList, field.Required(dataNodePath.Child("separateVolumes", "backup"),
+			"spec.dataNode.separateVolumes.backup should be specified when "+
+				"spec.dataNode.systemRestart.backupBeforeRestart is enabled"))
+	}
+
+	// check if the huge pages spec of the data nodes is valid
+	errList = append(errList, validateHugePages(nc.Spec.DataNode, dataNodePath)...)
+
+	// check if the data nodes use PVCs when the local volumes handling is enabled
+	if nc.Spec.DataNode.LocalVolumes != nil && nc.Spec.DataNode.PVCSpec == nil {
+		errList = append(errList, field.Required(dataNodePath.Child("pvcSpec"),
+			"spec.dataNode.pvcSpec should be specified when spec.dataNode.localVolumes is specified"))
+	}
+
+	// check if the data nodes store the backups in a PVC when their verification is enabled
+	if nc.Spec.VerifyBackups && nc.Spec.DataNode.PVCSpec == nil &&
+		(nc.Spec.DataNode.SeparateVolumes == nil || nc.Spec.DataNode.SeparateVolumes.Backup == nil) {
+		errList = append(errList, field.Required(dataNodePath.Child("pvcSpec"),
+			"spec.dataNode.pvcSpec or spec.dataNode.separateVolumes.backup should be specified when "+
+				"spec.verifyBackups is enabled"))
+	}
+
 	// check if there are any disallowed config params in managementNode Config.
 	if nc.Spec.ManagementNode != nil {
 		if err := validateConfigParams(nc.Spec.ManagementNode.Config, managementNodePath.Child("config")); err != nil {
 			errList = append(errList, err...)
 		}
+
+		// check if the cluster log destination is specified only once
+		if nc.Spec.ManagementNode.LogDestination != "" {
+			errList = append(errList, validateConfigParamsNotSetBySpec(nc.Spec.ManagementNode.Config,
+				[]string{"LogDestination"}, managementNodePath.Child("config"), managementNodePath.Child("logDestination"))...)
+		}
+
+		// check if the PDB spec of the management nodes is valid
+		errList = append(errList, validatePodDisruptionBudgetSpec(
+			nc.Spec.ManagementNode.PodDisruptionBudget, managementNodePath.Child("podDisruptionBudget"))...)
+
+		// check if the custom labels and annotations of the management nodes are valid
+		mgmdSpec := nc.Spec.ManagementNode
+		errList = append(errList, validateCustomMetadata(
+			mgmdSpec.PodLabels, mgmdSpec.PodAnnotations, mgmdSpec.ServiceAnnotations, managementNodePath)...)
+
+		// check if the load balancer configuration is valid
+		errList = append(errList, validateLoadBalancerSpec(
+			mgmdSpec.LoadBalancer, managementNodePath.Child("loadBalancer"))...)
+
+		// check if the update strategy of the management nodes is valid
+		errList = append(errList, validateUpdateStrategy(
+			mgmdSpec.UpdateStrategy, managementNodePath.Child("updateStrategy"))...)
 	}
 
 	// check if the MySQL root password secret name has the expected format
@@ -113,6 +804,33 @@ func (nc *NdbCluster) HasValidSpec() (bool, field.ErrorList) {
 			}
 		}
 
+		// check if the ndb operator password secret name has the expected format
+		if operatorPasswordSecret := mysqldSpec.NdbOperatorPasswordSecretName; operatorPasswordSecret != "" {
+			operatorPasswordSecretPath := mysqldPath.Child("ndbOperatorPasswordSecretName")
+			for _, err := range validation.IsDNS1123Subdomain(operatorPasswordSecret) {
+				errList = append(errList,
+					field.Invalid(operatorPasswordSecretPath, operatorPasswordSecret, err))
+			}
+			if operatorPasswordSecret == rootPasswordSecret {
+				errList = append(errList, field.Invalid(operatorPasswordSecretPath, operatorPasswordSecret,
+					"ndb operator password and root password cannot be stored in the same Secret"))
+			}
+		}
+
+		// check if the SecretProviderClass name has the expected format
+		if secretProviderClass := mysqldSpec.SecretProviderClass; secretProviderClass != "" {
+			secretProviderClassPath := mysqldPath.Child("secretProviderClass")
+			for _, err := range validation.IsDNS1123Subdomain(secretProviderClass) {
+				errList = append(errList,
+					field.Invalid(secretProviderClassPath, secretProviderClass, err))
+			}
+			if rootPasswordSecret == "" {
+				errList = append(errList, field.Required(mysqldPath.Child("rootPasswordSecretName"),
+					"spec.mysqlNode.rootPasswordSecretName should be specified when "+
+						"spec.mysqlNode.secretProviderClass is specified"))
+			}
+		}
+
 		// check if maxNodeCount is less than nodeCount
 		if mysqldSpec.MaxNodeCount != 0 &&
 			mysqldSpec.MaxNodeCount < mysqldSpec.NodeCount {
@@ -121,28 +839,383 @@ func (nc *NdbCluster) HasValidSpec() (bool, field.ErrorList) {
 			errList = append(errList,
 				field.Invalid(mysqldPath.Child("maxNodeCount"), mysqldSpec.MaxNodeCount, msg))
 		}
+
+		// check if the PDB spec of the MySQL Servers is valid
+		errList = append(errList, validatePodDisruptionBudgetSpec(
+			mysqldSpec.PodDisruptionBudget, mysqldPath.Child("podDisruptionBudget"))...)
+
+		// check if the custom labels and annotations of the MySQL Servers are valid
+		errList = append(errList, validateCustomMetadata(
+			mysqldSpec.PodLabels, mysqldSpec.PodAnnotations, mysqldSpec.ServiceAnnotations, mysqldPath)...)
+
+		// check if the load balancer configuration of the MySQL Servers is valid
+		errList = append(errList, validateLoadBalancerSpec(
+			mysqldSpec.LoadBalancer, mysqldPath.Child("loadBalancer"))...)
+
+		// check if the Gateway API exposure of the MySQL Servers is valid
+		if gateway := mysqldSpec.Gateway; gateway != nil {
+			gatewayPath := mysqldPath.Child("gateway")
+			if mysqldSpec.EnableLoadBalancer {
+				errList = append(errList, field.Forbidden(gatewayPath,
+					"spec.mysqlNode.gateway cannot be specified along with spec.mysqlNode.enableLoadBalancer"))
+			}
+			errList = append(errList, validateGatewaySpec(gateway, gatewayPath)...)
+		}
+
+		// check if the update strategy of the MySQL Servers is valid
+		errList = append(errList, validateUpdateStrategy(
+			mysqldSpec.UpdateStrategy, mysqldPath.Child("updateStrategy"))...)
+
+		// check if the canary rollout spec is valid
+		if canaryRollout := mysqldSpec.CanaryRollout; canaryRollout != nil {
+			canaryRolloutPath := mysqldPath.Child("canaryRollout")
+			if mysqldSpec.UpdateStrategy != nil {
+				errList = append(errList, field.Forbidden(canaryRolloutPath,
+					"spec.mysqlNode.canaryRollout cannot be specified along with spec.mysqlNode.updateStrategy"))
+			}
+			for i, query := range canaryRollout.HealthCheckQueries {
+				if strings.TrimSpace(query) == "" {
+					errList = append(errList, field.Required(
+						canaryRolloutPath.Child("healthCheckQueries").Index(i), "health check query cannot be empty"))
+				}
+			}
+		}
+
+		// check if the root password is provided by the user when a password
+		// policy is enforced, as the generated root password might not satisfy it
+		if passwordValidation := mysqldSpec.PasswordValidation; passwordValidation != nil &&
+			passwordValidation.Policy != "LOW" && rootPasswordSecret == "" {
+			errList = append(errList, field.Required(mysqldPath.Child("rootPasswordSecretName"),
+				"spec.mysqlNode.rootPasswordSecretName should be specified when "+
+					"spec.mysqlNode.passwordValidation enforces a MEDIUM or STRONG policy"))
+		}
+
+		// check if the autoscaler limits are within the reserved API slots
+		if autoscaling := mysqldSpec.Autoscaling; autoscaling != nil {
+			autoscalingPath := mysqldPath.Child("autoscaling")
+			maxNodeCount := nc.GetMySQLServerMaxNodeCount()
+			if autoscaling.MaxNodeCount > maxNodeCount {
+				msg := fmt.Sprintf(
+					"spec.mysqlNode.autoscaling.maxNodeCount cannot exceed spec.mysqlNode.maxNodeCount(=%d)", maxNodeCount)
+				errList = append(errList,
+					field.Invalid(autoscalingPath.Child("maxNodeCount"), autoscaling.MaxNodeCount, msg))
+			}
+
+			if autoscaling.MaxNodeCount != 0 && autoscaling.MinNodeCount > autoscaling.MaxNodeCount {
+				msg := "spec.mysqlNode.autoscaling.minNodeCount cannot exceed spec.mysqlNode.autoscaling.maxNodeCount"
+				errList = append(errList,
+					field.Invalid(autoscalingPath.Child("minNodeCount"), autoscaling.MinNodeCount, msg))
+			} else if autoscaling.MinNodeCount > maxNodeCount {
+				msg := fmt.Sprintf(
+					"spec.mysqlNode.autoscaling.minNodeCount cannot exceed spec.mysqlNode.maxNodeCount(=%d)", maxNodeCount)
+				errList = append(errList,
+					field.Invalid(autoscalingPath.Child("minNodeCount"), autoscaling.MinNodeCount, msg))
+			}
+		}
+	}
+
+	// check if a free API slot is available to restore the backup
+	if spec.InitFromBackup != nil && spec.FreeAPISlots < 1 {
+		errList = append(errList, field.Invalid(specPath.Child("freeAPISlots"), spec.FreeAPISlots,
+			"spec.freeAPISlots should be atleast 1 to restore the backup specified in spec.initFromBackup"))
+	}
+	if spec.InitFromBackup != nil {
+		errList = append(errList, validateInitFromBackupSpec(spec.InitFromBackup, specPath.Child("initFromBackup"))...)
+	}
+
+	// check if a MySQL Server is available to load the dump
+	if spec.InitFromDump != nil && nc.GetMySQLServerNodeCount() == 0 {
+		errList = append(errList, field.Invalid(mysqldPath.Child("nodeCount"), nc.GetMySQLServerNodeCount(),
+			"spec.mysqlNode.nodeCount should be atleast 1 to load the dump specified in spec.initFromDump"))
+	}
+
+	// check if the IP families are valid
+	errList = append(errList, validateIPFamilies(spec.IPFamilyPolicy, spec.IPFamilies, specPath)...)
+
+	// check if the location domain topology key is a valid label key
+	if spec.LocationDomainTopologyKey != "" {
+		for _, err := range validation.IsQualifiedName(spec.LocationDomainTopologyKey) {
+			errList = append(errList, field.Invalid(
+				specPath.Child("locationDomainTopologyKey"), spec.LocationDomainTopologyKey, err))
+		}
+	}
+
+	// check if the ServiceAccount of the MySQL Cluster pods is valid
+	if spec.ServiceAccount != nil {
+		errList = append(errList, validateServiceAccountSpec(spec.ServiceAccount, specPath.Child("serviceAccount"))...)
+	}
+
+	// check if the arbitrator can be run
+	if spec.Arbitrator != nil {
+		errList = append(errList, validateArbitrator(nc, specPath.Child("arbitrator"))...)
 	}
 
 	// check if any passed my.cnf has proper format
-	myCnfString := nc.GetMySQLCnf()
-	if len(myCnfString) > 0 {
-		myCnf, err := configparser.ParseString(myCnfString)
-		if err != nil {
-			// error parsing the cnf
-			errList = append(errList,
-				field.Invalid(mysqldPath.Child("myCnf"), myCnfString, err.Error()))
+	errList = append(errList, validateMyCnf(nc.GetMySQLCnf(), mysqldPath.Child("myCnf"))...)
+
+	// check if the extra arguments and the plugins of the MySQL Servers are valid
+	if spec.MysqlNode != nil {
+		errList = append(errList, validateMySQLServerExtraArgs(spec.MysqlNode.ExtraArgs, mysqldPath.Child("extraArgs"))...)
+		errList = append(errList, validateMySQLServerPlugins(spec.MysqlNode, mysqldPath.Child("plugins"))...)
+		errList = append(errList, validateMySQLServerAuditLog(spec.MysqlNode, mysqldPath.Child("auditLog"))...)
+	}
+
+	// check if the MySQL Server groups have unique names and proper my.cnfs
+	serverGroupNames := make(map[string]bool)
+	for i, serverGroup := range nc.GetMySQLServerGroups() {
+		serverGroupPath := mysqldPath.Child("serverGroups").Index(i)
+		if serverGroupNames[serverGroup.Name] {
+			errList = append(errList, field.Duplicate(serverGroupPath.Child("name"), serverGroup.Name))
+		}
+		serverGroupNames[serverGroup.Name] = true
+		errList = append(errList, validateMyCnf(serverGroup.GetMySQLCnf(), serverGroupPath.Child("myCnf"))...)
+	}
+
+	return errList == nil, errList
+}
+
+// validateArbitrator validates the arbitrator specified in the NdbCluster spec
+func validateArbitrator(nc *NdbCluster, arbitratorPath *field.Path) field.ErrorList {
+	var errList field.ErrorList
+	arbitrator := nc.Spec.Arbitrator
+
+	// check if the zone is a valid label value and is not used by the data nodes
+	if arbitrator.Zone != "" {
+		zonePath := arbitratorPath.Child("zone")
+		for _, err := range validation.IsValidLabelValue(arbitrator.Zone) {
+			errList = append(errList, field.Invalid(zonePath, arbitrator.Zone, err))
+		}
+		for _, zone := range nc.Spec.DataNode.Zones {
+			if zone == arbitrator.Zone {
+				errList = append(errList, field.Invalid(zonePath, arbitrator.Zone,
+					"the arbitrator should run in a zone not used by the data nodes"))
+			}
+		}
+	}
+
+	// check if the nodeIds of the API sections do not overlap the arbitrator's nodeId
+	numOfAPISections := nc.getNumOfAPISections()
+	maxNumOfAPISections := int32(constants.ArbitratorNodeId - constants.NdbNodeTypeAPIStartNodeId)
+	if numOfAPISections > maxNumOfAPISections {
+		errList = append(errList, field.Invalid(arbitratorPath, numOfAPISections, fmt.Sprintf(
+			"the MySQL Servers and the free API slots require %d API sections, "+
+				"but only %d are available when an arbitrator is run", numOfAPISections, maxNumOfAPISections)))
+	}
+
+	// check if the additional containers and volumes are valid
+	errList = append(errList, validateNdbPodSpecExtensions(arbitrator.NdbPodSpec, arbitratorPath.Child("ndbPodSpec"))...)
+
+	return errList
+}
+
+// validateIPFamilies validates the IP families and the IP family policy
+// specified for the Services created for the MySQL Cluster
+func validateIPFamilies(
+	ipFamilyPolicy *corev1.IPFamilyPolicy, ipFamilies []corev1.IPFamily, specPath *field.Path) (errList field.ErrorList) {
+	ipFamiliesPath := specPath.Child("ipFamilies")
+	seenIPFamilies := make(map[corev1.IPFamily]bool)
+	for i, ipFamily := range ipFamilies {
+		if ipFamily != corev1.IPv4Protocol && ipFamily != corev1.IPv6Protocol {
+			errList = append(errList, field.NotSupported(ipFamiliesPath.Index(i), ipFamily,
+				[]string{string(corev1.IPv4Protocol), string(corev1.IPv6Protocol)}))
+		} else if seenIPFamilies[ipFamily] {
+			errList = append(errList, field.Duplicate(ipFamiliesPath.Index(i), ipFamily))
+		}
+		seenIPFamilies[ipFamily] = true
+	}
+
+	if ipFamilyPolicy != nil && *ipFamilyPolicy == corev1.IPFamilyPolicySingleStack && len(ipFamilies) > 1 {
+		errList = append(errList, field.Invalid(ipFamiliesPath, ipFamilies,
+			"only one IP family can be specified when spec.ipFamilyPolicy is SingleStack"))
+	}
+
+	return errList
+}
+
+// validateMyCnf validates the given my.cnf specified for the MySQL Servers
+func validateMyCnf(myCnfString string, myCnfPath *field.Path) field.ErrorList {
+	if len(myCnfString) == 0 {
+		return nil
+	}
+
+	myCnf, err := configparser.ParseString(myCnfString)
+	if err != nil {
+		// error parsing the cnf
+		return field.ErrorList{field.Invalid(myCnfPath, myCnfString, err.Error())}
+	}
+
+	// accept only one mysqld section in the cnf
+	if len(myCnf) != 1 ||
+		myCnf.GetNumberOfSections("mysqld") != 1 {
+		return field.ErrorList{field.Invalid(myCnfPath,
+			myCnfString, myCnfPath.String()+" can have only one mysqld section")}
+	}
+
+	// Verify the options in a deterministic order. The unknown options
+	// are not rejected here, as they might be valid in the MySQL Server
+	// version being run, but are warned about by GetSpecWarnings.
+	var errList field.ErrorList
+	for _, option := range getMyCnfOptions(myCnf) {
+		if name, _ := normalizeMySQLServerOption(option); disallowedMySQLServerArgs[name] {
+			errList = append(errList, field.Forbidden(myCnfPath, fmt.Sprintf(
+				"option %q is not allowed in %s as it is configured by the Ndb Operator",
+				option, myCnfPath.String())))
+		}
+	}
+
+	return errList
+}
+
+// getMyCnfOptions returns the options in the
+// mysqld section of the given my.cnf, sorted
+func getMyCnfOptions(myCnf configparser.ConfigIni) []string {
+	var options []string
+	for option := range myCnf.GetSection("mysqld") {
+		options = append(options, strings.TrimSpace(option))
+	}
+	sort.Strings(options)
+	return options
+}
+
+// disallowedMySQLServerArgs are the mysqld options that are set by the
+// operator, and so, cannot be specified in spec.mysqlNode.extraArgs or
+// in the myCnf of the MySQL Servers.
+var disallowedMySQLServerArgs = map[string]bool{
+	"ndbcluster":                          true,
+	"ndb-connectstring":                   true,
+	"ndb-nodeid":                          true,
+	"ndb-cluster-connection-pool":         true,
+	"ndb-cluster-connection-pool-nodeids": true,
+	"user":                                true,
+	"datadir":                             true,
+	"defaults-file":                       true,
+	"defaults-extra-file":                 true,
+	"no-defaults":                         true,
+	"initialize":                          true,
+	"initialize-insecure":                 true,
+	"daemonize":                           true,
+	"plugin-dir":                          true,
+	"port":                                true,
+	"socket":                              true,
+}
+
+// validateMySQLServerExtraArgs validates the extra command-line arguments
+// specified for the MySQL Servers. Every argument should be a long option
+// and should not be one of the options set by the operator.
+func validateMySQLServerExtraArgs(extraArgs []string, extraArgsPath *field.Path) (errList field.ErrorList) {
+	for i, arg := range extraArgs {
+		if !strings.HasPrefix(arg, "--") || len(arg) == 2 {
+			errList = append(errList, field.Invalid(extraArgsPath.Index(i), arg,
+				"extra arguments should be long options of the form --option[=value]"))
+			continue
+		}
+
+		// Extract the option name, ignoring the modifiers
+		// and treating dashes and underscores alike.
+		option, _ := normalizeMySQLServerOption(strings.SplitN(arg[2:], "=", 2)[0])
+
+		if disallowedMySQLServerArgs[option] {
+			errList = append(errList, field.Forbidden(extraArgsPath.Index(i), fmt.Sprintf(
+				"option %q is not allowed in %s as it is configured by the Ndb Operator",
+				arg, extraArgsPath.String())))
+		}
+	}
+	return errList
+}
+
+// validateMySQLServerPlugins validates the custom plugins
+// and components specified for the MySQL Servers
+func validateMySQLServerPlugins(mysqldSpec *NdbMysqldSpec, pluginsPath *field.Path) (errList field.ErrorList) {
+	pluginsSpec := mysqldSpec.Plugins
+	if pluginsSpec == nil {
+		return nil
+	}
+
+	// The init containers share the pod with the ones in the ndbPodSpec
+	var initContainerNames []string
+	for _, container := range pluginsSpec.InitContainers {
+		initContainerNames = append(initContainerNames, container.Name)
+	}
+	errList = append(errList, validateNames(initContainerNames, pluginsPath.Child("initContainers"))...)
+	errList = append(errList, validateNotReserved(
+		initContainerNames, isReservedContainerName, pluginsPath.Child("initContainers"))...)
+	if mysqldSpec.NdbPodSpec != nil {
+		for i, name := range initContainerNames {
+			for _, container := range mysqldSpec.NdbPodSpec.InitContainers {
+				if name == container.Name {
+					errList = append(errList, field.Duplicate(
+						pluginsPath.Child("initContainers").Index(i).Child("name"), name))
+				}
+			}
+		}
+	}
+
+	seenPlugins := make(map[string]bool)
+	for i, plugin := range pluginsSpec.Plugins {
+		if name := strings.ToLower(plugin.Name); seenPlugins[name] {
+			errList = append(errList, field.Duplicate(pluginsPath.Child("plugins").Index(i).Child("name"), plugin.Name))
 		} else {
-			// accept only one mysqld section in the cnf
-			if len(myCnf) != 1 ||
-				myCnf.GetNumberOfSections("mysqld") != 1 {
-				errList = append(errList,
-					field.Invalid(mysqldPath.Child("myCnf"),
-						myCnfString, "spec.mysqlNode.myCnf can have only one mysqld section"))
+			seenPlugins[name] = true
+		}
+	}
+
+	seenComponents := make(map[string]bool)
+	for i, component := range pluginsSpec.Components {
+		componentPath := pluginsPath.Child("components").Index(i)
+		if !strings.HasPrefix(component, "file://") || len(component) == len("file://") ||
+			strings.ContainsAny(component, "'\"\\` ") {
+			errList = append(errList, field.Invalid(componentPath, component,
+				"component should be a URN of the form file://component_name"))
+		} else if seenComponents[component] {
+			errList = append(errList, field.Duplicate(componentPath, component))
+		}
+		seenComponents[component] = true
+	}
+
+	return errList
+}
+
+// minAuditLogRotateOnSize is the minimum size at which the audit log file can be rotated
+var minAuditLogRotateOnSize = resource.MustParse("4Ki")
+
+// validateMySQLServerAuditLog validates the audit log specified for the MySQL Servers
+func validateMySQLServerAuditLog(mysqldSpec *NdbMysqldSpec, auditLogPath *field.Path) (errList field.ErrorList) {
+	auditLog := mysqldSpec.AuditLog
+	if auditLog == nil {
+		return nil
+	}
+
+	if auditLog.RotateOnSize != nil && auditLog.RotateOnSize.Cmp(minAuditLogRotateOnSize) < 0 {
+		errList = append(errList, field.Invalid(auditLogPath.Child("rotateOnSize"), auditLog.RotateOnSize.String(),
+			fmt.Sprintf("should be at least %s", minAuditLogRotateOnSize.String())))
+	}
+
+	// The sidecar containers share the pod with the ones in the ndbPodSpec
+	var containerNames []string
+	for _, container := range auditLog.SidecarContainers {
+		containerNames = append(containerNames, container.Name)
+	}
+	sidecarContainersPath := auditLogPath.Child("sidecarContainers")
+	errList = append(errList, validateNames(containerNames, sidecarContainersPath)...)
+	errList = append(errList, validateNotReserved(containerNames, isReservedContainerName, sidecarContainersPath)...)
+	if mysqldSpec.NdbPodSpec != nil {
+		var ndbPodSpecContainerNames []string
+		for _, container := range mysqldSpec.NdbPodSpec.InitContainers {
+			ndbPodSpecContainerNames = append(ndbPodSpecContainerNames, container.Name)
+		}
+		for _, container := range mysqldSpec.NdbPodSpec.SidecarContainers {
+			ndbPodSpecContainerNames = append(ndbPodSpecContainerNames, container.Name)
+		}
+		for i, name := range containerNames {
+			for _, ndbPodSpecContainerName := range ndbPodSpecContainerNames {
+				if name == ndbPodSpecContainerName {
+					errList = append(errList, field.Duplicate(sidecarContainersPath.Index(i).Child("name"), name))
+				}
 			}
 		}
 	}
 
-	return errList == nil, errList
+	return errList
 }
 
 func cannotUpdateFieldError(specPath *field.Path, newValue interface{}) *field.Error {
@@ -169,6 +1242,117 @@ func validateNdbPodSpecResources(specPath *field.Path, oldNdbPodSpec, newNdbPodS
 	return nil
 }
 
+// validateDataNodePVCSpecUpdate validates the update of the data node PVC
+// spec. Only the storage request can be updated, and only increased, as
+// the existing PVCs can only be expanded.
+func validateDataNodePVCSpecUpdate(oldPVCSpec, newPVCSpec *corev1.PersistentVolumeClaimSpec, specPath *field.Path) field.ErrorList {
+	if oldPVCSpec == nil && newPVCSpec == nil {
+		return nil
+	}
+
+	if oldPVCSpec == nil || newPVCSpec == nil {
+		// PVCSpec added or removed
+		return field.ErrorList{cannotUpdateFieldError(specPath, newPVCSpec)}
+	}
+
+	// Verify that the storage request is not reduced
+	storagePath := specPath.Child("resources", "requests", string(corev1.ResourceStorage))
+	oldStorage := oldPVCSpec.Resources.Requests[corev1.ResourceStorage]
+	newStorage := newPVCSpec.Resources.Requests[corev1.ResourceStorage]
+	if newStorage.Cmp(oldStorage) < 0 {
+		return field.ErrorList{field.Invalid(storagePath, newStorage.String(),
+			fmt.Sprintf("%s cannot be reduced once NdbCluster has been created", storagePath.String()))}
+	}
+
+	// Verify that nothing other than the storage request has changed
+	oldPVCSpec, newPVCSpec = oldPVCSpec.DeepCopy(), newPVCSpec.DeepCopy()
+	for _, pvcSpec := range []*corev1.PersistentVolumeClaimSpec{oldPVCSpec, newPVCSpec} {
+		delete(pvcSpec.Resources.Requests, corev1.ResourceStorage)
+		if len(pvcSpec.Resources.Requests) == 0 {
+			pvcSpec.Resources.Requests = nil
+		}
+	}
+	if !reflect.DeepEqual(oldPVCSpec, newPVCSpec) {
+		return field.ErrorList{field.Forbidden(specPath, fmt.Sprintf(
+			"only %s can be updated once NdbCluster has been created", storagePath.String()))}
+	}
+
+	return nil
+}
+
+// validateReservedNodeIdsUpdate verifies that the config generated from the
+// new spec reserves the nodeIds of the existing nodes, as recorded in the
+// status of the NdbCluster, for the same nodes. The MySQL Servers whose pods
+// are being removed by the update are ignored, and the other MySQL Servers
+// can be moved to another nodeId reserved for a MySQL Server, as the operator
+// restarts them to use the new nodeIds. The nodeIds of the other nodes cannot
+// be changed without restarting the whole MySQL Cluster.
+func validateReservedNodeIdsUpdate(nc, newNc *NdbCluster, specPath *field.Path) (errList field.ErrorList) {
+	// The nodeIds of the MySQL Server groups in the new spec
+	// are allocated based on the ranges recorded in the status
+	newNcWithStatus := *newNc
+	newNcWithStatus.Status = nc.Status
+	newReservedNodeIds := newNcWithStatus.getReservedNodeIds()
+	newMySQLServerPods := newNc.getMySQLServerPodNames()
+	for _, node := range nc.Status.Nodes {
+		if node.NodeType == constants.NdbNodeTypeMySQLD && !newMySQLServerPods[node.PodName] {
+			// The MySQL Server is not part of the new spec
+			continue
+		}
+
+		newNode, reserved := newReservedNodeIds[node.NodeId]
+		switch {
+		case !reserved:
+			errList = append(errList, field.Invalid(specPath, node.NodeId, fmt.Sprintf(
+				"the update would not reserve the nodeId %d used by the %s node in the pod %q",
+				node.NodeId, node.NodeType, node.PodName)))
+		case newNode.NodeType != node.NodeType ||
+			(node.NodeType != constants.NdbNodeTypeMySQLD && newNode.PodName != node.PodName):
+			reservedFor := "a free API slot"
+			if newNode.PodName != "" {
+				reservedFor = fmt.Sprintf("the %s node in the pod %q", newNode.NodeType, newNode.PodName)
+			}
+			errList = append(errList, field.Invalid(specPath, node.NodeId, fmt.Sprintf(
+				"the update would reserve the nodeId %d used by the %s node in the pod %q for %s",
+				node.NodeId, node.NodeType, node.PodName, reservedFor)))
+		}
+	}
+
+	return errList
+}
+
+// validateNdbAPIApplicationsUpdate verifies that the new spec does not move
+// the pods of the existing NDBAPI applications to other nodeIds, as the
+// applications are not restarted by the operator to use the new nodeIds.
+// The nodeIds of the applications, and their pods, that are being removed
+// by the update are ignored.
+func validateNdbAPIApplicationsUpdate(nc, newNc *NdbCluster, appsPath *field.Path) (errList field.ErrorList) {
+	newAppHostnames := newNc.GetNdbAPIApplicationHostnames()
+	newAppPods := make(map[string]bool)
+	for _, hostname := range newAppHostnames {
+		newAppPods[hostname] = true
+	}
+
+	appHostnames := nc.GetNdbAPIApplicationHostnames()
+	var nodeIds []int
+	for nodeId := range appHostnames {
+		nodeIds = append(nodeIds, int(nodeId))
+	}
+	sort.Ints(nodeIds)
+
+	for _, nodeId := range nodeIds {
+		hostname := appHostnames[int32(nodeId)]
+		if newAppPods[hostname] && newAppHostnames[int32(nodeId)] != hostname {
+			errList = append(errList, field.Invalid(appsPath, nodeId, fmt.Sprintf(
+				"the update would move the NDBAPI application pod %q from the nodeId %d. "+
+					"Only the last application can be scaled and new applications can only be appended.",
+				hostname, nodeId)))
+		}
+	}
+
+	return errList
+}
+
 func (nc *NdbCluster) IsValidSpecUpdate(newNc *NdbCluster) (bool, field.ErrorList) {
 
 	var errList field.ErrorList
@@ -177,12 +1361,14 @@ func (nc *NdbCluster) IsValidSpecUpdate(newNc *NdbCluster) (bool, field.ErrorLis
 	dataNodePath := specPath.Child("dataNode")
 	mysqldPath := specPath.Child("mysqlNode")
 
-	if nc.Spec.RedundancyLevel == 1 {
-		// MySQL Cluster replica = 1 => updating MySQL config via
-		// rolling restart is not possible. Disallow any spec update.
+	if nc.Spec.RedundancyLevel == 1 && !newNc.IsDataNodeRestartAllowed(DataNodeSystemRestart) {
+		// MySQL Cluster replica = 1 => updating MySQL config via rolling
+		// restart is not possible. Disallow any spec update, unless the
+		// data nodes are allowed to be restarted via a system restart.
 		errList = append(errList,
 			field.InternalError(specPath,
-				errors.New("operator cannot handle any spec update to a MySQL Cluster whose replica is 1")))
+				errors.New("operator cannot handle any spec update to a MySQL Cluster whose replica is 1, "+
+					"unless spec.dataNode.systemRestart.allowed is enabled")))
 		return false, errList
 	}
 
@@ -193,10 +1379,26 @@ func (nc *NdbCluster) IsValidSpecUpdate(newNc *NdbCluster) (bool, field.ErrorLis
 				"spec.dataNode.nodeCount cannot be reduced once MySQL Cluster has been started"))
 	}
 
-	// Do not allow updating Spec.RedundancyLevel
+	// Allow updating Spec.RedundancyLevel only via the Recreate strategy, and
+	// only between the levels that run the same number of Management nodes
 	if nc.Spec.RedundancyLevel != newNc.Spec.RedundancyLevel {
-		errList = append(errList,
-			cannotUpdateFieldError(specPath.Child("redundancyLevel"), newNc.Spec.RedundancyLevel))
+		if newNc.Spec.RedundancyLevelUpdateStrategy != NdbRedundancyLevelUpdateRecreate {
+			errList = append(errList,
+				field.Invalid(specPath.Child("redundancyLevel"), newNc.Spec.RedundancyLevel,
+					"spec.redundancyLevel can be updated only if spec.redundancyLevelUpdateStrategy is Recreate"))
+		} else if nc.Spec.RedundancyLevel == 1 || newNc.Spec.RedundancyLevel == 1 {
+			errList = append(errList,
+				field.Invalid(specPath.Child("redundancyLevel"), newNc.Spec.RedundancyLevel,
+					"spec.redundancyLevel cannot be updated from or to 1"))
+		}
+	}
+
+	// Do not allow changing the logfile group, as MySQL
+	// Cluster supports only one logfile group at a time.
+	if nc.Spec.DataNode.DiskData != nil && newNc.Spec.DataNode.DiskData != nil &&
+		nc.Spec.DataNode.DiskData.LogfileGroup.Name != newNc.Spec.DataNode.DiskData.LogfileGroup.Name {
+		errList = append(errList, cannotUpdateFieldError(
+			dataNodePath.Child("diskData", "logfileGroup", "name"), newNc.Spec.DataNode.DiskData.LogfileGroup.Name))
 	}
 
 	// Do not allow updating Resource field of various ndbPodSpecs
@@ -219,6 +1421,139 @@ func (nc *NdbCluster) IsValidSpecUpdate(newNc *NdbCluster) (bool, field.ErrorLis
 		}
 	}
 
+	// Do not allow updating the podManagementPolicy as it
+	// is an immutable field of the StatefulSet spec
+	if nc.GetPodManagementPolicy(constants.NdbNodeTypeMgmd) != newNc.GetPodManagementPolicy(constants.NdbNodeTypeMgmd) {
+		errList = append(errList, cannotUpdateFieldError(managementNodePath.Child("podManagementPolicy"),
+			newNc.GetPodManagementPolicy(constants.NdbNodeTypeMgmd)))
+	}
+	if nc.GetPodManagementPolicy(constants.NdbNodeTypeNdbmtd) != newNc.GetPodManagementPolicy(constants.NdbNodeTypeNdbmtd) {
+		errList = append(errList, cannotUpdateFieldError(dataNodePath.Child("podManagementPolicy"),
+			newNc.GetPodManagementPolicy(constants.NdbNodeTypeNdbmtd)))
+	}
+	if nc.GetMySQLServerNodeCount() != 0 && newNc.GetMySQLServerNodeCount() != 0 &&
+		nc.GetPodManagementPolicy(constants.NdbNodeTypeMySQLD) != newNc.GetPodManagementPolicy(constants.NdbNodeTypeMySQLD) {
+		errList = append(errList, cannotUpdateFieldError(mysqldPath.Child("podManagementPolicy"),
+			newNc.GetPodManagementPolicy(constants.NdbNodeTypeMySQLD)))
+	}
+
+	// Do not allow moving the nodes in or out of the host network, as
+	// that changes the ports and the scheduling constraints of the nodes
+	if nc.UsesHostNetwork(constants.NdbNodeTypeMgmd) != newNc.UsesHostNetwork(constants.NdbNodeTypeMgmd) {
+		errList = append(errList, cannotUpdateFieldError(managementNodePath.Child("hostNetwork"),
+			newNc.UsesHostNetwork(constants.NdbNodeTypeMgmd)))
+	}
+	if nc.UsesHostNetwork(constants.NdbNodeTypeNdbmtd) != newNc.UsesHostNetwork(constants.NdbNodeTypeNdbmtd) {
+		errList = append(errList, cannotUpdateFieldError(dataNodePath.Child("hostNetwork"),
+			newNc.UsesHostNetwork(constants.NdbNodeTypeNdbmtd)))
+	}
+	if nc.UsesHostNetwork(constants.NdbNodeTypeMySQLD) != newNc.UsesHostNetwork(constants.NdbNodeTypeMySQLD) {
+		errList = append(errList, cannotUpdateFieldError(mysqldPath.Child("hostNetwork"),
+			newNc.UsesHostNetwork(constants.NdbNodeTypeMySQLD)))
+	}
+
+	// Do not allow changing the number of Management nodes, as
+	// that changes the nodeIds of all the other MySQL Cluster nodes
+	if nc.GetManagementNodeCount() != newNc.GetManagementNodeCount() {
+		errList = append(errList, cannotUpdateFieldError(managementNodePath.Child("nodeCount"),
+			newNc.GetManagementNodeCount()))
+	}
+
+	// Do not allow changing the ports, as that requires
+	// all the MySQL Cluster nodes to be restarted
+	if nc.GetManagementNodePort() != newNc.GetManagementNodePort() {
+		errList = append(errList, cannotUpdateFieldError(managementNodePath.Child("port"),
+			newNc.GetManagementNodePort()))
+	}
+	if nc.GetDataNodeServerPort() != newNc.GetDataNodeServerPort() {
+		errList = append(errList, cannotUpdateFieldError(dataNodePath.Child("serverPort"),
+			newNc.GetDataNodeServerPort()))
+	}
+	if nc.GetMySQLServerPort() != newNc.GetMySQLServerPort() {
+		errList = append(errList, cannotUpdateFieldError(mysqldPath.Child("port"),
+			newNc.GetMySQLServerPort()))
+	}
+
+	// Do not allow adding or removing the arbitrator, as that
+	// requires all the MySQL Cluster nodes to be restarted
+	if nc.HasArbitrator() != newNc.HasArbitrator() {
+		errList = append(errList, cannotUpdateFieldError(specPath.Child("arbitrator"), newNc.Spec.Arbitrator))
+	}
+
+	// Allow only increasing the storage requested for the data nodes
+	errList = append(errList, validateDataNodePVCSpecUpdate(
+		nc.Spec.DataNode.PVCSpec, newNc.Spec.DataNode.PVCSpec, dataNodePath.Child("pvcSpec"))...)
+
+	// Do not allow updating the separate volumes of the data nodes,
+	// as the files already stored in them will not be moved
+	if !reflect.DeepEqual(nc.Spec.DataNode.SeparateVolumes, newNc.Spec.DataNode.SeparateVolumes) {
+		errList = append(errList, cannotUpdateFieldError(
+			dataNodePath.Child("separateVolumes"), newNc.Spec.DataNode.SeparateVolumes))
+	}
+
+	// Do not allow updating the PVCs of the audit log, as
+	// the VolumeClaimTemplates of a StatefulSet are immutable
+	var auditLogPVCSpec, newAuditLogPVCSpec *corev1.PersistentVolumeClaimSpec
+	if nc.Spec.MysqlNode != nil && nc.Spec.MysqlNode.AuditLog != nil {
+		auditLogPVCSpec = nc.Spec.MysqlNode.AuditLog.PVCSpec
+	}
+	if newNc.Spec.MysqlNode != nil && newNc.Spec.MysqlNode.AuditLog != nil {
+		newAuditLogPVCSpec = newNc.Spec.MysqlNode.AuditLog.PVCSpec
+	}
+	if !reflect.DeepEqual(auditLogPVCSpec, newAuditLogPVCSpec) {
+		errList = append(errList, cannotUpdateFieldError(
+			mysqldPath.Child("auditLog", "pvcSpec"), newAuditLogPVCSpec))
+	}
+
+	// Do not allow updating the zones of the data nodes,
+	// as the placed data nodes will not be moved
+	if !reflect.DeepEqual(nc.Spec.DataNode.Zones, newNc.Spec.DataNode.Zones) {
+		errList = append(errList, cannotUpdateFieldError(dataNodePath.Child("zones"), newNc.Spec.DataNode.Zones))
+	}
+
+	// Do not allow updating the backup to be restored, as
+	// it is restored only when the NdbCluster is created
+	if !reflect.DeepEqual(nc.Spec.InitFromBackup, newNc.Spec.InitFromBackup) {
+		errList = append(errList, cannotUpdateFieldError(specPath.Child("initFromBackup"), newNc.Spec.InitFromBackup))
+	}
+
+	// Do not allow updating the dump to be loaded, as
+	// it is loaded only when the NdbCluster is created
+	if !reflect.DeepEqual(nc.Spec.InitFromDump, newNc.Spec.InitFromDump) {
+		errList = append(errList, cannotUpdateFieldError(specPath.Child("initFromDump"), newNc.Spec.InitFromDump))
+	}
+
+	// Do not allow updating the loadBalancerClass, as
+	// it is an immutable field of the Service spec
+	for _, node := range []struct {
+		nodeType constants.NdbNodeType
+		path     *field.Path
+	}{
+		{constants.NdbNodeTypeMgmd, managementNodePath},
+		{constants.NdbNodeTypeMySQLD, mysqldPath},
+	} {
+		var oldClass, newClass *string
+		if lbSpec := nc.GetLoadBalancerSpec(node.nodeType); lbSpec != nil {
+			oldClass = lbSpec.LoadBalancerClass
+		}
+		if lbSpec := newNc.GetLoadBalancerSpec(node.nodeType); lbSpec != nil {
+			newClass = lbSpec.LoadBalancerClass
+		}
+		if !reflect.DeepEqual(oldClass, newClass) {
+			errList = append(errList, cannotUpdateFieldError(
+				node.path.Child("loadBalancer", "loadBalancerClass"), newClass))
+		}
+	}
+
+	// Do not allow updating the IP families of the Services,
+	// as the MySQL Cluster config depends on them
+	if !reflect.DeepEqual(nc.Spec.IPFamilyPolicy, newNc.Spec.IPFamilyPolicy) {
+		errList = append(errList, cannotUpdateFieldError(specPath.Child("ipFamilyPolicy"), newNc.Spec.IPFamilyPolicy))
+	}
+	if !reflect.DeepEqual(nc.Spec.IPFamilies, newNc.Spec.IPFamilies) {
+		errList = append(errList, cannotUpdateFieldError(specPath.Child("ipFamilies"), newNc.Spec.IPFamilies))
+	}
+
 	if nc.GetMySQLServerConnectionPoolSize() > newNc.GetMySQLServerConnectionPoolSize() {
 		// Do not allow reducing connection pool size as that leads to chaos when reserving nodeIds
 		errList = append(errList,
@@ -227,6 +1562,10 @@ func (nc *NdbCluster) IsValidSpecUpdate(newNc *NdbCluster) (bool, field.ErrorLis
 				"connectionPoolSize cannot be reduced once MySQL Cluster has been started"))
 	}
 
+	// Do not allow the nodeIds of the existing nodes to be reserved for other nodes
+	errList = append(errList, validateReservedNodeIdsUpdate(nc, newNc, specPath)...)
+	errList = append(errList, validateNdbAPIApplicationsUpdate(nc, newNc, specPath.Child("ndbAPIApplications"))...)
+
 	// Check if the new NdbCluster valid is spec
 	if isValid, specErrList := newNc.HasValidSpec(); !isValid {
 		errList = append(errList, specErrList...)
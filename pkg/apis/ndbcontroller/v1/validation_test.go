@@ -1,4 +1,4 @@
-// Copyright (c) 2021, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2021, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -8,8 +8,14 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/mysql/ndb-operator/pkg/constants"
+
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -55,80 +61,1043 @@ func mysqldRootPasswordSecretNameTests(secretName string, fail bool, short strin
 	}
 }
 
-func ndbUpdateTests(redundancy, dnc, mysqldCount,
-	oldRedundancy, oldDnc, oldMysqldCount int32,
+func mysqldPasswordSecretsTests(rootSecretName, operatorSecretName, secretProviderClass string,
 	fail bool, short string) *validationCase {
 	return &validationCase{
 		spec: &NdbClusterSpec{
-			RedundancyLevel: redundancy,
+			RedundancyLevel: 1,
 			DataNode: &NdbDataNodeSpec{
-				NodeCount: dnc,
+				NodeCount: 1,
 			},
 			MysqlNode: &NdbMysqldSpec{
-				NodeCount: mysqldCount,
+				NodeCount:                     1,
+				RootPasswordSecretName:        rootSecretName,
+				NdbOperatorPasswordSecretName: operatorSecretName,
+				SecretProviderClass:           secretProviderClass,
 			},
 		},
-		oldSpec: &NdbClusterSpec{
-			RedundancyLevel: oldRedundancy,
+		shouldFail: fail,
+		explain: fmt.Sprintf("RootPasswordSecretName : '%s', NdbOperatorPasswordSecretName : '%s', "+
+			"SecretProviderClass : '%s' - %s", rootSecretName, operatorSecretName, secretProviderClass, short),
+	}
+}
+
+func mysqldServerGroupTests(serverGroups []NdbMysqldServerGroupSpec, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
 			DataNode: &NdbDataNodeSpec{
-				NodeCount: oldDnc,
+				NodeCount: 2,
 			},
 			MysqlNode: &NdbMysqldSpec{
-				NodeCount: oldMysqldCount,
+				NodeCount:    2,
+				MaxNodeCount: 2,
+				ServerGroups: serverGroups,
 			},
 		},
 		shouldFail: fail,
-		explain:    short,
+		explain:    fmt.Sprintf("MySQL Server groups : %v - %s", serverGroups, short),
 	}
 }
 
-func ndbUpdateNdbPodSpecTests(
-	oldNdbClusterSpec func(defaultSpec *NdbClusterSpec),
-	newNdbClusterSpec func(defaultSpec *NdbClusterSpec),
+func mysqldExtraArgsTests(extraArgs []string, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			MysqlNode: &NdbMysqldSpec{
+				NodeCount: 2,
+				ExtraArgs: extraArgs,
+			},
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("MySQL Server extra args : %v - %s", extraArgs, short),
+	}
+}
+
+func initFromBackupTests(initFromBackup *NdbClusterInitFromBackupSpec, fail bool, short string) *validationCase {
+	initFromBackup.BackupID = 1
+	initFromBackup.PersistentVolumeClaimName = "backups"
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			FreeAPISlots:   1,
+			InitFromBackup: initFromBackup,
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("init from backup : %+v - %s", *initFromBackup, short),
+	}
+}
+
+func mysqldMyCnfTests(myCnf string, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			MysqlNode: &NdbMysqldSpec{
+				NodeCount: 2,
+				MyCnf:     myCnf,
+			},
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("MySQL Server my.cnf : %q - %s", myCnf, short),
+	}
+}
+
+func mysqldPluginsTests(initContainerName string, plugins []NdbMysqldPlugin, components []string,
 	fail bool, short string) *validationCase {
-	vc := &validationCase{
+	return &validationCase{
 		spec: &NdbClusterSpec{
 			RedundancyLevel: 2,
-			ManagementNode:  &NdbManagementNodeSpec{},
 			DataNode: &NdbDataNodeSpec{
 				NodeCount: 2,
 			},
+			MysqlNode: &NdbMysqldSpec{
+				NodeCount: 2,
+				Plugins: &NdbMysqldPluginsSpec{
+					InitContainers: []corev1.Container{{Name: initContainerName, Image: "example.com/audit-plugin"}},
+					Plugins:        plugins,
+					Components:     components,
+				},
+				NdbPodSpec: &NdbClusterPodSpec{
+					InitContainers: []corev1.Container{{Name: "setup", Image: "busybox"}},
+				},
+			},
 		},
-		oldSpec: &NdbClusterSpec{
+		shouldFail: fail,
+		explain:    fmt.Sprintf("MySQL Server plugins : %v, %v - %s", plugins, components, short),
+	}
+}
+
+func mysqldAuditLogTests(rotateOnSize, sidecarName string, fail bool, short string) *validationCase {
+	auditLog := &NdbMysqldAuditLogSpec{
+		SidecarContainers: []corev1.Container{{Name: sidecarName, Image: "example.com/log-shipper"}},
+	}
+	if rotateOnSize != "" {
+		size := resource.MustParse(rotateOnSize)
+		auditLog.RotateOnSize = &size
+	}
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			MysqlNode: &NdbMysqldSpec{
+				NodeCount: 2,
+				AuditLog:  auditLog,
+				NdbPodSpec: &NdbClusterPodSpec{
+					SidecarContainers: []corev1.Container{{Name: "metrics", Image: "example.com/metrics"}},
+				},
+			},
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("MySQL Server audit log : %q, %q - %s", rotateOnSize, sidecarName, short),
+	}
+}
+
+func loadBalancerTests(sourceRanges []string, annotations map[string]string, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			MysqlNode: &NdbMysqldSpec{
+				NodeCount:          2,
+				EnableLoadBalancer: true,
+				LoadBalancer: &NdbLoadBalancerSpec{
+					LoadBalancerSourceRanges: sourceRanges,
+					ExternalTrafficPolicy:    corev1.ServiceExternalTrafficPolicyTypeLocal,
+					Annotations:              annotations,
+				},
+			},
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("load balancer : %v, %v - %s", sourceRanges, annotations, short),
+	}
+}
+
+func mysqldGatewayTests(gateway *NdbMysqldGatewaySpec, enableLoadBalancer bool, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			MysqlNode: &NdbMysqldSpec{
+				NodeCount:          2,
+				EnableLoadBalancer: enableLoadBalancer,
+				Gateway:            gateway,
+			},
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("MySQL Server gateway : %+v, %v - %s", *gateway, enableLoadBalancer, short),
+	}
+}
+
+func ipFamilyTests(ipFamilyPolicy corev1.IPFamilyPolicy, ipFamilies []corev1.IPFamily, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			IPFamilyPolicy: &ipFamilyPolicy,
+			IPFamilies:     ipFamilies,
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("IP family policy : '%s', IP families : %v - %s", ipFamilyPolicy, ipFamilies, short),
+	}
+}
+
+func locationDomainTests(topologyKey string, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			LocationDomainTopologyKey: topologyKey,
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("location domain topology key : '%s' - %s", topologyKey, short),
+	}
+}
+
+func serviceAccountTests(serviceAccount *NdbServiceAccountSpec, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			ServiceAccount: serviceAccount,
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("service account : %+v - %s", *serviceAccount, short),
+	}
+}
+
+func dataNodeZonesTests(redundancy, dnc int32, zones []string, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: redundancy,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: dnc,
+				Zones:     zones,
+			},
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("redundancy : %d, data nodes : %d, zones : %v - %s", redundancy, dnc, zones, short),
+	}
+}
+
+func arbitratorTests(zone string, dataNodeZones []string, freeAPISlots int32, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+				Zones:     dataNodeZones,
+			},
+			FreeAPISlots: freeAPISlots,
+			Arbitrator: &NdbArbitratorSpec{
+				Zone: zone,
+			},
+		},
+		shouldFail: fail,
+		explain: fmt.Sprintf("arbitrator zone : '%s', data node zones : %v, free API slots : %d - %s",
+			zone, dataNodeZones, freeAPISlots, short),
+	}
+}
+
+func apiSectionsTests(maxNodeCount, connectionPoolSize, freeAPISlots int32, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			MysqlNode: &NdbMysqldSpec{
+				NodeCount:          1,
+				MaxNodeCount:       maxNodeCount,
+				ConnectionPoolSize: connectionPoolSize,
+			},
+			FreeAPISlots: freeAPISlots,
+		},
+		shouldFail: fail,
+		explain: fmt.Sprintf("mysqld maxNodeCount : %d, connection pool size : %d, free API slots : %d - %s",
+			maxNodeCount, connectionPoolSize, freeAPISlots, short),
+	}
+}
+
+func ndbAPIApplicationsTests(apps []NdbAPIApplicationSpec, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			NdbAPIApplications: apps,
+		},
+		shouldFail: fail,
+		explain:    "NDBAPI applications - " + short,
+	}
+}
+
+func threadConfigTests(useNdbd bool, threadConfig, configKey string, fail bool, short string) *validationCase {
+	var config map[string]*intstr.IntOrString
+	if configKey != "" {
+		configValue := intstr.FromString("ldm={count=4}")
+		config = map[string]*intstr.IntOrString{
+			configKey: &configValue,
+		}
+	}
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount:    2,
+				UseNdbd:      useNdbd,
+				ThreadConfig: threadConfig,
+				Config:       config,
+			},
+		},
+		shouldFail: fail,
+		explain: fmt.Sprintf("useNdbd : %v, threadConfig : '%s', config key : '%s' - %s",
+			useNdbd, threadConfig, configKey, short),
+	}
+}
+
+func hugePagesTests(pageSize, size string, resources *corev1.ResourceRequirements, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+				HugePages: &NdbHugePagesSpec{
+					PageSize: pageSize,
+					Size:     resource.MustParse(size),
+				},
+				NdbPodSpec: &NdbClusterPodSpec{
+					Resources: resources,
+				},
+			},
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("huge pages page size : %s, size : %s - %s", pageSize, size, short),
+	}
+}
+
+func mysqldAutoscalingTests(maxNodeCount, minReplicas, maxReplicas int32, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			MysqlNode: &NdbMysqldSpec{
+				NodeCount:    1,
+				MaxNodeCount: maxNodeCount,
+				Autoscaling: &NdbMysqldAutoscalingSpec{
+					MinNodeCount: minReplicas,
+					MaxNodeCount: maxReplicas,
+				},
+			},
+		},
+		shouldFail: fail,
+		explain: fmt.Sprintf("autoscaling %d-%d, maxNodeCount %d - %s",
+			minReplicas, maxReplicas, maxNodeCount, short),
+	}
+}
+
+func mysqldPodDisruptionBudgetTests(minAvailable intstr.IntOrString, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
 			RedundancyLevel: 2,
-			ManagementNode:  &NdbManagementNodeSpec{},
 			DataNode: &NdbDataNodeSpec{
 				NodeCount: 2,
 			},
+			MysqlNode: &NdbMysqldSpec{
+				NodeCount: 2,
+				PodDisruptionBudget: &NdbPodDisruptionBudgetSpec{
+					MinAvailable: &minAvailable,
+				},
+			},
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("PDB minAvailable : '%s' - %s", minAvailable.String(), short),
+	}
+}
+
+func mysqldUpdateStrategyTests(
+	strategyType appsv1.StatefulSetUpdateStrategyType, partition *int32, fail bool, short string) *validationCase {
+	var rollingUpdate *appsv1.RollingUpdateStatefulSetStrategy
+	if partition != nil {
+		rollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{
+			Partition: partition,
+		}
+	}
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			MysqlNode: &NdbMysqldSpec{
+				NodeCount: 2,
+				UpdateStrategy: &appsv1.StatefulSetUpdateStrategy{
+					Type:          strategyType,
+					RollingUpdate: rollingUpdate,
+				},
+			},
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("update strategy : '%s' - %s", strategyType, short),
+	}
+}
+
+func customPodLabelsTests(podLabels map[string]string, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+				PodLabels: podLabels,
+			},
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("custom pod labels : '%v' - %s", podLabels, short),
+	}
+}
+
+func mysqldPasswordValidationTests(policy, rootPasswordSecretName string, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			MysqlNode: &NdbMysqldSpec{
+				NodeCount:              2,
+				RootPasswordSecretName: rootPasswordSecretName,
+				PasswordValidation: &NdbMysqldPasswordValidationSpec{
+					Policy: policy,
+				},
+			},
+		},
+		shouldFail: fail,
+		explain: fmt.Sprintf("password validation policy : '%s', root password secret : '%s' - %s",
+			policy, rootPasswordSecretName, short),
+	}
+}
+
+func imagePullSecretsTests(secretName string, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			ImagePullSecrets: []corev1.LocalObjectReference{
+				{
+					Name: secretName,
+				},
+			},
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("image pull secret : '%s' - %s", secretName, short),
+	}
+}
+
+func sidecarContainersTests(initContainerName, sidecarName string, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+				NdbPodSpec: &NdbClusterPodSpec{
+					InitContainers: []corev1.Container{
+						{
+							Name: initContainerName,
+						},
+					},
+					SidecarContainers: []corev1.Container{
+						{
+							Name: sidecarName,
+						},
+					},
+				},
+			},
+		},
+		shouldFail: fail,
+		explain: fmt.Sprintf("init container : '%s', sidecar container : '%s' - %s",
+			initContainerName, sidecarName, short),
+	}
+}
+
+func podVolumesTests(volumeName string, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+				NdbPodSpec: &NdbClusterPodSpec{
+					Volumes: []corev1.Volume{
+						{
+							Name: volumeName,
+						},
+					},
+				},
+			},
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("volume : '%s' - %s", volumeName, short),
+	}
+}
+
+func dataNodeLogLevelsTests(configKey string, fail bool, short string) *validationCase {
+	startupLevel := int32(15)
+	configValue := intstr.FromInt(10)
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+				Config: map[string]*intstr.IntOrString{
+					configKey: &configValue,
+				},
+				LogLevels: &NdbDataNodeLogLevels{
+					Startup: &startupLevel,
+				},
+			},
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("config key : '%s' - %s", configKey, short),
+	}
+}
+
+func diskDataTests(undoFileName, dataFileName, pvcStorage string, fail bool, short string) *validationCase {
+	undoFileSize := resource.MustParse("128Mi")
+	dataFileSize := resource.MustParse("1Gi")
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+				PVCSpec: &corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse(pvcStorage),
+						},
+					},
+				},
+				DiskData: &NdbDiskDataSpec{
+					LogfileGroup: NdbLogfileGroupSpec{
+						Name: "lg_1",
+						UndoFiles: []NdbDiskDataFileSpec{
+							{Name: undoFileName, Size: &undoFileSize},
+						},
+					},
+					Tablespaces: []NdbTablespaceSpec{
+						{
+							Name: "ts_1",
+							DataFiles: []NdbDiskDataFileSpec{
+								{Name: dataFileName, Size: &dataFileSize},
+							},
+						},
+					},
+				},
+			},
+		},
+		shouldFail: fail,
+		explain: fmt.Sprintf("undo file : '%s', data file : '%s', pvc storage : '%s' - %s",
+			undoFileName, dataFileName, pvcStorage, short),
+	}
+}
+
+func diskDataAutoSizeTests(pvcStorage string, fail bool, short string) *validationCase {
+	vc := diskDataTests("undo_1.log", "data_1.dat", "10Gi", fail, short)
+	vc.spec.DataNode.DiskData.LogfileGroup.UndoFiles[0].Size = nil
+	vc.spec.DataNode.DiskData.Tablespaces[0].DataFiles[0].Size = nil
+	if pvcStorage == "" {
+		vc.spec.DataNode.PVCSpec = nil
+	} else {
+		vc.spec.DataNode.PVCSpec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse(pvcStorage)
+	}
+	vc.explain = fmt.Sprintf("automatically sized files, pvc storage : '%s' - %s", pvcStorage, short)
+	return vc
+}
+
+func localVolumesTests(pvcSpec *corev1.PersistentVolumeClaimSpec, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+				PVCSpec:   pvcSpec,
+				LocalVolumes: &NdbDataNodeLocalVolumesSpec{
+					Reschedule: true,
+				},
+			},
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("local volumes with pvcSpec : '%v' - %s", pvcSpec != nil, short),
+	}
+}
+
+func separateVolumesTests(undoFilesPVCStorage, configKey string, fail bool, short string) *validationCase {
+	vc := diskDataTests("undo_1.log", "data_1.dat", "10Gi", fail, short)
+	vc.spec.DataNode.SeparateVolumes = &NdbDataNodeVolumesSpec{
+		UndoFiles: &corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(undoFilesPVCStorage),
+				},
+			},
+		},
+	}
+	if configKey != "" {
+		vc.spec.DataNode.Config = map[string]*intstr.IntOrString{
+			configKey: {Type: intstr.String, StrVal: "/var/lib/ndb/custom"},
+		}
+	}
+	vc.explain = fmt.Sprintf("undo files pvc storage : '%s', config : '%s' - %s",
+		undoFilesPVCStorage, configKey, short)
+	return vc
+}
+
+func backupBeforeRestartTests(backupPVCSpec *corev1.PersistentVolumeClaimSpec, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+				SeparateVolumes: &NdbDataNodeVolumesSpec{
+					Backup: backupPVCSpec,
+				},
+				SystemRestart: &NdbDataNodeSystemRestartSpec{
+					Allowed:             true,
+					BackupBeforeRestart: true,
+				},
+			},
+		},
+		shouldFail: fail,
+		explain:    fmt.Sprintf("backup before restart with a backup volume : '%v' - %s", backupPVCSpec != nil, short),
+	}
+}
+
+func verifyBackupsTests(dataPVCSpec, backupPVCSpec *corev1.PersistentVolumeClaimSpec,
+	fail bool, short string) *validationCase {
+	vc := &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+				PVCSpec:   dataPVCSpec,
+			},
+			VerifyBackups: true,
+		},
+		shouldFail: fail,
+		explain: fmt.Sprintf("verify backups with a data volume : '%v', backup volume : '%v' - %s",
+			dataPVCSpec != nil, backupPVCSpec != nil, short),
+	}
+	if backupPVCSpec != nil {
+		vc.spec.DataNode.SeparateVolumes = &NdbDataNodeVolumesSpec{
+			Backup: backupPVCSpec,
+		}
+	}
+	return vc
+}
+
+func ndbUpdateTests(redundancy, dnc, mysqldCount,
+	oldRedundancy, oldDnc, oldMysqldCount int32,
+	fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: redundancy,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: dnc,
+			},
+			MysqlNode: &NdbMysqldSpec{
+				NodeCount: mysqldCount,
+			},
+		},
+		oldSpec: &NdbClusterSpec{
+			RedundancyLevel: oldRedundancy,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: oldDnc,
+			},
+			MysqlNode: &NdbMysqldSpec{
+				NodeCount: oldMysqldCount,
+			},
+		},
+		shouldFail: fail,
+		explain:    short,
+	}
+}
+
+func ndbUpdateNdbPodSpecTests(
+	oldNdbClusterSpec func(defaultSpec *NdbClusterSpec),
+	newNdbClusterSpec func(defaultSpec *NdbClusterSpec),
+	fail bool, short string) *validationCase {
+	vc := &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			ManagementNode:  &NdbManagementNodeSpec{},
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+		},
+		oldSpec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			ManagementNode:  &NdbManagementNodeSpec{},
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+		},
+		shouldFail: fail,
+		explain:    short,
+	}
+	oldNdbClusterSpec(vc.oldSpec)
+	newNdbClusterSpec(vc.spec)
+	return vc
+}
+
+func hostNetworkPortTests(dataNodeServerPort int32, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			ManagementNode:  &NdbManagementNodeSpec{HostNetwork: true},
+			DataNode: &NdbDataNodeSpec{
+				NodeCount:   2,
+				HostNetwork: true,
+				ServerPort:  dataNodeServerPort,
+			},
+		},
+		shouldFail: fail,
+		explain:    short,
+	}
+}
+
+func managementNodeCountTests(redundancy, mgmdc int32, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: redundancy,
+			ManagementNode:  &NdbManagementNodeSpec{NodeCount: mgmdc},
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: redundancy,
+			},
+		},
+		shouldFail: fail,
+		explain: fmt.Sprintf("%3d redundancy, %3d management nodes - %s",
+			redundancy, mgmdc, short),
+	}
+}
+
+func Test_Validation(t *testing.T) {
+
+	shouldFail := true
+	partition, negativePartition := int32(1), int32(-1)
+	memoryRequest := &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+	}
+	pvcSpecWithStorage := func(storage string) *corev1.PersistentVolumeClaimSpec {
+		return &corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(storage)},
+			},
+		}
+	}
+	vcs := []*validationCase{
+		nodeNumberTests(0, 0, 0, shouldFail, "all zero"),
+		nodeNumberTests(0, 2, 2, shouldFail, "redundancy zero, not matching node count"),
+		nodeNumberTests(3, 2, 2, shouldFail, "redundancy not matching data node count"),
+		nodeNumberTests(2, 145, 2, shouldFail, "too many data nodes"),
+		nodeNumberTests(2, 144, 111, shouldFail, "too many nodes (including 2 mgmd nodes)"),
+		nodeNumberTests(1, 144, 108, !shouldFail, "okay no of nodes (including 1 mgmd nodes)"),
+		nodeNumberTests(2, 144, 2, !shouldFail, "okay"),
+		nodeNumberTests(3, 9, 2, !shouldFail, "okay"),
+		nodeNumberTests(2, 2, 2, !shouldFail, "all okay"),
+		nodeNumberTests(1, 2, 2, !shouldFail, "2 dn and redundancy 1 is okay"),
+		nodeNumberTests(2, 2, 0, !shouldFail, "okay with no mysqlds"),
+
+		mysqldRootPasswordSecretNameTests("root-pass.123", !shouldFail, "valid name"),
+		mysqldRootPasswordSecretNameTests("-root-pass123", shouldFail, "should start with an alphabet"),
+		mysqldRootPasswordSecretNameTests("root-pass-", shouldFail, "should end with an alphabet"),
+		mysqldRootPasswordSecretNameTests("root-pass!", shouldFail, "has invalid character"),
+		mysqldPasswordSecretsTests("", "operator-pass", "", !shouldFail, "custom operator password secret"),
+		mysqldPasswordSecretsTests("root-pass", "operator-pass", "vault-root-pass", !shouldFail,
+			"root password secret synced via a SecretProviderClass"),
+		mysqldPasswordSecretsTests("", "operator-pass!", "", shouldFail, "operator password secret has invalid character"),
+		mysqldPasswordSecretsTests("pass", "pass", "", shouldFail, "same secret for root and operator passwords"),
+		mysqldPasswordSecretsTests("", "", "vault-root-pass", shouldFail,
+			"SecretProviderClass without a root password secret"),
+		mysqldPasswordSecretsTests("root-pass", "", "vault_root_pass", shouldFail,
+			"SecretProviderClass has invalid character"),
+
+		mysqldPasswordValidationTests("MEDIUM", "root-pass", !shouldFail, "okay"),
+		mysqldPasswordValidationTests("LOW", "", !shouldFail, "okay with generated root password"),
+		mysqldPasswordValidationTests("STRONG", "", shouldFail, "generated root password might not satisfy the policy"),
+
+		mysqldServerGroupTests([]NdbMysqldServerGroupSpec{
+			{Name: "oltp", NodeCount: 2},
+			{Name: "olap", NodeCount: 1, MyCnf: "max_connections=50"},
+		}, !shouldFail, "okay"),
+		mysqldServerGroupTests([]NdbMysqldServerGroupSpec{
+			{Name: "oltp", NodeCount: 2},
+			{Name: "oltp", NodeCount: 1},
+		}, shouldFail, "duplicate group names"),
+		mysqldServerGroupTests([]NdbMysqldServerGroupSpec{
+			{Name: "olap", NodeCount: 1, MyCnf: "[mysqld]\nmax_connections=50\n[client]\nuser=root"},
+		}, shouldFail, "more than one section in the group my.cnf"),
+		mysqldServerGroupTests([]NdbMysqldServerGroupSpec{
+			{Name: "olap", NodeCount: 250},
+		}, shouldFail, "too many nodes including the group"),
+
+		initFromBackupTests(&NdbClusterInitFromBackupSpec{
+			Parallelism:      256,
+			RestoreEpoch:     true,
+			IncludeDatabases: []string{"app"},
+			ExcludeTables:    []string{"app.audit_log"},
+			RewriteDatabases: []NdbClusterDatabaseRewrite{{From: "app", To: "app_copy"}},
+		}, !shouldFail, "okay"),
+		initFromBackupTests(&NdbClusterInitFromBackupSpec{
+			IncludeDatabases: []string{"app,shop"},
+		}, shouldFail, "database name with a comma"),
+		initFromBackupTests(&NdbClusterInitFromBackupSpec{
+			IncludeTables: []string{"audit_log"},
+		}, shouldFail, "table name without the database"),
+		initFromBackupTests(&NdbClusterInitFromBackupSpec{
+			IncludeDatabases: []string{"my-app"},
+			IncludeTables:    []string{"my-app.audit$log"},
+			RewriteDatabases: []NdbClusterDatabaseRewrite{{From: "my-app", To: "my-app's_copy"}},
+		}, !shouldFail, "names with special characters"),
+		initFromBackupTests(&NdbClusterInitFromBackupSpec{
+			RewriteDatabases: []NdbClusterDatabaseRewrite{{From: "app", To: "app1"}, {From: "app", To: "app2"}},
+		}, shouldFail, "database rewritten twice"),
+
+		mysqldExtraArgsTests([]string{"--log-bin=binlog", "--skip-log-bin"}, !shouldFail, "okay"),
+		mysqldExtraArgsTests([]string{"--ndb-connectstring=example-ndb-mgmd"}, shouldFail, "operator managed option"),
+		mysqldExtraArgsTests([]string{"--loose_ndb_nodeid=150"}, shouldFail, "operator managed option with modifier"),
+		mysqldExtraArgsTests([]string{"--skip-ndbcluster"}, shouldFail, "disabling the ndbcluster engine"),
+		mysqldExtraArgsTests([]string{"log-bin"}, shouldFail, "not a long option"),
+		mysqldExtraArgsTests([]string{"--port=3307"}, shouldFail, "port managed by the operator"),
+		mysqldExtraArgsTests([]string{"--socket=/tmp/mysql.sock"}, shouldFail, "socket managed by the operator"),
+
+		mysqldMyCnfTests("[mysqld]\nmax-user-connections=42\nndb_extra_logging=10\n", !shouldFail, "okay"),
+		mysqldMyCnfTests("[mysqld]\ndefault_storage_engine = NDBCLUSTER\nskip-name-resolve=ON\n",
+			!shouldFail, "okay with spaces and modifiers"),
+		mysqldMyCnfTests("[mysqld]\nloose-my-plugin-option=1\n", !shouldFail, "unknown option with loose modifier"),
+		mysqldMyCnfTests("[mysqld]\nmysql_native_password=ON\n", !shouldFail, "unknown option only warned about"),
+		mysqldMyCnfTests("[mysqld]\nport=3307\n", shouldFail, "port managed by the operator"),
+		mysqldMyCnfTests("[mysqld]\nndb-connectstring=example-ndb-mgmd\n", shouldFail, "operator managed option"),
+		mysqldMyCnfTests("[mysqld]\nloose_datadir=/tmp\n", shouldFail, "operator managed option with modifier"),
+
+		mysqldPluginsTests("install-audit-plugin", []NdbMysqldPlugin{{Name: "audit_log", Library: "audit_log.so"}},
+			[]string{"file://component_audit_api_message_emit"}, !shouldFail, "okay"),
+		mysqldPluginsTests("setup", nil, nil, shouldFail, "init container name used in ndbPodSpec"),
+		mysqldPluginsTests("install-audit-plugin", []NdbMysqldPlugin{
+			{Name: "audit_log", Library: "audit_log.so"}, {Name: "AUDIT_LOG", Library: "audit_log.so"},
+		}, nil, shouldFail, "duplicate plugins"),
+		mysqldPluginsTests("install-audit-plugin", nil, []string{"component_audit_api_message_emit"},
+			shouldFail, "component without the file scheme"),
+		mysqldPluginsTests("install-audit-plugin", nil, []string{"file://component'; drop table t1; '"},
+			shouldFail, "component with quotes"),
+
+		mysqldAuditLogTests("100Mi", "audit-log-shipper", !shouldFail, "okay"),
+		mysqldAuditLogTests("", "audit-log-shipper", !shouldFail, "okay without rotation"),
+		mysqldAuditLogTests("1Ki", "audit-log-shipper", shouldFail, "rotate size too small"),
+		mysqldAuditLogTests("100Mi", "metrics", shouldFail, "sidecar name used in ndbPodSpec"),
+
+		loadBalancerTests([]string{"10.0.0.0/8", "2001:db8::/32"},
+			map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"}, !shouldFail, "okay"),
+		loadBalancerTests(nil, nil, !shouldFail, "okay without source ranges and annotations"),
+		loadBalancerTests([]string{"10.0.0.1"}, nil, shouldFail, "source range without a prefix length"),
+		loadBalancerTests(nil, map[string]string{"invalid key!": "nlb"}, shouldFail, "invalid annotation key"),
+
+		mysqldGatewayTests(&NdbMysqldGatewaySpec{GatewayClassName: "example", Port: 3306},
+			false, !shouldFail, "okay"),
+		mysqldGatewayTests(&NdbMysqldGatewaySpec{GatewayName: "shared-gateway", ListenerName: "mysql"},
+			false, !shouldFail, "okay with an existing gateway"),
+		mysqldGatewayTests(&NdbMysqldGatewaySpec{}, false, shouldFail, "no gateway class or name"),
+		mysqldGatewayTests(&NdbMysqldGatewaySpec{GatewayClassName: "example", GatewayName: "shared-gateway"},
+			false, shouldFail, "both gateway class and name"),
+		mysqldGatewayTests(&NdbMysqldGatewaySpec{GatewayClassName: "example", ListenerName: "mysql"},
+			false, shouldFail, "listener name without an existing gateway"),
+		mysqldGatewayTests(&NdbMysqldGatewaySpec{GatewayClassName: "example"},
+			true, shouldFail, "along with the load balancer"),
+
+		ipFamilyTests(corev1.IPFamilyPolicySingleStack, []corev1.IPFamily{corev1.IPv6Protocol}, !shouldFail, "okay"),
+		ipFamilyTests(corev1.IPFamilyPolicyRequireDualStack,
+			[]corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}, !shouldFail, "okay with dual-stack"),
+		ipFamilyTests(corev1.IPFamilyPolicySingleStack,
+			[]corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}, shouldFail, "two families with SingleStack"),
+		ipFamilyTests(corev1.IPFamilyPolicyPreferDualStack,
+			[]corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv4Protocol}, shouldFail, "duplicate families"),
+		locationDomainTests(corev1.LabelTopologyZone, !shouldFail, "zone label"),
+		locationDomainTests("example.com/rack", !shouldFail, "custom label"),
+		locationDomainTests("invalid key!", shouldFail, "invalid label"),
+		ipFamilyTests(corev1.IPFamilyPolicySingleStack, []corev1.IPFamily{"IPv5"}, shouldFail, "unsupported family"),
+
+		serviceAccountTests(&NdbServiceAccountSpec{}, !shouldFail, "okay with the default rules"),
+		serviceAccountTests(&NdbServiceAccountSpec{Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list"}},
+		}}, !shouldFail, "okay with custom rules"),
+		serviceAccountTests(&NdbServiceAccountSpec{Name: "mysql-agent"}, !shouldFail, "okay with an existing ServiceAccount"),
+		serviceAccountTests(&NdbServiceAccountSpec{Name: "mysql_agent"}, shouldFail, "invalid name"),
+		serviceAccountTests(&NdbServiceAccountSpec{Name: "mysql-agent", Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list"}},
+		}}, shouldFail, "rules for an existing ServiceAccount"),
+
+		dataNodeZonesTests(2, 4, []string{"zone-a", "zone-b"}, !shouldFail, "okay"),
+		dataNodeZonesTests(2, 4, []string{"zone-a", "zone-b", "zone-c"}, !shouldFail, "okay with more zones"),
+		dataNodeZonesTests(3, 3, []string{"zone-a", "zone-b"}, shouldFail, "fewer zones than redundancy"),
+		dataNodeZonesTests(2, 2, []string{"zone-a", "zone-a"}, shouldFail, "duplicate zones"),
+		dataNodeZonesTests(2, 2, []string{"zone-a", "zone b"}, shouldFail, "invalid zone"),
+
+		arbitratorTests("zone-c", []string{"zone-a", "zone-b"}, 2, !shouldFail, "okay"),
+		arbitratorTests("", nil, 2, !shouldFail, "okay without zone"),
+		arbitratorTests("zone-b", []string{"zone-a", "zone-b"}, 2, shouldFail, "zone used by the data nodes"),
+		arbitratorTests("zone c", nil, 2, shouldFail, "invalid zone"),
+		arbitratorTests("zone-c", nil, 107, !shouldFail, "okay with all the available API sections"),
+		arbitratorTests("zone-c", nil, 108, shouldFail, "API sections overlap the arbitrator nodeId"),
+
+		apiSectionsTests(10, 1, 98, !shouldFail, "okay with all the available API sections"),
+		apiSectionsTests(10, 1, 99, shouldFail, "API sections exceed the maximum nodeId"),
+		apiSectionsTests(36, 3, 0, !shouldFail, "okay with all the API sections used by the connection pools"),
+		apiSectionsTests(37, 3, 0, shouldFail, "connection pools exceed the maximum nodeId"),
+
+		ndbAPIApplicationsTests([]NdbAPIApplicationSpec{
+			{Name: "loader", Namespace: "apps", NodeCount: 2, ConnectionPoolSize: 2},
+			{Name: "reader", Namespace: "apps", NodeCount: 1},
+		}, !shouldFail, "okay"),
+		ndbAPIApplicationsTests([]NdbAPIApplicationSpec{
+			{Name: "loader", Namespace: "apps", NodeCount: 1},
+			{Name: "loader", Namespace: "other-apps", NodeCount: 1},
+		}, shouldFail, "duplicate names"),
+		ndbAPIApplicationsTests([]NdbAPIApplicationSpec{
+			{Name: "loader", Namespace: "Apps", NodeCount: 1},
+		}, shouldFail, "invalid namespace"),
+		ndbAPIApplicationsTests([]NdbAPIApplicationSpec{
+			{Name: "loader", Namespace: "apps", NodeCount: 55, ConnectionPoolSize: 2},
+		}, shouldFail, "API sections exceed the maximum nodeId"),
+
+		threadConfigTests(false, "ldm={count=4,cpubind=1-4},tc={count=2},main,rep,recv,send", "", !shouldFail, "okay"),
+		threadConfigTests(true, "", "", !shouldFail, "okay with ndbd"),
+		threadConfigTests(true, "ldm={count=2}", "", shouldFail, "thread config with ndbd"),
+		threadConfigTests(false, "ldm={count=2},cpu={count=1}", "", shouldFail, "unknown thread type"),
+		threadConfigTests(false, "ldm=count=2", "", shouldFail, "properties without braces"),
+		threadConfigTests(false, "ldm={count=2,tc={count=1}}", "", shouldFail, "nested braces"),
+		threadConfigTests(false, "ldm={count=2", "", shouldFail, "unbalanced braces"),
+		threadConfigTests(false, "ldm={count=2}", "threadconfig", shouldFail, "thread config also specified in config"),
+
+		hugePagesTests("2Mi", "1Gi", memoryRequest, !shouldFail, "okay"),
+		hugePagesTests("1Gi", "2Gi", memoryRequest, !shouldFail, "okay with 1Gi pages"),
+		hugePagesTests("2Mi", "3Mi", memoryRequest, shouldFail, "size not a multiple of page size"),
+		hugePagesTests("2Mi", "0", memoryRequest, shouldFail, "zero size"),
+		hugePagesTests("2Mi", "1Gi", nil, shouldFail, "no cpu or memory resource"),
+		threadConfigTests(false, "", "ThreadConfig", !shouldFail, "okay with thread config only in config"),
+
+		mysqldAutoscalingTests(5, 1, 5, !shouldFail, "okay"),
+		mysqldAutoscalingTests(5, 2, 0, !shouldFail, "okay with default autoscaling max"),
+		mysqldAutoscalingTests(5, 1, 6, shouldFail, "autoscaling max exceeds maxNodeCount"),
+		mysqldAutoscalingTests(5, 4, 3, shouldFail, "autoscaling min exceeds autoscaling max"),
+		mysqldAutoscalingTests(5, 6, 0, shouldFail, "autoscaling min exceeds maxNodeCount"),
+
+		mysqldPodDisruptionBudgetTests(intstr.FromInt(1), !shouldFail, "okay"),
+		mysqldPodDisruptionBudgetTests(intstr.FromString("50%"), !shouldFail, "okay with percentage"),
+		mysqldPodDisruptionBudgetTests(intstr.FromInt(-1), shouldFail, "negative minAvailable"),
+		mysqldPodDisruptionBudgetTests(intstr.FromString("half"), shouldFail, "invalid percentage"),
+
+		mysqldUpdateStrategyTests(appsv1.RollingUpdateStatefulSetStrategyType, &partition, !shouldFail, "okay"),
+		mysqldUpdateStrategyTests(appsv1.OnDeleteStatefulSetStrategyType, nil, !shouldFail, "okay with OnDelete"),
+		mysqldUpdateStrategyTests(appsv1.RollingUpdateStatefulSetStrategyType, &negativePartition, shouldFail, "negative partition"),
+		mysqldUpdateStrategyTests(appsv1.OnDeleteStatefulSetStrategyType, &partition, shouldFail, "partition with OnDelete"),
+		mysqldUpdateStrategyTests("Recreate", nil, shouldFail, "unsupported type"),
+
+		{
+			spec: &NdbClusterSpec{
+				RedundancyLevel: 2,
+				DataNode: &NdbDataNodeSpec{
+					NodeCount: 2,
+				},
+				MysqlNode: &NdbMysqldSpec{
+					NodeCount: 2,
+					CanaryRollout: &NdbMysqldCanaryRolloutSpec{
+						HealthCheckQueries: []string{"SELECT 1 FROM app.orders LIMIT 1"},
+					},
+				},
+			},
+			shouldFail: false,
+			explain:    "canary rollout is okay",
+		},
+		{
+			spec: &NdbClusterSpec{
+				RedundancyLevel: 2,
+				DataNode: &NdbDataNodeSpec{
+					NodeCount: 2,
+				},
+				MysqlNode: &NdbMysqldSpec{
+					NodeCount: 2,
+					UpdateStrategy: &appsv1.StatefulSetUpdateStrategy{
+						Type: appsv1.OnDeleteStatefulSetStrategyType,
+					},
+					CanaryRollout: &NdbMysqldCanaryRolloutSpec{},
+				},
+			},
+			shouldFail: true,
+			explain:    "canary rollout cannot be specified with an update strategy",
 		},
-		shouldFail: fail,
-		explain:    short,
-	}
-	oldNdbClusterSpec(vc.oldSpec)
-	newNdbClusterSpec(vc.spec)
-	return vc
-}
 
-func Test_Validation(t *testing.T) {
+		customPodLabelsTests(map[string]string{"app.kubernetes.io/part-of": "billing"}, !shouldFail, "okay"),
+		customPodLabelsTests(map[string]string{"team": "invalid value"}, shouldFail, "invalid label value"),
+		customPodLabelsTests(map[string]string{"-team": "db"}, shouldFail, "invalid label key"),
 
-	shouldFail := true
-	vcs := []*validationCase{
-		nodeNumberTests(0, 0, 0, shouldFail, "all zero"),
-		nodeNumberTests(0, 2, 2, shouldFail, "redundancy zero, not matching node count"),
-		nodeNumberTests(3, 2, 2, shouldFail, "redundancy not matching data node count"),
-		nodeNumberTests(2, 145, 2, shouldFail, "too many data nodes"),
-		nodeNumberTests(2, 144, 111, shouldFail, "too many nodes (including 2 mgmd nodes)"),
-		nodeNumberTests(1, 144, 108, !shouldFail, "okay no of nodes (including 1 mgmd nodes)"),
-		nodeNumberTests(2, 144, 2, !shouldFail, "okay"),
-		nodeNumberTests(3, 9, 2, !shouldFail, "okay"),
-		nodeNumberTests(2, 2, 2, !shouldFail, "all okay"),
-		nodeNumberTests(1, 2, 2, !shouldFail, "2 dn and redundancy 1 is okay"),
-		nodeNumberTests(2, 2, 0, !shouldFail, "okay with no mysqlds"),
+		imagePullSecretsTests("registry-credentials", !shouldFail, "okay"),
+		imagePullSecretsTests("Registry_Credentials", shouldFail, "invalid secret name"),
 
-		mysqldRootPasswordSecretNameTests("root-pass.123", !shouldFail, "valid name"),
-		mysqldRootPasswordSecretNameTests("-root-pass123", shouldFail, "should start with an alphabet"),
-		mysqldRootPasswordSecretNameTests("root-pass-", shouldFail, "should end with an alphabet"),
-		mysqldRootPasswordSecretNameTests("root-pass!", shouldFail, "has invalid character"),
+		sidecarContainersTests("setup", "log-shipper", !shouldFail, "okay"),
+		sidecarContainersTests("setup", "setup", shouldFail, "duplicate container name"),
+		sidecarContainersTests("setup", "", shouldFail, "empty container name"),
+		sidecarContainersTests("setup", "ndbmtd-container", shouldFail, "reserved sidecar container name"),
+		sidecarContainersTests("ndb-pod-init-container", "log-shipper", shouldFail, "reserved init container name"),
+
+		podVolumesTests("scratch", !shouldFail, "okay"),
+		podVolumesTests("ndbmtd-data-vol", shouldFail, "reserved volume name"),
+		podVolumesTests("helper-scripts-vol", shouldFail, "reserved volume name"),
+
+		dataNodeLogLevelsTests("LogLevelShutdown", !shouldFail, "okay"),
+		dataNodeLogLevelsTests("loglevelstartup", shouldFail, "log level specified twice"),
+
+		diskDataTests("undo_1.log", "data_1.dat", "10Gi", !shouldFail, "okay"),
+		diskDataTests("file_1", "file_1", "10Gi", shouldFail, "duplicate file name"),
+		diskDataTests("undo_1.log", "data_1.dat", "1Gi", shouldFail, "files exceed the pvc storage"),
+		diskDataAutoSizeTests("10Gi", !shouldFail, "okay"),
+		diskDataAutoSizeTests("", shouldFail, "no pvc storage to size the files"),
+		diskDataAutoSizeTests("1Mi", shouldFail, "pvc storage too small to size the files"),
+
+		separateVolumesTests("1Gi", "", !shouldFail, "okay"),
+		separateVolumesTests("64Mi", "", shouldFail, "undo files exceed the undo files pvc storage"),
+		separateVolumesTests("1Gi", "FileSystemPathUndoFiles", shouldFail, "config param set by the undo files volume"),
+
+		backupBeforeRestartTests(pvcSpecWithStorage("10Gi"), !shouldFail, "okay"),
+		backupBeforeRestartTests(nil, shouldFail, "backup stored in the data volume"),
+		verifyBackupsTests(pvcSpecWithStorage("10Gi"), nil, !shouldFail, "backup stored in the data volume"),
+		verifyBackupsTests(nil, pvcSpecWithStorage("10Gi"), !shouldFail, "backup stored in the backup volume"),
+		verifyBackupsTests(nil, nil, shouldFail, "backup not stored in a PVC"),
+
+		localVolumesTests(pvcSpecWithStorage("10Gi"), !shouldFail, "okay"),
+		localVolumesTests(nil, shouldFail, "local volumes without a pvcSpec"),
 
 		ndbUpdateTests(2, 2, 2, 1, 2, 2, shouldFail, "should not update redundancy"),
 		ndbUpdateTests(2, 4, 2, 2, 2, 2, !shouldFail, "allow increasing data node count"),
@@ -169,6 +1138,66 @@ func Test_Validation(t *testing.T) {
 			}
 		}, !shouldFail, "allow update to non-resource fields"),
 
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.ManagementNode.PodManagementPolicy = appsv1.OrderedReadyPodManagement
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.ManagementNode.PodManagementPolicy = appsv1.ParallelPodManagement
+		}, shouldFail, "should not update management node podManagementPolicy"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.PodManagementPolicy = ""
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.PodManagementPolicy = appsv1.OrderedReadyPodManagement
+		}, shouldFail, "should not update data node podManagementPolicy"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.PodManagementPolicy = ""
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.PodManagementPolicy = appsv1.ParallelPodManagement
+		}, !shouldFail, "allow setting the data node podManagementPolicy to its default"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.ManagementNode.PodManagementPolicy = appsv1.OrderedReadyPodManagement
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.ManagementNode.PodManagementPolicy = ""
+		}, !shouldFail, "allow unsetting the default management node podManagementPolicy"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.FreeAPISlots = 1
+			defaultSpec.InitFromBackup = &NdbClusterInitFromBackupSpec{BackupID: 1, PersistentVolumeClaimName: "backups"}
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.FreeAPISlots = 1
+			defaultSpec.InitFromBackup = &NdbClusterInitFromBackupSpec{BackupID: 2, PersistentVolumeClaimName: "backups"}
+		}, shouldFail, "should not update initFromBackup"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.InitFromBackup = &NdbClusterInitFromBackupSpec{BackupID: 1, PersistentVolumeClaimName: "backups"}
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.InitFromBackup = &NdbClusterInitFromBackupSpec{BackupID: 1, PersistentVolumeClaimName: "backups"}
+		}, shouldFail, "initFromBackup requires a free API slot"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.MysqlNode = &NdbMysqldSpec{NodeCount: 1}
+			defaultSpec.InitFromDump = &NdbClusterInitFromDumpSpec{PersistentVolumeClaimName: "dumps", Path: "app.sql"}
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.MysqlNode = &NdbMysqldSpec{NodeCount: 1}
+			defaultSpec.InitFromDump = &NdbClusterInitFromDumpSpec{PersistentVolumeClaimName: "dumps", Path: "app-v2.sql"}
+		}, shouldFail, "should not update initFromDump"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.InitFromDump = &NdbClusterInitFromDumpSpec{PersistentVolumeClaimName: "dumps", Path: "app.sql"}
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.InitFromDump = &NdbClusterInitFromDumpSpec{PersistentVolumeClaimName: "dumps", Path: "app.sql"}
+		}, shouldFail, "initFromDump requires a MySQL Server"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.ManagementNode.UpdateStrategy = nil
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.ManagementNode.UpdateStrategy = &appsv1.StatefulSetUpdateStrategy{
+				Type: appsv1.OnDeleteStatefulSetStrategyType,
+			}
+		}, !shouldFail, "allow update to management node updateStrategy"),
+
 		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
 			defaultSpec.DataNode.NdbPodSpec = &NdbClusterPodSpec{
 				NodeSelector: map[string]string{
@@ -263,6 +1292,191 @@ func Test_Validation(t *testing.T) {
 				},
 			}
 		}, !shouldFail, "allow update if Resources did not change (2)"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.HostNetwork = true
+		}, shouldFail, "should not update data node hostNetwork"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.ManagementNode.HostNetwork = true
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.ManagementNode.HostNetwork = true
+			defaultSpec.DataNode.NodeCount = 2
+		}, !shouldFail, "allow update if management node hostNetwork did not change"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.MysqlNode = &NdbMysqldSpec{NodeCount: 1, HostNetwork: true}
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.MysqlNode = &NdbMysqldSpec{NodeCount: 1}
+		}, shouldFail, "should not update MySQL Server hostNetwork"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.ManagementNode.Port = 1187
+		}, shouldFail, "should not update management node port"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.ServerPort = 1186
+		}, !shouldFail, "allow setting the data node serverPort to the port already in use"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.MysqlNode = &NdbMysqldSpec{NodeCount: 1}
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.MysqlNode = &NdbMysqldSpec{NodeCount: 1, Port: 3307}
+		}, shouldFail, "should not update MySQL Server port"),
+
+		hostNetworkPortTests(0, !shouldFail, "okay with the default ports"),
+		hostNetworkPortTests(1187, !shouldFail, "okay with a different data node port"),
+		hostNetworkPortTests(1186, shouldFail, "data node port same as the management node port"),
+
+		managementNodeCountTests(2, 0, !shouldFail, "okay with the default management node count"),
+		managementNodeCountTests(1, 2, !shouldFail, "okay to run 2 management nodes with redundancy 1"),
+		managementNodeCountTests(2, 1, !shouldFail, "okay to run a single management node with redundancy 2"),
+		managementNodeCountTests(2, 3, shouldFail, "more than 2 management nodes"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.ManagementNode.NodeCount = 1
+		}, shouldFail, "should not update management node count"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.Zones = []string{"zone-a", "zone-b"}
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.Zones = []string{"zone-a", "zone-c"}
+		}, shouldFail, "should not update data node zones"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.Zones = []string{"zone-a", "zone-b"}
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.Zones = []string{"zone-a", "zone-b"}
+		}, !shouldFail, "allow update if data node zones did not change"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.Arbitrator = &NdbArbitratorSpec{Zone: "zone-c"}
+		}, shouldFail, "should not add an arbitrator"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.Arbitrator = &NdbArbitratorSpec{Zone: "zone-c"}
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.Arbitrator = &NdbArbitratorSpec{Zone: "zone-d"}
+		}, !shouldFail, "allow moving the arbitrator to a different zone"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.PVCSpec = pvcSpecWithStorage("10Gi")
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.PVCSpec = pvcSpecWithStorage("20Gi")
+		}, !shouldFail, "allow increasing the data node storage"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.PVCSpec = pvcSpecWithStorage("10Gi")
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.PVCSpec = pvcSpecWithStorage("5Gi")
+		}, shouldFail, "should not reduce the data node storage"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.PVCSpec = pvcSpecWithStorage("10Gi")
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.PVCSpec = pvcSpecWithStorage("20Gi")
+			defaultSpec.DataNode.PVCSpec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+		}, shouldFail, "should not update the data node pvcSpec other than the storage"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.PVCSpec = pvcSpecWithStorage("10Gi")
+		}, shouldFail, "should not add a data node pvcSpec"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.MysqlNode = &NdbMysqldSpec{NodeCount: 1}
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.MysqlNode = &NdbMysqldSpec{NodeCount: 1, AuditLog: &NdbMysqldAuditLogSpec{}}
+		}, !shouldFail, "allow enabling the audit log without a pvcSpec"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.MysqlNode = &NdbMysqldSpec{NodeCount: 1, AuditLog: &NdbMysqldAuditLogSpec{}}
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.MysqlNode = &NdbMysqldSpec{NodeCount: 1, AuditLog: &NdbMysqldAuditLogSpec{
+				PVCSpec: pvcSpecWithStorage("1Gi"),
+			}}
+		}, shouldFail, "should not add an audit log pvcSpec"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.SeparateVolumes = &NdbDataNodeVolumesSpec{
+				Backup: pvcSpecWithStorage("10Gi"),
+			}
+		}, shouldFail, "should not update the data node separateVolumes"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.NodeCount = 6
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.NodeCount = 6
+			defaultSpec.RedundancyLevel = 3
+			defaultSpec.RedundancyLevelUpdateStrategy = NdbRedundancyLevelUpdateRecreate
+		}, !shouldFail, "allow updating redundancy via the Recreate strategy"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.NodeCount = 6
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.DataNode.NodeCount = 6
+			defaultSpec.RedundancyLevel = 3
+		}, shouldFail, "should not update redundancy without the Recreate strategy"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.RedundancyLevel = 1
+			defaultSpec.RedundancyLevelUpdateStrategy = NdbRedundancyLevelUpdateRecreate
+		}, shouldFail, "should not update redundancy to 1 even via the Recreate strategy"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.RedundancyLevel = 1
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.RedundancyLevel = 1
+			defaultSpec.DataNode.NodeCount = 3
+			defaultSpec.DataNode.SystemRestart = &NdbDataNodeSystemRestartSpec{Allowed: true}
+		}, !shouldFail, "allow updating spec with replica = 1 when the system restart is allowed"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.RedundancyLevel = 1
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.RedundancyLevelUpdateStrategy = NdbRedundancyLevelUpdateRecreate
+			defaultSpec.DataNode.SystemRestart = &NdbDataNodeSystemRestartSpec{Allowed: true}
+		}, shouldFail, "should not update redundancy from 1 even via the Recreate strategy"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			internalClass := "example.com/internal"
+			defaultSpec.MysqlNode = &NdbMysqldSpec{
+				NodeCount:    1,
+				LoadBalancer: &NdbLoadBalancerSpec{LoadBalancerClass: &internalClass},
+			}
+		}, func(defaultSpec *NdbClusterSpec) {
+			externalClass := "example.com/external"
+			defaultSpec.MysqlNode = &NdbMysqldSpec{
+				NodeCount:    1,
+				LoadBalancer: &NdbLoadBalancerSpec{LoadBalancerClass: &externalClass},
+			}
+		}, shouldFail, "should not update the loadBalancerClass"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.NdbAPIApplications = []NdbAPIApplicationSpec{
+				{Name: "loader", Namespace: "apps", NodeCount: 2},
+				{Name: "reader", Namespace: "apps", NodeCount: 1},
+			}
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.NdbAPIApplications = []NdbAPIApplicationSpec{
+				{Name: "loader", Namespace: "apps", NodeCount: 3},
+				{Name: "reader", Namespace: "apps", NodeCount: 1},
+			}
+		}, shouldFail, "should not scale an NDBAPI application followed by another"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.NdbAPIApplications = []NdbAPIApplicationSpec{{Name: "loader", Namespace: "apps", NodeCount: 2}}
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.NdbAPIApplications = []NdbAPIApplicationSpec{{Name: "loader", Namespace: "apps", NodeCount: 1}}
+		}, !shouldFail, "allow scaling down the last NDBAPI application"),
+
+		ndbUpdateNdbPodSpecTests(func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.NdbAPIApplications = []NdbAPIApplicationSpec{{Name: "loader", Namespace: "apps", NodeCount: 2}}
+		}, func(defaultSpec *NdbClusterSpec) {
+			defaultSpec.NdbAPIApplications = []NdbAPIApplicationSpec{
+				{Name: "loader", Namespace: "apps", NodeCount: 3},
+				{Name: "reader", Namespace: "apps", NodeCount: 1},
+			}
+		}, !shouldFail, "allow scaling up the last NDBAPI application and appending another"),
 	}
 
 	for _, vc := range vcs {
@@ -290,3 +1504,335 @@ func Test_Validation(t *testing.T) {
 	}
 
 }
+
+func TestIsValidSpecUpdateReservedNodeIds(t *testing.T) {
+	newNdb := func() *NdbCluster {
+		return &NdbCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "example-ndb",
+			},
+			Spec: NdbClusterSpec{
+				RedundancyLevel: 2,
+				DataNode: &NdbDataNodeSpec{
+					NodeCount: 2,
+				},
+				MysqlNode: &NdbMysqldSpec{
+					NodeCount:          2,
+					MaxNodeCount:       4,
+					ConnectionPoolSize: 1,
+					ServerGroups: []NdbMysqldServerGroupSpec{
+						{Name: "olap", NodeCount: 2},
+					},
+				},
+				FreeAPISlots: 2,
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		update     func(nc *NdbCluster)
+		shouldFail bool
+	}{
+		{
+			name:   "no changes",
+			update: func(nc *NdbCluster) {},
+		},
+		{
+			name: "MySQL Servers scaled up",
+			update: func(nc *NdbCluster) {
+				nc.Spec.MysqlNode.NodeCount = 4
+			},
+		},
+		{
+			name: "MySQL Servers moved to new nodeIds by the connection pool",
+			update: func(nc *NdbCluster) {
+				nc.Spec.MysqlNode.ServerGroups = nil
+				nc.Spec.MysqlNode.ConnectionPoolSize = 2
+			},
+		},
+		{
+			name: "removed MySQL Server group",
+			update: func(nc *NdbCluster) {
+				nc.Spec.MysqlNode.ServerGroups = nil
+				nc.Spec.FreeAPISlots = 4
+			},
+		},
+		{
+			name: "MySQL Server group nodeIds reserved for the free API slots",
+			update: func(nc *NdbCluster) {
+				nc.Spec.FreeAPISlots = 3
+			},
+			shouldFail: true,
+		},
+		{
+			name: "MySQL Server group nodeIds no longer reserved",
+			update: func(nc *NdbCluster) {
+				nc.Spec.MysqlNode.MaxNodeCount = 2
+			},
+			shouldFail: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Record the nodes of the existing MySQL Cluster in the status
+			oldNdb := newNdb()
+			for _, node := range oldNdb.getReservedNodeIds() {
+				if node.NodeType != constants.NdbNodeTypeAPI {
+					oldNdb.Status.Nodes = append(oldNdb.Status.Nodes, node)
+				}
+			}
+
+			ndb := newNdb()
+			tc.update(ndb)
+			isValid, errList := oldNdb.IsValidSpecUpdate(ndb)
+			if tc.shouldFail && isValid {
+				t.Error("Should fail with error but didn't")
+			} else if !tc.shouldFail && !isValid {
+				t.Errorf("Should pass but failed : %s", errList.ToAggregate())
+			}
+		})
+	}
+}
+
+func TestGetMySQLServerGroupNodeIds(t *testing.T) {
+	const apiStart = constants.NdbNodeTypeAPIStartNodeId
+	nc := &NdbCluster{
+		Spec: NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			MysqlNode: &NdbMysqldSpec{
+				NodeCount:          2,
+				MaxNodeCount:       2,
+				ConnectionPoolSize: 1,
+				ServerGroups: []NdbMysqldServerGroupSpec{
+					{Name: "olap", NodeCount: 2},
+					{Name: "oltp", NodeCount: 1},
+				},
+			},
+			FreeAPISlots: 1,
+		},
+	}
+
+	expectRanges := func(desc string, expected ...NdbMysqldServerGroupStatus) {
+		t.Helper()
+		ranges := nc.GetMySQLServerGroupNodeIds()
+		if len(ranges) != len(expected) {
+			t.Fatalf("%s : expected %d ranges but got %#v", desc, len(expected), ranges)
+		}
+		for i := range expected {
+			if ranges[i] != expected[i] {
+				t.Errorf("%s : expected %#v but got %#v", desc, expected[i], ranges[i])
+			}
+		}
+		nc.Status.MySQLServerGroups = ranges
+	}
+
+	// The groups get the nodeIds following the MySQL Servers and the free API slots
+	expectRanges("first allocation",
+		NdbMysqldServerGroupStatus{Name: "olap", StartNodeId: apiStart + 3, NumOfNodeIds: 2},
+		NdbMysqldServerGroupStatus{Name: "oltp", StartNodeId: apiStart + 5, NumOfNodeIds: 1})
+
+	// Freeing up the nodeIds before the groups doesn't move them
+	nc.Spec.FreeAPISlots = 0
+	expectRanges("free API slots removed",
+		NdbMysqldServerGroupStatus{Name: "olap", StartNodeId: apiStart + 3, NumOfNodeIds: 2},
+		NdbMysqldServerGroupStatus{Name: "oltp", StartNodeId: apiStart + 5, NumOfNodeIds: 1})
+
+	// A group that outgrows its range is moved to the first free range
+	nc.Spec.MysqlNode.ServerGroups[1].NodeCount = 3
+	expectRanges("group scaled up",
+		NdbMysqldServerGroupStatus{Name: "olap", StartNodeId: apiStart + 3, NumOfNodeIds: 2},
+		NdbMysqldServerGroupStatus{Name: "oltp", StartNodeId: apiStart + 5, NumOfNodeIds: 3})
+
+	// A group whose range overlaps the free API slots is moved
+	nc.Spec.FreeAPISlots = 2
+	expectRanges("free API slots overlapping a group",
+		NdbMysqldServerGroupStatus{Name: "olap", StartNodeId: apiStart + 8, NumOfNodeIds: 2},
+		NdbMysqldServerGroupStatus{Name: "oltp", StartNodeId: apiStart + 5, NumOfNodeIds: 3})
+
+	// A range beyond the available nodeIds is rejected by the validation
+	nc.Spec.MysqlNode.ServerGroups[0].NodeCount = constants.MaxNumberOfNodes
+	if isValid, _ := nc.HasValidSpec(); isValid {
+		t.Error("A MySQL Server group that cannot be allocated nodeIds should be rejected")
+	}
+}
+
+func TestGetSpecWarnings(t *testing.T) {
+	config := func(params map[string]intstr.IntOrString) map[string]*intstr.IntOrString {
+		c := make(map[string]*intstr.IntOrString)
+		for k := range params {
+			v := params[k]
+			c[k] = &v
+		}
+		return c
+	}
+
+	testcases := []struct {
+		desc             string
+		threadConfig     string
+		config           map[string]*intstr.IntOrString
+		mysqldSpec       *NdbMysqldSpec
+		expectedWarnings int
+	}{
+		{
+			desc:         "log parts evenly distributed among the LDM threads",
+			threadConfig: "ldm={count=4},tc,main,recv",
+			config:       config(map[string]intstr.IntOrString{"NoOfFragmentLogParts": intstr.FromInt(8)}),
+		},
+		{
+			desc:             "log parts not a multiple of the LDM threads",
+			threadConfig:     "ldm={count=2,cpubind=1,2},ldm,main,recv",
+			config:           config(map[string]intstr.IntOrString{"NoOfFragmentLogParts": intstr.FromInt(4)}),
+			expectedWarnings: 1,
+		},
+		{
+			desc:   "log parts without an explicit ThreadConfig",
+			config: config(map[string]intstr.IntOrString{"NoOfFragmentLogParts": intstr.FromInt(3)}),
+		},
+		{
+			desc:   "sufficient DataMemory",
+			config: config(map[string]intstr.IntOrString{"datamemory": intstr.FromString("2G")}),
+		},
+		{
+			desc:             "very small DataMemory in bytes",
+			config:           config(map[string]intstr.IntOrString{"DataMemory": intstr.FromInt(16 * 1024 * 1024)}),
+			expectedWarnings: 1,
+		},
+		{
+			desc: "deprecated config params",
+			config: config(map[string]intstr.IntOrString{
+				"IndexMemory":              intstr.FromString("32M"),
+				"ReservedSendBufferMemory": intstr.FromInt(0),
+			}),
+			expectedWarnings: 2,
+		},
+		{
+			desc: "known and loose MySQL Server options",
+			mysqldSpec: &NdbMysqldSpec{
+				MyCnf: "[mysqld]\nmax-user-connections=42\nloose-my-plugin-option=1\n",
+			},
+		},
+		{
+			desc: "unknown MySQL Server options",
+			mysqldSpec: &NdbMysqldSpec{
+				MyCnf: "[mysqld]\nmysql_native_password=ON\n",
+				ServerGroups: []NdbMysqldServerGroupSpec{
+					{Name: "reporting", NodeCount: 1, MyCnf: "maqx-user-connections=1"},
+				},
+			},
+			expectedWarnings: 2,
+		},
+	}
+
+	for _, tc := range testcases {
+		nc := &NdbCluster{
+			Spec: NdbClusterSpec{
+				RedundancyLevel: 2,
+				DataNode: &NdbDataNodeSpec{
+					NodeCount:    2,
+					Config:       tc.config,
+					ThreadConfig: tc.threadConfig,
+				},
+				MysqlNode: tc.mysqldSpec,
+			},
+		}
+
+		if warnings := nc.GetSpecWarnings(true); len(warnings) != tc.expectedWarnings {
+			t.Errorf("Testcase %q failed : expected %d warnings but got %v", tc.desc, tc.expectedWarnings, warnings)
+		}
+	}
+}
+
+func TestGetDiskDataFileSizes(t *testing.T) {
+	const mi = 1024 * 1024
+	undoFileSize := resource.MustParse("100Mi")
+	nc := &NdbCluster{
+		Spec: NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+				PVCSpec: &corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+					},
+				},
+				DiskData: &NdbDiskDataSpec{
+					LogfileGroup: NdbLogfileGroupSpec{
+						Name: "lg_1",
+						UndoFiles: []NdbDiskDataFileSpec{
+							{Name: "undo_1.log", Size: &undoFileSize},
+							{Name: "undo_2.log"},
+						},
+					},
+					Tablespaces: []NdbTablespaceSpec{
+						{Name: "ts_1", DataFiles: []NdbDiskDataFileSpec{{Name: "data_1.dat"}}},
+						{Name: "ts_2", DataFiles: []NdbDiskDataFileSpec{{Name: "data_2.dat"}, {Name: "data_3.dat"}}},
+					},
+				},
+			},
+		},
+	}
+
+	expectSizes := func(desc string, existingFileSizes, expected map[string]int64) {
+		t.Helper()
+		sizes := nc.GetDiskDataFileSizes(existingFileSizes)
+		if len(sizes) != len(expected) {
+			t.Fatalf("%s : expected the sizes %v but got %v", desc, expected, sizes)
+		}
+		for fileName, size := range expected {
+			if sizes[fileName] != size {
+				t.Errorf("%s : expected the size of %q to be %d but got %d", desc, fileName, size, sizes[fileName])
+			}
+		}
+	}
+
+	// The undo files share 1Gi and the data files share 4Gi of the 10Gi
+	// PVC storage, and the sizes are rounded down to a multiple of 1Mi
+	expectSizes("files in the data node PVCs", nil, map[string]int64{
+		"undo_1.log": 100 * mi,
+		"undo_2.log": 924 * mi,
+		"data_1.dat": 1365 * mi,
+		"data_2.dat": 1365 * mi,
+		"data_3.dat": 1365 * mi,
+	})
+
+	// The existing files retain their sizes
+	expectSizes("existing files", map[string]int64{"undo_2.log": 500 * mi, "data_1.dat": 2048 * mi},
+		map[string]int64{
+			"undo_1.log": 100 * mi,
+			"undo_2.log": 500 * mi,
+			"data_1.dat": 2048 * mi,
+			"data_2.dat": 1024 * mi,
+			"data_3.dat": 1024 * mi,
+		})
+
+	// The undo files share the storage of the separate undo files volumes
+	nc.Spec.DataNode.SeparateVolumes = &NdbDataNodeVolumesSpec{
+		UndoFiles: &corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("2Gi")},
+			},
+		},
+	}
+	expectSizes("undo files in separate volumes", nil, map[string]int64{
+		"undo_1.log": 100 * mi,
+		"undo_2.log": 1948 * mi,
+		"data_1.dat": 1706 * mi,
+		"data_2.dat": 1706 * mi,
+		"data_3.dat": 1706 * mi,
+	})
+
+	// The files cannot be sized if the PVCs request no storage
+	nc.Spec.DataNode.PVCSpec = nil
+	expectSizes("no data node PVCs", nil, map[string]int64{
+		"undo_1.log": 100 * mi,
+		"undo_2.log": 1948 * mi,
+		"data_1.dat": 0,
+		"data_2.dat": 0,
+		"data_3.dat": 0,
+	})
+}
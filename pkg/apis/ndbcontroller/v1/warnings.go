@@ -0,0 +1,195 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package v1
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mysql/ndb-operator/pkg/ndbconfig/configparser"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// minRecommendedDataMemory is the DataMemory, in bytes, below which the
+// data nodes are likely to run out of memory with any real workload
+const minRecommendedDataMemory = 64 * 1024 * 1024
+
+// deprecatedDataNodeConfigParams are the data node config params that
+// are deprecated in MySQL Cluster, keyed by their lowercase names, and
+// the details to be appended to the warning.
+var deprecatedDataNodeConfigParams = map[string]string{
+	"indexmemory":              "The hash indexes are stored in the DataMemory.",
+	"reservedsendbuffermemory": "It is ignored by the data nodes.",
+}
+
+// getDataNodeConfigParam returns the value of the given param from
+// the spec.dataNode.config, matching its name case-insensitively.
+func (nc *NdbCluster) getDataNodeConfigParam(param string) (key string, value *intstr.IntOrString) {
+	for configKey, configValue := range nc.Spec.DataNode.Config {
+		if strings.EqualFold(configKey, param) {
+			return configKey, configValue
+		}
+	}
+	return "", nil
+}
+
+// parseNdbMemorySize parses a memory size config param of MySQL Cluster,
+// which is either a number of bytes or a number with a K, M or G suffix.
+func parseNdbMemorySize(value *intstr.IntOrString) (int64, bool) {
+	if value.Type == intstr.Int {
+		return int64(value.IntVal), true
+	}
+
+	size := strings.TrimSpace(value.StrVal)
+	multiplier := int64(1)
+	if size != "" {
+		switch strings.ToUpper(size[len(size)-1:]) {
+		case "K":
+			multiplier = 1024
+		case "M":
+			multiplier = 1024 * 1024
+		case "G":
+			multiplier = 1024 * 1024 * 1024
+		}
+		if multiplier != 1 {
+			size = size[:len(size)-1]
+		}
+	}
+
+	number, err := strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return number * multiplier, true
+}
+
+// getLDMThreadCount returns the number of LDM threads specified by the
+// spec.dataNode.threadConfig, or false if the ThreadConfig does not
+// specify them explicitly.
+func (nc *NdbCluster) getLDMThreadCount() (int, bool) {
+	entries, ok := splitThreadConfig(nc.Spec.DataNode.ThreadConfig)
+	if nc.Spec.DataNode.ThreadConfig == "" || !ok {
+		return 0, false
+	}
+
+	ldmThreads := 0
+	for _, entry := range entries {
+		threadType, properties, _ := strings.Cut(strings.TrimSpace(entry), "=")
+		if !strings.EqualFold(strings.TrimSpace(threadType), "ldm") {
+			continue
+		}
+
+		// Every entry without a count property runs a single thread
+		count := 1
+		properties = strings.Trim(strings.TrimSpace(properties), "{}")
+		for _, property := range strings.Split(properties, ",") {
+			name, value, _ := strings.Cut(property, "=")
+			if strings.EqualFold(strings.TrimSpace(name), "count") {
+				if n, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+					count = n
+				}
+			}
+		}
+		ldmThreads += count
+	}
+
+	return ldmThreads, ldmThreads != 0
+}
+
+// getMyCnfWarnings returns the warnings about the options, in the my.cnf
+// of the MySQL Servers and of the MySQL Server groups, that are not known
+// options of the MySQL Server. Such options might be valid in the MySQL
+// Server version being run, but prevent the MySQL Servers from starting
+// otherwise.
+func (nc *NdbCluster) getMyCnfWarnings(mysqldPath string) (warnings []string) {
+	checkMyCnf := func(myCnfString, myCnfPath string) {
+		myCnf, err := configparser.ParseString(myCnfString)
+		if myCnfString == "" || err != nil {
+			// Nothing to check or an invalid my.cnf rejected by the validation
+			return
+		}
+
+		for _, option := range getMyCnfOptions(myCnf) {
+			if name, loose := normalizeMySQLServerOption(option); !loose && !isKnownMySQLServerOption(name) {
+				warnings = append(warnings, fmt.Sprintf(
+					"%s has the unknown MySQL Server option %q, which would prevent the MySQL Servers "+
+						"from starting unless it is supported by the MySQL Server version being run : "+
+						"prefix it with 'loose-' if it is an option of a plugin or a component",
+					myCnfPath, option))
+			}
+		}
+	}
+
+	checkMyCnf(nc.GetMySQLCnf(), mysqldPath+".myCnf")
+	for i, serverGroup := range nc.GetMySQLServerGroups() {
+		checkMyCnf(serverGroup.GetMySQLCnf(), fmt.Sprintf("%s.serverGroups[%d].myCnf", mysqldPath, i))
+	}
+	return warnings
+}
+
+// GetSpecWarnings returns the warnings about the settings in the NdbCluster
+// spec that are deprecated or risky but still valid. The warnings do not
+// prevent the NdbCluster from being created or updated, and are returned
+// to the client by the webhook. The productionNamespace should be true if
+// the NdbCluster is in a namespace marked as a production namespace.
+func (nc *NdbCluster) GetSpecWarnings(productionNamespace bool) (warnings []string) {
+	specPath := "spec"
+	dataNodeConfigPath := specPath + ".dataNode.config"
+
+	// A single replica of the data cannot survive the failure of a data node
+	if productionNamespace && nc.Spec.RedundancyLevel == 1 {
+		warnings = append(warnings, fmt.Sprintf(
+			"%s.redundancyLevel is 1 in a production namespace : "+
+				"the MySQL Cluster will be unavailable and can lose data when any data node fails", specPath))
+	}
+
+	// The unknown options in the my.cnf of the MySQL Servers
+	warnings = append(warnings, nc.getMyCnfWarnings(specPath+".mysqlNode")...)
+
+	if nc.Spec.DataNode == nil {
+		return warnings
+	}
+
+	// The redo log parts should be evenly distributed among the LDM threads
+	if key, value := nc.getDataNodeConfigParam("NoOfFragmentLogParts"); value != nil {
+		if ldmThreads, specified := nc.getLDMThreadCount(); specified {
+			if logParts := value.IntValue(); logParts > 0 && (logParts < ldmThreads || logParts%ldmThreads != 0) {
+				warnings = append(warnings, fmt.Sprintf(
+					"%s.%s is %d, which is not a multiple of the %d LDM threads specified by %s.dataNode.threadConfig : "+
+						"the redo log parts will not be evenly distributed among the LDM threads",
+					dataNodeConfigPath, key, logParts, ldmThreads, specPath))
+			}
+		}
+	}
+
+	// A very small DataMemory would be exhausted by any real workload
+	if key, value := nc.getDataNodeConfigParam("DataMemory"); value != nil {
+		if dataMemory, ok := parseNdbMemorySize(value); ok && dataMemory < minRecommendedDataMemory {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s.%s is %s, which is less than the recommended minimum of %dM : "+
+					"the data nodes might run out of memory",
+				dataNodeConfigPath, key, value.String(), minRecommendedDataMemory/(1024*1024)))
+		}
+	}
+
+	// Warn about the deprecated config params in a deterministic order
+	var deprecatedWarnings []string
+	for configKey := range nc.Spec.DataNode.Config {
+		if details, deprecated := deprecatedDataNodeConfigParams[strings.ToLower(configKey)]; deprecated {
+			warning := fmt.Sprintf("%s.%s is deprecated in MySQL Cluster and will be removed in a future release.",
+				dataNodeConfigPath, configKey)
+			if details != "" {
+				warning += " " + details
+			}
+			deprecatedWarnings = append(deprecatedWarnings, warning)
+		}
+	}
+	sort.Strings(deprecatedWarnings)
+
+	return append(warnings, deprecatedWarnings...)
+}
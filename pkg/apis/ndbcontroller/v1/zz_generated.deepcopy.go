@@ -10,11 +10,68 @@
 package v1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbAPIApplicationSpec) DeepCopyInto(out *NdbAPIApplicationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbAPIApplicationSpec.
+func (in *NdbAPIApplicationSpec) DeepCopy() *NdbAPIApplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbAPIApplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbArbitratorSpec) DeepCopyInto(out *NdbArbitratorSpec) {
+	*out = *in
+	if in.NdbPodSpec != nil {
+		in, out := &in.NdbPodSpec, &out.NdbPodSpec
+		*out = new(NdbClusterPodSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbArbitratorSpec.
+func (in *NdbArbitratorSpec) DeepCopy() *NdbArbitratorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbArbitratorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbBackupSpec) DeepCopyInto(out *NdbBackupSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbBackupSpec.
+func (in *NdbBackupSpec) DeepCopy() *NdbBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NdbCluster) DeepCopyInto(out *NdbCluster) {
 	*out = *in
@@ -60,6 +117,160 @@ func (in *NdbClusterCondition) DeepCopy() *NdbClusterCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterConfigRolloutStatus) DeepCopyInto(out *NdbClusterConfigRolloutStatus) {
+	*out = *in
+	if in.ManagementNodes != nil {
+		in, out := &in.ManagementNodes, &out.ManagementNodes
+		*out = new(NdbNodesConfigRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataNodes != nil {
+		in, out := &in.DataNodes, &out.DataNodes
+		*out = new(NdbNodesConfigRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MySQLServers != nil {
+		in, out := &in.MySQLServers, &out.MySQLServers
+		*out = new(NdbNodesConfigRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterConfigRolloutStatus.
+func (in *NdbClusterConfigRolloutStatus) DeepCopy() *NdbClusterConfigRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterConfigRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterDatabaseRewrite) DeepCopyInto(out *NdbClusterDatabaseRewrite) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterDatabaseRewrite.
+func (in *NdbClusterDatabaseRewrite) DeepCopy() *NdbClusterDatabaseRewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterDatabaseRewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterEpochStatus) DeepCopyInto(out *NdbClusterEpochStatus) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterEpochStatus.
+func (in *NdbClusterEpochStatus) DeepCopy() *NdbClusterEpochStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterEpochStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterHealthMonitoringSpec) DeepCopyInto(out *NdbClusterHealthMonitoringSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterHealthMonitoringSpec.
+func (in *NdbClusterHealthMonitoringSpec) DeepCopy() *NdbClusterHealthMonitoringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterHealthMonitoringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterHealthSnapshot) DeepCopyInto(out *NdbClusterHealthSnapshot) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterHealthSnapshot.
+func (in *NdbClusterHealthSnapshot) DeepCopy() *NdbClusterHealthSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterHealthSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterInitFromBackupSpec) DeepCopyInto(out *NdbClusterInitFromBackupSpec) {
+	*out = *in
+	if in.IncludeDatabases != nil {
+		in, out := &in.IncludeDatabases, &out.IncludeDatabases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeDatabases != nil {
+		in, out := &in.ExcludeDatabases, &out.ExcludeDatabases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IncludeTables != nil {
+		in, out := &in.IncludeTables, &out.IncludeTables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeTables != nil {
+		in, out := &in.ExcludeTables, &out.ExcludeTables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RewriteDatabases != nil {
+		in, out := &in.RewriteDatabases, &out.RewriteDatabases
+		*out = make([]NdbClusterDatabaseRewrite, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterInitFromBackupSpec.
+func (in *NdbClusterInitFromBackupSpec) DeepCopy() *NdbClusterInitFromBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterInitFromBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterInitFromDumpSpec) DeepCopyInto(out *NdbClusterInitFromDumpSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterInitFromDumpSpec.
+func (in *NdbClusterInitFromDumpSpec) DeepCopy() *NdbClusterInitFromDumpSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterInitFromDumpSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NdbClusterList) DeepCopyInto(out *NdbClusterList) {
 	*out = *in
@@ -93,6 +304,22 @@ func (in *NdbClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterNodeStatus) DeepCopyInto(out *NdbClusterNodeStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterNodeStatus.
+func (in *NdbClusterNodeStatus) DeepCopy() *NdbClusterNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NdbClusterPodSpec) DeepCopyInto(out *NdbClusterPodSpec) {
 	*out = *in
@@ -120,6 +347,34 @@ func (in *NdbClusterPodSpec) DeepCopyInto(out *NdbClusterPodSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SidecarContainers != nil {
+		in, out := &in.SidecarContainers, &out.SidecarContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -134,132 +389,972 @@ func (in *NdbClusterPodSpec) DeepCopy() *NdbClusterPodSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NdbClusterSpec) DeepCopyInto(out *NdbClusterSpec) {
+func (in *NdbClusterPolicy) DeepCopyInto(out *NdbClusterPolicy) {
 	*out = *in
-	if in.ManagementNode != nil {
-		in, out := &in.ManagementNode, &out.ManagementNode
-		*out = new(NdbManagementNodeSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.DataNode != nil {
-		in, out := &in.DataNode, &out.DataNode
-		*out = new(NdbDataNodeSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.MysqlNode != nil {
-		in, out := &in.MysqlNode, &out.MysqlNode
-		*out = new(NdbMysqldSpec)
-		(*in).DeepCopyInto(*out)
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterSpec.
-func (in *NdbClusterSpec) DeepCopy() *NdbClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterPolicy.
+func (in *NdbClusterPolicy) DeepCopy() *NdbClusterPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(NdbClusterSpec)
+	out := new(NdbClusterPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NdbClusterPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NdbClusterStatus) DeepCopyInto(out *NdbClusterStatus) {
+func (in *NdbClusterPolicyConstraints) DeepCopyInto(out *NdbClusterPolicyConstraints) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]NdbClusterCondition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.AllowedImages != nil {
+		in, out := &in.AllowedImages, &out.AllowedImages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterStatus.
-func (in *NdbClusterStatus) DeepCopy() *NdbClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterPolicyConstraints.
+func (in *NdbClusterPolicyConstraints) DeepCopy() *NdbClusterPolicyConstraints {
 	if in == nil {
 		return nil
 	}
-	out := new(NdbClusterStatus)
+	out := new(NdbClusterPolicyConstraints)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NdbDataNodeSpec) DeepCopyInto(out *NdbDataNodeSpec) {
+func (in *NdbClusterPolicyDefaults) DeepCopyInto(out *NdbClusterPolicyDefaults) {
 	*out = *in
-	if in.Config != nil {
-		in, out := &in.Config, &out.Config
-		*out = make(map[string]*intstr.IntOrString, len(*in))
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodLabels != nil {
+		in, out := &in.PodLabels, &out.PodLabels
+		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
-			var outVal *intstr.IntOrString
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = new(intstr.IntOrString)
-				**out = **in
-			}
-			(*out)[key] = outVal
+			(*out)[key] = val
 		}
 	}
-	if in.NdbPodSpec != nil {
-		in, out := &in.NdbPodSpec, &out.NdbPodSpec
-		*out = new(NdbClusterPodSpec)
-		(*in).DeepCopyInto(*out)
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.PVCSpec != nil {
-		in, out := &in.PVCSpec, &out.PVCSpec
-		*out = new(corev1.PersistentVolumeClaimSpec)
-		(*in).DeepCopyInto(*out)
+	if in.ServiceAnnotations != nil {
+		in, out := &in.ServiceAnnotations, &out.ServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbDataNodeSpec.
-func (in *NdbDataNodeSpec) DeepCopy() *NdbDataNodeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterPolicyDefaults.
+func (in *NdbClusterPolicyDefaults) DeepCopy() *NdbClusterPolicyDefaults {
 	if in == nil {
 		return nil
 	}
-	out := new(NdbDataNodeSpec)
+	out := new(NdbClusterPolicyDefaults)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NdbManagementNodeSpec) DeepCopyInto(out *NdbManagementNodeSpec) {
+func (in *NdbClusterPolicyList) DeepCopyInto(out *NdbClusterPolicyList) {
 	*out = *in
-	if in.Config != nil {
-		in, out := &in.Config, &out.Config
-		*out = make(map[string]*intstr.IntOrString, len(*in))
-		for key, val := range *in {
-			var outVal *intstr.IntOrString
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = new(intstr.IntOrString)
-				**out = **in
-			}
-			(*out)[key] = outVal
-		}
-	}
-	if in.NdbPodSpec != nil {
-		in, out := &in.NdbPodSpec, &out.NdbPodSpec
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NdbClusterPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterPolicyList.
+func (in *NdbClusterPolicyList) DeepCopy() *NdbClusterPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NdbClusterPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterPolicySpec) DeepCopyInto(out *NdbClusterPolicySpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new(NdbClusterPolicyDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Constraints != nil {
+		in, out := &in.Constraints, &out.Constraints
+		*out = new(NdbClusterPolicyConstraints)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterPolicySpec.
+func (in *NdbClusterPolicySpec) DeepCopy() *NdbClusterPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterSpec) DeepCopyInto(out *NdbClusterSpec) {
+	*out = *in
+	if in.ManagementNode != nil {
+		in, out := &in.ManagementNode, &out.ManagementNode
+		*out = new(NdbManagementNodeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataNode != nil {
+		in, out := &in.DataNode, &out.DataNode
+		*out = new(NdbDataNodeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MysqlNode != nil {
+		in, out := &in.MysqlNode, &out.MysqlNode
+		*out = new(NdbMysqldSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Arbitrator != nil {
+		in, out := &in.Arbitrator, &out.Arbitrator
+		*out = new(NdbArbitratorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NdbAPIApplications != nil {
+		in, out := &in.NdbAPIApplications, &out.NdbAPIApplications
+		*out = make([]NdbAPIApplicationSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodLabels != nil {
+		in, out := &in.PodLabels, &out.PodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ServiceAnnotations != nil {
+		in, out := &in.ServiceAnnotations, &out.ServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IPFamilyPolicy != nil {
+		in, out := &in.IPFamilyPolicy, &out.IPFamilyPolicy
+		*out = new(corev1.IPFamilyPolicy)
+		**out = **in
+	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]corev1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NdbNetworkPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccount != nil {
+		in, out := &in.ServiceAccount, &out.ServiceAccount
+		*out = new(NdbServiceAccountSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitFromBackup != nil {
+		in, out := &in.InitFromBackup, &out.InitFromBackup
+		*out = new(NdbClusterInitFromBackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitFromDump != nil {
+		in, out := &in.InitFromDump, &out.InitFromDump
+		*out = new(NdbClusterInitFromDumpSpec)
+		**out = **in
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(NdbBackupSpec)
+		**out = **in
+	}
+	if in.HealthMonitoring != nil {
+		in, out := &in.HealthMonitoring, &out.HealthMonitoring
+		*out = new(NdbClusterHealthMonitoringSpec)
+		**out = **in
+	}
+	if in.PodDNS != nil {
+		in, out := &in.PodDNS, &out.PodDNS
+		*out = new(NdbPodDNSSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterSpec.
+func (in *NdbClusterSpec) DeepCopy() *NdbClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterStatus) DeepCopyInto(out *NdbClusterStatus) {
+	*out = *in
+	if in.ConfigRollout != nil {
+		in, out := &in.ConfigRollout, &out.ConfigRollout
+		*out = new(NdbClusterConfigRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingChanges != nil {
+		in, out := &in.PendingChanges, &out.PendingChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]NdbClusterCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(NdbClusterHealthSnapshot)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LatestEpoch != nil {
+		in, out := &in.LatestEpoch, &out.LatestEpoch
+		*out = new(NdbClusterEpochStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NdbClusterNodeStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.MySQLServerGroups != nil {
+		in, out := &in.MySQLServerGroups, &out.MySQLServerGroups
+		*out = make([]NdbMysqldServerGroupStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterStatus.
+func (in *NdbClusterStatus) DeepCopy() *NdbClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbDataNodeLocalVolumesSpec) DeepCopyInto(out *NdbDataNodeLocalVolumesSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbDataNodeLocalVolumesSpec.
+func (in *NdbDataNodeLocalVolumesSpec) DeepCopy() *NdbDataNodeLocalVolumesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbDataNodeLocalVolumesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbDataNodeLogLevels) DeepCopyInto(out *NdbDataNodeLogLevels) {
+	*out = *in
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Shutdown != nil {
+		in, out := &in.Shutdown, &out.Shutdown
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Statistic != nil {
+		in, out := &in.Statistic, &out.Statistic
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Checkpoint != nil {
+		in, out := &in.Checkpoint, &out.Checkpoint
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodeRestart != nil {
+		in, out := &in.NodeRestart, &out.NodeRestart
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Connection != nil {
+		in, out := &in.Connection, &out.Connection
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Congestion != nil {
+		in, out := &in.Congestion, &out.Congestion
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Error != nil {
+		in, out := &in.Error, &out.Error
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Info != nil {
+		in, out := &in.Info, &out.Info
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbDataNodeLogLevels.
+func (in *NdbDataNodeLogLevels) DeepCopy() *NdbDataNodeLogLevels {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbDataNodeLogLevels)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbDataNodeRemediationSpec) DeepCopyInto(out *NdbDataNodeRemediationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbDataNodeRemediationSpec.
+func (in *NdbDataNodeRemediationSpec) DeepCopy() *NdbDataNodeRemediationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbDataNodeRemediationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbDataNodeSpec) DeepCopyInto(out *NdbDataNodeSpec) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]*intstr.IntOrString, len(*in))
+		for key, val := range *in {
+			var outVal *intstr.IntOrString
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(intstr.IntOrString)
+				**out = **in
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.LogLevels != nil {
+		in, out := &in.LogLevels, &out.LogLevels
+		*out = new(NdbDataNodeLogLevels)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NdbPodSpec != nil {
+		in, out := &in.NdbPodSpec, &out.NdbPodSpec
 		*out = new(NdbClusterPodSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PVCSpec != nil {
+		in, out := &in.PVCSpec, &out.PVCSpec
+		*out = new(corev1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SeparateVolumes != nil {
+		in, out := &in.SeparateVolumes, &out.SeparateVolumes
+		*out = new(NdbDataNodeVolumesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LocalVolumes != nil {
+		in, out := &in.LocalVolumes, &out.LocalVolumes
+		*out = new(NdbDataNodeLocalVolumesSpec)
+		**out = **in
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(NdbStartupProbeSpec)
+		**out = **in
+	}
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(NdbDataNodeRemediationSpec)
+		**out = **in
+	}
+	if in.SystemRestart != nil {
+		in, out := &in.SystemRestart, &out.SystemRestart
+		*out = new(NdbDataNodeSystemRestartSpec)
+		**out = **in
+	}
+	if in.DiskData != nil {
+		in, out := &in.DiskData, &out.DiskData
+		*out = new(NdbDiskDataSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodLabels != nil {
+		in, out := &in.PodLabels, &out.PodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ServiceAnnotations != nil {
+		in, out := &in.ServiceAnnotations, &out.ServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HugePages != nil {
+		in, out := &in.HugePages, &out.HugePages
+		*out = new(NdbHugePagesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LockPagesInMainMemory != nil {
+		in, out := &in.LockPagesInMainMemory, &out.LockPagesInMainMemory
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Numa != nil {
+		in, out := &in.Numa, &out.Numa
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbDataNodeSpec.
+func (in *NdbDataNodeSpec) DeepCopy() *NdbDataNodeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbDataNodeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbDataNodeSystemRestartSpec) DeepCopyInto(out *NdbDataNodeSystemRestartSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbDataNodeSystemRestartSpec.
+func (in *NdbDataNodeSystemRestartSpec) DeepCopy() *NdbDataNodeSystemRestartSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbDataNodeSystemRestartSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbDataNodeVolumesSpec) DeepCopyInto(out *NdbDataNodeVolumesSpec) {
+	*out = *in
+	if in.FileSystem != nil {
+		in, out := &in.FileSystem, &out.FileSystem
+		*out = new(corev1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(corev1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UndoFiles != nil {
+		in, out := &in.UndoFiles, &out.UndoFiles
+		*out = new(corev1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbDataNodeVolumesSpec.
+func (in *NdbDataNodeVolumesSpec) DeepCopy() *NdbDataNodeVolumesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbDataNodeVolumesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbDiskDataFileSpec) DeepCopyInto(out *NdbDiskDataFileSpec) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbDiskDataFileSpec.
+func (in *NdbDiskDataFileSpec) DeepCopy() *NdbDiskDataFileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbDiskDataFileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbDiskDataSpec) DeepCopyInto(out *NdbDiskDataSpec) {
+	*out = *in
+	in.LogfileGroup.DeepCopyInto(&out.LogfileGroup)
+	if in.Tablespaces != nil {
+		in, out := &in.Tablespaces, &out.Tablespaces
+		*out = make([]NdbTablespaceSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbDiskDataSpec.
+func (in *NdbDiskDataSpec) DeepCopy() *NdbDiskDataSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbDiskDataSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbHugePagesSpec) DeepCopyInto(out *NdbHugePagesSpec) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbHugePagesSpec.
+func (in *NdbHugePagesSpec) DeepCopy() *NdbHugePagesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbHugePagesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbLoadBalancerSpec) DeepCopyInto(out *NdbLoadBalancerSpec) {
+	*out = *in
+	if in.LoadBalancerClass != nil {
+		in, out := &in.LoadBalancerClass, &out.LoadBalancerClass
+		*out = new(string)
+		**out = **in
+	}
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbLoadBalancerSpec.
+func (in *NdbLoadBalancerSpec) DeepCopy() *NdbLoadBalancerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbLoadBalancerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbLogfileGroupSpec) DeepCopyInto(out *NdbLogfileGroupSpec) {
+	*out = *in
+	if in.UndoBufferSize != nil {
+		in, out := &in.UndoBufferSize, &out.UndoBufferSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.UndoFiles != nil {
+		in, out := &in.UndoFiles, &out.UndoFiles
+		*out = make([]NdbDiskDataFileSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbLogfileGroupSpec.
+func (in *NdbLogfileGroupSpec) DeepCopy() *NdbLogfileGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbLogfileGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbManagementNodeSpec) DeepCopyInto(out *NdbManagementNodeSpec) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]*intstr.IntOrString, len(*in))
+		for key, val := range *in {
+			var outVal *intstr.IntOrString
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(intstr.IntOrString)
+				**out = **in
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.NdbPodSpec != nil {
+		in, out := &in.NdbPodSpec, &out.NdbPodSpec
+		*out = new(NdbClusterPodSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LoadBalancer != nil {
+		in, out := &in.LoadBalancer, &out.LoadBalancer
+		*out = new(NdbLoadBalancerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(NdbPodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodLabels != nil {
+		in, out := &in.PodLabels, &out.PodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ServiceAnnotations != nil {
+		in, out := &in.ServiceAnnotations, &out.ServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(appsv1.StatefulSetUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbManagementNodeSpec.
+func (in *NdbManagementNodeSpec) DeepCopy() *NdbManagementNodeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbManagementNodeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMysqldAuditLogSpec) DeepCopyInto(out *NdbMysqldAuditLogSpec) {
+	*out = *in
+	if in.RotateOnSize != nil {
+		in, out := &in.RotateOnSize, &out.RotateOnSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.PVCSpec != nil {
+		in, out := &in.PVCSpec, &out.PVCSpec
+		*out = new(corev1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SidecarContainers != nil {
+		in, out := &in.SidecarContainers, &out.SidecarContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMysqldAuditLogSpec.
+func (in *NdbMysqldAuditLogSpec) DeepCopy() *NdbMysqldAuditLogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMysqldAuditLogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMysqldAutoscalingSpec) DeepCopyInto(out *NdbMysqldAutoscalingSpec) {
+	*out = *in
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetConnectionsPerServer != nil {
+		in, out := &in.TargetConnectionsPerServer, &out.TargetConnectionsPerServer
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMysqldAutoscalingSpec.
+func (in *NdbMysqldAutoscalingSpec) DeepCopy() *NdbMysqldAutoscalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMysqldAutoscalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMysqldCanaryRolloutSpec) DeepCopyInto(out *NdbMysqldCanaryRolloutSpec) {
+	*out = *in
+	if in.HealthCheckQueries != nil {
+		in, out := &in.HealthCheckQueries, &out.HealthCheckQueries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMysqldCanaryRolloutSpec.
+func (in *NdbMysqldCanaryRolloutSpec) DeepCopy() *NdbMysqldCanaryRolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMysqldCanaryRolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMysqldGatewaySpec) DeepCopyInto(out *NdbMysqldGatewaySpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMysqldGatewaySpec.
+func (in *NdbMysqldGatewaySpec) DeepCopy() *NdbMysqldGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMysqldGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMysqldPasswordValidationSpec) DeepCopyInto(out *NdbMysqldPasswordValidationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMysqldPasswordValidationSpec.
+func (in *NdbMysqldPasswordValidationSpec) DeepCopy() *NdbMysqldPasswordValidationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMysqldPasswordValidationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMysqldPlugin) DeepCopyInto(out *NdbMysqldPlugin) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMysqldPlugin.
+func (in *NdbMysqldPlugin) DeepCopy() *NdbMysqldPlugin {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMysqldPlugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMysqldPluginsSpec) DeepCopyInto(out *NdbMysqldPluginsSpec) {
+	*out = *in
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]NdbMysqldPlugin, len(*in))
+		copy(*out, *in)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbManagementNodeSpec.
-func (in *NdbManagementNodeSpec) DeepCopy() *NdbManagementNodeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMysqldPluginsSpec.
+func (in *NdbMysqldPluginsSpec) DeepCopy() *NdbMysqldPluginsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NdbManagementNodeSpec)
+	out := new(NdbMysqldPluginsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMysqldServerGroupSpec) DeepCopyInto(out *NdbMysqldServerGroupSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMysqldServerGroupSpec.
+func (in *NdbMysqldServerGroupSpec) DeepCopy() *NdbMysqldServerGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMysqldServerGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMysqldServerGroupStatus) DeepCopyInto(out *NdbMysqldServerGroupStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMysqldServerGroupStatus.
+func (in *NdbMysqldServerGroupStatus) DeepCopy() *NdbMysqldServerGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMysqldServerGroupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -267,6 +1362,36 @@ func (in *NdbManagementNodeSpec) DeepCopy() *NdbManagementNodeSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NdbMysqldSpec) DeepCopyInto(out *NdbMysqldSpec) {
 	*out = *in
+	if in.PasswordValidation != nil {
+		in, out := &in.PasswordValidation, &out.PasswordValidation
+		*out = new(NdbMysqldPasswordValidationSpec)
+		**out = **in
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = new(NdbMysqldPluginsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuditLog != nil {
+		in, out := &in.AuditLog, &out.AuditLog
+		*out = new(NdbMysqldAuditLogSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LoadBalancer != nil {
+		in, out := &in.LoadBalancer, &out.LoadBalancer
+		*out = new(NdbLoadBalancerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(NdbMysqldGatewaySpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.NdbPodSpec != nil {
 		in, out := &in.NdbPodSpec, &out.NdbPodSpec
 		*out = new(NdbClusterPodSpec)
@@ -292,6 +1417,52 @@ func (in *NdbMysqldSpec) DeepCopyInto(out *NdbMysqldSpec) {
 		*out = new(corev1.PersistentVolumeClaimSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(NdbMysqldAutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(NdbPodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodLabels != nil {
+		in, out := &in.PodLabels, &out.PodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ServiceAnnotations != nil {
+		in, out := &in.ServiceAnnotations, &out.ServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(appsv1.StatefulSetUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CanaryRollout != nil {
+		in, out := &in.CanaryRollout, &out.CanaryRollout
+		*out = new(NdbMysqldCanaryRolloutSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServerGroups != nil {
+		in, out := &in.ServerGroups, &out.ServerGroups
+		*out = make([]NdbMysqldServerGroupSpec, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -304,3 +1475,256 @@ func (in *NdbMysqldSpec) DeepCopy() *NdbMysqldSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbNetworkPolicySpec) DeepCopyInto(out *NdbNetworkPolicySpec) {
+	*out = *in
+	if in.MySQLClients != nil {
+		in, out := &in.MySQLClients, &out.MySQLClients
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NdbAPIClients != nil {
+		in, out := &in.NdbAPIClients, &out.NdbAPIClients
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbNetworkPolicySpec.
+func (in *NdbNetworkPolicySpec) DeepCopy() *NdbNetworkPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbNetworkPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbNodesConfigRolloutStatus) DeepCopyInto(out *NdbNodesConfigRolloutStatus) {
+	*out = *in
+	if in.PendingRestart != nil {
+		in, out := &in.PendingRestart, &out.PendingRestart
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbNodesConfigRolloutStatus.
+func (in *NdbNodesConfigRolloutStatus) DeepCopy() *NdbNodesConfigRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbNodesConfigRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbOperator) DeepCopyInto(out *NdbOperator) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbOperator.
+func (in *NdbOperator) DeepCopy() *NdbOperator {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbOperator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NdbOperator) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbOperatorList) DeepCopyInto(out *NdbOperatorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NdbOperator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbOperatorList.
+func (in *NdbOperatorList) DeepCopy() *NdbOperatorList {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbOperatorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NdbOperatorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbOperatorNamespaceStatus) DeepCopyInto(out *NdbOperatorNamespaceStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbOperatorNamespaceStatus.
+func (in *NdbOperatorNamespaceStatus) DeepCopy() *NdbOperatorNamespaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbOperatorNamespaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbOperatorStatus) DeepCopyInto(out *NdbOperatorStatus) {
+	*out = *in
+	if in.EnabledFeatures != nil {
+		in, out := &in.EnabledFeatures, &out.EnabledFeatures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]NdbOperatorNamespaceStatus, len(*in))
+		copy(*out, *in)
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbOperatorStatus.
+func (in *NdbOperatorStatus) DeepCopy() *NdbOperatorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbOperatorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbPodDNSSpec) DeepCopyInto(out *NdbPodDNSSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbPodDNSSpec.
+func (in *NdbPodDNSSpec) DeepCopy() *NdbPodDNSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbPodDNSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbPodDisruptionBudgetSpec) DeepCopyInto(out *NdbPodDisruptionBudgetSpec) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbPodDisruptionBudgetSpec.
+func (in *NdbPodDisruptionBudgetSpec) DeepCopy() *NdbPodDisruptionBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbPodDisruptionBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbServiceAccountSpec) DeepCopyInto(out *NdbServiceAccountSpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbServiceAccountSpec.
+func (in *NdbServiceAccountSpec) DeepCopy() *NdbServiceAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbServiceAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbStartupProbeSpec) DeepCopyInto(out *NdbStartupProbeSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbStartupProbeSpec.
+func (in *NdbStartupProbeSpec) DeepCopy() *NdbStartupProbeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbStartupProbeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbTablespaceSpec) DeepCopyInto(out *NdbTablespaceSpec) {
+	*out = *in
+	if in.DataFiles != nil {
+		in, out := &in.DataFiles, &out.DataFiles
+		*out = make([]NdbDiskDataFileSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbTablespaceSpec.
+func (in *NdbTablespaceSpec) DeepCopy() *NdbTablespaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbTablespaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -14,6 +14,10 @@ const (
 	NdbNodeTypeNdbmtd NdbNodeType = "ndbmtd"
 	NdbNodeTypeMySQLD NdbNodeType = "mysqld"
 	NdbNodeTypeAPI    NdbNodeType = "api"
+
+	// NdbNodeTypeArbitrator is the type of the Management
+	// node run only to act as an external arbitrator
+	NdbNodeTypeArbitrator NdbNodeType = "arbitrator"
 )
 
 const (
@@ -26,10 +30,37 @@ const (
 	// ClusterResourceTypeLabel is applied to all K8s resources except
 	// pods owned by an NdbCluster resource
 	ClusterResourceTypeLabel = ndbcontroller.GroupName + "/resource-type"
+	// MySQLServerGroupLabel is applied to the StatefulSets and the pods
+	// of the MySQL Server groups, instead of the ClusterNodeTypeLabel
+	MySQLServerGroupLabel = ndbcontroller.GroupName + "/mysqld-server-group"
+	// HostNetworkLabel is applied to all the pods that run
+	// in the host network, with the node type as its value
+	HostNetworkLabel = ndbcontroller.GroupName + "/host-network"
+	// ProductionNamespaceLabel, when set to "true" on a namespace, marks
+	// it as a production namespace. The webhook returns stricter warnings
+	// for the NdbCluster resources created in such namespaces.
+	ProductionNamespaceLabel = ndbcontroller.GroupName + "/production"
+	// DataNodeZonePlacementGate is the scheduling gate that holds the
+	// data node pods until the operator pins them to their planned zones
+	DataNodeZonePlacementGate = ndbcontroller.GroupName + "/zone-placement"
 )
 
 const DataDir = "/var/lib/ndb"
 
+const (
+	// DataNodeFileSystemDir is the directory used as the
+	// FileSystemPath of the data nodes, when a separate
+	// volume is specified for it in the NdbCluster spec.
+	DataNodeFileSystemDir = DataDir + "/filesystem"
+	// DataNodeBackupDir is the directory used as the BackupDataDir of the
+	// data nodes, when a separate volume is specified for it in the spec.
+	DataNodeBackupDir = DataDir + "/backup"
+	// DataNodeUndoFilesDir is the directory used as the
+	// FileSystemPathUndoFiles of the data nodes, when a
+	// separate volume is specified for it in the spec.
+	DataNodeUndoFilesDir = DataDir + "/undo"
+)
+
 const (
 	// MaxNumberOfNodes is the maximum number of nodes in Ndb Cluster
 	MaxNumberOfNodes = 256
@@ -46,12 +77,37 @@ const (
 	// NdbNodeTypeAPIStartNodeId is the nodeId of the
 	// first non-dedicated API/MySQLD section in MySQL Cluster config
 	NdbNodeTypeAPIStartNodeId = NdbOperatorDedicatedAPINodeId + 1
+
+	// ArbitratorNodeId is the nodeId of the external arbitrator. The
+	// last nodeId is used so that the nodeIds of the other nodes are
+	// not affected by the arbitrator.
+	ArbitratorNodeId = MaxNumberOfNodes - 1
+
+	// ManagementNodePort is the default port used by the Management nodes
+	ManagementNodePort = 1186
+
+	// DataNodeServerPort is the port used by the Data nodes
+	DataNodeServerPort = 1186
+
+	// DataNodeHostNetworkServerPort is the port used by the Data nodes
+	// when they run in the host network. It is different from the port
+	// used by the Management nodes to allow them to run in the same host.
+	DataNodeHostNetworkServerPort = 11860
+
+	// MySQLServerPort is the default port used by the MySQL Servers
+	MySQLServerPort = 3306
 )
 
 // List of ConfigMap keys
 const (
 	// ConfigIniKey is the key to the management config string
 	ConfigIniKey = "config.ini"
+	// MgmdRestartConfigVersion stores the version of the last config.ini
+	// that had a change requiring a restart of the Management nodes.
+	MgmdRestartConfigVersion = "mgmdRestartConfigVersion"
+	// DataNodeRestartType stores the type of the restart required by
+	// the data nodes to apply the config.ini and the pod definition.
+	DataNodeRestartType = "dataNodeRestartType"
 	// NumOfMySQLServers has the number of MySQL Servers declared in the NdbCluster spec.
 	NumOfMySQLServers = "numOfMySQLServers"
 	// NdbClusterGeneration stores the generation the config map is based on.
@@ -64,6 +120,18 @@ const (
 	MySQLConfigKey = "my.cnf"
 	// MySQLRootHost is the key to the MySQL Server's root account's host.
 	MySQLRootHost = "mysqlRootHost"
+	// MySQLServerGroups has the MySQL Server groups declared in the NdbCluster spec.
+	MySQLServerGroups = "mysqlServerGroups"
+	// LocationDomainZones has the values of the location domain topology
+	// key of the worker nodes running the MySQL Cluster nodes, mapped by
+	// the names of their pods.
+	LocationDomainZones = "locationDomainZones"
+	// RestartRequests has the NdbCluster annotations requesting a rolling
+	// restart of the MySQL Cluster nodes, that have been applied to the config.
+	RestartRequests = "restartRequests"
+	// ConfigChanges has a human-readable summary of the changes made to the
+	// MySQL Cluster and MySQL Server configs by the latest generation of the spec.
+	ConfigChanges = "configChanges"
 )
 
 // List of scripts loaded into the configmap
@@ -72,9 +140,18 @@ const (
 	// MgmdStartupProbeScript is the Management Nodes' Startup Probe
 	MgmdStartupProbeScript = "mgmd-startup-probe.sh"
 
+	// MgmdReadinessProbeScript is the Management Nodes' Readiness Probe
+	MgmdReadinessProbeScript = "mgmd-readiness-probe.sh"
+
 	// DataNodeStartupProbeScript is the Data Nodes' Startup Probe
 	DataNodeStartupProbeScript = "ndbmtd-startup-probe.sh"
 
+	// DataNodeReadinessProbeScript is the Data Nodes' Readiness Probe
+	DataNodeReadinessProbeScript = "ndbmtd-readiness-probe.sh"
+
+	// DataNodePreStopHookScript is the Data Nodes' PreStop hook
+	DataNodePreStopHookScript = "ndbmtd-prestop-hook.sh"
+
 	// MysqldInitScript is used to initialize the data directory of the MySQL Servers
 	MysqldInitScript = "mysqld-init-script.sh"
 
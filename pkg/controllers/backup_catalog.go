@@ -0,0 +1,366 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mysql/ndb-operator/config"
+	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller"
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/resources"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	klog "k8s.io/klog/v2"
+)
+
+// backupCompletedRegex matches the message of the cluster log entry
+// logged when a backup completes, which is of the form :
+// Node 2: Backup 1 started from node 1 completed. StartGCP: 5380 StopGCP: 5383
+// #Records: 2055 #LogRecords: 0 Data: 60236 bytes Log: 0 bytes
+const (
+	// backupCatalogReconcileInterval is the interval at which the backup
+	// catalog is reconciled with the backups stored by the data nodes
+	backupCatalogReconcileInterval = time.Hour
+	// backupCatalogReconcileTimeAnnotation records, in the backup catalog
+	// ConfigMap, the time at which the catalog was last reconciled
+	backupCatalogReconcileTimeAnnotation = ndbcontroller.GroupName + "/reconcile-time"
+	// catalogedBackupsAnnotation records, in the Job listing the backups,
+	// the ids of the backups that were cataloged when the Job was created
+	catalogedBackupsAnnotation = ndbcontroller.GroupName + "/cataloged-backups"
+)
+
+var backupCompletedRegex = regexp.MustCompile(`^Node \d+: Backup (\d+) started from node \d+ completed\. ` +
+	`StartGCP: (\d+) StopGCP: (\d+) #Records: (\d+) #LogRecords: (\d+) Data: (\d+) bytes Log: (\d+) bytes`)
+
+// parseBackupCompletedEntry returns the details of the backup from the
+// given cluster log entry, or nil if the entry doesn't report a completed
+// backup. The location and the NdbCluster generation are not filled in.
+func parseBackupCompletedEntry(entry *clusterLogEntry) *resources.BackupCatalogEntry {
+	matches := backupCompletedRegex.FindStringSubmatch(entry.message)
+	if matches == nil {
+		return nil
+	}
+
+	// The numbers have been matched by the regex and only an
+	// out of range value can fail to parse, which is left as 0
+	parseUint := func(match string, bitSize int) uint64 {
+		value, _ := strconv.ParseUint(match, 10, bitSize)
+		return value
+	}
+
+	return &resources.BackupCatalogEntry{
+		BackupID:       int32(parseUint(matches[1], 31)),
+		CompletionTime: entry.timestamp,
+		StartGCP:       uint32(parseUint(matches[2], 32)),
+		StopGCP:        uint32(parseUint(matches[3], 32)),
+		Records:        parseUint(matches[4], 64),
+		LogRecords:     parseUint(matches[5], 64),
+		DataBytes:      parseUint(matches[6], 64),
+		LogBytes:       parseUint(matches[7], 64),
+	}
+}
+
+// catalogBackup records the given completed backup of the NdbCluster in
+// its backup catalog ConfigMap, creating the ConfigMap if it doesn't exist.
+// The NdbCluster is read from the lister to record its latest generation.
+func (cls *clusterLogStreamer) catalogBackup(
+	ctx context.Context, nc *v1.NdbCluster, backup *resources.BackupCatalogEntry) {
+	if latestNc, err := cls.ndbsLister.NdbClusters(nc.Namespace).Get(nc.Name); err == nil {
+		nc = latestNc
+	}
+	backup.Location = resources.GetBackupLocation(nc, backup.BackupID)
+	backup.NdbClusterGeneration = nc.Generation
+
+	if err := writeBackupCatalogEntry(ctx, cls.k8sClient, nc, backup); err != nil {
+		klog.Errorf("Failed to catalog the backup %d of NdbCluster %q : %s",
+			backup.BackupID, getNdbClusterKey(nc), err)
+		return
+	}
+
+	klog.Infof("Cataloged the backup %d of NdbCluster %q", backup.BackupID, getNdbClusterKey(nc))
+}
+
+// patchBackupCatalog merges the given data and annotations into the backup
+// catalog ConfigMap, creating it if it doesn't exist. A nil value removes
+// the backup with that key from the catalog. The oldest backups beyond the
+// MaxBackupCatalogEntries latest ones are then removed from the catalog.
+func patchBackupCatalog(ctx context.Context, k8sClient kubernetes.Interface,
+	nc *v1.NdbCluster, data map[string]interface{}, annotations map[string]string) error {
+	patchData := func(data map[string]interface{}) ([]byte, error) {
+		patch := map[string]interface{}{
+			"data": data,
+		}
+		if len(annotations) != 0 {
+			patch["metadata"] = map[string]interface{}{
+				"annotations": annotations,
+			}
+		}
+		return json.Marshal(patch)
+	}
+
+	patch, err := patchData(data)
+	if err != nil {
+		return err
+	}
+
+	var catalog *corev1.ConfigMap
+	configMapInterface := k8sClient.CoreV1().ConfigMaps(nc.Namespace)
+	for attempt := 0; attempt < 2; attempt++ {
+		catalog, err = configMapInterface.Patch(ctx, nc.GetBackupCatalogConfigMapName(),
+			types.MergePatchType, patch, metav1.PatchOptions{FieldManager: fieldManager})
+		if !apierrors.IsNotFound(err) {
+			break
+		}
+
+		// The first backup of the NdbCluster - create the catalog
+		newCatalog := resources.NewBackupCatalogConfigMap(nc)
+		for key, value := range data {
+			if value != nil {
+				newCatalog.Data[key] = value.(string)
+			}
+		}
+		newCatalog.Annotations = annotations
+		catalog, err = configMapInterface.Create(ctx, newCatalog, createOptions())
+		if !apierrors.IsAlreadyExists(err) {
+			break
+		}
+		// The catalog was created concurrently - retry the patch
+	}
+	if err != nil {
+		return err
+	}
+
+	// Remove the oldest backups to keep the catalog within its limit
+	excessKeys := resources.GetExcessBackupCatalogKeys(catalog.Data)
+	if len(excessKeys) == 0 {
+		return nil
+	}
+	removals := make(map[string]interface{})
+	for _, key := range excessKeys {
+		removals[key] = nil
+	}
+	// The annotations have been applied already
+	annotations = nil
+	if patch, err = patchData(removals); err != nil {
+		return err
+	}
+	_, err = configMapInterface.Patch(ctx, nc.GetBackupCatalogConfigMapName(),
+		types.MergePatchType, patch, metav1.PatchOptions{FieldManager: fieldManager})
+	return err
+}
+
+// writeBackupCatalogEntry adds the given backup to the backup catalog
+// ConfigMap, replacing any existing entry of the backup. The entry is
+// merge-patched into the existing ConfigMap, so that the other backups
+// already cataloged, by the cluster log streamer or by the backup
+// verification, are retained without having to read the catalog first.
+func writeBackupCatalogEntry(ctx context.Context,
+	k8sClient kubernetes.Interface, nc *v1.NdbCluster, backup *resources.BackupCatalogEntry) error {
+	value, err := json.Marshal(backup)
+	if err != nil {
+		return err
+	}
+
+	return patchBackupCatalog(ctx, k8sClient, nc, map[string]interface{}{
+		resources.GetBackupCatalogKey(backup.BackupID): string(value),
+	}, nil)
+}
+
+// getJobTerminationMessage returns the termination message
+// written by the container of the given completed Job
+func (sc *SyncContext) getJobTerminationMessage(ctx context.Context, job *batchv1.Job) (string, error) {
+	pods, err := sc.kubeClientset().CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{"job-name": job.Name}.String(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, pod := range pods.Items {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if terminated := containerStatus.State.Terminated; terminated != nil && terminated.ExitCode == 0 {
+				return terminated.Message, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("completed pod of the Job %q not found", getNamespacedName(job))
+}
+
+// applyBackupListing reconciles the backup catalog with the backups listed
+// by the given completed Job. The backups cataloged when the Job was
+// created that are no longer stored by the data node are removed from the
+// catalog, and the stored backups missing from the catalog are added to it.
+// The backups cataloged after the Job was created are left untouched.
+func (sc *SyncContext) applyBackupListing(ctx context.Context, job *batchv1.Job) error {
+	nc := sc.ndb
+	listing, err := sc.getJobTerminationMessage(ctx, job)
+	if err != nil {
+		sc.logger.Error(err, "Failed to read the backups listed by the Job", "job", getNamespacedName(job))
+		return err
+	}
+	listedBackups, err := resources.ParseBackupListing(listing)
+	if err != nil {
+		sc.logger.Error(err, "Failed to parse the backups listed by the Job", "job", getNamespacedName(job))
+		return err
+	}
+
+	catalogData := make(map[string]string)
+	catalog, err := sc.kubeClientset().CoreV1().ConfigMaps(nc.Namespace).Get(
+		ctx, nc.GetBackupCatalogConfigMapName(), metav1.GetOptions{})
+	if err == nil {
+		catalogData = catalog.Data
+	} else if !apierrors.IsNotFound(err) {
+		sc.logger.Error(err, "Failed to read the backup catalog")
+		return err
+	}
+
+	changes := make(map[string]interface{})
+	for _, catalogedBackupId := range strings.Split(job.Annotations[catalogedBackupsAnnotation], ",") {
+		backupId, err := strconv.ParseInt(catalogedBackupId, 10, 32)
+		if err != nil {
+			continue
+		}
+		if _, stored := listedBackups[int32(backupId)]; !stored {
+			// The backup has been deleted from the data node
+			changes[resources.GetBackupCatalogKey(int32(backupId))] = nil
+		}
+	}
+
+	for backupId, completionTime := range listedBackups {
+		key := resources.GetBackupCatalogKey(backupId)
+		if _, exists := catalogData[key]; exists {
+			continue
+		}
+
+		// The backup was not cataloged from the cluster log
+		value, err := json.Marshal(&resources.BackupCatalogEntry{
+			BackupID:       backupId,
+			CompletionTime: completionTime,
+			Location:       resources.GetBackupLocation(nc, backupId),
+		})
+		if err != nil {
+			return err
+		}
+		changes[key] = string(value)
+	}
+
+	if err = patchBackupCatalog(ctx, sc.kubeClientset(), nc, changes, map[string]string{
+		backupCatalogReconcileTimeAnnotation: time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		sc.logger.Error(err, "Failed to reconcile the backup catalog")
+		return err
+	}
+
+	sc.logger.Info("Reconciled the backup catalog with the backups stored by the data nodes",
+		"backups", len(listedBackups), "changes", len(changes))
+	return nil
+}
+
+// reconcileBackupCatalog reconciles the backup catalog with the backups
+// stored by the first data node, once every backupCatalogReconcileInterval,
+// when the operator catalogs the backups from the cluster logs. The backups
+// are listed by a Job that mounts the PersistentVolumeClaims of the data
+// node, and the Job is deleted once the catalog has been reconciled. The
+// backups missed by the cluster log streaming, e.g. while the operator was
+// down, are added to the catalog, and the cataloged backups that have since
+// been deleted from the data node are removed from it. The reconciliation
+// runs alongside the MySQL Cluster and never stops the sync.
+func (sc *SyncContext) reconcileBackupCatalog(ctx context.Context) syncResult {
+	nc := sc.ndb
+	if !config.StreamClusterLog || sc.dataNodeSfSet == nil {
+		return continueProcessing()
+	}
+
+	var catalogedBackupIds []string
+	catalog, err := sc.configMapLister.ConfigMaps(nc.Namespace).Get(nc.GetBackupCatalogConfigMapName())
+	if err == nil {
+		if reconcileTime, err := time.Parse(
+			time.RFC3339, catalog.Annotations[backupCatalogReconcileTimeAnnotation]); err == nil {
+			if wait := time.Until(reconcileTime.Add(backupCatalogReconcileInterval)); wait > 0 {
+				// The catalog has been reconciled recently - check it again after a while
+				if sc.requeueAfter == 0 || wait < sc.requeueAfter {
+					sc.requeueAfter = wait
+				}
+				return continueProcessing()
+			}
+		}
+
+		backups, err := resources.ParseBackupCatalog(catalog)
+		if err != nil {
+			sc.logger.Error(err, "Failed to read the backup catalog")
+			return continueProcessing()
+		}
+		for _, backup := range backups {
+			catalogedBackupIds = append(catalogedBackupIds, strconv.Itoa(int(backup.BackupID)))
+		}
+	} else if !apierrors.IsNotFound(err) {
+		sc.logger.Error(err, "Failed to read the backup catalog")
+		return continueProcessing()
+	}
+
+	// The Job lists the backups stored by the first data node
+	dataNodePodName := fmt.Sprintf("%s-0", sc.dataNodeSfSet.Name)
+	volumes, volumeMounts := statefulset.GetDataNodePVCVolumes(nc, dataNodePodName)
+	if len(volumes) == 0 {
+		// The data nodes do not store the backups in a PersistentVolumeClaim
+		return continueProcessing()
+	}
+
+	pod, err := sc.podLister.Pods(nc.Namespace).Get(dataNodePodName)
+	if err != nil || pod.Spec.NodeName == "" {
+		// Wait for the data node pod to be scheduled
+		return continueProcessing()
+	}
+
+	listingJob := resources.NewBackupListingJob(nc, volumes, volumeMounts, pod.Spec.NodeName)
+	listingJob.Annotations = map[string]string{
+		catalogedBackupsAnnotation: strings.Join(catalogedBackupIds, ","),
+	}
+	job, state, err := sc.ensureInitJob(ctx, listingJob)
+	if err != nil {
+		return continueProcessing()
+	}
+
+	switch state {
+	case initJobCreated, initJobRunning:
+		// Wait for the backups to be listed
+		return continueProcessing()
+	case initJobFailed:
+		// Leave the catalog as it is until the next reconciliation
+		sc.logger.Info("Failed to list the backups stored by the data node", "reason", getInitJobFailure(job))
+		err = patchBackupCatalog(ctx, sc.kubeClientset(), nc, nil, map[string]string{
+			backupCatalogReconcileTimeAnnotation: time.Now().UTC().Format(time.RFC3339),
+		})
+	case initJobCompleted:
+		err = sc.applyBackupListing(ctx, job)
+	}
+	if err != nil {
+		return continueProcessing()
+	}
+
+	// Delete the Job, along with its pod, once the catalog has been reconciled
+	propagationPolicy := metav1.DeletePropagationBackground
+	if err = sc.kubeClientset().BatchV1().Jobs(job.Namespace).Delete(ctx, job.Name, metav1.DeleteOptions{
+		PropagationPolicy: &propagationPolicy,
+	}); err != nil && !apierrors.IsNotFound(err) {
+		sc.logger.Error(err, "Failed to delete the Job", "job", getNamespacedName(job))
+	}
+
+	return continueProcessing()
+}
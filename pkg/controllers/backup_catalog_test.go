@@ -0,0 +1,183 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	ndblisters "github.com/mysql/ndb-operator/pkg/generated/listers/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func Test_parseBackupCompletedEntry(t *testing.T) {
+	for _, tc := range []struct {
+		line           string
+		expectedBackup *resources.BackupCatalogEntry
+	}{
+		{
+			line: "2023-05-10 10:12:13 [MgmtSrvr] INFO     -- Node 3: Backup 7 started from node 1 completed. " +
+				"StartGCP: 5380 StopGCP: 5383 #Records: 2055 #LogRecords: 12 Data: 60236 bytes Log: 480 bytes",
+			expectedBackup: &resources.BackupCatalogEntry{
+				BackupID:       7,
+				CompletionTime: "2023-05-10 10:12:13",
+				StartGCP:       5380,
+				StopGCP:        5383,
+				Records:        2055,
+				LogRecords:     12,
+				DataBytes:      60236,
+				LogBytes:       480,
+			},
+		},
+		{
+			line:           "2023-05-10 10:12:10 [MgmtSrvr] INFO     -- Node 3: Backup 7 started from node 1",
+			expectedBackup: nil,
+		},
+	} {
+		backup := parseBackupCompletedEntry(parseClusterLogEntry(tc.line))
+		if !reflect.DeepEqual(backup, tc.expectedBackup) {
+			t.Errorf("parseBackupCompletedEntry(%q) returned %+v, expected %+v", tc.line, backup, tc.expectedBackup)
+		}
+	}
+}
+
+func Test_catalogBackup(t *testing.T) {
+	nc := testutils.NewTestNdb("default", "example-ndb", 2)
+	nc.Generation = 3
+	ndbIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := ndbIndexer.Add(nc); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	client := k8sfake.NewSimpleClientset()
+	cls := newClusterLogStreamer(client, ndblisters.NewNdbClusterLister(ndbIndexer), nil)
+
+	// The first backup creates the catalog and the next one is merged into it
+	ctx := context.Background()
+	for backupId := int32(1); backupId <= 2; backupId++ {
+		cls.catalogBackup(ctx, nc, &resources.BackupCatalogEntry{BackupID: backupId, StopGCP: uint32(100 * backupId)})
+	}
+
+	catalog, err := client.CoreV1().ConfigMaps(nc.Namespace).Get(
+		ctx, nc.GetBackupCatalogConfigMapName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	backups, err := resources.ParseBackupCatalog(catalog)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	expectedBackups := []resources.BackupCatalogEntry{
+		{BackupID: 1, StopGCP: 100, Location: "/var/lib/ndb/data/BACKUP/BACKUP-1", NdbClusterGeneration: 3},
+		{BackupID: 2, StopGCP: 200, Location: "/var/lib/ndb/data/BACKUP/BACKUP-2", NdbClusterGeneration: 3},
+	}
+	if !reflect.DeepEqual(backups, expectedBackups) {
+		t.Errorf("Expected the cataloged backups %+v but got %+v", expectedBackups, backups)
+	}
+}
+
+func Test_patchBackupCatalogLimit(t *testing.T) {
+	nc := testutils.NewTestNdb("default", "example-ndb", 2)
+	client := k8sfake.NewSimpleClientset()
+
+	// Catalog more backups than the limit
+	ctx := context.Background()
+	for backupId := int32(1); backupId <= resources.MaxBackupCatalogEntries+2; backupId++ {
+		if err := writeBackupCatalogEntry(ctx, client, nc, &resources.BackupCatalogEntry{BackupID: backupId}); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+
+	catalog, err := client.CoreV1().ConfigMaps(nc.Namespace).Get(
+		ctx, nc.GetBackupCatalogConfigMapName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	backups, err := resources.ParseBackupCatalog(catalog)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	// Only the latest backups should have been retained
+	if len(backups) != resources.MaxBackupCatalogEntries || backups[0].BackupID != 3 {
+		t.Errorf("Expected the latest %d backups, starting from backup 3, but got %d backups starting from backup %d",
+			resources.MaxBackupCatalogEntries, len(backups), backups[0].BackupID)
+	}
+}
+
+func Test_applyBackupListing(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	nc := testutils.NewTestNdb(ns, "example-ndb", 2)
+
+	f := newFixture(t, nc)
+	defer f.close()
+	f.newController()
+
+	// Backups 1 and 2 are cataloged, and backup 4 is cataloged after
+	// the Job was created. Backup 1 has been deleted from the data node
+	// and backup 3 was completed while the operator was down.
+	ctx := context.Background()
+	for _, backupId := range []int32{1, 2, 4} {
+		if err := writeBackupCatalogEntry(ctx, f.k8sclient, nc,
+			&resources.BackupCatalogEntry{BackupID: backupId, StopGCP: uint32(100 * backupId)}); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+
+	job := resources.NewBackupListingJob(nc, nil, nil, "worker-0")
+	job.Annotations = map[string]string{catalogedBackupsAnnotation: "1,2"}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      job.Name + "-abcde",
+			Namespace: ns,
+			Labels:    map[string]string{"job-name": job.Name},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{Message: "2:1683713533 3:1683717133 "},
+				},
+			}},
+		},
+	}
+	if _, err := f.k8sclient.CoreV1().Pods(ns).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	sc := f.c.newSyncContext(ctx, nc)
+	if err := sc.applyBackupListing(ctx, job); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	catalog, err := f.k8sclient.CoreV1().ConfigMaps(ns).Get(ctx, nc.GetBackupCatalogConfigMapName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	backups, err := resources.ParseBackupCatalog(catalog)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	expectedBackups := []resources.BackupCatalogEntry{
+		{BackupID: 2, StopGCP: 200},
+		{BackupID: 3, CompletionTime: "2023-05-10 11:12:13", Location: "/var/lib/ndb/data/BACKUP/BACKUP-3"},
+		{BackupID: 4, StopGCP: 400},
+	}
+	if !reflect.DeepEqual(backups, expectedBackups) {
+		t.Errorf("Expected the cataloged backups %+v but got %+v", expectedBackups, backups)
+	}
+	if catalog.Annotations[backupCatalogReconcileTimeAnnotation] == "" {
+		t.Error("The reconcile time should have been recorded in the catalog")
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mysql/ndb-operator/pkg/resources"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// getUnverifiedBackup returns the cataloged backup, with the lowest
+// backup id, that is yet to be verified, or nil if there is none.
+func (sc *SyncContext) getUnverifiedBackup() (*resources.BackupCatalogEntry, error) {
+	nc := sc.ndb
+	catalog, err := sc.configMapLister.ConfigMaps(nc.Namespace).Get(nc.GetBackupCatalogConfigMapName())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// No backups have been cataloged yet
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	backups, err := resources.ParseBackupCatalog(catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range backups {
+		if backups[i].Verification == "" {
+			return &backups[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// recordBackupVerification records the result of the verification
+// of the given backup in the backup catalog and in an Event.
+func (sc *SyncContext) recordBackupVerification(ctx context.Context,
+	backup *resources.BackupCatalogEntry, state resources.BackupVerificationState, message string) error {
+	nc := sc.ndb
+	backup.Verification = state
+	backup.VerificationMessage = message
+	if err := writeBackupCatalogEntry(ctx, sc.kubeClientset(), nc, backup); err != nil {
+		sc.logger.Error(err, "Failed to record the verification of the backup", "backupId", backup.BackupID)
+		return err
+	}
+
+	if state == resources.BackupVerified {
+		sc.logger.Info("Backup has been verified", "backupId", backup.BackupID)
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonBackupVerified, ActionNone,
+			"Backup %d has been verified", backup.BackupID)
+	} else {
+		sc.logger.Info("Backup failed the verification", "backupId", backup.BackupID, "reason", message)
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeWarning, ReasonBackupVerificationFailed, ActionNone,
+			"Backup %d failed the verification : %s", backup.BackupID, message)
+	}
+	return nil
+}
+
+// deleteBackupVerificationJobs deletes the Jobs, along with their pods,
+// that verified the given backup on the data nodes with ordinals below
+// the given count.
+func (sc *SyncContext) deleteBackupVerificationJobs(ctx context.Context, backupId int32, count int) {
+	nc := sc.ndb
+	propagationPolicy := metav1.DeletePropagationBackground
+	for ordinal := 0; ordinal < count; ordinal++ {
+		jobName := nc.GetBackupVerificationJobName(backupId, ordinal)
+		if err := sc.kubeClientset().BatchV1().Jobs(nc.Namespace).Delete(ctx, jobName, metav1.DeleteOptions{
+			PropagationPolicy: &propagationPolicy,
+		}); err != nil && !apierrors.IsNotFound(err) {
+			sc.logger.Error(err, "Failed to delete the Job", "job", getNamespacedName2(nc.Namespace, jobName))
+		}
+	}
+}
+
+// ensureBackupVerification verifies the cataloged backups of the MySQL
+// Cluster, one at a time, if it is enabled in the spec. Every data node
+// stores only the part of the backup it has taken, so a backup is verified
+// by a Job per data node, run one after the other, that reads the part of
+// the backup from the PersistentVolumeClaims of that data node. The Jobs are
+// deleted once the result has been recorded in the backup catalog. The
+// verification runs alongside the MySQL Cluster and never stops the sync.
+func (sc *SyncContext) ensureBackupVerification(ctx context.Context) syncResult {
+	nc := sc.ndb
+	if !nc.Spec.VerifyBackups || sc.dataNodeSfSet == nil {
+		return continueProcessing()
+	}
+
+	backup, err := sc.getUnverifiedBackup()
+	if err != nil {
+		sc.logger.Error(err, "Failed to read the backup catalog")
+		return continueProcessing()
+	}
+	if backup == nil {
+		// All the cataloged backups have been verified
+		return continueProcessing()
+	}
+
+	dataNodeCount := int(*sc.dataNodeSfSet.Spec.Replicas)
+	for ordinal := 0; ordinal < dataNodeCount; ordinal++ {
+		// The Job reads the part of the backup taken by the data node
+		dataNodePodName := fmt.Sprintf("%s-%d", sc.dataNodeSfSet.Name, ordinal)
+		volumes, volumeMounts := statefulset.GetDataNodePVCVolumes(nc, dataNodePodName)
+		if len(volumes) == 0 {
+			_ = sc.recordBackupVerification(ctx, backup, resources.BackupVerificationFailed,
+				"the data nodes do not store the backups in a PersistentVolumeClaim")
+			return continueProcessing()
+		}
+
+		pod, err := sc.podLister.Pods(nc.Namespace).Get(dataNodePodName)
+		if err != nil || pod.Spec.NodeName == "" {
+			// Wait for the data node pod to be scheduled
+			return continueProcessing()
+		}
+
+		job, state, err := sc.ensureInitJob(ctx, resources.NewBackupVerificationJob(
+			nc, backup, ordinal, volumes, volumeMounts, pod.Spec.NodeName))
+		if err != nil {
+			return continueProcessing()
+		}
+
+		switch state {
+		case initJobCreated:
+			sc.logger.Info("Verifying the backup", "backupId", backup.BackupID, "pod", dataNodePodName)
+			return continueProcessing()
+		case initJobRunning:
+			// Wait for the verification to complete
+			return continueProcessing()
+		case initJobFailed:
+			if err = sc.recordBackupVerification(ctx, backup,
+				resources.BackupVerificationFailed, getInitJobFailure(job)); err == nil {
+				sc.deleteBackupVerificationJobs(ctx, backup.BackupID, ordinal+1)
+			}
+			return continueProcessing()
+		}
+
+		// The part of the backup taken by the data node has
+		// been verified - continue with the next data node
+	}
+
+	// The parts of the backup taken by all the data nodes have been verified
+	if err = sc.recordBackupVerification(ctx, backup, resources.BackupVerified, ""); err == nil {
+		sc.deleteBackupVerificationJobs(ctx, backup.BackupID, dataNodeCount)
+	}
+	return continueProcessing()
+}
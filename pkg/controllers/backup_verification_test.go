@@ -0,0 +1,122 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ensureBackupVerification(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.Spec.VerifyBackups = true
+	ndb.Spec.DataNode.PVCSpec = &corev1.PersistentVolumeClaimSpec{}
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+	backup := &resources.BackupCatalogEntry{
+		BackupID: 4,
+		Location: resources.GetBackupLocation(ndb, 4),
+	}
+	value, err := json.Marshal(backup)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	catalog := resources.NewBackupCatalogConfigMap(ndb)
+	catalog.Data[resources.GetBackupCatalogKey(backup.BackupID)] = string(value)
+	if catalog, err = f.k8sclient.CoreV1().ConfigMaps(ns).Create(ctx, catalog, metav1.CreateOptions{}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if err = f.k8sIf.Core().V1().ConfigMaps().Informer().GetIndexer().Add(catalog); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	for ordinal, workerNode := range []string{"worker-1", "worker-2"} {
+		dataNodePod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("test-ndbmtd-%d", ordinal), Namespace: ns},
+			Spec:       corev1.PodSpec{NodeName: workerNode},
+		}
+		if err = f.k8sIf.Core().V1().Pods().Informer().GetIndexer().Add(dataNodePod); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+
+	newSyncContext := func() *SyncContext {
+		sc := f.c.newSyncContext(ctx, ndb)
+		replicas := int32(2)
+		sc.dataNodeSfSet = &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ndbmtd", Namespace: ns},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		}
+		return sc
+	}
+
+	// Every sync should create the Job for the next data node, on its worker node,
+	// once the Job of the previous data node completes
+	jobInterface := f.k8sclient.BatchV1().Jobs(ns)
+	for ordinal, workerNode := range []string{"worker-1", "worker-2"} {
+		if sr := newSyncContext().ensureBackupVerification(ctx); sr.stopSync() {
+			t.Fatalf("Backup verification stopped the sync, error : %v", sr.getError())
+		}
+
+		job, err := jobInterface.Get(ctx, ndb.GetBackupVerificationJobName(backup.BackupID, ordinal), metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Job to verify the backup on the data node %d was not created : %s", ordinal, err)
+		}
+		podSpec := job.Spec.Template.Spec
+		if podSpec.NodeName != workerNode {
+			t.Errorf("Expected the Job to run on %s but got %q", workerNode, podSpec.NodeName)
+		}
+		expectedClaimName := fmt.Sprintf("ndbmtd-data-vol-test-ndbmtd-%d", ordinal)
+		if len(podSpec.Volumes) != 1 || podSpec.Volumes[0].PersistentVolumeClaim.ClaimName != expectedClaimName {
+			t.Errorf("Unexpected volumes in the Job : %#v", podSpec.Volumes)
+		}
+
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		if _, err = jobInterface.UpdateStatus(ctx, job, metav1.UpdateOptions{}); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+
+	// The result should be recorded in the catalog once all the Jobs complete
+	if sr := newSyncContext().ensureBackupVerification(ctx); sr.stopSync() {
+		t.Fatalf("Backup verification stopped the sync, error : %v", sr.getError())
+	}
+
+	catalog, err = f.k8sclient.CoreV1().ConfigMaps(ns).Get(ctx, ndb.GetBackupCatalogConfigMapName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	backups, err := resources.ParseBackupCatalog(catalog)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if len(backups) != 1 || backups[0].Verification != resources.BackupVerified {
+		t.Errorf("Backup verification not recorded in the catalog : %+v", backups)
+	}
+
+	// The Jobs should be deleted once the result has been recorded
+	for ordinal := 0; ordinal < 2; ordinal++ {
+		jobName := ndb.GetBackupVerificationJobName(backup.BackupID, ordinal)
+		if _, err = jobInterface.Get(ctx, jobName, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+			t.Errorf("Expected the Job %q to be deleted but got error : %v", jobName, err)
+		}
+	}
+}
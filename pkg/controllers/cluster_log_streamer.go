@@ -0,0 +1,277 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/mysql/ndb-operator/config"
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	ndblisters "github.com/mysql/ndb-operator/pkg/generated/listers/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/events"
+	klog "k8s.io/klog/v2"
+)
+
+// clusterLogEntryRegex matches a cluster log entry written by
+// the Management Server to its console, which is of the form :
+// 2023-05-10 10:12:13 [MgmtSrvr] ALERT    -- Node 2: Forced node shutdown completed...
+var clusterLogEntryRegex = regexp.MustCompile(`^(\S+ \S+) \[MgmtSrvr\] ([A-Z]+)\s+-- (.*)$`)
+
+// importantClusterLogLevels are the levels of the cluster log entries that
+// are surfaced by the operator. These include the node failures, stalled
+// checkpoints and the resource usage warnings.
+var importantClusterLogLevels = map[string]bool{
+	"WARNING":  true,
+	"ERROR":    true,
+	"CRITICAL": true,
+	"ALERT":    true,
+}
+
+const (
+	// clusterLogEventBurst is the number of important cluster log
+	// entries of a MySQL Cluster that are reported as Events at once
+	clusterLogEventBurst = 10
+	// clusterLogEventInterval is the interval at which the Events are
+	// reported once the burst is exhausted. The entries in between are
+	// only logged by the operator and their count is reported with the
+	// next Event.
+	clusterLogEventInterval = time.Minute
+)
+
+// clusterLogEntry is a single entry of the MySQL Cluster log
+type clusterLogEntry struct {
+	timestamp string
+	level     string
+	message   string
+}
+
+// parseClusterLogEntry parses the given line from the Management
+// Server's console and returns the cluster log entry in it, or nil
+// if the line is not a cluster log entry.
+func parseClusterLogEntry(line string) *clusterLogEntry {
+	matches := clusterLogEntryRegex.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+
+	return &clusterLogEntry{
+		timestamp: matches[1],
+		level:     matches[2],
+		message:   matches[3],
+	}
+}
+
+// clusterLogEventLimiter rate limits the Events recorded
+// for the important entries of a MySQL Cluster log
+type clusterLogEventLimiter struct {
+	limiter *rate.Limiter
+	// suppressed is the number of entries not reported
+	// as Events since the last recorded Event
+	suppressed int
+}
+
+// newClusterLogEventLimiter creates a new clusterLogEventLimiter
+func newClusterLogEventLimiter() *clusterLogEventLimiter {
+	return &clusterLogEventLimiter{
+		limiter: rate.NewLimiter(rate.Every(clusterLogEventInterval), clusterLogEventBurst),
+	}
+}
+
+// eventNote returns the note of the Event to be recorded for the given
+// entry, observed at the given time, or an empty string if the entry
+// should not be reported as an Event due to the rate limit.
+func (cle *clusterLogEventLimiter) eventNote(entry *clusterLogEntry, now time.Time) string {
+	if !cle.limiter.AllowN(now, 1) {
+		cle.suppressed++
+		return ""
+	}
+
+	note := fmt.Sprintf("%s -- %s", entry.level, entry.message)
+	if cle.suppressed != 0 {
+		note += fmt.Sprintf(" (%d more entries were not reported as Events, see the operator logs)", cle.suppressed)
+		cle.suppressed = 0
+	}
+	return note
+}
+
+// clusterLogStream is an active stream of a MySQL Cluster log
+type clusterLogStream struct {
+	cancel context.CancelFunc
+}
+
+// clusterLogStreamer streams the cluster logs of the MySQL Clusters from
+// their Management Node pods, and surfaces the important entries as
+// operator log lines and as Events of the NdbCluster resources. The
+// completed backups reported in the cluster logs are cataloged.
+type clusterLogStreamer struct {
+	k8sClient  kubernetes.Interface
+	ndbsLister ndblisters.NdbClusterLister
+	recorder   events.EventRecorder
+
+	// activeStreams holds the active streams keyed by the NdbCluster key
+	activeStreams map[string]*clusterLogStream
+	// mutex protects the activeStreams map
+	mutex sync.Mutex
+}
+
+// newClusterLogStreamer creates a new clusterLogStreamer
+func newClusterLogStreamer(client kubernetes.Interface,
+	ndbsLister ndblisters.NdbClusterLister, recorder events.EventRecorder) *clusterLogStreamer {
+	return &clusterLogStreamer{
+		k8sClient:     client,
+		ndbsLister:    ndbsLister,
+		recorder:      recorder,
+		activeStreams: make(map[string]*clusterLogStream),
+	}
+}
+
+// ensureStreaming starts streaming the cluster log of the given NdbCluster
+// from the Management Node running in the given pod, if it is not being
+// streamed already.
+func (cls *clusterLogStreamer) ensureStreaming(nc *v1.NdbCluster, podName string) {
+	cls.mutex.Lock()
+	defer cls.mutex.Unlock()
+
+	key := getNdbClusterKey(nc)
+	if _, exists := cls.activeStreams[key]; exists {
+		// Cluster log is being streamed already
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &clusterLogStream{cancel: cancel}
+	cls.activeStreams[key] = stream
+	go cls.streamClusterLog(ctx, stream, nc.DeepCopy(), podName)
+}
+
+// stopStreaming stops streaming the cluster log of the NdbCluster with the given key
+func (cls *clusterLogStreamer) stopStreaming(key string) {
+	cls.mutex.Lock()
+	defer cls.mutex.Unlock()
+
+	if stream, exists := cls.activeStreams[key]; exists {
+		stream.cancel()
+		delete(cls.activeStreams, key)
+	}
+}
+
+// stopAll stops streaming the cluster logs of all the NdbClusters
+func (cls *clusterLogStreamer) stopAll() {
+	cls.mutex.Lock()
+	defer cls.mutex.Unlock()
+
+	for key, stream := range cls.activeStreams {
+		stream.cancel()
+		delete(cls.activeStreams, key)
+	}
+}
+
+// streamClusterLog follows the console logs of the given Management Node
+// pod and handles the cluster log entries in them. The stream ends when the
+// pod stops, after which it will be restarted by a later sync.
+func (cls *clusterLogStreamer) streamClusterLog(
+	ctx context.Context, stream *clusterLogStream, nc *v1.NdbCluster, podName string) {
+
+	key := getNdbClusterKey(nc)
+	defer func() {
+		// Remove the stream, unless it has been already stopped
+		cls.mutex.Lock()
+		if cls.activeStreams[key] == stream {
+			stream.cancel()
+			delete(cls.activeStreams, key)
+		}
+		cls.mutex.Unlock()
+	}()
+
+	// Read only the entries logged from now on
+	sinceTime := metav1.Now()
+	logStream, err := cls.k8sClient.CoreV1().Pods(nc.Namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container: statefulset.GetManagementNodeContainerName(),
+		Follow:    true,
+		SinceTime: &sinceTime,
+	}).Stream(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			klog.Warningf("Failed to stream the cluster log of NdbCluster %q from pod %q : %s", key, podName, err)
+		}
+		return
+	}
+	defer logStream.Close()
+
+	klog.Infof("Streaming the cluster log of NdbCluster %q from pod %q", key, podName)
+	eventLimiter := newClusterLogEventLimiter()
+	scanner := bufio.NewScanner(logStream)
+	for scanner.Scan() {
+		entry := parseClusterLogEntry(scanner.Text())
+		if entry == nil {
+			continue
+		}
+
+		if backup := parseBackupCompletedEntry(entry); backup != nil {
+			cls.catalogBackup(ctx, nc, backup)
+			continue
+		}
+
+		if !importantClusterLogLevels[entry.level] {
+			continue
+		}
+
+		klog.InfoS("MySQL Cluster log entry", "ndbcluster", key, "pod", podName,
+			"time", entry.timestamp, "level", entry.level, "message", entry.message)
+		if note := eventLimiter.eventNote(entry, time.Now()); note != "" {
+			cls.recorder.Eventf(nc, nil, corev1.EventTypeWarning, ReasonClusterLog, ActionNone, "%s", note)
+		}
+	}
+
+	if err = scanner.Err(); err != nil && ctx.Err() == nil {
+		klog.Warningf("Error streaming the cluster log of NdbCluster %q from pod %q : %s", key, podName, err)
+	}
+}
+
+// ensureClusterLogStreaming ensures that the cluster log of the MySQL
+// Cluster is being streamed from one of the ready Management Node pods,
+// when the cluster log streaming has been enabled via the operator flags.
+func (sc *SyncContext) ensureClusterLogStreaming() {
+	if !config.StreamClusterLog {
+		// Cluster log streaming is not enabled
+		return
+	}
+
+	mgmdSfset := sc.mgmdNodeSfset
+	if mgmdSfset == nil {
+		// The Management Nodes are yet to be created
+		return
+	}
+
+	for podOrdinal := 0; podOrdinal < int(*mgmdSfset.Spec.Replicas); podOrdinal++ {
+		podName := fmt.Sprintf("%s-%d", mgmdSfset.Name, podOrdinal)
+		pod, err := sc.podLister.Pods(mgmdSfset.Namespace).Get(podName)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				klog.Errorf("Failed to retrieve the pod %q : %s", getNamespacedName2(mgmdSfset.Namespace, podName), err)
+			}
+			continue
+		}
+
+		if readyCondition := getPodCondition(pod, corev1.PodReady); readyCondition != nil &&
+			readyCondition.Status == corev1.ConditionTrue && pod.DeletionTimestamp == nil {
+			// Stream from the first ready Management Node
+			sc.clusterLogStreamer.ensureStreaming(sc.ndb, podName)
+			return
+		}
+	}
+}
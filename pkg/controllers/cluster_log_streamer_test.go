@@ -0,0 +1,75 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_parseClusterLogEntry(t *testing.T) {
+	for _, tc := range []struct {
+		line          string
+		expectedEntry *clusterLogEntry
+	}{
+		{
+			line: "2023-05-10 10:12:13 [MgmtSrvr] ALERT    -- Node 3: Node 2 Disconnected",
+			expectedEntry: &clusterLogEntry{
+				timestamp: "2023-05-10 10:12:13",
+				level:     "ALERT",
+				message:   "Node 3: Node 2 Disconnected",
+			},
+		},
+		{
+			line: "2023-05-10 10:12:14 [MgmtSrvr] INFO     -- Node 3: Local checkpoint 12 started.",
+			expectedEntry: &clusterLogEntry{
+				timestamp: "2023-05-10 10:12:14",
+				level:     "INFO",
+				message:   "Node 3: Local checkpoint 12 started.",
+			},
+		},
+		{
+			// Not a cluster log entry
+			line:          "MySQL Cluster Management Server mysql-8.0.33 ndb-8.0.33",
+			expectedEntry: nil,
+		},
+	} {
+		entry := parseClusterLogEntry(tc.line)
+		if !reflect.DeepEqual(entry, tc.expectedEntry) {
+			t.Errorf("parseClusterLogEntry(%q) returned %+v, expected %+v", tc.line, entry, tc.expectedEntry)
+		}
+	}
+}
+
+func Test_clusterLogEventLimiter(t *testing.T) {
+	entry := &clusterLogEntry{level: "WARNING", message: "Node 2: GCP Monitor: GCP_COMMIT lag 10 seconds"}
+	limiter := newClusterLogEventLimiter()
+	now := time.Now()
+
+	// A burst of entries is reported as Events
+	for i := 0; i < clusterLogEventBurst; i++ {
+		if note := limiter.eventNote(entry, now); note != "WARNING -- "+entry.message {
+			t.Fatalf("Unexpected note for entry %d : %q", i, note)
+		}
+	}
+
+	// Entries exceeding the burst are not reported
+	for i := 0; i < 3; i++ {
+		if note := limiter.eventNote(entry, now); note != "" {
+			t.Fatalf("Entry exceeding the burst reported as an Event : %q", note)
+		}
+	}
+
+	// The next Event includes the count of the entries not reported
+	note := limiter.eventNote(entry, now.Add(clusterLogEventInterval))
+	if !strings.HasPrefix(note, "WARNING -- ") || !strings.Contains(note, "3 more entries") {
+		t.Errorf("Unexpected note after the interval : %q", note)
+	}
+	if limiter.suppressed != 0 {
+		t.Errorf("Suppressed entries not reset : %d", limiter.suppressed)
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2021, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2021, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -9,6 +9,8 @@ import (
 	"strconv"
 
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
 	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -19,6 +21,7 @@ import (
 const (
 	PodInitializing   = "PodInitializing"
 	ContainerCreating = "ContainerCreating"
+	OOMKilled         = "OOMKilled"
 	Separator         = "/"
 )
 
@@ -55,6 +58,45 @@ func statefulsetUpdateComplete(statefulset *appsv1.StatefulSet) bool {
 			statefulset.Status.CurrentReplicas == *(statefulset.Spec.Replicas))
 }
 
+// statefulsetSettled returns true when the StatefulSet controller has
+// no pending update to roll out to the pods of the given StatefulSet.
+// The pods of a StatefulSet with the OnDelete update strategy are
+// updated by the operator during the sync, so such a StatefulSet is
+// considered settled once all its pods are ready. Similarly, a canary
+// rollout of the MySQL Servers is tracked by the sync, and the rollout
+// is frozen once the canary has failed. A RollingUpdate staged with a
+// partition is considered settled once all the pods from the partition
+// ordinal onwards have been updated and all the pods are ready.
+func statefulsetSettled(statefulset *appsv1.StatefulSet) bool {
+	switch getCanaryRolloutState(statefulset) {
+	case canaryRolloutInProgress:
+		return true
+	case canaryRolloutFailed:
+		return statefulsetReady(statefulset)
+	}
+
+	if statefulset.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		return statefulsetReady(statefulset)
+	}
+
+	if rollingUpdate := statefulset.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil &&
+		rollingUpdate.Partition != nil && *rollingUpdate.Partition > 0 {
+		// The pods with an ordinal below the partition are not
+		// updated by the StatefulSet controller until the
+		// partition is lowered, so do not wait for them.
+		replicas := *(statefulset.Spec.Replicas)
+		updatesRequired := replicas - *rollingUpdate.Partition
+		if updatesRequired < 0 {
+			updatesRequired = 0
+		}
+		return statefulset.Status.Replicas == replicas &&
+			statefulset.Status.UpdatedReplicas >= updatesRequired &&
+			statefulsetReady(statefulset)
+	}
+
+	return statefulsetUpdateComplete(statefulset)
+}
+
 // statefulsetReady considers a StatefulSet to be ready if all the pods
 // created by the statefulSet are ready. Note that this doesn't check if
 // the pods created by the StatefulSet are running the latest revision of
@@ -73,6 +115,31 @@ func workloadHasConfigGeneration(obj metav1.Object, expectedConfigGeneration int
 	return existingConfigGeneration == expectedConfigGeneration
 }
 
+// workloadHasMySQLClusterConfigVersion returns true if the pod template of the given
+// StatefulSet has the expectedConfigVersion of the MySQL Cluster config (config.ini).
+func workloadHasMySQLClusterConfigVersion(sfset *appsv1.StatefulSet, expectedConfigVersion int32) bool {
+	existingConfigVersion, _ := strconv.ParseInt(
+		sfset.Spec.Template.Annotations[statefulset.LastAppliedMySQLClusterConfigVersion], 10, 32)
+	return int32(existingConfigVersion) == expectedConfigVersion
+}
+
+// workloadHasRestartRequests returns true if the pod template of the given
+// StatefulSet has the latest restart requests of the given node type.
+func workloadHasRestartRequests(
+	sfset *appsv1.StatefulSet, cs *ndbconfig.ConfigSummary, nodeType constants.NdbNodeType) bool {
+	annotations := sfset.Spec.Template.Annotations
+	return annotations[statefulset.LastAppliedRestartRequest] == cs.GetRestartRequest(nodeType) &&
+		annotations[statefulset.LastAppliedInitialRestartRequest] == cs.GetInitialRestartRequest(nodeType)
+}
+
+// getPodMySQLClusterConfigVersion returns the version of the MySQL
+// Cluster config (config.ini) the given pod was started with.
+func getPodMySQLClusterConfigVersion(pod *corev1.Pod) int32 {
+	configVersion, _ := strconv.ParseInt(
+		pod.GetAnnotations()[statefulset.LastAppliedMySQLClusterConfigVersion], 10, 32)
+	return int32(configVersion)
+}
+
 // getPodCondition returns the PodCondition of given type.
 func getPodCondition(pod *corev1.Pod, conditionType corev1.PodConditionType) *corev1.PodCondition {
 	for _, condition := range pod.Status.Conditions {
@@ -87,6 +154,37 @@ func getPodCondition(pod *corev1.Pod, conditionType corev1.PodConditionType) *co
 func checkContainersForError(containerStatuses []corev1.ContainerStatus, podName string) (errs []string) {
 	for _, containerStatus := range containerStatuses {
 		containerState := containerStatus.State
+		if !containerStatus.Ready {
+			// Check if the current or the previous run of the container was
+			// killed for exceeding its memory limit. Such a container is also
+			// terminated with exit code 137, but it is not stuck at startup.
+			terminatedState := containerState.Terminated
+			if terminatedState == nil {
+				terminatedState = containerStatus.LastTerminationState.Terminated
+			}
+			if terminatedState != nil && terminatedState.Reason == OOMKilled {
+				errs = append(errs, fmt.Sprintf(
+					"pod %q : container %q was killed as it ran out of memory (restart count : %d)",
+					podName, containerStatus.Name, containerStatus.RestartCount))
+				continue
+			}
+		}
+
+		if containerState.Running != nil &&
+			containerStatus.Started != nil && !*containerStatus.Started &&
+			containerStatus.RestartCount > 0 &&
+			containerStatus.LastTerminationState.Terminated != nil &&
+			containerStatus.LastTerminationState.Terminated.ExitCode == 137 {
+			// Container is running but has not passed its startup probe yet, and
+			// its previous run was killed. This happens when the container fails
+			// to start within the limits of its startup probe, i.e. it is stuck
+			// rather than just starting.
+			errs = append(errs, fmt.Sprintf(
+				"pod %q : container %q was killed before it could complete its startup (restart count : %d)",
+				podName, containerStatus.Name, containerStatus.RestartCount))
+			continue
+		}
+
 		if containerStatus.Ready ||
 			containerState.Running != nil {
 			// Container is either ready or running
@@ -0,0 +1,140 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_checkContainersForError_StartupProbe(t *testing.T) {
+	started := false
+	killedState := corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{
+			ExitCode: 137,
+		},
+	}
+	oomKilledState := corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{
+			ExitCode: 137,
+			Reason:   OOMKilled,
+		},
+	}
+	runningState := corev1.ContainerState{
+		Running: &corev1.ContainerStateRunning{},
+	}
+
+	for _, tc := range []struct {
+		desc            string
+		containerStatus corev1.ContainerStatus
+		expectError     bool
+		expectOOMKilled bool
+	}{
+		{
+			desc: "container is starting for the first time",
+			containerStatus: corev1.ContainerStatus{
+				Name:    "ndbmtd-container",
+				State:   runningState,
+				Started: &started,
+			},
+			expectError: false,
+		},
+		{
+			desc: "container was killed before completing its startup",
+			containerStatus: corev1.ContainerStatus{
+				Name:                 "ndbmtd-container",
+				State:                runningState,
+				LastTerminationState: killedState,
+				Started:              &started,
+				RestartCount:         1,
+			},
+			expectError: true,
+		},
+		{
+			desc: "container was killed for running out of memory",
+			containerStatus: corev1.ContainerStatus{
+				Name:                 "ndbmtd-container",
+				State:                runningState,
+				LastTerminationState: oomKilledState,
+				Started:              &started,
+				RestartCount:         1,
+			},
+			expectError:     true,
+			expectOOMKilled: true,
+		},
+		{
+			desc: "container has just been killed for running out of memory",
+			containerStatus: corev1.ContainerStatus{
+				Name:  "ndbmtd-container",
+				State: oomKilledState,
+			},
+			expectError:     true,
+			expectOOMKilled: true,
+		},
+	} {
+		errs := checkContainersForError([]corev1.ContainerStatus{tc.containerStatus}, "example-ndb-ndbmtd-0")
+		if tc.expectError != (len(errs) != 0) {
+			t.Errorf("Testcase %q failed : unexpected errors %v", tc.desc, errs)
+		}
+		if len(errs) != 0 && tc.expectOOMKilled != strings.Contains(errs[0], "out of memory") {
+			t.Errorf("Testcase %q failed : unexpected error %q", tc.desc, errs[0])
+		}
+	}
+}
+
+func Test_statefulsetSettled(t *testing.T) {
+	replicas := int32(2)
+	// StatefulSet whose pods are all ready but only one of them is updated
+	newStatefulSet := func(strategyType appsv1.StatefulSetUpdateStrategyType) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: &replicas,
+				UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+					Type: strategyType,
+				},
+			},
+			Status: appsv1.StatefulSetStatus{
+				Replicas:        2,
+				ReadyReplicas:   2,
+				CurrentReplicas: 1,
+				UpdatedReplicas: 1,
+			},
+		}
+	}
+
+	// The operator updates the pods of an OnDelete StatefulSet, so it is settled once ready
+	if !statefulsetSettled(newStatefulSet(appsv1.OnDeleteStatefulSetStrategyType)) {
+		t.Error("Ready StatefulSet with OnDelete update strategy is not settled")
+	}
+
+	// The StatefulSet controller is still rolling out the update
+	if statefulsetSettled(newStatefulSet(appsv1.RollingUpdateStatefulSetStrategyType)) {
+		t.Error("StatefulSet with a pending RollingUpdate is settled")
+	}
+
+	// The RollingUpdate is staged to update only the pod above the partition
+	sfset := newStatefulSet(appsv1.RollingUpdateStatefulSetStrategyType)
+	partition := int32(1)
+	sfset.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition}
+	if !statefulsetSettled(sfset) {
+		t.Error("StatefulSet with a completed partitioned RollingUpdate is not settled")
+	}
+
+	// The pod above the partition is not ready yet
+	sfset.Status.ReadyReplicas = 1
+	if statefulsetSettled(sfset) {
+		t.Error("StatefulSet with a pod not ready is settled")
+	}
+
+	// The pod above the partition is not updated yet
+	sfset.Status.ReadyReplicas = 2
+	sfset.Status.UpdatedReplicas = 0
+	if statefulsetSettled(sfset) {
+		t.Error("StatefulSet with a pending partitioned RollingUpdate is settled")
+	}
+}
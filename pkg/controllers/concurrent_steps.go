@@ -0,0 +1,81 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// concurrentStepsBudget is the maximum time the sync steps run
+// concurrently by runConcurrently are allowed to take together.
+const concurrentStepsBudget = 30 * time.Second
+
+// observeRetryInterval is the delay after which an NdbCluster is synced
+// again when the state of its MySQL Cluster could not be observed.
+const observeRetryInterval = 30 * time.Second
+
+// runConcurrently runs the given independent sync steps concurrently and
+// waits for all of them to complete. The steps share a context that is
+// cancelled once the concurrentStepsBudget expires or any of the steps
+// fails, and the first error returned by the steps is returned. An error is
+// returned as well if the steps did not complete within the budget. The
+// steps should not modify the same fields of the SyncContext.
+func runConcurrently(ctx context.Context, steps ...func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, concurrentStepsBudget)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	for _, step := range steps {
+		wg.Add(1)
+		go func(step func(ctx context.Context) error) {
+			defer wg.Done()
+			if err := step(ctx); err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					// Stop the other steps
+					cancel()
+				})
+			}
+		}(step)
+	}
+
+	wg.Wait()
+	if firstErr == nil && ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("the sync steps did not complete within %s", concurrentStepsBudget)
+	}
+	return firstErr
+}
+
+// runSyncStepsConcurrently runs the given independent sync steps
+// concurrently via runConcurrently. The sync is stopped with the first
+// error returned by the steps, or if none of them failed, with the result
+// of the first step, in the given order, that requested to stop the sync.
+func runSyncStepsConcurrently(ctx context.Context, steps ...func(ctx context.Context) syncResult) syncResult {
+	results := make([]syncResult, len(steps))
+	concurrentSteps := make([]func(ctx context.Context) error, len(steps))
+	for i := range steps {
+		i := i
+		concurrentSteps[i] = func(ctx context.Context) error {
+			results[i] = steps[i](ctx)
+			return results[i].getError()
+		}
+	}
+
+	if err := runConcurrently(ctx, concurrentSteps...); err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	for _, sr := range results {
+		if sr.stopSync() {
+			return sr
+		}
+	}
+	return continueProcessing()
+}
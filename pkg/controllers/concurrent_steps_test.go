@@ -0,0 +1,86 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_runConcurrently(t *testing.T) {
+	// All the steps should run, and concurrently
+	var completed atomic.Int32
+	started := make(chan struct{})
+	waitForOthers := func(ctx context.Context) error {
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			return errors.New("steps are not run concurrently")
+		}
+		completed.Add(1)
+		return nil
+	}
+	if err := runConcurrently(context.Background(), waitForOthers, waitForOthers,
+		func(ctx context.Context) error {
+			close(started)
+			completed.Add(1)
+			return nil
+		}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if completed.Load() != 3 {
+		t.Errorf("Expected 3 steps to complete but got %d", completed.Load())
+	}
+
+	// A failing step should cancel the others and its error returned
+	stepErr := errors.New("step failed")
+	err := runConcurrently(context.Background(),
+		func(ctx context.Context) error {
+			return stepErr
+		},
+		func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(5 * time.Second):
+				return errors.New("step was not cancelled")
+			}
+		})
+	if err != stepErr {
+		t.Errorf("Expected the error %q but got %v", stepErr, err)
+	}
+}
+
+func Test_runSyncStepsConcurrently(t *testing.T) {
+	ctx := context.Background()
+
+	// All the steps continue => the sync continues
+	continueStep := func(ctx context.Context) syncResult {
+		return continueProcessing()
+	}
+	if sr := runSyncStepsConcurrently(ctx, continueStep, continueStep); sr.stopSync() {
+		t.Error("The sync should continue when all the steps continue")
+	}
+
+	// A step stops the sync => the sync stops without an error
+	stopStep := func(ctx context.Context) syncResult {
+		return finishProcessing()
+	}
+	if sr := runSyncStepsConcurrently(ctx, continueStep, stopStep); !sr.stopSync() || sr.getError() != nil {
+		t.Error("The sync should stop without an error when a step stops it")
+	}
+
+	// A step fails => the sync stops with its error
+	stepErr := errors.New("step failed")
+	failingStep := func(ctx context.Context) syncResult {
+		return errorWhileProcessing(stepErr)
+	}
+	if sr := runSyncStepsConcurrently(ctx, stopStep, failingStep); sr.getError() != stepErr {
+		t.Errorf("Expected the error %q but got %v", stepErr, sr.getError())
+	}
+}
@@ -6,15 +6,17 @@ package controllers
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
+	"strings"
 
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
 	"github.com/mysql/ndb-operator/pkg/resources"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -26,6 +28,11 @@ import (
 type ConfigMapControlInterface interface {
 	EnsureConfigMap(ctx context.Context, sc *SyncContext) (*corev1.ConfigMap, bool, error)
 	PatchConfigMap(ctx context.Context, sc *SyncContext) (*corev1.ConfigMap, error)
+	PatchLocationDomains(
+		ctx context.Context, sc *SyncContext, locationDomainZones map[string]string) (*corev1.ConfigMap, error)
+	PatchRestartRequests(ctx context.Context, sc *SyncContext) (*corev1.ConfigMap, error)
+	PatchDataNodeRestartRequest(
+		ctx context.Context, sc *SyncContext, nodeId int, restartRequest string) (*corev1.ConfigMap, error)
 }
 
 type configMapControl struct {
@@ -63,7 +70,7 @@ func (cmc *configMapControl) EnsureConfigMap(
 
 	if err == nil {
 		// ConfigMap already exists
-		if err = sc.isOwnedByNdbCluster(cm); err != nil {
+		if err = sc.ensureOwnedByNdbCluster(ctx, cm); err != nil {
 			// But is not owned by NdbCluster resource
 			return nil, false, err
 		}
@@ -81,7 +88,7 @@ func (cmc *configMapControl) EnsureConfigMap(
 	// ConfigMap doesn't exist; create it.
 	klog.Infof("Creating ConfigMap %q", getNamespacedName2(nc.Namespace, configMapName))
 	cm = resources.CreateConfigMap(nc)
-	cm, err = cmc.getConfigMapInterface(nc.Namespace).Create(ctx, cm, metav1.CreateOptions{})
+	cm, err = cmc.getConfigMapInterface(nc.Namespace).Create(ctx, cm, createOptions())
 	if err != nil {
 		klog.Errorf("Failed to create ConfigMap %q : %s", getNamespacedName2(nc.Namespace, configMapName), err)
 		return nil, false, err
@@ -106,33 +113,115 @@ func (cmc *configMapControl) PatchConfigMap(
 	// Get an updated config map copy
 	cs := sc.configSummary
 	cmChg := resources.GetUpdatedConfigMap(nc, cmOrg, cs)
+	if cmChg == nil {
+		return nil, fmt.Errorf("failed to generate the new config for the NdbCluster")
+	}
+
+	if restartType := v1.DataNodeRestartType(cmChg.Data[constants.DataNodeRestartType]); !nc.IsDataNodeRestartAllowed(restartType) {
+		// The changes can be applied only by restarting all the data nodes
+		// at the same time, which would make the MySQL Cluster unavailable.
+		// Do not apply them unless the user has allowed it in the spec.
+		_, parameters, err := ndbconfig.GetDataNodeRestartType(
+			cmOrg.Data[constants.ConfigIniKey], cmChg.Data[constants.ConfigIniKey])
+		if err != nil {
+			return nil, err
+		}
+		sc.disallowedDataNodeRestart = restartType
+		change := "applying the new spec to the data nodes"
+		if len(parameters) != 0 {
+			change = fmt.Sprintf("changing the data node parameter(s) %s", strings.Join(parameters, ", "))
+		}
+		return nil, fmt.Errorf("%s requires the restart type %s, which is not allowed by the spec", change, restartType)
+	}
 
-	j, err := json.Marshal(cmOrg)
+	return cmc.applyConfigMap(ctx, nc, cmOrg, cmChg)
+}
+
+// PatchLocationDomains patches the existing config map with a new config.ini
+// that assigns the MySQL Cluster nodes to the location domains of the given zones
+func (cmc *configMapControl) PatchLocationDomains(
+	ctx context.Context, sc *SyncContext, locationDomainZones map[string]string) (cm *corev1.ConfigMap, err error) {
+
+	nc := sc.ndb
+	configMapName := nc.GetConfigMapName()
+	cmOrg, err := cmc.getConfigMap(nc.Namespace, configMapName)
 	if err != nil {
+		klog.Errorf("Error retrieving ConfigMap %q : %s", getNamespacedName2(nc.Namespace, configMapName), err)
 		return nil, err
 	}
 
-	j2, err := json.Marshal(cmChg)
+	// Get an updated config map copy
+	cmChg := resources.GetConfigMapWithLocationDomains(nc, cmOrg, sc.configSummary, locationDomainZones)
+
+	return cmc.applyConfigMap(ctx, nc, cmOrg, cmChg)
+}
+
+// PatchRestartRequests patches the existing config map with the
+// latest restart requests made via the NdbCluster annotations
+func (cmc *configMapControl) PatchRestartRequests(
+	ctx context.Context, sc *SyncContext) (cm *corev1.ConfigMap, err error) {
+
+	nc := sc.ndb
+	configMapName := nc.GetConfigMapName()
+	cmOrg, err := cmc.getConfigMap(nc.Namespace, configMapName)
 	if err != nil {
+		klog.Errorf("Error retrieving ConfigMap %q : %s", getNamespacedName2(nc.Namespace, configMapName), err)
 		return nil, err
 	}
 
-	patchBytes, err := strategicpatch.CreateTwoWayMergePatch(j, j2, corev1.ConfigMap{})
+	// Get an updated config map copy
+	cmChg := resources.GetConfigMapWithRestartRequests(nc, cmOrg, sc.configSummary)
+	if cmChg == nil {
+		return nil, fmt.Errorf("failed to generate the restart requests for the NdbCluster")
+	}
+
+	return cmc.applyConfigMap(ctx, nc, cmOrg, cmChg)
+}
+
+// PatchDataNodeRestartRequest patches the existing config map with the
+// given request for a restart of the data node with the given node id
+func (cmc *configMapControl) PatchDataNodeRestartRequest(
+	ctx context.Context, sc *SyncContext, nodeId int, restartRequest string) (cm *corev1.ConfigMap, err error) {
+
+	nc := sc.ndb
+	configMapName := nc.GetConfigMapName()
+	cmOrg, err := cmc.getConfigMap(nc.Namespace, configMapName)
+	if err != nil {
+		klog.Errorf("Error retrieving ConfigMap %q : %s", getNamespacedName2(nc.Namespace, configMapName), err)
+		return nil, err
+	}
+
+	// Get an updated config map copy
+	cmChg := resources.GetConfigMapWithDataNodeRestartRequest(cmOrg, sc.configSummary, nodeId, restartRequest)
+	if cmChg == nil {
+		return nil, fmt.Errorf("failed to generate the restart requests for the NdbCluster")
+	}
+
+	return cmc.applyConfigMap(ctx, nc, cmOrg, cmChg)
+}
+
+// applyConfigMap applies the changes in cmChg to the existing config map
+// cmOrg. Only the fields owned by the operator are applied, so that the
+// fields written by the other managers are neither changed nor taken over.
+func (cmc *configMapControl) applyConfigMap(ctx context.Context,
+	nc *v1.NdbCluster, cmOrg, cmChg *corev1.ConfigMap) (cm *corev1.ConfigMap, err error) {
+
+	patchBytes, err := newApplyPatch(
+		resources.NewConfigMapApplyConfiguration(nc, cmChg), corev1.SchemeGroupVersion.WithKind("ConfigMap"))
 	if err != nil {
 		return nil, err
 	}
 
 	var result *corev1.ConfigMap
-	ConfigMapInterface := cmc.getConfigMapInterface(nc.Namespace)
+	ConfigMapInterface := cmc.getConfigMapInterface(cmOrg.Namespace)
 	// Patch the ConfigMap with retries on failure
 	updateErr := wait.ExponentialBackoff(retry.DefaultBackoff, func() (ok bool, err error) {
 
 		result, err = ConfigMapInterface.Patch(
-			ctx, cmOrg.Name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{})
+			ctx, cmOrg.Name, types.ApplyPatchType, patchBytes, applyPatchOptions())
 
 		if err != nil {
-			klog.Errorf("Failed to patch ConfigMap %q : %s",
-				getNamespacedName2(nc.Namespace, configMapName), err)
+			klog.Errorf("Failed to patch ConfigMap %q : %s", getNamespacedName(cmOrg), err)
 			return false, err
 		}
 
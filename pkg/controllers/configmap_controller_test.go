@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -10,12 +10,16 @@ import (
 	"testing"
 
 	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
 	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
 	"github.com/mysql/ndb-operator/pkg/ndbconfig/configparser"
+	"github.com/mysql/ndb-operator/pkg/resources"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/client-go/tools/cache"
 )
@@ -127,7 +131,7 @@ func TestCreateConfigMap(t *testing.T) {
 	})
 
 	f.newController()
-	sc := f.c.newSyncContext(ndb)
+	sc := f.c.newSyncContext(context.Background(), ndb)
 
 	cm, existed, err := cmc.EnsureConfigMap(context.TODO(), sc)
 	f.expectCreateAction(ndb.GetNamespace(), "", "v1", "configmaps", cm)
@@ -168,7 +172,7 @@ func TestCreateConfigMap(t *testing.T) {
 	}
 	// Passing nil as expected patch to skip comparing the expected and original patches
 	f.expectPatchAction(ndb.GetNamespace(), "configmaps",
-		cm.GetName(), types.StrategicMergePatchType, nil)
+		cm.GetName(), types.ApplyPatchType, nil)
 
 	// Validate patched cm
 	validateMgmtConfig(t, patchedCm, ndb)
@@ -176,3 +180,119 @@ func TestCreateConfigMap(t *testing.T) {
 	// Validate all actions
 	f.checkActions()
 }
+
+func TestPatchConfigMap_SystemRestart(t *testing.T) {
+
+	for _, tc := range []struct {
+		redundancyLevel int32
+		parameter       string
+		value           int
+		desc            string
+	}{
+		{
+			// Diskless can be changed only by a system restart
+			redundancyLevel: 2,
+			parameter:       "Diskless",
+			value:           1,
+			desc:            "parameter changed by a system restart",
+		},
+		{
+			// The data nodes of a single replica MySQL Cluster are always restarted together
+			redundancyLevel: 1,
+			parameter:       "MaxNoOfTables",
+			value:           1000,
+			desc:            "parameter changed in a single replica MySQL Cluster",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ns := metav1.NamespaceDefault
+			ndb := testutils.NewTestNdb(ns, "test", 2)
+			ndb.Spec.RedundancyLevel = tc.redundancyLevel
+
+			f := newFixture(t, ndb)
+			defer f.close()
+
+			cmInformer := f.k8sIf.Core().V1().ConfigMaps()
+			cmc := NewConfigMapControl(f.k8sclient, cmInformer.Lister())
+
+			// Register handler to get a notification when the cache receives a configmap create event
+			cmCreated := make(chan struct{})
+			cmInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+				AddFunc: func(obj interface{}) {
+					close(cmCreated)
+				},
+			})
+
+			f.newController()
+			sc := f.c.newSyncContext(context.Background(), ndb)
+
+			cm, _, err := cmc.EnsureConfigMap(context.TODO(), sc)
+			if err != nil {
+				t.Fatalf("Unexpected error EnsuringConfigMap: %v", err)
+			}
+			f.expectCreateAction(ndb.GetNamespace(), "", "v1", "configmaps", cm)
+
+			// Wait for cache update before proceeding
+			<-cmCreated
+
+			if sc.configSummary, err = ndbconfig.NewConfigSummary(cm.Data); err != nil {
+				t.Fatalf("Failed to parse the config map : %v", err)
+			}
+
+			// The system restart is not allowed by default
+			value := intstr.FromInt(tc.value)
+			ndb.Spec.DataNode.Config = map[string]*intstr.IntOrString{
+				tc.parameter: &value,
+			}
+			if _, err = cmc.PatchConfigMap(context.TODO(), sc); err == nil {
+				t.Fatal("Expected PatchConfigMap to fail as the system restart is not allowed")
+			}
+			if sc.disallowedDataNodeRestart != v1.DataNodeSystemRestart {
+				t.Error("Expected the SyncContext to be marked as requiring a system restart")
+			}
+
+			// The config map is patched once the system restart is allowed
+			sc.disallowedDataNodeRestart = ""
+			ndb.Spec.DataNode.SystemRestart = &v1.NdbDataNodeSystemRestartSpec{
+				Allowed: true,
+			}
+			patchedCm, err := cmc.PatchConfigMap(context.TODO(), sc)
+			if err != nil {
+				t.Fatal("Unexpected error patching config map :", err)
+			}
+			// Passing nil as expected patch to skip comparing the expected and original patches
+			f.expectPatchAction(ndb.GetNamespace(), "configmaps",
+				cm.GetName(), types.ApplyPatchType, nil)
+
+			if restartType := patchedCm.Data[constants.DataNodeRestartType]; restartType != string(v1.DataNodeSystemRestart) {
+				t.Errorf("Expected the config map to have the restart type %q but got %q", v1.DataNodeSystemRestart, restartType)
+			}
+
+			// Validate all actions
+			f.checkActions()
+		})
+	}
+}
+
+func TestNewConfigMapApplyConfiguration(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+
+	// Config map with a data key and a label added by another manager
+	cm := resources.CreateConfigMap(ndb)
+	cm.Data["backup-schedule"] = "0 2 * * *"
+	cm.Labels["team"] = "dba"
+
+	applyCm := resources.NewConfigMapApplyConfiguration(ndb, cm)
+	if _, exists := applyCm.Data["backup-schedule"]; exists {
+		t.Error("Data key owned by another manager should not be applied")
+	}
+	if _, exists := applyCm.Labels["team"]; exists {
+		t.Error("Label owned by another manager should not be applied")
+	}
+	for key, value := range cm.Data {
+		if key != "backup-schedule" && applyCm.Data[key] != value {
+			t.Errorf("Operator owned data key %q is missing in the apply configuration", key)
+		}
+	}
+}
@@ -8,65 +8,127 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	kubeinformers "k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/workqueue"
 	klog "k8s.io/klog/v2"
 
+	"github.com/mysql/ndb-operator/config"
 	"github.com/mysql/ndb-operator/config/debug"
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
 	"github.com/mysql/ndb-operator/pkg/constants"
 	ndbclientset "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
 	ndbinformers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
 	ndblisters "github.com/mysql/ndb-operator/pkg/generated/listers/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+	"github.com/mysql/ndb-operator/pkg/resources"
 )
 
+// degradedSyncFailureThreshold is the number of consecutive sync
+// failures after which an NdbCluster is marked as Degraded
+const degradedSyncFailureThreshold = 5
+
 // Controller is the main controller implementation for Ndb resources
 type Controller struct {
 	kubernetesClient kubernetes.Interface
 	ndbClient        ndbclientset.Interface
+	dynamicClient    dynamic.Interface
 
 	// NdbCluster Lister
 	ndbsLister ndblisters.NdbClusterLister
 
 	// Controllers for various resources
-	mgmdController      *ndbNodeStatefulSetImpl
-	ndbmtdController    *ndbmtdStatefulSetController
-	mysqldController    *mysqldStatefulSetController
-	configMapController ConfigMapControlInterface
-	serviceController   ServiceControlInterface
-	pdbController       PodDisruptionBudgetControlInterface
+	mgmdController              *ndbNodeStatefulSetImpl
+	arbitratorController        *ndbNodeStatefulSetImpl
+	ndbmtdController            *ndbmtdStatefulSetController
+	mysqldController            *mysqldStatefulSetController
+	mysqldServerGroupController *mysqldServerGroupController
+	configMapController         ConfigMapControlInterface
+	serviceController           ServiceControlInterface
+	pdbController               PodDisruptionBudgetControlInterface
+	hpaController               HorizontalPodAutoscalerControlInterface
+	networkPolicyController     NetworkPolicyControlInterface
+	serviceAccountController    ServiceAccountControlInterface
+	gatewayController           GatewayControlInterface
+	clusterLogStreamer          *clusterLogStreamer
+	nodeEventListener           *nodeEventListener
+	epochSampler                *epochSampler
 
 	// K8s Listers
-	podLister     corelisters.PodLister
-	serviceLister corelisters.ServiceLister
+	podLister         corelisters.PodLister
+	serviceLister     corelisters.ServiceLister
+	configMapLister   corelisters.ConfigMapLister
+	statefulSetLister appslisters.StatefulSetLister
+	pvcLister         corelisters.PersistentVolumeClaimLister
+	pvLister          corelisters.PersistentVolumeLister
+	nodeLister        corelisters.NodeLister
+
+	// Fingerprints of the NdbClusters that are in sync with their spec
+	syncFingerprints *syncFingerprintStore
+	// Recent sync results of the NdbClusters, included in the state dumps
+	syncHistory *syncHistoryStore
+	// Container restarts of the data nodes failing to become ready
+	dataNodeFailures *dataNodeFailureTracker
+	// Worker nodes, hosting the local volumes of the data nodes, found missing
+	missingWorkerNodes *missingWorkerNodeTracker
 
 	// Slice of InformerSynced methods for all the informers used by the controller
 	informerSyncedMethods []cache.InformerSynced
+	// The informers used by the controller keyed by the resource
+	// they watch, whose cache metrics are exported by WriteMetrics
+	informers map[string]cache.SharedIndexInformer
+	// workersStarted is set once the workers start processing the workqueue
+	workersStarted atomic.Bool
+	// shuttingDown is set once the controller starts shutting down,
+	// after which the workers do not start any new reconciliation.
+	shuttingDown atomic.Bool
+	// syncTimeout is the maximum time allowed for a single sync. The
+	// syncs are not bounded if it is 0.
+	syncTimeout time.Duration
 
 	// A rate limited workqueue for queueing the NdbCluster resource
 	// keys on receiving an event. The workqueue ensures that the same
 	// key is not processed simultaneously in two different workers.
-	workqueue workqueue.RateLimitingInterface
+	// The queue is instrumented to collect the statistics of every NdbCluster.
+	workqueue *instrumentedQueue
 	// An event recorder for recording Event resources to the Kubernetes API.
 	recorder events.EventRecorder
 }
 
-// NewController returns a new Ndb controller
+// NewController returns a new Ndb controller. The secretInformer is used
+// to detect the deletion of the Secrets owned by the NdbClusters and the
+// changes to the passwords, and should be limited to the Secrets that
+// have the constants.ClusterLabel. The dynamicClient and the
+// dynamicSharedIndexInformer are used to manage the Gateway API resources
+// exposing the MySQL Servers, and the latter should also be limited to the
+// resources that have the constants.ClusterLabel.
 func NewController(
 	kubernetesClient kubernetes.Interface,
 	ndbClient ndbclientset.Interface,
+	dynamicClient dynamic.Interface,
 	k8sSharedIndexInformer kubeinformers.SharedInformerFactory,
-	ndbSharedIndexInformer ndbinformers.SharedInformerFactory) *Controller {
+	ndbSharedIndexInformer ndbinformers.SharedInformerFactory,
+	dynamicSharedIndexInformer dynamicinformer.DynamicSharedInformerFactory,
+	secretInformer coreinformers.SecretInformer) *Controller {
 
 	// Register for all the required informers
 	ndbClusterInformer := ndbSharedIndexInformer.Mysql().V1().NdbClusters()
@@ -74,6 +136,13 @@ func NewController(
 	podInformer := k8sSharedIndexInformer.Core().V1().Pods()
 	serviceInformer := k8sSharedIndexInformer.Core().V1().Services()
 	configmapInformer := k8sSharedIndexInformer.Core().V1().ConfigMaps()
+	networkPolicyInformer := k8sSharedIndexInformer.Networking().V1().NetworkPolicies()
+	serviceAccountInformer := k8sSharedIndexInformer.Core().V1().ServiceAccounts()
+	roleInformer := k8sSharedIndexInformer.Rbac().V1().Roles()
+	roleBindingInformer := k8sSharedIndexInformer.Rbac().V1().RoleBindings()
+	pvcInformer := k8sSharedIndexInformer.Core().V1().PersistentVolumeClaims()
+	pvInformer := k8sSharedIndexInformer.Core().V1().PersistentVolumes()
+	nodeInformer := k8sSharedIndexInformer.Core().V1().Nodes()
 
 	// Extract all the InformerSynced methods
 	informerSyncedMethods := []cache.InformerSynced{
@@ -82,34 +151,138 @@ func NewController(
 		podInformer.Informer().HasSynced,
 		serviceInformer.Informer().HasSynced,
 		configmapInformer.Informer().HasSynced,
+		networkPolicyInformer.Informer().HasSynced,
+		serviceAccountInformer.Informer().HasSynced,
+		roleInformer.Informer().HasSynced,
+		roleBindingInformer.Informer().HasSynced,
+		pvcInformer.Informer().HasSynced,
+		pvInformer.Informer().HasSynced,
+		nodeInformer.Informer().HasSynced,
+		secretInformer.Informer().HasSynced,
+	}
+	informers := map[string]cache.SharedIndexInformer{
+		"NdbCluster":            ndbClusterInformer.Informer(),
+		"StatefulSet":           statefulSetInformer.Informer(),
+		"Pod":                   podInformer.Informer(),
+		"Service":               serviceInformer.Informer(),
+		"ConfigMap":             configmapInformer.Informer(),
+		"NetworkPolicy":         networkPolicyInformer.Informer(),
+		"ServiceAccount":        serviceAccountInformer.Informer(),
+		"Role":                  roleInformer.Informer(),
+		"RoleBinding":           roleBindingInformer.Informer(),
+		"PersistentVolumeClaim": pvcInformer.Informer(),
+		"PersistentVolume":      pvInformer.Informer(),
+		"Node":                  nodeInformer.Informer(),
+		"Secret":                secretInformer.Informer(),
 	}
 
 	serviceLister := serviceInformer.Lister()
 	statefulSetLister := statefulSetInformer.Lister()
 	configmapLister := configmapInformer.Lister()
 
+	// The NetworkPolicies need to allow the traffic from the operator pods
+	// if the operator is running inside K8s, and the traffic from the
+	// configured operator CIDR otherwise.
+	var operatorNamespace string
+	if helpers.IsAppRunningInsideK8s() {
+		var err error
+		if operatorNamespace, err = helpers.GetCurrentNamespace(); err != nil {
+			klog.Warningf("Could not get the operator namespace : %s", err)
+		}
+	}
+
+	recorder := newEventRecorder(kubernetesClient)
 	controller := &Controller{
 		kubernetesClient:      kubernetesClient,
 		ndbClient:             ndbClient,
+		dynamicClient:         dynamicClient,
 		informerSyncedMethods: informerSyncedMethods,
+		informers:             informers,
 		ndbsLister:            ndbClusterInformer.Lister(),
 		podLister:             podInformer.Lister(),
+		serviceLister:         serviceLister,
+		configMapLister:       configmapLister,
+		statefulSetLister:     statefulSetLister,
+		pvcLister:             pvcInformer.Lister(),
+		pvLister:              pvInformer.Lister(),
+		nodeLister:            nodeInformer.Lister(),
+		syncFingerprints:      newSyncFingerprintStore(),
+		syncHistory:           newSyncHistoryStore(),
+		dataNodeFailures:      newDataNodeFailureTracker(),
+		missingWorkerNodes:    newMissingWorkerNodeTracker(),
 		configMapController:   NewConfigMapControl(kubernetesClient, configmapLister),
 		serviceController:     NewServiceControl(kubernetesClient, serviceLister),
-		workqueue:             workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Ndbs"),
-		recorder:              newEventRecorder(kubernetesClient),
-
-		mgmdController:   newMgmdStatefulSetController(kubernetesClient, statefulSetLister),
-		ndbmtdController: newNdbmtdStatefulSetController(kubernetesClient, statefulSetLister),
+		networkPolicyController: newNetworkPolicyControl(
+			kubernetesClient, networkPolicyInformer.Lister(), operatorNamespace, config.OperatorCIDR),
+		serviceAccountController: newServiceAccountControl(kubernetesClient, serviceAccountInformer.Lister(),
+			roleInformer.Lister(), roleBindingInformer.Lister()),
+		recorder: recorder,
+
+		mgmdController:       newMgmdStatefulSetController(kubernetesClient, statefulSetLister),
+		arbitratorController: newArbitratorStatefulSetController(kubernetesClient, statefulSetLister),
+		ndbmtdController:     newNdbmtdStatefulSetController(kubernetesClient, statefulSetLister),
 		mysqldController: newMySQLDStatefulSetController(
 			kubernetesClient, statefulSetLister, configmapLister),
+		mysqldServerGroupController: newMySQLDServerGroupController(
+			kubernetesClient, statefulSetLister, configmapLister),
+		clusterLogStreamer: newClusterLogStreamer(kubernetesClient, ndbClusterInformer.Lister(), recorder),
+		epochSampler:       newEpochSampler(kubernetesClient, ndbClient, statefulSetLister),
 	}
 
-	// Setup informer and controller for v1.PDB if K8s Server has the support
-	if ServerSupportsV1Policy(kubernetesClient) {
+	// The NdbClusters are queued with a priority based on their state,
+	// so that the syncs of the NdbClusters being created, updated or
+	// recovered are not delayed by the resyncs of the healthy ones.
+	controller.workqueue = newInstrumentedQueue(workqueue.NewRateLimitingQueueWithDelayingInterface(
+		workqueue.NewDelayingQueueWithCustomQueue(newPriorityQueue("Ndbs", controller.getSyncPriority), "Ndbs"),
+		newControllerRateLimiter()))
+
+	// The NdbClusters are reconciled on every node event, irrespective
+	// of their sync fingerprints, as the events are not reflected in them.
+	controller.nodeEventListener = newNodeEventListener(func(key string) {
+		controller.syncFingerprints.forget(key)
+		controller.workqueue.Add(key)
+	})
+
+	// Setup informer and controller for PDB based on the policy API version supported by the K8s Server
+	switch ServerPodDisruptionBudgetGroupVersion(kubernetesClient) {
+	case policyv1.SchemeGroupVersion:
 		pdbInformer := k8sSharedIndexInformer.Policy().V1().PodDisruptionBudgets()
 		controller.informerSyncedMethods = append(controller.informerSyncedMethods, pdbInformer.Informer().HasSynced)
+		controller.informers["PodDisruptionBudget"] = pdbInformer.Informer()
 		controller.pdbController = newPodDisruptionBudgetControl(kubernetesClient, pdbInformer.Lister())
+		pdbInformer.Informer().AddEventHandlerWithResyncPeriod(
+			controller.newOwnedResourceDeleteHandler("PodDisruptionBudget"), 0)
+	case policyv1beta1.SchemeGroupVersion:
+		pdbInformer := k8sSharedIndexInformer.Policy().V1beta1().PodDisruptionBudgets()
+		controller.informerSyncedMethods = append(controller.informerSyncedMethods, pdbInformer.Informer().HasSynced)
+		controller.informers["PodDisruptionBudget"] = pdbInformer.Informer()
+		controller.pdbController = newPodDisruptionBudgetV1beta1Control(kubernetesClient, pdbInformer.Lister())
+		pdbInformer.Informer().AddEventHandlerWithResyncPeriod(
+			controller.newOwnedResourceDeleteHandler("PodDisruptionBudget"), 0)
+	}
+
+	// Setup informer and controller for autoscaling/v2 HPA if K8s Server has the support
+	if ServerSupportsAutoscalingV2(kubernetesClient) {
+		hpaInformer := k8sSharedIndexInformer.Autoscaling().V2().HorizontalPodAutoscalers()
+		controller.informerSyncedMethods = append(controller.informerSyncedMethods, hpaInformer.Informer().HasSynced)
+		controller.informers["HorizontalPodAutoscaler"] = hpaInformer.Informer()
+		controller.hpaController = newHorizontalPodAutoscalerControl(kubernetesClient, hpaInformer.Lister())
+	}
+
+	// Setup informers and controller for the Gateway API resources if K8s Server has the support
+	if ServerSupportsGatewayAPI(kubernetesClient) {
+		gatewayInformer := dynamicSharedIndexInformer.ForResource(gatewayAPIResources[resources.GatewayGVK.Kind])
+		tcpRouteInformer := dynamicSharedIndexInformer.ForResource(gatewayAPIResources[resources.TCPRouteGVK.Kind])
+		controller.informerSyncedMethods = append(controller.informerSyncedMethods,
+			gatewayInformer.Informer().HasSynced, tcpRouteInformer.Informer().HasSynced)
+		controller.informers[resources.GatewayGVK.Kind] = gatewayInformer.Informer()
+		controller.informers[resources.TCPRouteGVK.Kind] = tcpRouteInformer.Informer()
+		controller.gatewayController = newGatewayControl(
+			dynamicClient, gatewayInformer.Lister(), tcpRouteInformer.Lister())
+		gatewayInformer.Informer().AddEventHandlerWithResyncPeriod(
+			controller.newOwnedResourceDeleteHandler(resources.GatewayGVK.Kind), 0)
+		tcpRouteInformer.Informer().AddEventHandlerWithResyncPeriod(
+			controller.newOwnedResourceDeleteHandler(resources.TCPRouteGVK.Kind), 0)
 	}
 
 	klog.Info("Setting up event handlers")
@@ -136,6 +309,15 @@ func NewController(
 				klog.Infof("Resource version updated from %s -> %s",
 					oldNdb.ResourceVersion, newNdb.ResourceVersion)
 				klog.Infof("NdbCluster resource %q is added to the queue for reconciliation", ndbKey)
+			} else if !reflect.DeepEqual(oldNdb.GetRestartRequests(), newNdb.GetRestartRequests()) {
+				// A rolling restart of the MySQL Cluster nodes was requested via the annotations
+				klog.Infof("Restart requests of the NdbCluster resource %q were updated", ndbKey)
+				klog.Infof("NdbCluster resource %q is added to the queue for reconciliation", ndbKey)
+			} else if oldNdb.ReconcilingStopped() != newNdb.ReconcilingStopped() ||
+				oldNdb.Annotations[v1.DumpStateAnnotation] != newNdb.Annotations[v1.DumpStateAnnotation] {
+				// Reconciliation was stopped or resumed, or a state dump was requested via the annotations
+				klog.Infof("Debug annotations of the NdbCluster resource %q were updated", ndbKey)
+				klog.Infof("NdbCluster resource %q is added to the queue for reconciliation", ndbKey)
 			} else if oldNdb.ResourceVersion != newNdb.ResourceVersion {
 				// Spec was not updated but the ResourceVersion changed => Status update
 				klog.V(2).Infof("Status of the NdbCluster resource '%s' was updated", ndbKey)
@@ -161,9 +343,20 @@ func NewController(
 			// Various K8s resources created and maintained for this NdbCluster
 			// resource will have proper owner resources setup. Due to that, this
 			// delete will automatically be cascaded to all those resources and
-			// the controller doesn't have to do anything.
+			// the controller only has to stop streaming its cluster log,
+			// listening to its node events and sampling its latest epoch,
+			// forget its sync fingerprint and close the connections to its
+			// MySQL Servers.
 			ndb := obj.(*v1.NdbCluster)
 			klog.Infof("NdbCluster resource '%s' was deleted", getNdbClusterKey(ndb))
+			controller.clusterLogStreamer.stopStreaming(getNdbClusterKey(ndb))
+			controller.nodeEventListener.stopListening(getNdbClusterKey(ndb))
+			controller.epochSampler.stopSampling(getNdbClusterKey(ndb))
+			controller.syncFingerprints.forget(getNdbClusterKey(ndb))
+			controller.syncHistory.forget(getNdbClusterKey(ndb))
+			controller.dataNodeFailures.forget(getNdbClusterKey(ndb))
+			controller.missingWorkerNodes.forget(getNdbClusterKey(ndb))
+			mysqlclient.CloseConnections(ndb.Namespace, ndb.GetWorkloadName(constants.NdbNodeTypeMySQLD))
 		},
 	})
 
@@ -171,15 +364,11 @@ func NewController(
 	statefulSetInformer.Informer().AddEventHandlerWithResyncPeriod(
 
 		cache.FilteringResourceEventHandler{
-			FilterFunc: func(obj interface{}) bool {
-				// Filter out all StatefulSets not owned by any
-				// NdbCluster resources. The StatefulSet labels
-				// will have the names of their respective
-				// NdbCluster owners.
-				sfset := obj.(*appsv1.StatefulSet)
-				_, clusterLabelExists := sfset.GetLabels()[constants.ClusterLabel]
-				return clusterLabelExists
-			},
+			// Filter out all StatefulSets not owned by any
+			// NdbCluster resources. The StatefulSet labels
+			// will have the names of their respective
+			// NdbCluster owners.
+			FilterFunc: hasClusterLabel,
 
 			Handler: cache.ResourceEventHandlerFuncs{
 				// When a StatefulSet owned by a NdbCluster resource
@@ -227,9 +416,7 @@ func NewController(
 				// is deleted, add the NdbCluster resource to the
 				// workqueue to start the next reconciliation loop.
 				DeleteFunc: func(obj interface{}) {
-					sfset := obj.(*appsv1.StatefulSet)
-					klog.Infof("StatefulSet %q is deleted", getNamespacedName(sfset))
-					controller.extractAndEnqueueNdbCluster(sfset, "StatefulSet", "deleted")
+					controller.handleOwnedResourceDeletion(obj, "StatefulSet")
 				},
 			},
 		},
@@ -241,14 +428,10 @@ func NewController(
 	// Set up event handlers for ConfigMap updates
 	configmapInformer.Informer().AddEventHandlerWithResyncPeriod(
 		cache.FilteringResourceEventHandler{
-			FilterFunc: func(obj interface{}) bool {
-				// Filter out all ConfigMaps not owned by any NdbCluster resources.
-				// The ConfigMap labels will have the names of their respective
-				// NdbCluster owners.
-				configmap := obj.(*corev1.ConfigMap)
-				_, clusterLabelExists := configmap.GetLabels()[constants.ClusterLabel]
-				return clusterLabelExists
-			},
+			// Filter out all ConfigMaps not owned by any NdbCluster resources.
+			// The ConfigMap labels will have the names of their respective
+			// NdbCluster owners.
+			FilterFunc: hasClusterLabel,
 
 			Handler: cache.ResourceEventHandlerFuncs{
 				// A ConfigMap owned by an NdbCluster object was updated
@@ -257,6 +440,12 @@ func NewController(
 					newConfigMap := newObj.(*corev1.ConfigMap)
 					controller.extractAndEnqueueNdbCluster(newConfigMap, "ConfigMap", "updated")
 				},
+
+				// A ConfigMap owned by an NdbCluster object was deleted
+				// Requeue owner for reconciliation to recreate it
+				DeleteFunc: func(obj interface{}) {
+					controller.handleOwnedResourceDeletion(obj, "ConfigMap")
+				},
 			},
 		},
 
@@ -268,16 +457,20 @@ func NewController(
 	podInformer.Informer().AddEventHandlerWithResyncPeriod(
 
 		cache.FilteringResourceEventHandler{
-			FilterFunc: func(obj interface{}) bool {
-				// Filter out all Pods not owned by any NdbCluster resources.
-				// The Pod labels will have the names of their respective
-				// NdbCluster owners.
-				pod := obj.(*corev1.Pod)
-				_, clusterLabelExists := pod.GetLabels()[constants.ClusterLabel]
-				return clusterLabelExists
-			},
+			// Filter out all Pods not owned by any NdbCluster resources.
+			// The Pod labels will have the names of their respective
+			// NdbCluster owners.
+			FilterFunc: hasClusterLabel,
 
 			Handler: cache.ResourceEventHandlerFuncs{
+				// When a data node pod is created with the zone placement
+				// scheduling gate, it has to be pinned to its planned zone.
+				AddFunc: func(obj interface{}) {
+					pod := obj.(*corev1.Pod)
+					if hasZonePlacementGate(pod) {
+						controller.extractAndEnqueueNdbCluster(pod, "Pod", "added")
+					}
+				},
 				// When a pod owned by an NdbCluster resource fails or
 				// recovers from an error, the NdbCluster status needs to be updated.
 				UpdateFunc: func(oldObj, newObj interface{}) {
@@ -296,9 +489,113 @@ func NewController(
 		0,
 	)
 
+	// Set up event handlers to recreate the Services, the Secrets
+	// and the agent ServiceAccounts, Roles and RoleBindings owned
+	// by an NdbCluster when they are deleted
+	serviceInformer.Informer().AddEventHandlerWithResyncPeriod(
+		controller.newOwnedResourceDeleteHandler("Service"), 0)
+	secretInformer.Informer().AddEventHandlerWithResyncPeriod(
+		controller.newOwnedResourceDeleteHandler("Secret"), 0)
+	serviceAccountInformer.Informer().AddEventHandlerWithResyncPeriod(
+		controller.newOwnedResourceDeleteHandler("ServiceAccount"), 0)
+	roleInformer.Informer().AddEventHandlerWithResyncPeriod(
+		controller.newOwnedResourceDeleteHandler("Role"), 0)
+	roleBindingInformer.Informer().AddEventHandlerWithResyncPeriod(
+		controller.newOwnedResourceDeleteHandler("RoleBinding"), 0)
+
+	// Set up event handlers to reconcile the NdbClusters when the Secrets
+	// holding their passwords are created or updated. This also covers the
+	// custom Secrets materialized from an external secret store, provided
+	// they have the constants.ClusterLabel set to the name of the NdbCluster.
+	secretInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.FilteringResourceEventHandler{
+			FilterFunc: hasClusterLabel,
+			Handler: cache.ResourceEventHandlerFuncs{
+				AddFunc: func(obj interface{}) {
+					secret := obj.(*corev1.Secret)
+					if metav1.GetControllerOf(secret) == nil {
+						// A custom Secret that the NdbCluster might be waiting for
+						controller.extractAndEnqueueNdbCluster(secret, "Secret", "created")
+					}
+				},
+				UpdateFunc: func(oldObj, newObj interface{}) {
+					oldSecret := oldObj.(*corev1.Secret)
+					newSecret := newObj.(*corev1.Secret)
+
+					if !reflect.DeepEqual(oldSecret.Data, newSecret.Data) {
+						// The password might have been rotated
+						controller.extractAndEnqueueNdbCluster(newSecret, "Secret", "updated")
+					}
+				},
+			},
+		},
+		0,
+	)
+
 	return controller
 }
 
+// getObjectFromDeleteEvent returns the object passed to an informer's
+// delete event handler. If the informer missed the delete event, the
+// object will be wrapped inside a cache.DeletedFinalStateUnknown
+// tombstone, and the last known state of the object is returned.
+func getObjectFromDeleteEvent(obj interface{}) metav1.Object {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	object, ok := obj.(metav1.Object)
+	if !ok {
+		klog.Error(debug.InternalError(fmt.Errorf("unexpected object in delete event : %#v", obj)))
+		return nil
+	}
+	return object
+}
+
+// hasClusterLabel returns true if the given object has the
+// constants.ClusterLabel, which holds the name of the NdbCluster
+// that owns it. It also handles the tombstones of the deleted objects.
+func hasClusterLabel(obj interface{}) bool {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	object, ok := obj.(metav1.Object)
+	if !ok {
+		return false
+	}
+
+	_, clusterLabelExists := object.GetLabels()[constants.ClusterLabel]
+	return clusterLabelExists
+}
+
+// handleOwnedResourceDeletion adds the NdbCluster that owns the deleted
+// object to the workqueue, so that the object is recreated immediately.
+func (c *Controller) handleOwnedResourceDeletion(obj interface{}, resource string) {
+	object := getObjectFromDeleteEvent(obj)
+	if object == nil {
+		return
+	}
+
+	klog.Infof("%s %q is deleted", resource, getNamespacedName(object))
+	c.extractAndEnqueueNdbCluster(object, resource, "deleted")
+}
+
+// newOwnedResourceDeleteHandler returns an event handler
+// that handles the deletion of the objects of the given
+// resource type that are owned by the NdbClusters.
+func (c *Controller) newOwnedResourceDeleteHandler(resource string) cache.ResourceEventHandler {
+	return cache.FilteringResourceEventHandler{
+		// Filter out all objects not owned by any NdbCluster resources
+		FilterFunc: hasClusterLabel,
+		Handler: cache.ResourceEventHandlerFuncs{
+			DeleteFunc: func(obj interface{}) {
+				c.handleOwnedResourceDeletion(obj, resource)
+			},
+		},
+	}
+}
+
 // ndbClusterExists returns true if a NdbCluster object exists with the given namespace/name
 func (c *Controller) ndbClusterExists(namespace, name string) (bool, error) {
 	_, err := c.ndbsLister.NdbClusters(namespace).Get(name)
@@ -329,14 +626,21 @@ func (c *Controller) extractAndEnqueueNdbCluster(obj metav1.Object, resource str
 	key := getNamespacedName2(obj.GetNamespace(), ndbClusterName)
 	objName := getNamespacedName2(obj.GetNamespace(), obj.GetName())
 	klog.Infof("NdbCluster resource %q is re-queued for further reconciliation as the %s %q owned by the NdbCluster is %s", key, resource, objName, event)
+	// Not all changes to the owned resources are reflected in the sync
+	// fingerprint, e.g. pod errors and deleted Services. Forget the
+	// fingerprint to ensure that the NdbCluster is fully reconciled.
+	c.syncFingerprints.forget(key)
 	c.workqueue.Add(key)
 }
 
 // Run will set up the event handlers for types we are interested in, as well
 // as syncing informer caches and starting workers. It will block until ctx is
-// cancelled, at which point it will shutdown the workqueue and wait for
-// workers to finish processing their current work items.
-func (c *Controller) Run(ctx context.Context, threadiness int) error {
+// cancelled, at which point it will stop accepting new work and wait for the
+// workers to finish processing their current work items. If the workers do not
+// finish within the shutdownTimeout, their in-flight syncs are cancelled. Every
+// sync is cancelled if it does not complete within the syncTimeout, so that an
+// unresponsive MySQL Cluster node cannot stall a worker indefinitely.
+func (c *Controller) Run(ctx context.Context, threadiness int, shutdownTimeout, syncTimeout time.Duration) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
 
@@ -349,24 +653,78 @@ func (c *Controller) Run(ctx context.Context, threadiness int) error {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
+	// The workers use a separate context that is cancelled only if they
+	// do not finish within the shutdownTimeout. This lets the in-flight
+	// syncs, and the Management Server connections opened by them,
+	// complete normally when ctx is cancelled.
+	workerCtx, cancelWorkers := context.WithCancel(klog.NewContext(context.Background(), klog.FromContext(ctx)))
+	defer cancelWorkers()
+
 	klog.Info("Starting workers")
+	c.syncTimeout = syncTimeout
 	// Launch worker go routines to process Ndb resources
+	var workersWg sync.WaitGroup
 	for i := 0; i < threadiness; i++ {
+		workersWg.Add(1)
 		go func() {
+			defer workersWg.Done()
 			// The workers continue processing work items
 			// available in the work queue until they are shutdown
-			for c.processNextWorkItem(ctx) {
+			for c.processNextWorkItem(workerCtx) {
 			}
 		}()
 	}
 
+	c.workersStarted.Store(true)
 	klog.Info("Started workers")
 	<-ctx.Done()
+
 	klog.Info("Shutting down workers")
+	// Stop accepting new work and wake up the idle workers
+	c.shuttingDown.Store(true)
+	c.workqueue.ShutDown()
+	// Stop streaming the cluster logs, listening to the
+	// node events and sampling the latest epochs
+	c.clusterLogStreamer.stopAll()
+	c.nodeEventListener.stopAll()
+	c.epochSampler.stopAll()
+
+	// Wait for the in-flight syncs to complete
+	workersDone := make(chan struct{})
+	go func() {
+		workersWg.Wait()
+		close(workersDone)
+	}()
+
+	select {
+	case <-workersDone:
+		klog.Info("All workers have completed their in-flight syncs")
+	case <-time.After(shutdownTimeout):
+		klog.Warningf("Workers did not complete their in-flight syncs within %s; cancelling them", shutdownTimeout)
+		cancelWorkers()
+		<-workersDone
+		klog.Info("All workers have stopped")
+	}
 
 	return nil
 }
 
+// IsReady returns true if the informer caches have been synced and
+// the workers have started processing the NdbCluster resources.
+func (c *Controller) IsReady() bool {
+	if !c.workersStarted.Load() {
+		return false
+	}
+
+	for _, informerSynced := range c.informerSyncedMethods {
+		if !informerSynced() {
+			return false
+		}
+	}
+
+	return true
+}
+
 // processNextWorkItem reads a single work item off the
 // workqueue and processes it, by calling the syncHandler.
 func (c *Controller) processNextWorkItem(ctx context.Context) (continueProcessing bool) {
@@ -381,6 +739,12 @@ func (c *Controller) processNextWorkItem(ctx context.Context) (continueProcessin
 	// Setup defer to call Done on the item to unblock it from other workers.
 	defer c.workqueue.Done(item)
 
+	if c.shuttingDown.Load() {
+		// The controller is shutting down. Do not start
+		// a new reconciliation for the remaining items.
+		return false
+	}
+
 	// The item is a string key of the NdbCluster
 	// resource object. It is of the form 'namespace/name'.
 	key, ok := item.(string)
@@ -392,15 +756,37 @@ func (c *Controller) processNextWorkItem(ctx context.Context) (continueProcessin
 		return true
 	}
 
-	// Run the syncHandler for the extracted key.
-	klog.Infof("Starting a reconciliation cycle for NdbCluster resource %q", key)
-	sr := c.syncHandler(ctx, key)
-	klog.Infof("Completed a reconciliation cycle for NdbCluster resource %q", key)
+	// Tag all the log entries of this reconciliation cycle with the NdbCluster key
+	logger := klog.LoggerWithValues(klog.FromContext(ctx), "ndbcluster", key)
+	ctx = klog.NewContext(ctx, logger)
+
+	// Run the syncHandler for the extracted key, with a
+	// context that is cancelled once the syncTimeout expires.
+	syncCtx := ctx
+	if c.syncTimeout > 0 {
+		var cancelSync context.CancelFunc
+		syncCtx, cancelSync = context.WithTimeout(ctx, c.syncTimeout)
+		defer cancelSync()
+	}
+	logger.Info("Starting a reconciliation cycle")
+	sr := c.syncHandler(syncCtx, key)
+	logger.Info("Completed a reconciliation cycle")
+	if syncCtx.Err() == context.DeadlineExceeded {
+		logger.Info("Reconciliation cycle did not complete within the sync timeout", "syncTimeout", c.syncTimeout)
+	}
 
 	if err := sr.getError(); err != nil {
-		klog.Infof("Reconciliation of NdbCluster resource %q failed", key)
 		// The sync failed. It will be retried.
-		klog.Info("Re-queuing resource to retry reconciliation after error")
+		c.workqueue.recordSyncFailure(key)
+		failures := c.workqueue.NumRequeues(key) + 1
+		logger.Error(err, "Reconciliation failed, re-queuing resource to retry reconciliation",
+			"consecutiveFailures", failures)
+		if failures >= degradedSyncFailureThreshold {
+			// The sync has been failing repeatedly. Surface it in the status.
+			if patchErr := c.markNdbClusterDegraded(ctx, key, err, failures); patchErr != nil {
+				logger.Error(patchErr, "Failed to set the Degraded condition")
+			}
+		}
 		c.workqueue.AddRateLimited(key)
 		return true
 	}
@@ -411,21 +797,38 @@ func (c *Controller) processNextWorkItem(ctx context.Context) (continueProcessin
 	return true
 }
 
-func (c *Controller) newSyncContext(ndb *v1.NdbCluster) *SyncContext {
+func (c *Controller) newSyncContext(ctx context.Context, ndb *v1.NdbCluster) *SyncContext {
 	return &SyncContext{
-		mgmdController:      c.mgmdController,
-		ndbmtdController:    c.ndbmtdController,
-		mysqldController:    c.mysqldController,
-		configMapController: c.configMapController,
-		serviceController:   c.serviceController,
-		pdbController:       c.pdbController,
-		ndb:                 ndb,
-		kubernetesClient:    c.kubernetesClient,
-		ndbClient:           c.ndbClient,
-		ndbsLister:          c.ndbsLister,
-		podLister:           c.podLister,
-		serviceLister:       c.serviceLister,
-		recorder:            c.recorder,
+		mgmdController:              c.mgmdController,
+		arbitratorController:        c.arbitratorController,
+		ndbmtdController:            c.ndbmtdController,
+		mysqldController:            c.mysqldController,
+		mysqldServerGroupController: c.mysqldServerGroupController,
+		configMapController:         c.configMapController,
+		serviceController:           c.serviceController,
+		pdbController:               c.pdbController,
+		hpaController:               c.hpaController,
+		networkPolicyController:     c.networkPolicyController,
+		serviceAccountController:    c.serviceAccountController,
+		gatewayController:           c.gatewayController,
+		clusterLogStreamer:          c.clusterLogStreamer,
+		nodeEventListener:           c.nodeEventListener,
+		epochSampler:                c.epochSampler,
+		dataNodeFailures:            c.dataNodeFailures,
+		missingWorkerNodes:          c.missingWorkerNodes,
+		ndb:                         ndb,
+		kubernetesClient:            c.kubernetesClient,
+		ndbClient:                   c.ndbClient,
+		dynamicClient:               c.dynamicClient,
+		ndbsLister:                  c.ndbsLister,
+		podLister:                   c.podLister,
+		serviceLister:               c.serviceLister,
+		configMapLister:             c.configMapLister,
+		pvcLister:                   c.pvcLister,
+		pvLister:                    c.pvLister,
+		nodeLister:                  c.nodeLister,
+		recorder:                    c.recorder,
+		logger:                      klog.FromContext(ctx),
 	}
 }
 
@@ -451,8 +854,7 @@ func (c *Controller) newSyncContext(ndb *v1.NdbCluster) *SyncContext {
 //     new changes from Ndb CRD are written to a new version of the config file
 //  5. update status of the CRD
 func (c *Controller) syncHandler(ctx context.Context, key string) (result syncResult) {
-
-	klog.Infof("Sync handler: %s", key)
+	logger := klog.FromContext(ctx)
 
 	// Convert the namespace/name string into a distinct namespace and name
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
@@ -467,22 +869,55 @@ func (c *Controller) syncHandler(ctx context.Context, key string) (result syncRe
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			// Stop processing if the NdbCluster resource no longer exists
-			klog.Infof("NdbCluster resource %q does not exist anymore", key)
+			logger.Info("NdbCluster resource does not exist anymore")
 			return finishProcessing()
 		}
 
-		klog.Infof("Failed to retrieve NdbCluster resource %q", key)
+		logger.Error(err, "Failed to retrieve NdbCluster resource")
+		return errorWhileProcessing(err)
+	}
+
+	// Dump the state of the MySQL Cluster if requested
+	if err = c.dumpStateIfRequested(ctx, ndbOrg); err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	if ndbOrg.ReconcilingStopped() {
+		// Reconciliation has been stopped via the annotations. Leave
+		// the MySQL Cluster and its resources as they are until the
+		// annotation is removed, which will requeue the NdbCluster.
+		logger.Info("Skipping reconciliation as it has been stopped via the annotation",
+			"annotation", v1.StopReconcilingAnnotation)
+		return finishProcessing()
+	}
+
+	// Skip the reconciliation if neither the NdbCluster nor any of
+	// its workloads have changed since the last successful sync.
+	fingerprint, err := computeSyncFingerprint(ndbOrg, c.configMapLister, c.statefulSetLister)
+	if err != nil {
+		logger.Error(err, "Failed to compute the sync fingerprint")
 		return errorWhileProcessing(err)
 	}
+	if c.syncFingerprints.canSkipSync(key, fingerprint) {
+		logger.Info("Skipping reconciliation as the NdbCluster and its workloads are unchanged since the last sync")
+		return finishProcessing()
+	}
 
 	// Create a syncContext with a DeepCopied NdbCluster resource
 	// to prevent the sync method from accidentally mutating the
 	// cache object.
 	nc := ndbOrg.DeepCopy()
-	syncContext := c.newSyncContext(nc)
+	syncContext := c.newSyncContext(ctx, nc)
 
 	// Run sync.
-	if result = syncContext.sync(ctx); result.getError() != nil {
+	result = syncContext.sync(ctx)
+	c.syncHistory.record(key, nc.Generation, result, syncContext.syncSuccess)
+	if syncContext.requeueAfter > 0 {
+		// A sync step has requested the NdbCluster to be synced again
+		logger.Info("Requeuing the NdbCluster", "after", syncContext.requeueAfter)
+		c.workqueue.AddAfter(key, syncContext.requeueAfter)
+	}
+	if result.getError() != nil {
 		// The sync step returned an error - no need to update status yet
 		return result
 	}
@@ -492,6 +927,12 @@ func (c *Controller) syncHandler(ctx context.Context, key string) (result syncRe
 	if err != nil {
 		// Status needs to be updated, but it failed.
 		// Do not record events yet.
+		if apierrors.IsConflict(err) {
+			// The NdbCluster was modified during the sync. Status only
+			// changes do not requeue the NdbCluster, so requeue it here
+			// to update the status from the latest NdbCluster.
+			c.workqueue.Add(key)
+		}
 		// TODO: Ensure that the sync doesn't get stuck
 		return result
 	}
@@ -514,6 +955,17 @@ func (c *Controller) syncHandler(ctx context.Context, key string) (result syncRe
 			// Record an InSync event
 			syncContext.recorder.Eventf(nc, nil,
 				corev1.EventTypeNormal, ReasonInSync, ActionNone, MessageInSync)
+
+			partitionedCondition := nc.GetPartitionedCondition()
+			nodesConnectedCondition := nc.GetNodesConnectedCondition()
+			if syncContext.syncSuccess &&
+				(partitionedCondition == nil || partitionedCondition.Status != corev1.ConditionTrue) &&
+				(nodesConnectedCondition == nil || nodesConnectedCondition.Status != corev1.ConditionFalse) {
+				// The MySQL Cluster is healthy and nothing changed in
+				// this loop. Record the fingerprint to skip the upcoming
+				// syncs until something changes.
+				c.syncFingerprints.record(key, fingerprint)
+			}
 		}
 	}
 
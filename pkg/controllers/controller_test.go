@@ -8,18 +8,25 @@ import (
 	"context"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	kubeinformers "k8s.io/client-go/informers"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
 	core "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
 	klog "k8s.io/klog/v2"
@@ -75,9 +82,41 @@ func newFixture(t *testing.T, ndbclusters ...*ndbcontroller.NdbCluster) *fixture
 	f.k8sclient = k8sfake.NewSimpleClientset(f.k8sObjects...)
 	f.k8sIf = kubeinformers.NewSharedInformerFactory(f.k8sclient, 0)
 
+	// The fake clientset's object tracker cannot create objects via an apply
+	// patch. Add a reactor that creates the object if it doesn't exist yet.
+	f.k8sclient.PrependReactor("patch", "*", f.createObjectOnApply)
+
 	return f
 }
 
+// createObjectOnApply handles a server side apply patch of a
+// non-existent object by creating it in the fake object tracker
+func (f *fixture) createObjectOnApply(action core.Action) (bool, runtime.Object, error) {
+	patchAction := action.(core.PatchAction)
+	if patchAction.GetPatchType() != types.ApplyPatchType {
+		// Let the default reactor handle the patch
+		return false, nil, nil
+	}
+
+	gvr := patchAction.GetResource()
+	ns := patchAction.GetNamespace()
+	tracker := f.k8sclient.Tracker()
+	if _, err := tracker.Get(gvr, ns, patchAction.GetName()); err == nil || !apierrors.IsNotFound(err) {
+		// Object exists or the lookup failed - let the default reactor handle it
+		return false, nil, nil
+	}
+
+	// Decode the patch into an object and create it
+	obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(patchAction.GetPatch(), nil, nil)
+	if err != nil {
+		return true, nil, err
+	}
+	if err = tracker.Create(gvr, obj, ns); err != nil {
+		return true, nil, err
+	}
+	return true, obj, nil
+}
+
 func (f *fixture) startInformers() {
 	f.ndbIf.Start(f.stopCh)
 	f.k8sIf.Start(f.stopCh)
@@ -85,7 +124,9 @@ func (f *fixture) startInformers() {
 
 func (f *fixture) newController() {
 
-	f.c = NewController(f.k8sclient, f.ndbclient, f.k8sIf, f.ndbIf)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+	f.c = NewController(f.k8sclient, f.ndbclient, dynamicClient, f.k8sIf, f.ndbIf,
+		dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0), f.k8sIf.Core().V1().Secrets())
 
 	for _, n := range f.ndbObjects {
 		if err := f.ndbIf.Mysql().V1().NdbClusters().Informer().GetIndexer().Add(n); err != nil {
@@ -182,14 +223,27 @@ func (f *fixture) checkActions() {
 		f.t.Errorf("%d additional expected actions:%+v", len(f.ndbActions)-len(actions), f.ndbActions[len(actions):])
 	}
 
+	// Some of the sync steps are run concurrently, so the actions
+	// done by them can happen in any order among themselves.
+	kubeActions := append([]core.Action(nil), f.kubeActions...)
 	for i, action := range k8sActions {
 
-		if len(f.kubeActions) < i+1 {
-			f.t.Errorf("%d unexpected actions: %+v", len(k8sActions)-len(f.kubeActions), k8sActions[i:])
+		if len(kubeActions) < i+1 {
+			f.t.Errorf("%d unexpected actions: %+v", len(k8sActions)-len(kubeActions), k8sActions[i:])
 			break
 		}
 
-		expectedAction := f.kubeActions[i]
+		if !actionMatches(kubeActions[i], action) {
+			// Look for the action among the other pending expected actions
+			for j := i + 1; j < len(kubeActions); j++ {
+				if actionMatches(kubeActions[j], action) {
+					kubeActions[i], kubeActions[j] = kubeActions[j], kubeActions[i]
+					break
+				}
+			}
+		}
+
+		expectedAction := kubeActions[i]
 		checkAction(expectedAction, action, f.t)
 		/*
 			s, _ := json.Marshal(expectedAction)
@@ -235,11 +289,18 @@ func extractObjectMetaData(actual core.Action, extO, actO runtime.Object, t *tes
 	return expOM, actOM
 }
 
+// actionMatches returns true if the actual action has
+// the verb and the resource of the expected action
+func actionMatches(expected, actual core.Action) bool {
+	return expected.Matches(actual.GetVerb(), actual.GetResource().Resource) &&
+		actual.GetSubresource() == expected.GetSubresource()
+}
+
 // checkAction verifies that expected and actual actions are equal and both have
 // same attached resources
 func checkAction(expected, actual core.Action, t *testing.T) {
 	// TODO: Compare the complete GroupVersionResource rather than just Resource
-	if !(expected.Matches(actual.GetVerb(), actual.GetResource().Resource) && actual.GetSubresource() == expected.GetSubresource()) {
+	if !actionMatches(expected, actual) {
 		t.Errorf("Expected\n\t%#v\ngot\n\t%#v", expected, actual)
 		return
 	}
@@ -330,10 +391,15 @@ func filterInformerActions(actions []core.Action) []core.Action {
 		if (action.GetNamespace() == "default" &&
 			(action.Matches("get", "secrets") ||
 				action.Matches("get", "ndbclusters"))) ||
-			(action.GetNamespace() == "" && action.Matches("get", "version")) {
+			(action.GetNamespace() == "" &&
+				(action.Matches("get", "version") || action.Matches("get", "resource"))) {
 			//klog.Infof("Filtering +%v", action)
 			continue
 		}
+		// Ignore the Events, as they are sent asynchronously by the recorder
+		if action.Matches("create", "events") || action.Matches("patch", "events") {
+			continue
+		}
 		// Ignore all list and watches mostly called by the informers
 		if len(action.GetNamespace()) == 0 &&
 			(action.Matches("list", "ndbclusters") ||
@@ -346,8 +412,24 @@ func filterInformerActions(actions []core.Action) []core.Action {
 				action.Matches("watch", "services") ||
 				action.Matches("list", "poddisruptionbudgets") ||
 				action.Matches("watch", "poddisruptionbudgets") ||
+				action.Matches("list", "networkpolicies") ||
+				action.Matches("watch", "networkpolicies") ||
+				action.Matches("list", "serviceaccounts") ||
+				action.Matches("watch", "serviceaccounts") ||
+				action.Matches("list", "roles") ||
+				action.Matches("watch", "roles") ||
+				action.Matches("list", "rolebindings") ||
+				action.Matches("watch", "rolebindings") ||
+				action.Matches("list", "secrets") ||
+				action.Matches("watch", "secrets") ||
 				action.Matches("list", "statefulsets") ||
 				action.Matches("watch", "statefulsets") ||
+				action.Matches("list", "persistentvolumeclaims") ||
+				action.Matches("watch", "persistentvolumeclaims") ||
+				action.Matches("list", "persistentvolumes") ||
+				action.Matches("watch", "persistentvolumes") ||
+				action.Matches("list", "nodes") ||
+				action.Matches("watch", "nodes") ||
 				action.Matches("list", "validatingwebhookconfigurations")) {
 			//klog.Infof("Filtering +%v", action)
 			continue
@@ -377,9 +459,12 @@ func (f *fixture) expectDeleteAction(ns, group, version, resource, name string)
 	f.kubeActions = append(f.kubeActions, core.NewDeleteAction(grpVersionResource, ns, name))
 }
 
-func (f *fixture) expectNdbClusterStatusUpdateAction(ns string, group, version, resource string) {
+// expectNdbClusterStatusPatchAction adds an expected
+// merge patch action on the status of the NdbCluster.
+func (f *fixture) expectNdbClusterStatusPatchAction(ns string, group, version, resource string) {
 	grpVersionResource := schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
-	f.ndbActions = append(f.ndbActions, core.NewUpdateSubresourceAction(grpVersionResource, "status", ns, nil))
+	f.ndbActions = append(f.ndbActions,
+		core.NewPatchSubresourceAction(grpVersionResource, ns, "", types.MergePatchType, nil, "status"))
 }
 
 func getKey(nc *ndbcontroller.NdbCluster, t *testing.T) string {
@@ -457,7 +542,7 @@ func TestCreatesCluster(t *testing.T) {
 	f.expectCreateAction(ns, "apps", "v1", "statefulsets", &appsv1.StatefulSet{ObjectMeta: *omd})
 
 	// Expect an update on ndbcluster/status
-	f.expectNdbClusterStatusUpdateAction(ns, "mysql.oracle.com", "v1", "ndbclusters")
+	f.expectNdbClusterStatusPatchAction(ns, "mysql.oracle.com", "v1", "ndbclusters")
 
 	// The reconciliation loop ends here. It continues only after the management nodes are ready.
 	f.runControllerAndValidateActions(ndb, false, nil)
@@ -475,7 +560,7 @@ func TestCreatesCluster(t *testing.T) {
 	f.expectCreateAction(ns, "apps", "v1", "statefulsets", &appsv1.StatefulSet{ObjectMeta: *omd})
 
 	// Expect an update on ndbcluster/status
-	f.expectNdbClusterStatusUpdateAction(ns, "mysql.oracle.com", "v1", "ndbclusters")
+	f.expectNdbClusterStatusPatchAction(ns, "mysql.oracle.com", "v1", "ndbclusters")
 
 	// The reconciliation loop ends here. It continues only after the data nodes are ready.
 	f.runControllerAndValidateActions(ndb, false, nil)
@@ -497,8 +582,191 @@ func TestCreatesCluster(t *testing.T) {
 	f.expectCreateAction(ns, "apps", "v1", "statefulsets", &appsv1.StatefulSet{ObjectMeta: *omd})
 
 	// Expect an update on ndbcluster/status
-	f.expectNdbClusterStatusUpdateAction(ns, "mysql.oracle.com", "v1", "ndbclusters")
+	f.expectNdbClusterStatusPatchAction(ns, "mysql.oracle.com", "v1", "ndbclusters")
 
 	// The reconciliation loop ends here.
 	f.runControllerAndValidateActions(ndb, false, nil)
 }
+
+func TestShutdownStopsAcceptingNewWork(t *testing.T) {
+
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	otherNdb := testutils.NewTestNdb(ns, "other-test", 2)
+
+	f := newFixture(t, ndb, otherNdb)
+	defer f.close()
+
+	// Block the first sync at its first write to the K8s Server
+	// until the controller has started shutting down.
+	syncStarted, releaseSync := make(chan struct{}), make(chan struct{})
+	var blockOnce sync.Once
+	blockFirstWrite := func(action core.Action) (bool, runtime.Object, error) {
+		blockOnce.Do(func() {
+			close(syncStarted)
+			<-releaseSync
+		})
+		return false, nil, nil
+	}
+	for _, verb := range []string{"create", "update", "patch"} {
+		f.k8sclient.PrependReactor(verb, "*", blockFirstWrite)
+	}
+
+	// create new controller and queue both the NdbClusters
+	f.newController()
+	f.c.workqueue.Add(getKey(ndb, t))
+	f.c.workqueue.Add(getKey(otherNdb, t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runReturned := make(chan error)
+	go func() {
+		// A single worker processes both the NdbClusters, one after the other
+		runReturned <- f.c.Run(ctx, 1, time.Minute, time.Minute)
+	}()
+
+	// Wait for the first sync to start
+	select {
+	case <-syncStarted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Controller did not start syncing the NdbClusters")
+	}
+
+	// Start shutting down the controller while the sync is in flight
+	cancel()
+
+	// Run should wait for the in-flight sync to complete
+	select {
+	case <-runReturned:
+		t.Fatal("Controller shut down without waiting for the in-flight sync")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	// Let the in-flight sync complete and verify that Run returns
+	close(releaseSync)
+	select {
+	case err := <-runReturned:
+		if err != nil {
+			t.Errorf("Unexpected error from Run : %s", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Controller did not shut down within the expected time")
+	}
+
+	// The in-flight sync should have run to completion and
+	// the other NdbCluster, still queued, should not be synced.
+	syncedClusters := 0
+	for _, nc := range []*ndbcontroller.NdbCluster{ndb, otherNdb} {
+		switch history := f.c.syncHistory.get(getNdbClusterKey(nc)); len(history) {
+		case 0:
+		case 1:
+			syncedClusters++
+		default:
+			t.Errorf("NdbCluster %q synced %d times, expected at most once", nc.Name, len(history))
+		}
+	}
+	if syncedClusters != 1 {
+		t.Errorf("Expected exactly one NdbCluster to complete its sync, but %d did", syncedClusters)
+	}
+
+	// Any work items queued after the shutdown should not be processed
+	f.c.workqueue.Add(getKey(ndb, t))
+	if f.c.processNextWorkItem(context.Background()) {
+		t.Error("Worker continued processing items after shutdown")
+	}
+}
+
+func TestOwnedResourceDeletionRequeuesNdbCluster(t *testing.T) {
+
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	// create new controller
+	f.newController()
+
+	// Create a Service and a Secret owned by the NdbCluster
+	ctx := context.Background()
+	service := &corev1.Service{ObjectMeta: *getObjectMetadata("test-mgmd", ndb)}
+	if _, err := f.k8sclient.CoreV1().Services(ns).Create(ctx, service, metav1.CreateOptions{}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	secret := &corev1.Secret{ObjectMeta: *getObjectMetadata("test-ndb-operator-password", ndb)}
+	if _, err := f.k8sclient.CoreV1().Secrets(ns).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	// Wait for the informers to receive the new objects
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		_, serviceErr := f.c.serviceLister.Services(ns).Get(service.Name)
+		_, secretErr := f.k8sIf.Core().V1().Secrets().Lister().Secrets(ns).Get(secret.Name)
+		return serviceErr == nil && secretErr == nil, nil
+	}); err != nil {
+		t.Fatal("Informers did not receive the new objects :", err)
+	}
+
+	for _, deleteObject := range []struct {
+		resource string
+		delete   func() error
+	}{
+		{
+			resource: "Service",
+			delete: func() error {
+				return f.k8sclient.CoreV1().Services(ns).Delete(ctx, service.Name, metav1.DeleteOptions{})
+			},
+		},
+		{
+			resource: "Secret",
+			delete: func() error {
+				return f.k8sclient.CoreV1().Secrets(ns).Delete(ctx, secret.Name, metav1.DeleteOptions{})
+			},
+		},
+	} {
+		// Record a fingerprint and clear the workqueue
+		f.c.syncFingerprints.record(getKey(ndb, t), "fingerprint")
+		for f.c.workqueue.Len() != 0 {
+			item, _ := f.c.workqueue.Get()
+			f.c.workqueue.Done(item)
+		}
+
+		if err := deleteObject.delete(); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+
+		// The NdbCluster should be requeued for reconciliation
+		if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+			return f.c.workqueue.Len() == 1, nil
+		}); err != nil {
+			t.Errorf("NdbCluster was not requeued when the %s was deleted", deleteObject.resource)
+		}
+
+		// The sync fingerprint should have been forgotten
+		if f.c.syncFingerprints.canSkipSync(getKey(ndb, t), "fingerprint") {
+			t.Errorf("Sync fingerprint was not forgotten when the %s was deleted", deleteObject.resource)
+		}
+	}
+}
+
+func Test_hasClusterLabel(t *testing.T) {
+	ndb := testutils.NewTestNdb(metav1.NamespaceDefault, "test", 2)
+	ownedService := &corev1.Service{ObjectMeta: *getObjectMetadata("test-mgmd", ndb)}
+	otherService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: ndb.Namespace}}
+
+	for _, tc := range []struct {
+		desc     string
+		obj      interface{}
+		expected bool
+	}{
+		{"owned object", ownedService, true},
+		{"other object", otherService, false},
+		{"tombstone of owned object", cache.DeletedFinalStateUnknown{Key: "default/test-mgmd", Obj: ownedService}, true},
+		{"tombstone of other object", cache.DeletedFinalStateUnknown{Key: "default/other", Obj: otherService}, false},
+		{"unexpected object", "default/test-mgmd", false},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			if hasClusterLabel(tc.obj) != tc.expected {
+				t.Errorf("Expected hasClusterLabel to return %v", tc.expected)
+			}
+		})
+	}
+}
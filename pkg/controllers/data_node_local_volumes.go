@@ -0,0 +1,268 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// defaultNodeLossTimeout is the time for which a worker node hosting the
+// local PersistentVolume of a data node should not be ready, before it is
+// considered lost, when the NdbCluster spec doesn't specify a timeout.
+const defaultNodeLossTimeout = 10 * time.Minute
+
+// missingWorkerNodeTracker tracks the worker nodes, hosting the local
+// PersistentVolumes of the data nodes, that do not exist anymore. As the
+// Node object of a deleted worker node doesn't record when it was deleted,
+// the node loss timeout is measured from when it was first found missing.
+type missingWorkerNodeTracker struct {
+	// missingSince holds the time at which the worker nodes were first
+	// found missing, keyed by the NdbCluster key and then by the hostname
+	missingSince map[string]map[string]time.Time
+	// mutex protects the missingSince map
+	mutex sync.Mutex
+}
+
+// newMissingWorkerNodeTracker creates a new missingWorkerNodeTracker
+func newMissingWorkerNodeTracker() *missingWorkerNodeTracker {
+	return &missingWorkerNodeTracker{
+		missingSince: make(map[string]map[string]time.Time),
+	}
+}
+
+// recordMissing records that the worker node with the given hostname has been
+// found missing at the given time, and returns the time since when it has
+// been missing.
+func (t *missingWorkerNodeTracker) recordMissing(key, hostname string, now time.Time) time.Time {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.missingSince[key] == nil {
+		t.missingSince[key] = make(map[string]time.Time)
+	}
+	missingSince, exists := t.missingSince[key][hostname]
+	if !exists {
+		t.missingSince[key][hostname] = now
+		return now
+	}
+	return missingSince
+}
+
+// recordFound records that the worker node with the given hostname exists
+func (t *missingWorkerNodeTracker) recordFound(key, hostname string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.missingSince[key], hostname)
+}
+
+// forget removes the missing worker nodes of the NdbCluster with the given key
+func (t *missingWorkerNodeTracker) forget(key string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.missingSince, key)
+}
+
+// getLocalVolumeHostname returns the hostname of the worker node hosting
+// the local PersistentVolume bound to the given PVC. An empty string is
+// returned if the PVC is not bound to a local PersistentVolume.
+func (sc *SyncContext) getLocalVolumeHostname(pvc *corev1.PersistentVolumeClaim) (string, error) {
+	if pvc.Spec.VolumeName == "" {
+		// PVC is not bound yet
+		return "", nil
+	}
+
+	pv, err := sc.pvLister.Get(pvc.Spec.VolumeName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	if pv.Spec.Local == nil || pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		// Not a local PersistentVolume
+		return "", nil
+	}
+
+	// The local PersistentVolumes are pinned to
+	// their worker nodes via the hostname label.
+	for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+		for _, expression := range term.MatchExpressions {
+			if expression.Key == corev1.LabelHostname &&
+				expression.Operator == corev1.NodeSelectorOpIn && len(expression.Values) == 1 {
+				return expression.Values[0], nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// getWorkerNodeLossTime returns the time since when the worker node with
+// the given hostname has not been ready, or has been missing if it doesn't
+// exist anymore. A zero time is returned if the worker node is ready.
+func (sc *SyncContext) getWorkerNodeLossTime(hostname string) (time.Time, error) {
+	nodes, err := sc.nodeLister.List(labels.SelectorFromSet(map[string]string{corev1.LabelHostname: hostname}))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	key := getNdbClusterKey(sc.ndb)
+	if len(nodes) == 0 {
+		// Worker node has been deleted
+		return sc.missingWorkerNodes.recordMissing(key, hostname, time.Now()), nil
+	}
+	sc.missingWorkerNodes.recordFound(key, hostname)
+
+	for _, condition := range nodes[0].Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status != corev1.ConditionTrue {
+			return condition.LastTransitionTime.Time, nil
+		}
+	}
+
+	return time.Time{}, nil
+}
+
+// handleLostLocalVolumes checks if the worker nodes hosting the local
+// PersistentVolumes of the data nodes are lost for good, i.e. they have
+// been missing or not ready for the node loss timeout, and sets
+// the NdbClusterLocalVolumesAvailable condition accordingly. If enabled in
+// the spec, a data node of a lost worker node is rescheduled onto another
+// worker node through an initial restart. Only one data node is
+// rescheduled per sync.
+func (sc *SyncContext) handleLostLocalVolumes(ctx context.Context) syncResult {
+	nc := sc.ndb
+	localVolumesSpec := nc.Spec.DataNode.LocalVolumes
+	ndbmtdSfset := sc.dataNodeSfSet
+	if localVolumesSpec == nil || nc.Spec.DataNode.PVCSpec == nil || ndbmtdSfset == nil {
+		// Disabled or the data nodes are yet to be created
+		return continueProcessing()
+	}
+
+	nodeLossTimeout := defaultNodeLossTimeout
+	if localVolumesSpec.NodeLossTimeoutSeconds != 0 {
+		nodeLossTimeout = time.Duration(localVolumesSpec.NodeLossTimeoutSeconds) * time.Second
+	}
+
+	var lostDataNodes []string
+	lostOrdinal := int32(-1)
+	for ordinal := int32(0); ordinal < *ndbmtdSfset.Spec.Replicas; ordinal++ {
+		podName := fmt.Sprintf("%s-%d", ndbmtdSfset.Name, ordinal)
+		pvcName := statefulset.GetDataNodePVCName(podName)
+		pvc, err := sc.pvcLister.PersistentVolumeClaims(nc.Namespace).Get(pvcName)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// PVC will be created along with the data node pod
+				continue
+			}
+			sc.logger.Error(err, "Failed to retrieve the PVC", "pvc", getNamespacedName2(nc.Namespace, pvcName))
+			return errorWhileProcessing(err)
+		}
+
+		hostname, err := sc.getLocalVolumeHostname(pvc)
+		if err != nil {
+			sc.logger.Error(err, "Failed to retrieve the PersistentVolume of the PVC", "pvc", getNamespacedName(pvc))
+			return errorWhileProcessing(err)
+		}
+
+		if hostname == "" {
+			// PVC is not bound to a local PersistentVolume
+			continue
+		}
+
+		lossTime, err := sc.getWorkerNodeLossTime(hostname)
+		if err != nil {
+			sc.logger.Error(err, "Failed to retrieve the worker node", "hostname", hostname)
+			return errorWhileProcessing(err)
+		}
+
+		if lossTime.IsZero() {
+			// Worker node is ready
+			continue
+		}
+
+		if elapsed := time.Since(lossTime); elapsed < nodeLossTimeout {
+			// Worker node might still recover or be recreated. The node
+			// updates do not trigger a sync, so requeue to enforce the timeout.
+			if delay := nodeLossTimeout - elapsed; sc.requeueAfter == 0 || delay < sc.requeueAfter {
+				sc.requeueAfter = delay
+			}
+			continue
+		}
+
+		// Worker node is lost for good
+		lostDataNodes = append(lostDataNodes, fmt.Sprintf("%s (worker node %q)", podName, hostname))
+		if lostOrdinal == -1 {
+			lostOrdinal = ordinal
+		}
+	}
+
+	localVolumesAvailableCondition := &v1.NdbClusterCondition{
+		Type:    v1.NdbClusterLocalVolumesAvailable,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1.NdbClusterLocalVolumesAvailableReasonWorkerNodesAvailable,
+		Message: "The worker nodes hosting the local PersistentVolumes of all the data nodes are available",
+	}
+	if len(lostDataNodes) != 0 {
+		localVolumesAvailableCondition.Status = corev1.ConditionFalse
+		localVolumesAvailableCondition.Reason = v1.NdbClusterLocalVolumesAvailableReasonWorkerNodeLost
+		localVolumesAvailableCondition.Message = "The worker nodes hosting the local PersistentVolumes of the data nodes " +
+			strings.Join(lostDataNodes, ", ") + " are lost"
+	}
+
+	sc.updateCondition(localVolumesAvailableCondition, nc.GetLocalVolumesAvailableCondition(), conditionEvents{
+		failureStatus: corev1.ConditionFalse,
+		failureReason: ReasonWorkerNodeLost,
+		renotify:      true,
+	})
+
+	sc.localVolumesAvailableCondition = localVolumesAvailableCondition
+
+	if lostOrdinal == -1 || !localVolumesSpec.Reschedule {
+		// Nothing to reschedule
+		return continueProcessing()
+	}
+
+	podName := fmt.Sprintf("%s-%d", ndbmtdSfset.Name, lostOrdinal)
+	pod, err := sc.podLister.Pods(nc.Namespace).Get(podName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Pod is yet to be recreated by the StatefulSet controller
+			return continueProcessing()
+		}
+		sc.logger.Error(err, "Failed to retrieve the pod", "pod", getNamespacedName2(nc.Namespace, podName))
+		return errorWhileProcessing(err)
+	}
+
+	// Reschedule the data node by deleting its pod along with its PVCs.
+	// The pod is force deleted as the kubelet of the lost worker node
+	// will never confirm its termination. The StatefulSet controller will
+	// then recreate the PVCs, which will be bound to new local
+	// PersistentVolumes on the worker node chosen for the new pod.
+	nodeId := sc.getDataNodeId(podName)
+	sr := sc.initialRestartDataNode(ctx, pod, nodeId, true)
+	if sr.stopSync() && sr.getError() == nil {
+		sc.recorder.Eventf(nc, pod, corev1.EventTypeNormal, ReasonDataNodeRescheduling, ActionRestart,
+			"Data node (nodeId=%d) is being rescheduled onto another worker node", nodeId)
+		localVolumesAvailableCondition.Reason = v1.NdbClusterLocalVolumesAvailableReasonRescheduling
+		localVolumesAvailableCondition.Message = fmt.Sprintf(
+			"The data node %s is being rescheduled onto another worker node", podName)
+	}
+
+	return sr
+}
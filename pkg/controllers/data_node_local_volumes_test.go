@@ -0,0 +1,197 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_handleLostLocalVolumes(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.Spec.DataNode.PVCSpec = &corev1.PersistentVolumeClaimSpec{
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+		},
+	}
+	ndb.Spec.DataNode.LocalVolumes = &v1.NdbDataNodeLocalVolumesSpec{
+		NodeLossTimeoutSeconds: 600,
+	}
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+	pvIndexer := f.k8sIf.Core().V1().PersistentVolumes().Informer().GetIndexer()
+	pvcIndexer := f.k8sIf.Core().V1().PersistentVolumeClaims().Informer().GetIndexer()
+	nodeIndexer := f.k8sIf.Core().V1().Nodes().Informer().GetIndexer()
+
+	// Create the local PVs and the PVCs of the data nodes, bound to them
+	for podName, hostname := range map[string]string{
+		"test-ndbmtd-0": "worker-1",
+		"test-ndbmtd-1": "worker-2",
+	} {
+		pv := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "local-pv-" + hostname},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					Local: &corev1.LocalVolumeSource{Path: "/mnt/disks/ssd1"},
+				},
+				NodeAffinity: &corev1.VolumeNodeAffinity{
+					Required: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchExpressions: []corev1.NodeSelectorRequirement{{
+								Key:      corev1.LabelHostname,
+								Operator: corev1.NodeSelectorOpIn,
+								Values:   []string{hostname},
+							}},
+						}},
+					},
+				},
+			},
+		}
+		if err := pvIndexer.Add(pv); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: statefulset.GetDataNodePVCName(podName), Namespace: ns},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: pv.Name},
+		}
+		if err := pvcIndexer.Add(pvc); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+
+	// Create the worker nodes with the given ready status and transition time
+	createWorkerNodes := func(notReadySince map[string]time.Duration) {
+		for _, hostname := range []string{"worker-1", "worker-2"} {
+			readyCondition := corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionTrue}
+			if duration, exists := notReadySince[hostname]; exists {
+				readyCondition.Status = corev1.ConditionUnknown
+				readyCondition.LastTransitionTime = metav1.NewTime(time.Now().Add(-duration))
+			}
+			workerNode := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   hostname,
+					Labels: map[string]string{corev1.LabelHostname: hostname},
+				},
+				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{readyCondition}},
+			}
+			if err := nodeIndexer.Update(workerNode); err != nil {
+				t.Fatal("Unexpected error :", err)
+			}
+		}
+	}
+
+	replicas := int32(2)
+	dataNodeSfset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: ndb.GetWorkloadName(constants.NdbNodeTypeNdbmtd), Namespace: ns},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	}
+
+	handleLostLocalVolumes := func() (*v1.NdbClusterCondition, time.Duration) {
+		sc := f.c.newSyncContext(ctx, ndb)
+		sc.dataNodeSfSet = dataNodeSfset
+		sr := sc.handleLostLocalVolumes(ctx)
+		if sr.stopSync() {
+			t.Fatal("Unexpected stop of the sync :", sr.getError())
+		}
+		if sc.localVolumesAvailableCondition == nil {
+			t.Fatal("Expected the LocalVolumesAvailable condition to be computed")
+		}
+		return sc.localVolumesAvailableCondition, sc.requeueAfter
+	}
+
+	// All the worker nodes are ready
+	createWorkerNodes(nil)
+	condition, requeueAfter := handleLostLocalVolumes()
+	if condition.Status != corev1.ConditionTrue || requeueAfter != 0 ||
+		condition.Reason != v1.NdbClusterLocalVolumesAvailableReasonWorkerNodesAvailable {
+		t.Errorf("Expected the worker nodes to be available but got the condition %v", condition)
+	}
+
+	// A worker node that is not ready only for a short while is not lost yet
+	createWorkerNodes(map[string]time.Duration{"worker-2": time.Minute})
+	condition, requeueAfter = handleLostLocalVolumes()
+	if condition.Status != corev1.ConditionTrue {
+		t.Errorf("Expected the worker nodes to be available but got the condition %v", condition)
+	}
+	if requeueAfter <= 0 || requeueAfter > 9*time.Minute {
+		t.Errorf("Expected the NdbCluster to be requeued before the timeout but got %s", requeueAfter)
+	}
+
+	// A worker node that is not ready for longer than the timeout is lost
+	createWorkerNodes(map[string]time.Duration{"worker-2": time.Hour})
+	condition, _ = handleLostLocalVolumes()
+	if condition.Status != corev1.ConditionFalse ||
+		condition.Reason != v1.NdbClusterLocalVolumesAvailableReasonWorkerNodeLost ||
+		!strings.Contains(condition.Message, "test-ndbmtd-1") {
+		t.Errorf("Expected the worker node of test-ndbmtd-1 to be lost but got the condition %v", condition)
+	}
+
+	// A deleted worker node is not lost until it has been missing for the timeout
+	createWorkerNodes(nil)
+	workerNode, _, _ := nodeIndexer.GetByKey("worker-1")
+	if err := nodeIndexer.Delete(workerNode); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	condition, requeueAfter = handleLostLocalVolumes()
+	if condition.Status != corev1.ConditionTrue {
+		t.Errorf("Expected the worker nodes to be available but got the condition %v", condition)
+	}
+	if requeueAfter <= 9*time.Minute || requeueAfter > 10*time.Minute {
+		t.Errorf("Expected the NdbCluster to be requeued after the timeout but got %s", requeueAfter)
+	}
+
+	// The timeout is measured from when the worker node was first found missing
+	f.c.missingWorkerNodes.missingSince[getNdbClusterKey(ndb)]["worker-1"] = time.Now().Add(-time.Hour)
+	condition, _ = handleLostLocalVolumes()
+	if condition.Status != corev1.ConditionFalse || !strings.Contains(condition.Message, "test-ndbmtd-0") ||
+		strings.Contains(condition.Message, "test-ndbmtd-1") {
+		t.Errorf("Expected the worker node of test-ndbmtd-0 to be lost but got the condition %v", condition)
+	}
+}
+
+func Test_missingWorkerNodeTracker(t *testing.T) {
+	tracker := newMissingWorkerNodeTracker()
+	firstObserved := time.Now().Add(-time.Minute)
+
+	// The first observation starts the loss time
+	if missingSince := tracker.recordMissing("default/test", "worker-1", firstObserved); !missingSince.Equal(firstObserved) {
+		t.Errorf("Expected the worker node to be missing since %s but got %s", firstObserved, missingSince)
+	}
+
+	// The later observations retain the first observation time
+	if missingSince := tracker.recordMissing("default/test", "worker-1", time.Now()); !missingSince.Equal(firstObserved) {
+		t.Errorf("Expected the worker node to be missing since %s but got %s", firstObserved, missingSince)
+	}
+
+	// A worker node found again starts a new loss time when it goes missing again
+	tracker.recordFound("default/test", "worker-1")
+	now := time.Now()
+	if missingSince := tracker.recordMissing("default/test", "worker-1", now); !missingSince.Equal(now) {
+		t.Errorf("Expected the worker node to be missing since %s but got %s", now, missingSince)
+	}
+
+	tracker.forget("default/test")
+	if len(tracker.missingSince) != 0 {
+		t.Errorf("Missing worker nodes not forgotten : %v", tracker.missingSince)
+	}
+}
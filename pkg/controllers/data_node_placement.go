@@ -0,0 +1,158 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/placement"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// hasZonePlacementGate returns true if the given pod is held by
+// the scheduling gate of the data node zone placement
+func hasZonePlacementGate(pod *corev1.Pod) bool {
+	for _, gate := range pod.Spec.SchedulingGates {
+		if gate.Name == constants.DataNodeZonePlacementGate {
+			return true
+		}
+	}
+	return false
+}
+
+// pinDataNodePodsToZones pins the data node pods, held by the zone
+// placement scheduling gate, to the zones planned for their ordinals and
+// then removes the gate to let them be scheduled. The node selector of a
+// pod can only be extended while its scheduling is gated, so this is the
+// only point at which the operator can place a pod of the StatefulSet by
+// its ordinal. The pods recreated by a restart are pinned to the same zone.
+func (sc *SyncContext) pinDataNodePodsToZones(ctx context.Context) error {
+	nc := sc.ndb
+	zones := nc.Spec.DataNode.Zones
+	if len(zones) == 0 || sc.dataNodeSfSet == nil {
+		// Zones not specified or the data nodes are yet to be created
+		return nil
+	}
+
+	plan, err := placement.NewPlan(*sc.dataNodeSfSet.Spec.Replicas, sc.configSummary.RedundancyLevel, zones)
+	if err != nil {
+		sc.logger.Error(err, "Failed to plan the placement of the data nodes")
+		return err
+	}
+
+	for _, assignment := range plan.Assignments {
+		podName := fmt.Sprintf("%s-%d", sc.dataNodeSfSet.Name, assignment.Ordinal)
+		pod, err := sc.podLister.Pods(nc.Namespace).Get(podName)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// Pod is yet to be created
+				continue
+			}
+			return err
+		}
+
+		if !hasZonePlacementGate(pod) {
+			// Pod has already been pinned to its zone
+			continue
+		}
+
+		// Add the zone to the node selector of the pod, and remove the gate
+		var schedulingGates []corev1.PodSchedulingGate
+		for _, gate := range pod.Spec.SchedulingGates {
+			if gate.Name != constants.DataNodeZonePlacementGate {
+				schedulingGates = append(schedulingGates, gate)
+			}
+		}
+		patch, err := json.Marshal(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"nodeSelector": map[string]string{
+					corev1.LabelTopologyZone: assignment.Zone,
+				},
+				"schedulingGates": schedulingGates,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err = sc.kubeClientset().CoreV1().Pods(pod.Namespace).Patch(
+			ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{FieldManager: fieldManager}); err != nil {
+			sc.logger.Error(err, "Failed to pin the data node pod to its zone", "pod", getNamespacedName(pod))
+			return err
+		}
+		sc.logger.Info("Pinned the data node pod to its planned zone",
+			"pod", getNamespacedName(pod), "zone", assignment.Zone, "nodeGroup", assignment.NodeGroup)
+	}
+
+	return nil
+}
+
+// verifyDataNodePlacement verifies the zones of the worker nodes running
+// the data node pods against the placement planned from the zones
+// specified in the spec, and sets the NdbClusterZoneRedundant condition
+// accordingly. A Warning event is recorded when a new violation is found.
+// The condition is left unchanged, and the error is returned, if the
+// placement could not be verified.
+func (sc *SyncContext) verifyDataNodePlacement(ctx context.Context) error {
+	nc := sc.ndb
+	zones := nc.Spec.DataNode.Zones
+	if len(zones) == 0 || sc.dataNodeSfSet == nil {
+		// Zones not specified or the data nodes are yet to be created
+		return nil
+	}
+
+	plan, err := placement.NewPlan(*sc.dataNodeSfSet.Spec.Replicas, sc.configSummary.RedundancyLevel, zones)
+	if err != nil {
+		sc.logger.Error(err, "Failed to plan the placement of the data nodes")
+		return err
+	}
+
+	podZones, err := sc.getPodZones([]*appsv1.StatefulSet{sc.dataNodeSfSet}, corev1.LabelTopologyZone)
+	if err != nil {
+		sc.logger.Error(err, "Failed to retrieve the zones of the data nodes")
+		return err
+	}
+
+	// Map the zones by the ordinals of the data node pods
+	dataNodeZones := make(map[int32]string, len(podZones))
+	for _, assignment := range plan.Assignments {
+		podName := fmt.Sprintf("%s-%d", sc.dataNodeSfSet.Name, assignment.Ordinal)
+		if zone, exists := podZones[podName]; exists {
+			dataNodeZones[assignment.Ordinal] = zone
+		}
+	}
+
+	zoneRedundantCondition := &v1.NdbClusterCondition{
+		Type:    v1.NdbClusterZoneRedundant,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1.NdbClusterZoneRedundantReasonPlacementVerified,
+		Message: fmt.Sprintf("The data nodes of every node group are placed in distinct zones among %v", zones),
+	}
+	if violations := plan.Verify(dataNodeZones); len(violations) != 0 {
+		zoneRedundantCondition.Status = corev1.ConditionFalse
+		zoneRedundantCondition.Reason = v1.NdbClusterZoneRedundantReasonPlacementViolated
+		zoneRedundantCondition.Message = "The data node placement violates the plan : " +
+			strings.Join(violations, "; ")
+	}
+
+	sc.updateCondition(zoneRedundantCondition, nc.GetZoneRedundantCondition(), conditionEvents{
+		failureStatus: corev1.ConditionFalse,
+		failureReason: ReasonPlacementViolated,
+		renotify:      true,
+	})
+
+	sc.zoneRedundantCondition = zoneRedundantCondition
+	return nil
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_verifyDataNodePlacement(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.Spec.DataNode.Zones = []string{"zone-a", "zone-b"}
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+
+	// Create the worker nodes in the two zones
+	for workerNodeName, zone := range map[string]string{
+		"worker-1": "zone-a",
+		"worker-2": "zone-b",
+	} {
+		workerNode := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   workerNodeName,
+				Labels: map[string]string{corev1.LabelTopologyZone: zone},
+			},
+		}
+		if err := f.k8sIf.Core().V1().Nodes().Informer().GetIndexer().Add(workerNode); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+
+	configSummary, err := ndbconfig.NewConfigSummary(resources.CreateConfigMap(ndb).Data)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	replicas := int32(2)
+	dataNodeSfset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: ndb.GetWorkloadName(constants.NdbNodeTypeNdbmtd), Namespace: ns},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	}
+
+	podIndexer := f.k8sIf.Core().V1().Pods().Informer().GetIndexer()
+	verifyPlacement := func(workerNodeNames map[string]string) *v1.NdbClusterCondition {
+		for podName, workerNodeName := range workerNodeNames {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: ns},
+				Spec:       corev1.PodSpec{NodeName: workerNodeName},
+			}
+			if err := podIndexer.Update(pod); err != nil {
+				t.Fatal("Unexpected error :", err)
+			}
+		}
+
+		sc := f.c.newSyncContext(ctx, ndb)
+		sc.dataNodeSfSet = dataNodeSfset
+		sc.configSummary = configSummary
+		if err := sc.verifyDataNodePlacement(ctx); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+		if sc.zoneRedundantCondition == nil {
+			t.Fatal("Expected the ZoneRedundant condition to be computed")
+		}
+		return sc.zoneRedundantCondition
+	}
+
+	// Data nodes of the node group placed in distinct zones
+	condition := verifyPlacement(map[string]string{
+		"test-ndbmtd-0": "worker-1",
+		"test-ndbmtd-1": "worker-2",
+	})
+	if condition.Status != corev1.ConditionTrue ||
+		condition.Reason != v1.NdbClusterZoneRedundantReasonPlacementVerified {
+		t.Errorf("Expected the placement to be verified but got the condition %v", condition)
+	}
+
+	// Data nodes of the node group placed in the same zone
+	condition = verifyPlacement(map[string]string{
+		"test-ndbmtd-0": "worker-2",
+		"test-ndbmtd-1": "worker-2",
+	})
+	if condition.Status != corev1.ConditionFalse ||
+		condition.Reason != v1.NdbClusterZoneRedundantReasonPlacementViolated {
+		t.Errorf("Expected the placement to be violated but got the condition %v", condition)
+	}
+}
+
+func Test_pinDataNodePodsToZones(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.Spec.DataNode.Zones = []string{"zone-a", "zone-b"}
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+	configSummary, err := ndbconfig.NewConfigSummary(resources.CreateConfigMap(ndb).Data)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	replicas := int32(2)
+	dataNodeSfset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: ndb.GetWorkloadName(constants.NdbNodeTypeNdbmtd), Namespace: ns},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	}
+
+	// The first pod is held by the gate and the second one has already been pinned
+	podIndexer := f.k8sIf.Core().V1().Pods().Informer().GetIndexer()
+	for _, pod := range []*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ndbmtd-0", Namespace: ns},
+			Spec: corev1.PodSpec{
+				SchedulingGates: []corev1.PodSchedulingGate{{Name: constants.DataNodeZonePlacementGate}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ndbmtd-1", Namespace: ns},
+			Spec: corev1.PodSpec{
+				NodeSelector: map[string]string{corev1.LabelTopologyZone: "zone-b"},
+			},
+		},
+	} {
+		if _, err = f.k8sclient.CoreV1().Pods(ns).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+		if err = podIndexer.Add(pod); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+
+	sc := f.c.newSyncContext(ctx, ndb)
+	sc.dataNodeSfSet = dataNodeSfset
+	sc.configSummary = configSummary
+	if err = sc.pinDataNodePodsToZones(ctx); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	// Only the gated pod should have been pinned, to the zone planned for it
+	pod, err := f.k8sclient.CoreV1().Pods(ns).Get(ctx, "test-ndbmtd-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if hasZonePlacementGate(pod) || pod.Spec.NodeSelector[corev1.LabelTopologyZone] != "zone-a" {
+		t.Errorf("Expected the pod to be pinned to zone-a but got the spec %v", pod.Spec)
+	}
+
+	patches := 0
+	for _, action := range f.k8sclient.Actions() {
+		if action.GetVerb() == "patch" && action.GetResource().Resource == "pods" {
+			patches++
+		}
+	}
+	if patches != 1 {
+		t.Errorf("Expected 1 pod to be patched but got %d", patches)
+	}
+}
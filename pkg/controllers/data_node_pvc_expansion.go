@@ -0,0 +1,200 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// pvcExpansionPollInterval is the interval at which the
+// progress of the data node PVC expansion is checked
+const pvcExpansionPollInterval = 15 * time.Second
+
+// isVolumeExpansionAllowed returns true if the storage
+// class of the given PVC allows expanding its volume
+func (sc *SyncContext) isVolumeExpansionAllowed(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		// The PVC is not bound via a storage class
+		return false, nil
+	}
+
+	storageClass, err := sc.kubeClientset().StorageV1().StorageClasses().Get(
+		ctx, *pvc.Spec.StorageClassName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion, nil
+}
+
+// expandPVC patches the storage request of the given PVC to the given size
+func (sc *SyncContext) expandPVC(ctx context.Context, pvc *corev1.PersistentVolumeClaim, size resource.Quantity) error {
+	patch := fmt.Sprintf(`{"spec":{"resources":{"requests":{%q:%q}}}}`, corev1.ResourceStorage, size.String())
+	_, err := sc.kubeClientset().CoreV1().PersistentVolumeClaims(pvc.Namespace).Patch(
+		ctx, pvc.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// hasFileSystemResizePending returns true if the volume of the given
+// PVC has been expanded but its file system will be expanded only when
+// the volume is mounted again by a new pod.
+func hasFileSystemResizePending(pvc *corev1.PersistentVolumeClaim) bool {
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Type == corev1.PersistentVolumeClaimFileSystemResizePending &&
+			condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// expandDataNodePVCs expands the PVCs of the data nodes to the storage
+// requested via spec.dataNode.pvcSpec, as the VolumeClaimTemplates of the
+// data node StatefulSet cannot be updated. The PVCs are expanded only if
+// their storage class allows it. A data node whose file system can be
+// expanded only offline is restarted, one at a time, to complete the
+// expansion. The progress is reported via the NdbClusterStorageResized
+// condition, and the sync is stopped until the expansion is complete, so
+// that the Disk Data files can use the expanded storage.
+func (sc *SyncContext) expandDataNodePVCs(ctx context.Context) syncResult {
+	nc := sc.ndb
+	pvcSpec := nc.Spec.DataNode.PVCSpec
+	if pvcSpec == nil || sc.dataNodeSfSet == nil {
+		// Data nodes do not use PVCs or are yet to be created
+		return continueProcessing()
+	}
+
+	requestedStorage, exists := pvcSpec.Resources.Requests[corev1.ResourceStorage]
+	if !exists {
+		// No storage requested in the spec
+		return continueProcessing()
+	}
+
+	var resizingPVCs, notExpandablePVCs []string
+	restartOrdinal := int32(-1)
+	for ordinal := int32(0); ordinal < *sc.dataNodeSfSet.Spec.Replicas; ordinal++ {
+		podName := fmt.Sprintf("%s-%d", sc.dataNodeSfSet.Name, ordinal)
+		pvcName := statefulset.GetDataNodePVCName(podName)
+		pvc, err := sc.kubeClientset().CoreV1().PersistentVolumeClaims(nc.Namespace).Get(ctx, pvcName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// PVC will be created along with the data node pod
+				continue
+			}
+			sc.logger.Error(err, "Failed to retrieve the PVC", "pvc", getNamespacedName2(nc.Namespace, pvcName))
+			return errorWhileProcessing(err)
+		}
+
+		if pvcStorage := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; pvcStorage.Cmp(requestedStorage) < 0 {
+			// The PVC has to be expanded
+			allowed, err := sc.isVolumeExpansionAllowed(ctx, pvc)
+			if err != nil {
+				sc.logger.Error(err, "Failed to retrieve the storage class of the PVC", "pvc", getNamespacedName(pvc))
+				return errorWhileProcessing(err)
+			}
+
+			if !allowed {
+				notExpandablePVCs = append(notExpandablePVCs, pvcName)
+				continue
+			}
+
+			if err = sc.expandPVC(ctx, pvc, requestedStorage); err != nil {
+				sc.logger.Error(err, "Failed to expand the PVC", "pvc", getNamespacedName(pvc))
+				return errorWhileProcessing(err)
+			}
+
+			sc.logger.Info("PVC is being expanded", "pvc", getNamespacedName(pvc), "storage", requestedStorage.String())
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonPVCExpanding, ActionUpdated,
+				"PVC %q is being expanded to %s", pvcName, requestedStorage.String())
+			resizingPVCs = append(resizingPVCs, pvcName)
+			continue
+		}
+
+		if pvcCapacity := pvc.Status.Capacity[corev1.ResourceStorage]; pvcCapacity.Cmp(requestedStorage) < 0 {
+			// The PVC is still being expanded
+			resizingPVCs = append(resizingPVCs, pvcName)
+			if restartOrdinal == -1 && hasFileSystemResizePending(pvc) {
+				restartOrdinal = ordinal
+			}
+		}
+	}
+
+	storageResizedCondition := &v1.NdbClusterCondition{
+		Type:    v1.NdbClusterStorageResized,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1.NdbClusterStorageResizedReasonResizeComplete,
+		Message: fmt.Sprintf("The PVCs of all the data nodes have the requested storage %s", requestedStorage.String()),
+	}
+	if len(notExpandablePVCs) != 0 {
+		storageResizedCondition.Status = corev1.ConditionFalse
+		storageResizedCondition.Reason = v1.NdbClusterStorageResizedReasonResizeNotAllowed
+		storageResizedCondition.Message = fmt.Sprintf(
+			"The storage class does not allow expanding the PVCs %s to %s",
+			strings.Join(notExpandablePVCs, ", "), requestedStorage.String())
+	} else if len(resizingPVCs) != 0 {
+		storageResizedCondition.Status = corev1.ConditionFalse
+		storageResizedCondition.Reason = v1.NdbClusterStorageResizedReasonResizeInProgress
+		storageResizedCondition.Message = fmt.Sprintf(
+			"The PVCs %s are being expanded to %s", strings.Join(resizingPVCs, ", "), requestedStorage.String())
+	}
+
+	// Retain the last transition time if the status has not changed
+	storageResizedCondition.LastTransitionTime = metav1.Now()
+	previousCondition := nc.GetStorageResizedCondition()
+	if previousCondition != nil && previousCondition.Status == storageResizedCondition.Status {
+		storageResizedCondition.LastTransitionTime = previousCondition.LastTransitionTime
+	}
+
+	// Record an event if the PVCs cannot be expanded
+	if storageResizedCondition.Reason == v1.NdbClusterStorageResizedReasonResizeNotAllowed &&
+		(previousCondition == nil || previousCondition.Message != storageResizedCondition.Message) {
+		sc.logger.Info("Data node PVCs cannot be expanded", "pvcs", notExpandablePVCs)
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeWarning,
+			ReasonPVCExpansionNotAllowed, ActionNone, storageResizedCondition.Message)
+	}
+
+	sc.storageResizedCondition = storageResizedCondition
+
+	if storageResizedCondition.Reason != v1.NdbClusterStorageResizedReasonResizeInProgress {
+		// Either the expansion is complete or it cannot be done
+		return continueProcessing()
+	}
+
+	if restartOrdinal != -1 {
+		// Restart the data node to expand its file system. All the
+		// data nodes are ready at this point, so restarting one of
+		// them does not affect the availability of the MySQL Cluster.
+		if _, err := sc.deletePodOnStsUpdate(ctx, sc.dataNodeSfSet, restartOrdinal); err != nil {
+			return errorWhileProcessing(err)
+		}
+
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonDataNodeRestarting, ActionRestart,
+			"Data node pod %q is being restarted to expand the file system of its PVC",
+			fmt.Sprintf("%s-%d", sc.dataNodeSfSet.Name, restartOrdinal))
+		// Stop processing. Reconciliation will continue
+		// once the StatefulSet is fully ready again.
+		return finishProcessing()
+	}
+
+	// Check the progress again later as the PVC updates do not trigger a sync
+	sc.logger.Info("Waiting for the data node PVCs to be expanded", "pvcs", resizingPVCs)
+	sc.requeueAfter = pvcExpansionPollInterval
+	return finishProcessing()
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_expandDataNodePVCs(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.Spec.DataNode.PVCSpec = &corev1.PersistentVolumeClaimSpec{
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")},
+		},
+	}
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+
+	// Create the storage classes
+	for storageClassName, allowVolumeExpansion := range map[string]bool{
+		"expandable":     true,
+		"not-expandable": false,
+	} {
+		allowVolumeExpansion := allowVolumeExpansion
+		storageClass := &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: storageClassName},
+			Provisioner:          "example.com/provisioner",
+			AllowVolumeExpansion: &allowVolumeExpansion,
+		}
+		if _, err := f.k8sclient.StorageV1().StorageClasses().Create(ctx, storageClass, metav1.CreateOptions{}); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+
+	replicas := int32(2)
+	dataNodeSfset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: ndb.GetWorkloadName(constants.NdbNodeTypeNdbmtd), Namespace: ns},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	}
+
+	// Create the data node PVCs with the old storage
+	createPVCs := func(storageClassName string) {
+		for _, podName := range []string{"test-ndbmtd-0", "test-ndbmtd-1"} {
+			storage := resource.MustParse("10Gi")
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: statefulset.GetDataNodePVCName(podName), Namespace: ns},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					StorageClassName: &storageClassName,
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: storage},
+					},
+				},
+				Status: corev1.PersistentVolumeClaimStatus{
+					Capacity: corev1.ResourceList{corev1.ResourceStorage: storage},
+				},
+			}
+			// Delete the PVC, if it exists, before creating it
+			pvcInterface := f.k8sclient.CoreV1().PersistentVolumeClaims(ns)
+			_ = pvcInterface.Delete(ctx, pvc.Name, metav1.DeleteOptions{})
+			if _, err := pvcInterface.Create(ctx, pvc, metav1.CreateOptions{}); err != nil {
+				t.Fatal("Unexpected error :", err)
+			}
+		}
+	}
+
+	expandPVCs := func() (*v1.NdbClusterCondition, bool) {
+		sc := f.c.newSyncContext(ctx, ndb)
+		sc.dataNodeSfSet = dataNodeSfset
+		sr := sc.expandDataNodePVCs(ctx)
+		if err := sr.getError(); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+		if sc.storageResizedCondition == nil {
+			t.Fatal("Expected the StorageResized condition to be computed")
+		}
+		return sc.storageResizedCondition, sr.stopSync()
+	}
+
+	// PVCs with a storage class that doesn't allow expansion
+	createPVCs("not-expandable")
+	condition, stopSync := expandPVCs()
+	if stopSync || condition.Status != corev1.ConditionFalse ||
+		condition.Reason != v1.NdbClusterStorageResizedReasonResizeNotAllowed {
+		t.Errorf("Expected the expansion to be not allowed but got the condition %v", condition)
+	}
+
+	// PVCs with a storage class that allows expansion
+	createPVCs("expandable")
+	condition, stopSync = expandPVCs()
+	if !stopSync || condition.Status != corev1.ConditionFalse ||
+		condition.Reason != v1.NdbClusterStorageResizedReasonResizeInProgress {
+		t.Errorf("Expected the expansion to be in progress but got the condition %v", condition)
+	}
+
+	// The PVCs should have been patched with the new storage
+	pvcList, err := f.k8sclient.CoreV1().PersistentVolumeClaims(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		if storage := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; storage.Cmp(resource.MustParse("20Gi")) != 0 {
+			t.Errorf("Expected PVC %q to request 20Gi but got %s", pvc.Name, storage.String())
+		}
+
+		// Complete the expansion
+		pvc.Status.Capacity[corev1.ResourceStorage] = resource.MustParse("20Gi")
+		if _, err = f.k8sclient.CoreV1().PersistentVolumeClaims(ns).UpdateStatus(ctx, pvc, metav1.UpdateOptions{}); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+
+	condition, stopSync = expandPVCs()
+	if stopSync || condition.Status != corev1.ConditionTrue ||
+		condition.Reason != v1.NdbClusterStorageResizedReasonResizeComplete {
+		t.Errorf("Expected the expansion to be complete but got the condition %v", condition)
+	}
+}
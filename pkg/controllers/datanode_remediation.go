@@ -0,0 +1,490 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// defaultDataNodeRestartThreshold is the number of container restarts
+	// after which a failing data node is restarted with an initial restart,
+	// when the NdbCluster spec doesn't specify a threshold.
+	defaultDataNodeRestartThreshold = 3
+	// dataNodeFailureWindow is the window within which the container
+	// restarts of a data node are counted towards the restartThreshold.
+	dataNodeFailureWindow = 15 * time.Minute
+)
+
+// dataNodeFailures records the container restarts
+// of a data node pod observed by the operator
+type dataNodeFailures struct {
+	// podUID is the UID of the pod the restarts were observed in
+	podUID types.UID
+	// restartCount is the last observed restart count of the container
+	restartCount int32
+	// failureTimes are the times at which the restarts were observed
+	failureTimes []time.Time
+	// restartTime is the time at which the operator last restarted
+	// the data node, by recreating its pod, to recover it
+	restartTime time.Time
+}
+
+// dataNodeFailureTracker tracks the consecutive container restarts of
+// the data nodes that fail to become ready, so that only the data nodes
+// crash looping right now are remediated, and not the ones that were
+// restarted at some point of their lifetime by an OOM kill or an upgrade.
+type dataNodeFailureTracker struct {
+	// failures holds the observed restarts keyed by
+	// the NdbCluster key and then by the pod name
+	failures map[string]map[string]*dataNodeFailures
+	// mutex protects the failures map
+	mutex sync.Mutex
+}
+
+// newDataNodeFailureTracker creates a new dataNodeFailureTracker
+func newDataNodeFailureTracker() *dataNodeFailureTracker {
+	return &dataNodeFailureTracker{
+		failures: make(map[string]map[string]*dataNodeFailures),
+	}
+}
+
+// recordFailures records the restarts of the given data node container
+// that have happened since it was last observed, and returns the number
+// of restarts observed within the dataNodeFailureWindow. The restarts
+// done before the pod was first observed are not counted. A pod recreated
+// with the same name starts with no restarts, but the time at which the
+// operator restarted the data node is retained.
+func (t *dataNodeFailureTracker) recordFailures(
+	key, podName string, podUID types.UID, restartCount int32, now time.Time) int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.failures[key] == nil {
+		t.failures[key] = make(map[string]*dataNodeFailures)
+	}
+	failures, exists := t.failures[key][podName]
+	if !exists {
+		t.failures[key][podName] = &dataNodeFailures{podUID: podUID, restartCount: restartCount}
+		return 0
+	}
+	if failures.podUID != podUID {
+		// The pod has been recreated
+		failures.podUID = podUID
+		failures.restartCount = restartCount
+		failures.failureTimes = nil
+		return 0
+	}
+
+	for ; failures.restartCount < restartCount; failures.restartCount++ {
+		failures.failureTimes = append(failures.failureTimes, now)
+	}
+
+	// Discard the restarts that happened before the window
+	recentFailures := failures.failureTimes[:0]
+	for _, failureTime := range failures.failureTimes {
+		if now.Sub(failureTime) < dataNodeFailureWindow {
+			recentFailures = append(recentFailures, failureTime)
+		}
+	}
+	failures.failureTimes = recentFailures
+	return len(recentFailures)
+}
+
+// recordRestart records that the operator restarted the
+// data node running in the pod with the given name
+func (t *dataNodeFailureTracker) recordRestart(key, podName string, now time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if failures, exists := t.failures[key][podName]; exists {
+		failures.restartTime = now
+	}
+}
+
+// restartedRecently returns true if the operator restarted the data node
+// running in the pod with the given name within the dataNodeFailureWindow
+func (t *dataNodeFailureTracker) restartedRecently(key, podName string, now time.Time) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	failures, exists := t.failures[key][podName]
+	return exists && !failures.restartTime.IsZero() &&
+		now.Sub(failures.restartTime) < dataNodeFailureWindow
+}
+
+// reset discards the restarts observed for the pod with the given name
+func (t *dataNodeFailureTracker) reset(key, podName string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.failures[key], podName)
+}
+
+// prune discards the restarts observed for the pods
+// of the NdbCluster that are not in the given podNames
+func (t *dataNodeFailureTracker) prune(key string, podNames map[string]bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for podName := range t.failures[key] {
+		if !podNames[podName] {
+			delete(t.failures[key], podName)
+		}
+	}
+}
+
+// forget removes the observed restarts of the NdbCluster with the given key
+func (t *dataNodeFailureTracker) forget(key string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.failures, key)
+}
+
+// isCrashLooping returns true if the kubelet is backing
+// off restarting the container after its repeated failures
+func isCrashLooping(containerStatus *corev1.ContainerStatus) bool {
+	return containerStatus.State.Waiting != nil &&
+		containerStatus.State.Waiting.Reason == "CrashLoopBackOff"
+}
+
+// getNodeGroupPeers returns the node ids of the other data nodes in the
+// node group of the data node with the given nodeId. The operator assigns
+// the node groups in the order of the node ids, so every consecutive
+// redundancyLevel number of data nodes, starting from firstDataNodeId,
+// form a node group.
+func getNodeGroupPeers(nodeId, firstDataNodeId, redundancyLevel int) []int {
+	firstNodeIdInGroup := nodeId - (nodeId-firstDataNodeId)%redundancyLevel
+	var peers []int
+	for peerNodeId := firstNodeIdInGroup; peerNodeId < firstNodeIdInGroup+redundancyLevel; peerNodeId++ {
+		if peerNodeId != nodeId {
+			peers = append(peers, peerNodeId)
+		}
+	}
+	return peers
+}
+
+// remediateDataNodes checks if any of the data nodes are crash looping,
+// i.e. their containers are in CrashLoopBackOff after having been
+// restarted by the kubelet, due to a failure or a failed startup probe,
+// at least restartThreshold times within the dataNodeFailureWindow
+// without becoming ready in between. The kubelet's restarts are the first
+// step of the recovery. If they don't help, the data node is restarted by
+// recreating its pod, and if the data node fails again within the
+// dataNodeFailureWindow, it is restarted with an initial restart. Only one
+// data node is remediated per sync.
+func (sc *SyncContext) remediateDataNodes(ctx context.Context) syncResult {
+	nc := sc.ndb
+	remediationSpec := nc.Spec.DataNode.Remediation
+	ndbmtdSfset := sc.dataNodeSfSet
+	if remediationSpec == nil || statefulsetReady(ndbmtdSfset) {
+		// Remediation is disabled or all data nodes are ready
+		return continueProcessing()
+	}
+
+	restartThreshold := remediationSpec.RestartThreshold
+	if restartThreshold == 0 {
+		restartThreshold = defaultDataNodeRestartThreshold
+	}
+
+	// Discard the restarts observed for the data nodes that no longer exist
+	ndbKey := getNdbClusterKey(nc)
+	podNames := make(map[string]bool)
+	for podOrdinal := 0; podOrdinal < int(*ndbmtdSfset.Spec.Replicas); podOrdinal++ {
+		podNames[fmt.Sprintf("%s-%d", ndbmtdSfset.Name, podOrdinal)] = true
+	}
+	sc.dataNodeFailures.prune(ndbKey, podNames)
+
+	for podOrdinal := 0; podOrdinal < int(*ndbmtdSfset.Spec.Replicas); podOrdinal++ {
+		podName := fmt.Sprintf("%s-%d", ndbmtdSfset.Name, podOrdinal)
+		pod, err := sc.podLister.Pods(ndbmtdSfset.Namespace).Get(podName)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// Pod is yet to be created by the StatefulSet controller
+				continue
+			}
+			sc.logger.Error(err, "Failed to retrieve the pod", "pod", getNamespacedName2(ndbmtdSfset.Namespace, podName))
+			return errorWhileProcessing(err)
+		}
+
+		if pod.DeletionTimestamp != nil {
+			// Pod is being deleted
+			continue
+		}
+
+		for i := range pod.Status.ContainerStatuses {
+			containerStatus := &pod.Status.ContainerStatuses[i]
+			if containerStatus.Name != statefulset.GetDataNodeContainerName() {
+				continue
+			}
+
+			if containerStatus.Ready {
+				// The data node has recovered - start counting afresh
+				sc.dataNodeFailures.reset(ndbKey, podName)
+				continue
+			}
+
+			now := time.Now()
+			recentFailures := sc.dataNodeFailures.recordFailures(
+				ndbKey, podName, pod.UID, containerStatus.RestartCount, now)
+			if !isCrashLooping(containerStatus) || recentFailures < int(restartThreshold) {
+				continue
+			}
+
+			// The data node has failed to start even after being restarted
+			// restartThreshold times by the kubelet.
+			nodeId := sc.getDataNodeId(podName)
+			sc.recorder.Eventf(nc, pod, corev1.EventTypeWarning, ReasonDataNodeFailing, ActionNone,
+				"Data node (nodeId=%d) failed to start after %d consecutive restarts", nodeId, recentFailures)
+
+			if !sc.dataNodeFailures.restartedRecently(ndbKey, podName, now) {
+				// Restart the data node by recreating its pod
+				sr := sc.restartFailingDataNode(ctx, pod, nodeId)
+				if sr.stopSync() && sr.getError() == nil {
+					sc.dataNodeFailures.recordRestart(ndbKey, podName, now)
+				}
+				return sr
+			}
+
+			// The data node failed again after being restarted
+			// by the operator. Attempt an initial restart.
+			sr := sc.initialRestartDataNode(ctx, pod, nodeId, false)
+			if sr.stopSync() && sr.getError() == nil {
+				sc.dataNodeFailures.reset(ndbKey, podName)
+			}
+			return sr
+		}
+	}
+
+	return continueProcessing()
+}
+
+// getDataNodeFailureStatus retrieves the status of the MySQL Cluster from
+// the Management Server, and returns it along with true if the data node
+// with the given nodeId has neither started nor is making progress
+// through its start phases.
+func (sc *SyncContext) getDataNodeFailureStatus(
+	ctx context.Context, nodeId int) (mgmapi.ClusterStatus, bool, error) {
+	mgmClient, err := mgmapi.NewMgmClientWithContext(ctx, sc.ndb.GetConnectstring())
+	if err != nil {
+		return nil, false, err
+	}
+	defer mgmClient.Disconnect()
+
+	clusterStatus, err := mgmClient.GetStatus()
+	if err != nil {
+		sc.logger.Error(err, "Error getting cluster status from management server")
+		return nil, false, err
+	}
+
+	if nodeStatus, exists := clusterStatus[nodeId]; exists && (nodeStatus.IsConnected || nodeStatus.IsStarting()) {
+		return clusterStatus, false, nil
+	}
+	return clusterStatus, true, nil
+}
+
+// restartFailingDataNode restarts the data node running in the given pod by
+// deleting the pod, so that the StatefulSet controller recreates it and
+// the data node starts afresh, without the kubelet's back off delay and
+// possibly on another worker node, but with its existing data.
+func (sc *SyncContext) restartFailingDataNode(ctx context.Context, pod *corev1.Pod, nodeId int) syncResult {
+	nc := sc.ndb
+	_, failed, err := sc.getDataNodeFailureStatus(ctx, nodeId)
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+	if !failed {
+		// The data node has started, or is making progress
+		// through its start phases, by now - nothing to remediate
+		return continueProcessing()
+	}
+
+	if err = sc.kubeClientset().CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil {
+		sc.logger.Error(err, "Failed to delete the pod", "pod", getNamespacedName(pod))
+		return errorWhileProcessing(err)
+	}
+
+	sc.logger.Info("Failing data node is being restarted", "nodeId", nodeId)
+	sc.recorder.Eventf(nc, pod, corev1.EventTypeNormal, ReasonDataNodeRestarting, ActionRestart,
+		"Data node (nodeId=%d) is being restarted to recover it", nodeId)
+
+	// Stop processing. Reconciliation will continue
+	// once the StatefulSet is fully ready again.
+	return finishProcessing()
+}
+
+// deleteDataNodeFileSystemPVCs deletes the PVCs holding the data directory
+// and the file system of the data node running in the given pod. It is
+// called after deleting the pod, so that the pod is not recreated with the
+// PVCs while they are still in use. The PVCs will be deleted only after the
+// pod is gone, and the StatefulSet controller will create new PVCs for the
+// new pod. The PVCs of the backups, if any, are retained.
+func (sc *SyncContext) deleteDataNodeFileSystemPVCs(ctx context.Context, namespace, podName string) error {
+	for _, pvcName := range sc.getDataNodeFileSystemPVCNames(podName) {
+		err := sc.kubeClientset().CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, pvcName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			sc.logger.Error(err, "Failed to delete the PVC", "pvc", getNamespacedName2(namespace, pvcName))
+			return err
+		}
+	}
+	return nil
+}
+
+// getDataNodeFileSystemPVCNames returns the names of the PVCs holding the data
+// directory and the file system of the data node running in the given pod
+func (sc *SyncContext) getDataNodeFileSystemPVCNames(podName string) []string {
+	nc := sc.ndb
+	pvcNames := statefulset.GetDataNodeFileSystemPVCNames(nc, podName)
+	if nc.Spec.DataNode.PVCSpec != nil {
+		pvcNames = append(pvcNames, statefulset.GetDataNodePVCName(podName))
+	}
+	return pvcNames
+}
+
+// recreateDataNodePodsWithDeletedPVCs deletes the data node pods that are
+// yet to be started and that have been created with the PVCs deleted by an
+// initial restart, which were still being deleted when the StatefulSet
+// controller recreated the pods. Such a pod cannot be scheduled, and is
+// deleted so that the StatefulSet controller recreates it, along with new
+// PVCs, once the deleted PVCs are gone.
+func (sc *SyncContext) recreateDataNodePodsWithDeletedPVCs(ctx context.Context) syncResult {
+	ndbmtdSfset := sc.dataNodeSfSet
+	if ndbmtdSfset == nil || statefulsetReady(ndbmtdSfset) {
+		return continueProcessing()
+	}
+
+	namespace := ndbmtdSfset.Namespace
+	podDeleted := false
+	for podOrdinal := 0; podOrdinal < int(*ndbmtdSfset.Spec.Replicas); podOrdinal++ {
+		podName := fmt.Sprintf("%s-%d", ndbmtdSfset.Name, podOrdinal)
+		pod, err := sc.podLister.Pods(namespace).Get(podName)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// Pod is yet to be created by the StatefulSet controller
+				continue
+			}
+			sc.logger.Error(err, "Failed to retrieve the pod", "pod", getNamespacedName2(namespace, podName))
+			return errorWhileProcessing(err)
+		}
+
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodPending {
+			// Pod is being deleted or has already started
+			continue
+		}
+
+		for _, pvcName := range sc.getDataNodeFileSystemPVCNames(podName) {
+			pvc, err := sc.kubeClientset().CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, metav1.GetOptions{})
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					// PVC is yet to be created by the StatefulSet controller
+					continue
+				}
+				sc.logger.Error(err, "Failed to retrieve the PVC", "pvc", getNamespacedName2(namespace, pvcName))
+				return errorWhileProcessing(err)
+			}
+
+			if pvc.DeletionTimestamp == nil {
+				continue
+			}
+
+			// The pod has been created with a PVC that is being deleted
+			sc.logger.Info("Deleting the pod created with a PVC that is being deleted",
+				"pod", getNamespacedName(pod), "pvc", pvcName)
+			err = sc.kubeClientset().CoreV1().Pods(namespace).Delete(ctx, podName, metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				sc.logger.Error(err, "Failed to delete the pod", "pod", getNamespacedName(pod))
+				return errorWhileProcessing(err)
+			}
+			podDeleted = true
+			break
+		}
+	}
+
+	if podDeleted {
+		// Wait for the StatefulSet controller to recreate the pods
+		return finishProcessing()
+	}
+	return continueProcessing()
+}
+
+// initialRestartDataNode restarts the data node running in the given pod
+// with an initial restart, by deleting the pod and then its
+// PersistentVolumeClaims, if any. As the data node is started without any
+// data, it recovers all its data from the other data nodes of its node
+// group. So, the initial restart is performed only if at least one other
+// data node of the same node group is connected to the MySQL Cluster.
+// The pod is deleted immediately, without waiting for the kubelet to
+// confirm its termination, if forceDelete is true.
+func (sc *SyncContext) initialRestartDataNode(
+	ctx context.Context, pod *corev1.Pod, nodeId int, forceDelete bool) syncResult {
+	nc := sc.ndb
+
+	clusterStatus, failed, err := sc.getDataNodeFailureStatus(ctx, nodeId)
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+	if !failed {
+		// The data node has started, or is making progress
+		// through its start phases, by now - nothing to remediate
+		return continueProcessing()
+	}
+
+	// Verify that a peer from the node group is available
+	// to restore the data of the restarted data node.
+	peerAvailable := false
+	firstDataNodeId := sc.configSummary.GetFirstDataNodeId()
+	for _, peerNodeId := range getNodeGroupPeers(nodeId, firstDataNodeId, int(sc.configSummary.RedundancyLevel)) {
+		if peerStatus, exists := clusterStatus[peerNodeId]; exists && peerStatus.IsConnected {
+			peerAvailable = true
+			break
+		}
+	}
+
+	if !peerAvailable {
+		// The initial restart will lose data if none of the peers are
+		// available. Leave the data node to be recovered manually.
+		sc.logger.Info("Skipping initial restart of data node as no other data node of its node group is connected", "nodeId", nodeId)
+		sc.recorder.Eventf(nc, pod, corev1.EventTypeWarning, ReasonInitialRestartBlocked, ActionNone,
+			"Initial restart of data node (nodeId=%d) skipped as no other data node of its node group is connected", nodeId)
+		return continueProcessing()
+	}
+
+	// Delete the pod. The StatefulSet controller will recreate it.
+	deleteOptions := metav1.DeleteOptions{}
+	if forceDelete {
+		gracePeriodSeconds := int64(0)
+		deleteOptions.GracePeriodSeconds = &gracePeriodSeconds
+	}
+	if err = sc.kubeClientset().CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, deleteOptions); err != nil {
+		sc.logger.Error(err, "Failed to delete the pod", "pod", getNamespacedName(pod))
+		return errorWhileProcessing(err)
+	}
+
+	// Delete the PVCs so that the data node starts with an empty data
+	// directory and file system.
+	if err = sc.deleteDataNodeFileSystemPVCs(ctx, pod.Namespace, pod.Name); err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	sc.logger.Info("Data node is being restarted with an initial restart", "nodeId", nodeId)
+	sc.recorder.Eventf(nc, pod, corev1.EventTypeNormal, ReasonInitialRestart, ActionInitialRestart,
+		"Data node (nodeId=%d) is being restarted with an initial restart", nodeId)
+
+	// Stop processing. Reconciliation will continue
+	// once the StatefulSet is fully ready again.
+	return finishProcessing()
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+	"github.com/mysql/ndb-operator/pkg/resources"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_getNodeGroupPeers(t *testing.T) {
+	for _, tc := range []struct {
+		nodeId          int
+		firstDataNodeId int
+		redundancyLevel int
+		expectedPeers   []int
+	}{
+		{nodeId: 2, firstDataNodeId: 2, redundancyLevel: 2, expectedPeers: []int{3}},
+		{nodeId: 3, firstDataNodeId: 2, redundancyLevel: 2, expectedPeers: []int{2}},
+		{nodeId: 5, firstDataNodeId: 3, redundancyLevel: 2, expectedPeers: []int{6}},
+		{nodeId: 7, firstDataNodeId: 3, redundancyLevel: 3, expectedPeers: []int{6, 8}},
+		{nodeId: 2, firstDataNodeId: 2, redundancyLevel: 1, expectedPeers: nil},
+	} {
+		peers := getNodeGroupPeers(tc.nodeId, tc.firstDataNodeId, tc.redundancyLevel)
+		if !reflect.DeepEqual(peers, tc.expectedPeers) {
+			t.Errorf("getNodeGroupPeers(%d, %d, %d) returned %v, expected %v",
+				tc.nodeId, tc.firstDataNodeId, tc.redundancyLevel, peers, tc.expectedPeers)
+		}
+	}
+}
+
+func Test_dataNodeFailureTracker(t *testing.T) {
+	tracker := newDataNodeFailureTracker()
+	now := time.Now()
+
+	// Restarts done before the pod was first observed are not counted
+	if failures := tracker.recordFailures("ns/test", "test-ndbmtd-0", "pod-uid", 5, now); failures != 0 {
+		t.Errorf("Expected no failures on the first observation but got %d", failures)
+	}
+	if failures := tracker.recordFailures("ns/test", "test-ndbmtd-0", "pod-uid", 7, now.Add(time.Minute)); failures != 2 {
+		t.Errorf("Expected 2 failures but got %d", failures)
+	}
+
+	// Restarts older than the window are discarded
+	if failures := tracker.recordFailures(
+		"ns/test", "test-ndbmtd-0", "pod-uid", 8, now.Add(dataNodeFailureWindow+2*time.Minute)); failures != 1 {
+		t.Errorf("Expected 1 failure within the window but got %d", failures)
+	}
+
+	// A recreated pod starts afresh, but the restart done by the operator is retained
+	tracker.recordRestart("ns/test", "test-ndbmtd-0", now)
+	if failures := tracker.recordFailures("ns/test", "test-ndbmtd-0", "new-pod-uid", 1, now); failures != 0 {
+		t.Errorf("Expected no failures for a recreated pod but got %d", failures)
+	}
+	if !tracker.restartedRecently("ns/test", "test-ndbmtd-0", now.Add(time.Minute)) {
+		t.Error("The restart done by the operator should have been retained")
+	}
+	if tracker.restartedRecently("ns/test", "test-ndbmtd-0", now.Add(dataNodeFailureWindow)) {
+		t.Error("The restart done by the operator before the window should be ignored")
+	}
+
+	// Pod becoming ready resets the failures
+	tracker.reset("ns/test", "test-ndbmtd-0")
+	if failures := tracker.recordFailures("ns/test", "test-ndbmtd-0", "new-pod-uid", 9, now); failures != 0 {
+		t.Errorf("Expected no failures after a reset but got %d", failures)
+	}
+
+	// Pods that no longer exist are pruned
+	tracker.recordFailures("ns/test", "test-ndbmtd-1", "pod-1-uid", 0, now)
+	tracker.prune("ns/test", map[string]bool{"test-ndbmtd-0": true})
+	if _, exists := tracker.failures["ns/test"]["test-ndbmtd-1"]; exists {
+		t.Error("The failures of the removed pod should have been pruned")
+	}
+	if _, exists := tracker.failures["ns/test"]["test-ndbmtd-0"]; !exists {
+		t.Error("The failures of the existing pod should have been retained")
+	}
+}
+
+func Test_remediateDataNodes(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.Spec.DataNode.Remediation = &v1.NdbDataNodeRemediationSpec{RestartThreshold: 2}
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	configSummary, err := ndbconfig.NewConfigSummary(resources.CreateConfigMap(ndb).Data)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	replicas := int32(2)
+	dataNodeSfset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: ndb.GetWorkloadName(constants.NdbNodeTypeNdbmtd), Namespace: ns},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1},
+	}
+
+	// Data node that was restarted several times in its lifetime
+	// and is now rejoining the MySQL Cluster after an OOM kill
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ndbmtd-1", Namespace: ns, UID: "ndbmtd-1-uid"},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         statefulset.GetDataNodeContainerName(),
+				RestartCount: 5,
+				State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}},
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err = f.k8sclient.CoreV1().Pods(ns).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	podIndexer := f.k8sIf.Core().V1().Pods().Informer().GetIndexer()
+	if err = podIndexer.Add(pod); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	remediate := func() syncResult {
+		sc := f.c.newSyncContext(ctx, ndb)
+		sc.dataNodeSfSet = dataNodeSfset
+		sc.configSummary = configSummary
+		return sc.remediateDataNodes(ctx)
+	}
+
+	// The lifetime restarts should not trigger the remediation
+	if sr := remediate(); sr.stopSync() {
+		t.Fatalf("Unexpected remediation of a data node that is not crash looping, error : %v", sr.getError())
+	}
+
+	// A restart that puts the data node in CrashLoopBackOff is
+	// still below the threshold of the consecutive restarts
+	crashLoop := func(restartCount int32) {
+		pod = pod.DeepCopy()
+		pod.Status.ContainerStatuses[0].RestartCount = restartCount
+		pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+		}
+		if err = podIndexer.Update(pod); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+	crashLoop(6)
+	if sr := remediate(); sr.stopSync() {
+		t.Fatalf("Unexpected remediation of a data node below the threshold, error : %v", sr.getError())
+	}
+
+	// The data node should be remediated once it reaches the threshold. The
+	// remediation stops the sync with an error here as the Management
+	// Server is not reachable to verify that the data node is not starting.
+	crashLoop(7)
+	if sr := remediate(); !sr.stopSync() {
+		t.Fatal("Expected the crash looping data node to be remediated")
+	}
+	if _, err = f.k8sclient.CoreV1().Pods(ns).Get(ctx, pod.Name, metav1.GetOptions{}); err != nil {
+		t.Error("Pod deleted without verifying the status of the data node :", err)
+	}
+}
+
+func Test_recreateDataNodePodsWithDeletedPVCs(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.Spec.DataNode.PVCSpec = &corev1.PersistentVolumeClaimSpec{}
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	replicas := int32(2)
+	dataNodeSfset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: ndb.GetWorkloadName(constants.NdbNodeTypeNdbmtd), Namespace: ns},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	podIndexer := f.k8sIf.Core().V1().Pods().Informer().GetIndexer()
+	for _, podName := range []string{"test-ndbmtd-0", "test-ndbmtd-1"} {
+		// Both the pods are pending, but only the PVC of the
+		// pod test-ndbmtd-1 is still being deleted
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: ns},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		}
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: statefulset.GetDataNodePVCName(podName), Namespace: ns},
+		}
+		if podName == "test-ndbmtd-1" {
+			deletionTime := metav1.Now()
+			pvc.DeletionTimestamp = &deletionTime
+		}
+		if _, err := f.k8sclient.CoreV1().Pods(ns).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+		if _, err := f.k8sclient.CoreV1().PersistentVolumeClaims(ns).Create(ctx, pvc, metav1.CreateOptions{}); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+		if err := podIndexer.Add(pod); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+
+	sc := f.c.newSyncContext(ctx, ndb)
+	sc.dataNodeSfSet = dataNodeSfset
+	if sr := sc.recreateDataNodePodsWithDeletedPVCs(ctx); !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to wait for the pod to be recreated, error : %v", sr.getError())
+	}
+
+	if _, err := f.k8sclient.CoreV1().Pods(ns).Get(ctx, "test-ndbmtd-0", metav1.GetOptions{}); err != nil {
+		t.Error("Pod with an existing PVC should not have been deleted :", err)
+	}
+	if _, err := f.k8sclient.CoreV1().Pods(ns).Get(ctx, "test-ndbmtd-1", metav1.GetOptions{}); err == nil {
+		t.Error("Pod with a PVC that is being deleted should have been deleted")
+	}
+}
@@ -0,0 +1,328 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller"
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// maxDataNodeRestartAttempts is the number of failed restarts of a data
+	// node, done to apply the same config version, after which the operator
+	// considers the restarts to be looping and stops restarting it.
+	maxDataNodeRestartAttempts = 3
+	// dataNodeRestartBackoff is the delay before a data node is restarted
+	// again to apply a config version it failed to reach on its previous
+	// restart. The delay is doubled for every further attempt.
+	dataNodeRestartBackoff = time.Minute
+	// dataNodeStartTimeout is how long a restarted data node is given to
+	// connect to the MySQL Cluster with the desired config version before
+	// its restart is considered failed.
+	dataNodeStartTimeout = 15 * time.Minute
+
+	// dataNodeRestartsAnnotation is the annotation of the data node
+	// StatefulSet that persists the restarts of the data nodes done by
+	// the operator, so that they survive the operator restarts.
+	dataNodeRestartsAnnotation = ndbcontroller.GroupName + "/data-node-restarts"
+)
+
+// dataNodeRestart records the restarts of a data node done to
+// apply a particular MySQL Cluster config version and pod revision
+type dataNodeRestart struct {
+	// ConfigVersion is the MySQL Cluster config version applied by the restarts
+	ConfigVersion int32 `json:"configVersion"`
+	// PodRevision is the pod revision applied by the restarts
+	PodRevision string `json:"podRevision"`
+	// Attempts is the number of restarts done
+	Attempts int `json:"attempts"`
+	// Failures is the number of restarts after which the data node
+	// did not connect to the MySQL Cluster with the ConfigVersion
+	Failures int `json:"failures"`
+	// Pending is true when the outcome of the last restart is not known yet
+	Pending bool `json:"pending,omitempty"`
+	// LastRestartTime is the time of the last restart
+	LastRestartTime metav1.Time `json:"lastRestartTime"`
+}
+
+// failed returns true if the last restart of the data node failed
+func (r *dataNodeRestart) failed() bool {
+	return r != nil && !r.Pending && r.Failures > 0
+}
+
+// dataNodeRestarts holds the restarts of the data nodes keyed by their nodeId
+type dataNodeRestarts map[int]*dataNodeRestart
+
+// getDataNodeRestarts returns the data node restarts persisted in the
+// given data node StatefulSet. Only the restarts done to apply the given
+// config version and pod revision are returned, and the restarts done to
+// apply any older config are discarded.
+func getDataNodeRestarts(
+	ndbmtdSfset *appsv1.StatefulSet, configVersion int32, podRevision string) dataNodeRestarts {
+	restarts := make(dataNodeRestarts)
+	value, exists := ndbmtdSfset.GetAnnotations()[dataNodeRestartsAnnotation]
+	if !exists {
+		return restarts
+	}
+
+	var persistedRestarts dataNodeRestarts
+	if err := json.Unmarshal([]byte(value), &persistedRestarts); err != nil {
+		// Ignore the corrupted annotation. It will be overwritten.
+		return restarts
+	}
+
+	for nodeId, restart := range persistedRestarts {
+		if restart != nil && restart.ConfigVersion == configVersion && restart.PodRevision == podRevision {
+			restarts[nodeId] = restart
+		}
+	}
+	return restarts
+}
+
+// saveDataNodeRestarts persists the given data node restarts in the data
+// node StatefulSet. The annotation is removed if there are no restarts.
+func (sc *SyncContext) saveDataNodeRestarts(ctx context.Context, restarts dataNodeRestarts) error {
+	ndbmtdSfset := sc.dataNodeSfSet
+	var value interface{}
+	if len(restarts) != 0 {
+		restartsJson, err := json.Marshal(restarts)
+		if err != nil {
+			return err
+		}
+		value = string(restartsJson)
+	} else if _, exists := ndbmtdSfset.GetAnnotations()[dataNodeRestartsAnnotation]; !exists {
+		// Nothing to remove
+		return nil
+	}
+
+	// Patch the annotation rather than applying it, so that the applies
+	// of the StatefulSet, which do not have it, do not remove it.
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				dataNodeRestartsAnnotation: value,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	updatedSfset, err := sc.kubeClientset().AppsV1().StatefulSets(ndbmtdSfset.Namespace).Patch(
+		ctx, ndbmtdSfset.Name, types.MergePatchType, patch, metav1.PatchOptions{FieldManager: fieldManager})
+	if err != nil {
+		sc.logger.Error(err, "Failed to persist the data node restarts", "statefulset", getNamespacedName(ndbmtdSfset))
+		return err
+	}
+	sc.dataNodeSfSet = updatedSfset
+	return nil
+}
+
+// recordDataNodeRestarts records and persists the restarts of the data
+// nodes with the given nodeIds, done to apply the desired config version
+// and pod revision. The restarts are recorded before the pods are deleted,
+// so that a restart is never left uncounted.
+func (sc *SyncContext) recordDataNodeRestarts(
+	ctx context.Context, restarts dataNodeRestarts, desiredPodRevision string, nodeIds ...int) error {
+	for _, nodeId := range nodeIds {
+		restart, exists := restarts[nodeId]
+		if !exists {
+			restart = &dataNodeRestart{
+				ConfigVersion: sc.configSummary.MySQLClusterConfigVersion,
+				PodRevision:   desiredPodRevision,
+			}
+			restarts[nodeId] = restart
+		}
+		restart.Attempts++
+		restart.Pending = true
+		restart.LastRestartTime = metav1.Now()
+	}
+	return sc.saveDataNodeRestarts(ctx, restarts)
+}
+
+// verifyDataNodeRestarts verifies the outcome of the pending data node
+// restarts. A restart succeeds when the data node connects to the MySQL
+// Cluster with the desired config version, and it fails when the data node
+// connects with any other config version or does not connect at all within
+// the dataNodeStartTimeout. The successful restarts are forgotten and the
+// failed ones are counted. The sync is stopped while the outcome of any
+// restart is still unknown.
+func (sc *SyncContext) verifyDataNodeRestarts(ctx context.Context, mgmClient mgmapi.MgmClient,
+	clusterStatus mgmapi.ClusterStatus, restarts dataNodeRestarts, desiredPodRevision string) syncResult {
+	ndbmtdSfset := sc.dataNodeSfSet
+	desiredConfigVersion := sc.configSummary.MySQLClusterConfigVersion
+
+	restartsChanged := false
+	var waitingNodeIds []int
+	for nodeId, restart := range restarts {
+		if !restart.Pending {
+			continue
+		}
+
+		podName := sc.configSummary.GetNodePodName(nodeId)
+		pod, err := sc.podLister.Pods(ndbmtdSfset.Namespace).Get(podName)
+		podUpdated := err == nil && pod.DeletionTimestamp == nil &&
+			pod.GetLabels()["controller-revision-hash"] == desiredPodRevision
+
+		if nodeStatus, exists := clusterStatus[nodeId]; podUpdated && exists && nodeStatus.IsConnected {
+			configVersion, err := mgmClient.GetConfigVersion(nodeId)
+			if err != nil {
+				sc.logger.Error(err, "Failed to retrieve the config version of the data node", "nodeId", nodeId)
+				return errorWhileProcessing(err)
+			}
+
+			if configVersion == uint32(desiredConfigVersion) {
+				// The restart applied the desired config
+				delete(restarts, nodeId)
+				restartsChanged = true
+				continue
+			}
+
+			// The data node came back with a different config version
+			sc.logger.Info("Data node did not start with the desired config version",
+				"nodeId", nodeId, "configVersion", configVersion, "desiredConfigVersion", desiredConfigVersion)
+		} else if time.Since(restart.LastRestartTime.Time) < dataNodeStartTimeout {
+			// The data node is still starting
+			waitingNodeIds = append(waitingNodeIds, nodeId)
+			continue
+		} else {
+			sc.logger.Info("Data node did not connect to the MySQL Cluster after its restart",
+				"nodeId", nodeId, "timeout", dataNodeStartTimeout)
+		}
+
+		// The restart failed
+		restart.Failures++
+		restart.Pending = false
+		restartsChanged = true
+	}
+
+	if restartsChanged {
+		if err := sc.saveDataNodeRestarts(ctx, restarts); err != nil {
+			return errorWhileProcessing(err)
+		}
+	}
+
+	if len(waitingNodeIds) != 0 {
+		sort.Ints(waitingNodeIds)
+		sc.logger.Info("Waiting for the restarted data nodes to connect with the desired config version",
+			"nodeIds", waitingNodeIds, "configVersion", desiredConfigVersion)
+		// Check again after a while, in case the data nodes
+		// never come up and no other event triggers a sync.
+		if sc.requeueAfter == 0 || dataNodeRestartBackoff < sc.requeueAfter {
+			sc.requeueAfter = dataNodeRestartBackoff
+		}
+		return finishProcessing()
+	}
+
+	return continueProcessing()
+}
+
+// getDataNodeRestartDelay returns how long a data node whose restarts
+// have already failed the given number of times has to wait before it is
+// restarted again. The delay is doubled after every failed attempt,
+// starting from the dataNodeRestartBackoff.
+func getDataNodeRestartDelay(failures int, lastRestartTime time.Time) time.Duration {
+	if failures == 0 {
+		return 0
+	}
+
+	backoff := dataNodeRestartBackoff << (failures - 1)
+	if delay := backoff - time.Since(lastRestartTime); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// checkDataNodeRestartLoop verifies that the data node with the given nodeId
+// can be restarted to apply the desired config version and pod revision.
+// If the previous restarts of the data node failed to apply them, the
+// restart is delayed by an exponential backoff, and if the restarts have
+// failed maxDataNodeRestartAttempts times, the restarts are stopped, the
+// NdbClusterDataNodeRestartLoop condition is set with the diagnostics and
+// a Warning event is recorded. The restarts resume once the NdbCluster spec
+// is updated, leading to a new config version or pod revision.
+func (sc *SyncContext) checkDataNodeRestartLoop(nodeId int, restart *dataNodeRestart) syncResult {
+	if restart == nil {
+		// First restart of the data node
+		return continueProcessing()
+	}
+
+	nc := sc.ndb
+	if restart.Failures >= maxDataNodeRestartAttempts {
+		restartLoopCondition := &v1.NdbClusterCondition{
+			Type:   v1.NdbClusterDataNodeRestartLoop,
+			Status: corev1.ConditionTrue,
+			Reason: v1.NdbClusterDataNodeRestartLoopReasonNotConverging,
+			Message: fmt.Sprintf("Data node (nodeId=%d) did not connect to the MySQL Cluster with the "+
+				"config version %d after being restarted %d times to apply it. Stopped restarting the "+
+				"data nodes, check the logs of the pod %q or update the NdbCluster spec to retry.",
+				nodeId, restart.ConfigVersion, restart.Attempts, sc.configSummary.GetNodePodName(nodeId)),
+		}
+
+		previousCondition := nc.GetDataNodeRestartLoopCondition()
+		restartLoopCondition.LastTransitionTime = metav1.Now()
+		if previousCondition != nil && previousCondition.Status == corev1.ConditionTrue {
+			restartLoopCondition.LastTransitionTime = previousCondition.LastTransitionTime
+		}
+
+		// Record an event only when the loop is detected
+		if previousCondition == nil || previousCondition.Message != restartLoopCondition.Message {
+			sc.logger.Info("Data node restarts are not converging", "nodeId", nodeId, "attempts", restart.Attempts)
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeWarning, ReasonDataNodeRestartLoop, ActionNone,
+				"%s", restartLoopCondition.Message)
+		}
+
+		sc.dataNodeRestartLoopCondition = restartLoopCondition
+		// Stop processing. The spec changes pending the data
+		// node restarts cannot be applied to the MySQL Cluster.
+		return finishProcessing()
+	}
+
+	if delay := getDataNodeRestartDelay(restart.Failures, restart.LastRestartTime.Time); delay > 0 {
+		// Back off before restarting the data node again
+		sc.logger.Info("Delaying the restart of the data node as its previous restart did not apply the desired config",
+			"nodeId", nodeId, "failures", restart.Failures, "delay", delay)
+		if sc.requeueAfter == 0 || delay < sc.requeueAfter {
+			sc.requeueAfter = delay
+		}
+		return finishProcessing()
+	}
+
+	return continueProcessing()
+}
+
+// resetDataNodeRestartLoop removes the persisted restarts of the data nodes
+// once they all run the desired config, and clears the
+// NdbClusterDataNodeRestartLoop condition if it was set.
+func (sc *SyncContext) resetDataNodeRestartLoop(ctx context.Context) error {
+	if err := sc.saveDataNodeRestarts(ctx, nil); err != nil {
+		return err
+	}
+
+	nc := sc.ndb
+	if previousCondition := nc.GetDataNodeRestartLoopCondition(); previousCondition != nil &&
+		previousCondition.Status == corev1.ConditionTrue {
+		sc.dataNodeRestartLoopCondition = &v1.NdbClusterCondition{
+			Type:               v1.NdbClusterDataNodeRestartLoop,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.Now(),
+			Reason:             v1.NdbClusterDataNodeRestartLoopReasonConverged,
+			Message:            "All the data nodes are running the desired config",
+		}
+	}
+	return nil
+}
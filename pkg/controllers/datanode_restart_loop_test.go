@@ -0,0 +1,230 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	listerscorev1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	klog "k8s.io/klog/v2"
+)
+
+// configVersionMgmClient is a MgmClient that
+// only reports the config versions of the nodes
+type configVersionMgmClient struct {
+	mgmapi.MgmClient
+	configVersions map[int]uint32
+}
+
+func (c *configVersionMgmClient) GetConfigVersion(nodeId ...int) (uint32, error) {
+	return c.configVersions[nodeId[0]], nil
+}
+
+func Test_getDataNodeRestartDelay(t *testing.T) {
+	for _, tc := range []struct {
+		failures      int
+		sinceLast     time.Duration
+		expectedDelay time.Duration
+	}{
+		{0, 0, 0},
+		{1, 30 * time.Second, 30 * time.Second},
+		{1, 2 * time.Minute, 0},
+		{2, 30 * time.Second, 90 * time.Second},
+	} {
+		delay := getDataNodeRestartDelay(tc.failures, time.Now().Add(-tc.sinceLast))
+		// Allow for the time elapsed during the test
+		if delay > tc.expectedDelay || delay < tc.expectedDelay-time.Second {
+			t.Errorf("%d failures, %s since the last restart : expected delay %s but got %s",
+				tc.failures, tc.sinceLast, tc.expectedDelay, delay)
+		}
+	}
+}
+
+func Test_getDataNodeRestarts(t *testing.T) {
+	sfset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				dataNodeRestartsAnnotation: `{"2":{"configVersion":2,"podRevision":"rev-2","attempts":1,"failures":1,"lastRestartTime":null},` +
+					`"3":{"configVersion":1,"podRevision":"rev-2","attempts":1,"failures":0,"lastRestartTime":null},` +
+					`"4":{"configVersion":2,"podRevision":"rev-1","attempts":1,"failures":0,"lastRestartTime":null}}`,
+			},
+		},
+	}
+
+	restarts := getDataNodeRestarts(sfset, 2, "rev-2")
+	if len(restarts) != 1 || restarts[2] == nil || !restarts[2].failed() {
+		t.Errorf("Expected only the failed restart of the data node 2 but got %#v", restarts)
+	}
+
+	sfset.Annotations[dataNodeRestartsAnnotation] = "corrupted"
+	if restarts = getDataNodeRestarts(sfset, 2, "rev-2"); len(restarts) != 0 {
+		t.Errorf("Expected no restarts from a corrupted annotation but got %#v", restarts)
+	}
+}
+
+// newRestartLoopTestSyncContext returns a SyncContext with two data
+// nodes, with nodeIds 2 and 3, running in the given pods
+func newRestartLoopTestSyncContext(t *testing.T, pods ...*corev1.Pod) (*SyncContext, *events.FakeRecorder) {
+	nc := testutils.NewTestNdb("default", "test", 2)
+	sfset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ndbmtd", Namespace: "default"},
+	}
+
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, pod := range pods {
+		if err := podIndexer.Add(pod); err != nil {
+			t.Fatalf("Failed to add the pod to the indexer : %s", err)
+		}
+	}
+
+	recorder := events.NewFakeRecorder(10)
+	return &SyncContext{
+		ndb: nc,
+		configSummary: &ndbconfig.ConfigSummary{
+			MySQLClusterConfigVersion: 2,
+			Nodes: []ndbconfig.NodeIdentity{
+				{NodeId: 2, NodeType: constants.NdbNodeTypeNdbmtd, PodName: "test-ndbmtd-0"},
+				{NodeId: 3, NodeType: constants.NdbNodeTypeNdbmtd, PodName: "test-ndbmtd-1"},
+			},
+		},
+		dataNodeSfSet:    sfset,
+		podLister:        listerscorev1.NewPodLister(podIndexer),
+		kubernetesClient: fake.NewSimpleClientset(sfset),
+		recorder:         recorder,
+		logger:           klog.Background(),
+	}, recorder
+}
+
+func newDataNodePod(name, revision string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"controller-revision-hash": revision},
+		},
+	}
+}
+
+func Test_verifyDataNodeRestarts(t *testing.T) {
+	ctx := context.Background()
+	sc, _ := newRestartLoopTestSyncContext(t,
+		newDataNodePod("test-ndbmtd-0", "rev-2"), newDataNodePod("test-ndbmtd-1", "rev-2"))
+
+	restarts := make(dataNodeRestarts)
+	if err := sc.recordDataNodeRestarts(ctx, restarts, "rev-2", 2, 3); err != nil {
+		t.Fatalf("Failed to record the restarts : %s", err)
+	}
+
+	// The restarts should have been persisted
+	if persisted := getDataNodeRestarts(sc.dataNodeSfSet, 2, "rev-2"); len(persisted) != 2 ||
+		persisted[2].Attempts != 1 || !persisted[2].Pending {
+		t.Fatalf("Unexpected persisted restarts : %#v", persisted)
+	}
+
+	// Data node 2 comes back with the desired config version,
+	// data node 3 with an older one, and both are connected
+	mgmClient := &configVersionMgmClient{configVersions: map[int]uint32{2: 2, 3: 1}}
+	clusterStatus := mgmapi.ClusterStatus{
+		2: &mgmapi.NodeStatus{NodeId: 2, NodeType: mgmapi.NodeTypeNDB, IsConnected: true},
+		3: &mgmapi.NodeStatus{NodeId: 3, NodeType: mgmapi.NodeTypeNDB, IsConnected: true},
+	}
+	if sr := sc.verifyDataNodeRestarts(ctx, mgmClient, clusterStatus, restarts, "rev-2"); sr.stopSync() {
+		t.Fatal("Verified restarts should not stop the sync")
+	}
+	if _, exists := restarts[2]; exists {
+		t.Error("The successful restart of the data node 2 should have been forgotten")
+	}
+	if !restarts[3].failed() || restarts[3].Failures != 1 {
+		t.Errorf("The restart of the data node 3 should have failed : %#v", restarts[3])
+	}
+	if persisted := getDataNodeRestarts(sc.dataNodeSfSet, 2, "rev-2"); len(persisted) != 1 || !persisted[3].failed() {
+		t.Errorf("Unexpected persisted restarts : %#v", persisted)
+	}
+
+	// A restarted data node that has not connected yet should be waited for
+	if err := sc.recordDataNodeRestarts(ctx, restarts, "rev-2", 3); err != nil {
+		t.Fatalf("Failed to record the restarts : %s", err)
+	}
+	clusterStatus[3].IsConnected = false
+	if sr := sc.verifyDataNodeRestarts(ctx, mgmClient, clusterStatus, restarts, "rev-2"); !sr.stopSync() {
+		t.Error("The sync should wait for the data node 3 to connect")
+	}
+
+	// ...until the start timeout expires
+	restarts[3].LastRestartTime = metav1.NewTime(time.Now().Add(-dataNodeStartTimeout))
+	if sr := sc.verifyDataNodeRestarts(ctx, mgmClient, clusterStatus, restarts, "rev-2"); sr.stopSync() {
+		t.Fatal("Timed out restarts should not stop the sync")
+	}
+	if restarts[3].Failures != 2 || restarts[3].Attempts != 2 {
+		t.Errorf("The restart of the data node 3 should have failed again : %#v", restarts[3])
+	}
+
+	// Once all the data nodes have the desired config, the annotation is removed
+	if err := sc.resetDataNodeRestartLoop(ctx); err != nil {
+		t.Fatalf("Failed to reset the restarts : %s", err)
+	}
+	if _, exists := sc.dataNodeSfSet.Annotations[dataNodeRestartsAnnotation]; exists {
+		t.Error("The data node restarts annotation should have been removed")
+	}
+}
+
+func Test_checkDataNodeRestartLoop(t *testing.T) {
+	sc, recorder := newRestartLoopTestSyncContext(t)
+	nc := sc.ndb
+
+	// The first restart is allowed
+	if sr := sc.checkDataNodeRestartLoop(2, nil); sr.stopSync() {
+		t.Fatal("First restart of the data node should be allowed")
+	}
+
+	// A restart after a failed restart should be delayed
+	restart := &dataNodeRestart{
+		ConfigVersion:   2,
+		PodRevision:     "rev-2",
+		Attempts:        1,
+		Failures:        1,
+		LastRestartTime: metav1.Now(),
+	}
+	if sr := sc.checkDataNodeRestartLoop(2, restart); !sr.stopSync() || sc.requeueAfter == 0 {
+		t.Fatal("Restart of the data node should be delayed")
+	}
+
+	// Restarts should stop once the attempts are exhausted
+	restart.Attempts, restart.Failures = maxDataNodeRestartAttempts, maxDataNodeRestartAttempts
+	if sr := sc.checkDataNodeRestartLoop(2, restart); !sr.stopSync() {
+		t.Fatal("Restarts of the data node should have been stopped")
+	}
+	condition := sc.dataNodeRestartLoopCondition
+	if condition == nil || condition.Status != corev1.ConditionTrue ||
+		condition.Reason != v1.NdbClusterDataNodeRestartLoopReasonNotConverging {
+		t.Fatalf("Unexpected restart loop condition : %#v", condition)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("Expected a warning event but got %d events", len(recorder.Events))
+	}
+
+	// Reset should clear the condition
+	nc.Status.Conditions = []v1.NdbClusterCondition{*condition}
+	if err := sc.resetDataNodeRestartLoop(context.Background()); err != nil {
+		t.Fatalf("Failed to reset the restarts : %s", err)
+	}
+	if condition = sc.dataNodeRestartLoopCondition; condition.Status != corev1.ConditionFalse {
+		t.Errorf("Restart loop condition was not cleared : %#v", condition)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// getBackupEncryptionPassword returns the password with which the
+// backups taken by the operator are encrypted, or an empty string
+// if they are not to be encrypted.
+func (sc *SyncContext) getBackupEncryptionPassword(ctx context.Context) (string, error) {
+	nc := sc.ndb
+	secretName := nc.GetBackupEncryptionPasswordSecretName()
+	if secretName == "" {
+		return "", nil
+	}
+
+	return NewMySQLUserPasswordSecretInterface(sc.kubeClientset()).ExtractPassword(ctx, nc.Namespace, secretName)
+}
+
+// systemRestartDataNodes applies a config that can only be applied by a
+// system restart, as allowed by the spec. All the data nodes are stopped
+// together, after taking a backup if it is requested in the spec, and their
+// pods are deleted so that the StatefulSet controller starts them again with
+// the latest pod definition and config. For an initial system restart, the
+// PVCs of the data nodes are deleted as well, and the data nodes start with
+// empty file systems, and all the MySQL Servers are restarted afterwards to
+// resync with the recreated MySQL Cluster. The MySQL Cluster is unavailable
+// until all the data nodes have started again. The data nodes whose
+// restart failed to apply the latest config are restarted again, subject to
+// the same restart loop detection as the data nodes restarted one by one.
+func (sc *SyncContext) systemRestartDataNodes(ctx context.Context, mgmClient mgmapi.MgmClient,
+	clusterStatus mgmapi.ClusterStatus, restarts dataNodeRestarts, desiredPodRevisionHash string, initial bool) syncResult {
+	nc := sc.ndb
+	ndbmtdSfset := sc.dataNodeSfSet
+
+	// Find the data node pods running with an outdated pod version
+	// or whose previous restart did not apply the latest config
+	var outdatedPods []*corev1.Pod
+	var outdatedNodeIds []int
+	for _, nodeId := range sc.configSummary.GetNodeIds(constants.NdbNodeTypeNdbmtd) {
+		podName := sc.configSummary.GetNodePodName(nodeId)
+		pod, err := sc.podLister.Pods(ndbmtdSfset.Namespace).Get(podName)
+		if err != nil {
+			sc.logger.Error(err, "Failed to find pod", "pod", getNamespacedName2(ndbmtdSfset.Namespace, podName))
+			return errorWhileProcessing(err)
+		}
+
+		restart := restarts[nodeId]
+		if pod.GetLabels()["controller-revision-hash"] != desiredPodRevisionHash || restart.failed() {
+			// Verify that the previous restarts of the data
+			// node, if any, are not looping
+			if sr := sc.checkDataNodeRestartLoop(nodeId, restart); sr.stopSync() {
+				return sr
+			}
+			outdatedPods = append(outdatedPods, pod)
+			outdatedNodeIds = append(outdatedNodeIds, nodeId)
+		}
+	}
+
+	if len(outdatedPods) == 0 {
+		// All the data nodes have been restarted with the latest config
+		return continueProcessing()
+	}
+
+	if len(outdatedPods) == int(*ndbmtdSfset.Spec.Replicas) {
+		// The system restart has not started yet
+		if !clusterStatus.IsHealthy() {
+			// Do not stop a MySQL Cluster that is still recovering
+			sc.logger.Info("Waiting for all the data nodes to connect before the system restart")
+			return finishProcessing()
+		}
+
+		if nc.Spec.DataNode.SystemRestart != nil && nc.Spec.DataNode.SystemRestart.BackupBeforeRestart {
+			// Take a backup of the MySQL Cluster and wait for it to complete
+			sc.logger.Info("Taking a backup of the MySQL Cluster before the system restart")
+			encryptionPassword, err := sc.getBackupEncryptionPassword(ctx)
+			if err != nil {
+				return errorWhileProcessing(err)
+			}
+			backupId, err := mgmClient.StartBackup(encryptionPassword)
+			if err != nil {
+				sc.logger.Error(err, "Failed to take a backup of the MySQL Cluster")
+				return errorWhileProcessing(err)
+			}
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonBackupCompleted, ActionNone,
+				"Backup (id=%d) of the MySQL Cluster was taken before the system restart", backupId)
+		}
+
+		if initial {
+			// The initial system restart wipes the schemas and the users stored in
+			// the MySQL Cluster. Request the MySQL Servers to be resynced with the
+			// recreated MySQL Cluster once the data nodes have restarted.
+			if err := sc.requestMySQLServersResync(ctx); err != nil {
+				return errorWhileProcessing(err)
+			}
+		}
+
+		// Stop all the data nodes together, to shut down the MySQL Cluster cleanly
+		var dataNodeIds []int
+		for nodeId, nodeStatus := range clusterStatus {
+			if nodeStatus.IsDataNode() && nodeStatus.IsConnected {
+				dataNodeIds = append(dataNodeIds, nodeId)
+			}
+		}
+		sort.Ints(dataNodeIds)
+		sc.logger.Info("Stopping all the data nodes for a system restart", "nodeIds", dataNodeIds)
+		if err := mgmClient.StopNodes(dataNodeIds); err != nil {
+			sc.logger.Error(err, "Failed to stop the data nodes")
+			return errorWhileProcessing(err)
+		}
+	}
+
+	// Record the restarts, and delete all the outdated pods and let
+	// the StatefulSet controller start them with the latest pod definition.
+	if err := sc.recordDataNodeRestarts(ctx, restarts, desiredPodRevisionHash, outdatedNodeIds...); err != nil {
+		return errorWhileProcessing(err)
+	}
+	for _, pod := range outdatedPods {
+		err := sc.kubeClientset().CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+		if err != nil {
+			sc.logger.Error(err, "Failed to delete pod", "pod", getNamespacedName(pod))
+			return errorWhileProcessing(err)
+		}
+
+		if initial {
+			// Delete the PVCs, after the pod, so that the
+			// data node starts with an empty file system
+			if err = sc.deleteDataNodeFileSystemPVCs(ctx, pod.Namespace, pod.Name); err != nil {
+				return errorWhileProcessing(err)
+			}
+		}
+	}
+
+	restartType := "a system restart"
+	if initial {
+		restartType = "an initial system restart"
+	}
+	sc.logger.Info("Data nodes are being restarted together with the latest config", "initial", initial)
+	sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonDataNodeRestarting, ActionRestart,
+		"All the data nodes are being restarted together by %s to apply the latest config", restartType)
+	// Stop processing. Reconciliation will continue
+	// once the StatefulSet is fully ready again.
+	return finishProcessing()
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// reconcileDiskDataObjects creates the logfile group and the tablespaces
+// specified in the NdbCluster spec, and adds any new files to them. The
+// objects are created via the MySQL Servers, and so this step is skipped
+// if the MySQL Cluster has no MySQL Servers.
+func (sc *SyncContext) reconcileDiskDataObjects(ctx context.Context) syncResult {
+	nc := sc.ndb
+	if nc.Spec.DataNode.DiskData == nil {
+		// No Disk Data objects to create
+		return continueProcessing()
+	}
+
+	if sc.mysqldSfset == nil || *sc.mysqldSfset.Spec.Replicas == 0 {
+		sc.logger.Info("Skipping the creation of the Disk Data objects as the NdbCluster has no MySQL Servers")
+		return continueProcessing()
+	}
+
+	// Extract the ndb operator mysql user password
+	operatorSecretName := resources.GetMySQLNDBOperatorPasswordSecretName(nc)
+	operatorPassword, err := NewMySQLUserPasswordSecretInterface(sc.kubeClientset()).ExtractPassword(ctx, nc.Namespace, operatorSecretName)
+	if err != nil {
+		sc.logger.Error(err, "Failed to extract ndb operator password from the secret")
+		return errorWhileProcessing(err)
+	}
+
+	updated, err := mysqlclient.EnsureDiskDataObjects(ctx, sc.mysqldSfset, nc, operatorPassword)
+	if err != nil {
+		sc.logger.Error(err, "Failed to create the Disk Data objects")
+		return errorWhileProcessing(err)
+	}
+
+	if updated {
+		sc.logger.Info("Disk Data objects have been created")
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonDiskDataObjectsCreated, ActionSynced,
+			"Logfile group and tablespaces have been created as per the spec")
+	}
+
+	return continueProcessing()
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	ndbclientset "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/util/retry"
+	klog "k8s.io/klog/v2"
+)
+
+// epochSampleInterval is the time between two
+// consecutive samples of the latest committed epoch.
+const epochSampleInterval = 10 * time.Second
+
+// newEpochStatus returns the NdbClusterEpochStatus of the given epoch
+func newEpochStatus(epoch uint64, observedAt metav1.Time) *v1.NdbClusterEpochStatus {
+	return &v1.NdbClusterEpochStatus{
+		Epoch:          int64(epoch),
+		GCI:            int64(epoch >> 32),
+		LastUpdateTime: observedAt,
+	}
+}
+
+// epochSampler samples the latest epoch committed in the MySQL Clusters
+// once every epochSampleInterval, and publishes it in the status of their
+// NdbClusters. The epochs are sampled on a timer, independent of the syncs,
+// as the syncs of an unchanged NdbCluster are skipped for long periods.
+type epochSampler struct {
+	kubernetesClient  kubernetes.Interface
+	ndbClient         ndbclientset.Interface
+	statefulSetLister appslisters.StatefulSetLister
+
+	// activeSamplers holds the cancel functions of the
+	// active samplers keyed by the NdbCluster key
+	activeSamplers map[string]context.CancelFunc
+	// mutex protects the activeSamplers map
+	mutex sync.Mutex
+}
+
+// newEpochSampler creates a new epochSampler
+func newEpochSampler(kubernetesClient kubernetes.Interface,
+	ndbClient ndbclientset.Interface, statefulSetLister appslisters.StatefulSetLister) *epochSampler {
+	return &epochSampler{
+		kubernetesClient:  kubernetesClient,
+		ndbClient:         ndbClient,
+		statefulSetLister: statefulSetLister,
+		activeSamplers:    make(map[string]context.CancelFunc),
+	}
+}
+
+// ensureSampling starts sampling the latest epoch of the
+// given NdbCluster, if it is not being sampled already.
+func (es *epochSampler) ensureSampling(nc *v1.NdbCluster) {
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+
+	key := getNdbClusterKey(nc)
+	if _, exists := es.activeSamplers[key]; exists {
+		// Epoch is being sampled already
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	es.activeSamplers[key] = cancel
+	go es.run(ctx, nc.DeepCopy())
+}
+
+// stopSampling stops sampling the latest epoch of the NdbCluster with the given key
+func (es *epochSampler) stopSampling(key string) {
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+
+	if cancel, exists := es.activeSamplers[key]; exists {
+		cancel()
+		delete(es.activeSamplers, key)
+	}
+}
+
+// stopAll stops sampling the latest epoch of all the NdbClusters
+func (es *epochSampler) stopAll() {
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+
+	for key, cancel := range es.activeSamplers {
+		cancel()
+		delete(es.activeSamplers, key)
+	}
+}
+
+// run samples the latest epoch of the given NdbCluster once
+// every epochSampleInterval, until the ctx is cancelled.
+func (es *epochSampler) run(ctx context.Context, nc *v1.NdbCluster) {
+	key := getNdbClusterKey(nc)
+	klog.Infof("Sampling the latest epoch of NdbCluster %q", key)
+	ticker := time.NewTicker(epochSampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		epoch, err := es.sampleLatestEpoch(ctx, nc)
+		if err != nil {
+			klog.Warningf("Failed to sample the latest epoch of NdbCluster %q : %s", key, err)
+			continue
+		}
+		if epoch == 0 {
+			// There are no MySQL Servers to sample the epoch through,
+			// or nothing has been committed since they connected
+			continue
+		}
+
+		if err = es.publishLatestEpoch(ctx, nc, newEpochStatus(epoch, metav1.Now())); err != nil {
+			klog.Warningf("Failed to publish the latest epoch of NdbCluster %q : %s", key, err)
+		}
+	}
+}
+
+// sampleLatestEpoch returns the latest epoch committed in the MySQL Cluster
+// of the given NdbCluster, sampled through its first MySQL Server. It
+// returns 0 if none of the MySQL Servers are ready.
+func (es *epochSampler) sampleLatestEpoch(ctx context.Context, nc *v1.NdbCluster) (uint64, error) {
+	mysqldSfset, err := es.statefulSetLister.StatefulSets(nc.Namespace).Get(
+		nc.GetWorkloadName(constants.NdbNodeTypeMySQLD))
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// There are no MySQL Servers
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	if mysqldSfset.Status.ReadyReplicas == 0 {
+		// There are no MySQL Servers to sample the epoch through
+		return 0, nil
+	}
+
+	// Extract the ndb operator mysql user password
+	secretClient := NewMySQLUserPasswordSecretInterface(es.kubernetesClient)
+	operatorSecretName := resources.GetMySQLNDBOperatorPasswordSecretName(nc)
+	operatorPassword, err := secretClient.ExtractPassword(ctx, mysqldSfset.Namespace, operatorSecretName)
+	if err != nil {
+		return 0, err
+	}
+
+	return mysqlclient.GetLastCommitEpoch(ctx, mysqldSfset, operatorPassword)
+}
+
+// publishLatestEpoch sets the given epoch as the latest epoch in the status
+// of the given NdbCluster. The NdbCluster is read from the K8s Server and
+// the update is retried with a fresh copy if the status changes meanwhile.
+func (es *epochSampler) publishLatestEpoch(
+	ctx context.Context, nc *v1.NdbCluster, latestEpoch *v1.NdbClusterEpochStatus) error {
+	ndbInterface := es.ndbClient.MysqlV1().NdbClusters(nc.Namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latestNc, err := ndbInterface.Get(ctx, nc.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		latestNc.Status.LatestEpoch = latestEpoch
+		_, err = ndbInterface.UpdateStatus(ctx, latestNc, metav1.UpdateOptions{})
+		return err
+	})
+
+	if apierrors.IsNotFound(err) {
+		// NdbCluster has been deleted
+		return nil
+	}
+	return err
+}
+
+// ensureEpochSampling ensures that the latest epoch of the MySQL
+// Cluster is being sampled, once its MySQL Servers have been created.
+func (sc *SyncContext) ensureEpochSampling() {
+	if sc.mysqldSfset == nil {
+		// There are no MySQL Servers to sample the epoch through
+		return
+	}
+
+	sc.epochSampler.ensureSampling(sc.ndb)
+}
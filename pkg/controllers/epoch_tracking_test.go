@@ -0,0 +1,79 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_newEpochStatus(t *testing.T) {
+	// Epoch 1234/5 => GCI 1234 and micro GCI 5
+	epoch := uint64(1234)<<32 | 5
+	epochStatus := newEpochStatus(epoch, metav1.Now())
+	if epochStatus.Epoch != 5299989643269 {
+		t.Errorf("Expected epoch 5299989643269 but got %d", epochStatus.Epoch)
+	}
+	if epochStatus.GCI != 1234 {
+		t.Errorf("Expected GCI 1234 but got %d", epochStatus.GCI)
+	}
+}
+
+func Test_epochSampler(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+	es := f.c.epochSampler
+
+	// No epoch is sampled when there are no MySQL Servers
+	if epoch, err := es.sampleLatestEpoch(ctx, ndb); err != nil || epoch != 0 {
+		t.Errorf("Unexpected sample without MySQL Servers : %d, %v", epoch, err)
+	}
+
+	// The epoch should be published in the status of the NdbCluster
+	if err := es.publishLatestEpoch(ctx, ndb, newEpochStatus(uint64(1234)<<32, metav1.Now())); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	nc, err := f.ndbclient.MysqlV1().NdbClusters(ns).Get(ctx, ndb.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if nc.Status.LatestEpoch == nil || nc.Status.LatestEpoch.GCI != 1234 {
+		t.Errorf("Latest epoch not published : %#v", nc.Status.LatestEpoch)
+	}
+
+	// A deleted NdbCluster should be ignored
+	deleted := testutils.NewTestNdb(ns, "deleted", 2)
+	if err = es.publishLatestEpoch(ctx, deleted, newEpochStatus(1, metav1.Now())); err != nil {
+		t.Error("Unexpected error for a deleted NdbCluster :", err)
+	}
+
+	// The sampling should be started only once per NdbCluster and stopped on request
+	key := getNdbClusterKey(ndb)
+	es.ensureSampling(ndb)
+	es.ensureSampling(ndb)
+	if _, exists := es.activeSamplers[key]; !exists || len(es.activeSamplers) != 1 {
+		t.Error("Epoch sampling started again for the same NdbCluster")
+	}
+	es.stopSampling(key)
+	if len(es.activeSamplers) != 0 {
+		t.Error("Epoch sampling not stopped")
+	}
+
+	// The published epoch should be retained by the sync
+	sc := f.c.newSyncContext(ctx, nc)
+	if status := sc.calculateNdbClusterStatus(); status.LatestEpoch == nil || status.LatestEpoch.GCI != 1234 {
+		t.Errorf("Latest epoch not retained : %#v", status.LatestEpoch)
+	}
+}
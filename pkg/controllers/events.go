@@ -22,12 +22,190 @@ const (
 	// some resource of the same name already existing but not owned by
 	// the Ndb object.
 	ReasonResourceExists = "ResourceExists"
+	// ReasonResourceAdopted is the reason used for an Event when the
+	// operator adopts an orphaned resource that was created for the
+	// Ndb object but is not owned by it.
+	ReasonResourceAdopted = "ResourceAdopted"
 	// ReasonSyncSuccess is the reason used for an Event when
 	// the MySQL Cluster is successfully synced with the Ndb object.
 	ReasonSyncSuccess = "SyncSuccess"
 	// ReasonInSync is the reason used for an Event when the MySQL Cluster
 	// is already in sync with the spec of the Ndb object.
 	ReasonInSync = "InSync"
+	// ReasonDataNodeFailing is the reason used for an Event when a
+	// data node repeatedly fails to start.
+	ReasonDataNodeFailing = "DataNodeFailing"
+	// ReasonInitialRestartBlocked is the reason used for an Event when
+	// the initial restart of a failing data node is not safe to perform.
+	ReasonInitialRestartBlocked = "InitialRestartBlocked"
+	// ReasonInitialRestart is the reason used for an Event when the
+	// operator performs an initial restart of a failing data node.
+	ReasonInitialRestart = "InitialRestart"
+	// ReasonClusterPartitioned is the reason used for an Event when some
+	// of the started data nodes lose their connection to the MySQL Cluster.
+	ReasonClusterPartitioned = "ClusterPartitioned"
+	// ReasonPartitionResolved is the reason used for an Event when all the
+	// disconnected data nodes have reconnected to the MySQL Cluster.
+	ReasonPartitionResolved = "PartitionResolved"
+	// ReasonNodesDisconnected is the reason used for an Event when some
+	// of the MySQL Cluster nodes are not started or not connected.
+	ReasonNodesDisconnected = "NodesDisconnected"
+	// ReasonNodesReconnected is the reason used for an Event when all
+	// the MySQL Cluster nodes are connected again.
+	ReasonNodesReconnected = "NodesReconnected"
+	// ReasonDataNodeRestartLoop is the reason used for an Event when the
+	// operator stops restarting a data node as its restarts repeatedly
+	// failed to apply the latest pod definition.
+	ReasonDataNodeRestartLoop = "DataNodeRestartLoop"
+	// ReasonHealthThresholdExceeded is the reason used for an Event when the
+	// health snapshot of the MySQL Cluster exceeds the thresholds in the spec.
+	ReasonHealthThresholdExceeded = "HealthThresholdExceeded"
+	// ReasonHealthRecovered is the reason used for an Event when the health
+	// snapshot of the MySQL Cluster is back within the thresholds in the spec.
+	ReasonHealthRecovered = "HealthRecovered"
+	// ReasonClusterLog is the reason used for an Event when
+	// an important entry is written to the MySQL Cluster log.
+	ReasonClusterLog = "ClusterLog"
+	// ReasonDiskDataObjectsCreated is the reason used for an Event when
+	// the operator creates or alters the Disk Data objects.
+	ReasonDiskDataObjectsCreated = "DiskDataObjectsCreated"
+	// ReasonConfigMapCreated is the reason used for an Event when the
+	// operator creates the ConfigMap holding the MySQL Cluster config.
+	ReasonConfigMapCreated = "ConfigMapCreated"
+	// ReasonConfigMapUpdated is the reason used for an Event when the
+	// operator updates the ConfigMap with a new generation of the spec.
+	ReasonConfigMapUpdated = "ConfigMapUpdated"
+	// ReasonStatefulSetCreated is the reason used for an Event when the
+	// operator creates a StatefulSet for the MySQL Cluster nodes.
+	ReasonStatefulSetCreated = "StatefulSetCreated"
+	// ReasonMgmdRestarting is the reason used for an Event when the
+	// Management nodes are restarted to apply a new config.
+	ReasonMgmdRestarting = "MgmdRestarting"
+	// ReasonMgmdConfigReloaded is the reason used for an Event when a new
+	// config is applied to the Management nodes without restarting them.
+	ReasonMgmdConfigReloaded = "MgmdConfigReloaded"
+	// ReasonDataNodeRestarting is the reason used for an Event when the operator
+	// restarts data nodes to apply a new pod definition or to recover them.
+	ReasonDataNodeRestarting = "DataNodeRestarting"
+	// ReasonDataNodeSystemRestartRequired is the reason used for an Event when
+	// the spec changes data node parameters that need a system restart.
+	ReasonDataNodeSystemRestartRequired = "DataNodeSystemRestartRequired"
+	// ReasonBackupCompleted is the reason used for an Event when the operator
+	// takes a backup of the MySQL Cluster before restarting all the data nodes.
+	ReasonBackupCompleted = "BackupCompleted"
+	// ReasonBackupVerified is the reason used for an Event when
+	// a completed backup has been verified by the operator.
+	ReasonBackupVerified = "BackupVerified"
+	// ReasonBackupVerificationFailed is the reason used for an
+	// Event when a completed backup fails the verification.
+	ReasonBackupVerificationFailed = "BackupVerificationFailed"
+	// ReasonDataNodesAdded is the reason used for an Event when new
+	// data nodes are added to the MySQL Cluster online.
+	ReasonDataNodesAdded = "DataNodesAdded"
+	// ReasonMySQLServersUpdating is the reason used for an Event when the
+	// MySQL Servers are updated to apply a new config or pod definition.
+	ReasonMySQLServersUpdating = "MySQLServersUpdating"
+	// ReasonMySQLScaleDown is the reason used for an Event when
+	// the operator scales down the MySQL Servers.
+	ReasonMySQLScaleDown = "MySQLScaleDown"
+	// ReasonMySQLCanaryStarted is the reason used for an Event when an
+	// update to the MySQL Servers is first applied to a canary MySQL Server.
+	ReasonMySQLCanaryStarted = "MySQLCanaryStarted"
+	// ReasonMySQLCanarySucceeded is the reason used for an Event when the
+	// canary MySQL Server passes the health check and the update is
+	// rolled out to the rest of the MySQL Servers.
+	ReasonMySQLCanarySucceeded = "MySQLCanarySucceeded"
+	// ReasonMySQLCanaryFailed is the reason used for an Event when the
+	// canary MySQL Server fails the health check and the update is rolled back.
+	ReasonMySQLCanaryFailed = "MySQLCanaryFailed"
+	// ReasonInitFromBackupStarted is the reason used for an Event when
+	// the operator starts restoring the backup specified in the spec.
+	ReasonInitFromBackupStarted = "InitFromBackupStarted"
+	// ReasonInitFromBackupCompleted is the reason used for an Event
+	// when the backup specified in the spec has been restored.
+	ReasonInitFromBackupCompleted = "InitFromBackupCompleted"
+	// ReasonInitFromBackupFailed is the reason used for an Event when
+	// the operator fails to restore the backup specified in the spec.
+	ReasonInitFromBackupFailed = "InitFromBackupFailed"
+	// ReasonInitFromDumpStarted is the reason used for an Event when
+	// the operator starts loading the SQL dump specified in the spec.
+	ReasonInitFromDumpStarted = "InitFromDumpStarted"
+	// ReasonInitFromDumpCompleted is the reason used for an Event
+	// when the SQL dump specified in the spec has been loaded.
+	ReasonInitFromDumpCompleted = "InitFromDumpCompleted"
+	// ReasonInitFromDumpFailed is the reason used for an Event when
+	// the operator fails to load the SQL dump specified in the spec.
+	ReasonInitFromDumpFailed = "InitFromDumpFailed"
+	// ReasonRootUserCreated is the reason used for an Event when the
+	// operator creates the root user in the MySQL Servers.
+	ReasonRootUserCreated = "RootUserCreated"
+	// ReasonRootUserUpdated is the reason used for an Event when the
+	// operator updates the host or the authentication plugin of the root user.
+	ReasonRootUserUpdated = "RootUserUpdated"
+	// ReasonRootPasswordUpdated is the reason used for an Event when the
+	// operator applies a change in the root password Secret to the root user.
+	ReasonRootPasswordUpdated = "RootPasswordUpdated"
+	// ReasonWaitingForSecret is the reason used for an Event when a
+	// Secret specified in the spec is yet to be created.
+	ReasonWaitingForSecret = "WaitingForSecret"
+	// ReasonOperatorUserRecovering is the reason used for an Event when the
+	// MySQL Servers reject the ndb operator user and the operator restarts
+	// the first MySQL Server to recover it.
+	ReasonOperatorUserRecovering = "OperatorUserRecovering"
+	// ReasonOperatorUserRecovered is the reason used for an Event when the
+	// recovered ndb operator user is accepted by the MySQL Servers.
+	ReasonOperatorUserRecovered = "OperatorUserRecovered"
+	// ReasonOperatorUserRecoveryFailed is the reason used for an Event when
+	// the ndb operator user is still rejected after the recovery.
+	ReasonOperatorUserRecoveryFailed = "OperatorUserRecoveryFailed"
+	// ReasonOperatorPasswordUpdated is the reason used for an Event when the
+	// operator applies a change in the ndb operator password Secret to the
+	// ndb operator user.
+	ReasonOperatorPasswordUpdated = "OperatorPasswordUpdated"
+	// ReasonMySQLServersResyncing is the reason used for an Event when the
+	// operator restarts all the MySQL Servers to resync them with a MySQL
+	// Cluster recreated by an initial system restart.
+	ReasonMySQLServersResyncing = "MySQLServersResyncing"
+	// ReasonLocationDomainsUpdated is the reason used for an Event when the
+	// MySQL Cluster nodes are assigned to new location domains as they have
+	// been moved to different zones.
+	ReasonLocationDomainsUpdated = "LocationDomainsUpdated"
+	// ReasonRestartRequested is the reason used for an Event when a rolling
+	// restart of the MySQL Cluster nodes is requested via the annotations.
+	ReasonRestartRequested = "RestartRequested"
+	// ReasonStateDumped is the reason used for an Event when the state of
+	// the MySQL Cluster is dumped into a ConfigMap as requested.
+	ReasonStateDumped = "StateDumped"
+	// ReasonRestartBlocked is the reason used for an Event when a requested
+	// restart of a data node is not safe to perform.
+	ReasonRestartBlocked = "RestartBlocked"
+	// ReasonPlacementViolated is the reason used for an Event when the
+	// data nodes are found to be placed in violation of the zone plan.
+	ReasonPlacementViolated = "PlacementViolated"
+	// ReasonPVCExpanding is the reason used for an Event when a data
+	// node PVC is expanded to the storage requested in the spec.
+	ReasonPVCExpanding = "PVCExpanding"
+	// ReasonPVCExpansionNotAllowed is the reason used for an Event when the
+	// storage class of the data node PVCs does not allow their expansion.
+	ReasonPVCExpansionNotAllowed = "PVCExpansionNotAllowed"
+	// ReasonWorkerNodeLost is the reason used for an Event when the worker
+	// node hosting the local PersistentVolume of a data node is lost.
+	ReasonWorkerNodeLost = "WorkerNodeLost"
+	// ReasonDataNodeRescheduling is the reason used for an Event when a data
+	// node of a lost worker node is being rescheduled onto another worker node.
+	ReasonDataNodeRescheduling = "DataNodeRescheduling"
+	// ReasonSingleArbitrator is the reason used for an Event when a MySQL
+	// Cluster with a redundancyLevel more than 1 is started with a single
+	// Management node as the only arbitrator.
+	ReasonSingleArbitrator = "SingleArbitrator"
+	// ReasonArbitrationUnavailable is the reason used for an Event when a
+	// Management node restart is delayed as the other Management node,
+	// which will act as the arbitrator, is not connected.
+	ReasonArbitrationUnavailable = "ArbitrationUnavailable"
+	// ReasonGatewayAPIUnavailable is the reason used for an Event when
+	// the gateway specified in the spec cannot be created as the K8s
+	// Server doesn't serve the Gateway API.
+	ReasonGatewayAPIUnavailable = "GatewayAPIUnavailable"
 
 	// ActionNone is the action used for an Event when the operator does nothing.
 	ActionNone = "None"
@@ -35,12 +213,40 @@ const (
 	// makes changes to the MySQL Cluster and successfully syncs it with
 	// the Ndb object.
 	ActionSynced = "Synced"
+	// ActionInitialRestart is the action used for an Event when the
+	// operator performs an initial restart of a data node.
+	ActionInitialRestart = "InitialRestart"
+	// ActionAdopted is the action used for an Event when
+	// the operator adopts an orphaned resource.
+	ActionAdopted = "Adopted"
+	// ActionCreated is the action used for an Event when
+	// the operator creates a resource.
+	ActionCreated = "Created"
+	// ActionUpdated is the action used for an Event when
+	// the operator updates a resource.
+	ActionUpdated = "Updated"
+	// ActionRestart is the action used for an Event when
+	// the operator restarts the MySQL Cluster nodes.
+	ActionRestart = "Restart"
+	// ActionScaleUp is the action used for an Event when
+	// the operator adds new nodes to the MySQL Cluster.
+	ActionScaleUp = "ScaleUp"
+	// ActionRollback is the action used for an Event when
+	// the operator rolls back an update to the MySQL Cluster nodes.
+	ActionRollback = "Rollback"
+	// ActionScaleDown is the action used for an Event when
+	// the operator removes nodes from the MySQL Cluster.
+	ActionScaleDown = "ScaleDown"
 
 	// MessageResourceExists is the message used for an Event when the
 	// operator fails to sync the Ndb object with MySQL Cluster due to
 	// some resource of the same name already existing but not owned by
 	// the Ndb object.
 	MessageResourceExists = "Resource %q already exists and is not managed by Ndb"
+	// MessageResourceAdopted is the message used for an Event when the
+	// operator adopts an orphaned resource that was created for the
+	// Ndb object but is not owned by it.
+	MessageResourceAdopted = "Orphaned resource %q was adopted by Ndb"
 	// MessageSyncSuccess is the message used for an Event when
 	// the MySQL Cluster is successfully synced with the Ndb object.
 	MessageSyncSuccess = "MySQL Cluster was successfully synced up to match the spec"
@@ -0,0 +1,214 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+// gatewayAPIResources maps the kinds of the Gateway API
+// resources created by the operator to their resources
+var gatewayAPIResources = map[string]schema.GroupVersionResource{
+	resources.GatewayGVK.Kind:  resources.GatewayGVK.GroupVersion().WithResource("gateways"),
+	resources.TCPRouteGVK.Kind: resources.TCPRouteGVK.GroupVersion().WithResource("tcproutes"),
+}
+
+// ServerSupportsGatewayAPI returns true if the K8s Server serves
+// the Gateway API resources used to expose the MySQL Servers
+func ServerSupportsGatewayAPI(client kubernetes.Interface) bool {
+	for _, gvr := range gatewayAPIResources {
+		resourceList, err := client.Discovery().ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+		if err == nil {
+			found := false
+			for _, resource := range resourceList.APIResources {
+				found = found || resource.Name == gvr.Resource
+			}
+			if found {
+				continue
+			}
+		}
+		klog.Warningf("Cannot expose MySQL Servers via the Gateway API as K8s Server doesn't support %s/%s",
+			gvr.GroupVersion().String(), gvr.Resource)
+		return false
+	}
+	return true
+}
+
+type GatewayControlInterface interface {
+	ReconcileGateway(ctx context.Context, sc *SyncContext) syncResult
+}
+
+type gatewayImpl struct {
+	dynamicClient dynamic.Interface
+	// listers of the Gateway API resources mapped by their kind
+	listers map[string]cache.GenericLister
+}
+
+// newGatewayControl creates a new GatewayControlInterface
+func newGatewayControl(
+	dynamicClient dynamic.Interface,
+	gatewayLister cache.GenericLister,
+	tcpRouteLister cache.GenericLister) GatewayControlInterface {
+	return &gatewayImpl{
+		dynamicClient: dynamicClient,
+		listers: map[string]cache.GenericLister{
+			resources.GatewayGVK.Kind:  gatewayLister,
+			resources.TCPRouteGVK.Kind: tcpRouteLister,
+		},
+	}
+}
+
+// isSubsetOf returns true if all the fields set in the desired
+// value have the same value in the existing value. This ignores
+// the fields defaulted by the K8s Server in the existing value.
+func isSubsetOf(desired, existing interface{}) bool {
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		existingValue, ok := existing.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range desiredValue {
+			if !isSubsetOf(value, existingValue[key]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		existingValue, ok := existing.([]interface{})
+		if !ok || len(existingValue) != len(desiredValue) {
+			return false
+		}
+		for i := range desiredValue {
+			if !isSubsetOf(desiredValue[i], existingValue[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return equality.Semantic.DeepEqual(desired, existing)
+	}
+}
+
+// gatewayAPIObjectUpToDate returns true if the existing
+// object has the spec and the metadata of the desired object
+func gatewayAPIObjectUpToDate(existing, desired *unstructured.Unstructured) bool {
+	return isSubsetOf(desired.Object["spec"], existing.Object["spec"]) &&
+		isSubsetOf(toInterfaceMap(desired.GetLabels()), toInterfaceMap(existing.GetLabels())) &&
+		isSubsetOf(toInterfaceMap(desired.GetAnnotations()), toInterfaceMap(existing.GetAnnotations()))
+}
+
+// toInterfaceMap converts the given string map into a generic map
+func toInterfaceMap(stringMap map[string]string) map[string]interface{} {
+	interfaceMap := make(map[string]interface{}, len(stringMap))
+	for key, value := range stringMap {
+		interfaceMap[key] = value
+	}
+	return interfaceMap
+}
+
+// ReconcileGateway creates or updates the Gateway and the TCPRoute that
+// expose the MySQL Servers if the Gateway API exposure is enabled, and
+// deletes them if it is disabled. The Gateway is created only if the
+// spec doesn't specify an existing Gateway to attach the TCPRoute to.
+func (gi *gatewayImpl) ReconcileGateway(ctx context.Context, sc *SyncContext) syncResult {
+	nc := sc.ndb
+
+	var gateway, tcpRoute *unstructured.Unstructured
+	if nc.GatewayEnabled() {
+		if nc.Spec.MysqlNode.Gateway.GatewayName == "" {
+			gateway = resources.NewMySQLServerGateway(nc)
+		}
+		tcpRoute = resources.NewMySQLServerTCPRoute(nc)
+	}
+
+	// Reconcile the Gateway before the TCPRoute attached to it
+	if err := gi.reconcileObject(ctx, sc, resources.GatewayGVK, nc.GetGatewayName(), gateway); err != nil {
+		return errorWhileProcessing(err)
+	}
+	if err := gi.reconcileObject(ctx, sc, resources.TCPRouteGVK, nc.GetTCPRouteName(), tcpRoute); err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	return continueProcessing()
+}
+
+// reconcileObject applies the desired Gateway API object of the given
+// kind, or deletes the existing object with the given name if the
+// desired object is nil.
+func (gi *gatewayImpl) reconcileObject(ctx context.Context, sc *SyncContext,
+	gvk schema.GroupVersionKind, name string, desired *unstructured.Unstructured) error {
+
+	nc := sc.ndb
+	objectName := getNamespacedName2(nc.Namespace, name)
+	resourceInterface := gi.dynamicClient.Resource(gatewayAPIResources[gvk.Kind]).Namespace(nc.Namespace)
+
+	var existing *unstructured.Unstructured
+	obj, err := gi.listers[gvk.Kind].ByNamespace(nc.Namespace).Get(name)
+	if err == nil {
+		existing = obj.(*unstructured.Unstructured)
+	} else if !apierrors.IsNotFound(err) {
+		// Error retrieving the object from the cache
+		klog.Errorf("Failed to retrieve %s %q : %s", gvk.Kind, objectName, err)
+		return err
+	}
+
+	if existing != nil {
+		// Object exists. Verify that it is owned by the NdbCluster resource.
+		if err = sc.ensureOwnedByNdbCluster(ctx, existing); err != nil {
+			return err
+		}
+	}
+
+	if desired == nil {
+		if existing == nil {
+			// Object is not required and there is none to delete
+			return nil
+		}
+
+		// Object is not required anymore - delete it
+		err = resourceInterface.Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.Errorf("Failed to delete %s %q : %s", gvk.Kind, objectName, err)
+			return err
+		}
+		klog.Infof("Deleted %s %q", gvk.Kind, objectName)
+		return nil
+	}
+
+	if existing != nil && gatewayAPIObjectUpToDate(existing, desired) {
+		// Object is up-to-date
+		return nil
+	}
+
+	// Create or update the object
+	patch, err := newApplyPatch(desired, gvk)
+	if err != nil {
+		klog.Errorf("Failed to generate the apply patch for %s %q : %s", gvk.Kind, objectName, err)
+		return err
+	}
+
+	if _, err = resourceInterface.Patch(
+		ctx, name, types.ApplyPatchType, patch, applyPatchOptions()); err != nil {
+		klog.Errorf("Failed to apply the %s %q : %s", gvk.Kind, objectName, err)
+		return err
+	}
+
+	klog.Infof("%s %q has been applied successfully", gvk.Kind, objectName)
+	return nil
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	core "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	klog "k8s.io/klog/v2"
+)
+
+func Test_ServerSupportsGatewayAPI(t *testing.T) {
+	gatewayResources := []*metav1.APIResourceList{
+		{
+			GroupVersion: resources.GatewayGVK.GroupVersion().String(),
+			APIResources: []metav1.APIResource{{Name: "gateways", Kind: "Gateway", Namespaced: true}},
+		},
+		{
+			GroupVersion: resources.TCPRouteGVK.GroupVersion().String(),
+			APIResources: []metav1.APIResource{{Name: "tcproutes", Kind: "TCPRoute", Namespaced: true}},
+		},
+	}
+
+	client := k8sfake.NewSimpleClientset()
+	client.Discovery().(*fakediscovery.FakeDiscovery).Resources = gatewayResources
+	if !ServerSupportsGatewayAPI(client) {
+		t.Error("Gateway API should be supported when both Gateway and TCPRoute are served")
+	}
+
+	// Only the Gateway is served
+	client.Discovery().(*fakediscovery.FakeDiscovery).Resources = gatewayResources[:1]
+	if ServerSupportsGatewayAPI(client) {
+		t.Error("Gateway API should not be supported when TCPRoute is not served")
+	}
+}
+
+func Test_ReconcileGateway(t *testing.T) {
+	nc := testutils.NewTestNdb("default", "example-ndb", 2)
+	nc.Spec.MysqlNode.Gateway = &v1.NdbMysqldGatewaySpec{GatewayClassName: "example"}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+	// Accept all the apply patches, as the fake client doesn't support them
+	dynamicClient.PrependReactor("patch", "*", func(action core.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+	gatewayIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	tcpRouteIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	gatewayControl := newGatewayControl(dynamicClient,
+		cache.NewGenericLister(gatewayIndexer, gatewayAPIResources[resources.GatewayGVK.Kind].GroupResource()),
+		cache.NewGenericLister(tcpRouteIndexer, gatewayAPIResources[resources.TCPRouteGVK.Kind].GroupResource()))
+	sc := &SyncContext{ndb: nc, dynamicClient: dynamicClient, logger: klog.Background()}
+
+	if sr := gatewayControl.ReconcileGateway(context.Background(), sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+
+	// Both the Gateway and the TCPRoute should have been applied
+	actions := dynamicClient.Actions()
+	if len(actions) != 2 || actions[0].GetResource().Resource != "gateways" ||
+		actions[1].GetResource().Resource != "tcproutes" {
+		t.Fatalf("Unexpected actions : %v", actions)
+	}
+	tcpRoute := &unstructured.Unstructured{}
+	if err := json.Unmarshal(actions[1].(core.PatchAction).GetPatch(), &tcpRoute.Object); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	parentRefs, _, _ := unstructured.NestedSlice(tcpRoute.Object, "spec", "parentRefs")
+	if len(parentRefs) != 1 || parentRefs[0].(map[string]interface{})["name"] != nc.GetGatewayName() {
+		t.Errorf("TCPRoute is not attached to the Gateway : %v", parentRefs)
+	}
+
+	// Nothing should be applied when the existing resources are up-to-date
+	for _, obj := range []*unstructured.Unstructured{
+		resources.NewMySQLServerGateway(nc), resources.NewMySQLServerTCPRoute(nc)} {
+		// Mimic the status set by the Gateway implementation
+		unstructured.SetNestedField(obj.Object, "Accepted", "status", "phase")
+		indexer := gatewayIndexer
+		if obj.GetKind() == resources.TCPRouteGVK.Kind {
+			indexer = tcpRouteIndexer
+		}
+		if err := indexer.Add(obj); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+	dynamicClient.ClearActions()
+	if sr := gatewayControl.ReconcileGateway(context.Background(), sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	if actions = dynamicClient.Actions(); len(actions) != 0 {
+		t.Errorf("Expected no actions but got %v", actions)
+	}
+
+	// Switching to an existing Gateway should delete the one created by the operator
+	nc.Spec.MysqlNode.Gateway = &v1.NdbMysqldGatewaySpec{GatewayName: "shared-gateway"}
+	if sr := gatewayControl.ReconcileGateway(context.Background(), sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	actions = dynamicClient.Actions()
+	if len(actions) != 2 || actions[0].GetVerb() != "delete" || actions[0].GetResource().Resource != "gateways" ||
+		actions[1].GetVerb() != "patch" || actions[1].GetResource().Resource != "tcproutes" {
+		t.Errorf("Unexpected actions : %v", actions)
+	}
+}
+
+func Test_reconcileGatewayUnsupported(t *testing.T) {
+	nc := testutils.NewTestNdb("default", "example-ndb", 2)
+	nc.Generation = 2
+	nc.Status.ProcessedGeneration = 1
+	nc.Spec.MysqlNode.Gateway = &v1.NdbMysqldGatewaySpec{GatewayClassName: "example"}
+
+	recorder := events.NewFakeRecorder(10)
+	sc := &SyncContext{
+		ndb:      nc,
+		recorder: recorder,
+		logger:   klog.Background(),
+	}
+
+	// A warning should be recorded when the spec is not processed yet
+	if sr := sc.reconcileGateway(context.Background()); sr.stopSync() {
+		t.Fatal("Expected the sync to continue when the Gateway API is not supported")
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("Expected a warning event but got %d events", len(recorder.Events))
+	}
+	<-recorder.Events
+
+	// The warning should not be repeated once the spec has been processed
+	nc.Status.ProcessedGeneration = 2
+	if sr := sc.reconcileGateway(context.Background()); sr.stopSync() {
+		t.Fatal("Expected the sync to continue when the Gateway API is not supported")
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("Expected no events but got %d events", len(recorder.Events))
+	}
+}
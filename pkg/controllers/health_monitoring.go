@@ -0,0 +1,131 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// getHealthyCondition returns the NdbClusterHealthy condition computed
+// by comparing the given health snapshot with the thresholds in the spec.
+func getHealthyCondition(
+	snapshot *v1.NdbClusterHealthSnapshot, spec *v1.NdbClusterHealthMonitoringSpec) *v1.NdbClusterCondition {
+
+	var violations []string
+	if snapshot.DataMemoryUsagePercent > spec.DataMemoryUsageThreshold {
+		violations = append(violations, fmt.Sprintf("data memory usage %d%% exceeds the threshold %d%%",
+			snapshot.DataMemoryUsagePercent, spec.DataMemoryUsageThreshold))
+	}
+	if snapshot.RedoLogSpaceUsagePercent > spec.RedoLogSpaceUsageThreshold {
+		violations = append(violations, fmt.Sprintf("redo log space usage %d%% exceeds the threshold %d%%",
+			snapshot.RedoLogSpaceUsagePercent, spec.RedoLogSpaceUsageThreshold))
+	}
+	if snapshot.DisconnectedTransporters > 0 {
+		violations = append(violations, fmt.Sprintf("%d transporters between the data nodes are not connected",
+			snapshot.DisconnectedTransporters))
+	}
+	if snapshot.LockWaits > spec.LockWaitsThreshold {
+		violations = append(violations, fmt.Sprintf("%d lock waits exceed the threshold %d",
+			snapshot.LockWaits, spec.LockWaitsThreshold))
+	}
+
+	if len(violations) != 0 {
+		return &v1.NdbClusterCondition{
+			Type:    v1.NdbClusterHealthy,
+			Status:  corev1.ConditionFalse,
+			Reason:  v1.NdbClusterHealthyReasonThresholdExceeded,
+			Message: "MySQL Cluster is unhealthy : " + strings.Join(violations, ", "),
+		}
+	}
+
+	return &v1.NdbClusterCondition{
+		Type:    v1.NdbClusterHealthy,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1.NdbClusterHealthyReasonWithinThresholds,
+		Message: "MySQL Cluster health is within the thresholds specified in the spec",
+	}
+}
+
+// monitorHealth samples the health of the data nodes from the ndbinfo
+// database, if it is enabled via spec.healthMonitoring, and sets the health
+// snapshot and the NdbClusterHealthy condition to be published in the
+// status. The health is sampled at most once per the interval specified in
+// the spec, and the NdbCluster is requeued to take the next sample. A Warning
+// event is recorded when a threshold is exceeded and a Normal event is
+// recorded once the MySQL Cluster recovers. The previous snapshot is left
+// unchanged if the health could not be sampled.
+func (sc *SyncContext) monitorHealth(ctx context.Context) {
+	nc := sc.ndb
+	healthMonitoring := nc.Spec.HealthMonitoring
+	if healthMonitoring == nil || sc.mysqldSfset == nil || *sc.mysqldSfset.Spec.Replicas == 0 {
+		// Health monitoring is disabled or there are no
+		// MySQL Servers to sample the health through.
+		return
+	}
+
+	// requeue requests the NdbCluster to be synced again after the given
+	// delay, unless a sync step has requested an earlier requeue.
+	requeue := func(delay time.Duration) {
+		if sc.requeueAfter == 0 || delay < sc.requeueAfter {
+			sc.requeueAfter = delay
+		}
+	}
+
+	interval := time.Duration(healthMonitoring.IntervalSeconds) * time.Second
+	previousSnapshot := nc.Status.Health
+	if previousSnapshot != nil {
+		if elapsed := time.Since(previousSnapshot.LastUpdateTime.Time); elapsed < interval {
+			// Previous snapshot is recent enough
+			requeue(interval - elapsed)
+			return
+		}
+	}
+	requeue(interval)
+
+	// Extract the ndb operator mysql user password
+	secretClient := NewMySQLUserPasswordSecretInterface(sc.kubeClientset())
+	operatorSecretName := resources.GetMySQLNDBOperatorPasswordSecretName(nc)
+	operatorPassword, err := secretClient.ExtractPassword(ctx, sc.mysqldSfset.Namespace, operatorSecretName)
+	if err != nil {
+		sc.logger.Error(err, "Failed to extract ndb operator password from the secret")
+		return
+	}
+
+	ndbInfoSnapshot, err := mysqlclient.GetNdbInfoSnapshot(ctx, sc.mysqldSfset, operatorPassword)
+	if err != nil {
+		sc.logger.Error(err, "Failed to sample the health of the MySQL Cluster")
+		return
+	}
+
+	snapshot := &v1.NdbClusterHealthSnapshot{
+		DataMemoryUsagePercent:   ndbInfoSnapshot.DataMemoryUsagePercent,
+		RedoLogSpaceUsagePercent: ndbInfoSnapshot.RedoLogSpaceUsagePercent,
+		DisconnectedTransporters: ndbInfoSnapshot.DisconnectedTransporters,
+		LockWaits:                ndbInfoSnapshot.LockWaits,
+		LastUpdateTime:           metav1.Now(),
+	}
+	healthyCondition := getHealthyCondition(snapshot, healthMonitoring)
+
+	// The sampled values are part of the message, so the
+	// Warning Event is not recorded again when they change
+	sc.updateCondition(healthyCondition, nc.GetHealthyCondition(), conditionEvents{
+		failureStatus:  corev1.ConditionFalse,
+		failureReason:  ReasonHealthThresholdExceeded,
+		recoveryReason: ReasonHealthRecovered,
+	})
+
+	sc.healthSnapshot = snapshot
+	sc.healthyCondition = healthyCondition
+}
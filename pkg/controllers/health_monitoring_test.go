@@ -0,0 +1,136 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_getHealthyCondition(t *testing.T) {
+	spec := &v1.NdbClusterHealthMonitoringSpec{
+		IntervalSeconds:            60,
+		DataMemoryUsageThreshold:   90,
+		RedoLogSpaceUsageThreshold: 80,
+		LockWaitsThreshold:         100,
+	}
+
+	for _, tc := range []struct {
+		desc             string
+		snapshot         v1.NdbClusterHealthSnapshot
+		expectedStatus   corev1.ConditionStatus
+		expectedMessages []string
+	}{
+		{
+			desc: "within thresholds",
+			snapshot: v1.NdbClusterHealthSnapshot{
+				DataMemoryUsagePercent:   90,
+				RedoLogSpaceUsagePercent: 80,
+				LockWaits:                100,
+			},
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			desc: "memory and redo log space thresholds exceeded",
+			snapshot: v1.NdbClusterHealthSnapshot{
+				DataMemoryUsagePercent:   95,
+				RedoLogSpaceUsagePercent: 81,
+			},
+			expectedStatus:   corev1.ConditionFalse,
+			expectedMessages: []string{"data memory usage 95%", "redo log space usage 81%"},
+		},
+		{
+			desc: "disconnected transporters and lock waits",
+			snapshot: v1.NdbClusterHealthSnapshot{
+				DisconnectedTransporters: 2,
+				LockWaits:                150,
+			},
+			expectedStatus:   corev1.ConditionFalse,
+			expectedMessages: []string{"2 transporters", "150 lock waits"},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			condition := getHealthyCondition(&tc.snapshot, spec)
+			if condition.Type != v1.NdbClusterHealthy || condition.Status != tc.expectedStatus {
+				t.Fatalf("Unexpected condition : %#v", condition)
+			}
+			for _, expectedMsg := range tc.expectedMessages {
+				if !strings.Contains(condition.Message, expectedMsg) {
+					t.Errorf("Condition message %q doesn't contain %q", condition.Message, expectedMsg)
+				}
+			}
+		})
+	}
+}
+
+func Test_monitorHealth(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.Spec.HealthMonitoring = &v1.NdbClusterHealthMonitoringSpec{
+		IntervalSeconds:            60,
+		DataMemoryUsageThreshold:   90,
+		RedoLogSpaceUsageThreshold: 80,
+		LockWaitsThreshold:         100,
+	}
+	ndb.Status.Health = &v1.NdbClusterHealthSnapshot{
+		DataMemoryUsagePercent: 95,
+		LastUpdateTime:         metav1.Now(),
+	}
+	ndb.Status.Conditions = []v1.NdbClusterCondition{
+		*getHealthyCondition(ndb.Status.Health, ndb.Spec.HealthMonitoring),
+	}
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+	sc := f.c.newSyncContext(ctx, ndb)
+	replicas := int32(1)
+	sc.mysqldSfset = &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-mysqld", Namespace: ns},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	}
+
+	// A recent snapshot should not be sampled again,
+	// but the next sample should be scheduled
+	sc.monitorHealth(ctx)
+	if sc.healthSnapshot != nil {
+		t.Error("Health sampled again before the interval elapsed")
+	}
+	if sc.requeueAfter <= 0 || sc.requeueAfter > time.Minute {
+		t.Errorf("Unexpected requeue delay %s", sc.requeueAfter)
+	}
+
+	// The previous snapshot and condition should be retained in the status
+	status := sc.calculateNdbClusterStatus()
+	if status.Health == nil || status.Health.DataMemoryUsagePercent != 95 {
+		t.Errorf("Health snapshot not retained : %#v", status.Health)
+	}
+	var healthyCondition *v1.NdbClusterCondition
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == v1.NdbClusterHealthy {
+			healthyCondition = &status.Conditions[i]
+		}
+	}
+	if healthyCondition == nil || healthyCondition.Status != corev1.ConditionFalse {
+		t.Errorf("Healthy condition not retained : %#v", healthyCondition)
+	}
+
+	// Snapshot should be dropped once the monitoring is disabled
+	ndb.Spec.HealthMonitoring = nil
+	if status = sc.calculateNdbClusterStatus(); status.Health != nil {
+		t.Errorf("Health snapshot retained after the monitoring was disabled : %#v", status.Health)
+	}
+}
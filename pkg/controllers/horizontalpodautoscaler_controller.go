@@ -0,0 +1,121 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	autoscalinglisterv2 "k8s.io/client-go/listers/autoscaling/v2"
+	klog "k8s.io/klog/v2"
+)
+
+// ServerSupportsAutoscalingV2 returns true if the K8s Server serves the autoscaling/v2 API
+func ServerSupportsAutoscalingV2(client kubernetes.Interface) bool {
+	_, err := client.Discovery().ServerResourcesForGroupVersion(autoscalingv2.SchemeGroupVersion.String())
+	if err != nil {
+		klog.Warningf("Cannot autoscale MySQL Servers as K8s Server doesn't support %s : %s",
+			autoscalingv2.SchemeGroupVersion.String(), err)
+		return false
+	}
+	return true
+}
+
+type HorizontalPodAutoscalerControlInterface interface {
+	ReconcileHorizontalPodAutoscaler(ctx context.Context, sc *SyncContext) syncResult
+}
+
+type horizontalPodAutoscalerImpl struct {
+	k8sClient kubernetes.Interface
+	hpaLister autoscalinglisterv2.HorizontalPodAutoscalerLister
+}
+
+// newHorizontalPodAutoscalerControl creates a new HorizontalPodAutoscalerControlInterface
+func newHorizontalPodAutoscalerControl(
+	client kubernetes.Interface,
+	hpaLister autoscalinglisterv2.HorizontalPodAutoscalerLister) HorizontalPodAutoscalerControlInterface {
+	return &horizontalPodAutoscalerImpl{
+		k8sClient: client,
+		hpaLister: hpaLister,
+	}
+}
+
+// hpaSpecEqual checks if the fields of the HPA spec set by the operator are equal.
+// The other fields, like Behavior, are defaulted by the K8s Server and are ignored.
+func hpaSpecEqual(existingSpec, newSpec *autoscalingv2.HorizontalPodAutoscalerSpec) bool {
+	return existingSpec.ScaleTargetRef == newSpec.ScaleTargetRef &&
+		equality.Semantic.DeepEqual(existingSpec.MinReplicas, newSpec.MinReplicas) &&
+		existingSpec.MaxReplicas == newSpec.MaxReplicas &&
+		equality.Semantic.DeepEqual(existingSpec.Metrics, newSpec.Metrics)
+}
+
+// ReconcileHorizontalPodAutoscaler creates or updates the HorizontalPodAutoscaler
+// of the MySQL Servers if autoscaling is enabled, and deletes it if it is disabled.
+func (hpai *horizontalPodAutoscalerImpl) ReconcileHorizontalPodAutoscaler(
+	ctx context.Context, sc *SyncContext) syncResult {
+
+	nc := sc.ndb
+	hpaName := nc.GetHorizontalPodAutoscalerName()
+	hpaInterface := hpai.k8sClient.AutoscalingV2().HorizontalPodAutoscalers(nc.Namespace)
+	existingHpa, err := hpai.hpaLister.HorizontalPodAutoscalers(nc.Namespace).Get(hpaName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		// Error retrieving HPA from the cache
+		klog.Errorf("Failed to retrieve HorizontalPodAutoscaler %q : %s",
+			getNamespacedName2(nc.Namespace, hpaName), err)
+		return errorWhileProcessing(err)
+	}
+
+	if existingHpa != nil {
+		// HPA exists. Verify that it is owned by the NdbCluster resource.
+		if err = sc.ensureOwnedByNdbCluster(ctx, existingHpa); err != nil {
+			return errorWhileProcessing(err)
+		}
+	}
+
+	if !nc.MySQLServerAutoscalingEnabled() {
+		if existingHpa == nil {
+			// Autoscaling is disabled and there is no HPA
+			return continueProcessing()
+		}
+
+		// Autoscaling has been disabled - delete the existing HPA
+		err = hpaInterface.Delete(ctx, hpaName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.Errorf("Failed to delete HorizontalPodAutoscaler %q : %s", getNamespacedName(existingHpa), err)
+			return errorWhileProcessing(err)
+		}
+		klog.Infof("Deleted HorizontalPodAutoscaler %q", getNamespacedName(existingHpa))
+		return continueProcessing()
+	}
+
+	hpa := resources.NewHorizontalPodAutoscaler(nc)
+	if existingHpa != nil && hpaSpecEqual(&existingHpa.Spec, &hpa.Spec) {
+		// HPA is up-to-date
+		return continueProcessing()
+	}
+
+	// Create or update the HPA
+	patch, err := newApplyPatch(hpa, autoscalingv2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"))
+	if err != nil {
+		klog.Errorf("Failed to generate the apply patch for HorizontalPodAutoscaler %q : %s",
+			getNamespacedName(hpa), err)
+		return errorWhileProcessing(err)
+	}
+
+	if _, err = hpaInterface.Patch(ctx, hpaName, types.ApplyPatchType, patch, applyPatchOptions()); err != nil {
+		klog.Errorf("Failed to apply the HorizontalPodAutoscaler %q : %s", getNamespacedName(hpa), err)
+		return errorWhileProcessing(err)
+	}
+
+	klog.Infof("HorizontalPodAutoscaler %q has been applied successfully", getNamespacedName(hpa))
+	return continueProcessing()
+}
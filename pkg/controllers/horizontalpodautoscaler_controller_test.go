@@ -0,0 +1,256 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	autoscalinglisterv2 "k8s.io/client-go/listers/autoscaling/v2"
+	"k8s.io/client-go/tools/cache"
+)
+
+func Test_ServerSupportsAutoscalingV2(t *testing.T) {
+	client := k8sfake.NewSimpleClientset()
+	if ServerSupportsAutoscalingV2(client) {
+		t.Error("autoscaling/v2 should not be supported when it is not served")
+	}
+
+	client.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: autoscalingv2.SchemeGroupVersion.String(),
+			APIResources: []metav1.APIResource{
+				{Name: "horizontalpodautoscalers", Kind: "HorizontalPodAutoscaler", Namespaced: true},
+			},
+		},
+	}
+	if !ServerSupportsAutoscalingV2(client) {
+		t.Error("autoscaling/v2 should be supported when it is served")
+	}
+}
+
+func Test_NewHorizontalPodAutoscaler(t *testing.T) {
+	int32Ptr := func(i int32) *int32 { return &i }
+
+	for _, tc := range []struct {
+		desc                string
+		autoscaling         v1.NdbMysqldAutoscalingSpec
+		expectedMinReplicas int32
+		expectedMaxReplicas int32
+		expectedCPUTarget   *int32
+		expectedConnections *int64
+		expectedMetricName  string
+	}{
+		{
+			desc:                "defaults",
+			expectedMinReplicas: 1,
+			// spec.mysqlNode.maxNodeCount of the test NdbCluster
+			expectedMaxReplicas: 4,
+			expectedCPUTarget:   int32Ptr(80),
+		},
+		{
+			desc: "limits within the reserved API slots",
+			autoscaling: v1.NdbMysqldAutoscalingSpec{
+				MinNodeCount:                   2,
+				MaxNodeCount:                   3,
+				TargetCPUUtilizationPercentage: int32Ptr(60),
+			},
+			expectedMinReplicas: 2,
+			expectedMaxReplicas: 3,
+			expectedCPUTarget:   int32Ptr(60),
+		},
+		{
+			desc: "max node count beyond the reserved API slots",
+			autoscaling: v1.NdbMysqldAutoscalingSpec{
+				MaxNodeCount: 10,
+			},
+			expectedMinReplicas: 1,
+			expectedMaxReplicas: 4,
+			expectedCPUTarget:   int32Ptr(80),
+		},
+		{
+			desc: "only the connections target",
+			autoscaling: v1.NdbMysqldAutoscalingSpec{
+				TargetConnectionsPerServer: int32Ptr(100),
+			},
+			expectedMinReplicas: 1,
+			expectedMaxReplicas: 4,
+			expectedConnections: func() *int64 { c := int64(100); return &c }(),
+			expectedMetricName:  "mysql_global_status_threads_connected",
+		},
+		{
+			desc: "both the CPU and the connections targets",
+			autoscaling: v1.NdbMysqldAutoscalingSpec{
+				TargetCPUUtilizationPercentage: int32Ptr(70),
+				TargetConnectionsPerServer:     int32Ptr(50),
+				ConnectionsMetricName:          "mysql_connections",
+			},
+			expectedMinReplicas: 1,
+			expectedMaxReplicas: 4,
+			expectedCPUTarget:   int32Ptr(70),
+			expectedConnections: func() *int64 { c := int64(50); return &c }(),
+			expectedMetricName:  "mysql_connections",
+		},
+	} {
+		nc := testutils.NewTestNdb("default", "example-ndb", 2)
+		autoscaling := tc.autoscaling
+		nc.Spec.MysqlNode.Autoscaling = &autoscaling
+
+		hpa := resources.NewHorizontalPodAutoscaler(nc)
+		if hpa.Name != nc.GetHorizontalPodAutoscalerName() || hpa.Namespace != nc.Namespace {
+			t.Errorf("%s : unexpected HorizontalPodAutoscaler name %q", tc.desc, getNamespacedName(hpa))
+		}
+
+		// The NdbCluster should be scaled via its scale subresource
+		expectedTargetRef := autoscalingv2.CrossVersionObjectReference{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       "NdbCluster",
+			Name:       nc.Name,
+		}
+		if hpa.Spec.ScaleTargetRef != expectedTargetRef {
+			t.Errorf("%s : unexpected scale target %v", tc.desc, hpa.Spec.ScaleTargetRef)
+		}
+
+		if hpa.Spec.MinReplicas == nil || *hpa.Spec.MinReplicas != tc.expectedMinReplicas {
+			t.Errorf("%s : expected minReplicas %d but got %v", tc.desc, tc.expectedMinReplicas, hpa.Spec.MinReplicas)
+		}
+		if hpa.Spec.MaxReplicas != tc.expectedMaxReplicas {
+			t.Errorf("%s : expected maxReplicas %d but got %d", tc.desc, tc.expectedMaxReplicas, hpa.Spec.MaxReplicas)
+		}
+
+		// Verify the metrics
+		var cpuTarget *int32
+		var connections *int64
+		var metricName string
+		for _, metric := range hpa.Spec.Metrics {
+			switch metric.Type {
+			case autoscalingv2.ResourceMetricSourceType:
+				if metric.Resource.Name != corev1.ResourceCPU ||
+					metric.Resource.Target.Type != autoscalingv2.UtilizationMetricType {
+					t.Errorf("%s : unexpected resource metric %v", tc.desc, metric.Resource)
+				}
+				cpuTarget = metric.Resource.Target.AverageUtilization
+			case autoscalingv2.PodsMetricSourceType:
+				if metric.Pods.Target.Type != autoscalingv2.AverageValueMetricType ||
+					metric.Pods.Target.AverageValue == nil {
+					t.Errorf("%s : unexpected pods metric %v", tc.desc, metric.Pods)
+					continue
+				}
+				value := metric.Pods.Target.AverageValue.Value()
+				connections = &value
+				metricName = metric.Pods.Metric.Name
+			default:
+				t.Errorf("%s : unexpected metric type %q", tc.desc, metric.Type)
+			}
+		}
+
+		if (cpuTarget == nil) != (tc.expectedCPUTarget == nil) ||
+			(cpuTarget != nil && *cpuTarget != *tc.expectedCPUTarget) {
+			t.Errorf("%s : expected CPU target %v but got %v", tc.desc, tc.expectedCPUTarget, cpuTarget)
+		}
+		if (connections == nil) != (tc.expectedConnections == nil) ||
+			(connections != nil && *connections != *tc.expectedConnections) {
+			t.Errorf("%s : expected connections target %v but got %v", tc.desc, tc.expectedConnections, connections)
+		}
+		if metricName != tc.expectedMetricName {
+			t.Errorf("%s : expected connections metric %q but got %q", tc.desc, tc.expectedMetricName, metricName)
+		}
+	}
+}
+
+func Test_ReconcileHorizontalPodAutoscaler(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.UID = "ndb-uid"
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+	hpaIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	hpai := newHorizontalPodAutoscalerControl(
+		f.k8sclient, autoscalinglisterv2.NewHorizontalPodAutoscalerLister(hpaIndexer))
+	hpaInterface := f.k8sclient.AutoscalingV2().HorizontalPodAutoscalers(ns)
+	hpaName := ndb.GetHorizontalPodAutoscalerName()
+
+	// countPatches returns the number of patches sent for the HPA
+	countPatches := func() int {
+		patches := 0
+		for _, action := range f.k8sclient.Actions() {
+			if action.Matches("patch", "horizontalpodautoscalers") {
+				patches++
+			}
+		}
+		return patches
+	}
+
+	// No HPA should be created when autoscaling is disabled
+	sc := f.c.newSyncContext(ctx, ndb)
+	if sr := hpai.ReconcileHorizontalPodAutoscaler(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	if _, err := hpaInterface.Get(ctx, hpaName, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("HorizontalPodAutoscaler %q created when autoscaling is disabled : %v", hpaName, err)
+	}
+
+	// A missing HPA should be created via server side apply
+	enabled := ndb.DeepCopy()
+	enabled.Spec.MysqlNode.Autoscaling = &v1.NdbMysqldAutoscalingSpec{MinNodeCount: 1}
+	sc = f.c.newSyncContext(ctx, enabled)
+	if sr := hpai.ReconcileHorizontalPodAutoscaler(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	hpa, err := hpaInterface.Get(ctx, hpaName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("HorizontalPodAutoscaler %q was not created : %s", hpaName, err)
+	}
+	if countPatches() != 1 {
+		t.Errorf("HorizontalPodAutoscaler %q not created via an apply", hpaName)
+	}
+
+	// An up-to-date HPA should not be applied again, even
+	// if the K8s Server has defaulted its other fields
+	hpa.Spec.Behavior = &autoscalingv2.HorizontalPodAutoscalerBehavior{}
+	if err = hpaIndexer.Add(hpa); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if sr := hpai.ReconcileHorizontalPodAutoscaler(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	if countPatches() != 1 {
+		t.Errorf("Up-to-date HorizontalPodAutoscaler %q was applied again", hpaName)
+	}
+
+	// The HPA should be updated when the autoscaling spec changes
+	updated := enabled.DeepCopy()
+	updated.Spec.MysqlNode.Autoscaling.MinNodeCount = 2
+	sc = f.c.newSyncContext(ctx, updated)
+	if sr := hpai.ReconcileHorizontalPodAutoscaler(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	if countPatches() != 2 {
+		t.Errorf("HorizontalPodAutoscaler %q was not updated when the autoscaling spec changed", hpaName)
+	}
+
+	// The HPA should be deleted when autoscaling is disabled
+	sc = f.c.newSyncContext(ctx, ndb)
+	if sr := hpai.ReconcileHorizontalPodAutoscaler(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	if _, err = hpaInterface.Get(ctx, hpaName, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("HorizontalPodAutoscaler %q not deleted when autoscaling was disabled : %v", hpaName, err)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ensureInitFromBackup restores the backup specified in spec.initFromBackup
+// when the MySQL Cluster is started for the first time. The backup is
+// restored by a Job once the Management and Data nodes are ready, and the
+// sync waits for the Job to complete before starting the MySQL Servers.
+// A failed restore is reported in the NdbCluster status and is not retried.
+func (sc *SyncContext) ensureInitFromBackup(ctx context.Context) syncResult {
+	nc := sc.ndb
+	if nc.Spec.InitFromBackup == nil || nc.Status.ProcessedGeneration != 0 {
+		// No backup to restore or the MySQL Cluster has already been started
+		return continueProcessing()
+	}
+
+	initFromBackup := nc.Spec.InitFromBackup
+	job, state, err := sc.ensureInitJob(ctx, resources.NewInitFromBackupJob(nc))
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	switch state {
+	case initJobCreated:
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonInitFromBackupStarted, ActionCreated,
+			"Restoring backup %d from PersistentVolumeClaim %q",
+			initFromBackup.BackupID, initFromBackup.PersistentVolumeClaimName)
+		return finishProcessing()
+	case initJobRunning:
+		// Wait for the restore to complete
+		return finishProcessing()
+	case initJobFailed:
+		// Restore failed. Report the failure until the NdbCluster is recreated.
+		errMsg := fmt.Sprintf("failed to restore backup %d : %s", initFromBackup.BackupID, getInitJobFailure(job))
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeWarning, ReasonInitFromBackupFailed, ActionNone, "%s", errMsg)
+		sc.workloadErrors = append(sc.workloadErrors, errMsg)
+		return finishProcessing()
+	}
+
+	// Backup has been restored
+	if sc.mysqldSfset == nil {
+		// MySQL Servers have not been started yet => the restore
+		// has just been completed. Record it in an Event.
+		sc.logger.Info("Backup has been restored", "backupId", initFromBackup.BackupID)
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonInitFromBackupCompleted, ActionSynced,
+			"Backup %d has been restored", initFromBackup.BackupID)
+	}
+	return continueProcessing()
+}
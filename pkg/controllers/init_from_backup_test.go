@@ -0,0 +1,112 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ensureInitFromBackup(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.Spec.InitFromBackup = &v1.NdbClusterInitFromBackupSpec{
+		BackupID:                     3,
+		PersistentVolumeClaimName:    "backups",
+		Parallelism:                  256,
+		RestoreEpoch:                 true,
+		IncludeDatabases:             []string{"app", "shop"},
+		RewriteDatabases:             []v1.NdbClusterDatabaseRewrite{{From: "app", To: "app_copy"}},
+		EncryptionPasswordSecretName: "backup-password",
+	}
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+	jobInterface := f.k8sclient.BatchV1().Jobs(ns)
+
+	// First sync should create the Job and wait for it to complete
+	sc := f.c.newSyncContext(ctx, ndb)
+	if sr := sc.ensureInitFromBackup(ctx); !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error, error : %v", sr.getError())
+	}
+	if sc.requeueAfter == 0 {
+		t.Error("NdbCluster not requeued to check the status of the Job")
+	}
+
+	job, err := jobInterface.Get(ctx, ndb.GetInitFromBackupJobName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Job to restore the backup was not created :", err)
+	}
+	env := job.Spec.Template.Spec.Containers[0].Env
+	getEnvValue := func(name string) string {
+		for _, envVar := range env {
+			if envVar.Name == name {
+				return envVar.Value
+			}
+		}
+		t.Errorf("Env variable %q not found in the Job : %#v", name, env)
+		return ""
+	}
+	if backupPath := getEnvValue("BACKUP_PATH"); backupPath != "/backup/BACKUP/BACKUP-3" {
+		t.Errorf("Unexpected backup path in the Job : %q", backupPath)
+	}
+	if options := getEnvValue("NDB_RESTORE_OPTIONS"); options !=
+		"--parallelism=256 '--include-databases=app,shop' '--rewrite-database=app,app_copy'" {
+		t.Errorf("Unexpected ndb_restore options in the Job : %q", options)
+	}
+	if epochOption := getEnvValue("RESTORE_EPOCH_OPTION"); epochOption != "--restore-epoch" {
+		t.Errorf("Unexpected ndb_restore epoch option in the Job : %q", epochOption)
+	}
+	// The password to decrypt the backup should be read from the Secret
+	if passwordEnv := env[len(env)-1]; passwordEnv.Name != "BACKUP_PASSWORD" || passwordEnv.ValueFrom == nil ||
+		passwordEnv.ValueFrom.SecretKeyRef == nil || passwordEnv.ValueFrom.SecretKeyRef.Name != "backup-password" {
+		t.Errorf("Unexpected backup password env in the Job : %#v", passwordEnv)
+	}
+
+	// The sync should continue once the Job completes
+	setJobCondition := func(conditionType batchv1.JobConditionType) {
+		job.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue}}
+		if job, err = jobInterface.UpdateStatus(ctx, job, metav1.UpdateOptions{}); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+	setJobCondition(batchv1.JobComplete)
+	sc = f.c.newSyncContext(ctx, ndb)
+	if sr := sc.ensureInitFromBackup(ctx); sr.stopSync() {
+		t.Fatalf("Sync stopped after the backup was restored, error : %v", sr.getError())
+	}
+
+	// A failed restore should be reported
+	setJobCondition(batchv1.JobFailed)
+	sc = f.c.newSyncContext(ctx, ndb)
+	if sr := sc.ensureInitFromBackup(ctx); !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error, error : %v", sr.getError())
+	}
+	if len(sc.workloadErrors) != 1 {
+		t.Errorf("Failed restore not reported : %v", sc.workloadErrors)
+	}
+
+	// Nothing should be restored once the MySQL Cluster has been started
+	startedNdb := ndb.DeepCopy()
+	startedNdb.Status.ProcessedGeneration = 1
+	if startedNdb, err = f.ndbclient.MysqlV1().NdbClusters(ns).UpdateStatus(
+		ctx, startedNdb, metav1.UpdateOptions{}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	sc = f.c.newSyncContext(ctx, startedNdb)
+	if sr := sc.ensureInitFromBackup(ctx); sr.stopSync() {
+		t.Fatalf("Sync stopped for an NdbCluster that has already been started, error : %v", sr.getError())
+	}
+}
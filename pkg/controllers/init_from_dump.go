@@ -0,0 +1,63 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ensureInitFromDump loads the SQL dump specified in spec.initFromDump
+// when the MySQL Cluster is started for the first time. The dump is
+// loaded by a Job once all the MySQL Cluster nodes are ready and the
+// root user has been created, and the sync waits for the Job to complete
+// before marking the NdbCluster as ready. A failed load is reported in
+// the NdbCluster status and is not retried.
+func (sc *SyncContext) ensureInitFromDump(ctx context.Context) syncResult {
+	nc := sc.ndb
+	if nc.Spec.InitFromDump == nil || nc.Status.ProcessedGeneration != 0 {
+		// No dump to load or the MySQL Cluster has already been started
+		return continueProcessing()
+	}
+
+	if sc.mysqldSfset == nil || *sc.mysqldSfset.Spec.Replicas == 0 {
+		// The spec validation ensures that there is atleast one MySQL Server
+		sc.logger.Info("Skipping loading the dump as the NdbCluster has no MySQL Servers")
+		return continueProcessing()
+	}
+
+	initFromDump := nc.Spec.InitFromDump
+	job, state, err := sc.ensureInitJob(ctx, resources.NewInitFromDumpJob(nc))
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	switch state {
+	case initJobCreated:
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonInitFromDumpStarted, ActionCreated,
+			"Loading dump %q from PersistentVolumeClaim %q", initFromDump.Path, initFromDump.PersistentVolumeClaimName)
+		return finishProcessing()
+	case initJobRunning:
+		// Wait for the dump to be loaded
+		return finishProcessing()
+	case initJobFailed:
+		// Load failed. Report the failure until the NdbCluster is recreated.
+		errMsg := fmt.Sprintf("failed to load dump %q : %s", initFromDump.Path, getInitJobFailure(job))
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeWarning, ReasonInitFromDumpFailed, ActionNone, "%s", errMsg)
+		sc.workloadErrors = append(sc.workloadErrors, errMsg)
+		return finishProcessing()
+	}
+
+	// Dump has been loaded. This step is run only until the
+	// end of the first successful sync, which follows right after.
+	sc.logger.Info("Dump has been loaded", "dump", initFromDump.Path)
+	sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonInitFromDumpCompleted, ActionSynced,
+		"Dump %q has been loaded", initFromDump.Path)
+	return continueProcessing()
+}
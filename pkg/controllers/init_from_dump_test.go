@@ -0,0 +1,76 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ensureInitFromDump(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.Spec.InitFromDump = &v1.NdbClusterInitFromDumpSpec{
+		PersistentVolumeClaimName: "dumps",
+		Path:                      "exports/app.sql",
+	}
+	ndb.Spec.MysqlNode.Port = 3307
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+	replicas := int32(2)
+	mysqldSfset := &appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{Replicas: &replicas},
+	}
+
+	// Nothing should be loaded without any MySQL Servers
+	sc := f.c.newSyncContext(ctx, ndb)
+	if sr := sc.ensureInitFromDump(ctx); sr.stopSync() {
+		t.Fatalf("Sync stopped for an NdbCluster without MySQL Servers, error : %v", sr.getError())
+	}
+
+	// Sync should create the Job and wait for it to complete
+	sc = f.c.newSyncContext(ctx, ndb)
+	sc.mysqldSfset = mysqldSfset
+	if sr := sc.ensureInitFromDump(ctx); !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error, error : %v", sr.getError())
+	}
+
+	jobInterface := f.k8sclient.BatchV1().Jobs(ns)
+	job, err := jobInterface.Get(ctx, ndb.GetInitFromDumpJobName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Job to load the dump was not created :", err)
+	}
+	for _, env := range job.Spec.Template.Spec.Containers[0].Env {
+		if env.Name == "DUMP_FILE" && env.Value != "/dump/exports/app.sql" {
+			t.Errorf("Unexpected dump file in the Job : %s", env.Value)
+		}
+		if env.Name == "MYSQL_PORT" && env.Value != "3307" {
+			t.Errorf("Unexpected MySQL Server port in the Job : %s", env.Value)
+		}
+	}
+
+	// The sync should continue once the Job completes
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	if _, err = jobInterface.UpdateStatus(ctx, job, metav1.UpdateOptions{}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	sc = f.c.newSyncContext(ctx, ndb)
+	sc.mysqldSfset = mysqldSfset
+	if sr := sc.ensureInitFromDump(ctx); sr.stopSync() {
+		t.Fatalf("Sync stopped after the dump was loaded, error : %v", sr.getError())
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// initJobPollInterval is the interval at which a Job
+// initialising the MySQL Cluster is checked for completion.
+const initJobPollInterval = 15 * time.Second
+
+// initJobState is the state of a Job that
+// initialises the MySQL Cluster when it is created
+type initJobState int
+
+const (
+	// initJobCreated implies that the Job has just been created
+	initJobCreated initJobState = iota
+	// initJobRunning implies that the Job is still running
+	initJobRunning
+	// initJobFailed implies that the Job has failed
+	initJobFailed
+	// initJobCompleted implies that the Job has completed successfully
+	initJobCompleted
+)
+
+// getJobCondition returns the given condition
+// of the Job if it is true, and nil otherwise
+func getJobCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		condition := &job.Status.Conditions[i]
+		if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+			return condition
+		}
+	}
+	return nil
+}
+
+// ensureInitJob creates the given Job if it doesn't exist yet and returns
+// the existing Job and its state otherwise. Jobs are not watched by the
+// operator, so the NdbCluster is requeued to check the Job again if it
+// has not finished yet.
+func (sc *SyncContext) ensureInitJob(
+	ctx context.Context, newJob *batchv1.Job) (job *batchv1.Job, state initJobState, err error) {
+
+	jobInterface := sc.kubeClientset().BatchV1().Jobs(newJob.Namespace)
+	job, err = jobInterface.Get(ctx, newJob.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		// Job doesn't exist yet - create it
+		if job, err = jobInterface.Create(ctx, newJob, createOptions()); err != nil {
+			sc.logger.Error(err, "Failed to create the Job", "job", getNamespacedName(newJob))
+			return nil, initJobCreated, err
+		}
+
+		sc.logger.Info("Created resource", "resource", "Job", "job", getNamespacedName(job))
+		sc.requeueAfter = initJobPollInterval
+		return job, initJobCreated, nil
+	} else if err != nil {
+		sc.logger.Error(err, "Failed to retrieve the Job", "job", getNamespacedName(newJob))
+		return nil, initJobCreated, err
+	}
+
+	// Verify that the Job is owned by the NdbCluster
+	if err = sc.ensureOwnedByNdbCluster(ctx, job); err != nil {
+		return nil, initJobCreated, err
+	}
+
+	if getJobCondition(job, batchv1.JobFailed) != nil {
+		return job, initJobFailed, nil
+	}
+
+	if getJobCondition(job, batchv1.JobComplete) == nil {
+		// Job is still running - check it again after a while
+		sc.logger.Info("Waiting for the Job to complete", "job", getNamespacedName(job))
+		sc.requeueAfter = initJobPollInterval
+		return job, initJobRunning, nil
+	}
+
+	return job, initJobCompleted, nil
+}
+
+// getInitJobFailure returns the reason for the failure of the given Job
+func getInitJobFailure(job *batchv1.Job) string {
+	failedCondition := getJobCondition(job, batchv1.JobFailed)
+	if failedCondition == nil {
+		return ""
+	}
+	return fmt.Sprintf("Job %q failed : %s", getNamespacedName(job), failedCondition.Message)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// getLocationDomainZones returns the values of the location domain
+// topology key on the worker nodes running the data node pods, mapped by
+// the names of the pods. Only the data nodes, which stay pinned to their
+// worker nodes and zones, are assigned to the location domains, as the
+// Management and MySQL Server pods can be rescheduled to any worker node
+// and every move would require a new config and a rolling restart. The
+// pods that are yet to be scheduled and the pods running on worker nodes
+// without the label are left out of the map.
+func (sc *SyncContext) getLocationDomainZones() (map[string]string, error) {
+	return sc.getPodZones([]*appsv1.StatefulSet{sc.dataNodeSfSet}, sc.ndb.Spec.LocationDomainTopologyKey)
+}
+
+// getPodZones returns the values of the given topology key on the worker
+// nodes running the pods of the given StatefulSets, mapped by the names of
+// the pods. The pods that are yet to be scheduled and the pods running on
+// worker nodes without the label are left out of the map.
+func (sc *SyncContext) getPodZones(
+	sfsets []*appsv1.StatefulSet, topologyKey string) (map[string]string, error) {
+	// Zones of the worker nodes, cached to look up every worker node only once
+	workerNodeZones := make(map[string]string)
+	podZones := make(map[string]string)
+	for _, sfset := range sfsets {
+		if sfset == nil {
+			// The StatefulSet doesn't exist
+			continue
+		}
+
+		for podOrdinal := 0; podOrdinal < int(*sfset.Spec.Replicas); podOrdinal++ {
+			podName := fmt.Sprintf("%s-%d", sfset.Name, podOrdinal)
+			pod, err := sc.podLister.Pods(sfset.Namespace).Get(podName)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					// Pod is yet to be created by the StatefulSet controller
+					continue
+				}
+				return nil, err
+			}
+
+			workerNodeName := pod.Spec.NodeName
+			if workerNodeName == "" {
+				// Pod is yet to be scheduled
+				continue
+			}
+
+			zone, exists := workerNodeZones[workerNodeName]
+			if !exists {
+				workerNode, err := sc.nodeLister.Get(workerNodeName)
+				if err != nil {
+					return nil, err
+				}
+				zone = workerNode.Labels[topologyKey]
+				workerNodeZones[workerNodeName] = zone
+			}
+
+			if zone != "" {
+				podZones[podName] = zone
+			}
+		}
+	}
+
+	return podZones, nil
+}
+
+// reconcileLocationDomains updates the config map with a new config.ini
+// if the MySQL Cluster nodes have been moved to different zones since the
+// last time they were assigned to the location domains. It does nothing
+// if the location domains have not been enabled in the spec.
+func (sc *SyncContext) reconcileLocationDomains(ctx context.Context) syncResult {
+	nc := sc.ndb
+	if nc.Spec.LocationDomainTopologyKey == "" {
+		// Location domains are not enabled
+		return continueProcessing()
+	}
+
+	locationDomainZones, err := sc.getLocationDomainZones()
+	if err != nil {
+		sc.logger.Error(err, "Failed to retrieve the zones of the MySQL Cluster nodes")
+		return errorWhileProcessing(err)
+	}
+
+	existingZones := sc.configSummary.LocationDomainZones
+	if len(locationDomainZones) == len(existingZones) &&
+		(len(existingZones) == 0 || reflect.DeepEqual(locationDomainZones, existingZones)) {
+		// The nodes are in the same zones as in the config
+		return continueProcessing()
+	}
+
+	// Update the config map with the new location domains
+	sc.logger.Info("The MySQL Cluster nodes need to be assigned to new location domains",
+		"zones", locationDomainZones)
+	if _, err = sc.configMapController.PatchLocationDomains(ctx, sc, locationDomainZones); err != nil {
+		sc.logger.Error(err, "Failed to patch the ConfigMap with the location domains")
+		return errorWhileProcessing(err)
+	}
+
+	sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonLocationDomainsUpdated, ActionUpdated,
+		"MySQL Cluster nodes are being assigned to the location domains of the %q label of their worker nodes",
+		nc.Spec.LocationDomainTopologyKey)
+
+	// The new config will be rolled out to the
+	// MySQL Cluster nodes starting from the next loop
+	return finishProcessing()
+}
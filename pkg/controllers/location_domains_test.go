@@ -0,0 +1,120 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_reconcileLocationDomains(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.Spec.LocationDomainTopologyKey = corev1.LabelTopologyZone
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+
+	// Create the config map
+	cm, err := f.k8sclient.CoreV1().ConfigMaps(ns).Create(ctx, resources.CreateConfigMap(ndb), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if err = f.k8sIf.Core().V1().ConfigMaps().Informer().GetIndexer().Add(cm); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	// Create the worker nodes in two zones and a worker node without a zone
+	for workerNodeName, zone := range map[string]string{
+		"worker-1": "zone-a",
+		"worker-2": "zone-b",
+		"worker-3": "",
+	} {
+		workerNode := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: workerNodeName},
+		}
+		if zone != "" {
+			workerNode.Labels = map[string]string{corev1.LabelTopologyZone: zone}
+		}
+		if err = f.k8sIf.Core().V1().Nodes().Informer().GetIndexer().Add(workerNode); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+
+	// Create the pods, with one of the Data node pods yet to be scheduled
+	// and a MySQL Server pod on the worker node without a zone
+	podIndexer := f.k8sIf.Core().V1().Pods().Informer().GetIndexer()
+	for podName, workerNodeName := range map[string]string{
+		"test-mgmd-0":   "worker-1",
+		"test-mgmd-1":   "worker-2",
+		"test-ndbmtd-0": "worker-2",
+		"test-ndbmtd-1": "",
+		"test-mysqld-0": "worker-3",
+	} {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: ns},
+			Spec:       corev1.PodSpec{NodeName: workerNodeName},
+		}
+		if err = podIndexer.Add(pod); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+
+	newStatefulSet := func(nodeType string) *appsv1.StatefulSet {
+		replicas := int32(2)
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: ndb.GetWorkloadName(nodeType), Namespace: ns},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		}
+	}
+
+	newSyncContext := func() *SyncContext {
+		sc := f.c.newSyncContext(ctx, ndb)
+		sc.mgmdNodeSfset = newStatefulSet(constants.NdbNodeTypeMgmd)
+		sc.dataNodeSfSet = newStatefulSet(constants.NdbNodeTypeNdbmtd)
+		sc.mysqldSfset = newStatefulSet(constants.NdbNodeTypeMySQLD)
+		if sc.configSummary, err = ndbconfig.NewConfigSummary(cm.Data); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+		return sc
+	}
+
+	// The config map should be updated with the location domains
+	sc := newSyncContext()
+	if sr := sc.reconcileLocationDomains(ctx); !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without any error, error : %v", sr.getError())
+	}
+	if cm, err = f.k8sclient.CoreV1().ConfigMaps(ns).Get(ctx, cm.Name, metav1.GetOptions{}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	// Only the data nodes are assigned to the location domains
+	expectedZones := `{"test-ndbmtd-0":"zone-b"}`
+	if cm.Data[constants.LocationDomainZones] != expectedZones {
+		t.Errorf("Expected the zones %s but got %s", expectedZones, cm.Data[constants.LocationDomainZones])
+	}
+	if count := strings.Count(cm.Data[constants.ConfigIniKey], "LocationDomainId="); count != 1 {
+		t.Errorf("Expected 1 node to have a LocationDomainId but %d have one :\n%s",
+			count, cm.Data[constants.ConfigIniKey])
+	}
+
+	// No further update is required once the config map has the location domains
+	sc = newSyncContext()
+	if sr := sc.reconcileLocationDomains(ctx); sr.stopSync() {
+		t.Fatalf("Expected the sync to continue, error : %v", sr.getError())
+	}
+}
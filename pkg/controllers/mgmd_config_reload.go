@@ -0,0 +1,76 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// configReloadRetryInterval is the interval after which the config
+// reload is retried, when the Management nodes have not yet received
+// the new config.ini from the config map volume mounted in their pods.
+const configReloadRetryInterval = 10 * time.Second
+
+// reloadManagementConfig applies the config.ini changes that do not
+// require a restart of the Management nodes, by reloading the config
+// in the running Management nodes. The other MySQL Cluster nodes are
+// restarted later in the sync to pick up the new config.
+func (sc *SyncContext) reloadManagementConfig(ctx context.Context) syncResult {
+	cs := sc.configSummary
+	if cs.MgmdRestartConfigVersion == cs.MySQLClusterConfigVersion {
+		// The Management nodes were (re)started with the latest config
+		return continueProcessing()
+	}
+
+	mgmClient, err := mgmapi.NewMgmClientWithContext(ctx, sc.ndb.GetConnectstring())
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+	defer mgmClient.Disconnect()
+
+	configVersion, err := mgmClient.GetConfigVersion()
+	if err != nil {
+		sc.logger.Error(err, "Failed to retrieve the config version of the Management node")
+		return errorWhileProcessing(err)
+	}
+
+	if configVersion == uint32(cs.MySQLClusterConfigVersion) {
+		// The config has already been reloaded
+		sc.mgmdConfigReloaded = true
+		return continueProcessing()
+	}
+
+	// Reload the config
+	sc.logger.Info("Reloading the config in the Management nodes",
+		"currentConfigVersion", configVersion, "configVersion", cs.MySQLClusterConfigVersion)
+	if err = mgmClient.ReloadConfig(); err != nil {
+		sc.logger.Error(err, "Failed to reload the config in the Management nodes")
+		return errorWhileProcessing(err)
+	}
+
+	if configVersion, err = mgmClient.GetConfigVersion(); err != nil {
+		sc.logger.Error(err, "Failed to retrieve the config version of the Management node")
+		return errorWhileProcessing(err)
+	}
+
+	if configVersion != uint32(cs.MySQLClusterConfigVersion) {
+		// The kubelet updates the config map volumes only periodically, and the
+		// Management node has reloaded the old config.ini. Retry later.
+		sc.logger.Info("The Management nodes have not received the new config yet",
+			"configVersion", configVersion, "retryAfter", configReloadRetryInterval)
+		sc.requeueAfter = configReloadRetryInterval
+		return finishProcessing()
+	}
+
+	sc.mgmdConfigReloaded = true
+	sc.recorder.Eventf(sc.ndb, nil, corev1.EventTypeNormal, ReasonMgmdConfigReloaded, ActionUpdated,
+		"Config version %d was applied to the Management nodes without restarting them", configVersion)
+	return continueProcessing()
+}
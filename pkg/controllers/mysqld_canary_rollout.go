@@ -0,0 +1,304 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+	"github.com/mysql/ndb-operator/pkg/resources"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// canaryGeneration is the annotation key which stores the NdbCluster
+	// generation being rolled out to the canary MySQL Server.
+	canaryGeneration = ndbcontroller.GroupName + "/canary-generation"
+	// canaryStartTime is the annotation key which stores the time at
+	// which the update was rolled out to the canary MySQL Server.
+	canaryStartTime = ndbcontroller.GroupName + "/canary-start-time"
+	// canaryFailed is the annotation key which is set when the canary
+	// MySQL Server failed and the update has been rolled back.
+	canaryFailed = ndbcontroller.GroupName + "/canary-failed"
+
+	// defaultCanaryTimeout is the time within which the canary MySQL Server
+	// should become ready, if no timeout is specified in the NdbCluster spec.
+	defaultCanaryTimeout = 10 * time.Minute
+	// canaryHealthCheckRetryInterval is the interval at which the health
+	// check of the canary MySQL Server is retried, when the canary could
+	// not be reached or the operator credentials could not be retrieved.
+	canaryHealthCheckRetryInterval = 10 * time.Second
+)
+
+// canaryRolloutState is the state of the canary rollout
+// of the config currently applied to a MySQL Server StatefulSet
+type canaryRolloutState int
+
+const (
+	// canaryRolloutNone implies that no canary rollout is in progress
+	canaryRolloutNone canaryRolloutState = iota
+	// canaryRolloutInProgress implies that the update has been rolled
+	// out only to the canary MySQL Server and is being verified
+	canaryRolloutInProgress
+	// canaryRolloutFailed implies that the canary MySQL Server failed
+	// and the update has been rolled back
+	canaryRolloutFailed
+)
+
+// getCanaryRolloutState returns the state of the canary
+// rollout of the config applied to the given StatefulSet.
+func getCanaryRolloutState(sfset *appsv1.StatefulSet) canaryRolloutState {
+	annotations := sfset.GetAnnotations()
+	generation, exists := annotations[canaryGeneration]
+	if !exists || generation != annotations[statefulset.LastAppliedConfigGeneration] {
+		// No canary rollout was started for the applied config
+		return canaryRolloutNone
+	}
+
+	if annotations[canaryFailed] == "true" {
+		return canaryRolloutFailed
+	}
+
+	return canaryRolloutInProgress
+}
+
+// getCanaryOrdinal returns the ordinal index of the canary MySQL Server.
+// The StatefulSet controller rolls out the update to the pods in the
+// reverse ordinal order, so the last pod is used as the canary.
+func getCanaryOrdinal(sfset *appsv1.StatefulSet) int32 {
+	return *(sfset.Spec.Replicas) - 1
+}
+
+// setRollingUpdatePartition sets the partition of the RollingUpdate
+// strategy of the given StatefulSet. Only the pods with an ordinal
+// greater than or equal to the partition are updated.
+func setRollingUpdatePartition(sfset *appsv1.StatefulSet, partition int32) {
+	sfset.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+		Type: appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+			Partition: &partition,
+		},
+	}
+}
+
+// startCanaryRollout updates the given new MySQL Server StatefulSet
+// to roll out the update only to the canary MySQL Server.
+func startCanaryRollout(updatedSfset *appsv1.StatefulSet, generation int64) {
+	setRollingUpdatePartition(updatedSfset, getCanaryOrdinal(updatedSfset))
+
+	annotations := updatedSfset.GetAnnotations()
+	annotations[canaryGeneration] = strconv.FormatInt(generation, 10)
+	annotations[canaryStartTime] = time.Now().UTC().Format(time.RFC3339)
+}
+
+// reconcileCanaryRollout verifies the canary MySQL Server, if a canary
+// rollout is in progress. If the canary is healthy, the update is rolled
+// out to the rest of the MySQL Servers. If the canary doesn't become ready
+// within the timeout or fails the health check, the update is rolled back.
+// The health check is retried until the timeout if it could not be run.
+func (mssc *mysqldStatefulSetController) reconcileCanaryRollout(ctx context.Context, sc *SyncContext) syncResult {
+	mysqldSfset := sc.mysqldSfset
+	nc := sc.ndb
+
+	switch getCanaryRolloutState(mysqldSfset) {
+	case canaryRolloutNone:
+		// No canary rollout in progress
+		return continueProcessing()
+	case canaryRolloutFailed:
+		// The update was rolled back. Report the failure until the spec is updated.
+		sc.workloadErrors = append(sc.workloadErrors, fmt.Sprintf(
+			"update of the MySQL Servers to NdbCluster generation %s was rolled back as the canary MySQL Server failed",
+			mysqldSfset.GetAnnotations()[canaryGeneration]))
+		return finishProcessing()
+	}
+
+	canaryRollout := nc.Spec.MysqlNode.CanaryRollout
+	if canaryRollout == nil {
+		// The canary rollout was disabled midway.
+		// Roll out the update to all the MySQL Servers.
+		sc.logger.Info("Canary rollout is disabled, rolling out the update to all MySQL Servers")
+		return mssc.completeCanaryRollout(ctx, sc)
+	}
+
+	// Check if the canary MySQL Server is running the updated pod definition and is ready
+	canaryOrdinal := getCanaryOrdinal(mysqldSfset)
+	canaryPodName := fmt.Sprintf("%s-%d", mysqldSfset.Name, canaryOrdinal)
+	canaryPod, err := sc.podLister.Pods(mysqldSfset.Namespace).Get(canaryPodName)
+	if err != nil && !errors.IsNotFound(err) {
+		sc.logger.Error(err, "Failed to retrieve the canary MySQL Server pod", "pod", canaryPodName)
+		return errorWhileProcessing(err)
+	}
+
+	canaryReady := false
+	if canaryPod != nil &&
+		canaryPod.GetLabels()["controller-revision-hash"] == mysqldSfset.Status.UpdateRevision {
+		readyCondition := getPodCondition(canaryPod, corev1.PodReady)
+		canaryReady = readyCondition != nil && readyCondition.Status == corev1.ConditionTrue
+	}
+
+	// The canary has to become ready and pass the health check within the timeout
+	timeout := defaultCanaryTimeout
+	if canaryRollout.TimeoutSeconds != 0 {
+		timeout = time.Duration(canaryRollout.TimeoutSeconds) * time.Second
+	}
+
+	startTime, err := time.Parse(time.RFC3339, mysqldSfset.GetAnnotations()[canaryStartTime])
+	if err != nil {
+		// Annotation missing or modified - start the timer now
+		startTime = time.Now()
+	}
+	remaining := timeout - time.Since(startTime)
+
+	if !canaryReady {
+		if remaining > 0 {
+			// Wait for the canary to become ready. The pod updates will
+			// trigger the next sync, but requeue anyway to enforce the timeout.
+			sc.logger.Info("Waiting for the canary MySQL Server to become ready", "pod", canaryPodName)
+			sc.requeueAfter = remaining
+			return finishProcessing()
+		}
+
+		return mssc.rollbackCanaryRollout(ctx, sc,
+			fmt.Errorf("canary MySQL Server %q did not become ready within %s", canaryPodName, timeout))
+	}
+
+	// Canary is ready - run the health check
+	secretClient := NewMySQLUserPasswordSecretInterface(sc.kubeClientset())
+	operatorSecretName := resources.GetMySQLNDBOperatorPasswordSecretName(nc)
+	operatorPassword, err := secretClient.ExtractPassword(ctx, mysqldSfset.Namespace, operatorSecretName)
+	if err == nil {
+		err = mysqlclient.RunHealthCheck(
+			ctx, mysqldSfset, canaryOrdinal, canaryRollout.HealthCheckQueries, operatorPassword)
+	}
+
+	if err != nil {
+		if mysqlclient.IsHealthCheckFailure(err) {
+			return mssc.rollbackCanaryRollout(ctx, sc,
+				fmt.Errorf("canary MySQL Server %q failed the health check : %s", canaryPodName, err))
+		}
+
+		// The health check could not be run, e.g. the canary is not
+		// reachable yet or the operator password is not available.
+		// These are not failures of the canary - retry until the timeout.
+		if remaining > 0 {
+			sc.logger.Info("Failed to run the health check of the canary MySQL Server, will retry",
+				"pod", canaryPodName, "error", err.Error())
+			sc.requeueAfter = canaryHealthCheckRetryInterval
+			if remaining < canaryHealthCheckRetryInterval {
+				sc.requeueAfter = remaining
+			}
+			return finishProcessing()
+		}
+
+		return mssc.rollbackCanaryRollout(ctx, sc,
+			fmt.Errorf("canary MySQL Server %q could not be health checked within %s : %s", canaryPodName, timeout, err))
+	}
+
+	// Canary is healthy
+	sc.logger.Info("Canary MySQL Server passed the health check", "pod", canaryPodName)
+	return mssc.completeCanaryRollout(ctx, sc)
+}
+
+// completeCanaryRollout removes the partition from the MySQL Server
+// StatefulSet to roll out the update to all the MySQL Servers.
+func (mssc *mysqldStatefulSetController) completeCanaryRollout(ctx context.Context, sc *SyncContext) syncResult {
+	mysqldSfset := sc.mysqldSfset
+	updatedSfset := mysqldSfset.DeepCopy()
+	updatedSfset.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+		Type: appsv1.RollingUpdateStatefulSetStrategyType,
+	}
+	annotations := updatedSfset.GetAnnotations()
+	delete(annotations, canaryGeneration)
+	delete(annotations, canaryStartTime)
+
+	sr := mssc.patchStatefulSet(ctx, mysqldSfset, updatedSfset)
+	if sr.getError() == nil {
+		sc.recorder.Eventf(sc.ndb, nil, corev1.EventTypeNormal, ReasonMySQLCanarySucceeded, ActionUpdated,
+			"Canary MySQL Server is healthy, rolling out generation %s of the spec to all MySQL Servers",
+			mysqldSfset.GetAnnotations()[canaryGeneration])
+	}
+	return sr
+}
+
+// getRevisionPodTemplate returns the pod template
+// recorded in the ControllerRevision with the given name.
+func getRevisionPodTemplate(ctx context.Context,
+	client kubernetes.Interface, namespace, revisionName string) (*corev1.PodTemplateSpec, error) {
+	revision, err := client.AppsV1().ControllerRevisions(namespace).Get(ctx, revisionName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// The StatefulSet controller records the pod
+	// template as a patch of the StatefulSet spec
+	var sfsetPatch struct {
+		Spec struct {
+			Template corev1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}
+	if err = json.Unmarshal(revision.Data.Raw, &sfsetPatch); err != nil {
+		return nil, fmt.Errorf("failed to decode the ControllerRevision %q : %s", revisionName, err)
+	}
+
+	return &sfsetPatch.Spec.Template, nil
+}
+
+// rollbackCanaryRollout stops the update from being rolled out to any
+// more MySQL Servers and restores the canary MySQL Server to the
+// previous pod definition.
+func (mssc *mysqldStatefulSetController) rollbackCanaryRollout(
+	ctx context.Context, sc *SyncContext, cause error) syncResult {
+	mysqldSfset := sc.mysqldSfset
+	sc.logger.Error(cause, "Rolling back the update of the MySQL Servers")
+
+	// Restore the pod template of the current revision, which resets the
+	// update revision of the StatefulSet to it. The pods created by any
+	// later scale-up will then not get the failed update. Mark the canary
+	// rollout as failed.
+	template, err := getRevisionPodTemplate(
+		ctx, sc.kubeClientset(), mysqldSfset.Namespace, mysqldSfset.Status.CurrentRevision)
+	if err != nil {
+		sc.logger.Error(err, "Failed to retrieve the pod template of the current revision",
+			"revision", mysqldSfset.Status.CurrentRevision)
+		return errorWhileProcessing(err)
+	}
+	updatedSfset := mysqldSfset.DeepCopy()
+	updatedSfset.Spec.Template = *template
+	updatedSfset.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+		Type: appsv1.RollingUpdateStatefulSetStrategyType,
+	}
+	updatedSfset.GetAnnotations()[canaryFailed] = "true"
+	if sr := mssc.patchStatefulSet(ctx, mysqldSfset, updatedSfset); sr.getError() != nil {
+		return sr
+	}
+
+	// Delete the canary pod. The StatefulSet controller
+	// will restore it with the previous pod definition.
+	canaryPodName := fmt.Sprintf("%s-%d", mysqldSfset.Name, getCanaryOrdinal(mysqldSfset))
+	err = sc.kubeClientset().CoreV1().Pods(mysqldSfset.Namespace).Delete(ctx, canaryPodName, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		sc.logger.Error(err, "Failed to delete the canary MySQL Server pod", "pod", canaryPodName)
+		return errorWhileProcessing(err)
+	}
+
+	sc.recorder.Eventf(sc.ndb, nil, corev1.EventTypeWarning, ReasonMySQLCanaryFailed, ActionRollback,
+		"Update of the MySQL Servers was rolled back : %s", cause)
+	sc.workloadErrors = append(sc.workloadErrors, cause.Error())
+
+	// Stop processing. The failure will be reported
+	// until the NdbCluster spec is updated.
+	return finishProcessing()
+}
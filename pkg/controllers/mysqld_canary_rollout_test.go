@@ -0,0 +1,146 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func Test_canaryRollout(t *testing.T) {
+	replicas := int32(3)
+	sfset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-mysqld",
+			Annotations: map[string]string{
+				statefulset.LastAppliedConfigGeneration: "2",
+			},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+		},
+		Status: appsv1.StatefulSetStatus{
+			Replicas:        3,
+			ReadyReplicas:   3,
+			CurrentReplicas: 2,
+			UpdatedReplicas: 1,
+		},
+	}
+
+	if state := getCanaryRolloutState(sfset); state != canaryRolloutNone {
+		t.Errorf("Expected no canary rollout but got state %d", state)
+	}
+
+	// Start the canary rollout
+	startCanaryRollout(sfset, 2)
+	rollingUpdate := sfset.Spec.UpdateStrategy.RollingUpdate
+	if rollingUpdate == nil || rollingUpdate.Partition == nil || *rollingUpdate.Partition != 2 {
+		t.Fatalf("Expected the update to be rolled out only to the last pod : %#v", sfset.Spec.UpdateStrategy)
+	}
+	if state := getCanaryRolloutState(sfset); state != canaryRolloutInProgress {
+		t.Errorf("Expected canary rollout to be in progress but got state %d", state)
+	}
+
+	// The rollout is tracked by the sync, so the StatefulSet
+	// is settled even though the update is incomplete
+	if !statefulsetSettled(sfset) {
+		t.Error("StatefulSet with a canary rollout in progress is not settled")
+	}
+
+	// Mark the canary as failed
+	sfset.Annotations[canaryFailed] = "true"
+	if state := getCanaryRolloutState(sfset); state != canaryRolloutFailed {
+		t.Errorf("Expected canary rollout to have failed but got state %d", state)
+	}
+
+	// A canary of an older generation should be ignored. The new
+	// generation is applied with the update strategy from the spec.
+	sfset.Annotations[statefulset.LastAppliedConfigGeneration] = "3"
+	sfset.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{}
+	if state := getCanaryRolloutState(sfset); state != canaryRolloutNone {
+		t.Errorf("Expected the canary of an older generation to be ignored but got state %d", state)
+	}
+	if statefulsetSettled(sfset) {
+		t.Error("StatefulSet with a pending RollingUpdate is settled")
+	}
+}
+
+func Test_rollbackCanaryRollout(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	// The current revision runs the previous image
+	ctx := context.Background()
+	revision := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-mysqld-old", Namespace: ns},
+		Data: runtime.RawExtension{
+			Raw: []byte(`{"spec":{"template":{"spec":{"containers":[{"name":"mysqld","image":"mysql:old"}]},"$patch":"replace"}}}`),
+		},
+	}
+	if _, err := f.k8sclient.AppsV1().ControllerRevisions(ns).Create(ctx, revision, metav1.CreateOptions{}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	// The failed update runs a new image on the canary
+	replicas := int32(3)
+	sfset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ndb.GetWorkloadName(constants.NdbNodeTypeMySQLD),
+			Namespace: ns,
+			Annotations: map[string]string{
+				statefulset.LastAppliedConfigGeneration: "2",
+			},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "mysqld", Image: "mysql:new"}},
+				},
+			},
+		},
+		Status: appsv1.StatefulSetStatus{
+			CurrentRevision: "test-mysqld-old",
+			UpdateRevision:  "test-mysqld-new",
+		},
+	}
+	startCanaryRollout(sfset, 2)
+
+	sc := f.c.newSyncContext(ctx, ndb)
+	sc.mysqldSfset = sfset
+	if sr := sc.mysqldController.rollbackCanaryRollout(ctx, sc, errors.New("canary failed")); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+
+	// The StatefulSet should be reset to the pod template of the current revision,
+	// so that the pods added by a scale-up do not get the failed update
+	updatedSfset, err := f.k8sclient.AppsV1().StatefulSets(ns).Get(ctx, sfset.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if image := updatedSfset.Spec.Template.Spec.Containers[0].Image; image != "mysql:old" {
+		t.Errorf("Expected the pod template of the current revision but got the image %q", image)
+	}
+	if updatedSfset.Spec.UpdateStrategy.RollingUpdate != nil {
+		t.Errorf("Expected the partition to be removed : %#v", updatedSfset.Spec.UpdateStrategy)
+	}
+	if state := getCanaryRolloutState(updatedSfset); state != canaryRolloutFailed {
+		t.Errorf("Expected canary rollout to have failed but got state %d", state)
+	}
+}
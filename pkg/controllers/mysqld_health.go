@@ -0,0 +1,130 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// mysqldHealthRecheckInterval is the interval at which the MySQL
+// Servers are checked again when some of them cannot reach the NDB engine.
+const mysqldHealthRecheckInterval = 30 * time.Second
+
+// checkMySQLServersHealth verifies that every ready MySQL Server can reach
+// the NDB engine, and records the number of connected MySQL Servers to be
+// reported in the NdbCluster status. If any of the MySQL Servers running the
+// current config cannot reach the NDB engine, the sync is stopped to prevent
+// rolling out further changes to the MySQL Cluster until they recover. If
+// the MySQL Servers reject the ndb operator user, it is recovered first.
+func (sc *SyncContext) checkMySQLServersHealth(ctx context.Context) syncResult {
+	connected := int32(0)
+	sc.connectedMySQLServers = &connected
+
+	mysqldSfset := sc.mysqldSfset
+	if mysqldSfset == nil || *mysqldSfset.Spec.Replicas == 0 {
+		// No MySQL Servers to check
+		return continueProcessing()
+	}
+
+	// Retrieve the ndb operator password accepted by the MySQL Servers
+	// before the checks replace the cached connections, as it is required
+	// to apply any change made to the password in the Secret.
+	acceptedPassword, passwordAccepted := mysqlclient.GetAcceptedPassword(mysqldSfset)
+
+	var operatorSecret *corev1.Secret
+	var operatorUserRejected bool
+	var unhealthyServers []string
+	for ordinal := int32(0); ordinal < *mysqldSfset.Spec.Replicas; ordinal++ {
+		podName := fmt.Sprintf("%s-%d", mysqldSfset.Name, ordinal)
+		pod, err := sc.podLister.Pods(mysqldSfset.Namespace).Get(podName)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// Pod doesn't exist yet
+				continue
+			}
+			sc.logger.Error(err, "Failed to retrieve the MySQL Server pod", "pod", podName)
+			return errorWhileProcessing(err)
+		}
+
+		if readyCondition := getPodCondition(pod, corev1.PodReady); readyCondition == nil ||
+			readyCondition.Status != corev1.ConditionTrue {
+			// Pod is not ready yet. Its readiness is reported via the pod errors.
+			continue
+		}
+
+		if operatorSecret == nil {
+			// Retrieve the secret holding the ndb operator mysql user password
+			operatorSecretName := resources.GetMySQLNDBOperatorPasswordSecretName(sc.ndb)
+			if operatorSecret, err = sc.kubeClientset().CoreV1().Secrets(mysqldSfset.Namespace).Get(
+				ctx, operatorSecretName, metav1.GetOptions{}); err != nil {
+				sc.logger.Error(err, "Failed to retrieve the ndb operator password secret")
+				return errorWhileProcessing(err)
+			}
+		}
+
+		operatorPassword := string(operatorSecret.Data[corev1.BasicAuthPasswordKey])
+		if err = mysqlclient.CheckNdbEngineConnectivity(ctx, mysqldSfset, ordinal, operatorPassword); err != nil {
+			if mysqlclient.IsAccessDeniedError(err) {
+				// The ndb operator user has been rejected by the MySQL Server
+				sc.logger.Info("MySQL Server rejected the ndb operator user", "pod", podName, "error", err.Error())
+				operatorUserRejected = true
+				continue
+			}
+			sc.logger.Info("MySQL Server cannot reach the NDB engine", "pod", podName, "error", err.Error())
+			unhealthyServers = append(unhealthyServers,
+				fmt.Sprintf("MySQL Server %q cannot reach the NDB engine : %s", podName, err))
+			continue
+		}
+
+		connected++
+	}
+
+	if operatorUserRejected {
+		if operatorPassword := string(operatorSecret.Data[corev1.BasicAuthPasswordKey]); passwordAccepted &&
+			acceptedPassword != operatorPassword {
+			// The password has been changed in the Secret. Apply
+			// it to the ndb operator user via the previous password.
+			sr := sc.updateOperatorPassword(ctx, operatorSecret, acceptedPassword)
+			if err := sr.getError(); err == nil || !mysqlclient.IsAccessDeniedError(err) {
+				return sr
+			}
+			// The previous password is not accepted either
+		}
+
+		// The ndb operator user has been lost or overwritten, possibly
+		// by a restore. Recover it before running any further queries.
+		return sc.recoverOperatorUser(ctx, operatorSecret)
+	}
+
+	if connected > 0 {
+		if _, requested := operatorSecret.Data[resources.NDBOperatorUserRecoveryKey]; requested {
+			// The recovered ndb operator user has been accepted by the MySQL Servers
+			if err := sc.completeOperatorUserRecovery(ctx, operatorSecret); err != nil {
+				return errorWhileProcessing(err)
+			}
+		}
+	}
+
+	if len(unhealthyServers) == 0 ||
+		!workloadHasConfigGeneration(mysqldSfset, sc.configSummary.NdbClusterGeneration) {
+		// Either all the MySQL Servers are healthy or they are yet to be updated
+		// with the current config, which might fix the unhealthy MySQL Servers.
+		return continueProcessing()
+	}
+
+	// Report the unhealthy MySQL Servers and check them again after a while
+	sc.workloadErrors = append(sc.workloadErrors, unhealthyServers...)
+	sc.requeueAfter = mysqldHealthRecheckInterval
+	return finishProcessing()
+}
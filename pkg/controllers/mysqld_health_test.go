@@ -0,0 +1,58 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_checkMySQLServersHealth(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.Status.ConnectedMySQLServers = "Connected:1/2"
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+
+	// The previous value should be retained if the health was not checked
+	sc := f.c.newSyncContext(ctx, ndb)
+	if status := sc.calculateNdbClusterStatus(); status.ConnectedMySQLServers != "Connected:1/2" {
+		t.Errorf("Connected MySQL Servers not retained : %q", status.ConnectedMySQLServers)
+	}
+
+	// MySQL Servers whose pods do not exist yet are not checked
+	replicas := int32(2)
+	sc.mysqldSfset = &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ndb.GetWorkloadName(constants.NdbNodeTypeMySQLD),
+			Namespace: ns,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+		},
+	}
+	if sr := sc.checkMySQLServersHealth(ctx); sr.stopSync() {
+		t.Fatalf("Sync stopped when no MySQL Server was checked, error : %v", sr.getError())
+	}
+	if len(sc.workloadErrors) != 0 {
+		t.Errorf("Unexpected workload errors : %v", sc.workloadErrors)
+	}
+
+	expected := fmt.Sprintf("Connected:0/%d", ndb.GetMySQLServerNodeCount())
+	if status := sc.calculateNdbClusterStatus(); status.ConnectedMySQLServers != expected {
+		t.Errorf("Expected connected MySQL Servers %q but got %q", expected, status.ConnectedMySQLServers)
+	}
+}
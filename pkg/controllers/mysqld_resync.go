@@ -0,0 +1,166 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// getOperatorSecret retrieves the secret holding the ndb operator mysql user password
+func (sc *SyncContext) getOperatorSecret(ctx context.Context) (*corev1.Secret, error) {
+	operatorSecretName := resources.GetMySQLNDBOperatorPasswordSecretName(sc.ndb)
+	operatorSecret, err := sc.kubeClientset().CoreV1().Secrets(sc.ndb.Namespace).Get(
+		ctx, operatorSecretName, metav1.GetOptions{})
+	if err != nil {
+		sc.logger.Error(err, "Failed to retrieve the ndb operator password secret")
+		return nil, err
+	}
+	return operatorSecret, nil
+}
+
+// requestMySQLServersResync requests all the MySQL Servers to be restarted
+// once the data nodes have completed an initial system restart, which wipes
+// the schemas and the users stored in the MySQL Cluster. The recovery of the
+// ndb operator user is requested as well, so that the first MySQL Server
+// recreates it when it is restarted.
+func (sc *SyncContext) requestMySQLServersResync(ctx context.Context) error {
+	operatorSecret, err := sc.getOperatorSecret(ctx)
+	if err != nil {
+		return err
+	}
+
+	requestTime := []byte(time.Now().Truncate(time.Second).Format(time.RFC3339))
+	data := map[string]interface{}{
+		resources.MySQLServersResyncKey: requestTime,
+	}
+	if _, requested := operatorSecret.Data[resources.NDBOperatorUserRecoveryKey]; !requested &&
+		sc.mysqldSfset != nil && *sc.mysqldSfset.Spec.Replicas > 0 {
+		data[resources.NDBOperatorUserRecoveryKey] = requestTime
+	}
+
+	if err = sc.patchOperatorSecretData(ctx, operatorSecret, data); err != nil {
+		return err
+	}
+
+	sc.logger.Info("Requested the MySQL Servers to be resynced after the initial system restart")
+	return nil
+}
+
+// resyncMySQLServers restarts all the MySQL Servers, if requested by an
+// initial system restart of the data nodes, so that they resync with the
+// recreated MySQL Cluster. The ndb operator user is recreated by the first
+// MySQL Server when it restarts, and the root user is recreated by a later
+// sync, once the resync is complete.
+func (sc *SyncContext) resyncMySQLServers(ctx context.Context) syncResult {
+	operatorSecret, err := sc.getOperatorSecret(ctx)
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	requestedAt, requested := operatorSecret.Data[resources.MySQLServersResyncKey]
+	if !requested {
+		// Nothing to do
+		return continueProcessing()
+	}
+
+	if sc.dataNodeSfSet == nil || !statefulsetUpdateComplete(sc.dataNodeSfSet) {
+		// The data nodes are yet to be restarted
+		return continueProcessing()
+	}
+
+	requestTime, err := time.Parse(time.RFC3339, string(requestedAt))
+	if err != nil {
+		sc.logger.Error(err, "Failed to parse the MySQL Servers resync request time")
+		return errorWhileProcessing(err)
+	}
+
+	// Restart the MySQL Servers started before the request
+	var mysqldSfsets []*appsv1.StatefulSet
+	if sc.mysqldSfset != nil {
+		mysqldSfsets = append(mysqldSfsets, sc.mysqldSfset)
+	}
+	for _, serverGroupSfset := range sc.mysqldServerGroupSfsets {
+		mysqldSfsets = append(mysqldSfsets, serverGroupSfset)
+	}
+
+	var restartedPods []string
+	restarting := false
+	for _, sfset := range mysqldSfsets {
+		for ordinal := int32(0); ordinal < *sfset.Spec.Replicas; ordinal++ {
+			podName := fmt.Sprintf("%s-%d", sfset.Name, ordinal)
+			pod, err := sc.podLister.Pods(sfset.Namespace).Get(podName)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					// Pod is being recreated
+					restarting = true
+					continue
+				}
+				sc.logger.Error(err, "Failed to retrieve the MySQL Server pod", "pod", podName)
+				return errorWhileProcessing(err)
+			}
+
+			if !pod.CreationTimestamp.Time.Before(requestTime) {
+				// Pod has been restarted since the request
+				continue
+			}
+
+			restarting = true
+			if pod.DeletionTimestamp == nil {
+				if err = sc.kubeClientset().CoreV1().Pods(pod.Namespace).Delete(
+					ctx, podName, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+					sc.logger.Error(err, "Failed to delete the MySQL Server pod", "pod", podName)
+					return errorWhileProcessing(err)
+				}
+				restartedPods = append(restartedPods, podName)
+			}
+		}
+	}
+
+	if len(restartedPods) != 0 {
+		sc.logger.Info("Restarting the MySQL Servers to resync them with the MySQL Cluster", "pods", restartedPods)
+		sc.recorder.Eventf(sc.ndb, nil, corev1.EventTypeNormal, ReasonMySQLServersResyncing, ActionRestart,
+			"MySQL Servers are being restarted to resync them with the MySQL Cluster recreated by the initial system restart")
+	}
+
+	if restarting {
+		// Reconciliation will continue once the MySQL Servers are ready again
+		return finishProcessing()
+	}
+
+	if mysqldSfset := sc.mysqldSfset; mysqldSfset != nil {
+		if _, exists := mysqldSfset.GetAnnotations()[rootHost]; exists {
+			// The root user was lost along with the MySQL Cluster.
+			// Remove its annotations to recreate it in a later sync.
+			updatedMysqldSfset := mysqldSfset.DeepCopy()
+			for _, annotation := range []string{
+				rootHost, rootAuthenticationPlugin, rootUserGeneration, rootPasswordSecretVersion} {
+				delete(updatedMysqldSfset.Annotations, annotation)
+			}
+			if sr := sc.mysqldController.patchStatefulSet(ctx, mysqldSfset, updatedMysqldSfset); sr.getError() != nil {
+				return sr
+			}
+			// Continue once the StatefulSet update is observed
+			return finishProcessing()
+		}
+	}
+
+	// All the MySQL Servers have been resynced
+	if err = sc.patchOperatorSecretData(ctx, operatorSecret, map[string]interface{}{
+		resources.MySQLServersResyncKey: nil,
+	}); err != nil {
+		return errorWhileProcessing(err)
+	}
+	sc.logger.Info("The MySQL Servers have been resynced with the MySQL Cluster")
+	return continueProcessing()
+}
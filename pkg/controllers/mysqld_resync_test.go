@@ -0,0 +1,139 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_resyncMySQLServers(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+	secretInterface := f.k8sclient.CoreV1().Secrets(ns)
+	podInterface := f.k8sclient.CoreV1().Pods(ns)
+	podIndexer := f.k8sIf.Core().V1().Pods().Informer().GetIndexer()
+
+	operatorSecret, err := secretInterface.Create(ctx, resources.NewMySQLNDBOperatorPasswordSecret(ndb), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	// createPod creates the first MySQL Server pod with the given creation time
+	createPod := func(creationTime time.Time) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "test-mysqld-0",
+				Namespace:         ns,
+				CreationTimestamp: metav1.NewTime(creationTime),
+			},
+		}
+		if _, err = podInterface.Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+		if err = podIndexer.Add(pod); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+	createPod(time.Now().Add(-time.Hour))
+
+	mysqldReplicas := int32(1)
+	dataNodeReplicas := int32(2)
+	newSyncContext := func() *SyncContext {
+		sc := f.c.newSyncContext(ctx, ndb)
+		sc.mysqldSfset = &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-mysqld", Namespace: ns},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &mysqldReplicas},
+		}
+		sc.dataNodeSfSet = &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ndbmtd", Namespace: ns},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: &dataNodeReplicas,
+				UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+					Type: appsv1.OnDeleteStatefulSetStrategyType,
+				},
+			},
+			Status: appsv1.StatefulSetStatus{
+				Replicas:        dataNodeReplicas,
+				ReadyReplicas:   dataNodeReplicas,
+				UpdatedReplicas: dataNodeReplicas,
+			},
+		}
+		return sc
+	}
+
+	// Nothing should be restarted without a request
+	sc := newSyncContext()
+	if sr := sc.resyncMySQLServers(ctx); sr.stopSync() {
+		t.Fatalf("Sync stopped without a resync request, error : %v", sr.getError())
+	}
+	if _, err = podInterface.Get(ctx, "test-mysqld-0", metav1.GetOptions{}); err != nil {
+		t.Fatal("MySQL Server pod restarted without a resync request :", err)
+	}
+
+	// The initial system restart should request the resync and the operator user recovery
+	if err = sc.requestMySQLServersResync(ctx); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if operatorSecret, err = secretInterface.Get(ctx, operatorSecret.Name, metav1.GetOptions{}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	for _, key := range []string{resources.MySQLServersResyncKey, resources.NDBOperatorUserRecoveryKey} {
+		if _, requested := operatorSecret.Data[key]; !requested {
+			t.Fatalf("%q was not requested", key)
+		}
+	}
+
+	// The MySQL Servers started before the request should be restarted
+	// once the data nodes have restarted. Backdate the request to ensure
+	// that the MySQL Server pod created below is newer than the request.
+	requestTime := time.Now().Add(-time.Minute).Truncate(time.Second).Format(time.RFC3339)
+	if err = sc.patchOperatorSecretData(ctx, operatorSecret, map[string]interface{}{
+		resources.MySQLServersResyncKey: []byte(requestTime),
+	}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	sc = newSyncContext()
+	if sr := sc.resyncMySQLServers(ctx); !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error, error : %v", sr.getError())
+	}
+	if _, err = podInterface.Get(ctx, "test-mysqld-0", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Fatal("MySQL Server pod was not restarted :", err)
+	}
+
+	// The resync request should be removed once all the MySQL Servers have restarted
+	if err = podIndexer.Delete(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-mysqld-0", Namespace: ns}}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	createPod(time.Now())
+	sc = newSyncContext()
+	if sr := sc.resyncMySQLServers(ctx); sr.stopSync() {
+		t.Fatalf("Sync stopped after the MySQL Servers were resynced, error : %v", sr.getError())
+	}
+	if operatorSecret, err = secretInterface.Get(ctx, operatorSecret.Name, metav1.GetOptions{}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if _, requested := operatorSecret.Data[resources.MySQLServersResyncKey]; requested {
+		t.Error("Resync request was not removed from the secret")
+	}
+	if _, requested := operatorSecret.Data[resources.NDBOperatorUserRecoveryKey]; !requested {
+		t.Error("Recovery request of the ndb operator user was removed before the user was recovered")
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes"
+	listersappsv1 "k8s.io/client-go/listers/apps/v1"
+	listerscorev1 "k8s.io/client-go/listers/core/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// mysqldServerGroupController manages the StatefulSets
+// of the MySQL Server groups specified in the NdbCluster
+type mysqldServerGroupController struct {
+	client            kubernetes.Interface
+	statefulSetLister listersappsv1.StatefulSetLister
+	configMapLister   listerscorev1.ConfigMapLister
+}
+
+// newMySQLDServerGroupController creates a new mysqldServerGroupController
+func newMySQLDServerGroupController(
+	client kubernetes.Interface,
+	statefulSetLister listersappsv1.StatefulSetLister,
+	configMapLister listerscorev1.ConfigMapLister) *mysqldServerGroupController {
+	return &mysqldServerGroupController{
+		client:            client,
+		statefulSetLister: statefulSetLister,
+		configMapLister:   configMapLister,
+	}
+}
+
+// getStatefulSetController returns the ndbNodeStatefulSetImpl
+// that controls the StatefulSet of the given MySQL Server group
+func (msgc *mysqldServerGroupController) getStatefulSetController(serverGroup string) *ndbNodeStatefulSetImpl {
+	return &ndbNodeStatefulSetImpl{
+		client:             msgc.client,
+		statefulSetLister:  msgc.statefulSetLister,
+		ndbNodeStatefulset: statefulset.NewMySQLdServerGroupStatefulSet(msgc.configMapLister, serverGroup),
+	}
+}
+
+// GetStatefulSets retrieves the StatefulSets of all the MySQL Server
+// groups owned by the NdbCluster, mapped by the name of their group.
+func (msgc *mysqldServerGroupController) GetStatefulSets(
+	ctx context.Context, sc *SyncContext) (map[string]*appsv1.StatefulSet, error) {
+	nc := sc.ndb
+
+	// List the StatefulSets that have the MySQL Server group label
+	serverGroupRequirement, err := labels.NewRequirement(
+		constants.MySQLServerGroupLabel, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	selector := labels.SelectorFromSet(nc.GetLabels()).Add(*serverGroupRequirement)
+	sfsets, err := msgc.statefulSetLister.StatefulSets(nc.Namespace).List(selector)
+	if err != nil {
+		klog.Errorf("Failed to list the MySQL Server group StatefulSets of NdbCluster %q : %s",
+			getNamespacedName(nc), err)
+		return nil, err
+	}
+
+	serverGroupSfsets := make(map[string]*appsv1.StatefulSet, len(sfsets))
+	for _, sfset := range sfsets {
+		// Verify ownership
+		if err = sc.ensureOwnedByNdbCluster(ctx, sfset); err != nil {
+			return nil, err
+		}
+		serverGroupSfsets[sfset.Labels[constants.MySQLServerGroupLabel]] = sfset
+	}
+
+	return serverGroupSfsets, nil
+}
+
+// sortedServerGroups returns the names of the MySQL Server
+// groups in the given StatefulSet map in a sorted order.
+func sortedServerGroups(serverGroupSfsets map[string]*appsv1.StatefulSet) []string {
+	serverGroups := make([]string, 0, len(serverGroupSfsets))
+	for serverGroup := range serverGroupSfsets {
+		serverGroups = append(serverGroups, serverGroup)
+	}
+	sort.Strings(serverGroups)
+	return serverGroups
+}
+
+// HandleScaleDown scales down or deletes the StatefulSets of the MySQL
+// Server groups whose node count has been reduced or who have been removed
+// from the NdbCluster spec. Like the mysqldStatefulSetController.HandleScaleDown,
+// this method is called before the new config is applied to the management
+// and data nodes, so that the MySQL Servers are shutdown before their
+// [mysqld] sections are removed from the config.
+func (msgc *mysqldServerGroupController) HandleScaleDown(ctx context.Context, sc *SyncContext) syncResult {
+	nc := sc.ndb
+	cs := sc.configSummary
+
+	for _, serverGroupName := range sortedServerGroups(sc.mysqldServerGroupSfsets) {
+		sfset := sc.mysqldServerGroupSfsets[serverGroupName]
+		if !sc.isStatefulsetUpdated(sfset, cs.NdbClusterGeneration, Complete) {
+			// Previous StatefulSet update is not complete yet.
+			// Finish processing. Reconciliation will continue
+			// once the StatefulSet update has been rolled out.
+			return finishProcessing()
+		}
+
+		serverGroup := cs.GetMySQLServerGroup(serverGroupName)
+		if serverGroup == nil {
+			// The group has been removed from the spec - delete the StatefulSet
+			if err := msgc.getStatefulSetController(serverGroupName).deleteStatefulSet(ctx, sfset, sc); err != nil {
+				return errorWhileProcessing(err)
+			}
+			mysqlclient.CloseConnections(sfset.Namespace, sfset.Name)
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonMySQLScaleDown, ActionScaleDown,
+				"MySQL Server group %q is being removed", serverGroupName)
+
+			// reconciliation will continue once the statefulset has been deleted
+			return finishProcessing()
+		}
+
+		if sfset.Status.Replicas <= serverGroup.NodeCount {
+			// No scale down requested or, it has been processed already
+			continue
+		}
+
+		// Scale down the StatefulSet
+		updatedSfset := sfset.DeepCopy()
+		replicas := serverGroup.NodeCount
+		updatedSfset.Spec.Replicas = &replicas
+		sr := msgc.getStatefulSetController(serverGroupName).patchStatefulSet(ctx, sfset, updatedSfset)
+		if sr.getError() == nil {
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonMySQLScaleDown, ActionScaleDown,
+				"MySQL Server group %q is being scaled down from %d to %d",
+				serverGroupName, sfset.Status.Replicas, serverGroup.NodeCount)
+		}
+		return sr
+	}
+
+	// All the scale downs have been processed
+	return continueProcessing()
+}
+
+// ReconcileStatefulSets creates the StatefulSets of the new MySQL Server
+// groups and applies any changes to the existing ones. This method is called
+// after the new config has been ensured in both Management and Data Nodes.
+func (msgc *mysqldServerGroupController) ReconcileStatefulSets(ctx context.Context, sc *SyncContext) syncResult {
+	nc := sc.ndb
+
+	for _, serverGroup := range sc.configSummary.MySQLServerGroups {
+		sfsetController := msgc.getStatefulSetController(serverGroup.Name)
+		sfset, exists := sc.mysqldServerGroupSfsets[serverGroup.Name]
+		if !exists {
+			// StatefulSet has to be created
+			// First ensure that a root password secret exists
+			if sr := sc.ensureMySQLRootPassword(ctx, sfsetController.ndbNodeStatefulset.GetName(nc)); sr.stopSync() {
+				return sr
+			}
+
+			// create a statefulset
+			if _, err := sfsetController.createStatefulSet(ctx, sc); err != nil {
+				return errorWhileProcessing(err)
+			}
+
+			// StatefulSet was created successfully.
+			// Finish processing. Reconciliation will
+			// continue once the statefulset is updated.
+			return finishProcessing()
+		}
+
+		// Apply any changes to the StatefulSet
+		if sr := sfsetController.ReconcileStatefulSet(ctx, sfset, sc); sr.stopSync() {
+			return sr
+		}
+
+		// Restart the outdated pods if the StatefulSet uses the OnDelete update strategy
+		if sr := sc.ensureOnDeletePodVersion(
+			ctx, sfset, fmt.Sprintf("MySQL Server group %q", serverGroup.Name)); sr.stopSync() {
+			return sr
+		}
+	}
+
+	// All the MySQL Server groups are up-to-date
+	return continueProcessing()
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func Test_mysqldServerGroupController_HandleScaleDown(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.Spec.MysqlNode.ServerGroups = []v1.NdbMysqldServerGroupSpec{
+		{Name: "oltp", NodeCount: 2},
+	}
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	// Create the StatefulSets of the group in the spec and a removed group
+	ctx := context.Background()
+	sfsetIndexer := f.k8sIf.Apps().V1().StatefulSets().Informer().GetIndexer()
+	for _, serverGroup := range []string{"oltp", "old"} {
+		replicas := int32(3)
+		sfset := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            ndb.GetMySQLServerGroupWorkloadName(serverGroup),
+				Namespace:       ns,
+				Labels:          labels.Merge(ndb.GetLabels(), map[string]string{constants.MySQLServerGroupLabel: serverGroup}),
+				OwnerReferences: ndb.GetOwnerReferences(),
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: &replicas,
+			},
+			Status: appsv1.StatefulSetStatus{
+				Replicas: replicas,
+			},
+		}
+		sfset, err := f.k8sclient.AppsV1().StatefulSets(ns).Create(ctx, sfset, metav1.CreateOptions{})
+		if err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+		if err = sfsetIndexer.Add(sfset); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+
+	serverGroupsString, err := ndbconfig.GetMySQLServerGroupsString(ndb)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	sc := f.c.newSyncContext(ctx, ndb)
+	if sc.configSummary, err = ndbconfig.NewConfigSummary(map[string]string{
+		constants.NdbClusterGeneration: "1",
+		constants.MySQLServerGroups:    serverGroupsString,
+	}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	if sc.mysqldServerGroupSfsets, err = sc.mysqldServerGroupController.GetStatefulSets(ctx, sc); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if len(sc.mysqldServerGroupSfsets) != 2 {
+		t.Fatalf("Expected 2 MySQL Server group StatefulSets but got %d", len(sc.mysqldServerGroupSfsets))
+	}
+
+	// The StatefulSet of the removed group should be deleted first
+	if sr := sc.mysqldServerGroupController.HandleScaleDown(ctx, sc); !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without any error, error : %v", sr.getError())
+	}
+	oldSfsetName := ndb.GetMySQLServerGroupWorkloadName("old")
+	if _, err = f.k8sclient.AppsV1().StatefulSets(ns).Get(ctx, oldSfsetName, metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("StatefulSet %q of the removed group was not deleted, error : %v", oldSfsetName, err)
+	}
+
+	// Then the StatefulSet of the other group should be scaled down
+	delete(sc.mysqldServerGroupSfsets, "old")
+	if sr := sc.mysqldServerGroupController.HandleScaleDown(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	oltpSfset, err := f.k8sclient.AppsV1().StatefulSets(ns).Get(
+		ctx, ndb.GetMySQLServerGroupWorkloadName("oltp"), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if *oltpSfset.Spec.Replicas != 2 {
+		t.Errorf("Expected the MySQL Server group to be scaled down to 2 but has %d replicas", *oltpSfset.Spec.Replicas)
+	}
+}
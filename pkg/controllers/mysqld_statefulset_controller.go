@@ -10,11 +10,17 @@ import (
 	"strconv"
 
 	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller"
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
 	"github.com/mysql/ndb-operator/pkg/mysqlclient"
 	"github.com/mysql/ndb-operator/pkg/resources"
 	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	listersappsv1 "k8s.io/client-go/listers/apps/v1"
 	listerscorev1 "k8s.io/client-go/listers/core/v1"
@@ -24,9 +30,18 @@ import (
 const (
 	// rootHost is the annotation key which stores the Root user's current host
 	rootHost = ndbcontroller.GroupName + "/root-host"
+	// rootAuthenticationPlugin is the annotation key which stores the
+	// authentication plugin last applied to the Root user from the spec.
+	rootAuthenticationPlugin = ndbcontroller.GroupName + "/root-authentication-plugin"
 	// rootUserGeneration is the annotation key which stores the NdbCluster
 	// generation whose spec has been applied to the Root user.
 	rootUserGeneration = ndbcontroller.GroupName + "/root-user-generation"
+	// rootPasswordSecretVersion is the annotation key which stores the resource
+	// version of the root password Secret last applied to the Root user.
+	rootPasswordSecretVersion = ndbcontroller.GroupName + "/root-password-secret-version"
+	// pluginsGeneration is the annotation key which stores, on a MySQL Server
+	// pod, the NdbCluster generation whose plugins have been installed in it.
+	pluginsGeneration = ndbcontroller.GroupName + "/plugins-generation"
 )
 
 type mysqldStatefulSetController struct {
@@ -95,7 +110,7 @@ func (mssc *mysqldStatefulSetController) HandleScaleDown(ctx context.Context, sc
 			return errorWhileProcessing(err)
 		}
 
-		if err := mysqlclient.DeleteRootUserIfExists(mysqldSfset, rootHost, operatorPassword); err != nil {
+		if err := mysqlclient.DeleteRootUserIfExists(ctx, mysqldSfset, rootHost, operatorPassword); err != nil {
 			klog.Errorf("Failed to delete root user")
 			return errorWhileProcessing(err)
 		}
@@ -104,7 +119,8 @@ func (mssc *mysqldStatefulSetController) HandleScaleDown(ctx context.Context, sc
 		annotations := mysqldSfset.GetAnnotations()
 		secretName := annotations[statefulset.RootPasswordSecret]
 
-		if secretClient.IsControlledBy(ctx, secretName, nc) {
+		if nc.Spec.MysqlNode.RootPasswordSecretPolicy != v1.NdbRootPasswordSecretRetain &&
+			secretClient.IsControlledBy(ctx, secretName, nc) {
 			// The given NdbCluster is set as the Owner of the secret,
 			// which implies that this was created by the operator.
 			err := secretClient.Delete(ctx, mysqldSfset.Namespace, secretName)
@@ -121,6 +137,9 @@ func (mssc *mysqldStatefulSetController) HandleScaleDown(ctx context.Context, sc
 		if err := mssc.deleteStatefulSet(ctx, mysqldSfset, sc); err != nil {
 			return errorWhileProcessing(err)
 		}
+		mysqlclient.CloseConnections(mysqldSfset.Namespace, mysqldSfset.Name)
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonMySQLScaleDown, ActionScaleDown,
+			"MySQL Servers are being scaled down from %d to 0", mysqldSfset.Status.Replicas)
 
 		// reconciliation will continue once the statefulset has been deleted
 		return finishProcessing()
@@ -131,7 +150,12 @@ func (mssc *mysqldStatefulSetController) HandleScaleDown(ctx context.Context, sc
 	//        during ReconcileStatefulset
 	updatedSfset := mysqldSfset.DeepCopy()
 	updatedSfset.Spec.Replicas = &mysqldNodeCount
-	return mssc.patchStatefulSet(ctx, mysqldSfset, updatedSfset)
+	sr := mssc.patchStatefulSet(ctx, mysqldSfset, updatedSfset)
+	if sr.getError() == nil {
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonMySQLScaleDown, ActionScaleDown,
+			"MySQL Servers are being scaled down from %d to %d", mysqldSfset.Status.Replicas, mysqldNodeCount)
+	}
+	return sr
 }
 
 // ReconcileStatefulSet compares the MySQL Server spec defined in NdbCluster resource
@@ -151,11 +175,8 @@ func (mssc *mysqldStatefulSetController) ReconcileStatefulSet(ctx context.Contex
 
 		// StatefulSet has to be created
 		// First ensure that a root password secret exists
-		secretClient := NewMySQLUserPasswordSecretInterface(mssc.client)
-		if _, err := secretClient.EnsureMySQLRootPassword(ctx, nc); err != nil {
-			klog.Errorf("Failed to ensure root password secret for StatefulSet %q : %s",
-				mssc.ndbNodeStatefulset.GetName(nc), err)
-			return errorWhileProcessing(err)
+		if sr := sc.ensureMySQLRootPassword(ctx, mssc.ndbNodeStatefulset.GetName(nc)); sr.stopSync() {
+			return sr
 		}
 
 		// create a statefulset
@@ -171,14 +192,26 @@ func (mssc *mysqldStatefulSetController) ReconcileStatefulSet(ctx context.Contex
 
 	// At this point the statefulset exists and has already been verified
 	// to be complete (i.e. no previous updates still being applied) by HandleScaleDown.
-	// Check if the statefulset has the recent config generation.
-	if workloadHasConfigGeneration(mysqldSfset, cs.NdbClusterGeneration) {
-		// Statefulset upto date
+	// Check if the statefulset has the recent config generation,
+	// config.ini version and restart requests.
+	if workloadHasConfigGeneration(mysqldSfset, cs.NdbClusterGeneration) &&
+		workloadHasMySQLClusterConfigVersion(mysqldSfset, cs.MySQLClusterConfigVersion) &&
+		workloadHasRestartRequests(mysqldSfset, cs, constants.NdbNodeTypeMySQLD) {
+		// Statefulset upto date. Verify the canary MySQL
+		// Server if the update is still being rolled out.
+		if sr := mssc.reconcileCanaryRollout(ctx, sc); sr.stopSync() {
+			return sr
+		}
 		klog.Info("All MySQL Servers are up-to-date and ready")
 		return continueProcessing()
 	}
 
 	// Statefulset has to be patched
+	// Apply any change to the RootPasswordSecretPolicy to the root password secret
+	if sr := sc.ensureMySQLRootPassword(ctx, mysqldSfset.Name); sr.stopSync() {
+		return sr
+	}
+
 	// Patch the Governing Service first
 	if err := sc.serviceController.patchService(ctx, sc, mssc.ndbNodeStatefulset); err != nil {
 		return errorWhileProcessing(err)
@@ -190,7 +223,95 @@ func (mssc *mysqldStatefulSetController) ReconcileStatefulSet(ctx context.Contex
 		return errorWhileProcessing(err)
 	}
 
-	return mssc.patchStatefulSet(ctx, mysqldSfset, updatedStatefulSet)
+	if nc.Spec.MysqlNode.CanaryRollout != nil {
+		// Roll out the update only to the canary MySQL Server first
+		startCanaryRollout(updatedStatefulSet, cs.NdbClusterGeneration)
+	}
+
+	sr := mssc.patchStatefulSet(ctx, mysqldSfset, updatedStatefulSet)
+	if sr.stopSync() && sr.getError() == nil {
+		// The StatefulSet controller will now roll out the update to the MySQL Servers
+		if nc.Spec.MysqlNode.CanaryRollout != nil {
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonMySQLCanaryStarted, ActionUpdated,
+				"Generation %d of the spec is being applied to the canary MySQL Server", cs.NdbClusterGeneration)
+		} else {
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonMySQLServersUpdating, ActionUpdated,
+				"MySQL Servers are being updated to apply generation %d of the spec", cs.NdbClusterGeneration)
+		}
+	}
+	return sr
+}
+
+// reconcilePlugins installs the custom plugins and components specified in
+// the NdbCluster spec in every ready MySQL Server pod, including the ones
+// in the server groups, that doesn't have them installed yet. The pods are
+// annotated with the NdbCluster generation whose plugins have been
+// installed in them, so that the plugins are installed again when the spec
+// changes or when a pod is recreated and becomes ready.
+func (mssc *mysqldStatefulSetController) reconcilePlugins(ctx context.Context, sc *SyncContext) syncResult {
+	nc := sc.ndb
+	pluginsSpec := nc.Spec.MysqlNode.Plugins
+	if pluginsSpec == nil || sc.mysqldSfset == nil {
+		// No plugins to install or the MySQL Servers do not exist
+		return continueProcessing()
+	}
+
+	sfsets := []*appsv1.StatefulSet{sc.mysqldSfset}
+	for _, serverGroup := range sortedServerGroups(sc.mysqldServerGroupSfsets) {
+		sfsets = append(sfsets, sc.mysqldServerGroupSfsets[serverGroup])
+	}
+
+	recentNdbGen := strconv.FormatInt(sc.configSummary.NdbClusterGeneration, 10)
+	var operatorPassword string
+	for _, sfset := range sfsets {
+		for ordinal := int32(0); ordinal < *sfset.Spec.Replicas; ordinal++ {
+			podName := fmt.Sprintf("%s-%d", sfset.Name, ordinal)
+			pod, err := sc.podLister.Pods(sfset.Namespace).Get(podName)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					// Pod doesn't exist yet
+					continue
+				}
+				klog.Errorf("Failed to retrieve the MySQL Server pod %q : %s", podName, err)
+				return errorWhileProcessing(err)
+			}
+
+			if pod.Annotations[pluginsGeneration] == recentNdbGen {
+				// The plugins are installed already
+				continue
+			}
+
+			if readyCondition := getPodCondition(pod, corev1.PodReady); readyCondition == nil ||
+				readyCondition.Status != corev1.ConditionTrue {
+				// The plugins will be installed once the pod becomes ready
+				continue
+			}
+
+			if operatorPassword == "" {
+				// Extract ndb operator mysql user password.
+				secretClient := NewMySQLUserPasswordSecretInterface(sc.kubeClientset())
+				operatorSecretName := resources.GetMySQLNDBOperatorPasswordSecretName(nc)
+				if operatorPassword, err = secretClient.ExtractPassword(ctx, sfset.Namespace, operatorSecretName); err != nil {
+					return errorWhileProcessing(err)
+				}
+			}
+
+			if err = mysqlclient.InstallPlugins(ctx, sfset, ordinal, pluginsSpec, operatorPassword); err != nil {
+				klog.Errorf("Failed to install the plugins in MySQL Server %q : %s", podName, err)
+				return errorWhileProcessing(err)
+			}
+
+			// Record the generation whose plugins have been installed in the pod
+			patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, pluginsGeneration, recentNdbGen)
+			if _, err = sc.kubeClientset().CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name,
+				types.MergePatchType, []byte(patch), metav1.PatchOptions{FieldManager: fieldManager}); err != nil {
+				klog.Errorf("Failed to annotate the MySQL Server pod %q : %s", podName, err)
+				return errorWhileProcessing(err)
+			}
+		}
+	}
+
+	return continueProcessing()
 }
 
 // reconcileRootUser creates or updates the root user with the recent NdbCluster spec
@@ -208,14 +329,28 @@ func (mssc *mysqldStatefulSetController) reconcileRootUser(ctx context.Context,
 		rootUserGen, _ = strconv.ParseInt(genString, 10, 64)
 	}
 
+	// Retrieve the root password Secret to detect any change to the password,
+	// e.g. when it is rotated in an external secret store and synced again.
+	nc := sc.ndb
+	rootSecretName, customSecret := resources.GetMySQLRootPasswordSecretName(nc)
+	rootSecret, err := sc.kubeClientset().CoreV1().Secrets(nc.Namespace).Get(ctx, rootSecretName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) && customSecret {
+			// The custom secret specified in the spec is yet to be created
+			return sc.waitForCustomSecret(rootSecretName)
+		}
+		klog.Errorf("Failed to retrieve the root password Secret %q : %s", rootSecretName, err)
+		return errorWhileProcessing(err)
+	}
+	rootPasswordChanged := annotations[rootPasswordSecretVersion] != rootSecret.ResourceVersion
+
 	recentNdbGen := sc.configSummary.NdbClusterGeneration
-	if rootUserGen == recentNdbGen {
-		// The Root user spec is up-to-date
+	if rootUserGen == recentNdbGen && !rootPasswordChanged {
+		// The Root user spec and password are up-to-date
 		return continueProcessing()
 	}
 
 	// The root user needs be created or updated
-	nc := sc.ndb
 	newRootHost := nc.Spec.MysqlNode.RootHost
 
 	// Extract ndb operator mysql user password.
@@ -226,25 +361,75 @@ func (mssc *mysqldStatefulSetController) reconcileRootUser(ctx context.Context,
 		return errorWhileProcessing(err)
 	}
 
-	if existingRootHost, exists := annotations[rootHost]; !exists {
-		// Root user doesn't exist yet - create it.
-		// Extract root user password.
-		secretName, _ := resources.GetMySQLRootPasswordSecretName(nc)
-		rootPassword, err := secretClient.ExtractPassword(ctx, mysqldSfset.Namespace, secretName)
-		if err != nil {
-			return errorWhileProcessing(err)
+	// Configure the password validation in all the MySQL Servers before
+	// creating or updating the root user, so that the root password is
+	// validated against the policy specified in the spec.
+	if passwordValidation := nc.Spec.MysqlNode.PasswordValidation; passwordValidation != nil {
+		for ordinal := int32(0); ordinal < *mysqldSfset.Spec.Replicas; ordinal++ {
+			if err = mysqlclient.ConfigurePasswordValidation(
+				ctx, mysqldSfset, ordinal, passwordValidation, operatorPassword); err != nil {
+				klog.Errorf("Failed to configure the password validation in MySQL Server %s-%d", mysqldSfset.Name, ordinal)
+				return errorWhileProcessing(err)
+			}
+		}
+
+		// Configure it in the MySQL Servers of the groups as well
+		for _, serverGroupSfset := range sc.mysqldServerGroupSfsets {
+			for ordinal := int32(0); ordinal < *serverGroupSfset.Spec.Replicas; ordinal++ {
+				if err = mysqlclient.ConfigurePasswordValidation(
+					ctx, serverGroupSfset, ordinal, passwordValidation, operatorPassword); err != nil {
+					klog.Errorf("Failed to configure the password validation in MySQL Server %s-%d",
+						serverGroupSfset.Name, ordinal)
+					return errorWhileProcessing(err)
+				}
+			}
 		}
+	}
+
+	existingRootHost, rootUserExists := annotations[rootHost]
+	newAuthPlugin := nc.Spec.MysqlNode.RootAuthenticationPlugin
+	authPluginChanged := newAuthPlugin != "" && newAuthPlugin != annotations[rootAuthenticationPlugin]
 
-		// Create Root user
-		if err = mysqlclient.CreateRootUserIfNotExist(mysqldSfset, newRootHost, rootPassword, operatorPassword); err != nil {
+	rootPassword := string(rootSecret.Data[corev1.BasicAuthPasswordKey])
+
+	if !rootUserExists {
+		// Root user doesn't exist yet - create it.
+		if err = mysqlclient.CreateRootUserIfNotExist(
+			ctx, mysqldSfset, newRootHost, rootPassword, newAuthPlugin, operatorPassword); err != nil {
 			klog.Errorf("Failed to create root user")
 			return errorWhileProcessing(err)
 		}
-	} else if newRootHost != existingRootHost {
-		// Root Host needs to be updated
-		if err := mysqlclient.UpdateRootUser(mysqldSfset, existingRootHost, newRootHost, operatorPassword); err != nil {
-			klog.Errorf("Failed to update root user")
-			return errorWhileProcessing(err)
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonRootUserCreated, ActionCreated,
+			"Root user was created with host %q", newRootHost)
+	} else {
+		if newRootHost != existingRootHost {
+			// Root Host needs to be updated
+			if err := mysqlclient.UpdateRootUser(ctx, mysqldSfset, existingRootHost, newRootHost, operatorPassword); err != nil {
+				klog.Errorf("Failed to update root user")
+				return errorWhileProcessing(err)
+			}
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonRootUserUpdated, ActionUpdated,
+				"Root user host was updated from %q to %q", existingRootHost, newRootHost)
+		}
+
+		if authPluginChanged {
+			// Root user's authentication plugin needs to be updated
+			if err := mysqlclient.UpdateRootUserAuthenticationPlugin(
+				ctx, mysqldSfset, newRootHost, rootPassword, newAuthPlugin, operatorPassword); err != nil {
+				klog.Errorf("Failed to update the authentication plugin of root user")
+				return errorWhileProcessing(err)
+			}
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonRootUserUpdated, ActionUpdated,
+				"Root user authentication plugin was updated to %q", newAuthPlugin)
+		} else if rootPasswordChanged {
+			// The password in the root password Secret has changed
+			if err := mysqlclient.UpdateRootUserPassword(
+				ctx, mysqldSfset, newRootHost, rootPassword, operatorPassword); err != nil {
+				klog.Errorf("Failed to update the password of root user")
+				return errorWhileProcessing(err)
+			}
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonRootPasswordUpdated, ActionUpdated,
+				"Root user password was updated from the Secret %q", rootSecretName)
 		}
 	}
 
@@ -253,6 +438,10 @@ func (mssc *mysqldStatefulSetController) reconcileRootUser(ctx context.Context,
 	updatedMysqldSfset := mysqldSfset.DeepCopy()
 	annotations = updatedMysqldSfset.Annotations
 	annotations[rootHost] = newRootHost
+	if newAuthPlugin != "" {
+		annotations[rootAuthenticationPlugin] = newAuthPlugin
+	}
 	annotations[rootUserGeneration] = fmt.Sprintf("%d", recentNdbGen)
+	annotations[rootPasswordSecretVersion] = rootSecret.ResourceVersion
 	return mssc.patchStatefulSet(ctx, mysqldSfset, updatedMysqldSfset)
 }
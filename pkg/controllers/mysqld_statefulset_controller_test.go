@@ -0,0 +1,81 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_reconcilePlugins(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.Spec.MysqlNode.Plugins = &v1.NdbMysqldPluginsSpec{
+		Components: []string{"file://component_audit_api_message_emit"},
+	}
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+	sc := f.c.newSyncContext(ctx, ndb)
+	sc.configSummary = &ndbconfig.ConfigSummary{NdbClusterGeneration: 2}
+	replicas := int32(3)
+	sc.mysqldSfset = &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ndb.GetWorkloadName(constants.NdbNodeTypeMySQLD),
+			Namespace: ns,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+		},
+	}
+
+	// The first pod is yet to become ready, the second pod has the plugins
+	// of the recent generation installed already and the third pod doesn't
+	// exist yet. None of them should have the plugins installed now.
+	podIndexer := f.k8sIf.Core().V1().Pods().Informer().GetIndexer()
+	for podName, pod := range map[string]*corev1.Pod{
+		"test-mysqld-0": {
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+			},
+		},
+		"test-mysqld-1": {
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					pluginsGeneration: strconv.FormatInt(sc.configSummary.NdbClusterGeneration, 10),
+				},
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		},
+	} {
+		pod.Name = podName
+		pod.Namespace = ns
+		if err := podIndexer.Add(pod); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+
+	if sr := sc.mysqldController.reconcilePlugins(ctx, sc); sr.stopSync() {
+		t.Fatalf("Expected the sync to continue, error : %v", sr.getError())
+	}
+	if actions := filterInformerActions(f.k8sclient.Actions()); len(actions) != 0 {
+		t.Errorf("Expected no actions but got %v", actions)
+	}
+}
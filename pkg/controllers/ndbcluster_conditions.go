@@ -0,0 +1,59 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// conditionEvents describes the Events recorded by
+// updateCondition when the state of a condition changes
+type conditionEvents struct {
+	// failureStatus is the condition status that reports a failure
+	failureStatus corev1.ConditionStatus
+	// failureReason is the reason of the Warning Event recorded
+	// when the condition enters the failureStatus
+	failureReason string
+	// recoveryReason is the reason of the Normal Event recorded when
+	// the condition leaves the failureStatus. No Event is recorded
+	// on recovery if it is empty.
+	recoveryReason string
+	// renotify, if true, records the Warning Event again whenever
+	// the reason or the message of the failed condition changes
+	renotify bool
+}
+
+// updateCondition sets the LastTransitionTime of the given condition,
+// retaining the one of the previousCondition if the status has not
+// changed, and records the Events described by the given
+// conditionEvents if the state of the condition has changed.
+func (sc *SyncContext) updateCondition(
+	condition, previousCondition *v1.NdbClusterCondition, events conditionEvents) {
+
+	// Retain the last transition time if the status has not changed
+	condition.LastTransitionTime = metav1.Now()
+	if previousCondition != nil && previousCondition.Status == condition.Status {
+		condition.LastTransitionTime = previousCondition.LastTransitionTime
+	}
+
+	previouslyFailed := previousCondition != nil && previousCondition.Status == events.failureStatus
+	if condition.Status == events.failureStatus {
+		if !previouslyFailed || (events.renotify &&
+			(previousCondition.Reason != condition.Reason || previousCondition.Message != condition.Message)) {
+			sc.logger.Info("NdbCluster condition reports a failure",
+				"condition", condition.Type, "message", condition.Message)
+			sc.recorder.Eventf(sc.ndb, nil, corev1.EventTypeWarning,
+				events.failureReason, ActionNone, "%s", condition.Message)
+		}
+	} else if previouslyFailed && events.recoveryReason != "" {
+		sc.logger.Info("NdbCluster condition has recovered",
+			"condition", condition.Type, "message", condition.Message)
+		sc.recorder.Eventf(sc.ndb, nil, corev1.EventTypeNormal,
+			events.recoveryReason, ActionNone, "%s", condition.Message)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	klog "k8s.io/klog/v2"
+)
+
+func Test_updateCondition(t *testing.T) {
+	lastTransitionTime := metav1.NewTime(time.Now().Add(-time.Hour))
+	newCondition := func(status corev1.ConditionStatus, message string) *v1.NdbClusterCondition {
+		return &v1.NdbClusterCondition{
+			Type:               v1.NdbClusterNodesConnected,
+			Status:             status,
+			Message:            message,
+			LastTransitionTime: lastTransitionTime,
+		}
+	}
+
+	for _, tc := range []struct {
+		desc                  string
+		previous, current     *v1.NdbClusterCondition
+		renotify              bool
+		expectedEvent         string
+		retainsTransitionTime bool
+	}{
+		{
+			desc:    "first condition without failure",
+			current: newCondition(corev1.ConditionTrue, "ok"),
+		},
+		{
+			desc:          "first condition with failure",
+			current:       newCondition(corev1.ConditionFalse, "failed"),
+			expectedEvent: "Warning Failed",
+		},
+		{
+			desc:          "failure",
+			previous:      newCondition(corev1.ConditionTrue, "ok"),
+			current:       newCondition(corev1.ConditionFalse, "failed"),
+			expectedEvent: "Warning Failed",
+		},
+		{
+			desc:                  "unchanged failure",
+			previous:              newCondition(corev1.ConditionFalse, "failed"),
+			current:               newCondition(corev1.ConditionFalse, "failed"),
+			renotify:              true,
+			retainsTransitionTime: true,
+		},
+		{
+			desc:                  "changed failure",
+			previous:              newCondition(corev1.ConditionFalse, "failed"),
+			current:               newCondition(corev1.ConditionFalse, "failed again"),
+			renotify:              true,
+			expectedEvent:         "Warning Failed",
+			retainsTransitionTime: true,
+		},
+		{
+			desc:                  "changed failure without renotify",
+			previous:              newCondition(corev1.ConditionFalse, "failed"),
+			current:               newCondition(corev1.ConditionFalse, "failed again"),
+			retainsTransitionTime: true,
+		},
+		{
+			desc:          "recovery",
+			previous:      newCondition(corev1.ConditionFalse, "failed"),
+			current:       newCondition(corev1.ConditionTrue, "ok"),
+			expectedEvent: "Normal Recovered",
+		},
+	} {
+		recorder := events.NewFakeRecorder(10)
+		sc := &SyncContext{
+			ndb:      testutils.NewTestNdb("default", "test", 2),
+			recorder: recorder,
+			logger:   klog.Background(),
+		}
+
+		sc.updateCondition(tc.current, tc.previous, conditionEvents{
+			failureStatus:  corev1.ConditionFalse,
+			failureReason:  "Failed",
+			recoveryReason: "Recovered",
+			renotify:       tc.renotify,
+		})
+
+		if tc.current.LastTransitionTime.Equal(&lastTransitionTime) != tc.retainsTransitionTime {
+			t.Errorf("Testcase %q failed : unexpected last transition time %v",
+				tc.desc, tc.current.LastTransitionTime)
+		}
+
+		select {
+		case event := <-recorder.Events:
+			if tc.expectedEvent == "" || !strings.HasPrefix(event, tc.expectedEvent+" ") {
+				t.Errorf("Testcase %q failed : unexpected event %q", tc.desc, event)
+			}
+		default:
+			if tc.expectedEvent != "" {
+				t.Errorf("Testcase %q failed : expected the event %q", tc.desc, tc.expectedEvent)
+			}
+		}
+	}
+}
@@ -5,14 +5,24 @@
 package controllers
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
 	"github.com/mysql/ndb-operator/pkg/resources"
 
+	jsonpatch "github.com/evanphx/json-patch"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
 	klog "k8s.io/klog/v2"
 )
 
@@ -24,11 +34,60 @@ func statusEqual(oldStatus *v1.NdbClusterStatus, newStatus *v1.NdbClusterStatus)
 		oldStatus.ReadyManagementNodes == newStatus.ReadyManagementNodes &&
 		oldStatus.ReadyDataNodes == newStatus.ReadyDataNodes &&
 		oldStatus.ReadyMySQLServers == newStatus.ReadyMySQLServers &&
+		oldStatus.ConnectedMySQLServers == newStatus.ConnectedMySQLServers &&
+		oldStatus.MySQLServerReplicas == newStatus.MySQLServerReplicas &&
+		oldStatus.MySQLServerSelector == newStatus.MySQLServerSelector &&
 		oldStatus.GeneratedRootPasswordSecretName == newStatus.GeneratedRootPasswordSecretName &&
-		// TODO: Improve this comparison when more conditions are added
-		oldStatus.Conditions[0].Status == newStatus.Conditions[0].Status &&
-		oldStatus.Conditions[0].Reason == newStatus.Conditions[0].Reason &&
-		oldStatus.Conditions[0].Message == newStatus.Conditions[0].Message
+		oldStatus.PendingDataNodeRestart == newStatus.PendingDataNodeRestart &&
+		equality.Semantic.DeepEqual(oldStatus.ConfigRollout, newStatus.ConfigRollout) &&
+		equality.Semantic.DeepEqual(oldStatus.PendingChanges, newStatus.PendingChanges) &&
+		equality.Semantic.DeepEqual(oldStatus.Health, newStatus.Health) &&
+		equality.Semantic.DeepEqual(oldStatus.LatestEpoch, newStatus.LatestEpoch) &&
+		equality.Semantic.DeepEqual(oldStatus.Nodes, newStatus.Nodes) &&
+		equality.Semantic.DeepEqual(oldStatus.MySQLServerGroups, newStatus.MySQLServerGroups) &&
+		conditionsEqual(oldStatus.Conditions, newStatus.Conditions)
+}
+
+// conditionsEqual checks if the given two lists of NdbClusterConditions
+// are equal, ignoring their LastTransitionTime.
+func conditionsEqual(oldConditions, newConditions []v1.NdbClusterCondition) bool {
+	if len(oldConditions) != len(newConditions) {
+		return false
+	}
+
+	for i := range oldConditions {
+		if oldConditions[i].Type != newConditions[i].Type ||
+			oldConditions[i].Status != newConditions[i].Status ||
+			oldConditions[i].Reason != newConditions[i].Reason ||
+			oldConditions[i].Message != newConditions[i].Message {
+			return false
+		}
+	}
+
+	return true
+}
+
+// createStatusPatch returns a JSON merge patch that updates the status of
+// an NdbCluster resource from the oldStatus to the newStatus. The patch
+// has only the fields that have changed, and the conditions list, if
+// changed, is replaced entirely. If a resourceVersion is given, it is
+// included in the patch as a precondition, so that the patch is rejected
+// with a Conflict if the NdbCluster has been modified since it was read.
+func createStatusPatch(oldStatus, newStatus *v1.NdbClusterStatus, resourceVersion string) ([]byte, error) {
+	oldJSON, err := json.Marshal(&v1.NdbCluster{Status: *oldStatus})
+	if err != nil {
+		return nil, err
+	}
+
+	newJSON, err := json.Marshal(&v1.NdbCluster{
+		ObjectMeta: metav1.ObjectMeta{ResourceVersion: resourceVersion},
+		Status:     *newStatus,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonpatch.CreateMergePatch(oldJSON, newJSON)
 }
 
 // calculateNdbClusterStatus generates the current status for the NdbCluster in SyncContext
@@ -60,6 +119,7 @@ func (sc *SyncContext) calculateNdbClusterStatus() *v1.NdbClusterStatus {
 	numOfMySQLServersRequired := nc.GetMySQLServerNodeCount()
 	if sc.mysqldSfset != nil {
 		numOfReadyMySQLNodes = sc.mysqldSfset.Status.ReadyReplicas
+		status.MySQLServerReplicas = sc.mysqldSfset.Status.Replicas
 		// Update generatedRootPasswordSecretName if one exists
 		if numOfMySQLServersRequired > 0 {
 			if secretName, customSecret := resources.GetMySQLRootPasswordSecretName(nc); !customSecret {
@@ -70,6 +130,16 @@ func (sc *SyncContext) calculateNdbClusterStatus() *v1.NdbClusterStatus {
 	}
 	status.ReadyMySQLServers = fmt.Sprintf(
 		"Ready:%d/%d", numOfReadyMySQLNodes, numOfMySQLServersRequired)
+	// Number of MySQL Servers connected to the NDB engine. Retain
+	// the previous value if it could not be computed during this sync.
+	if sc.connectedMySQLServers != nil {
+		status.ConnectedMySQLServers = fmt.Sprintf(
+			"Connected:%d/%d", *sc.connectedMySQLServers, numOfMySQLServersRequired)
+	} else {
+		status.ConnectedMySQLServers = nc.Status.ConnectedMySQLServers
+	}
+	// Selector for the MySQL Server pods used by the scale subresource
+	status.MySQLServerSelector = nc.GetMySQLServerSelector().String()
 
 	// Set processedGeneration and upToDate condition
 	upToDateCondition := v1.NdbClusterCondition{
@@ -89,9 +159,15 @@ func (sc *SyncContext) calculateNdbClusterStatus() *v1.NdbClusterStatus {
 		status.ProcessedGeneration = nc.Status.ProcessedGeneration
 
 		upToDateCondition.Status = corev1.ConditionFalse
-		if errMsgs := sc.retrievePodErrors(); errMsgs != nil {
-			// One or more pods owned by the NdbCluster resource is failing
-			klog.Errorf("One or more pods owned by the ndbcluster resource %q are failing : \n%s", getNamespacedName(nc), errMsgs)
+		if sc.disallowedDataNodeRestart != "" {
+			// The spec changes cannot be applied to the MySQL Cluster
+			upToDateCondition.Reason = v1.NdbClusterUptoDateReasonError
+			upToDateCondition.Message = fmt.Sprintf(
+				"NdbCluster spec generation %d requires the data nodes to be restarted with "+
+					"the restart type %s, which is not allowed by the spec", nc.Generation, sc.disallowedDataNodeRestart)
+		} else if errMsgs := append(sc.retrievePodErrors(), sc.workloadErrors...); errMsgs != nil {
+			// One or more pods or workloads owned by the NdbCluster resource is failing
+			klog.Errorf("One or more pods or workloads owned by the ndbcluster resource %q are failing : \n%s", getNamespacedName(nc), errMsgs)
 			upToDateCondition.Reason = v1.NdbClusterUptoDateReasonError
 			upToDateCondition.Message = strings.Join(errMsgs, "\n")
 		} else if nc.Generation == 1 {
@@ -107,5 +183,337 @@ func (sc *SyncContext) calculateNdbClusterStatus() *v1.NdbClusterStatus {
 	}
 	status.Conditions = append(status.Conditions, upToDateCondition)
 
+	// Set the type of the restart the data nodes are pending to apply the latest config
+	status.PendingDataNodeRestart = sc.getPendingDataNodeRestart()
+
+	// Set the progress of the rollout of the latest config to the nodes
+	status.ConfigRollout = sc.getConfigRolloutStatus()
+
+	// Set the summary of the config changes being applied
+	status.PendingChanges = sc.getPendingChanges()
+
+	// Set the partitioned condition. Retain the previous one
+	// if it could not be computed during this sync.
+	if sc.partitionedCondition != nil {
+		status.Conditions = append(status.Conditions, *sc.partitionedCondition)
+	} else if partitionedCondition := nc.GetPartitionedCondition(); partitionedCondition != nil {
+		status.Conditions = append(status.Conditions, *partitionedCondition)
+	}
+
+	// Set the data node restart loop condition. Retain
+	// the previous one if it was not changed during this sync.
+	if sc.dataNodeRestartLoopCondition != nil {
+		status.Conditions = append(status.Conditions, *sc.dataNodeRestartLoopCondition)
+	} else if restartLoopCondition := nc.GetDataNodeRestartLoopCondition(); restartLoopCondition != nil {
+		status.Conditions = append(status.Conditions, *restartLoopCondition)
+	}
+
+	// Set the nodes connected condition. Retain the previous
+	// one if it was not checked during this sync.
+	if sc.nodesConnectedCondition != nil {
+		status.Conditions = append(status.Conditions, *sc.nodesConnectedCondition)
+	} else if nodesConnectedCondition := nc.GetNodesConnectedCondition(); nodesConnectedCondition != nil {
+		status.Conditions = append(status.Conditions, *nodesConnectedCondition)
+	}
+
+	// Set the zone redundant condition, if the zones are specified.
+	// Retain the previous one if it could not be computed during this sync.
+	if len(nc.Spec.DataNode.Zones) != 0 {
+		if sc.zoneRedundantCondition != nil {
+			status.Conditions = append(status.Conditions, *sc.zoneRedundantCondition)
+		} else if zoneRedundantCondition := nc.GetZoneRedundantCondition(); zoneRedundantCondition != nil {
+			status.Conditions = append(status.Conditions, *zoneRedundantCondition)
+		}
+	}
+
+	// Set the storage resized condition, if the data nodes use PVCs.
+	// Retain the previous one if it could not be computed during this sync.
+	if nc.Spec.DataNode.PVCSpec != nil {
+		if sc.storageResizedCondition != nil {
+			status.Conditions = append(status.Conditions, *sc.storageResizedCondition)
+		} else if storageResizedCondition := nc.GetStorageResizedCondition(); storageResizedCondition != nil {
+			status.Conditions = append(status.Conditions, *storageResizedCondition)
+		}
+	}
+
+	// Set the local volumes available condition, if it is enabled in the spec.
+	// Retain the previous one if it could not be computed during this sync.
+	if nc.Spec.DataNode.LocalVolumes != nil {
+		if sc.localVolumesAvailableCondition != nil {
+			status.Conditions = append(status.Conditions, *sc.localVolumesAvailableCondition)
+		} else if localVolumesAvailableCondition := nc.GetLocalVolumesAvailableCondition(); localVolumesAvailableCondition != nil {
+			status.Conditions = append(status.Conditions, *localVolumesAvailableCondition)
+		}
+	}
+
+	// Set the health snapshot and the healthy condition, if the health
+	// monitoring is enabled. Retain the previous ones if the health
+	// was not sampled during this sync.
+	if nc.Spec.HealthMonitoring != nil {
+		if sc.healthSnapshot != nil {
+			status.Health = sc.healthSnapshot
+			status.Conditions = append(status.Conditions, *sc.healthyCondition)
+		} else {
+			status.Health = nc.Status.Health
+			if healthyCondition := nc.GetHealthyCondition(); healthyCondition != nil {
+				status.Conditions = append(status.Conditions, *healthyCondition)
+			}
+		}
+	}
+
+	// Retain the latest epoch published by the epochSampler
+	status.LatestEpoch = nc.Status.LatestEpoch
+
+	// Set the nodeId to pod mapping of the MySQL Cluster nodes
+	status.Nodes = sc.getNodesStatus()
+
+	// Record the nodeIds reserved for the MySQL Server groups
+	status.MySQLServerGroups = sc.getMySQLServerGroupsStatus()
+
+	// The status is calculated only when the sync succeeds. So, if the
+	// NdbCluster was marked as degraded, mark it as recovered.
+	if degradedCondition := nc.GetDegradedCondition(); degradedCondition != nil {
+		if degradedCondition.Status == corev1.ConditionTrue {
+			degradedCondition = &v1.NdbClusterCondition{
+				Type:               v1.NdbClusterDegraded,
+				Status:             corev1.ConditionFalse,
+				LastTransitionTime: metav1.Now(),
+				Reason:             v1.NdbClusterDegradedReasonSyncRecovered,
+				Message:            "NdbCluster was successfully synced after repeated failures",
+			}
+		}
+		status.Conditions = append(status.Conditions, *degradedCondition)
+	}
+
 	return status
 }
+
+// getNodesStatus maps the nodes declared in the current config to the
+// pods running them. The previous mapping is retained if the config
+// has not been generated yet.
+func (sc *SyncContext) getNodesStatus() []v1.NdbClusterNodeStatus {
+	nc := sc.ndb
+	if sc.configSummary == nil {
+		return nc.Status.Nodes
+	}
+
+	var nodes []v1.NdbClusterNodeStatus
+	for _, node := range sc.configSummary.Nodes {
+		nodeStatus := v1.NdbClusterNodeStatus{
+			NodeId:   int32(node.NodeId),
+			NodeType: node.NodeType,
+			PodName:  node.PodName,
+		}
+		if pod, err := sc.podLister.Pods(nc.Namespace).Get(node.PodName); err == nil {
+			nodeStatus.PodIP = pod.Status.PodIP
+		}
+		nodes = append(nodes, nodeStatus)
+	}
+
+	return nodes
+}
+
+// getMySQLServerGroupsStatus returns the ranges of nodeIds reserved for the
+// MySQL Server groups in the current config. The previous ranges are
+// retained if the config has not been generated yet.
+func (sc *SyncContext) getMySQLServerGroupsStatus() []v1.NdbMysqldServerGroupStatus {
+	nc := sc.ndb
+	if sc.configSummary == nil {
+		return nc.Status.MySQLServerGroups
+	}
+
+	var serverGroups []v1.NdbMysqldServerGroupStatus
+	for _, serverGroup := range sc.configSummary.MySQLServerGroups {
+		numOfNodeIds := serverGroup.NumOfNodeIds
+		if numOfNodeIds == 0 {
+			// The config was generated before the size of the ranges was
+			// recorded, and the range holds only the nodeIds of the group.
+			numOfNodeIds = serverGroup.NodeCount * nc.GetMySQLServerConnectionPoolSize()
+		}
+		serverGroups = append(serverGroups, v1.NdbMysqldServerGroupStatus{
+			Name:         serverGroup.Name,
+			StartNodeId:  serverGroup.StartNodeId,
+			NumOfNodeIds: numOfNodeIds,
+		})
+	}
+
+	return serverGroups
+}
+
+// getPendingDataNodeRestart returns the type of the restart the data nodes
+// need to apply the latest spec, or an empty string if there is none.
+func (sc *SyncContext) getPendingDataNodeRestart() v1.DataNodeRestartType {
+	if sc.disallowedDataNodeRestart != "" {
+		return sc.disallowedDataNodeRestart
+	}
+
+	cs := sc.configSummary
+	sfset := sc.dataNodeSfSet
+	if sc.syncSuccess || cs == nil || sfset == nil || sc.ndb.Status.ProcessedGeneration == 0 {
+		// The data nodes are up-to-date or are being started for the first time
+		return ""
+	}
+
+	if workloadHasMySQLClusterConfigVersion(sfset, cs.MySQLClusterConfigVersion) &&
+		sfset.Status.ObservedGeneration == sfset.Generation &&
+		sfset.Spec.Replicas != nil && sfset.Status.UpdatedReplicas >= *sfset.Spec.Replicas {
+		// All the data nodes are running with the latest config
+		return ""
+	}
+
+	return cs.DataNodeRestartType
+}
+
+// getPendingChanges returns the summary of the changes made by the
+// latest spec to the configs, while the spec is being applied.
+func (sc *SyncContext) getPendingChanges() []string {
+	nc := sc.ndb
+	cs := sc.configSummary
+	if sc.syncSuccess || cs == nil || nc.Status.ProcessedGeneration == 0 ||
+		cs.NdbClusterGeneration != nc.Generation {
+		// The spec has been applied, the MySQL Cluster is being started
+		// for the first time or the config is yet to be updated
+		return nil
+	}
+
+	return cs.ConfigChanges
+}
+
+// getConfigRolloutStatus returns the progress of applying the latest
+// config and pod definitions to the MySQL Cluster nodes. The previous
+// status is retained if the config summary is not available.
+func (sc *SyncContext) getConfigRolloutStatus() *v1.NdbClusterConfigRolloutStatus {
+	cs := sc.configSummary
+	if cs == nil {
+		return sc.ndb.Status.ConfigRollout
+	}
+
+	mysqldSfsets := []*appsv1.StatefulSet{sc.mysqldSfset}
+	for _, sfset := range sc.mysqldServerGroupSfsets {
+		mysqldSfsets = append(mysqldSfsets, sfset)
+	}
+
+	return &v1.NdbClusterConfigRolloutStatus{
+		ConfigGeneration: cs.MySQLClusterConfigVersion,
+		// The external arbitrator is a Management node
+		ManagementNodes: sc.getNodesConfigRolloutStatus(
+			constants.NdbNodeTypeMgmd, sc.mgmdNodeSfset, sc.arbitratorSfset),
+		DataNodes: sc.getNodesConfigRolloutStatus(
+			constants.NdbNodeTypeNdbmtd, sc.dataNodeSfSet),
+		MySQLServers: sc.getNodesConfigRolloutStatus(
+			constants.NdbNodeTypeMySQLD, mysqldSfsets...),
+	}
+}
+
+// getNodesConfigRolloutStatus returns the progress of applying the latest
+// config and pod definition to the pods of the given StatefulSets, which
+// all run nodes of the given type. It returns nil if there are no pods.
+func (sc *SyncContext) getNodesConfigRolloutStatus(
+	nodeType constants.NdbNodeType, sfsets ...*appsv1.StatefulSet) *v1.NdbNodesConfigRolloutStatus {
+	cs := sc.configSummary
+	desiredConfigVersion := cs.GetMySQLClusterConfigVersion(nodeType)
+
+	var status *v1.NdbNodesConfigRolloutStatus
+	for _, sfset := range sfsets {
+		if sfset == nil {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(sfset.Spec.Selector)
+		if err != nil {
+			sc.logger.Error(err, "Failed to parse the StatefulSet selector", "statefulset", sfset.Name)
+			continue
+		}
+
+		pods, err := sc.podLister.Pods(sfset.Namespace).List(selector)
+		if err != nil {
+			sc.logger.Error(err, "Failed to list the StatefulSet pods", "statefulset", sfset.Name)
+			continue
+		}
+
+		for _, pod := range pods {
+			configVersion := getPodMySQLClusterConfigVersion(pod)
+			pendingRestart := configVersion != desiredConfigVersion ||
+				pod.GetLabels()["controller-revision-hash"] != sfset.Status.UpdateRevision
+			if !pendingRestart && nodeType == constants.NdbNodeTypeMgmd && sc.mgmdConfigReloaded {
+				// The Management node is running with the reloaded config
+				configVersion = cs.MySQLClusterConfigVersion
+			}
+
+			if status == nil {
+				status = &v1.NdbNodesConfigRolloutStatus{
+					ConfigGeneration: configVersion,
+				}
+			} else if configVersion < status.ConfigGeneration {
+				status.ConfigGeneration = configVersion
+			}
+
+			if pendingRestart {
+				status.PendingRestart = append(status.PendingRestart, pod.Name)
+			}
+		}
+	}
+
+	if status != nil {
+		// The pods are listed in no particular order
+		sort.Strings(status.PendingRestart)
+	}
+
+	return status
+}
+
+// markNdbClusterDegraded sets the NdbClusterDegraded condition of the
+// NdbCluster with the given key to True, with the error returned by the
+// last sync and the approximate time until the next retry.
+func (c *Controller) markNdbClusterDegraded(
+	ctx context.Context, key string, syncErr error, failures int) error {
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	degradedCondition := v1.NdbClusterCondition{
+		Type:               v1.NdbClusterDegraded,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             v1.NdbClusterDegradedReasonSyncFailing,
+		Message: fmt.Sprintf("Sync has failed %d consecutive times, will be retried in about %s. Last error : %s",
+			failures, expectedRetryDelay(failures), syncErr),
+	}
+
+	// The lister copy of the NdbCluster might not have the conditions recently
+	// set by the sync, so read the NdbCluster from the K8s Server and update only
+	// its Degraded condition. The update carries the resourceVersion of the read
+	// object and is retried with a fresh copy if the status changes meanwhile.
+	ndbInterface := c.ndbClient.MysqlV1().NdbClusters(namespace)
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nc, err := ndbInterface.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		// Replace any existing degraded condition, retaining its LastTransitionTime if it is already degraded
+		condition := degradedCondition
+		conditions := nc.Status.Conditions[:0]
+		for _, existingCondition := range nc.Status.Conditions {
+			if existingCondition.Type == v1.NdbClusterDegraded {
+				if existingCondition.Status == corev1.ConditionTrue {
+					condition.LastTransitionTime = existingCondition.LastTransitionTime
+				}
+				continue
+			}
+			conditions = append(conditions, existingCondition)
+		}
+		nc.Status.Conditions = append(conditions, condition)
+
+		_, err = ndbInterface.UpdateStatus(ctx, nc, metav1.UpdateOptions{})
+		return err
+	})
+
+	if apierrors.IsNotFound(err) {
+		// NdbCluster has been deleted
+		return nil
+	}
+	return err
+}
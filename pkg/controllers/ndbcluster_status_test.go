@@ -0,0 +1,277 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+func Test_createStatusPatch(t *testing.T) {
+	oldStatus := &v1.NdbClusterStatus{
+		ProcessedGeneration:  1,
+		ReadyManagementNodes: "Ready:2/2",
+		ReadyDataNodes:       "Ready:1/2",
+		Conditions: []v1.NdbClusterCondition{
+			{
+				Type:   v1.NdbClusterUpToDate,
+				Status: corev1.ConditionFalse,
+				Reason: "SyncInProgress",
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		desc            string
+		updateStatus    func(status *v1.NdbClusterStatus)
+		resourceVersion string
+		expectedPatch   string
+	}{
+		{
+			desc:          "no change",
+			updateStatus:  func(status *v1.NdbClusterStatus) {},
+			expectedPatch: `{}`,
+		},
+		{
+			desc: "only changed fields are patched",
+			updateStatus: func(status *v1.NdbClusterStatus) {
+				status.ReadyDataNodes = "Ready:2/2"
+			},
+			expectedPatch: `{"status":{"readyDataNodes":"Ready:2/2"}}`,
+		},
+		{
+			desc: "conditions are replaced entirely",
+			updateStatus: func(status *v1.NdbClusterStatus) {
+				status.Conditions[0].Status = corev1.ConditionTrue
+				status.Conditions[0].Reason = "SyncSuccess"
+			},
+			expectedPatch: `{"status":{"conditions":[` +
+				`{"lastTransitionTime":null,"reason":"SyncSuccess","status":"True","type":"UpToDate"}]}}`,
+		},
+		{
+			desc: "removed fields are set to null",
+			updateStatus: func(status *v1.NdbClusterStatus) {
+				status.ReadyManagementNodes = ""
+			},
+			expectedPatch: `{"status":{"readyManagementNodes":null}}`,
+		},
+		{
+			desc: "resourceVersion is included as a precondition",
+			updateStatus: func(status *v1.NdbClusterStatus) {
+				status.ReadyDataNodes = "Ready:2/2"
+			},
+			resourceVersion: "42",
+			expectedPatch:   `{"metadata":{"resourceVersion":"42"},"status":{"readyDataNodes":"Ready:2/2"}}`,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			newStatus := oldStatus.DeepCopy()
+			tc.updateStatus(newStatus)
+			patch, err := createStatusPatch(oldStatus, newStatus, tc.resourceVersion)
+			if err != nil {
+				t.Fatalf("Unexpected error : %s", err)
+			}
+
+			if string(patch) != tc.expectedPatch {
+				t.Errorf("Expected patch %s but got %s", tc.expectedPatch, patch)
+			}
+		})
+	}
+}
+
+func Test_markNdbClusterDegraded(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.Status.Conditions = []v1.NdbClusterCondition{
+		{
+			Type:   v1.NdbClusterUpToDate,
+			Status: corev1.ConditionFalse,
+			Reason: v1.NdbClusterUptoDateReasonSpecUpdateInProgress,
+		},
+	}
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	// Update the UpToDate condition only in the K8s Server,
+	// leaving the lister copy of the NdbCluster outdated
+	ctx := context.Background()
+	updatedNdb := ndb.DeepCopy()
+	updatedNdb.Status.Conditions[0].Status = corev1.ConditionTrue
+	updatedNdb.Status.Conditions[0].Reason = v1.NdbClusterUptoDateReasonSyncSuccess
+	if _, err := f.ndbclient.MysqlV1().NdbClusters(ns).UpdateStatus(ctx, updatedNdb, metav1.UpdateOptions{}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	if err := f.c.markNdbClusterDegraded(ctx, getKey(ndb, t), errors.New("failed to connect"), 5); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	nc, err := f.ndbclient.MysqlV1().NdbClusters(ns).Get(ctx, ndb.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	// The latest existing condition should be retained
+	if len(nc.Status.Conditions) != 2 || nc.Status.Conditions[0].Type != v1.NdbClusterUpToDate ||
+		nc.Status.Conditions[0].Status != corev1.ConditionTrue {
+		t.Errorf("Unexpected conditions : %#v", nc.Status.Conditions)
+	}
+
+	degradedCondition := nc.GetDegradedCondition()
+	if degradedCondition == nil || degradedCondition.Status != corev1.ConditionTrue ||
+		degradedCondition.Reason != v1.NdbClusterDegradedReasonSyncFailing {
+		t.Fatalf("Degraded condition not set as expected : %#v", degradedCondition)
+	}
+
+	for _, expectedMsg := range []string{"5 consecutive times", "failed to connect", "about 1m20s"} {
+		if !strings.Contains(degradedCondition.Message, expectedMsg) {
+			t.Errorf("Degraded condition message %q doesn't contain %q", degradedCondition.Message, expectedMsg)
+		}
+	}
+
+	// A successful sync should mark the NdbCluster as recovered
+	sc := f.c.newSyncContext(ctx, nc)
+	status := sc.calculateNdbClusterStatus()
+	for _, condition := range status.Conditions {
+		if condition.Type == v1.NdbClusterDegraded {
+			if condition.Status != corev1.ConditionFalse ||
+				condition.Reason != v1.NdbClusterDegradedReasonSyncRecovered {
+				t.Errorf("Degraded condition not reset as expected : %#v", condition)
+			}
+			return
+		}
+	}
+	t.Error("Degraded condition missing from the recalculated status")
+}
+
+func Test_getConfigRolloutStatus(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	podLabels := map[string]string{constants.ClusterNodeTypeLabel: constants.NdbNodeTypeNdbmtd}
+	dataNodeSfset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ndbmtd", Namespace: ns},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+		},
+		Status: appsv1.StatefulSetStatus{UpdateRevision: "rev-2"},
+	}
+
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, pod := range []struct {
+		name, revision, configVersion string
+	}{
+		{"test-ndbmtd-0", "rev-2", "3"},
+		{"test-ndbmtd-1", "rev-1", "2"},
+		{"test-ndbmtd-2", "rev-1", "2"},
+	} {
+		if err := podIndexer.Add(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pod.name,
+				Namespace: ns,
+				Labels: labels.Merge(podLabels, map[string]string{
+					"controller-revision-hash": pod.revision,
+				}),
+				Annotations: map[string]string{
+					statefulset.LastAppliedMySQLClusterConfigVersion: pod.configVersion,
+				},
+			},
+		}); err != nil {
+			t.Fatalf("Unexpected error : %s", err)
+		}
+	}
+
+	sc := &SyncContext{
+		ndb: testutils.NewTestNdb(ns, "test", 2),
+		configSummary: &ndbconfig.ConfigSummary{
+			MySQLClusterConfigVersion: 3,
+			MgmdRestartConfigVersion:  3,
+		},
+		dataNodeSfSet: dataNodeSfset,
+		podLister:     corelisters.NewPodLister(podIndexer),
+		logger:        klog.Background(),
+	}
+
+	status := sc.getConfigRolloutStatus()
+	if status.ConfigGeneration != 3 {
+		t.Errorf("Expected config generation 3 but got %d", status.ConfigGeneration)
+	}
+
+	if status.ManagementNodes != nil || status.MySQLServers != nil {
+		t.Errorf("Expected no rollout status for the missing nodes : %#v", status)
+	}
+
+	expectedDataNodesStatus := &v1.NdbNodesConfigRolloutStatus{
+		ConfigGeneration: 2,
+		PendingRestart:   []string{"test-ndbmtd-1", "test-ndbmtd-2"},
+	}
+	if !reflect.DeepEqual(status.DataNodes, expectedDataNodesStatus) {
+		t.Errorf("Expected data nodes rollout status %#v but got %#v", expectedDataNodesStatus, status.DataNodes)
+	}
+
+	// The previous status should be retained if the config summary is not available
+	sc.ndb.Status.ConfigRollout = status
+	sc.configSummary = nil
+	if sc.getConfigRolloutStatus() != status {
+		t.Error("Previous config rollout status was not retained")
+	}
+}
+
+func Test_getNodesStatus(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := podIndexer.Add(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ndbmtd-0", Namespace: ns},
+		Status:     corev1.PodStatus{PodIP: "10.0.0.3"},
+	}); err != nil {
+		t.Fatalf("Unexpected error : %s", err)
+	}
+
+	sc := &SyncContext{
+		ndb: testutils.NewTestNdb(ns, "test", 2),
+		configSummary: &ndbconfig.ConfigSummary{
+			Nodes: []ndbconfig.NodeIdentity{
+				{NodeId: 1, NodeType: constants.NdbNodeTypeMgmd, PodName: "test-mgmd-0"},
+				{NodeId: 3, NodeType: constants.NdbNodeTypeNdbmtd, PodName: "test-ndbmtd-0"},
+			},
+		},
+		podLister: corelisters.NewPodLister(podIndexer),
+		logger:    klog.Background(),
+	}
+
+	// The pod IP should be set only for the pods that exist
+	expectedNodes := []v1.NdbClusterNodeStatus{
+		{NodeId: 1, NodeType: constants.NdbNodeTypeMgmd, PodName: "test-mgmd-0"},
+		{NodeId: 3, NodeType: constants.NdbNodeTypeNdbmtd, PodName: "test-ndbmtd-0", PodIP: "10.0.0.3"},
+	}
+	nodes := sc.getNodesStatus()
+	if !reflect.DeepEqual(nodes, expectedNodes) {
+		t.Errorf("Expected nodes status %#v but got %#v", expectedNodes, nodes)
+	}
+
+	// The previous status should be retained if the config summary is not available
+	sc.ndb.Status.Nodes = nodes
+	sc.configSummary = nil
+	if !reflect.DeepEqual(sc.getNodesStatus(), nodes) {
+		t.Error("Previous nodes status was not retained")
+	}
+}
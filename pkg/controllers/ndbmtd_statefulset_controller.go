@@ -15,6 +15,7 @@ import (
 	"github.com/mysql/ndb-operator/pkg/resources"
 	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	listerappsv1 "k8s.io/client-go/listers/apps/v1"
 	klog "k8s.io/klog/v2"
@@ -60,9 +61,9 @@ func (nssc *ndbmtdStatefulSetController) startNewDataNodes(ctx context.Context,
 }
 
 // createNodeGroups inducts the new data nodes into the MySQL Cluster by creating nodegroups on them.
-func (nssc *ndbmtdStatefulSetController) createNodeGroups(sc *SyncContext) syncResult {
+func (nssc *ndbmtdStatefulSetController) createNodeGroups(ctx context.Context, sc *SyncContext) syncResult {
 	// Connect to the Management Server
-	mgmClient, err := mgmapi.NewMgmClient(sc.ndb.GetConnectstring())
+	mgmClient, err := mgmapi.NewMgmClientWithContext(ctx, sc.ndb.GetConnectstring())
 	if err != nil {
 		klog.Errorf("Failed to connect to Management Server : %s", err)
 		return errorWhileProcessing(err)
@@ -121,7 +122,7 @@ func (nssc *ndbmtdStatefulSetController) reorgNdbTables(ctx context.Context, sc
 	}
 
 	// Connect to the 0th MySQL Pod to perform reorg partition and optimize
-	mysqlClient, err := mysqlclient.ConnectToStatefulSet(sc.mysqldSfset, "", operatorPassword)
+	mysqlClient, err := mysqlclient.ConnectToStatefulSet(ctx, sc.mysqldSfset, "", operatorPassword)
 	if err != nil {
 		return errorWhileProcessing(err)
 	}
@@ -133,6 +134,7 @@ func (nssc *ndbmtdStatefulSetController) reorgNdbTables(ctx context.Context, sc
 		klog.Errorf("Failed to execute query %q : %s", query, err)
 		return errorWhileProcessing(err)
 	}
+	defer rows.Close()
 
 	// Run reorg and optimize for all tables, one by one.
 	klog.Infof("Redistributing NDB data among all data nodes, including the new ones")
@@ -204,7 +206,7 @@ func (nssc *ndbmtdStatefulSetController) handleAddNodeOnline(ctx context.Context
 	}
 
 	// Create node groups
-	if sr := nssc.createNodeGroups(sc); sr.stopSync() {
+	if sr := nssc.createNodeGroups(ctx, sc); sr.stopSync() {
 		return sr
 	}
 
@@ -216,5 +218,11 @@ func (nssc *ndbmtdStatefulSetController) handleAddNodeOnline(ctx context.Context
 	// Delete AddNodeOnlineInProgress annotation as add node online procedure is now complete
 	updatedSfset := ndbmtdSfset.DeepCopy()
 	delete(updatedSfset.GetAnnotations(), AddNodeOnlineInProgress)
-	return nssc.patchStatefulSet(ctx, ndbmtdSfset, updatedSfset)
+	sr := nssc.patchStatefulSet(ctx, ndbmtdSfset, updatedSfset)
+	if sr.getError() == nil {
+		sc.recorder.Eventf(sc.ndb, nil, corev1.EventTypeNormal, ReasonDataNodesAdded, ActionScaleUp,
+			"MySQL Cluster now has %d data nodes and all the data has been redistributed",
+			sc.configSummary.NumOfDataNodes)
+	}
+	return sr
 }
@@ -0,0 +1,111 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	networkinglisterv1 "k8s.io/client-go/listers/networking/v1"
+	klog "k8s.io/klog/v2"
+)
+
+type NetworkPolicyControlInterface interface {
+	ReconcileNetworkPolicy(ctx context.Context, sc *SyncContext) syncResult
+}
+
+type networkPolicyImpl struct {
+	k8sClient           kubernetes.Interface
+	networkPolicyLister networkinglisterv1.NetworkPolicyLister
+	// operatorNamespace is the namespace the NDB Operator is running
+	// in. It is empty if the operator is running outside K8s.
+	operatorNamespace string
+	// operatorCIDR is the CIDR from which an operator
+	// running outside K8s connects to the MySQL Clusters.
+	operatorCIDR string
+}
+
+// newNetworkPolicyControl creates a new NetworkPolicyControlInterface
+func newNetworkPolicyControl(
+	client kubernetes.Interface,
+	networkPolicyLister networkinglisterv1.NetworkPolicyLister,
+	operatorNamespace, operatorCIDR string) NetworkPolicyControlInterface {
+	return &networkPolicyImpl{
+		k8sClient:           client,
+		networkPolicyLister: networkPolicyLister,
+		operatorNamespace:   operatorNamespace,
+		operatorCIDR:        operatorCIDR,
+	}
+}
+
+// ReconcileNetworkPolicy creates or updates the NetworkPolicy of the
+// MySQL Cluster if it is enabled, and deletes it if it is disabled.
+func (npi *networkPolicyImpl) ReconcileNetworkPolicy(ctx context.Context, sc *SyncContext) syncResult {
+
+	nc := sc.ndb
+	networkPolicyName := nc.GetNetworkPolicyName()
+	networkPolicyInterface := npi.k8sClient.NetworkingV1().NetworkPolicies(nc.Namespace)
+	existingNetworkPolicy, err := npi.networkPolicyLister.NetworkPolicies(nc.Namespace).Get(networkPolicyName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		// Error retrieving NetworkPolicy from the cache
+		klog.Errorf("Failed to retrieve NetworkPolicy %q : %s",
+			getNamespacedName2(nc.Namespace, networkPolicyName), err)
+		return errorWhileProcessing(err)
+	}
+
+	if existingNetworkPolicy != nil {
+		// NetworkPolicy exists. Verify that it is owned by the NdbCluster resource.
+		if err = sc.ensureOwnedByNdbCluster(ctx, existingNetworkPolicy); err != nil {
+			return errorWhileProcessing(err)
+		}
+	}
+
+	if !nc.NetworkPolicyEnabled() {
+		if existingNetworkPolicy == nil {
+			// NetworkPolicy is disabled and there is none to delete
+			return continueProcessing()
+		}
+
+		// NetworkPolicy has been disabled - delete the existing one
+		err = networkPolicyInterface.Delete(ctx, networkPolicyName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.Errorf("Failed to delete NetworkPolicy %q : %s", getNamespacedName(existingNetworkPolicy), err)
+			return errorWhileProcessing(err)
+		}
+		klog.Infof("Deleted NetworkPolicy %q", getNamespacedName(existingNetworkPolicy))
+		return continueProcessing()
+	}
+
+	networkPolicy := resources.NewNetworkPolicy(nc, npi.operatorNamespace, npi.operatorCIDR)
+	if existingNetworkPolicy != nil &&
+		equality.Semantic.DeepEqual(existingNetworkPolicy.Spec, networkPolicy.Spec) {
+		// NetworkPolicy is up-to-date
+		return continueProcessing()
+	}
+
+	// Create or update the NetworkPolicy
+	patch, err := newApplyPatch(networkPolicy, networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"))
+	if err != nil {
+		klog.Errorf("Failed to generate the apply patch for NetworkPolicy %q : %s",
+			getNamespacedName(networkPolicy), err)
+		return errorWhileProcessing(err)
+	}
+
+	if _, err = networkPolicyInterface.Patch(
+		ctx, networkPolicyName, types.ApplyPatchType, patch, applyPatchOptions()); err != nil {
+		klog.Errorf("Failed to apply the NetworkPolicy %q : %s", getNamespacedName(networkPolicy), err)
+		return errorWhileProcessing(err)
+	}
+
+	klog.Infof("NetworkPolicy %q has been applied successfully", getNamespacedName(networkPolicy))
+	return continueProcessing()
+}
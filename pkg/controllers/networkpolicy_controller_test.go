@@ -0,0 +1,268 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	networkinglisterv1 "k8s.io/client-go/listers/networking/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// networkPolicyRulePorts returns the port numbers allowed by the given ingress rule
+func networkPolicyRulePorts(t *testing.T, rule networkingv1.NetworkPolicyIngressRule) []int {
+	var ports []int
+	for _, port := range rule.Ports {
+		if port.Protocol == nil || *port.Protocol != corev1.ProtocolTCP || port.Port == nil {
+			t.Errorf("Unexpected NetworkPolicyPort : %v", port)
+			continue
+		}
+		ports = append(ports, port.Port.IntValue())
+	}
+	return ports
+}
+
+func Test_NewNetworkPolicy(t *testing.T) {
+	mysqlClients := []networkingv1.NetworkPolicyPeer{
+		{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "mysql-client"}}},
+	}
+	ndbAPIClients := []networkingv1.NetworkPolicyPeer{
+		{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/24"}},
+	}
+
+	for _, tc := range []struct {
+		desc              string
+		dataNodePort      int32
+		operatorNamespace string
+		operatorCIDR      string
+		// expectedRulePorts has the ports of the expected ingress rules, in order
+		expectedRulePorts [][]int
+	}{
+		{
+			desc:              "operator running inside K8s",
+			operatorNamespace: "ndb-operator",
+			operatorCIDR:      "192.168.0.0/16",
+			expectedRulePorts: [][]int{
+				{1186, 3306}, // cluster nodes
+				{1186, 3306}, // operator pods
+				{3306},       // MySQL clients
+				{1186},       // NDBAPI clients
+				{1186},       // NDBAPI applications
+			},
+		},
+		{
+			desc:         "operator running outside K8s",
+			dataNodePort: 11860,
+			operatorCIDR: "192.168.0.0/16",
+			expectedRulePorts: [][]int{
+				{1186, 11860, 3306},
+				{1186, 11860, 3306},
+				{3306},
+				{1186, 11860},
+				{1186, 11860},
+			},
+		},
+		{
+			desc: "operator running outside K8s without a CIDR",
+			expectedRulePorts: [][]int{
+				{1186, 3306},
+				{3306},
+				{1186},
+				{1186},
+			},
+		},
+	} {
+		nc := testutils.NewTestNdb("default", "example-ndb", 2)
+		nc.Spec.DataNode.ServerPort = tc.dataNodePort
+		nc.Spec.NetworkPolicy = &v1.NdbNetworkPolicySpec{
+			MySQLClients:  mysqlClients,
+			NdbAPIClients: ndbAPIClients,
+		}
+		nc.Spec.NdbAPIApplications = []v1.NdbAPIApplicationSpec{
+			{Name: "ndbapi-app", Namespace: "apps", NodeCount: 2},
+		}
+
+		np := resources.NewNetworkPolicy(nc, tc.operatorNamespace, tc.operatorCIDR)
+		if np.Name != nc.GetNetworkPolicyName() || np.Namespace != nc.Namespace {
+			t.Errorf("%s : unexpected NetworkPolicy name %q", tc.desc, getNamespacedName(np))
+		}
+		if !reflect.DeepEqual(np.Spec.PodSelector.MatchLabels, nc.GetLabels()) {
+			t.Errorf("%s : NetworkPolicy doesn't select the MySQL Cluster pods : %v",
+				tc.desc, np.Spec.PodSelector)
+		}
+		if !reflect.DeepEqual(np.Spec.PolicyTypes, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}) {
+			t.Errorf("%s : unexpected policy types : %v", tc.desc, np.Spec.PolicyTypes)
+		}
+
+		rules := np.Spec.Ingress
+		if len(rules) != len(tc.expectedRulePorts) {
+			t.Errorf("%s : expected %d ingress rules but got %d : %v",
+				tc.desc, len(tc.expectedRulePorts), len(rules), rules)
+			continue
+		}
+		for i, expectedPorts := range tc.expectedRulePorts {
+			if ports := networkPolicyRulePorts(t, rules[i]); !reflect.DeepEqual(ports, expectedPorts) {
+				t.Errorf("%s : ingress rule %d : expected ports %v but got %v", tc.desc, i, expectedPorts, ports)
+			}
+		}
+
+		// The first rule allows the traffic between the MySQL Cluster nodes
+		if peers := rules[0].From; len(peers) != 1 || peers[0].PodSelector == nil ||
+			!reflect.DeepEqual(peers[0].PodSelector.MatchLabels, nc.GetLabels()) {
+			t.Errorf("%s : unexpected cluster nodes peers : %v", tc.desc, peers)
+		}
+
+		// The second rule, if any, allows the traffic from the operator
+		operatorRuleIdx := 1
+		switch {
+		case tc.operatorNamespace != "":
+			peers := rules[1].From
+			if len(peers) != 1 || peers[0].NamespaceSelector == nil || peers[0].PodSelector == nil ||
+				peers[0].NamespaceSelector.MatchLabels[corev1.LabelMetadataName] != tc.operatorNamespace ||
+				peers[0].PodSelector.MatchLabels["app"] != "ndb-operator" || peers[0].IPBlock != nil {
+				t.Errorf("%s : unexpected operator peers : %v", tc.desc, peers)
+			}
+		case tc.operatorCIDR != "":
+			peers := rules[1].From
+			if len(peers) != 1 || peers[0].IPBlock == nil || peers[0].IPBlock.CIDR != tc.operatorCIDR {
+				t.Errorf("%s : unexpected operator peers : %v", tc.desc, peers)
+			}
+		default:
+			operatorRuleIdx = 0
+		}
+
+		// The clients are allowed as specified
+		if peers := rules[operatorRuleIdx+1].From; !reflect.DeepEqual(peers, mysqlClients) {
+			t.Errorf("%s : unexpected MySQL clients peers : %v", tc.desc, peers)
+		}
+		if peers := rules[operatorRuleIdx+2].From; !reflect.DeepEqual(peers, ndbAPIClients) {
+			t.Errorf("%s : unexpected NDBAPI clients peers : %v", tc.desc, peers)
+		}
+
+		// The pods of the NDBAPI applications are selected by their names
+		expectedAppPeers := []networkingv1.NetworkPolicyPeer{
+			{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{corev1.LabelMetadataName: "apps"},
+				},
+				PodSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      appsv1.StatefulSetPodNameLabel,
+							Operator: metav1.LabelSelectorOpIn,
+							Values:   []string{"ndbapi-app-0", "ndbapi-app-1"},
+						},
+					},
+				},
+			},
+		}
+		if peers := rules[operatorRuleIdx+3].From; !reflect.DeepEqual(peers, expectedAppPeers) {
+			t.Errorf("%s : unexpected NDBAPI application peers : %v", tc.desc, peers)
+		}
+	}
+
+	// No client rules are added when none are specified
+	nc := testutils.NewTestNdb("default", "example-ndb", 2)
+	nc.Spec.NetworkPolicy = &v1.NdbNetworkPolicySpec{}
+	if np := resources.NewNetworkPolicy(nc, "ndb-operator", ""); len(np.Spec.Ingress) != 2 {
+		t.Errorf("Expected only the cluster nodes and the operator rules but got : %v", np.Spec.Ingress)
+	}
+}
+
+func Test_ReconcileNetworkPolicy(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.UID = "ndb-uid"
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+	npIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	npi := newNetworkPolicyControl(
+		f.k8sclient, networkinglisterv1.NewNetworkPolicyLister(npIndexer), "ndb-operator", "")
+	npInterface := f.k8sclient.NetworkingV1().NetworkPolicies(ns)
+	npName := ndb.GetNetworkPolicyName()
+
+	// countPatches returns the number of patches sent for the NetworkPolicy
+	countPatches := func() int {
+		patches := 0
+		for _, action := range f.k8sclient.Actions() {
+			if action.Matches("patch", "networkpolicies") {
+				patches++
+			}
+		}
+		return patches
+	}
+
+	// No NetworkPolicy should be created when it is disabled
+	sc := f.c.newSyncContext(ctx, ndb)
+	if sr := npi.ReconcileNetworkPolicy(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	if _, err := npInterface.Get(ctx, npName, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("NetworkPolicy %q created when it is disabled : %v", npName, err)
+	}
+
+	// A missing NetworkPolicy should be created via server side apply
+	enabled := ndb.DeepCopy()
+	enabled.Spec.NetworkPolicy = &v1.NdbNetworkPolicySpec{}
+	sc = f.c.newSyncContext(ctx, enabled)
+	if sr := npi.ReconcileNetworkPolicy(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	np, err := npInterface.Get(ctx, npName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("NetworkPolicy %q was not created : %s", npName, err)
+	}
+	if countPatches() != 1 {
+		t.Errorf("NetworkPolicy %q not created via an apply", npName)
+	}
+
+	// An up-to-date NetworkPolicy should not be applied again
+	if err = npIndexer.Add(np); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if sr := npi.ReconcileNetworkPolicy(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	if countPatches() != 1 {
+		t.Errorf("Up-to-date NetworkPolicy %q was applied again", npName)
+	}
+
+	// The NetworkPolicy should be updated when the clients change
+	withClients := enabled.DeepCopy()
+	withClients.Spec.NetworkPolicy.MySQLClients = []networkingv1.NetworkPolicyPeer{
+		{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/24"}},
+	}
+	sc = f.c.newSyncContext(ctx, withClients)
+	if sr := npi.ReconcileNetworkPolicy(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	if countPatches() != 2 {
+		t.Errorf("NetworkPolicy %q was not updated when the clients changed", npName)
+	}
+
+	// The NetworkPolicy should be deleted when it is disabled
+	sc = f.c.newSyncContext(ctx, ndb)
+	if sr := npi.ReconcileNetworkPolicy(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	if _, err = npInterface.Get(ctx, npName, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("NetworkPolicy %q not deleted when it was disabled : %v", npName, err)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+
+	klog "k8s.io/klog/v2"
+)
+
+// nodeEventListenerRetryInterval is the time the nodeEventListener waits
+// before listening again once the connection to the Management Server
+// is lost, e.g. when the Management Node pod is restarted.
+const nodeEventListenerRetryInterval = 10 * time.Second
+
+// nodeEventListener listens to the node events of the MySQL Clusters from
+// their Management Servers and reconciles an NdbCluster whenever one of its
+// nodes fails, disconnects or connects, or an arbitration is run. This lets
+// the NdbClusterPartitioned condition reflect a network partition as soon
+// as it happens, rather than at the next periodic sync.
+type nodeEventListener struct {
+	// enqueue adds the NdbCluster with the given key to the workqueue
+	enqueue func(key string)
+
+	// activeListeners holds the cancel functions of the
+	// active listeners keyed by the NdbCluster key
+	activeListeners map[string]context.CancelFunc
+	// mutex protects the activeListeners map
+	mutex sync.Mutex
+}
+
+// newNodeEventListener creates a new nodeEventListener
+func newNodeEventListener(enqueue func(key string)) *nodeEventListener {
+	return &nodeEventListener{
+		enqueue:         enqueue,
+		activeListeners: make(map[string]context.CancelFunc),
+	}
+}
+
+// ensureListening starts listening to the node events of the
+// given NdbCluster, if they are not being listened to already.
+func (nel *nodeEventListener) ensureListening(nc *v1.NdbCluster) {
+	nel.mutex.Lock()
+	defer nel.mutex.Unlock()
+
+	key := getNdbClusterKey(nc)
+	if _, exists := nel.activeListeners[key]; exists {
+		// Node events are being listened to already
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	nel.activeListeners[key] = cancel
+	go nel.listen(ctx, key, nc.GetConnectstring())
+}
+
+// stopListening stops listening to the node events of the NdbCluster with the given key
+func (nel *nodeEventListener) stopListening(key string) {
+	nel.mutex.Lock()
+	defer nel.mutex.Unlock()
+
+	if cancel, exists := nel.activeListeners[key]; exists {
+		cancel()
+		delete(nel.activeListeners, key)
+	}
+}
+
+// stopAll stops listening to the node events of all the NdbClusters
+func (nel *nodeEventListener) stopAll() {
+	nel.mutex.Lock()
+	defer nel.mutex.Unlock()
+
+	for key, cancel := range nel.activeListeners {
+		cancel()
+		delete(nel.activeListeners, key)
+	}
+}
+
+// listen listens to the node events from the Management Servers at the
+// given connectstring, and enqueues the NdbCluster with the given key for
+// every event, until the ctx is cancelled. The connection is reopened
+// whenever it is lost.
+func (nel *nodeEventListener) listen(ctx context.Context, key, connectstring string) {
+	klog.Infof("Listening to the node events of NdbCluster %q", key)
+	for {
+		err := mgmapi.ListenNodeEvents(ctx, connectstring, func(event mgmapi.NodeEvent) {
+			klog.V(2).InfoS("MySQL Cluster node event", "ndbcluster", key,
+				"type", event.Type, "sourceNodeId", event.SourceNodeId, "details", event.Details)
+			nel.enqueue(key)
+		})
+		if ctx.Err() != nil {
+			// Stopped listening
+			return
+		}
+		if err != nil {
+			klog.Warningf("Failed to listen to the node events of NdbCluster %q : %s", key, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(nodeEventListenerRetryInterval):
+		}
+	}
+}
+
+// ensureNodeEventListening ensures that the node events of the MySQL
+// Cluster are being listened to, once its Management Servers are ready.
+func (sc *SyncContext) ensureNodeEventListening() {
+	if sc.mgmdNodeSfset == nil || !statefulsetReady(sc.mgmdNodeSfset) {
+		// The Management Servers are not ready yet
+		return
+	}
+
+	sc.nodeEventListener.ensureListening(sc.ndb)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func Test_nodeEventListener(t *testing.T) {
+	// Start a fake Management Server that sends a node event to the listener
+	mgmServer, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	defer mgmServer.Close()
+	go func() {
+		conn, err := mgmServer.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// Consume the listen event command
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() && scanner.Text() != "" {
+		}
+		_, _ = conn.Write([]byte("listen event\nresult: 0\n\n" +
+			"log event reply\ntype=1\ntime=1683713533\nsource_nodeid=1\nnode=3\n\n"))
+
+		// Keep the connection open until the listener is stopped
+		for scanner.Scan() {
+		}
+	}()
+
+	enqueued := make(chan string, 1)
+	nel := newNodeEventListener(func(key string) {
+		enqueued <- key
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	listenerDone := make(chan struct{})
+	go func() {
+		nel.listen(ctx, "default/test", mgmServer.Addr().String())
+		close(listenerDone)
+	}()
+
+	// The NdbCluster should be enqueued for the node event
+	select {
+	case key := <-enqueued:
+		if key != "default/test" {
+			t.Errorf("Expected the NdbCluster %q to be enqueued but got %q", "default/test", key)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("NdbCluster was not enqueued for the node event")
+	}
+
+	// The listener should stop once the ctx is cancelled
+	cancel()
+	select {
+	case <-listenerDone:
+	case <-time.After(5 * time.Second):
+		t.Error("Listener did not stop once the ctx was cancelled")
+	}
+}
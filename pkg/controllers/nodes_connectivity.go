@@ -0,0 +1,104 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// findDisconnectedNodes returns the node ids of the given nodes that are
+// not connected to the MySQL Cluster as per the clusterStatus. The
+// Management and the Data nodes are always expected to be connected,
+// but the MySQL Servers are expected to be connected only if their pods
+// are running, as the config declares more MySQL Servers than are run.
+func findDisconnectedNodes(clusterStatus mgmapi.ClusterStatus,
+	nodes []ndbconfig.NodeIdentity, podRunning func(podName string) bool) (disconnectedNodeIds []int) {
+	for _, node := range nodes {
+		if node.NodeType == constants.NdbNodeTypeMySQLD && !podRunning(node.PodName) {
+			// MySQL Server is not being run
+			continue
+		}
+
+		if nodeStatus, exists := clusterStatus[node.NodeId]; !exists || !nodeStatus.IsConnected {
+			disconnectedNodeIds = append(disconnectedNodeIds, node.NodeId)
+		}
+	}
+
+	return disconnectedNodeIds
+}
+
+// checkNodesConnectivity checks, using the given clusterStatus, if all
+// the MySQL Cluster nodes are started and connected, and sets the
+// NdbClusterNodesConnected condition accordingly. It is checked during
+// every sync, irrespective of whether the NdbCluster spec has been
+// applied, so that the condition always reflects the live state of the
+// MySQL Cluster. A Warning event is recorded when some nodes get
+// disconnected and a Normal event is recorded once they are all connected
+// again. The condition is not set until the MySQL Cluster has been
+// started for the first time.
+func (sc *SyncContext) checkNodesConnectivity(clusterStatus mgmapi.ClusterStatus, clusterStatusErr error) {
+	nc := sc.ndb
+	if sc.configSummary == nil || sc.mgmdNodeSfset == nil || nc.Status.ProcessedGeneration == 0 {
+		// The MySQL Cluster is yet to be started
+		return
+	}
+
+	nodesConnectedCondition := &v1.NdbClusterCondition{
+		Type:    v1.NdbClusterNodesConnected,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1.NdbClusterNodesConnectedReasonAllConnected,
+		Message: "All the MySQL Cluster nodes are connected",
+	}
+
+	if clusterStatusErr != nil {
+		nodesConnectedCondition.Status = corev1.ConditionFalse
+		nodesConnectedCondition.Reason = v1.NdbClusterNodesConnectedReasonMgmdUnreachable
+		nodesConnectedCondition.Message = fmt.Sprintf(
+			"Failed to retrieve the state of the MySQL Cluster nodes from the Management nodes : %s",
+			clusterStatusErr)
+	} else if disconnectedNodeIds := findDisconnectedNodes(
+		clusterStatus, sc.configSummary.Nodes, sc.isPodRunning); len(disconnectedNodeIds) != 0 {
+		nodesConnectedCondition.Status = corev1.ConditionFalse
+		nodesConnectedCondition.Reason = v1.NdbClusterNodesConnectedReasonNodesDisconnected
+		nodesConnectedCondition.Message = fmt.Sprintf(
+			"The MySQL Cluster nodes %v are not started or not connected", disconnectedNodeIds)
+	}
+
+	sc.updateCondition(nodesConnectedCondition, nc.GetNodesConnectedCondition(), conditionEvents{
+		failureStatus:  corev1.ConditionFalse,
+		failureReason:  ReasonNodesDisconnected,
+		recoveryReason: ReasonNodesReconnected,
+		renotify:       true,
+	})
+
+	sc.nodesConnectedCondition = nodesConnectedCondition
+}
+
+// getClusterStatus retrieves the state of all the
+// MySQL Cluster nodes from the Management nodes.
+func (sc *SyncContext) getClusterStatus(ctx context.Context) (mgmapi.ClusterStatus, error) {
+	mgmClient, err := mgmapi.NewMgmClientWithContext(ctx, sc.ndb.GetConnectstring())
+	if err != nil {
+		return nil, err
+	}
+	defer mgmClient.Disconnect()
+
+	return mgmClient.GetStatus()
+}
+
+// isPodRunning returns true if the pod with the given
+// name exists and is not being deleted.
+func (sc *SyncContext) isPodRunning(podName string) bool {
+	pod, err := sc.podLister.Pods(sc.ndb.Namespace).Get(podName)
+	return err == nil && pod.DeletionTimestamp == nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+)
+
+func Test_findDisconnectedNodes(t *testing.T) {
+	// 1 Management node, 2 data nodes and 2 MySQL Servers
+	nodes := []ndbconfig.NodeIdentity{
+		{NodeId: 1, NodeType: constants.NdbNodeTypeMgmd, PodName: "example-ndb-mgmd-0"},
+		{NodeId: 2, NodeType: constants.NdbNodeTypeNdbmtd, PodName: "example-ndb-ndbmtd-0"},
+		{NodeId: 3, NodeType: constants.NdbNodeTypeNdbmtd, PodName: "example-ndb-ndbmtd-1"},
+		{NodeId: 146, NodeType: constants.NdbNodeTypeMySQLD, PodName: "example-ndb-mysqld-0"},
+		{NodeId: 147, NodeType: constants.NdbNodeTypeMySQLD, PodName: "example-ndb-mysqld-1"},
+	}
+
+	// clusterStatus in which only the given nodes are connected
+	clusterStatusWithConnectedNodes := func(connectedNodeIds ...int) mgmapi.ClusterStatus {
+		clusterStatus := make(mgmapi.ClusterStatus)
+		for _, node := range nodes {
+			clusterStatus[node.NodeId] = &mgmapi.NodeStatus{NodeId: node.NodeId}
+		}
+		for _, nodeId := range connectedNodeIds {
+			clusterStatus[nodeId].IsConnected = true
+		}
+		return clusterStatus
+	}
+
+	for _, tc := range []struct {
+		desc                        string
+		clusterStatus               mgmapi.ClusterStatus
+		runningPods                 []string
+		expectedDisconnectedNodeIds []int
+	}{
+		{
+			desc:          "all nodes connected",
+			clusterStatus: clusterStatusWithConnectedNodes(1, 2, 3, 146, 147),
+			runningPods:   []string{"example-ndb-mysqld-0", "example-ndb-mysqld-1"},
+		},
+		{
+			desc:          "MySQL Servers that are not run are not reported",
+			clusterStatus: clusterStatusWithConnectedNodes(1, 2, 3, 146),
+			runningPods:   []string{"example-ndb-mysqld-0"},
+		},
+		{
+			desc:                        "data node and MySQL Server not connected",
+			clusterStatus:               clusterStatusWithConnectedNodes(1, 2, 146),
+			runningPods:                 []string{"example-ndb-mysqld-0", "example-ndb-mysqld-1"},
+			expectedDisconnectedNodeIds: []int{3, 147},
+		},
+		{
+			desc:                        "nodes missing from the cluster status",
+			clusterStatus:               mgmapi.ClusterStatus{},
+			expectedDisconnectedNodeIds: []int{1, 2, 3},
+		},
+	} {
+		podRunning := func(podName string) bool {
+			for _, runningPod := range tc.runningPods {
+				if runningPod == podName {
+					return true
+				}
+			}
+			return false
+		}
+
+		disconnectedNodeIds := findDisconnectedNodes(tc.clusterStatus, nodes, podRunning)
+		if !reflect.DeepEqual(disconnectedNodeIds, tc.expectedDisconnectedNodeIds) {
+			t.Errorf("Testcase %q failed : got disconnected nodes %v, expected %v",
+				tc.desc, disconnectedNodeIds, tc.expectedDisconnectedNodeIds)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	klog "k8s.io/klog/v2"
+)
+
+// operatorStatusPublishInterval is the interval at which
+// the operator status is published to the NdbOperator resource
+const operatorStatusPublishInterval = time.Minute
+
+// OperatorInfo has the details of the running
+// operator that are published to the NdbOperator
+type OperatorInfo struct {
+	// Name of the NdbOperator resource
+	Name string
+	// Build version of the operator
+	Version string
+	// Identity of the operator instance running the controller
+	Identity string
+	// ClusterScoped and WatchNamespace are the scope of the operator
+	ClusterScoped  bool
+	WatchNamespace string
+	// EnabledFeatures are the optional features enabled in the operator
+	EnabledFeatures []string
+}
+
+// PublishOperatorStatus periodically publishes the status of the operator,
+// including the summary of the NdbClusters it manages, to the cluster-scoped
+// NdbOperator resource with the name info.Name. The resource is created if
+// it does not exist. It blocks until ctx is cancelled. The status is not
+// published until the controller is ready, so that the summary is not
+// computed from partially synced informer caches.
+func (c *Controller) PublishOperatorStatus(ctx context.Context, info OperatorInfo) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if !c.IsReady() {
+			return
+		}
+
+		if err := c.publishOperatorStatus(ctx, info); err != nil {
+			klog.Errorf("Failed to publish the operator status to NdbOperator %q : %s", info.Name, err)
+		}
+	}, operatorStatusPublishInterval)
+}
+
+// publishOperatorStatus publishes the current status of the operator to the NdbOperator
+func (c *Controller) publishOperatorStatus(ctx context.Context, info OperatorInfo) error {
+	ndbOperators := c.ndbClient.MysqlV1().NdbOperators()
+	ndbOperator, err := ndbOperators.Get(ctx, info.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		ndbOperator, err = ndbOperators.Create(ctx, &v1.NdbOperator{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: info.Name,
+			},
+		}, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	status, err := c.getOperatorStatus(info)
+	if err != nil {
+		return err
+	}
+
+	ndbOperator = ndbOperator.DeepCopy()
+	ndbOperator.Status = *status
+	_, err = ndbOperators.UpdateStatus(ctx, ndbOperator, metav1.UpdateOptions{})
+	return err
+}
+
+// getOperatorStatus returns the NdbOperatorStatus with the
+// given info and the summary of the NdbClusters being managed
+func (c *Controller) getOperatorStatus(info OperatorInfo) (*v1.NdbOperatorStatus, error) {
+	ndbClusters, err := c.ndbsLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	// Summarise the NdbClusters per namespace
+	namespaceStatuses := make(map[string]*v1.NdbOperatorNamespaceStatus)
+	for _, nc := range ndbClusters {
+		namespaceStatus, exists := namespaceStatuses[nc.Namespace]
+		if !exists {
+			namespaceStatus = &v1.NdbOperatorNamespaceStatus{Namespace: nc.Namespace}
+			namespaceStatuses[nc.Namespace] = namespaceStatus
+		}
+
+		namespaceStatus.NdbClusters++
+		if upToDate := nc.GetUpToDateCondition(); upToDate != nil && upToDate.Status == corev1.ConditionTrue {
+			namespaceStatus.UpToDateNdbClusters++
+		}
+	}
+
+	status := &v1.NdbOperatorStatus{
+		Version:         info.Version,
+		LeaderIdentity:  info.Identity,
+		ClusterScoped:   info.ClusterScoped,
+		WatchNamespace:  info.WatchNamespace,
+		EnabledFeatures: append(append([]string(nil), info.EnabledFeatures...), c.getEnabledAPIFeatures()...),
+		LastUpdateTime:  metav1.Now(),
+	}
+	sort.Strings(status.EnabledFeatures)
+
+	for _, namespaceStatus := range namespaceStatuses {
+		status.Namespaces = append(status.Namespaces, *namespaceStatus)
+	}
+	sort.Slice(status.Namespaces, func(i, j int) bool {
+		return status.Namespaces[i].Namespace < status.Namespaces[j].Namespace
+	})
+
+	return status, nil
+}
+
+// getEnabledAPIFeatures returns the features of the operator that
+// are enabled as the K8s Server supports the APIs they depend on
+func (c *Controller) getEnabledAPIFeatures() (features []string) {
+	if c.pdbController != nil {
+		features = append(features, "PodDisruptionBudgets")
+	}
+	if c.hpaController != nil {
+		features = append(features, "HorizontalPodAutoscalers")
+	}
+	if c.gatewayController != nil {
+		features = append(features, "GatewayAPI")
+	}
+	return features
+}
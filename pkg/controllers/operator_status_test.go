@@ -0,0 +1,77 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_publishOperatorStatus(t *testing.T) {
+	upToDateNdb := testutils.NewTestNdb("ns-a", "up-to-date", 2)
+	upToDateNdb.Status.Conditions = []v1.NdbClusterCondition{{
+		Type:   v1.NdbClusterUpToDate,
+		Status: corev1.ConditionTrue,
+	}}
+	f := newFixture(t,
+		upToDateNdb,
+		testutils.NewTestNdb("ns-a", "being-created", 2),
+		testutils.NewTestNdb("ns-b", "test", 2))
+	defer f.close()
+	f.newController()
+
+	info := OperatorInfo{
+		Name:            "ndb-operator",
+		Version:         "1.0.0",
+		Identity:        "ndb-operator-pod",
+		ClusterScoped:   true,
+		EnabledFeatures: []string{"Pprof"},
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		// The first publish creates the NdbOperator and the next one updates it
+		if err := f.c.publishOperatorStatus(ctx, info); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+
+	ndbOperator, err := f.ndbclient.MysqlV1().NdbOperators().Get(ctx, info.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	status := ndbOperator.Status
+	if status.Version != info.Version || status.LeaderIdentity != info.Identity || !status.ClusterScoped {
+		t.Errorf("Unexpected operator details in the published status : %#v", status)
+	}
+
+	if status.LastUpdateTime.IsZero() {
+		t.Error("LastUpdateTime is not set in the published status")
+	}
+
+	pprofEnabled := false
+	for _, feature := range status.EnabledFeatures {
+		pprofEnabled = pprofEnabled || feature == "Pprof"
+	}
+	if !pprofEnabled {
+		t.Errorf("Expected the enabled features to include Pprof but got %v", status.EnabledFeatures)
+	}
+
+	expectedNamespaces := []v1.NdbOperatorNamespaceStatus{
+		{Namespace: "ns-a", NdbClusters: 2, UpToDateNdbClusters: 1},
+		{Namespace: "ns-b", NdbClusters: 1, UpToDateNdbClusters: 0},
+	}
+	if !reflect.DeepEqual(status.Namespaces, expectedNamespaces) {
+		t.Errorf("Expected the namespace summary %v but got %v", expectedNamespaces, status.Namespaces)
+	}
+}
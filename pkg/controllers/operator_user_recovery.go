@@ -0,0 +1,154 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// operatorPasswordRecheckDelay is the delay after which the MySQL Servers
+// are checked again once a new ndb operator password has been applied.
+const operatorPasswordRecheckDelay = 5 * time.Second
+
+// patchOperatorUserRecoveryKey sets the NDBOperatorUserRecoveryKey in the
+// ndb operator password secret to the given value, or removes it if the
+// value is nil.
+func (sc *SyncContext) patchOperatorUserRecoveryKey(
+	ctx context.Context, operatorSecret *corev1.Secret, value []byte) error {
+	return sc.patchOperatorSecretData(ctx, operatorSecret, map[string]interface{}{
+		resources.NDBOperatorUserRecoveryKey: value,
+	})
+}
+
+// patchOperatorSecretData sets the given keys in the ndb operator password
+// secret to the given values, or removes the keys whose values are nil.
+func (sc *SyncContext) patchOperatorSecretData(
+	ctx context.Context, operatorSecret *corev1.Secret, data map[string]interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"data": data,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err = sc.kubeClientset().CoreV1().Secrets(operatorSecret.Namespace).Patch(
+		ctx, operatorSecret.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		sc.logger.Error(err, "Failed to patch the ndb operator password secret", "secret", operatorSecret.Name)
+		return err
+	}
+
+	return nil
+}
+
+// recoverOperatorUser recovers the ndb operator user that has been rejected
+// by the MySQL Servers, which happens when the user has been lost or
+// overwritten by a restore. The recovery is requested by setting the
+// NDBOperatorUserRecoveryKey in the ndb operator password secret and
+// restarting the first MySQL Server, whose init container then recreates the
+// user through the local root user, over the MySQL Server's socket. If the
+// user is still rejected after the restart, the failure is reported in the
+// NdbCluster status and the recovery is not retried.
+func (sc *SyncContext) recoverOperatorUser(ctx context.Context, operatorSecret *corev1.Secret) syncResult {
+	nc := sc.ndb
+	mysqldSfset := sc.mysqldSfset
+	podName := mysqldSfset.Name + "-0"
+
+	requestTime := time.Now().Truncate(time.Second)
+	if requestedAt, requested := operatorSecret.Data[resources.NDBOperatorUserRecoveryKey]; requested {
+		// Recovery has already been requested
+		var err error
+		if requestTime, err = time.Parse(time.RFC3339, string(requestedAt)); err != nil {
+			sc.logger.Error(err, "Failed to parse the ndb operator user recovery request time")
+			return errorWhileProcessing(err)
+		}
+	} else {
+		// Request the recovery of the ndb operator user
+		if err := sc.patchOperatorUserRecoveryKey(
+			ctx, operatorSecret, []byte(requestTime.Format(time.RFC3339))); err != nil {
+			return errorWhileProcessing(err)
+		}
+		sc.logger.Info("Requested the recovery of the ndb operator user", "pod", podName)
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeWarning, ReasonOperatorUserRecovering, ActionRestart,
+			"MySQL Servers rejected the ndb operator user. Restarting MySQL Server %q to recover it", podName)
+	}
+
+	// Check the MySQL Server through which the user is recovered
+	sc.requeueAfter = mysqldHealthRecheckInterval
+	pod, err := sc.podLister.Pods(mysqldSfset.Namespace).Get(podName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Pod is being recreated
+			return finishProcessing()
+		}
+		sc.logger.Error(err, "Failed to retrieve the MySQL Server pod", "pod", podName)
+		return errorWhileProcessing(err)
+	}
+
+	if pod.CreationTimestamp.Time.Before(requestTime) {
+		// Pod has not been restarted since the recovery was requested
+		if pod.DeletionTimestamp == nil {
+			// Restart the pod to recover the user from its init container
+			if err = sc.kubeClientset().CoreV1().Pods(pod.Namespace).Delete(
+				ctx, podName, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				sc.logger.Error(err, "Failed to delete the MySQL Server pod", "pod", podName)
+				return errorWhileProcessing(err)
+			}
+		}
+		return finishProcessing()
+	}
+
+	// The pod has been restarted, but the user is still being rejected
+	errMsg := fmt.Sprintf("failed to recover the ndb operator user via MySQL Server %q. "+
+		"Verify that its local root user accepts the password in the root password secret", podName)
+	sc.recorder.Eventf(nc, nil, corev1.EventTypeWarning, ReasonOperatorUserRecoveryFailed, ActionNone, "%s", errMsg)
+	sc.workloadErrors = append(sc.workloadErrors, errMsg)
+	return finishProcessing()
+}
+
+// completeOperatorUserRecovery removes the NDBOperatorUserRecoveryKey from
+// the ndb operator password secret once the recovered ndb operator user has
+// been accepted by the MySQL Servers.
+func (sc *SyncContext) completeOperatorUserRecovery(ctx context.Context, operatorSecret *corev1.Secret) error {
+	if err := sc.patchOperatorUserRecoveryKey(ctx, operatorSecret, nil); err != nil {
+		return err
+	}
+
+	sc.logger.Info("The ndb operator user has been recovered")
+	sc.recorder.Eventf(sc.ndb, nil, corev1.EventTypeNormal, ReasonOperatorUserRecovered, ActionSynced,
+		"The ndb operator user has been recovered")
+	return nil
+}
+
+// updateOperatorPassword applies the password changed in the ndb operator
+// password secret to the ndb operator user accounts, by connecting to the
+// MySQL Servers with the previous password. The MySQL Cluster nodes started
+// after the change pick up the new password from the secret. The sync is
+// requeued to check the MySQL Servers again with the new password.
+func (sc *SyncContext) updateOperatorPassword(
+	ctx context.Context, operatorSecret *corev1.Secret, previousPassword string) syncResult {
+	newPassword := string(operatorSecret.Data[corev1.BasicAuthPasswordKey])
+	if err := mysqlclient.UpdateNdbOperatorUserPassword(
+		ctx, sc.mysqldSfset, previousPassword, newPassword); err != nil {
+		sc.logger.Error(err, "Failed to update the password of the ndb operator user")
+		return errorWhileProcessing(err)
+	}
+
+	sc.logger.Info("Updated the password of the ndb operator user", "secret", operatorSecret.Name)
+	sc.recorder.Eventf(sc.ndb, nil, corev1.EventTypeNormal, ReasonOperatorPasswordUpdated, ActionUpdated,
+		"The ndb operator user password was updated from the Secret %q", operatorSecret.Name)
+	sc.requeueAfter = operatorPasswordRecheckDelay
+	return finishProcessing()
+}
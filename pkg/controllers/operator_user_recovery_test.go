@@ -0,0 +1,106 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_recoverOperatorUser(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+	secretInterface := f.k8sclient.CoreV1().Secrets(ns)
+	podInterface := f.k8sclient.CoreV1().Pods(ns)
+	podIndexer := f.k8sIf.Core().V1().Pods().Informer().GetIndexer()
+
+	operatorSecret, err := secretInterface.Create(ctx, resources.NewMySQLNDBOperatorPasswordSecret(ndb), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	// createPod creates the first MySQL Server pod with the given creation time
+	createPod := func(creationTime time.Time) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "test-mysqld-0",
+				Namespace:         ns,
+				CreationTimestamp: metav1.NewTime(creationTime),
+			},
+		}
+		if _, err = podInterface.Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+		if err = podIndexer.Add(pod); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+	createPod(time.Now().Add(-time.Hour))
+
+	replicas := int32(2)
+	mysqldSfset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-mysqld", Namespace: ns},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	}
+
+	// The recovery should be requested and the first MySQL Server restarted
+	sc := f.c.newSyncContext(ctx, ndb)
+	sc.mysqldSfset = mysqldSfset
+	if sr := sc.recoverOperatorUser(ctx, operatorSecret); !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error, error : %v", sr.getError())
+	}
+	if operatorSecret, err = secretInterface.Get(ctx, operatorSecret.Name, metav1.GetOptions{}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if _, requested := operatorSecret.Data[resources.NDBOperatorUserRecoveryKey]; !requested {
+		t.Fatal("Recovery of the ndb operator user was not requested")
+	}
+	if _, err = podInterface.Get(ctx, "test-mysqld-0", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Fatal("MySQL Server pod was not restarted :", err)
+	}
+
+	// The user being rejected after the restart should be reported
+	if err = podIndexer.Delete(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-mysqld-0", Namespace: ns}}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	createPod(time.Now().Add(time.Second))
+	sc = f.c.newSyncContext(ctx, ndb)
+	sc.mysqldSfset = mysqldSfset
+	if sr := sc.recoverOperatorUser(ctx, operatorSecret); !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error, error : %v", sr.getError())
+	}
+	if len(sc.workloadErrors) != 1 {
+		t.Errorf("Failed recovery not reported : %v", sc.workloadErrors)
+	}
+
+	// The recovery request should be removed once the user is accepted
+	if err = sc.completeOperatorUserRecovery(ctx, operatorSecret); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if operatorSecret, err = secretInterface.Get(ctx, operatorSecret.Name, metav1.GetOptions{}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if _, requested := operatorSecret.Data[resources.NDBOperatorUserRecoveryKey]; requested {
+		t.Error("Recovery request was not removed from the secret")
+	}
+	if len(operatorSecret.Data[corev1.BasicAuthPasswordKey]) == 0 {
+		t.Error("Password removed from the secret")
+	}
+}
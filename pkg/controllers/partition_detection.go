@@ -0,0 +1,157 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"fmt"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// findDisconnectedDataNodes returns the node ids of the started data nodes
+// that are not connected to the MySQL Cluster as per the clusterStatus, and
+// the node groups in which none of the data nodes are connected. The data
+// nodes that are being restarted, either by the operator or on their own,
+// are not considered disconnected. The node groups are identified by their
+// position, as the operator assigns them in the order of the node ids,
+// starting from firstDataNodeId.
+func findDisconnectedDataNodes(
+	clusterStatus mgmapi.ClusterStatus, startedNodeIds []int,
+	firstDataNodeId, numOfDataNodes, redundancyLevel int) (disconnectedNodeIds []int, lostNodeGroups []int) {
+
+	isConnected := func(nodeId int) bool {
+		nodeStatus, exists := clusterStatus[nodeId]
+		return exists && nodeStatus.IsConnected
+	}
+
+	isRestarting := func(nodeId int) bool {
+		nodeStatus, exists := clusterStatus[nodeId]
+		return exists && nodeStatus.IsRestarting()
+	}
+
+	disconnected := make(map[int]bool)
+	for _, nodeId := range startedNodeIds {
+		if !isConnected(nodeId) && !isRestarting(nodeId) {
+			disconnectedNodeIds = append(disconnectedNodeIds, nodeId)
+			disconnected[nodeId] = true
+		}
+	}
+
+	if len(disconnectedNodeIds) == 0 {
+		// All started data nodes are connected
+		return nil, nil
+	}
+
+	// Look for node groups that have lost all their data nodes
+	for nodeGroup := 0; nodeGroup*redundancyLevel < numOfDataNodes; nodeGroup++ {
+		nodeGroupConnected, nodeGroupDisconnected := false, false
+		for nodeId := firstDataNodeId + nodeGroup*redundancyLevel; nodeId < firstDataNodeId+(nodeGroup+1)*redundancyLevel; nodeId++ {
+			// A restarting data node will rejoin its node group
+			nodeGroupConnected = nodeGroupConnected || isConnected(nodeId) || isRestarting(nodeId)
+			nodeGroupDisconnected = nodeGroupDisconnected || disconnected[nodeId]
+		}
+
+		if !nodeGroupConnected && nodeGroupDisconnected {
+			lostNodeGroups = append(lostNodeGroups, nodeGroup)
+		}
+	}
+
+	return disconnectedNodeIds, lostNodeGroups
+}
+
+// getStartedDataNodeIds returns the node ids of the data nodes whose
+// containers have completed their startup and are still running. Such data
+// nodes are expected to be connected to the MySQL Cluster, unlike the ones
+// being started or restarted by the operator.
+func (sc *SyncContext) getStartedDataNodeIds() ([]int, error) {
+	ndbmtdSfset := sc.dataNodeSfSet
+	var startedNodeIds []int
+	for podOrdinal := 0; podOrdinal < int(*ndbmtdSfset.Spec.Replicas); podOrdinal++ {
+		podName := fmt.Sprintf("%s-%d", ndbmtdSfset.Name, podOrdinal)
+		pod, err := sc.podLister.Pods(ndbmtdSfset.Namespace).Get(podName)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// Pod is yet to be created by the StatefulSet controller
+				continue
+			}
+			return nil, err
+		}
+
+		if pod.DeletionTimestamp != nil {
+			// Pod is being deleted
+			continue
+		}
+
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.Name == statefulset.GetDataNodeContainerName() &&
+				containerStatus.State.Running != nil &&
+				containerStatus.Started != nil && *containerStatus.Started {
+				startedNodeIds = append(startedNodeIds, sc.getDataNodeId(podName))
+			}
+		}
+	}
+
+	return startedNodeIds, nil
+}
+
+// detectPartitioning checks, using the given clusterStatus, if any of the
+// started data nodes have lost their connection to the MySQL Cluster, and
+// sets the NdbClusterPartitioned condition accordingly. A Warning event is
+// recorded when a partition is detected and a Normal event is recorded
+// once it is resolved. The condition is left unchanged if the cluster
+// status could not be retrieved, i.e. if clusterStatusErr is not nil.
+func (sc *SyncContext) detectPartitioning(clusterStatus mgmapi.ClusterStatus, clusterStatusErr error) {
+	nc := sc.ndb
+	if sc.dataNodeSfSet == nil || !statefulsetReady(sc.mgmdNodeSfset) || clusterStatusErr != nil {
+		// The MySQL Cluster is yet to be started or
+		// the Management Server is not reachable
+		return
+	}
+
+	startedNodeIds, err := sc.getStartedDataNodeIds()
+	if err != nil {
+		sc.logger.Error(err, "Failed to retrieve the data node pods")
+		return
+	}
+
+	disconnectedNodeIds, lostNodeGroups := findDisconnectedDataNodes(
+		clusterStatus, startedNodeIds, sc.configSummary.GetFirstDataNodeId(),
+		int(*sc.dataNodeSfSet.Spec.Replicas), int(sc.configSummary.RedundancyLevel))
+
+	partitionedCondition := &v1.NdbClusterCondition{
+		Type:    v1.NdbClusterPartitioned,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1.NdbClusterPartitionedReasonConnected,
+		Message: "All the started data nodes are connected to the MySQL Cluster",
+	}
+	if len(lostNodeGroups) != 0 {
+		partitionedCondition.Status = corev1.ConditionTrue
+		partitionedCondition.Reason = v1.NdbClusterPartitionedReasonArbitrationLost
+		partitionedCondition.Message = fmt.Sprintf(
+			"All the data nodes of the node groups %v have lost their connection to the MySQL Cluster, "+
+				"possibly due to a network partition and a failed arbitration (disconnected data nodes : %v)",
+			lostNodeGroups, disconnectedNodeIds)
+	} else if len(disconnectedNodeIds) != 0 {
+		partitionedCondition.Status = corev1.ConditionTrue
+		partitionedCondition.Reason = v1.NdbClusterPartitionedReasonDisconnected
+		partitionedCondition.Message = fmt.Sprintf(
+			"The data nodes %v have lost their connection to the MySQL Cluster, possibly due to a network partition",
+			disconnectedNodeIds)
+	}
+
+	sc.updateCondition(partitionedCondition, nc.GetPartitionedCondition(), conditionEvents{
+		failureStatus:  corev1.ConditionTrue,
+		failureReason:  ReasonClusterPartitioned,
+		recoveryReason: ReasonPartitionResolved,
+		renotify:       true,
+	})
+
+	sc.partitionedCondition = partitionedCondition
+}
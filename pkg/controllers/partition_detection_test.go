@@ -0,0 +1,99 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+)
+
+func Test_findDisconnectedDataNodes(t *testing.T) {
+	// clusterStatus with 4 data nodes, nodeIds 2 to 5,
+	// where the given nodes are connected
+	clusterStatusWithConnectedNodes := func(connectedNodeIds ...int) mgmapi.ClusterStatus {
+		clusterStatus := make(mgmapi.ClusterStatus)
+		for nodeId := 2; nodeId <= 5; nodeId++ {
+			clusterStatus[nodeId] = &mgmapi.NodeStatus{
+				NodeType: mgmapi.NodeTypeNDB,
+				NodeId:   nodeId,
+			}
+		}
+		for _, nodeId := range connectedNodeIds {
+			clusterStatus[nodeId].IsConnected = true
+		}
+		return clusterStatus
+	}
+
+	// clusterStatus with the given nodes restarting and the rest connected
+	clusterStatusWithRestartingNodes := func(restartingNodeIds ...int) mgmapi.ClusterStatus {
+		clusterStatus := clusterStatusWithConnectedNodes(2, 3, 4, 5)
+		for _, nodeId := range restartingNodeIds {
+			clusterStatus[nodeId].IsConnected = false
+			clusterStatus[nodeId].Status = "RESTARTING"
+		}
+		return clusterStatus
+	}
+
+	for _, tc := range []struct {
+		desc                        string
+		clusterStatus               mgmapi.ClusterStatus
+		startedNodeIds              []int
+		expectedDisconnectedNodeIds []int
+		expectedLostNodeGroups      []int
+	}{
+		{
+			desc:           "all data nodes connected",
+			clusterStatus:  clusterStatusWithConnectedNodes(2, 3, 4, 5),
+			startedNodeIds: []int{2, 3, 4, 5},
+		},
+		{
+			desc:           "data nodes being started are not reported",
+			clusterStatus:  clusterStatusWithConnectedNodes(2, 4),
+			startedNodeIds: []int{2, 4},
+		},
+		{
+			desc:                        "started data node disconnected",
+			clusterStatus:               clusterStatusWithConnectedNodes(2, 3, 4),
+			startedNodeIds:              []int{2, 3, 4, 5},
+			expectedDisconnectedNodeIds: []int{5},
+		},
+		{
+			desc:                        "all data nodes of a node group disconnected",
+			clusterStatus:               clusterStatusWithConnectedNodes(2, 3),
+			startedNodeIds:              []int{2, 3, 4, 5},
+			expectedDisconnectedNodeIds: []int{4, 5},
+			expectedLostNodeGroups:      []int{1},
+		},
+		{
+			desc:           "restarting data node not reported",
+			clusterStatus:  clusterStatusWithRestartingNodes(5),
+			startedNodeIds: []int{2, 3, 4, 5},
+		},
+		{
+			desc:           "node group with a restarting data node not lost",
+			clusterStatus:  clusterStatusWithRestartingNodes(4, 5),
+			startedNodeIds: []int{2, 3, 4, 5},
+		},
+		{
+			desc: "node group with a disconnected and a restarting data node not lost",
+			clusterStatus: func() mgmapi.ClusterStatus {
+				clusterStatus := clusterStatusWithRestartingNodes(5)
+				clusterStatus[4].IsConnected = false
+				return clusterStatus
+			}(),
+			startedNodeIds:              []int{2, 3, 4, 5},
+			expectedDisconnectedNodeIds: []int{4},
+		},
+	} {
+		disconnectedNodeIds, lostNodeGroups := findDisconnectedDataNodes(tc.clusterStatus, tc.startedNodeIds, 2, 4, 2)
+		if !reflect.DeepEqual(disconnectedNodeIds, tc.expectedDisconnectedNodeIds) ||
+			!reflect.DeepEqual(lostNodeGroups, tc.expectedLostNodeGroups) {
+			t.Errorf("Testcase %q failed : got disconnected nodes %v and lost node groups %v, expected %v and %v",
+				tc.desc, disconnectedNodeIds, lostNodeGroups, tc.expectedDisconnectedNodeIds, tc.expectedLostNodeGroups)
+		}
+	}
+}
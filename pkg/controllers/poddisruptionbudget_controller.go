@@ -6,33 +6,47 @@ package controllers
 
 import (
 	"context"
-	"strconv"
 
+	"github.com/mysql/ndb-operator/pkg/constants"
 	"github.com/mysql/ndb-operator/pkg/resources"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	policylisterv1 "k8s.io/client-go/listers/policy/v1"
 	klog "k8s.io/klog/v2"
 )
 
-func ServerSupportsV1Policy(client kubernetes.Interface) bool {
-	info, err := client.Discovery().ServerVersion()
-	if err != nil {
-		klog.Warning(
-			"Cannot use PodDisruptionBudgets as operator is unable to detect connected K8s server version :", err)
-		return false
-	}
+// ServerPodDisruptionBudgetGroupVersion returns the policy API version via
+// which the K8s Server serves the PodDisruptionBudgets. The policy/v1 API is
+// preferred and the policy/v1beta1 API is used as a fallback for the older
+// K8s Servers. An empty GroupVersion is returned if neither of them is served.
+func ServerPodDisruptionBudgetGroupVersion(client kubernetes.Interface) schema.GroupVersion {
+	for _, gv := range []schema.GroupVersion{
+		policyv1.SchemeGroupVersion,
+		policyv1beta1.SchemeGroupVersion,
+	} {
+		resourceList, err := client.Discovery().ServerResourcesForGroupVersion(gv.String())
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				klog.Warningf("Failed to discover the resources served by %s : %s", gv.String(), err)
+			}
+			continue
+		}
 
-	majorVersion, _ := strconv.Atoi(info.Major)
-	minorVersion, _ := strconv.Atoi(info.Minor)
-	if majorVersion == 1 && minorVersion < 25 && minorVersion >= 19 {
-		return true
+		for _, resource := range resourceList.APIResources {
+			if resource.Name == "poddisruptionbudgets" {
+				return gv
+			}
+		}
 	}
 
-	klog.Warningf(
-		"Cannot use PodDisruptionBudgets as K8s Server version %q doesn't support v1/policy", info.String())
-	return false
+	klog.Warning("Cannot use PodDisruptionBudgets as K8s Server doesn't support policy/v1 or policy/v1beta1")
+	return schema.GroupVersion{}
 }
 
 type PodDisruptionBudgetControlInterface interface {
@@ -55,8 +69,50 @@ func newPodDisruptionBudgetControl(
 	}
 }
 
-// EnsurePodDisruptionBudget creates a new PodDisruptionBudget
-// for the given node type if one doesn't exist yet.
+// pdbLimitsEqual checks if the MinAvailable and MaxUnavailable values of the PDB specs are equal
+func pdbLimitsEqual(existingSpec, newSpec *policyv1.PodDisruptionBudgetSpec) bool {
+	return equality.Semantic.DeepEqual(existingSpec.MinAvailable, newSpec.MinAvailable) &&
+		equality.Semantic.DeepEqual(existingSpec.MaxUnavailable, newSpec.MaxUnavailable)
+}
+
+// applyPodDisruptionBudget updates the given PodDisruptionBudget via server side apply
+func (pdbi *podDisruptionBudgetImpl) applyPodDisruptionBudget(
+	ctx context.Context, pdb *policyv1.PodDisruptionBudget) error {
+	patch, err := newApplyPatch(pdb, policyv1.SchemeGroupVersion.WithKind("PodDisruptionBudget"))
+	if err != nil {
+		klog.Errorf("Failed to generate the apply patch for PDB %q : %s", getNamespacedName(pdb), err)
+		return err
+	}
+
+	pdbInterface := pdbi.k8sClient.PolicyV1().PodDisruptionBudgets(pdb.Namespace)
+	if _, err = pdbInterface.Patch(
+		ctx, pdb.Name, types.ApplyPatchType, patch, applyPatchOptions()); err != nil {
+		klog.Errorf("Failed to apply the PDB %q : %s", getNamespacedName(pdb), err)
+		return err
+	}
+
+	klog.Infof("PodDisruptionBudget %q has been updated successfully", getNamespacedName(pdb))
+	return nil
+}
+
+// deletePodDisruptionBudget deletes the given PodDisruptionBudget
+func (pdbi *podDisruptionBudgetImpl) deletePodDisruptionBudget(
+	ctx context.Context, pdb *policyv1.PodDisruptionBudget) error {
+	pdbInterface := pdbi.k8sClient.PolicyV1().PodDisruptionBudgets(pdb.Namespace)
+	if err := pdbInterface.Delete(ctx, pdb.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("Failed to delete the PDB %q : %s", getNamespacedName(pdb), err)
+		return err
+	}
+
+	klog.Infof("PodDisruptionBudget %q has been deleted successfully", getNamespacedName(pdb))
+	return nil
+}
+
+// EnsurePodDisruptionBudget creates a new PodDisruptionBudget for the given
+// node type if one doesn't exist yet, or updates the existing one if its
+// limits are not up-to-date with the NdbCluster spec. The PodDisruptionBudget
+// of the MySQL Servers is not required, and is deleted if it exists, when
+// the NdbCluster has no MySQL Servers.
 func (pdbi *podDisruptionBudgetImpl) EnsurePodDisruptionBudget(
 	ctx context.Context, sc *SyncContext, nodeType string) (existed bool, err error) {
 
@@ -64,36 +120,65 @@ func (pdbi *podDisruptionBudgetImpl) EnsurePodDisruptionBudget(
 	nc := sc.ndb
 	pdbName := nc.GetPodDisruptionBudgetName(nodeType)
 	pdb, err := pdbi.pdbLister.PodDisruptionBudgets(nc.Namespace).Get(pdbName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		// Error retrieving PDB from the cache
+		klog.Errorf("Failed to retrieve PDB \"%s/%s\" : %s",
+			nc.Namespace, pdbName, err)
+		return false, err
+	}
+	pdbExists := err == nil
 
-	if err == nil {
-		// PDB exists. Verify that it is owned by the NdbCluster resource.
-		if err = sc.isOwnedByNdbCluster(pdb); err != nil {
-			// PDB is not owned by NdbCluster resource
+	if nodeType == constants.NdbNodeTypeMySQLD && nc.GetMySQLServerNodeCount() == 0 {
+		// No MySQL Servers to protect. Delete the
+		// PDB if it was created by a previous spec.
+		if pdbExists && metav1.IsControlledBy(pdb, nc) {
+			return true, pdbi.deletePodDisruptionBudget(ctx, pdb)
+		}
+		return true, nil
+	}
+
+	newPdb := resources.NewPodDisruptionBudget(nc, nodeType)
+	if !pdbExists {
+		// PDB doesn't exist yet. Create it via server side apply
+		// so that its limits are owned by the operator's apply
+		// field manager and can be switched later via apply.
+		klog.Infof("Creating a PodDisruptionBudget for node type %q : \"%s/%s\"",
+			nodeType, nc.Namespace, pdbName)
+		if err = pdbi.applyPodDisruptionBudget(ctx, newPdb); err != nil {
 			return false, err
 		}
 
-		return true, nil
+		// Successfully created a PDB for the given node type
+		return false, nil
 	}
 
-	if !apierrors.IsNotFound(err) {
-		// Error retrieving PDB
-		klog.Errorf("Failed to retrieve PDB \"%s/%s\" : %s",
-			nc.Namespace, pdbName, err)
+	// PDB exists. Verify that it is owned by the NdbCluster resource.
+	if err = sc.ensureOwnedByNdbCluster(ctx, pdb); err != nil {
+		// PDB is not owned by NdbCluster resource
+		return false, err
 	}
 
-	// PDB doesn't exist yet. Create it.
-	klog.Infof("Creating a PodDisruptionBudget for node type %q : \"%s/%s\"",
-		nodeType, nc.Namespace, pdbName)
-	pdb = resources.NewPodDisruptionBudget(nc, nodeType)
-	pdbInterface := sc.kubeClientset().PolicyV1().PodDisruptionBudgets(sc.ndb.Namespace)
-	_, err = pdbInterface.Create(ctx, pdb, metav1.CreateOptions{})
+	// Update the PDB if the limits have been changed in the NdbCluster spec
+	if pdbLimitsEqual(&pdb.Spec, &newPdb.Spec) {
+		return true, nil
+	}
 
-	if err != nil && !apierrors.IsAlreadyExists(err) {
-		// Error creating PDB.
-		// Ignore AlreadyExists error as the cache read might be outdated.
-		return false, err
+	if (pdb.Spec.MinAvailable != nil && newPdb.Spec.MinAvailable == nil) ||
+		(pdb.Spec.MaxUnavailable != nil && newPdb.Spec.MaxUnavailable == nil) {
+		// The limit being replaced might not be owned by the apply field
+		// manager, if the PDB was created by an older operator via Create,
+		// and an apply would then leave both the limits set. Replace the
+		// limits via an update instead.
+		updatedPdb := pdb.DeepCopy()
+		updatedPdb.Spec.MinAvailable = newPdb.Spec.MinAvailable
+		updatedPdb.Spec.MaxUnavailable = newPdb.Spec.MaxUnavailable
+		pdbInterface := pdbi.k8sClient.PolicyV1().PodDisruptionBudgets(nc.Namespace)
+		if _, err = pdbInterface.Update(ctx, updatedPdb, metav1.UpdateOptions{FieldManager: fieldManager}); err != nil {
+			klog.Errorf("Failed to update the PDB %q : %s", getNamespacedName(pdb), err)
+			return true, err
+		}
+		return true, nil
 	}
 
-	// Successfully created a PDB for the given node type
-	return false, nil
+	return true, pdbi.applyPodDisruptionBudget(ctx, newPdb)
 }
@@ -0,0 +1,194 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	policylisterv1 "k8s.io/client-go/listers/policy/v1"
+	policylisterv1beta1 "k8s.io/client-go/listers/policy/v1beta1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func Test_ServerPodDisruptionBudgetGroupVersion(t *testing.T) {
+	pdbResourceList := func(gv schema.GroupVersion) *metav1.APIResourceList {
+		return &metav1.APIResourceList{
+			GroupVersion: gv.String(),
+			APIResources: []metav1.APIResource{
+				{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget", Namespaced: true},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		desc       string
+		resources  []*metav1.APIResourceList
+		expectedGV schema.GroupVersion
+	}{
+		{
+			desc: "both policy/v1 and policy/v1beta1 are served",
+			resources: []*metav1.APIResourceList{
+				pdbResourceList(policyv1beta1.SchemeGroupVersion),
+				pdbResourceList(policyv1.SchemeGroupVersion),
+			},
+			expectedGV: policyv1.SchemeGroupVersion,
+		},
+		{
+			desc: "only policy/v1beta1 is served",
+			resources: []*metav1.APIResourceList{
+				pdbResourceList(policyv1beta1.SchemeGroupVersion),
+			},
+			expectedGV: policyv1beta1.SchemeGroupVersion,
+		},
+		{
+			desc:       "policy API is not served",
+			expectedGV: schema.GroupVersion{},
+		},
+	} {
+		client := k8sfake.NewSimpleClientset()
+		client.Discovery().(*fakediscovery.FakeDiscovery).Resources = tc.resources
+
+		if gv := ServerPodDisruptionBudgetGroupVersion(client); gv != tc.expectedGV {
+			t.Errorf("%s : expected %q but got %q", tc.desc, tc.expectedGV.String(), gv.String())
+		}
+	}
+}
+
+func Test_ManagementNodePodDisruptionBudget(t *testing.T) {
+	for _, tc := range []struct {
+		nodeCount              int32
+		expectedMaxUnavailable int
+	}{
+		// A single Management node should never be evicted
+		{1, 0},
+		// Only one of the two Management nodes can be unavailable
+		{2, 1},
+	} {
+		nc := testutils.NewTestNdb("default", "example-ndb", 2)
+		nc.Spec.ManagementNode.NodeCount = tc.nodeCount
+
+		pdb := resources.NewPodDisruptionBudget(nc, constants.NdbNodeTypeMgmd)
+		if pdb.Spec.MaxUnavailable == nil || pdb.Spec.MaxUnavailable.IntValue() != tc.expectedMaxUnavailable {
+			t.Errorf("%d Management nodes : expected maxUnavailable %d but got %v",
+				tc.nodeCount, tc.expectedMaxUnavailable, pdb.Spec.MaxUnavailable)
+		}
+	}
+}
+
+func Test_EnsurePodDisruptionBudget(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.UID = "ndb-uid"
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+	pdbIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	pdbi := newPodDisruptionBudgetControl(f.k8sclient, policylisterv1.NewPodDisruptionBudgetLister(pdbIndexer))
+	pdbInterface := f.k8sclient.PolicyV1().PodDisruptionBudgets(ns)
+
+	// A missing PDB should be created via server side apply
+	sc := f.c.newSyncContext(ctx, ndb)
+	existed, err := pdbi.EnsurePodDisruptionBudget(ctx, sc, constants.NdbNodeTypeMySQLD)
+	if err != nil || existed {
+		t.Fatalf("Unexpected result when creating the PDB : existed=%v, err=%v", existed, err)
+	}
+	pdbName := ndb.GetPodDisruptionBudgetName(constants.NdbNodeTypeMySQLD)
+	pdb, err := pdbInterface.Get(ctx, pdbName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("PDB %q was not created : %s", pdbName, err)
+	}
+	for _, action := range f.k8sclient.Actions() {
+		if action.Matches("create", "poddisruptionbudgets") {
+			t.Errorf("PDB %q created via Create instead of an apply", pdbName)
+		}
+	}
+
+	// The mysqld PDB should be deleted when there are no MySQL Servers
+	if err = pdbIndexer.Add(pdb); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	noMySQLServers := ndb.DeepCopy()
+	noMySQLServers.Spec.MysqlNode.NodeCount = 0
+	sc = f.c.newSyncContext(ctx, noMySQLServers)
+	if _, err = pdbi.EnsurePodDisruptionBudget(ctx, sc, constants.NdbNodeTypeMySQLD); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if _, err = pdbInterface.Get(ctx, pdbName, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("PDB %q not deleted when the MySQL Servers were scaled down to 0 : %v", pdbName, err)
+	}
+
+	// No mysqld PDB should be created when there are no MySQL Servers
+	if err = pdbIndexer.Delete(pdb); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if _, err = pdbi.EnsurePodDisruptionBudget(ctx, sc, constants.NdbNodeTypeMySQLD); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if _, err = pdbInterface.Get(ctx, pdbName, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("PDB %q created for an NdbCluster without MySQL Servers", pdbName)
+	}
+}
+
+func Test_EnsurePodDisruptionBudgetV1beta1(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.UID = "ndb-uid"
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+	pdbIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	pdbi := newPodDisruptionBudgetV1beta1Control(
+		f.k8sclient, policylisterv1beta1.NewPodDisruptionBudgetLister(pdbIndexer))
+	pdbInterface := f.k8sclient.PolicyV1beta1().PodDisruptionBudgets(ns)
+
+	// A missing PDB should be created via server side apply
+	sc := f.c.newSyncContext(ctx, ndb)
+	existed, err := pdbi.EnsurePodDisruptionBudget(ctx, sc, constants.NdbNodeTypeMySQLD)
+	if err != nil || existed {
+		t.Fatalf("Unexpected result when creating the PDB : existed=%v, err=%v", existed, err)
+	}
+	pdbName := ndb.GetPodDisruptionBudgetName(constants.NdbNodeTypeMySQLD)
+	pdb, err := pdbInterface.Get(ctx, pdbName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("PDB %q was not created : %s", pdbName, err)
+	}
+	for _, action := range f.k8sclient.Actions() {
+		if action.Matches("create", "poddisruptionbudgets") {
+			t.Errorf("PDB %q created via Create instead of an apply", pdbName)
+		}
+	}
+
+	// The mysqld PDB should be deleted when there are no MySQL Servers
+	if err = pdbIndexer.Add(pdb); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	noMySQLServers := ndb.DeepCopy()
+	noMySQLServers.Spec.MysqlNode.NodeCount = 0
+	sc = f.c.newSyncContext(ctx, noMySQLServers)
+	if _, err = pdbi.EnsurePodDisruptionBudget(ctx, sc, constants.NdbNodeTypeMySQLD); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if _, err = pdbInterface.Get(ctx, pdbName, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("PDB %q not deleted when the MySQL Servers were scaled down to 0 : %v", pdbName, err)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	policylisterv1beta1 "k8s.io/client-go/listers/policy/v1beta1"
+	klog "k8s.io/klog/v2"
+)
+
+// podDisruptionBudgetV1beta1Impl implements the PodDisruptionBudgetControlInterface
+// using the policy/v1beta1 API for the K8s Servers that do not support policy/v1.
+type podDisruptionBudgetV1beta1Impl struct {
+	k8sClient kubernetes.Interface
+	pdbLister policylisterv1beta1.PodDisruptionBudgetLister
+}
+
+// newPodDisruptionBudgetV1beta1Control creates a new PodDisruptionBudgetControlInterface
+// that manages the PodDisruptionBudgets via the policy/v1beta1 API
+func newPodDisruptionBudgetV1beta1Control(
+	client kubernetes.Interface,
+	pdbLister policylisterv1beta1.PodDisruptionBudgetLister) PodDisruptionBudgetControlInterface {
+	return &podDisruptionBudgetV1beta1Impl{
+		k8sClient: client,
+		pdbLister: pdbLister,
+	}
+}
+
+// applyPodDisruptionBudget updates the given PodDisruptionBudget via server side apply
+func (pdbi *podDisruptionBudgetV1beta1Impl) applyPodDisruptionBudget(
+	ctx context.Context, pdb *policyv1beta1.PodDisruptionBudget) error {
+	patch, err := newApplyPatch(pdb, policyv1beta1.SchemeGroupVersion.WithKind("PodDisruptionBudget"))
+	if err != nil {
+		klog.Errorf("Failed to generate the apply patch for PDB %q : %s", getNamespacedName(pdb), err)
+		return err
+	}
+
+	pdbInterface := pdbi.k8sClient.PolicyV1beta1().PodDisruptionBudgets(pdb.Namespace)
+	if _, err = pdbInterface.Patch(
+		ctx, pdb.Name, types.ApplyPatchType, patch, applyPatchOptions()); err != nil {
+		klog.Errorf("Failed to apply the PDB %q : %s", getNamespacedName(pdb), err)
+		return err
+	}
+
+	klog.Infof("PodDisruptionBudget %q has been updated successfully", getNamespacedName(pdb))
+	return nil
+}
+
+// deletePodDisruptionBudget deletes the given PodDisruptionBudget
+func (pdbi *podDisruptionBudgetV1beta1Impl) deletePodDisruptionBudget(
+	ctx context.Context, pdb *policyv1beta1.PodDisruptionBudget) error {
+	pdbInterface := pdbi.k8sClient.PolicyV1beta1().PodDisruptionBudgets(pdb.Namespace)
+	if err := pdbInterface.Delete(ctx, pdb.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("Failed to delete the PDB %q : %s", getNamespacedName(pdb), err)
+		return err
+	}
+
+	klog.Infof("PodDisruptionBudget %q has been deleted successfully", getNamespacedName(pdb))
+	return nil
+}
+
+// EnsurePodDisruptionBudget creates a new PodDisruptionBudget for the given
+// node type if one doesn't exist yet, or updates the existing one if its
+// limits are not up-to-date with the NdbCluster spec. The PodDisruptionBudget
+// of the MySQL Servers is not required, and is deleted if it exists, when
+// the NdbCluster has no MySQL Servers.
+func (pdbi *podDisruptionBudgetV1beta1Impl) EnsurePodDisruptionBudget(
+	ctx context.Context, sc *SyncContext, nodeType string) (existed bool, err error) {
+
+	// Check if the PDB exists already
+	nc := sc.ndb
+	pdbName := nc.GetPodDisruptionBudgetName(nodeType)
+	pdb, err := pdbi.pdbLister.PodDisruptionBudgets(nc.Namespace).Get(pdbName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		// Error retrieving PDB from the cache
+		klog.Errorf("Failed to retrieve PDB \"%s/%s\" : %s",
+			nc.Namespace, pdbName, err)
+		return false, err
+	}
+	pdbExists := err == nil
+
+	if nodeType == constants.NdbNodeTypeMySQLD && nc.GetMySQLServerNodeCount() == 0 {
+		// No MySQL Servers to protect. Delete the
+		// PDB if it was created by a previous spec.
+		if pdbExists && metav1.IsControlledBy(pdb, nc) {
+			return true, pdbi.deletePodDisruptionBudget(ctx, pdb)
+		}
+		return true, nil
+	}
+
+	newPdb := resources.NewPodDisruptionBudgetV1beta1(nc, nodeType)
+	if !pdbExists {
+		// PDB doesn't exist yet. Create it via server side apply
+		// so that its limits are owned by the operator's apply
+		// field manager and can be switched later via apply.
+		klog.Infof("Creating a policy/v1beta1 PodDisruptionBudget for node type %q : \"%s/%s\"",
+			nodeType, nc.Namespace, pdbName)
+		if err = pdbi.applyPodDisruptionBudget(ctx, newPdb); err != nil {
+			return false, err
+		}
+
+		// Successfully created a PDB for the given node type
+		return false, nil
+	}
+
+	// PDB exists. Verify that it is owned by the NdbCluster resource.
+	if err = sc.ensureOwnedByNdbCluster(ctx, pdb); err != nil {
+		// PDB is not owned by NdbCluster resource
+		return false, err
+	}
+
+	// Update the PDB if the limits have been changed in the NdbCluster spec
+	if equality.Semantic.DeepEqual(pdb.Spec.MinAvailable, newPdb.Spec.MinAvailable) &&
+		equality.Semantic.DeepEqual(pdb.Spec.MaxUnavailable, newPdb.Spec.MaxUnavailable) {
+		return true, nil
+	}
+
+	if (pdb.Spec.MinAvailable != nil && newPdb.Spec.MinAvailable == nil) ||
+		(pdb.Spec.MaxUnavailable != nil && newPdb.Spec.MaxUnavailable == nil) {
+		// The limit being replaced might not be owned by the apply field
+		// manager, if the PDB was created by an older operator via Create,
+		// and an apply would then leave both the limits set. Replace the
+		// limits via an update instead.
+		updatedPdb := pdb.DeepCopy()
+		updatedPdb.Spec.MinAvailable = newPdb.Spec.MinAvailable
+		updatedPdb.Spec.MaxUnavailable = newPdb.Spec.MaxUnavailable
+		pdbInterface := pdbi.k8sClient.PolicyV1beta1().PodDisruptionBudgets(nc.Namespace)
+		if _, err = pdbInterface.Update(ctx, updatedPdb, metav1.UpdateOptions{FieldManager: fieldManager}); err != nil {
+			klog.Errorf("Failed to update the PDB %q : %s", getNamespacedName(pdb), err)
+			return true, err
+		}
+		return true, nil
+	}
+
+	return true, pdbi.applyPodDisruptionBudget(ctx, newPdb)
+}
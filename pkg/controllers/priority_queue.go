@@ -0,0 +1,299 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// syncPriority is the priority with which an NdbCluster is synced
+type syncPriority int
+
+const (
+	// syncPriorityLow is the priority of the NdbClusters
+	// that are in a steady state, i.e. the periodic resyncs
+	syncPriorityLow syncPriority = iota
+	// syncPriorityHigh is the priority of the NdbClusters that are
+	// being created, updated or recovered from a failure
+	syncPriorityHigh
+)
+
+// maxConsecutiveHighPriorityItems is the maximum number of high
+// priority items handed out by the priorityQueue in a row, before
+// a low priority item is handed out to prevent its starvation.
+const maxConsecutiveHighPriorityItems = 4
+
+// unfinishedWorkUpdatePeriod is the interval at which the metrics
+// of the items being processed by a priorityQueue are updated
+const unfinishedWorkUpdatePeriod = 500 * time.Millisecond
+
+// priorityQueue implements the workqueue.Interface with two FIFO queues,
+// one for each syncPriority. The priority of an item is determined by the
+// getPriority function whenever the item is added, and the high priority
+// items are handed out before the low priority ones. Like the default
+// workqueue, an item is queued only once however many times it is added,
+// and it is never handed out to more than one worker at a time. A named
+// priorityQueue records the same metrics as the default workqueue.
+type priorityQueue struct {
+	getPriority func(item interface{}) syncPriority
+
+	// queues holds the items waiting to be processed, by their priority
+	queues [syncPriorityHigh + 1][]interface{}
+	// dirty holds the items that need to be processed,
+	// along with the priority with which they were added
+	dirty map[interface{}]syncPriority
+	// processing holds the items currently being processed
+	processing map[interface{}]bool
+	// consecutiveHighPriorityItems is the number of high priority
+	// items handed out since the last low priority item
+	consecutiveHighPriorityItems int
+	// metrics records the metrics of the queue. It is nil if the queue is not named.
+	metrics *priorityQueueMetrics
+
+	cond         *sync.Cond
+	shuttingDown bool
+	drain        bool
+}
+
+// newPriorityQueue returns a new priorityQueue. The queue records
+// its metrics with the queueMetricsProvider if the name is not empty.
+func newPriorityQueue(name string, getPriority func(item interface{}) syncPriority) *priorityQueue {
+	pq := &priorityQueue{
+		getPriority: getPriority,
+		dirty:       make(map[interface{}]syncPriority),
+		processing:  make(map[interface{}]bool),
+		cond:        sync.NewCond(&sync.Mutex{}),
+	}
+
+	if name != "" {
+		pq.metrics = newPriorityQueueMetrics(queueMetricsProvider, name)
+		go pq.updateUnfinishedWorkLoop()
+	}
+	return pq
+}
+
+// updateUnfinishedWorkLoop periodically updates the metrics of
+// the items being processed until the queue is shutdown.
+func (pq *priorityQueue) updateUnfinishedWorkLoop() {
+	ticker := time.NewTicker(unfinishedWorkUpdatePeriod)
+	defer ticker.Stop()
+	for range ticker.C {
+		pq.cond.L.Lock()
+		if pq.shuttingDown {
+			pq.cond.L.Unlock()
+			return
+		}
+		pq.metrics.updateUnfinishedWork()
+		pq.cond.L.Unlock()
+	}
+}
+
+// removeFromQueue removes the item from the queue of the given priority.
+// The caller should hold the lock when calling this method.
+func (pq *priorityQueue) removeFromQueue(item interface{}, priority syncPriority) {
+	queue := pq.queues[priority]
+	for i := range queue {
+		if queue[i] == item {
+			pq.queues[priority] = append(queue[:i], queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// Add marks the item as needing processing
+func (pq *priorityQueue) Add(item interface{}) {
+	priority := pq.getPriority(item)
+
+	pq.cond.L.Lock()
+	defer pq.cond.L.Unlock()
+	if pq.shuttingDown {
+		return
+	}
+
+	if queuedPriority, dirty := pq.dirty[item]; dirty {
+		if priority <= queuedPriority {
+			// Already queued with the same or a higher priority
+			return
+		}
+
+		// Promote the item to the higher priority
+		pq.dirty[item] = priority
+		if !pq.processing[item] {
+			pq.removeFromQueue(item, queuedPriority)
+			pq.queues[priority] = append(pq.queues[priority], item)
+		}
+		return
+	}
+
+	pq.dirty[item] = priority
+	pq.metrics.add(item)
+	if pq.processing[item] {
+		// The item will be queued again once it is done
+		return
+	}
+
+	pq.queues[priority] = append(pq.queues[priority], item)
+	pq.cond.Signal()
+}
+
+// Len returns the number of items waiting to be processed
+func (pq *priorityQueue) Len() int {
+	pq.cond.L.Lock()
+	defer pq.cond.L.Unlock()
+	return len(pq.queues[syncPriorityHigh]) + len(pq.queues[syncPriorityLow])
+}
+
+// Get blocks until it can return an item to be processed. The high
+// priority items are returned first, except that a low priority
+// item is returned after every maxConsecutiveHighPriorityItems.
+func (pq *priorityQueue) Get() (item interface{}, shutdown bool) {
+	pq.cond.L.Lock()
+	defer pq.cond.L.Unlock()
+	for len(pq.queues[syncPriorityHigh])+len(pq.queues[syncPriorityLow]) == 0 && !pq.shuttingDown {
+		pq.cond.Wait()
+	}
+	if len(pq.queues[syncPriorityHigh])+len(pq.queues[syncPriorityLow]) == 0 {
+		// Shutting down and no items left
+		return nil, true
+	}
+
+	priority := syncPriorityHigh
+	if len(pq.queues[syncPriorityHigh]) == 0 ||
+		(len(pq.queues[syncPriorityLow]) != 0 &&
+			pq.consecutiveHighPriorityItems >= maxConsecutiveHighPriorityItems) {
+		priority = syncPriorityLow
+	}
+
+	if priority == syncPriorityHigh {
+		pq.consecutiveHighPriorityItems++
+	} else {
+		pq.consecutiveHighPriorityItems = 0
+	}
+
+	item = pq.queues[priority][0]
+	// Let the backing array release the item
+	pq.queues[priority][0] = nil
+	pq.queues[priority] = pq.queues[priority][1:]
+
+	pq.processing[item] = true
+	delete(pq.dirty, item)
+	pq.metrics.get(item)
+	return item, false
+}
+
+// Done marks the item as done processing, and queues it
+// again if it was added while it was being processed
+func (pq *priorityQueue) Done(item interface{}) {
+	pq.cond.L.Lock()
+	defer pq.cond.L.Unlock()
+
+	delete(pq.processing, item)
+	pq.metrics.done(item)
+	if priority, dirty := pq.dirty[item]; dirty {
+		pq.queues[priority] = append(pq.queues[priority], item)
+		pq.cond.Signal()
+	} else if len(pq.processing) == 0 {
+		// Wake up the ShutDownWithDrain, if it is waiting
+		pq.cond.Broadcast()
+	}
+}
+
+// ShutDown makes the queue ignore all new items
+// and makes the workers waiting in Get to return.
+func (pq *priorityQueue) ShutDown() {
+	pq.cond.L.Lock()
+	defer pq.cond.L.Unlock()
+	pq.drain = false
+	pq.shuttingDown = true
+	pq.cond.Broadcast()
+}
+
+// ShutDownWithDrain is like ShutDown, but it also
+// waits for the items being processed to be done.
+func (pq *priorityQueue) ShutDownWithDrain() {
+	pq.cond.L.Lock()
+	defer pq.cond.L.Unlock()
+	pq.drain = true
+	pq.shuttingDown = true
+	pq.cond.Broadcast()
+	for len(pq.processing) != 0 && pq.drain {
+		pq.cond.Wait()
+	}
+}
+
+// ShuttingDown returns true if the queue is shutting down
+func (pq *priorityQueue) ShuttingDown() bool {
+	pq.cond.L.Lock()
+	defer pq.cond.L.Unlock()
+	return pq.shuttingDown
+}
+
+// allNodesReady returns true if the given ready status of a node
+// type, of the form "Ready:<ready>/<total>", reports all the nodes
+// to be ready. A status that cannot be parsed is considered ready.
+func allNodesReady(readyStatus string) bool {
+	var ready, total int
+	if _, err := fmt.Sscanf(readyStatus, "Ready:%d/%d", &ready, &total); err != nil {
+		return true
+	}
+	return ready >= total
+}
+
+// getSyncPriority returns the priority with which the NdbCluster with
+// the given key has to be synced. NdbClusters that are being created,
+// updated or recovered from a failure are synced with a high priority,
+// and the steady state NdbClusters with a low priority. So, in a large
+// fleet, the periodic resyncs of the healthy NdbClusters do not delay
+// the syncs that need to make progress.
+func (c *Controller) getSyncPriority(item interface{}) syncPriority {
+	key, ok := item.(string)
+	if !ok {
+		return syncPriorityLow
+	}
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return syncPriorityLow
+	}
+
+	nc, err := c.ndbsLister.NdbClusters(namespace).Get(name)
+	if err != nil {
+		// The NdbCluster has been deleted or is not in the cache
+		// yet. Process it quickly to clean up or create it.
+		return syncPriorityHigh
+	}
+
+	if nc.Status.ProcessedGeneration != nc.Generation {
+		// The NdbCluster is being created or updated
+		return syncPriorityHigh
+	}
+
+	if upToDateCondition := nc.GetUpToDateCondition(); upToDateCondition == nil ||
+		upToDateCondition.Status != corev1.ConditionTrue {
+		// The spec is still being applied to the MySQL Cluster
+		return syncPriorityHigh
+	}
+
+	if degradedCondition := nc.GetDegradedCondition(); degradedCondition != nil &&
+		degradedCondition.Status == corev1.ConditionTrue {
+		// The syncs of the NdbCluster have been failing
+		return syncPriorityHigh
+	}
+
+	for _, readyStatus := range []string{
+		nc.Status.ReadyManagementNodes, nc.Status.ReadyDataNodes, nc.Status.ReadyMySQLServers} {
+		if !allNodesReady(readyStatus) {
+			// Some MySQL Cluster nodes are recovering from a failure
+			return syncPriorityHigh
+		}
+	}
+
+	return syncPriorityLow
+}
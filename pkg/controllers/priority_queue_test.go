@@ -0,0 +1,127 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"testing"
+
+	ndbcontroller "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_priorityQueue(t *testing.T) {
+	priorities := map[string]syncPriority{}
+	pq := newPriorityQueue("", func(item interface{}) syncPriority {
+		return priorities[item.(string)]
+	})
+
+	expectItems := func(desc string, expectedItems ...string) {
+		t.Helper()
+		for _, expected := range expectedItems {
+			item, shutdown := pq.Get()
+			if shutdown {
+				t.Fatalf("%s : queue unexpectedly shut down", desc)
+			}
+			if item != expected {
+				t.Errorf("%s : expected %q but got %q", desc, expected, item)
+			}
+			pq.Done(item)
+		}
+		if pq.Len() != 0 {
+			t.Errorf("%s : expected an empty queue but got %d items", desc, pq.Len())
+		}
+	}
+
+	// High priority items are handed out before the low priority ones
+	priorities["h1"], priorities["h2"] = syncPriorityHigh, syncPriorityHigh
+	pq.Add("l1")
+	pq.Add("h1")
+	pq.Add("l1")
+	pq.Add("h2")
+	if pq.Len() != 3 {
+		t.Errorf("Expected 3 items in the queue but got %d", pq.Len())
+	}
+	expectItems("high before low", "h1", "h2", "l1")
+
+	// A low priority item is handed out after maxConsecutiveHighPriorityItems
+	pq.Add("l1")
+	pq.Add("l2")
+	for _, item := range []string{"h1", "h2", "h3", "h4", "h5"} {
+		priorities[item] = syncPriorityHigh
+		pq.Add(item)
+	}
+	expectItems("no starvation", "h1", "h2", "h3", "h4", "l1", "h5", "l2")
+
+	// A low priority item is promoted when added with a high priority
+	pq.Add("l1")
+	pq.Add("l2")
+	priorities["l2"] = syncPriorityHigh
+	pq.Add("l2")
+	expectItems("promotion", "l2", "l1")
+	priorities["l2"] = syncPriorityLow
+
+	// An item added while it is being processed is queued again once it is done
+	pq.Add("l1")
+	item, _ := pq.Get()
+	pq.Add("l1")
+	if pq.Len() != 0 {
+		t.Errorf("Expected the item being processed not to be queued but got %d items", pq.Len())
+	}
+	pq.Done(item)
+	expectItems("re-add while processing", "l1")
+
+	// Shutdown makes the Get return after the queue is empty
+	pq.Add("l1")
+	pq.ShutDown()
+	pq.Add("l2")
+	expectItems("shutdown", "l1")
+	if _, shutdown := pq.Get(); !shutdown {
+		t.Error("Expected the queue to be shut down")
+	}
+}
+
+func Test_getSyncPriority(t *testing.T) {
+	newNdb := testutils.NewTestNdb(metav1.NamespaceDefault, "new", 2)
+
+	steadyNdb := testutils.NewTestNdb(metav1.NamespaceDefault, "steady", 2)
+	steadyNdb.Generation = 1
+	steadyNdb.Status = ndbcontroller.NdbClusterStatus{
+		ProcessedGeneration:  1,
+		ReadyManagementNodes: "Ready:2/2",
+		ReadyDataNodes:       "Ready:2/2",
+		ReadyMySQLServers:    "Ready:0/0",
+		Conditions: []ndbcontroller.NdbClusterCondition{
+			{
+				Type:   ndbcontroller.NdbClusterUpToDate,
+				Status: corev1.ConditionTrue,
+			},
+		},
+	}
+
+	recoveringNdb := steadyNdb.DeepCopy()
+	recoveringNdb.Name = "recovering"
+	recoveringNdb.Status.ReadyDataNodes = "Ready:1/2"
+
+	f := newFixture(t, newNdb, steadyNdb, recoveringNdb)
+	defer f.close()
+	f.newController()
+
+	for _, tc := range []struct {
+		key      string
+		expected syncPriority
+	}{
+		{getKey(newNdb, t), syncPriorityHigh},
+		{getKey(steadyNdb, t), syncPriorityLow},
+		{getKey(recoveringNdb, t), syncPriorityHigh},
+		{"default/deleted", syncPriorityHigh},
+	} {
+		if priority := f.c.getSyncPriority(tc.key); priority != tc.expected {
+			t.Errorf("Expected priority %d for %q but got %d", tc.expected, tc.key, priority)
+		}
+	}
+}
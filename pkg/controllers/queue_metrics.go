@@ -0,0 +1,265 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// ndbClusterQueueStats are the workqueue statistics of an NdbCluster
+type ndbClusterQueueStats struct {
+	// pendingRequests is the number of sync requests added for the
+	// NdbCluster since it was last picked up by a worker. The workqueue
+	// coalesces them into a single item, so they are all handled by the
+	// next sync of the NdbCluster.
+	pendingRequests int
+	// syncs is the number of syncs of the NdbCluster completed
+	syncs int64
+	// syncFailures is the number of syncs of the NdbCluster that failed
+	syncFailures int64
+	// syncStartTime is the start time of the ongoing sync, if any
+	syncStartTime time.Time
+	// lastSyncDuration is the time taken by the last completed sync
+	lastSyncDuration time.Duration
+}
+
+// instrumentedQueue wraps the controller's workqueue and collects the
+// statistics of every NdbCluster. The workqueue never holds more than
+// one item per NdbCluster and never hands out an item to more than one
+// worker at a time. So, an NdbCluster with frequent updates cannot
+// starve others.
+type instrumentedQueue struct {
+	workqueue.RateLimitingInterface
+	// stats holds the statistics keyed by the NdbCluster keys
+	stats map[string]*ndbClusterQueueStats
+	// mutex protects the stats map
+	mutex sync.Mutex
+}
+
+func newInstrumentedQueue(queue workqueue.RateLimitingInterface) *instrumentedQueue {
+	return &instrumentedQueue{
+		RateLimitingInterface: queue,
+		stats:                 make(map[string]*ndbClusterQueueStats),
+	}
+}
+
+// getStats returns the statistics of the given item. The
+// caller should hold the mutex when calling this method.
+func (iq *instrumentedQueue) getStats(item interface{}) *ndbClusterQueueStats {
+	key, _ := item.(string)
+	stats, exists := iq.stats[key]
+	if !exists {
+		stats = &ndbClusterQueueStats{}
+		iq.stats[key] = stats
+	}
+	return stats
+}
+
+// recordRequest records a sync request for the given item
+func (iq *instrumentedQueue) recordRequest(item interface{}) {
+	iq.mutex.Lock()
+	defer iq.mutex.Unlock()
+	iq.getStats(item).pendingRequests++
+}
+
+// Add adds the item to the workqueue
+func (iq *instrumentedQueue) Add(item interface{}) {
+	iq.recordRequest(item)
+	iq.RateLimitingInterface.Add(item)
+}
+
+// AddAfter adds the item to the workqueue after the given duration
+func (iq *instrumentedQueue) AddAfter(item interface{}, duration time.Duration) {
+	iq.recordRequest(item)
+	iq.RateLimitingInterface.AddAfter(item, duration)
+}
+
+// AddRateLimited adds the item to the workqueue once the rate limiter allows it
+func (iq *instrumentedQueue) AddRateLimited(item interface{}) {
+	iq.recordRequest(item)
+	iq.RateLimitingInterface.AddRateLimited(item)
+}
+
+// recordSyncFailure records the failure of a sync of the given item
+func (iq *instrumentedQueue) recordSyncFailure(item interface{}) {
+	iq.mutex.Lock()
+	defer iq.mutex.Unlock()
+	iq.getStats(item).syncFailures++
+}
+
+// Get blocks until an item can be processed and returns it
+func (iq *instrumentedQueue) Get() (item interface{}, shutdown bool) {
+	item, shutdown = iq.RateLimitingInterface.Get()
+	if shutdown {
+		return item, shutdown
+	}
+
+	iq.mutex.Lock()
+	defer iq.mutex.Unlock()
+	stats := iq.getStats(item)
+	stats.pendingRequests = 0
+	stats.syncStartTime = time.Now()
+	return item, shutdown
+}
+
+// Done marks the processing of the item as complete
+func (iq *instrumentedQueue) Done(item interface{}) {
+	iq.mutex.Lock()
+	stats := iq.getStats(item)
+	stats.syncs++
+	stats.lastSyncDuration = time.Since(stats.syncStartTime)
+	stats.syncStartTime = time.Time{}
+	iq.mutex.Unlock()
+
+	iq.RateLimitingInterface.Done(item)
+}
+
+// forgetDeleted drops the statistics of the items for
+// which exists returns false and returns the remaining keys
+func (iq *instrumentedQueue) forgetDeleted(exists func(key string) bool) []string {
+	iq.mutex.Lock()
+	defer iq.mutex.Unlock()
+
+	var keys []string
+	for key, stats := range iq.stats {
+		if !exists(key) && stats.pendingRequests == 0 && stats.syncStartTime.IsZero() {
+			delete(iq.stats, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeMetric writes a metric, labelled with the NdbCluster
+// keys, in the Prometheus text exposition format
+func writeMetric(w io.Writer, name, metricType, help string, samples map[string]float64, keys []string) {
+	writeLabelledMetric(w, name, metricType, help, "ndbcluster", samples, keys)
+}
+
+// writeLabelledMetric writes a metric in the Prometheus text exposition
+// format, with a sample for every key labelled with the given label name
+func writeLabelledMetric(w io.Writer, name, metricType, help, label string, samples map[string]float64, keys []string) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+	if keys == nil {
+		// Metric without labels
+		_, _ = fmt.Fprintf(w, "%s %v\n", name, samples[""])
+		return
+	}
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(w, "%s{%s=%q} %v\n", name, label, key, samples[key])
+	}
+}
+
+// writeSummaryMetric writes a summary metric, with the sum and the count of
+// the observations of every key, in the Prometheus text exposition format
+func writeSummaryMetric(w io.Writer, name, help, label string, keys []string, get func(key string) (float64, int64)) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s summary\n", name, help, name)
+	for _, key := range keys {
+		sum, count := get(key)
+		_, _ = fmt.Fprintf(w, "%s_sum{%s=%q} %v\n%s_count{%s=%q} %d\n", name, label, key, sum, name, label, key, count)
+	}
+}
+
+// WriteMetrics writes the metrics of the controller, including the
+// workqueue statistics of every NdbCluster, to the given writer in the
+// Prometheus text exposition format.
+func (c *Controller) WriteMetrics(w io.Writer) {
+	// Drop the statistics of the deleted NdbClusters
+	keys := c.workqueue.forgetDeleted(func(key string) bool {
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			return false
+		}
+		_, err = c.ndbsLister.NdbClusters(namespace).Get(name)
+		return err == nil
+	})
+
+	queueDepth := make(map[string]float64, len(keys))
+	retries := make(map[string]float64, len(keys))
+	syncs := make(map[string]float64, len(keys))
+	syncFailures := make(map[string]float64, len(keys))
+	lastSyncDuration := make(map[string]float64, len(keys))
+	c.workqueue.mutex.Lock()
+	for _, key := range keys {
+		if stats, exists := c.workqueue.stats[key]; exists {
+			queueDepth[key] = float64(stats.pendingRequests)
+			syncs[key] = float64(stats.syncs)
+			syncFailures[key] = float64(stats.syncFailures)
+			lastSyncDuration[key] = stats.lastSyncDuration.Seconds()
+		}
+	}
+	c.workqueue.mutex.Unlock()
+	for _, key := range keys {
+		retries[key] = float64(c.workqueue.NumRequeues(key))
+	}
+
+	writeMetric(w, "ndb_operator_workqueue_depth", "gauge",
+		"The number of NdbClusters waiting in the workqueue to be synced.",
+		map[string]float64{"": float64(c.workqueue.Len())}, nil)
+	writeMetric(w, "ndb_operator_ndbcluster_queue_depth", "gauge",
+		"The number of sync requests of the NdbCluster waiting to be handled by its next sync.",
+		queueDepth, keys)
+	writeMetric(w, "ndb_operator_ndbcluster_sync_retries", "gauge",
+		"The number of times the sync of the NdbCluster has been retried since it last succeeded.",
+		retries, keys)
+	writeMetric(w, "ndb_operator_ndbcluster_syncs_total", "counter",
+		"The number of syncs of the NdbCluster completed.",
+		syncs, keys)
+	writeMetric(w, "ndb_operator_ndbcluster_sync_failures_total", "counter",
+		"The number of syncs of the NdbCluster that failed.",
+		syncFailures, keys)
+	writeMetric(w, "ndb_operator_ndbcluster_last_sync_duration_seconds", "gauge",
+		"The time taken by the last completed sync of the NdbCluster.",
+		lastSyncDuration, keys)
+	writeMetric(w, "ndb_operator_mgm_connections", "gauge",
+		"The number of connections open to the Management Servers.",
+		map[string]float64{"": float64(mgmapi.GetActiveConnections())}, nil)
+	writeMetric(w, "ndb_operator_mysql_connections", "gauge",
+		"The number of connections open to the MySQL Servers.",
+		map[string]float64{"": float64(mysqlclient.GetOpenConnections())}, nil)
+
+	// The metrics of the workqueues and the informer caches
+	queueMetricsProvider.writeMetrics(w)
+	c.writeInformerMetrics(w)
+}
+
+// writeInformerMetrics writes the metrics of the informer caches
+// used by the controller in the Prometheus text exposition format.
+func (c *Controller) writeInformerMetrics(w io.Writer) {
+	resources := make([]string, 0, len(c.informers))
+	for resource := range c.informers {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	cachedObjects := make(map[string]float64, len(resources))
+	synced := make(map[string]float64, len(resources))
+	for _, resource := range resources {
+		informer := c.informers[resource]
+		cachedObjects[resource] = float64(len(informer.GetStore().ListKeys()))
+		if informer.HasSynced() {
+			synced[resource] = 1
+		}
+	}
+
+	writeLabelledMetric(w, "ndb_operator_informer_cached_objects", "gauge",
+		"The number of objects in the informer cache of the resource.", "resource",
+		cachedObjects, resources)
+	writeLabelledMetric(w, "ndb_operator_informer_synced", "gauge",
+		"Whether the informer cache of the resource has synced, 1 if synced and 0 otherwise.", "resource",
+		synced, resources)
+}
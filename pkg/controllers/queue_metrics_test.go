@@ -0,0 +1,79 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_WriteMetrics(t *testing.T) {
+	ndb := testutils.NewTestNdb(metav1.NamespaceDefault, "test", 2)
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	// The sync requests of an NdbCluster are coalesced
+	key := getKey(ndb, t)
+	f.c.workqueue.Add(key)
+	f.c.workqueue.Add(key)
+	// An NdbCluster that has been deleted
+	f.c.workqueue.Add("default/deleted")
+
+	writeMetrics := func() string {
+		var metrics bytes.Buffer
+		f.c.WriteMetrics(&metrics)
+		return metrics.String()
+	}
+
+	metrics := writeMetrics()
+	for _, sample := range []string{
+		"ndb_operator_workqueue_depth 2\n",
+		"ndb_operator_ndbcluster_queue_depth{ndbcluster=\"default/test\"} 2\n",
+		"ndb_operator_ndbcluster_queue_depth{ndbcluster=\"default/deleted\"} 1\n",
+		"ndb_operator_ndbcluster_syncs_total{ndbcluster=\"default/test\"} 0\n",
+		"ndb_operator_ndbcluster_sync_failures_total{ndbcluster=\"default/test\"} 0\n",
+	} {
+		if !strings.Contains(metrics, sample) {
+			t.Errorf("Expected %q in the metrics but got :\n%s", sample, metrics)
+		}
+	}
+
+	// Process both the items, failing the sync of the NdbCluster
+	for f.c.workqueue.Len() != 0 {
+		item, _ := f.c.workqueue.Get()
+		if item == key {
+			f.c.workqueue.recordSyncFailure(item)
+		}
+		f.c.workqueue.Done(item)
+	}
+
+	metrics = writeMetrics()
+	for _, sample := range []string{
+		"ndb_operator_workqueue_depth 0\n",
+		"ndb_operator_ndbcluster_queue_depth{ndbcluster=\"default/test\"} 0\n",
+		"ndb_operator_ndbcluster_syncs_total{ndbcluster=\"default/test\"} 1\n",
+		"ndb_operator_ndbcluster_sync_failures_total{ndbcluster=\"default/test\"} 1\n",
+		"workqueue_adds_total{name=\"Ndbs\"}",
+		"workqueue_work_duration_seconds_count{name=\"Ndbs\"}",
+		"ndb_operator_informer_synced{resource=\"NdbCluster\"}",
+		"ndb_operator_informer_cached_objects{resource=\"StatefulSet\"}",
+	} {
+		if !strings.Contains(metrics, sample) {
+			t.Errorf("Expected %q in the metrics but got :\n%s", sample, metrics)
+		}
+	}
+
+	// The statistics of the deleted NdbCluster should be dropped once it is processed
+	if strings.Contains(metrics, "default/deleted") {
+		t.Errorf("Expected no metrics of the deleted NdbCluster but got :\n%s", metrics)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// baseRetryDelay is the delay used when an item is requeued for the first time
+	baseRetryDelay = 5 * time.Second
+	// maxRetryDelay is the maximum delay an item will wait before being requeued
+	maxRetryDelay = 5 * time.Minute
+	// retryJitterFactor is the maximum fraction of the delay added as jitter
+	retryJitterFactor = 0.1
+
+	// itemRetryQPS and itemRetryBurst are the rate and the bucket
+	// size of the token bucket that limits the retries of an item
+	itemRetryQPS   = 1
+	itemRetryBurst = 10
+)
+
+// jitteredExponentialRateLimiter wraps an item exponential failure
+// rate limiter and adds a random jitter to the delay returned by it,
+// so that multiple failing NdbClusters are not retried in lock step.
+type jitteredExponentialRateLimiter struct {
+	workqueue.RateLimiter
+	maxDelay     time.Duration
+	jitterFactor float64
+}
+
+// When returns the delay after which the item should be requeued
+func (r *jitteredExponentialRateLimiter) When(item interface{}) time.Duration {
+	delay := r.RateLimiter.When(item)
+	delay += time.Duration(rand.Float64() * r.jitterFactor * float64(delay))
+	if delay > r.maxDelay {
+		// Never exceed the maximum delay
+		delay = r.maxDelay
+	}
+	return delay
+}
+
+func newJitteredExponentialRateLimiter(
+	baseDelay, maxDelay time.Duration, jitterFactor float64) workqueue.RateLimiter {
+	return &jitteredExponentialRateLimiter{
+		RateLimiter:  workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+		maxDelay:     maxDelay,
+		jitterFactor: jitterFactor,
+	}
+}
+
+// itemBucketRateLimiter limits the rate of every item with a separate
+// token bucket, so that the retries of an item never use up the tokens
+// of the other items. This isolates the NdbClusters from each other, as
+// an NdbCluster that fails repeatedly cannot delay the retries of others.
+type itemBucketRateLimiter struct {
+	qps   rate.Limit
+	burst int
+	// limiters holds the token buckets keyed by the items
+	limiters map[interface{}]*rate.Limiter
+	// mutex protects the limiters map
+	mutex sync.Mutex
+}
+
+func newItemBucketRateLimiter(qps rate.Limit, burst int) *itemBucketRateLimiter {
+	return &itemBucketRateLimiter{
+		qps:      qps,
+		burst:    burst,
+		limiters: make(map[interface{}]*rate.Limiter),
+	}
+}
+
+// When returns the delay after which the item should be requeued
+func (r *itemBucketRateLimiter) When(item interface{}) time.Duration {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	limiter, exists := r.limiters[item]
+	if !exists {
+		limiter = rate.NewLimiter(r.qps, r.burst)
+		r.limiters[item] = limiter
+	}
+	return limiter.Reserve().Delay()
+}
+
+// NumRequeues always returns 0 as the token buckets do not track the failures
+func (r *itemBucketRateLimiter) NumRequeues(interface{}) int {
+	return 0
+}
+
+// Forget drops the token bucket of the item
+func (r *itemBucketRateLimiter) Forget(item interface{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.limiters, item)
+}
+
+// newControllerRateLimiter returns the RateLimiter used by the
+// controller's workqueue. The per item delay grows exponentially
+// on every failure up to a maximum, and a token bucket per item
+// limits the retry rate of every NdbCluster resource separately.
+func newControllerRateLimiter() workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		newJitteredExponentialRateLimiter(baseRetryDelay, maxRetryDelay, retryJitterFactor),
+		newItemBucketRateLimiter(rate.Limit(itemRetryQPS), itemRetryBurst),
+	)
+}
+
+// expectedRetryDelay returns the approximate delay, excluding the jitter,
+// after which an item that has failed the given number of times will be
+// retried by the rate limiter returned by newControllerRateLimiter.
+func expectedRetryDelay(failures int) time.Duration {
+	delay := baseRetryDelay
+	for i := 1; i < failures && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_jitteredExponentialRateLimiter(t *testing.T) {
+	baseDelay := 1 * time.Second
+	maxDelay := 10 * time.Second
+	rl := newJitteredExponentialRateLimiter(baseDelay, maxDelay, 0.1)
+
+	item := "default/example-ndb"
+	expectedDelay := baseDelay
+	for i := 0; i < 8; i++ {
+		delay := rl.When(item)
+		if delay > maxDelay {
+			t.Errorf("Delay %s exceeds the max delay %s", delay, maxDelay)
+		}
+
+		// delay should be within [expectedDelay, expectedDelay + 10%] or the max delay
+		if expectedDelay < maxDelay &&
+			(delay < expectedDelay || delay > expectedDelay+expectedDelay/10) {
+			t.Errorf("Retry %d : delay %s not in expected range [%s, %s]",
+				i, delay, expectedDelay, expectedDelay+expectedDelay/10)
+		}
+		expectedDelay *= 2
+	}
+
+	if rl.NumRequeues(item) != 8 {
+		t.Errorf("Expected 8 requeues but got %d", rl.NumRequeues(item))
+	}
+
+	// Forget should reset the backoff
+	rl.Forget(item)
+	if delay := rl.When(item); delay < baseDelay || delay > baseDelay+baseDelay/10 {
+		t.Errorf("Delay %s after forget not in expected range", delay)
+	}
+}
+
+func Test_expectedRetryDelay(t *testing.T) {
+	for failures, expectedDelay := range map[int]time.Duration{
+		1:  baseRetryDelay,
+		2:  2 * baseRetryDelay,
+		4:  8 * baseRetryDelay,
+		7:  maxRetryDelay,
+		50: maxRetryDelay,
+	} {
+		if delay := expectedRetryDelay(failures); delay != expectedDelay {
+			t.Errorf("Failures %d : expected delay %s but got %s", failures, expectedDelay, delay)
+		}
+	}
+}
+
+func Test_itemBucketRateLimiter(t *testing.T) {
+	rl := newItemBucketRateLimiter(1, 2)
+
+	// The burst of an item is allowed without any delay
+	failingItem := "default/failing-ndb"
+	for i := 0; i < 2; i++ {
+		if delay := rl.When(failingItem); delay != 0 {
+			t.Errorf("Retry %d : expected no delay but got %s", i, delay)
+		}
+	}
+
+	// Further retries of the item should be delayed
+	if delay := rl.When(failingItem); delay <= 0 {
+		t.Errorf("Expected the retry to be delayed but got %s", delay)
+	}
+
+	// Other items should not be affected by the failing item
+	if delay := rl.When("default/example-ndb"); delay != 0 {
+		t.Errorf("Expected no delay for another item but got %s", delay)
+	}
+
+	// Forget should reset the token bucket of the item
+	rl.Forget(failingItem)
+	if delay := rl.When(failingItem); delay != 0 {
+		t.Errorf("Expected no delay after forget but got %s", delay)
+	}
+}
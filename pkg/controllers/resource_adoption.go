@@ -0,0 +1,155 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mysql/ndb-operator/config/debug"
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// canAdoptObject returns true if the given object was created for the
+// NdbCluster being synced, but is not controlled by it. i.e. the object
+// has the constants.ClusterLabel set to the name of the NdbCluster, and
+// it either has no controller or is controlled by a previous incarnation
+// of the NdbCluster with the same name. The latter can happen when the
+// resources are restored from an etcd backup or copied from another
+// namespace, as the NdbCluster will then have a new UID.
+func (sc *SyncContext) canAdoptObject(object metav1.Object) bool {
+	nc := sc.ndb
+	if object.GetDeletionTimestamp() != nil {
+		// Object is being deleted
+		return false
+	}
+
+	if object.GetLabels()[constants.ClusterLabel] != nc.Name {
+		// Object was not created for this NdbCluster
+		return false
+	}
+
+	controllerRef := metav1.GetControllerOf(object)
+	if controllerRef == nil {
+		// Object is an orphan
+		return true
+	}
+
+	// Adopt the object only if it is controlled by
+	// an older NdbCluster resource with the same name
+	gv, err := schema.ParseGroupVersion(controllerRef.APIVersion)
+	return err == nil && gv.Group == v1.SchemeGroupVersion.Group &&
+		controllerRef.Kind == "NdbCluster" && controllerRef.Name == nc.Name &&
+		controllerRef.UID != nc.UID
+}
+
+// adoptObject makes the NdbCluster being synced the controller of the
+// given object. The patch carries the resourceVersion of the object, so
+// that the adoption fails if the object was modified in the meantime.
+func (sc *SyncContext) adoptObject(ctx context.Context, object metav1.Object) error {
+	// Replace any existing controller reference with one to the NdbCluster
+	ownerReferences := sc.ndb.GetOwnerReferences()
+	for _, ownerRef := range object.GetOwnerReferences() {
+		if ownerRef.Controller == nil || !*ownerRef.Controller {
+			ownerReferences = append(ownerReferences, ownerRef)
+		}
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"ownerReferences": ownerReferences,
+			"resourceVersion": object.GetResourceVersion(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	namespace, name := object.GetNamespace(), object.GetName()
+	client := sc.kubeClientset()
+	patchOpts := metav1.PatchOptions{}
+	switch obj := object.(type) {
+	case *appsv1.StatefulSet:
+		_, err = client.AppsV1().StatefulSets(namespace).Patch(ctx, name, types.MergePatchType, patch, patchOpts)
+	case *corev1.Service:
+		_, err = client.CoreV1().Services(namespace).Patch(ctx, name, types.MergePatchType, patch, patchOpts)
+	case *corev1.ConfigMap:
+		_, err = client.CoreV1().ConfigMaps(namespace).Patch(ctx, name, types.MergePatchType, patch, patchOpts)
+	case *policyv1.PodDisruptionBudget:
+		_, err = client.PolicyV1().PodDisruptionBudgets(namespace).Patch(ctx, name, types.MergePatchType, patch, patchOpts)
+	case *policyv1beta1.PodDisruptionBudget:
+		_, err = client.PolicyV1beta1().PodDisruptionBudgets(namespace).Patch(ctx, name, types.MergePatchType, patch, patchOpts)
+	case *autoscalingv2.HorizontalPodAutoscaler:
+		_, err = client.AutoscalingV2().HorizontalPodAutoscalers(namespace).Patch(ctx, name, types.MergePatchType, patch, patchOpts)
+	case *networkingv1.NetworkPolicy:
+		_, err = client.NetworkingV1().NetworkPolicies(namespace).Patch(ctx, name, types.MergePatchType, patch, patchOpts)
+	case *corev1.ServiceAccount:
+		_, err = client.CoreV1().ServiceAccounts(namespace).Patch(ctx, name, types.MergePatchType, patch, patchOpts)
+	case *rbacv1.Role:
+		_, err = client.RbacV1().Roles(namespace).Patch(ctx, name, types.MergePatchType, patch, patchOpts)
+	case *rbacv1.RoleBinding:
+		_, err = client.RbacV1().RoleBindings(namespace).Patch(ctx, name, types.MergePatchType, patch, patchOpts)
+	case *batchv1.Job:
+		_, err = client.BatchV1().Jobs(namespace).Patch(ctx, name, types.MergePatchType, patch, patchOpts)
+	case *unstructured.Unstructured:
+		// Gateway API resources
+		gvr, supported := gatewayAPIResources[obj.GetKind()]
+		if !supported {
+			return debug.InternalError(fmt.Errorf("adopting an object of kind %q is not supported", obj.GetKind()))
+		}
+		_, err = sc.dynamicClient.Resource(gvr).Namespace(namespace).Patch(
+			ctx, name, types.MergePatchType, patch, patchOpts)
+	default:
+		return debug.InternalError(fmt.Errorf("adopting an object of type %T is not supported", object))
+	}
+
+	return err
+}
+
+// ensureOwnedByNdbCluster returns an error if the given object is not
+// owned by the NdbCluster resource being synced. If the object was
+// created for the NdbCluster but has lost its owner reference, the
+// NdbCluster adopts it rather than failing the sync forever.
+func (sc *SyncContext) ensureOwnedByNdbCluster(ctx context.Context, object metav1.Object) error {
+	if metav1.IsControlledBy(object, sc.ndb) {
+		// Object is owned by the NdbCluster
+		return nil
+	}
+
+	if sc.canAdoptObject(object) {
+		objectName := getNamespacedName(object)
+		sc.logger.Info("Adopting the orphaned resource", "resource", objectName)
+		if err := sc.adoptObject(ctx, object); err != nil {
+			sc.logger.Error(err, "Failed to adopt the orphaned resource", "resource", objectName)
+			return err
+		}
+
+		sc.recorder.Eventf(sc.ndb, nil,
+			corev1.EventTypeNormal, ReasonResourceAdopted, ActionAdopted, MessageResourceAdopted, objectName)
+		return nil
+	}
+
+	// The object is not owned by the NdbCluster resource being synced.
+	// Record a warning and return an error.
+	err := fmt.Errorf(MessageResourceExists, getNamespacedName(object))
+	sc.recorder.Eventf(sc.ndb, nil,
+		corev1.EventTypeWarning, ReasonResourceExists, ActionNone, err.Error())
+	return err
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_ensureOwnedByNdbCluster(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.UID = "current-uid"
+
+	// Controller reference to a previous incarnation of the NdbCluster
+	staleNdb := ndb.DeepCopy()
+	staleNdb.UID = "stale-uid"
+	staleOwnerRefs := staleNdb.GetOwnerReferences()
+
+	// Controller reference to some other resource
+	isController := true
+	otherOwnerRefs := []metav1.OwnerReference{{
+		APIVersion: "apps/v1", Kind: "Deployment", Name: "test", UID: "other-uid", Controller: &isController}}
+
+	for _, tc := range []struct {
+		desc            string
+		labels          map[string]string
+		ownerReferences []metav1.OwnerReference
+		expectAdoption  bool
+		expectError     bool
+	}{
+		{
+			desc:            "owned by NdbCluster",
+			labels:          ndb.GetLabels(),
+			ownerReferences: ndb.GetOwnerReferences(),
+		},
+		{
+			desc:           "orphan with cluster label is adopted",
+			labels:         ndb.GetLabels(),
+			expectAdoption: true,
+		},
+		{
+			desc:            "owned by stale NdbCluster is adopted",
+			labels:          ndb.GetLabels(),
+			ownerReferences: staleOwnerRefs,
+			expectAdoption:  true,
+		},
+		{
+			desc:        "orphan without cluster label",
+			expectError: true,
+		},
+		{
+			desc:        "orphan with label of a different NdbCluster",
+			labels:      map[string]string{constants.ClusterLabel: "other"},
+			expectError: true,
+		},
+		{
+			desc:            "controlled by another resource",
+			labels:          ndb.GetLabels(),
+			ownerReferences: otherOwnerRefs,
+			expectError:     true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			f := newFixture(t, ndb)
+			defer f.close()
+			f.newController()
+
+			ctx := context.Background()
+			sfset := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "test-mgmd",
+					Namespace:       ns,
+					Labels:          tc.labels,
+					OwnerReferences: tc.ownerReferences,
+				},
+			}
+			sfset, err := f.k8sclient.AppsV1().StatefulSets(ns).Create(ctx, sfset, metav1.CreateOptions{})
+			if err != nil {
+				t.Fatal("Unexpected error :", err)
+			}
+
+			sc := f.c.newSyncContext(ctx, ndb)
+			err = sc.ensureOwnedByNdbCluster(ctx, sfset)
+			if tc.expectError != (err != nil) {
+				t.Fatalf("Unexpected result from ensureOwnedByNdbCluster, error : %v", err)
+			}
+
+			// Verify that the object was patched only if it had to be adopted
+			patched := false
+			for _, action := range f.k8sclient.Actions() {
+				if action.Matches("patch", "statefulsets") {
+					patched = true
+				}
+			}
+			if patched != tc.expectAdoption {
+				t.Fatalf("Expected adoption : %v, but the StatefulSet patched : %v", tc.expectAdoption, patched)
+			}
+
+			if tc.expectAdoption {
+				adoptedSfset, err := f.k8sclient.AppsV1().StatefulSets(ns).Get(ctx, sfset.Name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatal("Unexpected error :", err)
+				}
+
+				if controllerRef := metav1.GetControllerOf(adoptedSfset); controllerRef == nil ||
+					controllerRef.UID != types.UID("current-uid") {
+					t.Errorf("StatefulSet was not adopted by the NdbCluster : %#v", adoptedSfset.OwnerReferences)
+				}
+			}
+		})
+	}
+}
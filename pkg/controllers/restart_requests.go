@@ -0,0 +1,151 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// reconcileRestartRequests applies the requests for a rolling restart
+// of the MySQL Cluster nodes, made via the annotations of the NdbCluster
+// resource, to the config map. The requested restarts are then rolled
+// out by the same steps that restart the nodes to apply a new config,
+// with the same guarantees on the availability of the MySQL Cluster.
+// The requested restarts of single data nodes are done first, one
+// data node per loop.
+func (sc *SyncContext) reconcileRestartRequests(ctx context.Context) syncResult {
+	nc := sc.ndb
+	if nodeId, restartRequest := sc.configSummary.GetPendingDataNodeRestartRequest(nc); nodeId != 0 {
+		return sc.restartDataNode(ctx, nodeId, restartRequest)
+	}
+
+	if !sc.configSummary.RestartRequestsNeedUpdate(nc) {
+		// All the requested restarts have been applied
+		return continueProcessing()
+	}
+
+	sc.logger.Info("New rolling restarts of the MySQL Cluster nodes have been requested",
+		"restartRequests", nc.GetRestartRequests())
+	if _, err := sc.configMapController.PatchRestartRequests(ctx, sc); err != nil {
+		sc.logger.Error(err, "Failed to patch the ConfigMap with the restart requests")
+		return errorWhileProcessing(err)
+	}
+
+	sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonRestartRequested, ActionRestart,
+		"MySQL Cluster nodes are being restarted as requested via the NdbCluster annotations")
+
+	// The restarts will be rolled out to the
+	// MySQL Cluster nodes starting from the next loop
+	return finishProcessing()
+}
+
+// restartDataNode gracefully restarts the data node with the given node
+// id, as requested via the annotations of the NdbCluster resource, by
+// deleting the pod it runs in. The data node is restarted only if all
+// the other data nodes of its node group are connected to the MySQL
+// Cluster, so that the data stays available during the restart.
+func (sc *SyncContext) restartDataNode(ctx context.Context, nodeId int, restartRequest string) syncResult {
+	nc := sc.ndb
+	ndbmtdSfset := sc.dataNodeSfSet
+	podName := sc.configSummary.GetNodePodName(nodeId)
+	pod, err := sc.podLister.Pods(ndbmtdSfset.Namespace).Get(podName)
+	if err != nil {
+		sc.logger.Error(err, "Failed to find pod", "pod", getNamespacedName2(ndbmtdSfset.Namespace, podName))
+		return errorWhileProcessing(err)
+	}
+
+	firstDataNodeId := sc.configSummary.GetFirstDataNodeId()
+	peers := getNodeGroupPeers(nodeId, firstDataNodeId, int(sc.configSummary.RedundancyLevel))
+	if len(peers) == 0 {
+		// Restarting the only data node of the node group will make its data
+		// unavailable. Mark the request as applied without restarting it.
+		sc.logger.Info("Skipping the requested restart of the data node as it has no peers in its node group", "nodeId", nodeId)
+		sc.recorder.Eventf(nc, pod, corev1.EventTypeWarning, ReasonRestartBlocked, ActionNone,
+			"Restart of data node (nodeId=%d) skipped as it is the only data node of its node group", nodeId)
+		if _, err = sc.configMapController.PatchDataNodeRestartRequest(ctx, sc, nodeId, restartRequest); err != nil {
+			sc.logger.Error(err, "Failed to patch the ConfigMap with the restart request")
+			return errorWhileProcessing(err)
+		}
+		return finishProcessing()
+	}
+
+	mgmClient, err := mgmapi.NewMgmClientWithContext(ctx, nc.GetConnectstring())
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+	defer mgmClient.Disconnect()
+
+	clusterStatus, err := mgmClient.GetStatus()
+	if err != nil {
+		sc.logger.Error(err, "Error getting cluster status from management server")
+		return errorWhileProcessing(err)
+	}
+
+	// Verify that the peers from the node group are healthy
+	for _, peerNodeId := range peers {
+		if peerStatus, exists := clusterStatus[peerNodeId]; !exists || !peerStatus.IsConnected {
+			// Retry the restart in a later loop, once the peer is connected
+			sc.logger.Info("Delaying the requested restart of the data node as a peer from its node group is not connected",
+				"nodeId", nodeId, "peerNodeId", peerNodeId)
+			sc.recorder.Eventf(nc, pod, corev1.EventTypeWarning, ReasonRestartBlocked, ActionNone,
+				"Restart of data node (nodeId=%d) delayed as its peer (nodeId=%d) is not connected", nodeId, peerNodeId)
+			return finishProcessing()
+		}
+	}
+
+	// Delete the pod. The data node will be stopped gracefully
+	// and the StatefulSet controller will recreate the pod.
+	if err = sc.kubeClientset().CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil {
+		sc.logger.Error(err, "Failed to delete the pod", "pod", getNamespacedName(pod))
+		return errorWhileProcessing(err)
+	}
+
+	// Record the request as applied
+	if _, err = sc.configMapController.PatchDataNodeRestartRequest(ctx, sc, nodeId, restartRequest); err != nil {
+		sc.logger.Error(err, "Failed to patch the ConfigMap with the restart request")
+		return errorWhileProcessing(err)
+	}
+
+	sc.logger.Info("Data node is being restarted as requested", "nodeId", nodeId)
+	sc.recorder.Eventf(nc, pod, corev1.EventTypeNormal, ReasonRestartRequested, ActionRestart,
+		"Data node (nodeId=%d) is being restarted as requested via the NdbCluster annotations", nodeId)
+
+	// Stop processing. Reconciliation will continue
+	// once the StatefulSet is fully ready again.
+	return finishProcessing()
+}
+
+// hasPendingInitialRestartRequest returns true if any of the data
+// nodes is yet to be restarted for the latest initial restart request.
+func (sc *SyncContext) hasPendingInitialRestartRequest() (bool, error) {
+	ndbmtdSfset := sc.dataNodeSfSet
+	initialRestartRequest := ndbmtdSfset.Spec.Template.Annotations[statefulset.LastAppliedInitialRestartRequest]
+	if initialRestartRequest == "" {
+		// No initial restart has been requested
+		return false, nil
+	}
+
+	for ordinal := int32(0); ordinal < *ndbmtdSfset.Spec.Replicas; ordinal++ {
+		podName := fmt.Sprintf("%s-%d", ndbmtdSfset.Name, ordinal)
+		pod, err := sc.podLister.Pods(ndbmtdSfset.Namespace).Get(podName)
+		if err != nil {
+			sc.logger.Error(err, "Failed to find pod", "pod", getNamespacedName2(ndbmtdSfset.Namespace, podName))
+			return false, err
+		}
+
+		if pod.GetAnnotations()[statefulset.LastAppliedInitialRestartRequest] != initialRestartRequest {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -6,6 +6,7 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
 	"github.com/mysql/ndb-operator/pkg/resources"
@@ -18,6 +19,10 @@ import (
 	klog "k8s.io/klog/v2"
 )
 
+// secretPollInterval is the interval at which a custom
+// Secret that is yet to be created is checked for existence.
+const secretPollInterval = 15 * time.Second
+
 type SecretControlInterface interface {
 	IsControlledBy(ctx context.Context, secretName string, ndb *v1.NdbCluster) bool
 	EnsureMySQLRootPassword(ctx context.Context, ndb *v1.NdbCluster) (*corev1.Secret, error)
@@ -81,7 +86,9 @@ func NewMySQLUserPasswordSecretInterface(client kubernetes.Interface) SecretCont
 }
 
 // EnsureMySQLRootPassword checks if the MySQL root user secret exists
-// and creates a new one if it doesn't exist already
+// and creates a new one if it doesn't exist already. An existing secret
+// generated by the operator is updated to comply with the
+// RootPasswordSecretPolicy specified in the NdbCluster spec.
 func (mups *mysqlUserPasswordSecrets) EnsureMySQLRootPassword(ctx context.Context, ndb *v1.NdbCluster) (*corev1.Secret, error) {
 	// Check if the root secret exists
 	secretName, customSecret := resources.GetMySQLRootPasswordSecretName(ndb)
@@ -89,7 +96,11 @@ func (mups *mysqlUserPasswordSecrets) EnsureMySQLRootPassword(ctx context.Contex
 	secret, err := mups.secretInterface(ndb.Namespace).Get(ctx, secretName, metav1.GetOptions{})
 	if err == nil {
 		// Secret exists
-		return secret, nil
+		if customSecret {
+			// Custom secrets are managed by the user
+			return secret, nil
+		}
+		return mups.applyRootPasswordSecretPolicy(ctx, ndb, secret)
 	}
 
 	if !errors.IsNotFound(err) {
@@ -107,7 +118,7 @@ func (mups *mysqlUserPasswordSecrets) EnsureMySQLRootPassword(ctx context.Contex
 
 	// Secret not found and not a custom secret - create a new one
 	secret = resources.NewMySQLRootPasswordSecret(ndb)
-	secret, err = mups.secretInterface(ndb.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+	secret, err = mups.secretInterface(ndb.Namespace).Create(ctx, secret, createOptions())
 	if err != nil {
 		klog.Errorf("Failed to create secret %s : %v", secretName, err)
 	}
@@ -115,6 +126,79 @@ func (mups *mysqlUserPasswordSecrets) EnsureMySQLRootPassword(ctx context.Contex
 	return secret, err
 }
 
+// isLeftOverRootPasswordSecret returns true if the given root password
+// secret has been left over by a previous NdbCluster with the same name,
+// i.e. if it is owned by an NdbCluster with a different UID, or if it
+// already exists when the NdbCluster is being created. A secret found
+// later without an owner is the secret of the NdbCluster itself, released
+// by a previous Retain policy, and its password is in use by the MySQL
+// Servers.
+func isLeftOverRootPasswordSecret(secret *corev1.Secret, ndb *v1.NdbCluster) bool {
+	if ndb.Status.ProcessedGeneration == 0 {
+		// NdbCluster is being created
+		return true
+	}
+
+	for _, ownerReference := range secret.OwnerReferences {
+		if ownerReference.Kind == "NdbCluster" && ownerReference.UID != ndb.UID {
+			// Owned by a stale NdbCluster
+			return true
+		}
+	}
+	return false
+}
+
+// applyRootPasswordSecretPolicy updates the ownership of the existing root
+// password secret generated by the operator as per the RootPasswordSecretPolicy.
+// A secret that has to be retained is released from the NdbCluster, so that it
+// is not garbage collected when the NdbCluster is deleted. Any other secret is
+// adopted by the NdbCluster, except that a secret left over by a previous
+// NdbCluster is replaced with a new one if the policy is Recreate.
+func (mups *mysqlUserPasswordSecrets) applyRootPasswordSecretPolicy(
+	ctx context.Context, ndb *v1.NdbCluster, secret *corev1.Secret) (*corev1.Secret, error) {
+	policy := ndb.Spec.MysqlNode.RootPasswordSecretPolicy
+	controlled := metav1.IsControlledBy(secret, ndb)
+
+	updatedSecret := secret.DeepCopy()
+	switch {
+	case policy == v1.NdbRootPasswordSecretRetain && controlled:
+		// Release the secret from the NdbCluster
+		var ownerReferences []metav1.OwnerReference
+		for _, ownerReference := range secret.OwnerReferences {
+			if ownerReference.UID != ndb.UID {
+				ownerReferences = append(ownerReferences, ownerReference)
+			}
+		}
+		updatedSecret.OwnerReferences = ownerReferences
+	case policy == v1.NdbRootPasswordSecretRecreate && !controlled && isLeftOverRootPasswordSecret(secret, ndb):
+		// Replace the secret left over by a previous NdbCluster
+		klog.Infof("Replacing the MySQL root password Secret %q left over by a previous NdbCluster", secret.Name)
+		if err := mups.Delete(ctx, secret.Namespace, secret.Name); err != nil && !errors.IsNotFound(err) {
+			klog.Errorf("Failed to delete secret %s : %v", secret.Name, err)
+			return nil, err
+		}
+		newSecret, err := mups.secretInterface(ndb.Namespace).Create(
+			ctx, resources.NewMySQLRootPasswordSecret(ndb), createOptions())
+		if err != nil {
+			klog.Errorf("Failed to create secret %s : %v", secret.Name, err)
+		}
+		return newSecret, err
+	case policy != v1.NdbRootPasswordSecretRetain && !controlled:
+		// Adopt the secret so that it is deleted along with the NdbCluster
+		updatedSecret.OwnerReferences = ndb.GetOwnerReferences()
+	default:
+		// Secret already complies with the policy
+		return secret, nil
+	}
+
+	updatedSecret, err := mups.secretInterface(ndb.Namespace).Update(ctx, updatedSecret, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Errorf("Failed to update the owner of the secret %s : %v", secret.Name, err)
+		return nil, err
+	}
+	return updatedSecret, nil
+}
+
 // EnsureNDBOperatorPassword checks if the MySQL ndb-operator user secret exists
 // and creates a new one if it doesn't exist already
 func (mups *mysqlUserPasswordSecrets) EnsureNDBOperatorPassword(
@@ -134,9 +218,16 @@ func (mups *mysqlUserPasswordSecrets) EnsureNDBOperatorPassword(
 		return nil, err
 	}
 
-	// Secret not found create a new one
+	// Secret not found
+	if nc.Spec.MysqlNode != nil && nc.Spec.MysqlNode.NdbOperatorPasswordSecretName != "" {
+		// Secret specified in the spec doesn't exist
+		klog.Errorf("NDB operator password Secret specified in the Ndb Spec doesn't exist : %v", err)
+		return nil, err
+	}
+
+	// Secret not found and not a custom secret - create a new one
 	secret = resources.NewMySQLNDBOperatorPasswordSecret(nc)
-	secret, err = mups.secretInterface(nc.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+	secret, err = mups.secretInterface(nc.Namespace).Create(ctx, secret, createOptions())
 	if err != nil {
 		klog.Errorf("Failed to create secret %s : %v", secretName, err)
 	}
@@ -144,3 +235,40 @@ func (mups *mysqlUserPasswordSecrets) EnsureNDBOperatorPassword(
 	klog.Errorf("successfully created secret %s", secretName)
 	return secret, err
 }
+
+// waitForCustomSecret makes the sync wait for the given custom Secret,
+// specified in the NdbCluster spec, to be created. Such Secrets might be
+// materialized from an external secret store, e.g. by an ExternalSecret,
+// some time after the NdbCluster is created. The NdbCluster is requeued
+// to check for the Secret again after the secretPollInterval.
+func (sc *SyncContext) waitForCustomSecret(secretName string) syncResult {
+	sc.logger.Info("Waiting for the custom Secret to be created", "secret", secretName)
+	sc.recorder.Eventf(sc.ndb, nil, corev1.EventTypeWarning, ReasonWaitingForSecret, ActionNone,
+		"Waiting for the Secret %q specified in the spec to be created", secretName)
+	sc.requeueAfter = secretPollInterval
+	return finishProcessing()
+}
+
+// ensureMySQLRootPassword ensures that the root password Secret exists
+// before the MySQL Server StatefulSet with the given name is created. A
+// Secret synced via the SecretProviderClass is created only after it is
+// mounted into a MySQL Server, and so it is not waited for.
+func (sc *SyncContext) ensureMySQLRootPassword(ctx context.Context, sfsetName string) syncResult {
+	nc := sc.ndb
+	if nc.Spec.MysqlNode.SecretProviderClass != "" {
+		return continueProcessing()
+	}
+
+	secretClient := NewMySQLUserPasswordSecretInterface(sc.kubernetesClient)
+	if _, err := secretClient.EnsureMySQLRootPassword(ctx, nc); err != nil {
+		if errors.IsNotFound(err) {
+			// The custom secret specified in the spec is yet to be created
+			secretName, _ := resources.GetMySQLRootPasswordSecretName(nc)
+			return sc.waitForCustomSecret(secretName)
+		}
+		klog.Errorf("Failed to ensure root password secret for StatefulSet %q : %s", sfsetName, err)
+		return errorWhileProcessing(err)
+	}
+
+	return continueProcessing()
+}
@@ -8,9 +8,11 @@ import (
 	"context"
 	"testing"
 
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
 	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
 	"github.com/mysql/ndb-operator/pkg/resources"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -100,6 +102,109 @@ func TestMysqlRootPasswordSecrets(t *testing.T) {
 	}
 	f.expectDeleteAction(ns, "core", "v1", "secrets", secret.Name)
 
+	// Test custom NDB operator secret ensuring when the secret doesn't exist
+	customOperatorSecretName := "custom-ndb-operator-password"
+	ndb.Spec.MysqlNode.NdbOperatorPasswordSecretName = customOperatorSecretName
+	// Ensuring should fail
+	_, err = sci.EnsureNDBOperatorPassword(context.TODO(), ndb)
+	if err == nil {
+		t.Errorf("Expected '%s' secret not found error but got no error", customOperatorSecretName)
+	} else if !errors.IsNotFound(err) {
+		t.Errorf("Expected '%s' secret not found error but got : %v", customOperatorSecretName, err)
+	}
+	// No action is expected
+
 	// Validate all the actions
 	f.checkActions()
 }
+
+func TestMysqlRootPasswordSecretPolicy(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.UID = "uid-1"
+
+	f := newFixture(t, ndb)
+	defer f.close()
+
+	ctx := context.Background()
+	sci := NewMySQLUserPasswordSecretInterface(f.k8sclient)
+
+	// A secret to be retained should not be owned by the NdbCluster
+	ndb.Spec.MysqlNode.RootPasswordSecretPolicy = v1.NdbRootPasswordSecretRetain
+	retainedSecret, err := sci.EnsureMySQLRootPassword(ctx, ndb)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if len(retainedSecret.OwnerReferences) != 0 {
+		t.Errorf("Retained secret should not have any owner but got %v", retainedSecret.OwnerReferences)
+	}
+
+	// The retained secret should be adopted when the policy is changed to Delete
+	ndb.Spec.MysqlNode.RootPasswordSecretPolicy = v1.NdbRootPasswordSecretDelete
+	secret, err := sci.EnsureMySQLRootPassword(ctx, ndb)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if !metav1.IsControlledBy(secret, ndb) {
+		t.Error("Secret was not adopted by the NdbCluster")
+	}
+	if string(secret.Data[corev1.BasicAuthPasswordKey]) != string(retainedSecret.Data[corev1.BasicAuthPasswordKey]) {
+		t.Error("Password should not be changed when the secret is adopted")
+	}
+
+	// The secret should be released when the policy is changed back to Retain
+	ndb.Spec.MysqlNode.RootPasswordSecretPolicy = v1.NdbRootPasswordSecretRetain
+	if secret, err = sci.EnsureMySQLRootPassword(ctx, ndb); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if len(secret.OwnerReferences) != 0 {
+		t.Errorf("Secret was not released by the NdbCluster : %v", secret.OwnerReferences)
+	}
+
+	// A running NdbCluster should adopt its released secret, without
+	// changing the password, when the policy is changed to Recreate
+	ndb.Status.ProcessedGeneration = 1
+	ndb.Spec.MysqlNode.RootPasswordSecretPolicy = v1.NdbRootPasswordSecretRecreate
+	if secret, err = sci.EnsureMySQLRootPassword(ctx, ndb); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if !metav1.IsControlledBy(secret, ndb) {
+		t.Error("Secret was not adopted by the NdbCluster")
+	}
+	if string(secret.Data[corev1.BasicAuthPasswordKey]) != string(retainedSecret.Data[corev1.BasicAuthPasswordKey]) {
+		t.Error("Password of a running NdbCluster should not be changed when the policy is changed to Recreate")
+	}
+
+	// A running NdbCluster should replace a secret owned by a stale NdbCluster
+	ndb.UID = "uid-2"
+	if secret, err = sci.EnsureMySQLRootPassword(ctx, ndb); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if !metav1.IsControlledBy(secret, ndb) {
+		t.Error("Secret owned by a stale NdbCluster was not recreated")
+	}
+	if string(secret.Data[corev1.BasicAuthPasswordKey]) == string(retainedSecret.Data[corev1.BasicAuthPasswordKey]) {
+		t.Error("Recreated secret should have a new password")
+	}
+
+	// Release the secret again
+	ndb.Spec.MysqlNode.RootPasswordSecretPolicy = v1.NdbRootPasswordSecretRetain
+	if retainedSecret, err = sci.EnsureMySQLRootPassword(ctx, ndb); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	// A new NdbCluster with the same name should replace the
+	// secret left over by the previous one when set to Recreate
+	ndb.UID = "uid-3"
+	ndb.Status.ProcessedGeneration = 0
+	ndb.Spec.MysqlNode.RootPasswordSecretPolicy = v1.NdbRootPasswordSecretRecreate
+	if secret, err = sci.EnsureMySQLRootPassword(ctx, ndb); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if !metav1.IsControlledBy(secret, ndb) {
+		t.Error("Secret was not recreated for the new NdbCluster")
+	}
+	if string(secret.Data[corev1.BasicAuthPasswordKey]) == string(retainedSecret.Data[corev1.BasicAuthPasswordKey]) {
+		t.Error("Recreated secret should have a new password")
+	}
+}
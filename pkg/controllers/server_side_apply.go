@@ -0,0 +1,89 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"encoding/json"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// fieldManager is the name of the field manager used by the
+// NDB Operator when creating or applying changes to resources.
+const fieldManager = "ndb-operator"
+
+// applyPatchOptions returns the PatchOptions to be used when
+// applying a resource via server side apply. The operator is
+// the sole owner of the fields it sets, so any conflicts with
+// other field managers are forcibly resolved in its favour.
+func applyPatchOptions() metav1.PatchOptions {
+	force := true
+	return metav1.PatchOptions{
+		FieldManager: fieldManager,
+		Force:        &force,
+	}
+}
+
+// createOptions returns the CreateOptions to be used when creating a resource
+func createOptions() metav1.CreateOptions {
+	return metav1.CreateOptions{
+		FieldManager: fieldManager,
+	}
+}
+
+// newApplyPatch generates the server side apply patch for the given object.
+// The object's server populated metadata (resourceVersion, managedFields)
+// is dropped from the patch so that the apply only expresses the fields
+// owned by the operator.
+func newApplyPatch(obj runtime.Object, gvk schema.GroupVersionKind) ([]byte, error) {
+	obj = obj.DeepCopyObject()
+	// Apply patches require the apiVersion and kind to be set
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	objMeta.SetResourceVersion("")
+	objMeta.SetManagedFields(nil)
+
+	return json.Marshal(obj)
+}
+
+// getAppliedAnnotationKeys returns the keys of the annotations of the given
+// object that are owned by the operator's apply field manager. The other
+// annotations have been set by other controllers or users.
+func getAppliedAnnotationKeys(objMeta metav1.Object) map[string]bool {
+	keys := make(map[string]bool)
+	for _, managedFields := range objMeta.GetManagedFields() {
+		if managedFields.Manager != fieldManager ||
+			managedFields.Operation != metav1.ManagedFieldsOperationApply ||
+			managedFields.FieldsV1 == nil {
+			continue
+		}
+
+		// The owned fields are encoded as a nested map of the field paths
+		var fields struct {
+			Metadata struct {
+				Annotations map[string]interface{} `json:"f:annotations"`
+			} `json:"f:metadata"`
+		}
+		if err := json.Unmarshal(managedFields.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+
+		for field := range fields.Metadata.Annotations {
+			if key, isKey := strings.CutPrefix(field, "f:"); isKey {
+				keys[key] = true
+			}
+		}
+	}
+
+	return keys
+}
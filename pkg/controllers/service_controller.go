@@ -6,11 +6,11 @@ package controllers
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -53,13 +53,13 @@ func (svcCtrl *serviceControl) ensureService(
 	ndbSfset statefulset.NdbStatefulSetInterface) (*corev1.Service, error) {
 
 	nc := sc.ndb
-	serviceName := nc.GetServiceName(ndbSfset.GetTypeName())
+	serviceName := ndbSfset.GetServiceName(nc)
 
 	svc, err := svcCtrl.serviceLister.Services(nc.Namespace).Get(serviceName)
 
 	if err == nil {
 		// Service exists already
-		if err = sc.isOwnedByNdbCluster(svc); err != nil {
+		if err = sc.ensureOwnedByNdbCluster(ctx, svc); err != nil {
 			// But it is not owned by the NdbCluster resource
 			klog.Errorf(
 				"Attempting to create service %q failed as it exists already but not owned by NdbCluster resource %q",
@@ -80,14 +80,85 @@ func (svcCtrl *serviceControl) ensureService(
 	// Service not found - create it
 	svc = ndbSfset.NewGoverningService(nc)
 	klog.Infof("Creating a new Service %q for NdbCluster resource %q", getNamespacedName(svc), getNamespacedName(sc.ndb))
-	svc, err = svcCtrl.getServiceInterface(sc.ndb.Namespace).Create(ctx, svc, metav1.CreateOptions{})
-	if err != nil && !apierrors.IsAlreadyExists(err) {
-		// Create failed. Ignore AlreadyExists error as it
-		// might have been caused due to an outdated cache read.
-		klog.Errorf("Error creating Service %q : %s", getNamespacedName(svc), err)
+	createdSvc, err := svcCtrl.getServiceInterface(nc.Namespace).Create(ctx, svc, createOptions())
+	if err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			klog.Errorf("Error creating Service %q : %s", getNamespacedName(svc), err)
+			return nil, err
+		}
+
+		// The Service was created already but the cache didn't have it
+		// yet. Read it from the API Server rather than applying the
+		// initial spec over it.
+		if createdSvc, err = svcCtrl.getServiceInterface(nc.Namespace).Get(
+			ctx, serviceName, metav1.GetOptions{}); err != nil {
+			klog.Errorf("Error getting Service %q : %s", getNamespacedName(svc), err)
+			return nil, err
+		}
+	}
+
+	return createdSvc, nil
+}
+
+// applyService creates or updates the given Service using server side apply
+func (svcCtrl *serviceControl) applyService(ctx context.Context, svc *corev1.Service) (*corev1.Service, error) {
+	patch, err := newApplyPatch(svc, corev1.SchemeGroupVersion.WithKind("Service"))
+	if err != nil {
+		klog.Errorf("Failed to generate the apply patch for Service %q : %s", getNamespacedName(svc), err)
+		return nil, err
+	}
+
+	appliedSvc, err := svcCtrl.getServiceInterface(svc.Namespace).Patch(
+		ctx, svc.Name, types.ApplyPatchType, patch, applyPatchOptions())
+	if err != nil {
+		klog.Errorf("Failed to apply the Service %q : %s", getNamespacedName(svc), err)
 		return nil, err
 	}
-	return svc, nil
+
+	return appliedSvc, nil
+}
+
+// serviceAnnotationsEqual checks if the annotations managed by the operator
+// in the current Service are equal to the annotations of the updated Service.
+// The annotations added by other controllers are ignored, and the annotations
+// previously applied by the operator but no longer in the updated Service are
+// treated as a change, so that they get removed by the next apply.
+func serviceAnnotationsEqual(current, updated *corev1.Service) bool {
+	for key, value := range updated.Annotations {
+		if currentValue, exists := current.Annotations[key]; !exists || currentValue != value {
+			return false
+		}
+	}
+
+	for key := range getAppliedAnnotationKeys(current) {
+		if _, exists := updated.Annotations[key]; !exists {
+			return false
+		}
+	}
+
+	return true
+}
+
+// loadBalancerConfigEqual checks if the load balancer configuration of the
+// current Service matches the one of the updated Service. The fields that
+// are not set in the updated Service are ignored, as they might have been
+// defaulted by the K8s API Server.
+func loadBalancerConfigEqual(current, updated *corev1.Service) bool {
+	if updated.Spec.ExternalTrafficPolicy != "" &&
+		current.Spec.ExternalTrafficPolicy != updated.Spec.ExternalTrafficPolicy {
+		return false
+	}
+
+	if updated.Spec.LoadBalancerClass != nil &&
+		!equality.Semantic.DeepEqual(current.Spec.LoadBalancerClass, updated.Spec.LoadBalancerClass) {
+		return false
+	}
+
+	// Compare the source ranges, treating nil and empty ranges as equal
+	if len(current.Spec.LoadBalancerSourceRanges) == 0 && len(updated.Spec.LoadBalancerSourceRanges) == 0 {
+		return true
+	}
+	return equality.Semantic.DeepEqual(current.Spec.LoadBalancerSourceRanges, updated.Spec.LoadBalancerSourceRanges)
 }
 
 // patchService patches the given service if required
@@ -105,21 +176,20 @@ func (svcCtrl *serviceControl) patchService(
 	nc := sc.ndb
 	updatedSvc := ndbSfset.NewGoverningService(nc)
 
-	// Only changing the Service type is supported
-	if currentSvc.Spec.Type == updatedSvc.Spec.Type {
+	// Only changing the Service type, the custom annotations and the load
+	// balancer configuration is supported. The publishNotReadyAddresses is
+	// also restored if it has been disabled, as the per-pod DNS records of
+	// the MySQL Cluster nodes are required before they become ready.
+	if currentSvc.Spec.Type == updatedSvc.Spec.Type &&
+		currentSvc.Spec.PublishNotReadyAddresses == updatedSvc.Spec.PublishNotReadyAddresses &&
+		serviceAnnotationsEqual(currentSvc, updatedSvc) &&
+		loadBalancerConfigEqual(currentSvc, updatedSvc) {
 		// No change to service
 		return nil
 	}
 
-	// For some reason the "regular" patch method do not work for Services.
-	// Use a JSON Merge patch instead
-	jsonMergePatch := fmt.Sprintf(`{"spec":{"type":"%s"}}`, updatedSvc.Spec.Type)
-
-	// Patch the service
-	_, err = svcCtrl.getServiceInterface(currentSvc.Namespace).Patch(
-		ctx, currentSvc.GetName(), types.MergePatchType, []byte(jsonMergePatch), metav1.PatchOptions{})
-	if err != nil {
-		klog.Errorf("Failed to patch the service %q : %s", getNamespacedName(currentSvc), err)
+	// Apply the updated service
+	if _, err = svcCtrl.applyService(ctx, updatedSvc); err != nil {
 		return err
 	}
 
@@ -0,0 +1,90 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_serviceAnnotationsEqual(t *testing.T) {
+	// newService returns a Service with the given annotations, of
+	// which the appliedKeys are owned by the operator's field manager
+	newService := func(annotations map[string]string, appliedKeys ...string) *corev1.Service {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Annotations: annotations},
+		}
+		if len(appliedKeys) != 0 {
+			fields := `{"f:metadata":{"f:annotations":{`
+			for i, key := range appliedKeys {
+				if i != 0 {
+					fields += ","
+				}
+				fields += `"f:` + key + `":{}`
+			}
+			fields += `}}}`
+			svc.ManagedFields = []metav1.ManagedFieldsEntry{
+				{
+					Manager:   "other-controller",
+					Operation: metav1.ManagedFieldsOperationUpdate,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{"f:other":{}}}}`)},
+				},
+				{
+					Manager:   fieldManager,
+					Operation: metav1.ManagedFieldsOperationApply,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(fields)},
+				},
+			}
+		}
+		return svc
+	}
+
+	for _, tc := range []struct {
+		desc    string
+		current *corev1.Service
+		updated *corev1.Service
+		equal   bool
+	}{
+		{
+			desc:    "no annotations",
+			current: newService(nil),
+			updated: newService(nil),
+			equal:   true,
+		},
+		{
+			desc:    "annotations added by other controllers",
+			current: newService(map[string]string{"other": "value"}),
+			updated: newService(nil),
+			equal:   true,
+		},
+		{
+			desc:    "applied annotations unchanged",
+			current: newService(map[string]string{"custom": "value", "other": "value"}, "custom"),
+			updated: newService(map[string]string{"custom": "value"}),
+			equal:   true,
+		},
+		{
+			desc:    "custom annotation added",
+			current: newService(map[string]string{"other": "value"}),
+			updated: newService(map[string]string{"custom": "value"}),
+		},
+		{
+			desc:    "custom annotation changed",
+			current: newService(map[string]string{"custom": "value"}, "custom"),
+			updated: newService(map[string]string{"custom": "new-value"}),
+		},
+		{
+			desc:    "custom annotation removed",
+			current: newService(map[string]string{"custom": "value", "other": "value"}, "custom"),
+			updated: newService(nil),
+		},
+	} {
+		if equal := serviceAnnotationsEqual(tc.current, tc.updated); equal != tc.equal {
+			t.Errorf("Testcase %q failed : expected %v but got %v", tc.desc, tc.equal, equal)
+		}
+	}
+}
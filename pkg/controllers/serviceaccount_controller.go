@@ -0,0 +1,203 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	rbaclisters "k8s.io/client-go/listers/rbac/v1"
+	klog "k8s.io/klog/v2"
+)
+
+type ServiceAccountControlInterface interface {
+	ReconcileServiceAccount(ctx context.Context, sc *SyncContext) syncResult
+}
+
+type serviceAccountImpl struct {
+	k8sClient            kubernetes.Interface
+	serviceAccountLister corelisters.ServiceAccountLister
+	roleLister           rbaclisters.RoleLister
+	roleBindingLister    rbaclisters.RoleBindingLister
+}
+
+// newServiceAccountControl creates a new ServiceAccountControlInterface
+func newServiceAccountControl(
+	client kubernetes.Interface,
+	serviceAccountLister corelisters.ServiceAccountLister,
+	roleLister rbaclisters.RoleLister,
+	roleBindingLister rbaclisters.RoleBindingLister) ServiceAccountControlInterface {
+	return &serviceAccountImpl{
+		k8sClient:            client,
+		serviceAccountLister: serviceAccountLister,
+		roleLister:           roleLister,
+		roleBindingLister:    roleBindingLister,
+	}
+}
+
+// agentResource is one of the resources created by the operator
+// for the agents running in the MySQL Cluster pods
+type agentResource struct {
+	// desired is the resource as required by the NdbCluster spec
+	desired runtime.Object
+	gvk     schema.GroupVersionKind
+	// get retrieves the existing resource from the cache
+	get func() (metav1.Object, error)
+	// upToDate returns true if the existing resource matches the desired one
+	upToDate func(existing metav1.Object) bool
+	// apply applies the given patch to the resource
+	apply func(ctx context.Context, patch []byte) error
+	// delete deletes the existing resource
+	delete func(ctx context.Context) error
+}
+
+// getAgentResources returns the ServiceAccount, the Role and the
+// RoleBinding created for the agents, in the order of their creation
+func (sai *serviceAccountImpl) getAgentResources(sc *SyncContext) []agentResource {
+	nc := sc.ndb
+	name := nc.GetAgentServiceAccountName()
+	serviceAccountInterface := sai.k8sClient.CoreV1().ServiceAccounts(nc.Namespace)
+	roleInterface := sai.k8sClient.RbacV1().Roles(nc.Namespace)
+	roleBindingInterface := sai.k8sClient.RbacV1().RoleBindings(nc.Namespace)
+
+	role := resources.NewAgentRole(nc)
+	roleBinding := resources.NewAgentRoleBinding(nc)
+	return []agentResource{
+		{
+			desired: resources.NewAgentServiceAccount(nc),
+			gvk:     corev1.SchemeGroupVersion.WithKind("ServiceAccount"),
+			get: func() (metav1.Object, error) {
+				serviceAccount, err := sai.serviceAccountLister.ServiceAccounts(nc.Namespace).Get(name)
+				if err != nil {
+					return nil, err
+				}
+				return serviceAccount, nil
+			},
+			// The ServiceAccount has nothing to be updated
+			upToDate: func(metav1.Object) bool { return true },
+			apply: func(ctx context.Context, patch []byte) error {
+				_, err := serviceAccountInterface.Patch(ctx, name, types.ApplyPatchType, patch, applyPatchOptions())
+				return err
+			},
+			delete: func(ctx context.Context) error {
+				return serviceAccountInterface.Delete(ctx, name, metav1.DeleteOptions{})
+			},
+		},
+		{
+			desired: role,
+			gvk:     rbacv1.SchemeGroupVersion.WithKind("Role"),
+			get: func() (metav1.Object, error) {
+				existingRole, err := sai.roleLister.Roles(nc.Namespace).Get(name)
+				if err != nil {
+					return nil, err
+				}
+				return existingRole, nil
+			},
+			upToDate: func(existing metav1.Object) bool {
+				return equality.Semantic.DeepEqual(existing.(*rbacv1.Role).Rules, role.Rules)
+			},
+			apply: func(ctx context.Context, patch []byte) error {
+				_, err := roleInterface.Patch(ctx, name, types.ApplyPatchType, patch, applyPatchOptions())
+				return err
+			},
+			delete: func(ctx context.Context) error {
+				return roleInterface.Delete(ctx, name, metav1.DeleteOptions{})
+			},
+		},
+		{
+			desired: roleBinding,
+			gvk:     rbacv1.SchemeGroupVersion.WithKind("RoleBinding"),
+			get: func() (metav1.Object, error) {
+				existingRoleBinding, err := sai.roleBindingLister.RoleBindings(nc.Namespace).Get(name)
+				if err != nil {
+					return nil, err
+				}
+				return existingRoleBinding, nil
+			},
+			upToDate: func(existing metav1.Object) bool {
+				existingRoleBinding := existing.(*rbacv1.RoleBinding)
+				return equality.Semantic.DeepEqual(existingRoleBinding.Subjects, roleBinding.Subjects) &&
+					existingRoleBinding.RoleRef == roleBinding.RoleRef
+			},
+			apply: func(ctx context.Context, patch []byte) error {
+				_, err := roleBindingInterface.Patch(ctx, name, types.ApplyPatchType, patch, applyPatchOptions())
+				return err
+			},
+			delete: func(ctx context.Context) error {
+				return roleBindingInterface.Delete(ctx, name, metav1.DeleteOptions{})
+			},
+		},
+	}
+}
+
+// ReconcileServiceAccount creates or updates the ServiceAccount, the Role
+// and the RoleBinding of the agents running in the MySQL Cluster pods if
+// the operator has to create them, and deletes them otherwise.
+func (sai *serviceAccountImpl) ReconcileServiceAccount(ctx context.Context, sc *SyncContext) syncResult {
+	nc := sc.ndb
+	resourceName := getNamespacedName2(nc.Namespace, nc.GetAgentServiceAccountName())
+	for _, resource := range sai.getAgentResources(sc) {
+		kind := resource.gvk.Kind
+		existing, err := resource.get()
+		if err != nil && !apierrors.IsNotFound(err) {
+			// Error retrieving the resource from the cache
+			klog.Errorf("Failed to retrieve %s %q : %s", kind, resourceName, err)
+			return errorWhileProcessing(err)
+		}
+
+		if existing != nil {
+			// Resource exists. Verify that it is owned by the NdbCluster resource.
+			if err = sc.ensureOwnedByNdbCluster(ctx, existing); err != nil {
+				return errorWhileProcessing(err)
+			}
+		}
+
+		if !nc.AgentServiceAccountEnabled() {
+			if existing == nil {
+				// Resource is not required and there is none to delete
+				continue
+			}
+
+			// Resource is no longer required - delete the existing one
+			if err = resource.delete(ctx); err != nil && !apierrors.IsNotFound(err) {
+				klog.Errorf("Failed to delete %s %q : %s", kind, resourceName, err)
+				return errorWhileProcessing(err)
+			}
+			klog.Infof("Deleted %s %q", kind, resourceName)
+			continue
+		}
+
+		if existing != nil && resource.upToDate(existing) {
+			// Resource is up-to-date
+			continue
+		}
+
+		// Create or update the resource
+		patch, err := newApplyPatch(resource.desired, resource.gvk)
+		if err != nil {
+			klog.Errorf("Failed to generate the apply patch for %s %q : %s", kind, resourceName, err)
+			return errorWhileProcessing(err)
+		}
+
+		if err = resource.apply(ctx, patch); err != nil {
+			klog.Errorf("Failed to apply the %s %q : %s", kind, resourceName, err)
+			return errorWhileProcessing(err)
+		}
+		klog.Infof("%s %q has been applied successfully", kind, resourceName)
+	}
+
+	return continueProcessing()
+}
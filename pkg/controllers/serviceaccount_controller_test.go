@@ -0,0 +1,190 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/resources"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	rbaclisters "k8s.io/client-go/listers/rbac/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func Test_NewAgentResources(t *testing.T) {
+	nc := testutils.NewTestNdb("default", "example-ndb", 2)
+	nc.Spec.ServiceAccount = &v1.NdbServiceAccountSpec{}
+
+	// The Role and the RoleBinding should have the name of the ServiceAccount
+	serviceAccount := resources.NewAgentServiceAccount(nc)
+	role := resources.NewAgentRole(nc)
+	roleBinding := resources.NewAgentRoleBinding(nc)
+	for _, objectMeta := range []metav1.ObjectMeta{serviceAccount.ObjectMeta, role.ObjectMeta, roleBinding.ObjectMeta} {
+		if objectMeta.Name != "example-ndb-ndb-agent" || objectMeta.Namespace != nc.Namespace {
+			t.Errorf("Unexpected name %q", getNamespacedName2(objectMeta.Namespace, objectMeta.Name))
+		}
+	}
+
+	// The RoleBinding should bind the Role to the ServiceAccount
+	expectedSubjects := []rbacv1.Subject{
+		{Kind: rbacv1.ServiceAccountKind, Name: serviceAccount.Name, Namespace: nc.Namespace},
+	}
+	if !reflect.DeepEqual(roleBinding.Subjects, expectedSubjects) {
+		t.Errorf("Unexpected RoleBinding subjects : %v", roleBinding.Subjects)
+	}
+	expectedRoleRef := rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: role.Name}
+	if roleBinding.RoleRef != expectedRoleRef {
+		t.Errorf("Unexpected RoleBinding roleRef : %v", roleBinding.RoleRef)
+	}
+
+	// The default rules should allow reading the pods and the NdbCluster
+	if len(role.Rules) != 2 || role.Rules[1].ResourceNames[0] != nc.Name {
+		t.Errorf("Unexpected default rules : %v", role.Rules)
+	}
+
+	// The rules specified in the NdbCluster should replace the default ones
+	rules := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get"}},
+	}
+	nc.Spec.ServiceAccount.Rules = rules
+	if role = resources.NewAgentRole(nc); !reflect.DeepEqual(role.Rules, rules) {
+		t.Errorf("Expected the rules %v but got %v", rules, role.Rules)
+	}
+}
+
+func Test_ReconcileServiceAccount(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.UID = "ndb-uid"
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	ctx := context.Background()
+	serviceAccountIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	roleIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	roleBindingIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	sai := newServiceAccountControl(f.k8sclient,
+		corelisters.NewServiceAccountLister(serviceAccountIndexer),
+		rbaclisters.NewRoleLister(roleIndexer),
+		rbaclisters.NewRoleBindingLister(roleBindingIndexer))
+	name := ndb.GetAgentServiceAccountName()
+
+	// countPatches returns the number of patches sent for the given resource
+	countPatches := func(resource string) int {
+		patches := 0
+		for _, action := range f.k8sclient.Actions() {
+			if action.Matches("patch", resource) {
+				patches++
+			}
+		}
+		return patches
+	}
+
+	// getAll retrieves all the resources created for the agents
+	getAll := func() (err [3]error) {
+		_, err[0] = f.k8sclient.CoreV1().ServiceAccounts(ns).Get(ctx, name, metav1.GetOptions{})
+		_, err[1] = f.k8sclient.RbacV1().Roles(ns).Get(ctx, name, metav1.GetOptions{})
+		_, err[2] = f.k8sclient.RbacV1().RoleBindings(ns).Get(ctx, name, metav1.GetOptions{})
+		return err
+	}
+
+	// Nothing should be created when the spec doesn't have a ServiceAccount
+	sc := f.c.newSyncContext(ctx, ndb)
+	if sr := sai.ReconcileServiceAccount(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	for _, err := range getAll() {
+		if !apierrors.IsNotFound(err) {
+			t.Errorf("Agent resources created when no ServiceAccount is specified : %v", err)
+		}
+	}
+
+	// Nothing should be created when an existing ServiceAccount is specified
+	existing := ndb.DeepCopy()
+	existing.Spec.ServiceAccount = &v1.NdbServiceAccountSpec{Name: "mysql-agent"}
+	sc = f.c.newSyncContext(ctx, existing)
+	if sr := sai.ReconcileServiceAccount(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	for _, err := range getAll() {
+		if !apierrors.IsNotFound(err) {
+			t.Errorf("Agent resources created for an existing ServiceAccount : %v", err)
+		}
+	}
+
+	// The missing resources should be created via server side apply
+	enabled := ndb.DeepCopy()
+	enabled.Spec.ServiceAccount = &v1.NdbServiceAccountSpec{}
+	sc = f.c.newSyncContext(ctx, enabled)
+	if sr := sai.ReconcileServiceAccount(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	for _, err := range getAll() {
+		if err != nil {
+			t.Fatalf("Agent resources were not created : %s", err)
+		}
+	}
+	for _, resource := range []string{"serviceaccounts", "roles", "rolebindings"} {
+		if countPatches(resource) != 1 {
+			t.Errorf("Agent %s not created via an apply", resource)
+		}
+	}
+
+	// Up-to-date resources should not be applied again
+	serviceAccount, _ := f.k8sclient.CoreV1().ServiceAccounts(ns).Get(ctx, name, metav1.GetOptions{})
+	role, _ := f.k8sclient.RbacV1().Roles(ns).Get(ctx, name, metav1.GetOptions{})
+	roleBinding, _ := f.k8sclient.RbacV1().RoleBindings(ns).Get(ctx, name, metav1.GetOptions{})
+	for indexer, obj := range map[cache.Indexer]interface{}{
+		serviceAccountIndexer: serviceAccount,
+		roleIndexer:           role,
+		roleBindingIndexer:    roleBinding,
+	} {
+		if err := indexer.Add(obj); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+	if sr := sai.ReconcileServiceAccount(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	for _, resource := range []string{"serviceaccounts", "roles", "rolebindings"} {
+		if countPatches(resource) != 1 {
+			t.Errorf("Up-to-date agent %s applied again", resource)
+		}
+	}
+
+	// Only the Role should be updated when the rules change
+	withRules := enabled.DeepCopy()
+	withRules.Spec.ServiceAccount.Rules = []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list"}},
+	}
+	sc = f.c.newSyncContext(ctx, withRules)
+	if sr := sai.ReconcileServiceAccount(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	if countPatches("roles") != 2 || countPatches("serviceaccounts") != 1 || countPatches("rolebindings") != 1 {
+		t.Error("Expected only the agent Role to be updated when the rules changed")
+	}
+
+	// The resources should be deleted once the spec no longer requires them
+	sc = f.c.newSyncContext(ctx, existing)
+	if sr := sai.ReconcileServiceAccount(ctx, sc); sr.getError() != nil {
+		t.Fatal("Unexpected error :", sr.getError())
+	}
+	for _, err := range getAll() {
+		if !apierrors.IsNotFound(err) {
+			t.Errorf("Agent resources not deleted when they are no longer required : %v", err)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// maxSyncHistoryLength is the number of recent
+// sync results retained for every NdbCluster
+const maxSyncHistoryLength = 10
+
+// Keys of the state dump ConfigMap
+const (
+	// stateDumpRequestKey has the dump request that was served
+	stateDumpRequestKey = "request"
+	// stateDumpTimeKey has the time at which the state was dumped
+	stateDumpTimeKey = "dumpTime"
+	// stateDumpNdbClusterKey has the NdbCluster resource
+	stateDumpNdbClusterKey = "ndbcluster.json"
+	// stateDumpConfigKey has the data of the config map
+	// holding the config generated for the MySQL Cluster
+	stateDumpConfigKey = "config.json"
+	// stateDumpMgmStatusKey has the MySQL Cluster
+	// status reported by the Management Server
+	stateDumpMgmStatusKey = "mgmStatus.json"
+	// stateDumpSyncHistoryKey has the recent sync results
+	stateDumpSyncHistoryKey = "syncHistory.json"
+)
+
+// syncHistoryEntry is the result of a sync of an NdbCluster
+type syncHistoryEntry struct {
+	Time       time.Time `json:"time"`
+	Generation int64     `json:"generation"`
+	// Result is one of Continue, Finished or Error
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+	// InSync is true if the sync found the MySQL Cluster in sync with the spec
+	InSync bool `json:"inSync"`
+}
+
+// syncHistoryStore stores the recent sync results of the NdbClusters,
+// to be included in the state dumps.
+type syncHistoryStore struct {
+	// history holds the sync results keyed by the NdbCluster key
+	history map[string][]syncHistoryEntry
+	// mutex protects the history map
+	mutex sync.Mutex
+}
+
+// newSyncHistoryStore creates a new syncHistoryStore
+func newSyncHistoryStore() *syncHistoryStore {
+	return &syncHistoryStore{
+		history: make(map[string][]syncHistoryEntry),
+	}
+}
+
+// record stores the result of a sync of the NdbCluster with the given key,
+// retaining only the last maxSyncHistoryLength results.
+func (shs *syncHistoryStore) record(key string, generation int64, result syncResult, inSync bool) {
+	entry := syncHistoryEntry{
+		Time:       time.Now().UTC(),
+		Generation: generation,
+		Result:     "Continue",
+		InSync:     inSync,
+	}
+	if err := result.getError(); err != nil {
+		entry.Result = "Error"
+		entry.Error = err.Error()
+	} else if result.stopSync() {
+		entry.Result = "Finished"
+	}
+
+	shs.mutex.Lock()
+	defer shs.mutex.Unlock()
+
+	history := append(shs.history[key], entry)
+	if len(history) > maxSyncHistoryLength {
+		history = history[len(history)-maxSyncHistoryLength:]
+	}
+	shs.history[key] = history
+}
+
+// get returns a copy of the recent sync results of the NdbCluster with the given key
+func (shs *syncHistoryStore) get(key string) []syncHistoryEntry {
+	shs.mutex.Lock()
+	defer shs.mutex.Unlock()
+
+	return append([]syncHistoryEntry(nil), shs.history[key]...)
+}
+
+// forget removes the sync results of the NdbCluster with the given key
+func (shs *syncHistoryStore) forget(key string) {
+	shs.mutex.Lock()
+	defer shs.mutex.Unlock()
+
+	delete(shs.history, key)
+}
+
+// marshalStateDumpValue returns the given value as an indented JSON
+// string. Any error is recorded in the dump in place of the value.
+func marshalStateDumpValue(value interface{}) string {
+	valueBytes, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return string(valueBytes)
+}
+
+// newStateDumpConfigMap returns a ConfigMap with the given view
+// of the MySQL Cluster, dumped for the given dump request.
+func newStateDumpConfigMap(nc *v1.NdbCluster, request string, configMap *corev1.ConfigMap,
+	clusterStatus mgmapi.ClusterStatus, mgmErr error, syncHistory []syncHistoryEntry) *corev1.ConfigMap {
+
+	// Drop the managed fields as they only bloat the dump
+	ncCopy := nc.DeepCopy()
+	ncCopy.ManagedFields = nil
+
+	data := map[string]string{
+		stateDumpRequestKey:     request,
+		stateDumpTimeKey:        time.Now().UTC().Format(time.RFC3339),
+		stateDumpNdbClusterKey:  marshalStateDumpValue(ncCopy),
+		stateDumpSyncHistoryKey: marshalStateDumpValue(syncHistory),
+	}
+
+	if configMap != nil {
+		data[stateDumpConfigKey] = marshalStateDumpValue(configMap.Data)
+	} else {
+		data[stateDumpConfigKey] = "error: config map does not exist"
+	}
+
+	if mgmErr != nil {
+		data[stateDumpMgmStatusKey] = "error: " + mgmErr.Error()
+	} else {
+		data[stateDumpMgmStatusKey] = marshalStateDumpValue(clusterStatus)
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nc.GetStateDumpConfigMapName(),
+			Namespace: nc.Namespace,
+			// The cluster label is not set, so that
+			// updating the dump doesn't trigger a sync
+			Labels: map[string]string{
+				constants.ClusterResourceTypeLabel: "ndb-state-dump",
+			},
+			OwnerReferences: nc.GetOwnerReferences(),
+		},
+		Data: data,
+	}
+}
+
+// dumpStateIfRequested dumps the operator's view of the MySQL Cluster
+// into the state dump ConfigMap if a new dump has been requested via
+// the annotations of the NdbCluster. The dump is done even if the
+// reconciliation of the NdbCluster has been stopped.
+func (c *Controller) dumpStateIfRequested(ctx context.Context, nc *v1.NdbCluster) error {
+	request := nc.Annotations[v1.DumpStateAnnotation]
+	if request == "" {
+		// No dump has been requested
+		return nil
+	}
+
+	logger := klog.FromContext(ctx)
+	configMapInterface := c.kubernetesClient.CoreV1().ConfigMaps(nc.Namespace)
+	existingDump, err := configMapInterface.Get(ctx, nc.GetStateDumpConfigMapName(), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "Failed to retrieve the state dump ConfigMap")
+		return err
+	}
+	if err == nil && existingDump.Data[stateDumpRequestKey] == request {
+		// The request has already been served
+		return nil
+	}
+
+	configMap, err := c.configMapLister.ConfigMaps(nc.Namespace).Get(nc.GetConfigMapName())
+	if err != nil {
+		// The config map may not exist yet - the dump will record the same
+		configMap = nil
+	}
+
+	// Retrieve the status of the MySQL Cluster from the Management Server.
+	// The Management Server might be unavailable, and that is recorded in the dump.
+	var clusterStatus mgmapi.ClusterStatus
+	mgmClient, mgmErr := mgmapi.NewMgmClientWithContext(ctx, nc.GetConnectstring())
+	if mgmErr == nil {
+		clusterStatus, mgmErr = mgmClient.GetStatus()
+		mgmClient.Disconnect()
+	}
+
+	stateDump := newStateDumpConfigMap(
+		nc, request, configMap, clusterStatus, mgmErr, c.syncHistory.get(getNdbClusterKey(nc)))
+	// Apply the dump, creating the ConfigMap if it doesn't exist yet
+	patchBytes, err := newApplyPatch(stateDump, corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+	if err != nil {
+		return err
+	}
+	_, err = configMapInterface.Patch(
+		ctx, stateDump.Name, types.ApplyPatchType, patchBytes, applyPatchOptions())
+	if err != nil {
+		logger.Error(err, "Failed to write the state dump ConfigMap")
+		return err
+	}
+
+	logger.Info("Dumped the state of the NdbCluster", "configMap", stateDump.Name)
+	c.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonStateDumped, ActionNone,
+		"State of the MySQL Cluster dumped into the ConfigMap %q", stateDump.Name)
+	return nil
+}
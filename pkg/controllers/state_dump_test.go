@@ -0,0 +1,85 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_syncHistoryStore(t *testing.T) {
+	shs := newSyncHistoryStore()
+	key := "default/test"
+
+	shs.record(key, 1, errorWhileProcessing(errors.New("sync failed")), false)
+	for i := 0; i < maxSyncHistoryLength; i++ {
+		shs.record(key, 2, finishProcessing(), true)
+	}
+
+	// Only the latest results should be retained
+	history := shs.get(key)
+	if len(history) != maxSyncHistoryLength {
+		t.Fatalf("Expected %d sync results but got %d", maxSyncHistoryLength, len(history))
+	}
+	for _, entry := range history {
+		if entry.Result != "Finished" || entry.Generation != 2 || !entry.InSync {
+			t.Errorf("Unexpected sync result %+v", entry)
+		}
+	}
+
+	shs.record(key, 3, errorWhileProcessing(errors.New("sync failed")), false)
+	if entry := shs.get(key)[maxSyncHistoryLength-1]; entry.Result != "Error" || entry.Error != "sync failed" {
+		t.Errorf("Unexpected sync result %+v", entry)
+	}
+
+	shs.forget(key)
+	if len(shs.get(key)) != 0 {
+		t.Error("Sync results were not forgotten")
+	}
+}
+
+func Test_newStateDumpConfigMap(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "test", 2)
+	nc.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubectl"}}
+	configMap := &corev1.ConfigMap{Data: map[string]string{constants.ConfigIniKey: "[ndbd default]"}}
+	clusterStatus := mgmapi.ClusterStatus{1: &mgmapi.NodeStatus{NodeId: 1, IsConnected: true}}
+
+	cm := newStateDumpConfigMap(nc, "req-1", configMap, clusterStatus, nil, nil)
+	if cm.Name != nc.GetStateDumpConfigMapName() || cm.Namespace != nc.Namespace {
+		t.Errorf("Unexpected state dump ConfigMap %s/%s", cm.Namespace, cm.Name)
+	}
+	if _, exists := cm.Labels[constants.ClusterLabel]; exists {
+		t.Error("State dump ConfigMap should not have the cluster label")
+	}
+	if cm.Data[stateDumpRequestKey] != "req-1" {
+		t.Errorf("Expected the request to be %q but got %q", "req-1", cm.Data[stateDumpRequestKey])
+	}
+	if strings.Contains(cm.Data[stateDumpNdbClusterKey], "kubectl") {
+		t.Error("Managed fields of the NdbCluster were not dropped from the dump")
+	}
+	if !strings.Contains(cm.Data[stateDumpConfigKey], "[ndbd default]") {
+		t.Errorf("Config was not dumped : %s", cm.Data[stateDumpConfigKey])
+	}
+	if !strings.Contains(cm.Data[stateDumpMgmStatusKey], `"IsConnected": true`) {
+		t.Errorf("Management Server status was not dumped : %s", cm.Data[stateDumpMgmStatusKey])
+	}
+
+	// The errors should be recorded in the dump
+	cm = newStateDumpConfigMap(nc, "req-2", nil, nil, errors.New("connection refused"), nil)
+	if !strings.HasPrefix(cm.Data[stateDumpConfigKey], "error:") {
+		t.Errorf("Missing config map was not recorded : %s", cm.Data[stateDumpConfigKey])
+	}
+	if cm.Data[stateDumpMgmStatusKey] != "error: connection refused" {
+		t.Errorf("Management Server error was not recorded : %s", cm.Data[stateDumpMgmStatusKey])
+	}
+}
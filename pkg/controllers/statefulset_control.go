@@ -6,16 +6,15 @@ package controllers
 
 import (
 	"context"
-	"encoding/json"
 
 	"github.com/mysql/ndb-operator/pkg/constants"
 	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/client-go/kubernetes"
 	typedappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
 	appslisters "k8s.io/client-go/listers/apps/v1"
@@ -40,6 +39,17 @@ func newMgmdStatefulSetController(
 	}
 }
 
+// newArbitratorStatefulSetController creates a new ndbNodeStatefulSetImpl for the external arbitrator
+func newArbitratorStatefulSetController(
+	client kubernetes.Interface,
+	statefulSetLister appslisters.StatefulSetLister) *ndbNodeStatefulSetImpl {
+	return &ndbNodeStatefulSetImpl{
+		client:             client,
+		statefulSetLister:  statefulSetLister,
+		ndbNodeStatefulset: statefulset.NewArbitratorStatefulSet(),
+	}
+}
+
 // statefulSetInterface returns a typed/apps/v1.StatefulSetInterface
 func (ndbSfset *ndbNodeStatefulSetImpl) statefulSetInterface(namespace string) typedappsv1.StatefulSetInterface {
 	return ndbSfset.client.AppsV1().StatefulSets(namespace)
@@ -52,7 +62,7 @@ func (ndbSfset *ndbNodeStatefulSetImpl) GetTypeName() string {
 }
 
 // GetStatefulSet retrieves the StatefulSet owned by the given NdbCluster resource
-func (ndbSfset *ndbNodeStatefulSetImpl) GetStatefulSet(sc *SyncContext) (*appsv1.StatefulSet, error) {
+func (ndbSfset *ndbNodeStatefulSetImpl) GetStatefulSet(ctx context.Context, sc *SyncContext) (*appsv1.StatefulSet, error) {
 	// Get the StatefulSet from cache using statefulSetLister
 	nc := sc.ndb
 	sfsetName := ndbSfset.ndbNodeStatefulset.GetName(nc)
@@ -70,7 +80,7 @@ func (ndbSfset *ndbNodeStatefulSetImpl) GetStatefulSet(sc *SyncContext) (*appsv1
 	}
 
 	// StatefulSet exists. Verify ownership
-	if err = sc.isOwnedByNdbCluster(sfset); err != nil {
+	if err = sc.ensureOwnedByNdbCluster(ctx, sfset); err != nil {
 		// StatefulSet is not owned by the current NdbCluster resource
 		return nil, err
 	}
@@ -96,9 +106,7 @@ func (ndbSfset *ndbNodeStatefulSetImpl) createStatefulSet(
 	}
 	klog.Infof("Creating StatefulSet %q of type %q with Replica = %d",
 		getNamespacedName2(nc.Namespace, sfsetName), ndbSfset.ndbNodeStatefulset.GetTypeName(), *sfset.Spec.Replicas)
-	sfsetInterface := ndbSfset.statefulSetInterface(nc.Namespace)
-	sfset, err = sfsetInterface.Create(ctx, sfset, metav1.CreateOptions{})
-
+	sfset, err = ndbSfset.statefulSetInterface(nc.Namespace).Create(ctx, sfset, createOptions())
 	if err != nil {
 		if errors.IsAlreadyExists(err) {
 			// The StatefulSet was created already but the cache
@@ -114,10 +122,33 @@ func (ndbSfset *ndbNodeStatefulSetImpl) createStatefulSet(
 		return nil, err
 	}
 
-	// New StatefulSet was successfully created
+	sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonStatefulSetCreated, ActionCreated,
+		"StatefulSet %q was created for the %q nodes with %d replicas",
+		getNamespacedName2(nc.Namespace, sfsetName), ndbSfset.GetTypeName(), *sfset.Spec.Replicas)
 	return sfset, nil
 }
 
+// applyStatefulSet creates or updates the given StatefulSet using server side apply
+func (ndbSfset *ndbNodeStatefulSetImpl) applyStatefulSet(
+	ctx context.Context, namespace string, sfset *appsv1.StatefulSet) (*appsv1.StatefulSet, error) {
+
+	patch, err := newApplyPatch(sfset, appsv1.SchemeGroupVersion.WithKind("StatefulSet"))
+	if err != nil {
+		klog.Errorf("Failed to generate the apply patch for StatefulSet %q : %s",
+			getNamespacedName2(namespace, sfset.Name), err)
+		return nil, err
+	}
+
+	appliedSfset, err := ndbSfset.statefulSetInterface(namespace).Patch(
+		ctx, sfset.Name, types.ApplyPatchType, patch, applyPatchOptions())
+	if err != nil {
+		klog.Errorf("Failed to apply the StatefulSet %q : %s", getNamespacedName2(namespace, sfset.Name), err)
+		return nil, err
+	}
+
+	return appliedSfset, nil
+}
+
 // deleteStatefulSet deletes the given statefulSet
 func (ndbSfset *ndbNodeStatefulSetImpl) deleteStatefulSet(ctx context.Context, sfset *appsv1.StatefulSet, sc *SyncContext) error {
 	err := ndbSfset.statefulSetInterface(sfset.Namespace).Delete(ctx, sfset.Name, metav1.DeleteOptions{})
@@ -132,14 +163,15 @@ func (ndbSfset *ndbNodeStatefulSetImpl) deleteStatefulSet(ctx context.Context, s
 	klog.Errorf("Deleted the StatefulSet %q", getNamespacedName(sfset))
 
 	// Delete the governing Service
-	return sc.serviceController.deleteService(ctx, sfset.Namespace, sc.ndb.GetServiceName(ndbSfset.GetTypeName()))
+	return sc.serviceController.deleteService(
+		ctx, sfset.Namespace, ndbSfset.ndbNodeStatefulset.GetServiceName(sc.ndb))
 }
 
 // EnsureStatefulSet creates a StatefulSet for the MySQL Cluster nodes if one doesn't exist already.
 func (ndbSfset *ndbNodeStatefulSetImpl) EnsureStatefulSet(
 	ctx context.Context, sc *SyncContext) (sfset *appsv1.StatefulSet, existed bool, err error) {
 
-	if sfset, err = ndbSfset.GetStatefulSet(sc); err != nil {
+	if sfset, err = ndbSfset.GetStatefulSet(ctx, sc); err != nil {
 		// Error retrieving sfset
 		return nil, false, err
 	} else if sfset != nil {
@@ -156,33 +188,9 @@ func (ndbSfset *ndbNodeStatefulSetImpl) EnsureStatefulSet(
 func (ndbSfset *ndbNodeStatefulSetImpl) patchStatefulSet(ctx context.Context,
 	existingStatefulSet *appsv1.StatefulSet, updatedStatefulSet *appsv1.StatefulSet) syncResult {
 
-	// JSON encode both StatefulSets
-	existingJSON, err := json.Marshal(existingStatefulSet)
-	if err != nil {
-		klog.Errorf("Failed to encode existing StatefulSet: %v", err)
-		return errorWhileProcessing(err)
-	}
-	updatedJSON, err := json.Marshal(updatedStatefulSet)
-	if err != nil {
-		klog.Errorf("Failed to encode updated StatefulSet: %v", err)
-		return errorWhileProcessing(err)
-	}
-
-	// Generate the patch to be applied
-	patch, err := strategicpatch.CreateTwoWayMergePatch(existingJSON, updatedJSON, appsv1.StatefulSet{})
+	// Apply the updated StatefulSet
+	updatedStatefulSet, err := ndbSfset.applyStatefulSet(ctx, existingStatefulSet.Namespace, updatedStatefulSet)
 	if err != nil {
-		klog.Errorf("Failed to generate the patch to be applied: %v", err)
-		return errorWhileProcessing(err)
-	}
-
-	// klog.Infof("Patching StatefulSets.\nExisting : %v\n. Modified : %v\nPatch : %v", string(existingJSON), string(updatedJSON), string(patch))
-
-	// Patch the StatefulSet
-	sfsetInterface := ndbSfset.statefulSetInterface(existingStatefulSet.Namespace)
-	updatedStatefulSet, err = sfsetInterface.Patch(
-		ctx, existingStatefulSet.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
-	if err != nil {
-		klog.Errorf("Failed to apply the patch to the StatefulSet %q : %s", getNamespacedName(existingStatefulSet), err)
 		return errorWhileProcessing(err)
 	}
 
@@ -214,8 +222,15 @@ func (ndbSfset *ndbNodeStatefulSetImpl) ReconcileStatefulSet(
 	ctx context.Context, sfset *appsv1.StatefulSet, sc *SyncContext) syncResult {
 
 	cs := sc.configSummary
-	if workloadHasConfigGeneration(sfset, cs.NdbClusterGeneration) {
-		// StatefulSet upto date
+	if workloadHasConfigGeneration(sfset, cs.NdbClusterGeneration) &&
+		workloadHasMySQLClusterConfigVersion(sfset, cs.GetMySQLClusterConfigVersion(ndbSfset.GetTypeName())) &&
+		workloadHasRestartRequests(sfset, cs, ndbSfset.GetTypeName()) {
+		// StatefulSet upto date. Note that the config.ini and the
+		// restart requests can be updated without a change in the
+		// spec, when the nodes are moved to different location
+		// domains or a restart is requested via the annotations.
+		// The Management nodes are not restarted for the config
+		// changes that can be applied by reloading the config.
 		return continueProcessing()
 	}
 
@@ -232,6 +247,11 @@ func (ndbSfset *ndbNodeStatefulSetImpl) ReconcileStatefulSet(
 		return errorWhileProcessing(err)
 	}
 
+	// The VolumeClaimTemplates cannot be updated. Retain the existing
+	// ones, as any increase in the storage requested for the data nodes
+	// is applied by expanding their PVCs directly.
+	updatedStatefulSet.Spec.VolumeClaimTemplates = sfset.Spec.VolumeClaimTemplates
+
 	if ndbSfset.GetTypeName() == constants.NdbNodeTypeNdbmtd &&
 		*(sfset.Spec.Replicas) < *(updatedStatefulSet.Spec.Replicas) {
 		// New data nodes are being added to MySQL Cluster
@@ -241,5 +261,11 @@ func (ndbSfset *ndbNodeStatefulSetImpl) ReconcileStatefulSet(
 		updatedStatefulSet.Annotations[AddNodeOnlineInProgress] = "true"
 	}
 
-	return ndbSfset.patchStatefulSet(ctx, sfset, updatedStatefulSet)
+	sr := ndbSfset.patchStatefulSet(ctx, sfset, updatedStatefulSet)
+	if ndbSfset.GetTypeName() == constants.NdbNodeTypeMgmd && sr.stopSync() && sr.getError() == nil {
+		// The StatefulSet controller will now do a rolling restart of the Management nodes
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonMgmdRestarting, ActionRestart,
+			"Management nodes are being restarted to apply generation %d of the spec", cs.NdbClusterGeneration)
+	}
+	return sr
 }
@@ -7,15 +7,18 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	listerscorev1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/events"
-	"k8s.io/client-go/util/retry"
 	klog "k8s.io/klog/v2"
 
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
@@ -23,6 +26,7 @@ import (
 	ndblisters "github.com/mysql/ndb-operator/pkg/generated/listers/ndbcontroller/v1"
 	"github.com/mysql/ndb-operator/pkg/mgmapi"
 	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+	"github.com/mysql/ndb-operator/pkg/resources"
 )
 
 // SyncContext stores all information collected in/for a single run of syncHandler
@@ -34,28 +38,113 @@ type SyncContext struct {
 	mgmdNodeSfset *appsv1.StatefulSet
 	dataNodeSfSet *appsv1.StatefulSet
 	mysqldSfset   *appsv1.StatefulSet
+	// arbitratorSfset is the StatefulSet of the external
+	// arbitrator. It is nil if no arbitrator is required.
+	arbitratorSfset *appsv1.StatefulSet
+	// mysqldServerGroupSfsets are the StatefulSets
+	// of the MySQL Server groups, mapped by their name
+	mysqldServerGroupSfsets map[string]*appsv1.StatefulSet
 
 	ndb *v1.NdbCluster
 
 	// controller handling creation and changes of resources
-	mgmdController      *ndbNodeStatefulSetImpl
-	ndbmtdController    *ndbmtdStatefulSetController
-	mysqldController    *mysqldStatefulSetController
-	configMapController ConfigMapControlInterface
-	serviceController   ServiceControlInterface
-	pdbController       PodDisruptionBudgetControlInterface
+	mgmdController              *ndbNodeStatefulSetImpl
+	arbitratorController        *ndbNodeStatefulSetImpl
+	ndbmtdController            *ndbmtdStatefulSetController
+	mysqldController            *mysqldStatefulSetController
+	mysqldServerGroupController *mysqldServerGroupController
+	configMapController         ConfigMapControlInterface
+	serviceController           ServiceControlInterface
+	pdbController               PodDisruptionBudgetControlInterface
+	hpaController               HorizontalPodAutoscalerControlInterface
+	networkPolicyController     NetworkPolicyControlInterface
+	serviceAccountController    ServiceAccountControlInterface
+	gatewayController           GatewayControlInterface
+	clusterLogStreamer          *clusterLogStreamer
+	nodeEventListener           *nodeEventListener
+	epochSampler                *epochSampler
+
+	// dataNodeFailures tracks the container restarts of the data
+	// nodes failing to become ready, shared by all the syncs
+	dataNodeFailures *dataNodeFailureTracker
+
+	// missingWorkerNodes tracks the worker nodes hosting the local
+	// volumes of the data nodes found missing, shared by all the syncs
+	missingWorkerNodes *missingWorkerNodeTracker
 
 	kubernetesClient kubernetes.Interface
 	ndbClient        ndbclientset.Interface
+	dynamicClient    dynamic.Interface
 	ndbsLister       ndblisters.NdbClusterLister
 	podLister        listerscorev1.PodLister
 	serviceLister    listerscorev1.ServiceLister
+	configMapLister  listerscorev1.ConfigMapLister
+	pvcLister        listerscorev1.PersistentVolumeClaimLister
+	pvLister         listerscorev1.PersistentVolumeLister
+	nodeLister       listerscorev1.NodeLister
 
 	// bool flag to control the NdbCluster status processedGeneration value
 	syncSuccess bool
 
+	// workloadErrors are the errors detected in the workloads during
+	// the sync, which have to be reported via the NdbCluster status.
+	workloadErrors []string
+
+	// requeueAfter, when set, is the delay after which the NdbCluster
+	// has to be synced again to check the progress of a sync step that
+	// will not be notified by any event from the K8s resources.
+	requeueAfter time.Duration
+
+	// connectedMySQLServers is the number of MySQL Servers that could
+	// reach the NDB engine during the sync. It is nil if it was not checked.
+	connectedMySQLServers *int32
+
+	// partitionedCondition is the NdbClusterPartitioned condition
+	// computed during the sync. It is nil if it could not be computed.
+	partitionedCondition *v1.NdbClusterCondition
+
+	// dataNodeRestartLoopCondition is the NdbClusterDataNodeRestartLoop
+	// condition computed during the sync. It is nil if it was not changed.
+	dataNodeRestartLoopCondition *v1.NdbClusterCondition
+
+	// nodesConnectedCondition is the NdbClusterNodesConnected condition
+	// computed during the sync. It is nil if it was not checked.
+	nodesConnectedCondition *v1.NdbClusterCondition
+
+	// healthSnapshot and healthyCondition are the health snapshot and the
+	// NdbClusterHealthy condition sampled during the sync. They are nil if
+	// the health was not sampled.
+	healthSnapshot   *v1.NdbClusterHealthSnapshot
+	healthyCondition *v1.NdbClusterCondition
+
+	// zoneRedundantCondition is the NdbClusterZoneRedundant condition
+	// computed during the sync. It is nil if it could not be computed.
+	zoneRedundantCondition *v1.NdbClusterCondition
+
+	// storageResizedCondition is the NdbClusterStorageResized condition
+	// computed during the sync. It is nil if it could not be computed.
+	storageResizedCondition *v1.NdbClusterCondition
+
+	// localVolumesAvailableCondition is the NdbClusterLocalVolumesAvailable
+	// condition computed during the sync. It is nil if it could not be computed.
+	localVolumesAvailableCondition *v1.NdbClusterCondition
+
+	// disallowedDataNodeRestart is the type of the restart required by the
+	// data nodes to apply the new spec, when the spec does not allow the
+	// operator to perform it. It is empty if there is no such restart.
+	disallowedDataNodeRestart v1.DataNodeRestartType
+
+	// mgmdConfigReloaded is set to true if the Management nodes
+	// were verified to be running with the latest config.ini
+	// after it was reloaded without restarting them.
+	mgmdConfigReloaded bool
+
 	// recorder is an event recorder for recording Event resources to the Kubernetes API.
 	recorder events.EventRecorder
+
+	// logger is used to log the steps of the sync. All
+	// its entries are tagged with the NdbCluster key.
+	logger klog.Logger
 }
 
 const (
@@ -67,6 +156,11 @@ const (
 	Complete = "Complete"
 )
 
+// arbitrationCheckInterval is the interval at which the operator checks
+// if a delayed Management node restart can proceed without risking the
+// arbitration between the Data nodes.
+const arbitrationCheckInterval = 15 * time.Second
+
 func (sc *SyncContext) kubeClientset() kubernetes.Interface {
 	return sc.kubernetesClient
 }
@@ -75,20 +169,6 @@ func (sc *SyncContext) ndbClientset() ndbclientset.Interface {
 	return sc.ndbClient
 }
 
-// isOwnedByNdbCluster returns an error if the given
-// object is not owned by the NdbCluster resource being synced.
-func (sc *SyncContext) isOwnedByNdbCluster(object metav1.Object) error {
-	if !metav1.IsControlledBy(object, sc.ndb) {
-		// The object is not owned by the NdbCluster resource being synced.
-		// Record a warning and return an error.
-		err := fmt.Errorf(MessageResourceExists, getNamespacedName(object))
-		sc.recorder.Eventf(sc.ndb, nil,
-			corev1.EventTypeWarning, ReasonResourceExists, ActionNone, err.Error())
-		return err
-	}
-	return nil
-}
-
 // ensureManagementServerStatefulSet creates the StatefulSet for
 // Management Server in the K8s Server if they don't exist yet.
 func (sc *SyncContext) ensureManagementServerStatefulSet(
@@ -96,6 +176,26 @@ func (sc *SyncContext) ensureManagementServerStatefulSet(
 	return sc.mgmdController.EnsureStatefulSet(ctx, sc)
 }
 
+// ensureArbitratorStatefulSet creates the StatefulSet for the external
+// arbitrator in the K8s Server if it is declared in the config and
+// doesn't exist yet. The data nodes do not require the arbitrator
+// to start, so the sync is not stopped while the arbitrator starts.
+func (sc *SyncContext) ensureArbitratorStatefulSet(ctx context.Context) (err error) {
+	if !sc.configSummary.HasArbitrator {
+		// No arbitrator is required
+		return nil
+	}
+
+	var existed bool
+	if sc.arbitratorSfset, existed, err = sc.arbitratorController.EnsureStatefulSet(ctx, sc); err != nil {
+		return err
+	}
+	if !existed {
+		sc.logger.Info("Created resource", "resource", "StatefulSet for the Arbitrator")
+	}
+	return nil
+}
+
 // ensureDataNodeStatefulSet creates the StatefulSet for
 // Data Nodes in the K8s Server if they don't exist yet.
 func (sc *SyncContext) ensureDataNodeStatefulSet(
@@ -105,20 +205,64 @@ func (sc *SyncContext) ensureDataNodeStatefulSet(
 
 // validateMySQLServerStatefulSet retrieves the MySQL Server statefulset from K8s.
 // If the statefulset exists, it verifies if it is owned by the NdbCluster resource.
-func (sc *SyncContext) validateMySQLServerStatefulSet() (*appsv1.StatefulSet, error) {
-	return sc.mysqldController.GetStatefulSet(sc)
+func (sc *SyncContext) validateMySQLServerStatefulSet(ctx context.Context) (*appsv1.StatefulSet, error) {
+	return sc.mysqldController.GetStatefulSet(ctx, sc)
+}
+
+// reconcileHorizontalPodAutoscaler reconciles the HorizontalPodAutoscaler of the MySQL Servers
+func (sc *SyncContext) reconcileHorizontalPodAutoscaler(ctx context.Context) syncResult {
+	if sc.hpaController == nil {
+		if sc.ndb.MySQLServerAutoscalingEnabled() {
+			sc.logger.Info("Ignoring spec.mysqlNode.autoscaling as the K8s Server doesn't support autoscaling/v2")
+		}
+		return continueProcessing()
+	}
+
+	return sc.hpaController.ReconcileHorizontalPodAutoscaler(ctx, sc)
 }
 
-// ensurePodDisruptionBudgets creates PodDisruptionBudgets for data nodes
+// reconcileGateway reconciles the Gateway API resources exposing the MySQL Servers
+func (sc *SyncContext) reconcileGateway(ctx context.Context) syncResult {
+	if sc.gatewayController == nil {
+		nc := sc.ndb
+		if nc.GatewayEnabled() {
+			sc.logger.Info("Ignoring spec.mysqlNode.gateway as the K8s Server doesn't support the Gateway API")
+			if nc.Status.ProcessedGeneration != nc.Generation {
+				// Warn once for every generation of the spec
+				sc.recorder.Eventf(nc, nil, corev1.EventTypeWarning, ReasonGatewayAPIUnavailable, ActionNone,
+					"The MySQL Servers are not exposed via spec.mysqlNode.gateway "+
+						"as the K8s Server doesn't support the Gateway API")
+			}
+		}
+		return continueProcessing()
+	}
+
+	return sc.gatewayController.ReconcileGateway(ctx, sc)
+}
+
+// ensurePodDisruptionBudgets creates PodDisruptionBudgets for
+// the Management nodes, Data nodes and the MySQL Servers
 func (sc *SyncContext) ensurePodDisruptionBudget(ctx context.Context) (existed bool, err error) {
-	// ensure ndbmtd PDB
 	if sc.pdbController == nil {
 		// v1 policy is not supported
 		// return true to suppress operator's "created" log
 		return true, nil
 	}
-	return sc.pdbController.EnsurePodDisruptionBudget(
-		ctx, sc, sc.ndbmtdController.GetTypeName())
+
+	existed = true
+	for _, nodeType := range []string{
+		sc.mgmdController.GetTypeName(),
+		sc.ndbmtdController.GetTypeName(),
+		sc.mysqldController.GetTypeName(),
+	} {
+		var pdbExisted bool
+		if pdbExisted, err = sc.pdbController.EnsurePodDisruptionBudget(ctx, sc, nodeType); err != nil {
+			return false, err
+		}
+		existed = existed && pdbExisted
+	}
+
+	return existed, nil
 }
 
 // reconcileManagementNodeStatefulSet patches the Management Node
@@ -127,6 +271,15 @@ func (sc *SyncContext) reconcileManagementNodeStatefulSet(ctx context.Context) s
 	return sc.mgmdController.ReconcileStatefulSet(ctx, sc.mgmdNodeSfset, sc)
 }
 
+// reconcileArbitratorStatefulSet patches the arbitrator StatefulSet,
+// if one exists, with the spec from the latest generation of NdbCluster.
+func (sc *SyncContext) reconcileArbitratorStatefulSet(ctx context.Context) syncResult {
+	if sc.arbitratorSfset == nil {
+		return continueProcessing()
+	}
+	return sc.arbitratorController.ReconcileStatefulSet(ctx, sc.arbitratorSfset, sc)
+}
+
 // reconcileDataNodeStatefulSet patches the Data Node StatefulSet
 // with the spec from the latest generation of NdbCluster.
 func (sc *SyncContext) reconcileDataNodeStatefulSet(ctx context.Context) syncResult {
@@ -143,33 +296,148 @@ func (sc *SyncContext) ensurePodVersion(
 	// Retrieve the pod and extract its version
 	pod, err := sc.podLister.Pods(namespace).Get(podName)
 	if err != nil {
-		klog.Errorf("Failed to find pod '%s/%s' running %s : %s", namespace, podName, podDescription, err)
+		sc.logger.Error(err, "Failed to find pod", "pod", getNamespacedName2(namespace, podName), "node", podDescription)
 		return false, err
 	}
 
 	podVersion := pod.GetLabels()["controller-revision-hash"]
-	klog.Infof("Version of %s's pod : %s", podDescription, podVersion)
+	sc.logger.Info("Retrieved the pod version", "node", podDescription, "podVersion", podVersion)
 
 	if podVersion == desiredPodVersion {
-		klog.Infof("%s has the desired version of podSpec", podDescription)
+		sc.logger.Info("Pod has the desired version of podSpec", "node", podDescription)
 		return false, nil
 	}
 
-	klog.Infof("%s does not have desired version of podSpec", podDescription)
+	sc.logger.Info("Pod does not have the desired version of podSpec", "node", podDescription)
 
 	// The Pod does not have the desired version.
 	// Delete it and let the statefulset controller restart it with the latest pod definition.
 	err = sc.kubeClientset().CoreV1().Pods(namespace).Delete(ctx, podName, metav1.DeleteOptions{})
 	if err != nil {
-		klog.Errorf("Failed to delete pod '%s/%s' running %s : %s", namespace, podName, podDescription, err)
+		sc.logger.Error(err, "Failed to delete pod", "pod", getNamespacedName2(namespace, podName), "node", podDescription)
 		return false, err
 	}
 
 	// The pod has been deleted.
-	klog.Infof("Pod running %s is being restarted with the desired configuration", podDescription)
+	sc.logger.Info("Pod is being restarted with the desired configuration", "node", podDescription)
 	return true, nil
 }
 
+// deleteDataNodePod deletes the pod running the data node with the given
+// nodeId, allowing the StatefulSet controller to restart it with the latest
+// pod definition along with the latest config.
+func (sc *SyncContext) deleteDataNodePod(ctx context.Context, nodeId int) error {
+	namespace := sc.dataNodeSfSet.Namespace
+	podName := sc.configSummary.GetNodePodName(nodeId)
+	err := sc.kubeClientset().CoreV1().Pods(namespace).Delete(ctx, podName, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		sc.logger.Error(err, "Failed to delete pod", "pod", getNamespacedName2(namespace, podName),
+			"node", fmt.Sprintf("Data Node(nodeId=%d)", nodeId))
+		return err
+	}
+	return nil
+}
+
+// ensureOnDeletePodVersion restarts the outdated pods of the given
+// Management node or MySQL Server StatefulSet, if it uses the OnDelete
+// update strategy. The pods are restarted one at a time, in the reverse
+// ordinal order, just like a RollingUpdate done by the StatefulSet
+// controller. Reconciliation continues only after all the pods are updated.
+func (sc *SyncContext) ensureOnDeletePodVersion(
+	ctx context.Context, sfset *appsv1.StatefulSet, nodeDescription string) syncResult {
+	if sfset == nil ||
+		sfset.Spec.UpdateStrategy.Type != appsv1.OnDeleteStatefulSetStrategyType ||
+		statefulsetUpdateComplete(sfset) {
+		// The StatefulSet doesn't exist, is updated by
+		// the StatefulSet controller or is already up-to-date.
+		return continueProcessing()
+	}
+
+	desiredPodRevisionHash := sfset.Status.UpdateRevision
+	for ordinal := *(sfset.Spec.Replicas) - 1; ordinal >= 0; ordinal-- {
+		podName := fmt.Sprintf("%s-%d", sfset.Name, ordinal)
+		podDeleted, err := sc.ensurePodVersion(ctx, sfset.Namespace, podName, desiredPodRevisionHash,
+			fmt.Sprintf("%s(pod=%s)", nodeDescription, podName))
+		if err != nil {
+			return errorWhileProcessing(err)
+		}
+
+		if podDeleted {
+			// Stop processing. Reconciliation will continue
+			// once the restarted pod is ready again.
+			return finishProcessing()
+		}
+	}
+
+	// All pods have the desired pod version
+	return continueProcessing()
+}
+
+// ensureArbitrationAvailable verifies, before the operator restarts an
+// outdated Management node, that all the Management nodes are connected
+// to the MySQL Cluster. With two Management nodes and no external
+// arbitrator, this ensures that the Data nodes can fail over to the
+// other Management node as the arbitrator while one is being restarted.
+func (sc *SyncContext) ensureArbitrationAvailable(ctx context.Context) syncResult {
+	nc := sc.ndb
+	mgmdSfset := sc.mgmdNodeSfset
+	if nc.GetManagementNodeCount() < 2 || nc.HasArbitrator() || mgmdSfset == nil ||
+		mgmdSfset.Spec.UpdateStrategy.Type != appsv1.OnDeleteStatefulSetStrategyType ||
+		statefulsetUpdateComplete(mgmdSfset) {
+		// Either no Management node will be restarted by the
+		// operator or the arbitration doesn't depend on them
+		return continueProcessing()
+	}
+
+	mgmClient, err := mgmapi.NewMgmClientWithContext(ctx, nc.GetConnectstring())
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+	defer mgmClient.Disconnect()
+
+	clusterStatus, err := mgmClient.GetStatus()
+	if err != nil {
+		sc.logger.Error(err, "Error getting cluster status from management server")
+		return errorWhileProcessing(err)
+	}
+
+	for _, nodeStatus := range clusterStatus {
+		if !nodeStatus.IsMgmNode() || nodeStatus.IsConnected {
+			continue
+		}
+
+		sc.requeueAfter = arbitrationCheckInterval
+		if sc.mgmdPodRestartedByRollout(nodeStatus.NodeId) {
+			// The Management node has just been restarted by this
+			// rollout and is yet to reconnect, which is expected.
+			sc.logger.Info("Waiting for the restarted Management node to reconnect before restarting the next one",
+				"nodeId", nodeStatus.NodeId)
+			return finishProcessing()
+		}
+
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeWarning, ReasonArbitrationUnavailable, ActionNone,
+			"Delaying the Management node restart as Management node %d is not connected",
+			nodeStatus.NodeId)
+		return finishProcessing()
+	}
+
+	return continueProcessing()
+}
+
+// mgmdPodRestartedByRollout returns true if the pod of the Management node
+// with the given nodeId has already been restarted by the ongoing rollout,
+// i.e. it is either being recreated or already has the update revision.
+func (sc *SyncContext) mgmdPodRestartedByRollout(nodeId int) bool {
+	mgmdSfset := sc.mgmdNodeSfset
+	pod, err := sc.podLister.Pods(mgmdSfset.Namespace).Get(sc.configSummary.GetNodePodName(nodeId))
+	if err != nil {
+		// The deleted pod is yet to be recreated by the StatefulSet controller
+		return errors.IsNotFound(err)
+	}
+
+	return pod.GetLabels()[appsv1.ControllerRevisionHashLabelKey] == mgmdSfset.Status.UpdateRevision
+}
+
 // ensureDataNodePodVersion checks if all the Data Node pods
 // have the latest podSpec defined by the StatefulSet. If not, it safely
 // restarts them without affecting the availability of MySQL Cluster.
@@ -185,18 +453,22 @@ func (sc *SyncContext) ensurePodVersion(
 // maneuver, ensuring MySQL Cluster's availability.
 func (sc *SyncContext) ensureDataNodePodVersion(ctx context.Context) syncResult {
 	ndbmtdSfset := sc.dataNodeSfSet
-	if statefulsetUpdateComplete(ndbmtdSfset) {
+	desiredPodRevisionHash := ndbmtdSfset.Status.UpdateRevision
+	restarts := getDataNodeRestarts(ndbmtdSfset, sc.configSummary.MySQLClusterConfigVersion, desiredPodRevisionHash)
+	if statefulsetUpdateComplete(ndbmtdSfset) && len(restarts) == 0 {
 		// All data nodes have the desired pod version.
 		// Continue with rest of the sync process.
-		klog.Info("All Data node pods are up-to-date and ready")
+		sc.logger.Info("All Data node pods are up-to-date and ready")
+		if err := sc.resetDataNodeRestartLoop(ctx); err != nil {
+			return errorWhileProcessing(err)
+		}
 		return continueProcessing()
 	}
 
-	desiredPodRevisionHash := ndbmtdSfset.Status.UpdateRevision
-	klog.Infof("Ensuring Data Node pods have the desired podSpec version, %s", desiredPodRevisionHash)
+	sc.logger.Info("Ensuring Data Node pods have the desired podSpec version", "podVersion", desiredPodRevisionHash)
 
 	// Get the node and nodegroup details via clusterStatus
-	mgmClient, err := mgmapi.NewMgmClient(sc.ndb.GetConnectstring())
+	mgmClient, err := mgmapi.NewMgmClientWithContext(ctx, sc.ndb.GetConnectstring())
 	if err != nil {
 		return errorWhileProcessing(err)
 	}
@@ -204,10 +476,24 @@ func (sc *SyncContext) ensureDataNodePodVersion(ctx context.Context) syncResult
 
 	clusterStatus, err := mgmClient.GetStatus()
 	if err != nil {
-		klog.Errorf("Error getting cluster status from management server: %s", err)
+		sc.logger.Error(err, "Error getting cluster status from management server")
 		return errorWhileProcessing(err)
 	}
 
+	// Verify that the data nodes restarted earlier, if any,
+	// have connected to the MySQL Cluster with the desired config
+	if sr := sc.verifyDataNodeRestarts(
+		ctx, mgmClient, clusterStatus, restarts, desiredPodRevisionHash); sr.stopSync() {
+		return sr
+	}
+
+	if restartType := sc.configSummary.DataNodeRestartType; restartType == v1.DataNodeSystemRestart ||
+		restartType == v1.DataNodeInitialSystemRestart {
+		// The latest config can be applied only by a system restart
+		return sc.systemRestartDataNodes(ctx, mgmClient, clusterStatus,
+			restarts, desiredPodRevisionHash, restartType == v1.DataNodeInitialSystemRestart)
+	}
+
 	// Group the nodes based on nodegroup.
 	// The node ids are sorted within the sub arrays and the array
 	// itself is sorted based on the node groups. Every sub array
@@ -218,6 +504,18 @@ func (sc *SyncContext) ensureDataNodePodVersion(ctx context.Context) syncResult
 		return errorWhileProcessing(err)
 	}
 
+	pendingInitialRestart, err := sc.hasPendingInitialRestartRequest()
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	if sc.configSummary.DataNodeRestartType == v1.DataNodeInitialNodeRestart || pendingInitialRestart {
+		// The latest config can be applied only by initial
+		// restarts, or an initial restart has been requested
+		return sc.initialRestartOutdatedDataNode(
+			ctx, clusterStatus, nodesGroupedByNodegroups, restarts, desiredPodRevisionHash)
+	}
+
 	// Pick up the i'th node id from every sub array of
 	// nodesGroupedByNodegroups during every iteration and
 	// ensure that they all have the latest Pod definition.
@@ -230,44 +528,131 @@ func (sc *SyncContext) ensureDataNodePodVersion(ctx context.Context) syncResult
 		}
 
 		// Check the pods running MySQL Cluster nodes with candidateNodeIds
-		// and delete them if they have an older pod definition.
+		// and restart them if they have an older pod definition or if their
+		// previous restart failed to apply the desired config.
 		var nodesBeingUpdated []int
 		for _, nodeId := range candidateNodeIds {
-			// Generate the pod name using nodeId.
-			// Data node with nodeId 'i' runs in a pod with ordinal index 'i-1-numberOfMgmdNodes'
-			ndbmtdPodName := fmt.Sprintf(
-				"%s-%d", ndbmtdSfset.Name, nodeId-1-int(sc.configSummary.NumOfManagementNodes))
-
-			// Check the pod version and delete it if its outdated
-			podDeleted, err := sc.ensurePodVersion(
-				ctx, ndbmtdSfset.Namespace, ndbmtdPodName, desiredPodRevisionHash,
-				fmt.Sprintf("Data Node(nodeId=%d)", nodeId))
+			// Retrieve the name of the pod running the nodeId from the config
+			ndbmtdPodName := sc.configSummary.GetNodePodName(nodeId)
+			pod, err := sc.podLister.Pods(ndbmtdSfset.Namespace).Get(ndbmtdPodName)
 			if err != nil {
+				sc.logger.Error(err, "Failed to find pod", "pod", getNamespacedName2(ndbmtdSfset.Namespace, ndbmtdPodName))
 				return errorWhileProcessing(err)
 			}
 
-			if podDeleted {
-				nodesBeingUpdated = append(nodesBeingUpdated, nodeId)
+			restart := restarts[nodeId]
+			if pod.GetLabels()["controller-revision-hash"] == desiredPodRevisionHash && !restart.failed() {
+				// Data node is already up-to-date
+				continue
 			}
+
+			// Verify that the previous restarts of the data node, if
+			// any, are not looping before restarting it again
+			if sr := sc.checkDataNodeRestartLoop(nodeId, restart); sr.stopSync() {
+				return sr
+			}
+			nodesBeingUpdated = append(nodesBeingUpdated, nodeId)
 		}
 
 		if len(nodesBeingUpdated) > 0 {
+			// Record the restarts and then delete the pods
+			// to let the StatefulSet controller restart them
+			// with the latest pod definition.
+			if err = sc.recordDataNodeRestarts(ctx, restarts, desiredPodRevisionHash, nodesBeingUpdated...); err != nil {
+				return errorWhileProcessing(err)
+			}
+			for _, nodeId := range nodesBeingUpdated {
+				if err = sc.deleteDataNodePod(ctx, nodeId); err != nil {
+					return errorWhileProcessing(err)
+				}
+			}
+
 			// The outdated data nodes are being updated.
 			// Exit here and allow them to be restarted by the statefulset controllers.
 			// Continue syncing once they are up, in a later reconciliation loop.
-			klog.Infof("The data nodes %v, identified with old pod version, are being restarted", nodesBeingUpdated)
+			sc.logger.Info("Data nodes identified with old pod version are being restarted", "nodeIds", nodesBeingUpdated)
+			sc.recorder.Eventf(sc.ndb, nil, corev1.EventTypeNormal, ReasonDataNodeRestarting, ActionRestart,
+				"Data nodes (nodeIds=%v) are being restarted to apply the latest pod definition", nodesBeingUpdated)
 			// Stop processing. Reconciliation will continue
 			// once the StatefulSet is fully ready again.
 			return finishProcessing()
 		}
 
-		klog.Infof("The data nodes %v have the desired pod version %s", candidateNodeIds, desiredPodRevisionHash)
+		sc.logger.Info("Data nodes have the desired pod version", "nodeIds", candidateNodeIds, "podVersion", desiredPodRevisionHash)
 	}
 
 	// Control will never reach here but to make compiler happy return continue.
 	return continueProcessing()
 }
 
+// initialRestartOutdatedDataNode restarts the first data node that has an
+// outdated pod version with an initial restart, to apply a config that
+// requires the data nodes to recreate their file system. An initially
+// restarted data node copies all its data from the other data nodes of its
+// node group. So, unlike the node restarts, only one data node is restarted
+// at a time, going through the node groups one by one, and only when all
+// the other data nodes of its node group are connected to the MySQL Cluster.
+func (sc *SyncContext) initialRestartOutdatedDataNode(ctx context.Context,
+	clusterStatus mgmapi.ClusterStatus, nodesGroupedByNodegroups [][]int,
+	restarts dataNodeRestarts, desiredPodRevisionHash string) syncResult {
+	ndbmtdSfset := sc.dataNodeSfSet
+	for _, nodesInNodegroup := range nodesGroupedByNodegroups {
+		for _, nodeId := range nodesInNodegroup {
+			ndbmtdPodName := sc.configSummary.GetNodePodName(nodeId)
+			pod, err := sc.podLister.Pods(ndbmtdSfset.Namespace).Get(ndbmtdPodName)
+			if err != nil {
+				sc.logger.Error(err, "Failed to find pod", "pod", getNamespacedName2(ndbmtdSfset.Namespace, ndbmtdPodName))
+				return errorWhileProcessing(err)
+			}
+
+			restart := restarts[nodeId]
+			if pod.GetLabels()["controller-revision-hash"] == desiredPodRevisionHash && !restart.failed() {
+				// Data node is already up-to-date
+				continue
+			}
+
+			// Verify that the previous restarts of the data
+			// node, if any, are not looping
+			if sr := sc.checkDataNodeRestartLoop(nodeId, restart); sr.stopSync() {
+				return sr
+			}
+
+			// Verify that all the peers are available to restore the data
+			for _, peerNodeId := range nodesInNodegroup {
+				if peerStatus, exists := clusterStatus[peerNodeId]; peerNodeId != nodeId &&
+					(!exists || !peerStatus.IsConnected) {
+					sc.logger.Info("Waiting for the node group peers to connect before the initial restart of the data node",
+						"nodeId", nodeId, "peerNodeId", peerNodeId)
+					return finishProcessing()
+				}
+			}
+
+			if err = sc.recordDataNodeRestarts(ctx, restarts, desiredPodRevisionHash, nodeId); err != nil {
+				return errorWhileProcessing(err)
+			}
+			if err = sc.deleteDataNodePod(ctx, nodeId); err != nil {
+				return errorWhileProcessing(err)
+			}
+
+			// Delete the PVCs, after the pod, so that the data node starts with
+			// an empty data directory and creates its file system with the new config.
+			if err = sc.deleteDataNodeFileSystemPVCs(ctx, pod.Namespace, pod.Name); err != nil {
+				return errorWhileProcessing(err)
+			}
+
+			sc.logger.Info("Data node with old pod version is being initially restarted", "nodeId", nodeId)
+			sc.recorder.Eventf(sc.ndb, pod, corev1.EventTypeNormal, ReasonDataNodeRestarting, ActionRestart,
+				"Data node (nodeId=%d) is being restarted with an initial restart to apply the latest config", nodeId)
+			// Stop processing. Reconciliation will continue
+			// once the StatefulSet is fully ready again.
+			return finishProcessing()
+		}
+	}
+
+	// All the data nodes have the desired pod version
+	return continueProcessing()
+}
+
 // ensureAllResources creates all K8s resources required for running the
 // MySQL Cluster if they do no exist already. Resource creation needs to
 // be idempotent just like any other step in the syncHandler. The config
@@ -278,35 +663,63 @@ func (sc *SyncContext) ensureAllResources(ctx context.Context) syncResult {
 	var err error
 	var resourceExists bool
 
-	// create pod disruption budgets
-	if resourceExists, err = sc.ensurePodDisruptionBudget(ctx); err != nil {
-		return errorWhileProcessing(err)
-	}
-	if !resourceExists {
-		klog.Info("Created resource : Pod Disruption Budgets")
-	}
-
-	// ensure config map
-	var cm *corev1.ConfigMap
-	if cm, resourceExists, err = sc.configMapController.EnsureConfigMap(ctx, sc); err != nil {
-		return errorWhileProcessing(err)
-	}
-	if !resourceExists {
-		klog.Info("Created resource : Config Map")
-	}
-
-	// Create a new ConfigSummary
-	if sc.configSummary, err = ndbconfig.NewConfigSummary(cm.Data); err != nil {
-		// less likely to happen as the only possible error is a config
-		// parse error, and the configString was generated by the operator
-		return errorWhileProcessing(err)
-	}
+	// The NetworkPolicy, the ServiceAccount of the agents, the
+	// PodDisruptionBudgets, the ConfigMap and the operator password are
+	// independent of each other, so ensure them concurrently. They are
+	// all required before creating any pods.
+	if sr := runSyncStepsConcurrently(ctx,
+		func(ctx context.Context) syncResult {
+			return sc.networkPolicyController.ReconcileNetworkPolicy(ctx, sc)
+		},
+		func(ctx context.Context) syncResult {
+			return sc.serviceAccountController.ReconcileServiceAccount(ctx, sc)
+		},
+		func(ctx context.Context) syncResult {
+			// create pod disruption budgets
+			resourceExists, err := sc.ensurePodDisruptionBudget(ctx)
+			if err != nil {
+				return errorWhileProcessing(err)
+			}
+			if !resourceExists {
+				sc.logger.Info("Created resource", "resource", "PodDisruptionBudgets")
+			}
+			return continueProcessing()
+		},
+		func(ctx context.Context) syncResult {
+			// ensure config map
+			cm, resourceExists, err := sc.configMapController.EnsureConfigMap(ctx, sc)
+			if err != nil {
+				return errorWhileProcessing(err)
+			}
+			if !resourceExists {
+				sc.logger.Info("Created resource", "resource", "ConfigMap")
+				sc.recorder.Eventf(sc.ndb, nil, corev1.EventTypeNormal, ReasonConfigMapCreated, ActionCreated,
+					"ConfigMap %q was created with generation %d of the spec", getNamespacedName(cm), sc.ndb.Generation)
+			}
 
-	// First ensure that a operator password secret exists before creating statefulSet
-	secretClient := NewMySQLUserPasswordSecretInterface(sc.kubernetesClient)
-	if _, err := secretClient.EnsureNDBOperatorPassword(ctx, sc.ndb); err != nil {
-		klog.Errorf("Failed to ensure ndb-operator password secret : %s", err)
-		return errorWhileProcessing(err)
+			// Create a new ConfigSummary
+			if sc.configSummary, err = ndbconfig.NewConfigSummary(cm.Data); err != nil {
+				// less likely to happen as the only possible error is a config
+				// parse error, and the configString was generated by the operator
+				return errorWhileProcessing(err)
+			}
+			return continueProcessing()
+		},
+		func(ctx context.Context) syncResult {
+			// Ensure that a operator password secret exists before creating statefulSet
+			secretClient := NewMySQLUserPasswordSecretInterface(sc.kubernetesClient)
+			if _, err := secretClient.EnsureNDBOperatorPassword(ctx, sc.ndb); err != nil {
+				if errors.IsNotFound(err) {
+					// The custom secret specified in the spec is yet to be created
+					return sc.waitForCustomSecret(resources.GetMySQLNDBOperatorPasswordSecretName(sc.ndb))
+				}
+				sc.logger.Error(err, "Failed to ensure ndb-operator password secret")
+				return errorWhileProcessing(err)
+			}
+			return continueProcessing()
+		},
+	); sr.stopSync() {
+		return sr
 	}
 
 	initialSystemRestart := sc.ndb.Status.ProcessedGeneration == 0
@@ -328,10 +741,17 @@ func (sc *SyncContext) ensureAllResources(ctx context.Context) syncResult {
 	}
 	if !resourceExists {
 		// Management statefulset was just created.
-		klog.Info("Created resource : StatefulSet for Management Nodes")
+		sc.logger.Info("Created resource", "resource", "StatefulSet for Management Nodes")
+		if nc.Spec.RedundancyLevel > 1 && nc.GetManagementNodeCount() == 1 && !nc.HasArbitrator() {
+			// Warn that the arbitration depends on a single Management node
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeWarning, ReasonSingleArbitrator, ActionNone,
+				"The only Management node is the only arbitrator of the Data nodes, and a Data node "+
+					"failure while it is unavailable might shut down the MySQL Cluster. Consider "+
+					"running 2 Management nodes or an external arbitrator.")
+		}
 		// Wait for it to become ready before starting the data nodes.
 		// Reconciliation will continue once all the pods in the statefulset are ready.
-		klog.Infof("Reconciliation will continue after all the management nodes are ready.")
+		sc.logger.Info("Reconciliation will continue after all the management nodes are ready")
 		return finishProcessing()
 	}
 
@@ -347,11 +767,12 @@ func (sc *SyncContext) ensureAllResources(ctx context.Context) syncResult {
 			// Statefulset.
 
 			// User updated the NDB spec and the statefulset needs to be patched.
-			klog.Infof("A new generation of NdbCluster spec exists and the statefulset %q needs to be updated", getNamespacedName(sc.mgmdNodeSfset))
+			sc.logger.Info("A new generation of NdbCluster spec exists and the statefulset needs to be updated",
+				"statefulset", getNamespacedName(sc.mgmdNodeSfset))
 			if sr := sc.reconcileManagementNodeStatefulSet(ctx); sr.stopSync() {
 				if err := sr.getError(); err == nil {
 					// ManagementNodeStatefulSet patched successfully
-					klog.Infof("Delete smallest ordinal pod manually to avoid delay in restart")
+					sc.logger.Info("Delete smallest ordinal pod manually to avoid delay in restart")
 					_, err := sc.deletePodOnStsUpdate(ctx, sc.mgmdNodeSfset, 0)
 					return errorWhileProcessing(err)
 				}
@@ -367,23 +788,38 @@ func (sc *SyncContext) ensureAllResources(ctx context.Context) syncResult {
 		return finishProcessing()
 	}
 
-	// create the data node stateful set if it doesn't exist
-	if sc.dataNodeSfSet, resourceExists, err = sc.ensureDataNodeStatefulSet(ctx); err != nil {
+	// create the arbitrator stateful set if it is required and the data node
+	// stateful set, if they don't exist, concurrently as they are independent
+	if err = runConcurrently(ctx,
+		sc.ensureArbitratorStatefulSet,
+		func(ctx context.Context) (err error) {
+			sc.dataNodeSfSet, resourceExists, err = sc.ensureDataNodeStatefulSet(ctx)
+			return err
+		},
+	); err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	// Pin the data node pods to the zones planned for them, as
+	// they cannot be scheduled and become ready until then.
+	if err = sc.pinDataNodePodsToZones(ctx); err != nil {
 		return errorWhileProcessing(err)
 	}
+
 	if !resourceExists {
 		// Data nodes statefulset was just created.
-		klog.Info("Created resource : StatefulSet for Data Nodes")
+		sc.logger.Info("Created resource", "resource", "StatefulSet for Data Nodes")
 		// Wait for it to become ready.
 		// Reconciliation will continue once all the pods in the statefulset are ready.
-		klog.Infof("Reconciliation will continue after all the data nodes are ready.")
+		sc.logger.Info("Reconciliation will continue after all the data nodes are ready")
 		return finishProcessing()
 	}
 
 	if initialSystemRestart && !statefulsetUpdateComplete(sc.dataNodeSfSet) {
 		if !workloadHasConfigGeneration(sc.dataNodeSfSet, sc.configSummary.NdbClusterGeneration) {
 			// User updated the NDB spec and the statefulset needs to be patched
-			klog.Infof("A new generation of NdbCluster spec exists and the statefulset %q needs to be updated", getNamespacedName(sc.dataNodeSfSet))
+			sc.logger.Info("A new generation of NdbCluster spec exists and the statefulset needs to be updated",
+				"statefulset", getNamespacedName(sc.dataNodeSfSet))
 			if sr := sc.reconcileDataNodeStatefulSet(ctx); sr.stopSync() {
 				return sr
 			}
@@ -396,24 +832,42 @@ func (sc *SyncContext) ensureAllResources(ctx context.Context) syncResult {
 	}
 
 	// MySQL Server StatefulSet will be created only if required.
-	// For now, just verify that if it exists, it is indeed owned by the NdbCluster resource.
-	if sc.mysqldSfset, err = sc.validateMySQLServerStatefulSet(); err != nil {
+	// For now, just verify that if it exists, it is indeed owned by the NdbCluster
+	// resource, and retrieve the StatefulSets of the MySQL Server groups, if any.
+	if err = runConcurrently(ctx,
+		func(ctx context.Context) (err error) {
+			sc.mysqldSfset, err = sc.validateMySQLServerStatefulSet(ctx)
+			return err
+		},
+		func(ctx context.Context) (err error) {
+			sc.mysqldServerGroupSfsets, err = sc.mysqldServerGroupController.GetStatefulSets(ctx, sc)
+			return err
+		},
+	); err != nil {
 		return errorWhileProcessing(err)
 	}
 
-	if sc.mysqldSfset != nil && !statefulsetUpdateComplete(sc.mysqldSfset) && !nc.HasSyncError() {
+	if sc.mysqldSfset != nil && !statefulsetSettled(sc.mysqldSfset) && !nc.HasSyncError() {
 		// MySQL Server StatefulSet exists, but it is not complete yet
 		// which implies that this reconciliation was triggered only
 		// to update the NdbCluster status. No need to proceed further.
 		return finishProcessing()
 	}
 
+	for _, sfset := range sc.mysqldServerGroupSfsets {
+		if !statefulsetSettled(sfset) && !nc.HasSyncError() {
+			// A MySQL Server group StatefulSet is not complete yet.
+			// No need to proceed further.
+			return finishProcessing()
+		}
+	}
+
 	// The StatefulSets already existed before this sync loop.
 	// There is a rare chance that some other resources were created during
 	// this sync loop as they were dropped by some other application other
 	// than the operator. We can still continue processing in that case as
 	// they will become immediately ready.
-	klog.Infof("All resources exist")
+	sc.logger.Info("All resources exist")
 	return continueProcessing()
 }
 
@@ -425,7 +879,7 @@ func (sc *SyncContext) retrievePodErrors() (errs []string) {
 	// List all pods owned by NdbCluster resource
 	pods, listErr := sc.podLister.Pods(nc.Namespace).List(labels.Set(nc.GetLabels()).AsSelector())
 	if listErr != nil {
-		klog.Errorf("Failed to list pods owned by NdbCluster %q : %s", getNamespacedName(nc), listErr)
+		sc.logger.Error(listErr, "Failed to list pods owned by NdbCluster")
 		return []string{listErr.Error()}
 	}
 
@@ -450,13 +904,24 @@ func (sc *SyncContext) isStatefulsetUpdated(statefulset *appsv1.StatefulSet, exp
 		if completeOrReady == Ready {
 			return statefulsetReady(statefulset)
 		} else if completeOrReady == Complete {
-			return statefulsetUpdateComplete(statefulset)
+			return statefulsetSettled(statefulset)
 		}
-		klog.Infof("invalid argument: upgradeOrReady string")
+		sc.logger.Info("Invalid argument: upgradeOrReady string", "completeOrReady", completeOrReady)
 		return false
 	}
 }
 
+// areServerGroupStatefulSetsUpdated checks if all the
+// MySQL Server group StatefulSets are updated and complete.
+func (sc *SyncContext) areServerGroupStatefulSetsUpdated(expectedConfigGeneration int64) bool {
+	for _, sfset := range sc.mysqldServerGroupSfsets {
+		if !sc.isStatefulsetUpdated(sfset, expectedConfigGeneration, Complete) {
+			return false
+		}
+	}
+	return true
+}
+
 // ensureWorkloadsReadiness checks if all the workloads created for the
 // NdbCluster resource are ready. The sync is stopped if they are not ready.
 func (sc *SyncContext) ensureWorkloadsReadiness() syncResult {
@@ -468,21 +933,65 @@ func (sc *SyncContext) ensureWorkloadsReadiness() syncResult {
 	// has different generation, then this implies that there is a spec change and hence that
 	// particular workload needs to be patched. So, no need to wait for the stale versions
 	if sc.isStatefulsetUpdated(sc.mgmdNodeSfset, NdbGeneration, Complete) &&
+		sc.isStatefulsetUpdated(sc.arbitratorSfset, NdbGeneration, Complete) &&
 		sc.isStatefulsetUpdated(sc.dataNodeSfSet, NdbGeneration, Ready) &&
-		sc.isStatefulsetUpdated(sc.mysqldSfset, NdbGeneration, Complete) {
-		klog.Infof("All workloads owned by the NdbCluster resource %q are ready", getNamespacedName(sc.ndb))
+		sc.isStatefulsetUpdated(sc.mysqldSfset, NdbGeneration, Complete) &&
+		sc.areServerGroupStatefulSetsUpdated(NdbGeneration) {
+		sc.logger.Info("All workloads owned by the NdbCluster resource are ready")
 		return continueProcessing()
 	}
 
 	// Some workload is not ready yet => some pods are not ready yet
-	klog.Infof("Some pods owned by the NdbCluster resource %q are not ready yet",
-		getNamespacedName(sc.ndb))
+	sc.logger.Info("Some pods owned by the NdbCluster resource are not ready yet")
 
 	// Stop processing.
 	// Reconciliation will continue when all the pods are ready.
 	return finishProcessing()
 }
 
+// observeClusterState surfaces the cluster log, and updates the conditions
+// reflecting the current state of the MySQL Cluster. The steps only observe
+// the MySQL Cluster and are independent of each other, so they are run
+// concurrently. The cluster status is retrieved from the Management Server
+// only once and is shared by the steps requiring it. The first error
+// encountered by the steps is returned.
+func (sc *SyncContext) observeClusterState(ctx context.Context) error {
+	return runConcurrently(ctx,
+		func(ctx context.Context) error {
+			// Surface the important entries from the cluster log
+			sc.ensureClusterLogStreaming()
+			return nil
+		},
+		func(ctx context.Context) error {
+			var clusterStatus mgmapi.ClusterStatus
+			var err error
+			if sc.mgmdNodeSfset != nil &&
+				(statefulsetReady(sc.mgmdNodeSfset) || sc.ndb.Status.ProcessedGeneration != 0) {
+				// The Management Server is expected to be reachable
+				if clusterStatus, err = sc.getClusterStatus(ctx); err != nil {
+					sc.logger.Error(err, "Error getting cluster status from management server")
+				}
+			}
+
+			// Check if any of the data nodes have been disconnected by a
+			// network partition, and listen to the node events to check
+			// it again as soon as any node disconnects.
+			sc.detectPartitioning(clusterStatus, err)
+			sc.ensureNodeEventListening()
+
+			// Check if all the MySQL Cluster nodes are connected,
+			// irrespective of any pending spec changes.
+			sc.checkNodesConnectivity(clusterStatus, err)
+			return err
+		},
+		func(ctx context.Context) error {
+			// Verify the placement of the data nodes
+			// across the zones specified in the spec.
+			return sc.verifyDataNodePlacement(ctx)
+		},
+	)
+}
+
 // sync updates the configuration of the MySQL Cluster running
 // inside the K8s Cluster based on the NdbCluster spec. This is
 // the core reconciliation loop, and a complete update takes
@@ -494,8 +1003,36 @@ func (sc *SyncContext) sync(ctx context.Context) syncResult {
 	// them if they do not exist yet.
 	if sr := sc.ensureAllResources(ctx); sr.stopSync() {
 		if err := sr.getError(); err != nil {
-			klog.Errorf("Failed to ensure that all the required resources exist. Error : %v", err)
+			sc.logger.Error(err, "Failed to ensure that all the required resources exist")
+		}
+		return sr
+	}
+
+	// Observe the current state of the MySQL Cluster. A failed observation
+	// does not block the sync, as the conditions already reflect it, but
+	// the NdbCluster is requeued to observe the MySQL Cluster again soon.
+	if err := sc.observeClusterState(ctx); err != nil {
+		sc.logger.Error(err, "Failed to observe the state of the MySQL Cluster")
+		if sc.requeueAfter == 0 || observeRetryInterval < sc.requeueAfter {
+			sc.requeueAfter = observeRetryInterval
 		}
+	}
+
+	// Recreate the data node pods that were recreated by the StatefulSet
+	// controller before the PVCs deleted by an initial restart were gone.
+	if sr := sc.recreateDataNodePodsWithDeletedPVCs(ctx); sr.stopSync() {
+		return sr
+	}
+
+	// Handle the data nodes whose local PersistentVolumes are
+	// lost along with their worker nodes, if it has been enabled.
+	if sr := sc.handleLostLocalVolumes(ctx); sr.stopSync() {
+		return sr
+	}
+
+	// Recover the data nodes that are repeatedly failing
+	// to start, if it has been enabled in the spec.
+	if sr := sc.remediateDataNodes(ctx); sr.stopSync() {
 		return sr
 	}
 
@@ -507,6 +1044,32 @@ func (sc *SyncContext) sync(ctx context.Context) syncResult {
 		return sr
 	}
 
+	// Resync the MySQL Servers with the MySQL Cluster, if
+	// it has been recreated by an initial system restart.
+	if sr := sc.resyncMySQLServers(ctx); sr.stopSync() {
+		return sr
+	}
+
+	// Restore the backup specified in the spec, if any, into
+	// the new MySQL Cluster before starting the MySQL Servers.
+	if sr := sc.ensureInitFromBackup(ctx); sr.stopSync() {
+		return sr
+	}
+
+	// Verify that all the ready MySQL Servers can reach the NDB
+	// engine before rolling out any further changes to them.
+	if sr := sc.checkMySQLServersHealth(ctx); sr.stopSync() {
+		return sr
+	}
+
+	// Sample the health of the data nodes from ndbinfo,
+	// if it has been enabled in the spec.
+	sc.monitorHealth(ctx)
+
+	// Ensure that the latest epoch committed in
+	// the MySQL Cluster is being sampled
+	sc.ensureEpochSampling()
+
 	// The workloads are ready => MySQL Cluster is healthy.
 	// Before starting to handle any new changes from the Ndb
 	// Custom object, verify that the MySQL Cluster is in sync
@@ -527,6 +1090,11 @@ func (sc *SyncContext) sync(ctx context.Context) syncResult {
 		return sr
 	}
 
+	// Similarly, scale down or remove the MySQL Server groups
+	if sr := sc.mysqldServerGroupController.HandleScaleDown(ctx, sc); sr.stopSync() {
+		return sr
+	}
+
 	// Reconcile Management Server by updating the statefulSet definition.
 	// Management StatefulSet uses the default RollingUpdate strategy and
 	// the update will be rolled out by the controller once the StatefulSet
@@ -536,14 +1104,35 @@ func (sc *SyncContext) sync(ctx context.Context) syncResult {
 			// ManagementNodeStatefulSet patched successfully
 			if sc.ndb.HasSyncError() {
 				mgmdReplicaCount := *(sc.mgmdNodeSfset.Spec.Replicas)
-				klog.Infof("Delete largest ordinal pod manually to avoid delay in restart")
+				sc.logger.Info("Delete largest ordinal pod manually to avoid delay in restart")
 				_, err := sc.deletePodOnStsUpdate(ctx, sc.mgmdNodeSfset, mgmdReplicaCount-1)
 				return errorWhileProcessing(err)
 			}
 		}
 		return sr
 	}
-	klog.Info("All Management node pods are up-to-date and ready")
+
+	// Restart the Management node pods, if required, to update their
+	// definitions, but only when the arbitration remains available.
+	if sr := sc.ensureArbitrationAvailable(ctx); sr.stopSync() {
+		return sr
+	}
+	if sr := sc.ensureOnDeletePodVersion(ctx, sc.mgmdNodeSfset, "Management Node"); sr.stopSync() {
+		return sr
+	}
+	sc.logger.Info("All Management node pods are up-to-date and ready")
+
+	// Apply the config changes that do not require a restart
+	// of the Management nodes by reloading their config.
+	if sr := sc.reloadManagementConfig(ctx); sr.stopSync() {
+		return sr
+	}
+
+	// Reconcile the external arbitrator, if any, after the Management
+	// nodes. The update will be rolled out by the StatefulSet controller.
+	if sr := sc.reconcileArbitratorStatefulSet(ctx); sr.stopSync() {
+		return sr
+	}
 
 	// Reconcile Data Nodes by updating their statefulSet definition
 	if sr := sc.reconcileDataNodeStatefulSet(ctx); sr.stopSync() {
@@ -555,26 +1144,68 @@ func (sc *SyncContext) sync(ctx context.Context) syncResult {
 		return sr
 	}
 
+	// Expand the Data Node PVCs, if the storage has been increased in the spec
+	if sr := sc.expandDataNodePVCs(ctx); sr.stopSync() {
+		return sr
+	}
+
 	// Second pass of MySQL Server reconciliation
 	// Reconcile the rest of spec/config change in MySQL Server StatefulSet
 	if sr := sc.mysqldController.ReconcileStatefulSet(ctx, sc); sr.stopSync() {
 		return sr
 	}
 
+	// Restart the MySQL Server pods, if required, to update their definitions
+	if sr := sc.ensureOnDeletePodVersion(ctx, sc.mysqldSfset, "MySQL Server"); sr.stopSync() {
+		return sr
+	}
+
+	// Create or update the StatefulSets of the MySQL Server groups
+	if sr := sc.mysqldServerGroupController.ReconcileStatefulSets(ctx, sc); sr.stopSync() {
+		return sr
+	}
+
+	// Reconcile the HorizontalPodAutoscaler of the MySQL Servers
+	if sr := sc.reconcileHorizontalPodAutoscaler(ctx); sr.stopSync() {
+		return sr
+	}
+
+	// Reconcile the Gateway API resources exposing the MySQL Servers
+	if sr := sc.reconcileGateway(ctx); sr.stopSync() {
+		return sr
+	}
+
 	// Handle online add data node request
 	if sr := sc.ndbmtdController.handleAddNodeOnline(ctx, sc); sr.stopSync() {
 		return sr
 	}
 
+	// Install the custom plugins in the ready MySQL Servers before
+	// reconciling the root user, which might require one of them.
+	if sr := sc.mysqldController.reconcilePlugins(ctx, sc); sr.stopSync() {
+		return sr
+	}
+
 	// Reconcile the Root user
 	if sr := sc.mysqldController.reconcileRootUser(ctx, sc); sr.stopSync() {
 		return sr
 	}
 
+	// Create the Disk Data objects
+	if sr := sc.reconcileDiskDataObjects(ctx); sr.stopSync() {
+		return sr
+	}
+
+	// Load the SQL dump specified in the spec, if any, into the new MySQL
+	// Cluster once the root user and the Disk Data objects have been created.
+	if sr := sc.ensureInitFromDump(ctx); sr.stopSync() {
+		return sr
+	}
+
 	// At this point, the MySQL Cluster is in sync with the configuration in the config map.
 	// The configuration in the config map has to be checked to see if it is still the
 	// desired config specified in the Ndb object.
-	klog.Infof("The generation of the config in the configMap : \"%d\"", sc.configSummary.NdbClusterGeneration)
+	sc.logger.Info("Retrieved the generation of the config in the configMap", "generation", sc.configSummary.NdbClusterGeneration)
 
 	// Check if the config map has processed the latest NdbCluster Generation
 	patched, err := sc.patchConfigMap(ctx)
@@ -583,16 +1214,43 @@ func (sc *SyncContext) sync(ctx context.Context) syncResult {
 		// The next loop will actually start the sync
 		return finishProcessing()
 	} else if err != nil {
-		klog.Errorf("Failed to patch the ConfigMap. Error : %v", err)
+		sc.logger.Error(err, "Failed to patch the ConfigMap")
 		return errorWhileProcessing(err)
 	}
 
+	// Assign the MySQL Cluster nodes to the location domains of their
+	// worker nodes, if it is enabled in the spec. Any change will be
+	// rolled out to the MySQL Cluster starting from the next loop.
+	if sr := sc.reconcileLocationDomains(ctx); sr.stopSync() {
+		return sr
+	}
+
+	// Apply the rolling restarts requested via the annotations
+	// of the NdbCluster resource. The nodes will be restarted
+	// starting from the next loop.
+	if sr := sc.reconcileRestartRequests(ctx); sr.stopSync() {
+		return sr
+	}
+
+	// Reconcile the backup catalog with the backups stored by the data
+	// nodes. The reconciliation runs alongside the MySQL Cluster.
+	if sr := sc.reconcileBackupCatalog(ctx); sr.stopSync() {
+		return sr
+	}
+
+	// Verify the completed backups of the MySQL Cluster, if it is enabled
+	// in the spec. The verification runs alongside the MySQL Cluster.
+	if sr := sc.ensureBackupVerification(ctx); sr.stopSync() {
+		return sr
+	}
+
 	// MySQL Cluster in sync with the NdbCluster spec
 	sc.syncSuccess = true
 	return finishProcessing()
 }
 
-// updateNdbClusterStatus updates the status of the SyncContext's NdbCluster resource in the K8s API Server
+// updateNdbClusterStatus patches the status of the SyncContext's NdbCluster resource
+// in the K8s API Server with the fields that have changed since the last update.
 func (sc *SyncContext) updateNdbClusterStatus(ctx context.Context) (statusUpdated bool, err error) {
 
 	// Use the DeepCopied NdbCluster resource to make the update
@@ -600,61 +1258,64 @@ func (sc *SyncContext) updateNdbClusterStatus(ctx context.Context) (statusUpdate
 	// Generate status with recent state of various resources
 	status := sc.calculateNdbClusterStatus()
 
-	// Update the status. Use RetryOnConflict to automatically handle
-	// conflicts that can occur if the spec changes between the time
-	// the controller gets the NdbCluster object and updates it.
-	ndbClusterInterface := sc.ndbClientset().MysqlV1().NdbClusters(sc.ndb.Namespace)
-	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		// Check if the status has changed, if not the K8s update can be skipped
-		if statusEqual(&nc.Status, status) {
-			// Status up-to-date. No update required.
-			return nil
-		}
-
-		// Update the status of NdbCluster
-		status.DeepCopyInto(&nc.Status)
-		// Send the update to K8s server
-		klog.Infof("Updating the NdbCluster resource %q status", getNamespacedName(sc.ndb))
-		_, updateErr := ndbClusterInterface.UpdateStatus(ctx, nc, metav1.UpdateOptions{})
+	// Check if the status has changed, if not the K8s update can be skipped
+	if statusEqual(&nc.Status, status) {
+		// Status up-to-date. No update required.
+		return false, nil
+	}
 
-		if updateErr == nil {
-			// Status update succeeded
-			statusUpdated = true
-			return nil
-		}
+	// Generate a merge patch with only the changed fields of the status.
+	// The patch carries the resourceVersion of the NdbCluster read by this
+	// sync, so that a status calculated from an outdated NdbCluster doesn't
+	// overwrite any changes made in the meantime.
+	patch, err := createStatusPatch(&nc.Status, status, nc.ResourceVersion)
+	if err != nil {
+		sc.logger.Error(err, "Failed to generate the status patch for NdbCluster resource")
+		return false, err
+	}
 
-		// Get the latest version of the NdbCluster object from
-		// the K8s API Server for retrying the update.
-		var getErr error
-		nc, getErr = ndbClusterInterface.Get(ctx, sc.ndb.Name, metav1.GetOptions{})
-		if getErr != nil {
-			klog.Errorf("Failed to get NdbCluster resource during status update %q: %v",
-				getNamespacedName(sc.ndb), getErr)
-			return getErr
+	// Send the patch to K8s server
+	sc.logger.Info("Patching the NdbCluster resource status", "patch", string(patch))
+	if _, err = sc.ndbClientset().MysqlV1().NdbClusters(nc.Namespace).Patch(
+		ctx, nc.Name, types.MergePatchType, patch, metav1.PatchOptions{}, "status"); err != nil {
+		if errors.IsConflict(err) {
+			// The NdbCluster has been modified since this sync read it.
+			// The modification will trigger another sync, which will
+			// update the status from the latest NdbCluster.
+			sc.logger.Info("Skipping the status update as the NdbCluster resource has been modified")
+			return false, err
 		}
-
-		// Return the updateErr. If it is a conflict error, RetryOnConflict
-		// will retry the update with the latest version of the NdbCluster object.
-		return updateErr
-	})
-
-	if err != nil {
-		klog.Errorf("Failed to update the status of NdbCluster resource %q : %v",
-			getNamespacedName(sc.ndb), err)
+		sc.logger.Error(err, "Failed to update the status of NdbCluster resource")
+		return false, err
 	}
 
-	return statusUpdated, err
+	// Status patch succeeded
+	status.DeepCopyInto(&nc.Status)
+	return true, nil
 }
 
-// patchConfigMap patches the config map with the newer spec of the NdbCluster resource
+// patchConfigMap patches the config map with the newer spec of the NdbCluster resource.
+// It returns true if the config map was patched, or if the newer spec cannot be applied
+// to the MySQL Cluster at all, and the sync has to be stopped in both the cases.
 func (sc *SyncContext) patchConfigMap(ctx context.Context) (bool, error) {
 	// Check if the config map has processed the latest NdbCluster Generation
 	if sc.configSummary.NdbClusterGeneration != sc.ndb.Generation {
 		// The Ndb object spec has changed - patch the config map
-		klog.Info("A new generation of NdbCluster spec exists and the config map needs to be updated")
+		sc.logger.Info("A new generation of NdbCluster spec exists and the config map needs to be updated")
 		if _, err := sc.configMapController.PatchConfigMap(ctx, sc); err != nil {
+			if sc.disallowedDataNodeRestart != "" {
+				// The new spec cannot be applied. Stop the sync, without
+				// retrying, until the spec is updated to revert the changes.
+				sc.logger.Error(err, "Failed to apply the new generation of the NdbCluster spec")
+				sc.recorder.Eventf(sc.ndb, nil, corev1.EventTypeWarning,
+					ReasonDataNodeSystemRestartRequired, ActionNone, "%s", err)
+				return true, nil
+			}
 			return false, err
 		}
+		sc.recorder.Eventf(sc.ndb, nil, corev1.EventTypeNormal, ReasonConfigMapUpdated, ActionUpdated,
+			"ConfigMap %q was updated with generation %d of the spec",
+			getNamespacedName2(sc.ndb.Namespace, sc.ndb.GetConfigMapName()), sc.ndb.Generation)
 		return true, nil
 	}
 
@@ -662,6 +1323,15 @@ func (sc *SyncContext) patchConfigMap(ctx context.Context) (bool, error) {
 	return false, nil
 }
 
+// getDataNodeId returns the nodeId of the data node running in the given
+// pod, as declared in the current config, or 0 if there is no such node.
+func (sc *SyncContext) getDataNodeId(podName string) int {
+	if nodeIds := sc.configSummary.GetPodNodeIds(podName); len(nodeIds) != 0 {
+		return nodeIds[0]
+	}
+	return 0
+}
+
 // deletePodOnStsUpdate deletes the pod with given pod ordinal index
 func (sc *SyncContext) deletePodOnStsUpdate(
 	ctx context.Context, mgmdSfset *appsv1.StatefulSet, podOrdinalIndex int32) (podDeleted bool, err error) {
@@ -671,10 +1341,10 @@ func (sc *SyncContext) deletePodOnStsUpdate(
 	// Delete it and let the statefulset controller restart it with the latest pod definition.
 	err = sc.kubeClientset().CoreV1().Pods(namespace).Delete(ctx, podName, metav1.DeleteOptions{})
 	if err != nil {
-		klog.Errorf("Failed to delete pod '%s/%s' running : %s", namespace, podName, err)
+		sc.logger.Error(err, "Failed to delete pod", "pod", getNamespacedName2(namespace, podName))
 		return false, err
 	}
-	klog.Infof("Successfully deleted pod '%s/%s'", namespace, podName)
+	sc.logger.Info("Successfully deleted pod", "pod", getNamespacedName2(namespace, podName))
 	// The pod has been deleted.
 	return true, nil
 }
@@ -0,0 +1,72 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func Test_mgmdPodRestartedByRollout(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	sc := f.c.newSyncContext(context.Background(), ndb)
+	sc.podLister = corelisters.NewPodLister(podIndexer)
+	sc.mgmdNodeSfset = &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-mgmd", Namespace: ns},
+		Status:     appsv1.StatefulSetStatus{CurrentRevision: "old", UpdateRevision: "new"},
+	}
+	sc.configSummary = &ndbconfig.ConfigSummary{
+		Nodes: []ndbconfig.NodeIdentity{
+			{NodeId: 1, NodeType: constants.NdbNodeTypeMgmd, PodName: "test-mgmd-0"},
+			{NodeId: 2, NodeType: constants.NdbNodeTypeMgmd, PodName: "test-mgmd-1"},
+		},
+	}
+
+	// The pod of the Management node 2 has been restarted with
+	// the update revision while the other still runs the old one
+	for podName, revision := range map[string]string{"test-mgmd-0": "old", "test-mgmd-1": "new"} {
+		if err := podIndexer.Add(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      podName,
+				Namespace: ns,
+				Labels:    map[string]string{appsv1.ControllerRevisionHashLabelKey: revision},
+			},
+		}); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+
+	if sc.mgmdPodRestartedByRollout(1) {
+		t.Error("Management node 1, yet to be restarted, was considered restarted by the rollout")
+	}
+	if !sc.mgmdPodRestartedByRollout(2) {
+		t.Error("Management node 2, running the update revision, was not considered restarted by the rollout")
+	}
+
+	// A deleted pod, yet to be recreated, is being restarted by the rollout
+	if err := podIndexer.Delete(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-mgmd-0", Namespace: ns}}); err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	if !sc.mgmdPodRestartedByRollout(1) {
+		t.Error("Management node 1, whose pod is being recreated, was not considered restarted by the rollout")
+	}
+}
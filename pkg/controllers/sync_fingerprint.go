@@ -0,0 +1,145 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+)
+
+// maxSkippedSyncPeriod is the maximum period for which the reconciliation
+// of an unchanged NdbCluster is skipped. A full sync is run at least once
+// within this period to detect the changes in the MySQL Cluster that are
+// not visible via the K8s resources, e.g. a network partition.
+const maxSkippedSyncPeriod = 5 * time.Minute
+
+// syncFingerprint identifies the state of an NdbCluster resource and
+// its child resources at the time of its last successful sync.
+type syncFingerprint struct {
+	hash         string
+	fullSyncTime time.Time
+}
+
+// syncFingerprintStore stores the fingerprints of the NdbClusters whose
+// last sync completed successfully and left them in sync with their spec.
+// The syncHandler uses them to skip the reconciliation of the NdbClusters
+// that have not changed since then.
+type syncFingerprintStore struct {
+	// fingerprints holds the fingerprints keyed by the NdbCluster key
+	fingerprints map[string]syncFingerprint
+	// mutex protects the fingerprints map
+	mutex sync.Mutex
+}
+
+// newSyncFingerprintStore creates a new syncFingerprintStore
+func newSyncFingerprintStore() *syncFingerprintStore {
+	return &syncFingerprintStore{
+		fingerprints: make(map[string]syncFingerprint),
+	}
+}
+
+// canSkipSync returns true if the hash matches the fingerprint
+// recorded by the last successful sync of the NdbCluster with
+// the given key, and if that sync is not older than the
+// maxSkippedSyncPeriod. An empty hash is never skipped.
+func (sfs *syncFingerprintStore) canSkipSync(key, hash string) bool {
+	if hash == "" {
+		return false
+	}
+
+	sfs.mutex.Lock()
+	defer sfs.mutex.Unlock()
+
+	fingerprint, exists := sfs.fingerprints[key]
+	return exists && fingerprint.hash == hash &&
+		time.Since(fingerprint.fullSyncTime) < maxSkippedSyncPeriod
+}
+
+// record stores the hash of an NdbCluster that was successfully synced
+func (sfs *syncFingerprintStore) record(key, hash string) {
+	if hash == "" {
+		// The NdbCluster cannot be skipped
+		return
+	}
+
+	sfs.mutex.Lock()
+	defer sfs.mutex.Unlock()
+
+	sfs.fingerprints[key] = syncFingerprint{
+		hash:         hash,
+		fullSyncTime: time.Now(),
+	}
+}
+
+// forget removes the fingerprint of the NdbCluster with the given key
+func (sfs *syncFingerprintStore) forget(key string) {
+	sfs.mutex.Lock()
+	defer sfs.mutex.Unlock()
+
+	delete(sfs.fingerprints, key)
+}
+
+// computeSyncFingerprint returns a hash of the generation and the resource
+// versions of the NdbCluster and its ConfigMap and StatefulSets. The hash
+// changes whenever the spec, the status or the config of the NdbCluster
+// changes, or when any of its workloads is updated. An empty hash is
+// returned if any of the workloads is not ready, as the pod failures and
+// restarts are not reflected in the resource versions, so that the
+// NdbCluster is fully reconciled until all its pods are ready. It is
+// computed only from the informer caches and does not send any request
+// to the K8s API Server or the Management Server.
+func computeSyncFingerprint(nc *v1.NdbCluster,
+	configMapLister corelisters.ConfigMapLister, statefulSetLister appslisters.StatefulSetLister) (string, error) {
+
+	hasher := fnv.New64a()
+	_, _ = fmt.Fprintf(hasher, "%s/%s/%d/%d;",
+		nc.UID, nc.ResourceVersion, nc.Generation, nc.Status.ProcessedGeneration)
+
+	// writeResourceVersion adds the resource version
+	// of the given child resource to the hash
+	writeResourceVersion := func(name string, obj metav1.Object, err error) error {
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			// The resource doesn't exist
+			_, _ = fmt.Fprintf(hasher, "%s=;", name)
+			return nil
+		}
+		_, _ = fmt.Fprintf(hasher, "%s=%s;", name, obj.GetResourceVersion())
+		return nil
+	}
+
+	configMapName := nc.GetConfigMapName()
+	configMap, err := configMapLister.ConfigMaps(nc.Namespace).Get(configMapName)
+	if err = writeResourceVersion(configMapName, configMap, err); err != nil {
+		return "", err
+	}
+
+	for _, nodeType := range []constants.NdbNodeType{
+		constants.NdbNodeTypeMgmd, constants.NdbNodeTypeNdbmtd, constants.NdbNodeTypeMySQLD} {
+		sfsetName := nc.GetWorkloadName(nodeType)
+		sfset, err := statefulSetLister.StatefulSets(nc.Namespace).Get(sfsetName)
+		if err = writeResourceVersion(sfsetName, sfset, err); err != nil {
+			return "", err
+		}
+		if sfset != nil && !statefulsetReady(sfset) {
+			// Not all the pods of the workload are ready
+			return "", nil
+		}
+	}
+
+	return fmt.Sprintf("%x", hasher.Sum64()), nil
+}
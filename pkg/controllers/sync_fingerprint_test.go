@@ -0,0 +1,121 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func Test_computeSyncFingerprint(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "test", 2)
+	nc.ResourceVersion = "1"
+
+	configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	sfsetIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	configMapLister := corelisters.NewConfigMapLister(configMapIndexer)
+	sfsetLister := appslisters.NewStatefulSetLister(sfsetIndexer)
+
+	fingerprint := func() string {
+		hash, err := computeSyncFingerprint(nc, configMapLister, sfsetLister)
+		if err != nil {
+			t.Fatalf("Unexpected error : %s", err)
+		}
+		return hash
+	}
+
+	// The fingerprint should be stable when nothing changes
+	initialHash := fingerprint()
+	if initialHash != fingerprint() {
+		t.Fatal("Fingerprint changed without any change in the resources")
+	}
+
+	// A change in any of the resources should change the fingerprint
+	if err := configMapIndexer.Add(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name: nc.GetConfigMapName(), Namespace: nc.Namespace, ResourceVersion: "2"}}); err != nil {
+		t.Fatalf("Unexpected error : %s", err)
+	}
+	configMapHash := fingerprint()
+	if configMapHash == initialHash {
+		t.Error("Fingerprint did not change when the ConfigMap was created")
+	}
+
+	replicas := int32(2)
+	sfset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nc.GetWorkloadName(constants.NdbNodeTypeMgmd), Namespace: nc.Namespace, ResourceVersion: "3"},
+		Spec:   appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status: appsv1.StatefulSetStatus{ReadyReplicas: replicas},
+	}
+	if err := sfsetIndexer.Add(sfset); err != nil {
+		t.Fatalf("Unexpected error : %s", err)
+	}
+	sfsetHash := fingerprint()
+	if sfsetHash == configMapHash {
+		t.Error("Fingerprint did not change when the StatefulSet was created")
+	}
+
+	sfset = sfset.DeepCopy()
+	sfset.ResourceVersion = "4"
+	if err := sfsetIndexer.Update(sfset); err != nil {
+		t.Fatalf("Unexpected error : %s", err)
+	}
+	if fingerprint() == sfsetHash {
+		t.Error("Fingerprint did not change when the StatefulSet was updated")
+	}
+
+	nc.ResourceVersion = "5"
+	if fingerprint() == sfsetHash {
+		t.Error("Fingerprint did not change when the NdbCluster was updated")
+	}
+
+	// No fingerprint should be computed while a workload is not ready
+	sfset = sfset.DeepCopy()
+	sfset.Status.ReadyReplicas = 1
+	if err := sfsetIndexer.Update(sfset); err != nil {
+		t.Fatalf("Unexpected error : %s", err)
+	}
+	if hash := fingerprint(); hash != "" {
+		t.Errorf("Expected an empty fingerprint for a workload that is not ready but got %q", hash)
+	}
+}
+
+func Test_syncFingerprintStore(t *testing.T) {
+	sfs := newSyncFingerprintStore()
+
+	if sfs.canSkipSync("default/test", "hash1") {
+		t.Error("Sync skipped without a recorded fingerprint")
+	}
+
+	sfs.record("default/test", "hash1")
+	if !sfs.canSkipSync("default/test", "hash1") {
+		t.Error("Sync not skipped with a matching fingerprint")
+	}
+	if sfs.canSkipSync("default/test", "hash2") {
+		t.Error("Sync skipped with a different fingerprint")
+	}
+	if sfs.canSkipSync("default/test2", "hash1") {
+		t.Error("Sync of a different NdbCluster skipped")
+	}
+
+	sfs.record("default/test2", "")
+	if sfs.canSkipSync("default/test2", "") {
+		t.Error("Sync skipped with an empty fingerprint")
+	}
+
+	sfs.forget("default/test")
+	if sfs.canSkipSync("default/test", "hash1") {
+		t.Error("Sync skipped after the fingerprint was forgotten")
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// workqueueMetricValue is a single numerical value
+// that implements all the workqueue metric interfaces
+type workqueueMetricValue struct {
+	value float64
+	mutex sync.Mutex
+}
+
+func (mv *workqueueMetricValue) Inc() { mv.add(1) }
+func (mv *workqueueMetricValue) Dec() { mv.add(-1) }
+
+func (mv *workqueueMetricValue) add(delta float64) {
+	mv.mutex.Lock()
+	defer mv.mutex.Unlock()
+	mv.value += delta
+}
+
+func (mv *workqueueMetricValue) Set(value float64) {
+	mv.mutex.Lock()
+	defer mv.mutex.Unlock()
+	mv.value = value
+}
+
+func (mv *workqueueMetricValue) get() float64 {
+	mv.mutex.Lock()
+	defer mv.mutex.Unlock()
+	return mv.value
+}
+
+// workqueueMetricSummary records the sum and the count of
+// the observations and implements the HistogramMetric
+type workqueueMetricSummary struct {
+	sum   float64
+	count int64
+	mutex sync.Mutex
+}
+
+func (ms *workqueueMetricSummary) Observe(value float64) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	ms.sum += value
+	ms.count++
+}
+
+func (ms *workqueueMetricSummary) get() (sum float64, count int64) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	return ms.sum, ms.count
+}
+
+// workqueueMetrics holds the metrics of a single named workqueue
+type workqueueMetrics struct {
+	depth                   workqueueMetricValue
+	adds                    workqueueMetricValue
+	retries                 workqueueMetricValue
+	latency                 workqueueMetricSummary
+	workDuration            workqueueMetricSummary
+	unfinishedWorkSeconds   workqueueMetricValue
+	longestRunningProcessor workqueueMetricValue
+}
+
+// workqueueMetricsProvider implements the workqueue.MetricsProvider and
+// collects the metrics of all the named workqueues created in the process.
+type workqueueMetricsProvider struct {
+	// queues holds the metrics keyed by the workqueue names
+	queues map[string]*workqueueMetrics
+	// mutex protects the queues map
+	mutex sync.Mutex
+}
+
+// queueMetricsProvider collects the metrics of the workqueues
+var queueMetricsProvider = &workqueueMetricsProvider{
+	queues: make(map[string]*workqueueMetrics),
+}
+
+func init() {
+	// The provider has to be set before any named workqueue is created
+	workqueue.SetProvider(queueMetricsProvider)
+}
+
+// getQueueMetrics returns the metrics of the workqueue with the given name
+func (p *workqueueMetricsProvider) getQueueMetrics(name string) *workqueueMetrics {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	qm, exists := p.queues[name]
+	if !exists {
+		qm = &workqueueMetrics{}
+		p.queues[name] = qm
+	}
+	return qm
+}
+
+func (p *workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return &p.getQueueMetrics(name).depth
+}
+
+func (p *workqueueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return &p.getQueueMetrics(name).adds
+}
+
+func (p *workqueueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return &p.getQueueMetrics(name).latency
+}
+
+func (p *workqueueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return &p.getQueueMetrics(name).workDuration
+}
+
+func (p *workqueueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return &p.getQueueMetrics(name).unfinishedWorkSeconds
+}
+
+func (p *workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return &p.getQueueMetrics(name).longestRunningProcessor
+}
+
+func (p *workqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return &p.getQueueMetrics(name).retries
+}
+
+// writeMetrics writes the metrics of all the workqueues to the
+// given writer in the Prometheus text exposition format.
+func (p *workqueueMetricsProvider) writeMetrics(w io.Writer) {
+	p.mutex.Lock()
+	names := make([]string, 0, len(p.queues))
+	for name := range p.queues {
+		names = append(names, name)
+	}
+	queues := p.queues
+	p.mutex.Unlock()
+	sort.Strings(names)
+
+	samples := func(value func(qm *workqueueMetrics) float64) map[string]float64 {
+		values := make(map[string]float64, len(names))
+		for _, name := range names {
+			values[name] = value(queues[name])
+		}
+		return values
+	}
+
+	writeLabelledMetric(w, "workqueue_depth", "gauge",
+		"Current depth of the workqueue.", "name",
+		samples(func(qm *workqueueMetrics) float64 { return qm.depth.get() }), names)
+	writeLabelledMetric(w, "workqueue_adds_total", "counter",
+		"Total number of adds handled by the workqueue.", "name",
+		samples(func(qm *workqueueMetrics) float64 { return qm.adds.get() }), names)
+	writeLabelledMetric(w, "workqueue_retries_total", "counter",
+		"Total number of retries handled by the workqueue.", "name",
+		samples(func(qm *workqueueMetrics) float64 { return qm.retries.get() }), names)
+	writeLabelledMetric(w, "workqueue_unfinished_work_seconds", "gauge",
+		"The number of seconds of work that is in progress and hasn't been observed by work_duration.", "name",
+		samples(func(qm *workqueueMetrics) float64 { return qm.unfinishedWorkSeconds.get() }), names)
+	writeLabelledMetric(w, "workqueue_longest_running_processor_seconds", "gauge",
+		"The number of seconds the longest running processor of the workqueue has been running.", "name",
+		samples(func(qm *workqueueMetrics) float64 { return qm.longestRunningProcessor.get() }), names)
+
+	for _, summary := range []struct {
+		name, help string
+		get        func(qm *workqueueMetrics) *workqueueMetricSummary
+	}{
+		{"workqueue_queue_duration_seconds", "How long in seconds an item stays in the workqueue before being requested.",
+			func(qm *workqueueMetrics) *workqueueMetricSummary { return &qm.latency }},
+		{"workqueue_work_duration_seconds", "How long in seconds processing an item from the workqueue takes.",
+			func(qm *workqueueMetrics) *workqueueMetricSummary { return &qm.workDuration }},
+	} {
+		writeSummaryMetric(w, summary.name, summary.help, "name", names, func(name string) (float64, int64) {
+			return summary.get(queues[name]).get()
+		})
+	}
+}
+
+// priorityQueueMetrics records the metrics of a priorityQueue in the same
+// way the default workqueue records them, via the workqueue.MetricsProvider.
+type priorityQueueMetrics struct {
+	depth                   workqueue.GaugeMetric
+	adds                    workqueue.CounterMetric
+	latency                 workqueue.HistogramMetric
+	workDuration            workqueue.HistogramMetric
+	unfinishedWorkSeconds   workqueue.SettableGaugeMetric
+	longestRunningProcessor workqueue.SettableGaugeMetric
+
+	// addTimes holds the time at which the waiting items were added
+	addTimes map[interface{}]time.Time
+	// processingStartTimes holds the time at which
+	// the items being processed were handed out
+	processingStartTimes map[interface{}]time.Time
+}
+
+// newPriorityQueueMetrics returns the priorityQueueMetrics that record the
+// metrics of the priorityQueue with the given name using the provider.
+func newPriorityQueueMetrics(provider workqueue.MetricsProvider, name string) *priorityQueueMetrics {
+	return &priorityQueueMetrics{
+		depth:                   provider.NewDepthMetric(name),
+		adds:                    provider.NewAddsMetric(name),
+		latency:                 provider.NewLatencyMetric(name),
+		workDuration:            provider.NewWorkDurationMetric(name),
+		unfinishedWorkSeconds:   provider.NewUnfinishedWorkSecondsMetric(name),
+		longestRunningProcessor: provider.NewLongestRunningProcessorSecondsMetric(name),
+		addTimes:                make(map[interface{}]time.Time),
+		processingStartTimes:    make(map[interface{}]time.Time),
+	}
+}
+
+// The following methods should be called with the lock of the
+// priorityQueue held, and can be called on a nil priorityQueueMetrics.
+
+// add records the queueing of the item
+func (m *priorityQueueMetrics) add(item interface{}) {
+	if m == nil {
+		return
+	}
+	m.adds.Inc()
+	m.depth.Inc()
+	if _, exists := m.addTimes[item]; !exists {
+		m.addTimes[item] = time.Now()
+	}
+}
+
+// get records the handing out of the item to a worker
+func (m *priorityQueueMetrics) get(item interface{}) {
+	if m == nil {
+		return
+	}
+	m.depth.Dec()
+	m.processingStartTimes[item] = time.Now()
+	if addTime, exists := m.addTimes[item]; exists {
+		m.latency.Observe(time.Since(addTime).Seconds())
+		delete(m.addTimes, item)
+	}
+}
+
+// done records the completion of the processing of the item
+func (m *priorityQueueMetrics) done(item interface{}) {
+	if m == nil {
+		return
+	}
+	if startTime, exists := m.processingStartTimes[item]; exists {
+		m.workDuration.Observe(time.Since(startTime).Seconds())
+		delete(m.processingStartTimes, item)
+	}
+}
+
+// updateUnfinishedWork updates the metrics of the items being processed
+func (m *priorityQueueMetrics) updateUnfinishedWork() {
+	if m == nil {
+		return
+	}
+	var total, longest float64
+	for _, startTime := range m.processingStartTimes {
+		elapsed := time.Since(startTime).Seconds()
+		total += elapsed
+		longest = math.Max(longest, elapsed)
+	}
+	m.unfinishedWorkSeconds.Set(total)
+	m.longestRunningProcessor.Set(longest)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_priorityQueueMetrics(t *testing.T) {
+	provider := &workqueueMetricsProvider{
+		queues: make(map[string]*workqueueMetrics),
+	}
+	pqm := newPriorityQueueMetrics(provider, "test")
+	qm := provider.getQueueMetrics("test")
+
+	pqm.add("default/test-1")
+	pqm.add("default/test-2")
+	if depth, adds := qm.depth.get(), qm.adds.get(); depth != 2 || adds != 2 {
+		t.Errorf("Expected depth 2 and adds 2 but got %v and %v", depth, adds)
+	}
+
+	pqm.get("default/test-1")
+	pqm.updateUnfinishedWork()
+	if depth := qm.depth.get(); depth != 1 {
+		t.Errorf("Expected depth 1 but got %v", depth)
+	}
+	if _, count := qm.latency.get(); count != 1 {
+		t.Errorf("Expected 1 latency observation but got %d", count)
+	}
+	if len(pqm.processingStartTimes) != 1 {
+		t.Errorf("Expected 1 item being processed but got %d", len(pqm.processingStartTimes))
+	}
+
+	pqm.done("default/test-1")
+	pqm.updateUnfinishedWork()
+	if _, count := qm.workDuration.get(); count != 1 {
+		t.Errorf("Expected 1 work duration observation but got %d", count)
+	}
+	if unfinished := qm.unfinishedWorkSeconds.get(); unfinished != 0 {
+		t.Errorf("Expected no unfinished work but got %v", unfinished)
+	}
+
+	var metrics bytes.Buffer
+	provider.writeMetrics(&metrics)
+	for _, sample := range []string{
+		"workqueue_depth{name=\"test\"} 1\n",
+		"workqueue_adds_total{name=\"test\"} 2\n",
+		"workqueue_queue_duration_seconds_count{name=\"test\"} 1\n",
+		"workqueue_work_duration_seconds_count{name=\"test\"} 1\n",
+	} {
+		if !strings.Contains(metrics.String(), sample) {
+			t.Errorf("Expected %q in the metrics but got :\n%s", sample, metrics.String())
+		}
+	}
+}
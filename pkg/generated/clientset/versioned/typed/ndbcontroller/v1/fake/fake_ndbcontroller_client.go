@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -20,6 +20,14 @@ func (c *FakeMysqlV1) NdbClusters(namespace string) v1.NdbClusterInterface {
 	return &FakeNdbClusters{c, namespace}
 }
 
+func (c *FakeMysqlV1) NdbClusterPolicies() v1.NdbClusterPolicyInterface {
+	return &FakeNdbClusterPolicies{c}
+}
+
+func (c *FakeMysqlV1) NdbOperators() v1.NdbOperatorInterface {
+	return &FakeNdbOperators{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeMysqlV1) RESTClient() rest.Interface {
@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -7,3 +7,7 @@
 package v1
 
 type NdbClusterExpansion interface{}
+
+type NdbClusterPolicyExpansion interface{}
+
+type NdbOperatorExpansion interface{}
@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -15,6 +15,8 @@ import (
 type MysqlV1Interface interface {
 	RESTClient() rest.Interface
 	NdbClustersGetter
+	NdbClusterPoliciesGetter
+	NdbOperatorsGetter
 }
 
 // MysqlV1Client is used to interact with features provided by the mysql.oracle.com group.
@@ -26,6 +28,14 @@ func (c *MysqlV1Client) NdbClusters(namespace string) NdbClusterInterface {
 	return newNdbClusters(c, namespace)
 }
 
+func (c *MysqlV1Client) NdbClusterPolicies() NdbClusterPolicyInterface {
+	return newNdbClusterPolicies(c)
+}
+
+func (c *MysqlV1Client) NdbOperators() NdbOperatorInterface {
+	return newNdbOperators(c)
+}
+
 // NewForConfig creates a new MysqlV1Client for the given config.
 func NewForConfig(c *rest.Config) (*MysqlV1Client, error) {
 	config := *c
@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -43,6 +43,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=mysql.oracle.com, Version=v1
 	case v1.SchemeGroupVersion.WithResource("ndbclusters"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Mysql().V1().NdbClusters().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("ndbclusterpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Mysql().V1().NdbClusterPolicies().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("ndboperators"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Mysql().V1().NdbOperators().Informer()}, nil
 
 	}
 
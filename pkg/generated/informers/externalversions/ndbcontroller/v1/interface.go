@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -14,6 +14,10 @@ import (
 type Interface interface {
 	// NdbClusters returns a NdbClusterInformer.
 	NdbClusters() NdbClusterInformer
+	// NdbClusterPolicies returns a NdbClusterPolicyInformer.
+	NdbClusterPolicies() NdbClusterPolicyInformer
+	// NdbOperators returns a NdbOperatorInformer.
+	NdbOperators() NdbOperatorInformer
 }
 
 type version struct {
@@ -31,3 +35,13 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (v *version) NdbClusters() NdbClusterInformer {
 	return &ndbClusterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// NdbClusterPolicies returns a NdbClusterPolicyInformer.
+func (v *version) NdbClusterPolicies() NdbClusterPolicyInformer {
+	return &ndbClusterPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// NdbOperators returns a NdbOperatorInformer.
+func (v *version) NdbOperators() NdbOperatorInformer {
+	return &ndbOperatorInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -13,3 +13,11 @@ type NdbClusterListerExpansion interface{}
 // NdbClusterNamespaceListerExpansion allows custom methods to be added to
 // NdbClusterNamespaceLister.
 type NdbClusterNamespaceListerExpansion interface{}
+
+// NdbClusterPolicyListerExpansion allows custom methods to be added to
+// NdbClusterPolicyLister.
+type NdbClusterPolicyListerExpansion interface{}
+
+// NdbOperatorListerExpansion allows custom methods to be added to
+// NdbOperatorLister.
+type NdbOperatorListerExpansion interface{}
@@ -0,0 +1,15 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package helpers
+
+import "strings"
+
+// This file defines useful utility functions related to shell scripts
+
+// ShellQuote quotes the given string so that a shell treats it as a single
+// word, without expanding any variables, commands or wildcards in it.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
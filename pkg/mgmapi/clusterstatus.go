@@ -47,6 +47,10 @@ type NodeStatus struct {
 	// isConnected reports if the node is fully started and connected to cluster
 	IsConnected bool
 
+	// Status is the status of the node as reported by the
+	// Management Server, e.g. STARTED, STARTING or NOT_STARTED
+	Status string
+
 	// NodeGroup reports which node group the node is in, -1 if unclear or wrong node type
 	NodeGroup int
 
@@ -58,6 +62,25 @@ func (ns *NodeStatus) IsDataNode() bool {
 	return ns.NodeType == NodeTypeNDB
 }
 
+// IsStarting returns true if the data node is going through its start phases
+func (ns *NodeStatus) IsStarting() bool {
+	return ns.IsDataNode() && ns.Status == "STARTING"
+}
+
+// IsRestarting returns true if the data node is being stopped,
+// restarted or started, i.e. it is expected to be disconnected
+// from the MySQL Cluster for the duration of the restart.
+func (ns *NodeStatus) IsRestarting() bool {
+	if !ns.IsDataNode() {
+		return false
+	}
+	switch ns.Status {
+	case "NOT_STARTED", "STARTING", "RESTARTING", "SHUTTING_DOWN":
+		return true
+	}
+	return false
+}
+
 func (ns *NodeStatus) IsMgmNode() bool {
 	return ns.NodeType == NodeTypeMGM
 }
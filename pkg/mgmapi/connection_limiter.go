@@ -0,0 +1,72 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mgmapi
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// connectionSlotTimeout is the maximum time NewMgmClient
+// waits for a free connection slot before failing
+const connectionSlotTimeout = 30 * time.Second
+
+// connectionLimiter limits the number of concurrent
+// connections opened to the Management Servers
+type connectionLimiter struct {
+	// slots has a buffer of the maximum number of connections
+	// allowed. It is nil if the connections are not limited.
+	slots chan struct{}
+	// active is the number of connections currently open
+	active atomic.Int32
+}
+
+// connections limits the Management Server connections
+// opened to all the MySQL Clusters by the process.
+var connections = &connectionLimiter{}
+
+// SetMaxConnections limits the number of concurrent connections opened
+// by the NewMgmClient to the Management Servers of all the MySQL Clusters
+// to maxConnections. The connections are not limited if maxConnections is
+// 0. It should be called before any client is created.
+func SetMaxConnections(maxConnections int) {
+	connections = &connectionLimiter{}
+	if maxConnections > 0 {
+		connections.slots = make(chan struct{}, maxConnections)
+	}
+}
+
+// GetActiveConnections returns the number of
+// connections currently open to the Management Servers
+func GetActiveConnections() int {
+	return int(connections.active.Load())
+}
+
+// acquire waits for a free connection slot until
+// the connectionSlotTimeout or until ctx is done
+func (cl *connectionLimiter) acquire(ctx context.Context) error {
+	if cl.slots != nil {
+		select {
+		case cl.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(connectionSlotTimeout):
+			return fmt.Errorf("timed out waiting for one of the %d Management Server connections to be freed",
+				cap(cl.slots))
+		}
+	}
+	cl.active.Add(1)
+	return nil
+}
+
+// release frees a connection slot acquired earlier
+func (cl *connectionLimiter) release() {
+	cl.active.Add(-1)
+	if cl.slots != nil {
+		<-cl.slots
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mgmapi
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_connectionLimiter(t *testing.T) {
+	cl := &connectionLimiter{slots: make(chan struct{}, 2)}
+
+	// Acquire all the slots
+	for i := 0; i < 2; i++ {
+		if err := cl.acquire(context.Background()); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+	if active := cl.active.Load(); active != 2 {
+		t.Errorf("Expected 2 active connections but got %d", active)
+	}
+
+	// Further connections should wait for a slot to be released
+	acquired := make(chan error)
+	go func() {
+		acquired <- cl.acquire(context.Background())
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("Expected the connection to wait for a free slot")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cl.release()
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the connection to acquire the released slot")
+	}
+
+	// Waiting connections should give up once their context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := cl.acquire(ctx); err != context.Canceled {
+		t.Errorf("Expected the cancelled connection to fail but got %v", err)
+	}
+
+	// Connections are not limited without slots
+	unlimited := &connectionLimiter{}
+	for i := 0; i < 10; i++ {
+		if err := unlimited.acquire(context.Background()); err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+	}
+	if active := unlimited.active.Load(); active != 10 {
+		t.Errorf("Expected 10 active connections but got %d", active)
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2022, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -6,6 +6,7 @@ package mgmapi
 
 import (
 	"bufio"
+	"context"
 	"net"
 	"testing"
 )
@@ -30,6 +31,7 @@ func newFakeMgmServerAndClient(t *testing.T) (*fakeMgmServer, *mgmClientImpl) {
 			t:          t,
 		}, &mgmClientImpl{
 			connection: client,
+			ctx:        context.Background(),
 		}
 }
 
@@ -7,6 +7,7 @@ package mgmapi
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -26,6 +27,8 @@ type MgmClient interface {
 	StopNodes(nodeIds []int) error
 	TryReserveNodeId(nodeId int, nodeType NodeTypeEnum) (int, error)
 	CreateNodeGroup(nodeIds []int) (int, error)
+	ReloadConfig() error
+	StartBackup(encryptionPassword string) (int, error)
 
 	GetConfigVersion(nodeID ...int) (uint32, error)
 	GetDataMemory(dataNodeId int) (uint64, error)
@@ -50,12 +53,39 @@ type MgmClient interface {
 // MySQL Cluster nodes via wire protocol.
 type mgmClientImpl struct {
 	connection net.Conn
+	// limiter is the connectionLimiter from which a connection
+	// slot was acquired by NewMgmClient, if any. It is released
+	// when the client is disconnected.
+	limiter *connectionLimiter
+	// ctx bounds the lifetime of the client. The commands fail
+	// once its deadline expires or once it is cancelled.
+	ctx context.Context
+	// stopWatching stops the goroutine that interrupts
+	// the pending commands when the ctx is cancelled
+	stopWatching chan struct{}
 }
 
 // NewMgmClient returns a new mgmClientImpl connected to MySQL Cluster
 func NewMgmClient(connectstring string, desiredNodeId ...int) (*mgmClientImpl, error) {
+	return NewMgmClientWithContext(context.Background(), connectstring, desiredNodeId...)
+}
+
+// NewMgmClientWithContext returns a new mgmClientImpl connected to MySQL
+// Cluster, whose operations are bound by the given context. The connection
+// attempt and the commands fail once the context's deadline expires or once
+// it is cancelled, so that an unresponsive Management Server cannot block
+// the caller beyond the context's lifetime.
+func NewMgmClientWithContext(
+	ctx context.Context, connectstring string, desiredNodeId ...int) (*mgmClientImpl, error) {
+
+	// Wait for a free slot if the connections are limited
+	limiter := connections
+	if err := limiter.acquire(ctx); err != nil {
+		klog.Errorf("Error connecting management server : %s", err)
+		return nil, err
+	}
 
-	client := &mgmClientImpl{}
+	client := &mgmClientImpl{ctx: ctx}
 	var err error
 	switch len(desiredNodeId) {
 	case 0:
@@ -69,12 +99,58 @@ func NewMgmClient(connectstring string, desiredNodeId ...int) (*mgmClientImpl, e
 	}
 
 	if err != nil {
+		limiter.release()
 		klog.Errorf("Error connecting management server : %s", err)
 		return nil, err
 	}
+	client.limiter = limiter
+	client.watchContext()
 	return client, nil
 }
 
+// watchContext interrupts any pending read or write
+// on the connection when the client's ctx is cancelled.
+func (mci *mgmClientImpl) watchContext() {
+	if mci.ctx.Done() == nil {
+		// The context can never be cancelled
+		return
+	}
+
+	mci.stopWatching = make(chan struct{})
+	go func(connection net.Conn, stopWatching <-chan struct{}) {
+		select {
+		case <-mci.ctx.Done():
+			_ = connection.SetDeadline(time.Now())
+		case <-stopWatching:
+		}
+	}(mci.connection, mci.stopWatching)
+}
+
+// getDeadline returns the deadline for an operation that is allowed to
+// take the given timeout, bounded by the deadline of the client's ctx.
+func (mci *mgmClientImpl) getDeadline(timeout time.Duration) time.Time {
+	if mci.ctx.Err() != nil {
+		// Fail the operation immediately
+		return time.Now()
+	}
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := mci.ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		return ctxDeadline
+	}
+	return deadline
+}
+
+// dialContext opens the connections to the Management Servers
+var dialContext = (&net.Dialer{}).DialContext
+
+// SetDialer makes the clients open the connections to the Management
+// Servers using the given dial function. It should be called before
+// any client is created.
+func SetDialer(dial func(ctx context.Context, network, address string) (net.Conn, error)) {
+	dialContext = dial
+}
+
 // connect creates a tcp connection to the mgmd server
 // Note : always use NewMgmClient to create a client rather
 // than directly using mgmClientImpl and connect
@@ -96,7 +172,7 @@ func (mci *mgmClientImpl) connect(connectstring string) error {
 			continue
 		}
 
-		mci.connection, err = net.Dial("tcp", host)
+		mci.connection, err = dialContext(mci.ctx, "tcp", host)
 		if err != nil {
 			klog.V(4).Infof("Failed to connect to Management Node at %q : %s", host, err)
 			// Try the next host in the connectstring
@@ -121,6 +197,11 @@ func (mci *mgmClientImpl) connectToNodeId(connectstring string, desiredNodeId in
 	// load balancer URL and check the id of the connected node. If it is not the
 	// desired node id, retry.
 	for retries := 0; retries < 10; retries++ {
+		if err := mci.ctx.Err(); err != nil {
+			// The caller is no longer waiting for the connection
+			return err
+		}
+
 		err := mci.connect(connectstring)
 		if err != nil {
 			if _, ok := err.(*net.DNSError); ok {
@@ -162,10 +243,19 @@ func (mci *mgmClientImpl) connectToNodeId(connectstring string, desiredNodeId in
 
 // Disconnect closes the tcp connection to the mgmd server
 func (mci *mgmClientImpl) Disconnect() {
+	if mci.stopWatching != nil {
+		close(mci.stopWatching)
+		mci.stopWatching = nil
+	}
 	if mci.connection != nil {
 		_ = mci.connection.Close()
 		klog.V(4).Infof("Management server disconnected.")
 	}
+	if mci.limiter != nil {
+		// Free the connection slot
+		mci.limiter.release()
+		mci.limiter = nil
+	}
 }
 
 const (
@@ -202,7 +292,7 @@ func (mci *mgmClientImpl) executeCommand(
 	fmt.Fprint(&cmdWithArgs, "\n")
 
 	// Set write deadline
-	err := mci.connection.SetWriteDeadline(time.Now().Add(defaultReadWriteTimeout))
+	err := mci.connection.SetWriteDeadline(mci.getDeadline(defaultReadWriteTimeout))
 	if err != nil {
 		klog.Error("SetWriteDeadline failed : ", err)
 		return nil, err
@@ -219,7 +309,7 @@ func (mci *mgmClientImpl) executeCommand(
 	if slowCommand {
 		replyReadTimeout = delayedReplyTimeout
 	}
-	err = mci.connection.SetReadDeadline(time.Now().Add(replyReadTimeout))
+	err = mci.connection.SetReadDeadline(mci.getDeadline(replyReadTimeout))
 	if err != nil {
 		klog.Error("SetReadDeadline failed : ", err)
 		return nil, err
@@ -444,6 +534,7 @@ func (mci *mgmClientImpl) GetStatus() (ClusterStatus, error) {
 			// arbitrary looping order of the 'reply' map.
 			statusKey := fmt.Sprintf("node.%d.status", nodeId)
 			statusValue := reply[statusKey]
+			ns.Status = statusValue
 			// for data node, STARTED => connected
 			// for mgm/api nodes, CONNECTED => connected
 			if (ns.IsDataNode() && statusValue == "STARTED") ||
@@ -611,6 +702,75 @@ func (mci *mgmClientImpl) CreateNodeGroup(nodeIds []int) (int, error) {
 	return ng, nil
 }
 
+// ReloadConfig sends a command to the Management Server to reload the
+// config from the config file it was started with. The new config is
+// distributed to all the Management Servers of the MySQL Cluster, and
+// the other nodes will pick it up when they are restarted next.
+func (mci *mgmClientImpl) ReloadConfig() error {
+
+	// command :
+	// reload config
+
+	// reply :
+	// reload config reply
+	// result: Ok
+
+	// send the command and read the reply
+	_, err := mci.executeCommand(
+		"reload config", nil, true,
+		[]string{"reload config reply", "result"})
+	return err
+}
+
+// StartBackup sends a command to the Management Server to start an NDB
+// native backup of the MySQL Cluster, and waits for the backup to complete.
+// The backup is stored by the data nodes in their BackupDataDir, and is
+// encrypted with the given password if it is not empty. It returns the id
+// of the completed backup on success and an error on failure.
+func (mci *mgmClientImpl) StartBackup(encryptionPassword string) (int, error) {
+
+	// command :
+	// start backup
+	// completed: 2
+	// encryption_password: <password>
+	// password_length: <length of the password>
+
+	// reply :
+	// start backup reply
+	// result: Ok
+	// id: <backup id>
+
+	// build args
+	args := map[string]interface{}{
+		// wait for the backup to complete
+		"completed": 2,
+	}
+
+	if encryptionPassword != "" {
+		if strings.ContainsAny(encryptionPassword, "\r\n") {
+			// The password would break the command
+			return 0, errors.New("backup encryption password cannot contain line breaks")
+		}
+		args["encryption_password"] = encryptionPassword
+		args["password_length"] = len(encryptionPassword)
+	}
+
+	// send the command and read the reply
+	reply, err := mci.executeCommand(
+		"start backup", args, true,
+		[]string{"start backup reply", "result", "id"})
+	if err != nil {
+		return 0, err
+	}
+
+	backupId, err := strconv.Atoi(reply["id"])
+	if err != nil {
+		return 0, debug.InternalError("id in start backup reply has unexpected format : " + err.Error())
+	}
+
+	return backupId, nil
+}
+
 // getConfig extracts the value of the config variable 'configKey'
 // from the MySQL Cluster node with node id 'nodeId'. The config
 // is either retrieved from the config stored in connected
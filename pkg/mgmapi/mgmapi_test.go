@@ -1,15 +1,19 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
 package mgmapi
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 const connectstring = "127.0.0.1:1186"
@@ -18,7 +22,7 @@ func getConnectionToMgmd(t *testing.T) *mgmClientImpl {
 	t.Helper()
 
 	// create a mgm client and connect to mgmd
-	mgmClient := &mgmClientImpl{}
+	mgmClient := &mgmClientImpl{ctx: context.Background()}
 	err := mgmClient.connect(connectstring)
 	if err != nil {
 		if strings.Contains(err.Error(), "connection refused") {
@@ -607,3 +611,162 @@ func TestMgmClientImpl_TryReserveNodeId(t *testing.T) {
 		t.Errorf("TryReserveNodeId returned an unexpected error : %s", err.Error())
 	}
 }
+
+// TestMgmClientImpl_ReloadConfig tests the reload
+// config reply handling with a fake server.
+func TestMgmClientImpl_ReloadConfig(t *testing.T) {
+	for _, tc := range []struct {
+		reply         string
+		expectedError string
+	}{
+		{"reload config reply\nresult: Ok", ""},
+		{"reload config reply\nresult: Config not changed", "Config not changed"},
+	} {
+		mgmServer, mci := newFakeMgmServerAndClient(t)
+		mgmServer.run([]byte(tc.reply))
+
+		err := mci.ReloadConfig()
+		if tc.expectedError == "" && err != nil {
+			t.Errorf("ReloadConfig failed : %s", err)
+		} else if tc.expectedError != "" && (err == nil || !strings.Contains(err.Error(), tc.expectedError)) {
+			t.Errorf("Expected ReloadConfig to fail with %q but got : %v", tc.expectedError, err)
+		}
+
+		mci.Disconnect()
+		mgmServer.disconnect()
+	}
+}
+
+// TestMgmClientImpl_StartBackup tests the start
+// backup command using a fake management server
+func TestMgmClientImpl_StartBackup(t *testing.T) {
+	for _, tc := range []struct {
+		reply            string
+		expectedBackupId int
+		expectedError    string
+	}{
+		{"start backup reply\nresult: Ok\nid: 3", 3, ""},
+		{"start backup reply\nresult: Backup failed: file already exists", 0, "Backup failed"},
+	} {
+		mgmServer, mci := newFakeMgmServerAndClient(t)
+		mgmServer.run([]byte(tc.reply))
+
+		backupId, err := mci.StartBackup("")
+		if tc.expectedError == "" && err != nil {
+			t.Errorf("StartBackup failed : %s", err)
+		} else if tc.expectedError != "" && (err == nil || !strings.Contains(err.Error(), tc.expectedError)) {
+			t.Errorf("Expected StartBackup to fail with %q but got : %v", tc.expectedError, err)
+		} else if backupId != tc.expectedBackupId {
+			t.Errorf("Expected backup id %d but got %d", tc.expectedBackupId, backupId)
+		}
+
+		mci.Disconnect()
+		mgmServer.disconnect()
+	}
+}
+
+// TestMgmClientImpl_StartBackupEncrypted verifies that the encryption
+// password is sent along with the start backup command
+func TestMgmClientImpl_StartBackupEncrypted(t *testing.T) {
+	mgmServer, mci := newFakeMgmServerAndClient(t)
+	defer mci.Disconnect()
+	defer mgmServer.disconnect()
+	server := mgmServer.connection
+
+	commandLines := make(chan []string, 1)
+	go func() {
+		// Read the command and reply to it
+		var lines []string
+		scanner := bufio.NewScanner(server)
+		for scanner.Scan() && scanner.Text() != "" {
+			lines = append(lines, scanner.Text())
+		}
+		commandLines <- lines
+		_, _ = server.Write([]byte("start backup reply\nresult: Ok\nid: 4\n\n"))
+	}()
+
+	backupId, err := mci.StartBackup("secret pass")
+	if err != nil || backupId != 4 {
+		t.Fatalf("Unexpected result : backupId=%d, err=%v", backupId, err)
+	}
+
+	lines := <-commandLines
+	sort.Strings(lines[1:])
+	expectedLines := []string{"start backup", "completed: 2", "encryption_password: secret pass", "password_length: 11"}
+	if !reflect.DeepEqual(lines, expectedLines) {
+		t.Errorf("Expected command %q but got %q", expectedLines, lines)
+	}
+
+	// A password with a line break is rejected without sending the command
+	if _, err = mci.StartBackup("secret\npass"); err == nil {
+		t.Error("Expected StartBackup to fail with a password with a line break")
+	}
+}
+
+// TestMgmClientImpl_context verifies that the commands sent to an
+// unresponsive management server fail once the client's context
+// expires or is cancelled, rather than waiting for the timeouts.
+func TestMgmClientImpl_context(t *testing.T) {
+	deadlineCtx, cancelDeadlineCtx := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelDeadlineCtx()
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	for desc, ctx := range map[string]context.Context{
+		"deadline":     deadlineCtx,
+		"cancellation": cancelledCtx,
+	} {
+		// The fake server doesn't read the commands or send any reply
+		mgmServer, mci := newFakeMgmServerAndClient(t)
+		mci.ctx = ctx
+		mci.watchContext()
+
+		start := time.Now()
+		if _, err := mci.GetStatus(); err == nil {
+			t.Errorf("%s : expected GetStatus to fail", desc)
+		}
+		if elapsed := time.Since(start); elapsed >= defaultReadWriteTimeout {
+			t.Errorf("%s : GetStatus failed only after %s", desc, elapsed)
+		}
+
+		mci.Disconnect()
+		mgmServer.disconnect()
+	}
+}
+
+// TestMgmClientImpl_listenNodeEvents tests the parsing of the
+// node events sent by a fake management server to a listener
+func TestMgmClientImpl_listenNodeEvents(t *testing.T) {
+	mgmServer, mci := newFakeMgmServerAndClient(t)
+	defer mgmServer.disconnect()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mci.ctx = ctx
+	mci.watchContext()
+	defer mci.Disconnect()
+
+	mgmServer.run(
+		[]byte("listen event\nresult: 0\n\n"),
+		[]byte("<PING>\nlog event reply\ntype=1\ntime=1683713533\nsource_nodeid=2\nnode=3\n\n<PING>\n"),
+		[]byte("log event reply\ntype=30\ntime=1683713534\nsource_nodeid=4\ncode=5\narbit_node=1\n"))
+
+	// Stop listening once both the events have been received
+	var events []NodeEvent
+	handler := func(event NodeEvent) {
+		events = append(events, event)
+		if len(events) == 2 {
+			cancel()
+		}
+	}
+	if err := mci.listenNodeEvents(handler); err != nil {
+		t.Fatalf("listenNodeEvents failed : %s", err)
+	}
+
+	expectedEvents := []NodeEvent{
+		{Type: 1, SourceNodeId: 2, Details: map[string]string{"time": "1683713533", "node": "3"}},
+		{Type: 30, SourceNodeId: 4, Details: map[string]string{"time": "1683713534", "code": "5", "arbit_node": "1"}},
+	}
+	if !reflect.DeepEqual(events, expectedEvents) {
+		t.Errorf("Expected the events %v but got %v", expectedEvents, events)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mgmapi
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	klog "k8s.io/klog/v2"
+)
+
+// nodeEventsFilter subscribes to the events of the node restart category
+// (254), which has the node failures and the arbitration results, and of
+// the connection category (255), which has the connects and disconnects of
+// the nodes, up to the log level 8. The events of the other categories and
+// the more verbose events, like the fragment copies of a node restart, are
+// not reported.
+const nodeEventsFilter = "254=8 255=8"
+
+// nodeEventHeader starts every event sent by the Management Server
+const nodeEventHeader = "log event reply"
+
+// nodeEventPing is sent by the Management Server to check that the
+// listeners are still connected. It does not have any event.
+const nodeEventPing = "<PING>"
+
+// NodeEvent is an event of the MySQL Cluster nodes
+// reported by the Management Server
+type NodeEvent struct {
+	// Type is the Ndb_logevent_type of the event
+	Type int
+	// SourceNodeId is the id of the node that reported the event
+	SourceNodeId int
+	// Details has the other parameters of the
+	// event, like the 'node', keyed by their names
+	Details map[string]string
+}
+
+// ListenNodeEvents connects to the Management Server and subscribes to the
+// events about the node failures, the arbitrations and the connections of
+// the MySQL Cluster nodes. The given handler is called for every event
+// until the connection is closed or until the ctx is cancelled. The
+// connection is long-lived and so, unlike the NewMgmClient connections, it
+// is not limited by the SetMaxConnections.
+func ListenNodeEvents(ctx context.Context, connectstring string, handler func(NodeEvent)) error {
+	mci := &mgmClientImpl{ctx: ctx}
+	if err := mci.connect(connectstring); err != nil {
+		return err
+	}
+	mci.watchContext()
+	defer mci.Disconnect()
+
+	return mci.listenNodeEvents(handler)
+}
+
+// listenNodeEvents subscribes to the node events and calls the handler for
+// every event until the connection is closed or until the ctx is cancelled
+func (mci *mgmClientImpl) listenNodeEvents(handler func(NodeEvent)) error {
+
+	// command :
+	// listen event
+	// parsable: 1
+	// filter: 254=8 255=8
+
+	// reply :
+	// listen event
+	// result: 0
+	//
+	// followed by the events, separated by the pings :
+	// log event reply
+	// type=1
+	// time=1683713533
+	// source_nodeid=2
+	// node=3
+	//
+	// <PING>
+
+	// Send the command
+	if err := mci.connection.SetWriteDeadline(mci.getDeadline(defaultReadWriteTimeout)); err != nil {
+		return err
+	}
+	command := fmt.Sprintf("listen event\nparsable: 1\nfilter: %s\n\n", nodeEventsFilter)
+	if _, err := mci.connection.Write([]byte(command)); err != nil {
+		klog.Error("failed to send command to connected management server :", err)
+		return err
+	}
+
+	// Read the reply. The events are read from the same
+	// scanner as it might have buffered some of them already.
+	if err := mci.connection.SetReadDeadline(mci.getDeadline(defaultReadWriteTimeout)); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(mci.connection)
+	if !scanner.Scan() || scanner.Text() != "listen event" {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		return errors.New("unexpected reply to the listen event command")
+	}
+	reply := make(map[string]string)
+	for scanner.Scan() && scanner.Text() != "" {
+		key, value, _ := strings.Cut(scanner.Text(), ":")
+		reply[key] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if reply["result"] != "0" {
+		return fmt.Errorf("failed to listen to the node events : %s", reply["msg"])
+	}
+
+	// Read the events until the connection is closed. The read
+	// deadline is cleared as the events might be sent rarely.
+	if err := mci.connection.SetReadDeadline(time.Time{}); err != nil {
+		return err
+	}
+	if mci.ctx.Err() != nil {
+		// The listener was stopped before the deadline was cleared
+		return nil
+	}
+	var event *NodeEvent
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == nodeEventPing:
+			continue
+		case line == nodeEventHeader:
+			event = &NodeEvent{Details: make(map[string]string)}
+		case line == "":
+			// End of the event
+			if event != nil {
+				handler(*event)
+				event = nil
+			}
+		case event != nil:
+			key, value, found := strings.Cut(line, "=")
+			if !found {
+				klog.Errorf("node event has unexpected format : %s", line)
+				continue
+			}
+			switch key {
+			case "type":
+				event.Type, _ = strconv.Atoi(value)
+			case "source_nodeid":
+				event.SourceNodeId, _ = strconv.Atoi(value)
+			default:
+				event.Details[key] = value
+			}
+		}
+	}
+
+	if mci.ctx.Err() != nil {
+		// The listener was stopped
+		return nil
+	}
+	return scanner.Err()
+}
@@ -5,11 +5,15 @@
 package mysqlclient
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"net"
+	"strconv"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	appsv1 "k8s.io/api/apps/v1"
 	klog "k8s.io/klog/v2"
 )
@@ -27,11 +31,47 @@ const (
 	DbInformationSchema = "information_schema"
 )
 
-// Connect to the MySQL Server at given mysqldHost
-func Connect(mysqldHost string, dbName string, ndbOperatorPassword string) (*sql.DB, error) {
+// MySQL Server error codes returned when the
+// credentials of a user are rejected
+const (
+	errAccessDenied      = 1045
+	errHostNotPrivileged = 1130
+)
+
+// connectTimeout is the maximum time allowed
+// to open a connection to a MySQL Server
+const connectTimeout = 10 * time.Second
+
+// maxOpenConnsPerServer is the maximum number of connections
+// opened by a connection pool to a single MySQL Server
+var maxOpenConnsPerServer = 10
+
+// SetMaxOpenConnsPerServer limits the number of connections opened by
+// every connection pool to a MySQL Server to maxOpenConns. As the pools
+// are cached per MySQL Server, this bounds the connections held open to
+// the MySQL Servers of all the NdbClusters. It should be called before
+// any connection is opened.
+func SetMaxOpenConnsPerServer(maxOpenConns int) {
+	maxOpenConnsPerServer = maxOpenConns
+}
+
+// SetDialer makes the connection pools open the connections to the
+// MySQL Servers using the given dial function. It should be called
+// before any connection is opened.
+func SetDialer(dial func(ctx context.Context, network, address string) (net.Conn, error)) {
+	mysql.RegisterDialContext("tcp", func(ctx context.Context, addr string) (net.Conn, error) {
+		return dial(ctx, "tcp", addr)
+	})
+}
+
+// connect opens a connection to the MySQL Server at given mysqldHost and port.
+// The connection attempt is aborted if the context is cancelled or
+// if the MySQL Server doesn't respond within the connectTimeout.
+func connect(ctx context.Context,
+	mysqldHost string, port int, dbName string, ndbOperatorPassword string) (*sql.DB, error) {
 	// Generate the complete address to connect to
-	dataSource := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?timeout=10s",
-		ndbOperatorUser, ndbOperatorPassword, mysqldHost, mysqldPort, dbName)
+	dataSource := fmt.Sprintf("%s:%s@tcp(%s)/%s?timeout=%s", ndbOperatorUser, ndbOperatorPassword,
+		net.JoinHostPort(mysqldHost, strconv.Itoa(port)), dbName, connectTimeout)
 	db, err := sql.Open(sqlDriverName, dataSource)
 	if err != nil {
 		klog.Infof("Error opening connection to MySQL server at %q : %s", mysqldHost, err)
@@ -39,30 +79,50 @@ func Connect(mysqldHost string, dbName string, ndbOperatorPassword string) (*sql
 	}
 
 	// Verify the DB is connected
-	if db != nil {
-		err = db.Ping()
-	}
-
-	if db == nil || err != nil {
+	pingCtx, cancel := context.WithTimeout(ctx, connectTimeout)
+	defer cancel()
+	if err = db.PingContext(pingCtx); err != nil {
 		klog.Infof("Error connecting to the MySQL server at %q : %s", mysqldHost, err)
+		_ = db.Close()
 		return nil, err
 	}
 
 	// Recommended settings
 	db.SetConnMaxLifetime(time.Minute * 3)
-	db.SetMaxOpenConns(10)
-	db.SetMaxIdleConns(10)
+	db.SetMaxOpenConns(maxOpenConnsPerServer)
+	db.SetMaxIdleConns(maxOpenConnsPerServer)
 
 	return db, nil
 }
 
-// ConnectToStatefulSet opens a connection to the first MySQL Server pod managed by the given MySQL Server StatefulSet
-func ConnectToStatefulSet(mysqldSfset *appsv1.StatefulSet, dbName string, ndbOperatorPassword string) (*sql.DB, error) {
+// IsAccessDeniedError returns true if the given error was returned as the
+// MySQL Server rejected the credentials of the ndb operator user, which
+// happens when the user has been lost or overwritten by a restore.
+func IsAccessDeniedError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) &&
+		(mysqlErr.Number == errAccessDenied || mysqlErr.Number == errHostNotPrivileged)
+}
 
-	// Generate the MySQL Server host using the hostname of StatefulSet's pod-0
-	mysqldHost := fmt.Sprintf("%s-0.%s.%s",
-		mysqldSfset.Name, mysqldSfset.Spec.ServiceName, mysqldSfset.Namespace)
+// Connect to the MySQL Server at given mysqldHost and port.
+// The connection attempt is aborted if the context is cancelled.
+func Connect(ctx context.Context,
+	mysqldHost string, port int, dbName string, ndbOperatorPassword string) (*sql.DB, error) {
+	return connect(ctx, mysqldHost, port, dbName, ndbOperatorPassword)
+}
 
-	return Connect(mysqldHost, dbName, ndbOperatorPassword)
+// ConnectToStatefulSet returns a connection to the first MySQL Server pod managed by the given MySQL Server
+// StatefulSet. The connection is cached and must not be closed by the caller.
+func ConnectToStatefulSet(
+	ctx context.Context, mysqldSfset *appsv1.StatefulSet, dbName string, ndbOperatorPassword string) (*sql.DB, error) {
+	return ConnectToStatefulSetPod(ctx, mysqldSfset, 0, dbName, ndbOperatorPassword)
+}
 
+// ConnectToStatefulSetPod returns a connection to the MySQL Server pod with
+// the given ordinal index managed by the given MySQL Server StatefulSet. The
+// connection is cached until the StatefulSet changes and must not be closed
+// by the caller.
+func ConnectToStatefulSetPod(ctx context.Context,
+	mysqldSfset *appsv1.StatefulSet, ordinal int32, dbName string, ndbOperatorPassword string) (*sql.DB, error) {
+	return connections.get(ctx, mysqldSfset, ordinal, dbName, ndbOperatorPassword)
 }
@@ -0,0 +1,30 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func Test_IsAccessDeniedError(t *testing.T) {
+	for _, tc := range []struct {
+		err      error
+		expected bool
+	}{
+		{err: &mysql.MySQLError{Number: errAccessDenied}, expected: true},
+		{err: fmt.Errorf("wrapped : %w", &mysql.MySQLError{Number: errHostNotPrivileged}), expected: true},
+		{err: &mysql.MySQLError{Number: 1049}, expected: false},
+		{err: errors.New("connection refused"), expected: false},
+		{err: nil, expected: false},
+	} {
+		if IsAccessDeniedError(tc.err) != tc.expected {
+			t.Errorf("IsAccessDeniedError(%v) returned %v, expected %v", tc.err, !tc.expected, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	klog "k8s.io/klog/v2"
+)
+
+// cachedConnection is a connection pool to a MySQL Server pod
+// managed by a MySQL Server StatefulSet.
+type cachedConnection struct {
+	db *sql.DB
+	// sfsetUID and sfsetGeneration identify the version of the
+	// StatefulSet for which the connection was opened. The connection
+	// is reopened when the StatefulSet is recreated or updated, as the
+	// MySQL Server pods are restarted then.
+	sfsetUID        types.UID
+	sfsetGeneration int64
+	// password is the ndb operator password used to open the connection
+	password string
+}
+
+// connectionCache caches the connections to the MySQL Servers so
+// that they are reused across the reconciliation loops of an NdbCluster,
+// instead of opening a new connection to the MySQL Server for every query.
+type connectionCache struct {
+	// connections holds the cached connections keyed by
+	// <namespace>/<StatefulSet name>/<pod ordinal>/<database name>
+	connections map[string]*cachedConnection
+	// acceptedPasswords holds the last ndb operator password accepted by
+	// the MySQL Servers of a StatefulSet, keyed by <namespace>/<StatefulSet name>/.
+	// It is retained when the password changes, so that the operator can
+	// still connect with the previous password to apply the new one.
+	acceptedPasswords map[string]string
+	// mutex protects the connections and the acceptedPasswords maps
+	mutex sync.Mutex
+}
+
+// connections is the cache of the connections to the MySQL
+// Servers of all the NdbClusters managed by the operator.
+var connections = newConnectionCache()
+
+// newConnectionCache creates a new connectionCache
+func newConnectionCache() *connectionCache {
+	return &connectionCache{
+		connections:       make(map[string]*cachedConnection),
+		acceptedPasswords: make(map[string]string),
+	}
+}
+
+// getStatefulSetKeyPrefix returns the prefix of the keys of all the
+// connections to the pods managed by the StatefulSet with the given name
+func getStatefulSetKeyPrefix(namespace, sfsetName string) string {
+	return fmt.Sprintf("%s/%s/", namespace, sfsetName)
+}
+
+// getMySQLServerPort returns the port exposed by the MySQL Server
+// container of the given StatefulSet, or the default port if none.
+func getMySQLServerPort(mysqldSfset *appsv1.StatefulSet) int {
+	for _, container := range mysqldSfset.Spec.Template.Spec.Containers {
+		if len(container.Ports) != 0 {
+			return int(container.Ports[0].ContainerPort)
+		}
+	}
+	return mysqldPort
+}
+
+// get returns a connection to the MySQL Server pod with the given ordinal
+// index managed by the given StatefulSet. A cached connection is returned
+// if one exists for the current version of the StatefulSet, and a new
+// connection is opened and cached otherwise.
+func (cc *connectionCache) get(ctx context.Context, mysqldSfset *appsv1.StatefulSet,
+	ordinal int32, dbName string, ndbOperatorPassword string) (*sql.DB, error) {
+	key := fmt.Sprintf("%s%d/%s",
+		getStatefulSetKeyPrefix(mysqldSfset.Namespace, mysqldSfset.Name), ordinal, dbName)
+
+	cc.mutex.Lock()
+	cachedConn, exists := cc.connections[key]
+	if exists {
+		if cachedConn.sfsetUID == mysqldSfset.UID &&
+			cachedConn.sfsetGeneration == mysqldSfset.Generation &&
+			cachedConn.password == ndbOperatorPassword {
+			// The cached connection is still valid
+			cc.mutex.Unlock()
+			return cachedConn.db, nil
+		}
+
+		// The StatefulSet or the password has changed since
+		// the connection was opened - close the connection.
+		klog.V(2).Infof("Closing the stale connection to %q", key)
+		delete(cc.connections, key)
+		_ = cachedConn.db.Close()
+	}
+	cc.mutex.Unlock()
+
+	// Open a new connection without holding the lock,
+	// so that the other NdbClusters are not blocked.
+	mysqldHost := fmt.Sprintf("%s-%d.%s.%s",
+		mysqldSfset.Name, ordinal, mysqldSfset.Spec.ServiceName, mysqldSfset.Namespace)
+	db, err := connect(ctx, mysqldHost, getMySQLServerPort(mysqldSfset), dbName, ndbOperatorPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+	if existingConn, exists := cc.connections[key]; exists {
+		// Connection was opened by a concurrent caller.
+		// Close it as it is replaced by the new one.
+		_ = existingConn.db.Close()
+	}
+	cc.connections[key] = &cachedConnection{
+		db:              db,
+		sfsetUID:        mysqldSfset.UID,
+		sfsetGeneration: mysqldSfset.Generation,
+		password:        ndbOperatorPassword,
+	}
+	cc.acceptedPasswords[getStatefulSetKeyPrefix(mysqldSfset.Namespace, mysqldSfset.Name)] = ndbOperatorPassword
+
+	return db, nil
+}
+
+// closeAll closes and removes all the cached connections
+// to the pods managed by the StatefulSet with the given name
+func (cc *connectionCache) closeAll(namespace, sfsetName string) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	keyPrefix := getStatefulSetKeyPrefix(namespace, sfsetName)
+	for key, cachedConn := range cc.connections {
+		if strings.HasPrefix(key, keyPrefix) {
+			_ = cachedConn.db.Close()
+			delete(cc.connections, key)
+		}
+	}
+	delete(cc.acceptedPasswords, keyPrefix)
+}
+
+// acceptedPassword returns the last ndb operator password accepted by
+// the MySQL Servers of the StatefulSet with the given namespace and name.
+func (cc *connectionCache) acceptedPassword(namespace, sfsetName string) (password string, exists bool) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	password, exists = cc.acceptedPasswords[getStatefulSetKeyPrefix(namespace, sfsetName)]
+	return password, exists
+}
+
+// GetAcceptedPassword returns the last ndb operator password with which a
+// connection was opened to the MySQL Servers of the given StatefulSet since
+// the operator started. It returns false if no connection was opened yet.
+func GetAcceptedPassword(mysqldSfset *appsv1.StatefulSet) (string, bool) {
+	return connections.acceptedPassword(mysqldSfset.Namespace, mysqldSfset.Name)
+}
+
+// CloseConnections closes all the cached connections to the MySQL
+// Servers managed by the StatefulSet with the given namespace and name.
+func CloseConnections(namespace, mysqldSfsetName string) {
+	connections.closeAll(namespace, mysqldSfsetName)
+}
+
+// openConnections returns the number of connections
+// currently open by all the cached connection pools
+func (cc *connectionCache) openConnections() int {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	openConnections := 0
+	for _, cachedConn := range cc.connections {
+		openConnections += cachedConn.db.Stats().OpenConnections
+	}
+	return openConnections
+}
+
+// GetOpenConnections returns the number of connections currently
+// open to the MySQL Servers of all the NdbClusters.
+func GetOpenConnections() int {
+	return connections.openConnections()
+}
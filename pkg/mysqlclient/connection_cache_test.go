@@ -0,0 +1,108 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_connectionCache(t *testing.T) {
+	cc := newConnectionCache()
+	sfset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-mysqld",
+			Namespace:  "default",
+			UID:        "sfset-uid",
+			Generation: 1,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			ServiceName: "test-mysqld",
+		},
+	}
+
+	// addConnection adds a connection, that is never
+	// used to connect, to the cache for the given key
+	addConnection := func(key string, generation int64) *sql.DB {
+		db, err := sql.Open(sqlDriverName, "user:pass@tcp(127.0.0.1:3306)/")
+		if err != nil {
+			t.Fatalf("Unexpected error : %s", err)
+		}
+		cc.connections[key] = &cachedConnection{
+			db:              db,
+			sfsetUID:        sfset.UID,
+			sfsetGeneration: generation,
+			password:        "pass",
+		}
+		return db
+	}
+
+	cachedDb := addConnection("default/test-mysqld/0/", 1)
+	otherDb := addConnection("default/test-mysqld-2/0/", 1)
+
+	// The cached connection should be returned if the StatefulSet hasn't changed
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if db, err := cc.get(ctx, sfset, 0, "", "pass"); err != nil || db != cachedDb {
+		t.Fatalf("Cached connection not returned, error : %v", err)
+	}
+
+	// The cached connection should be closed once the StatefulSet is updated.
+	// The new connection fails as there is no MySQL Server to connect to.
+	sfset.Generation = 2
+	if _, err := cc.get(ctx, sfset, 0, "", "pass"); err == nil {
+		t.Fatal("Expected the connection to an unreachable MySQL Server to fail")
+	}
+	if _, exists := cc.connections["default/test-mysqld/0/"]; exists {
+		t.Error("Stale connection was not removed from the cache")
+	}
+	if err := cachedDb.Ping(); err == nil || err.Error() != "sql: database is closed" {
+		t.Errorf("Stale connection was not closed, error : %v", err)
+	}
+
+	// Closing the connections of a StatefulSet should not
+	// affect the connections of the other StatefulSets
+	addConnection("default/test-mysqld/1/ndbinfo", 2)
+	cc.closeAll("default", "test-mysqld")
+	if len(cc.connections) != 1 || cc.connections["default/test-mysqld-2/0/"].db != otherDb {
+		t.Errorf("Unexpected connections in the cache after closing the StatefulSet connections : %v", cc.connections)
+	}
+}
+
+func Test_connectionCacheAcceptedPassword(t *testing.T) {
+	cc := newConnectionCache()
+	sfset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mysqld",
+			Namespace: "default",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			ServiceName: "test-mysqld",
+		},
+	}
+	cc.acceptedPasswords["default/test-mysqld/"] = "old-pass"
+
+	// A rejected password should not replace the accepted password.
+	// The connection fails as there is no MySQL Server to connect to.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := cc.get(ctx, sfset, 0, "", "new-pass"); err == nil {
+		t.Fatal("Expected the connection to an unreachable MySQL Server to fail")
+	}
+	if password, exists := cc.acceptedPassword("default", "test-mysqld"); !exists || password != "old-pass" {
+		t.Errorf("Expected the accepted password to be retained but got %q", password)
+	}
+
+	// Closing the connections of the StatefulSet should forget the password
+	cc.closeAll("default", "test-mysqld")
+	if _, exists := cc.acceptedPassword("default", "test-mysqld"); exists {
+		t.Error("Accepted password not removed after closing the StatefulSet connections")
+	}
+}
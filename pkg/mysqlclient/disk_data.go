@@ -0,0 +1,181 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// diskDataFile describes an existing undo log file or data file
+type diskDataFile struct {
+	fileType         string
+	tablespaceName   string
+	logfileGroupName string
+	initialSize      int64
+}
+
+// getDiskDataFiles returns the undo log files and the
+// data files of the MySQL Cluster, keyed by their name.
+func getDiskDataFiles(ctx context.Context, db *sql.DB) (map[string]diskDataFile, error) {
+	query := "SELECT FILE_NAME, FILE_TYPE, IFNULL(TABLESPACE_NAME, ''), IFNULL(LOGFILE_GROUP_NAME, ''), " +
+		"IFNULL(INITIAL_SIZE, 0) FROM " + DbInformationSchema + ".FILES WHERE ENGINE = 'ndbcluster'"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := make(map[string]diskDataFile)
+	for rows.Next() {
+		var fileName string
+		var file diskDataFile
+		if err = rows.Scan(&fileName, &file.fileType,
+			&file.tablespaceName, &file.logfileGroupName, &file.initialSize); err != nil {
+			klog.Errorf("Failed to scan the list of Disk Data files : %s", err)
+			return nil, err
+		}
+		files[fileName] = file
+	}
+
+	return files, rows.Err()
+}
+
+// getDiskDataQueries returns the queries that create the logfile group and
+// the tablespaces specified in the diskDataSpec, and add the files missing
+// from the existing files to them, with the sizes given in the fileSizes.
+func getDiskDataQueries(diskDataSpec *v1.NdbDiskDataSpec,
+	fileSizes map[string]int64, files map[string]diskDataFile) ([]string, error) {
+
+	// objectExists returns true if any of the files belong to the given object
+	objectExists := func(fileType string, belongsTo func(file diskDataFile) bool) bool {
+		for _, file := range files {
+			if file.fileType == fileType && belongsTo(file) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// getFileSize returns the size of the given file to be created
+	getFileSize := func(fileName string) (int64, error) {
+		size := fileSizes[fileName]
+		if size <= 0 {
+			return 0, fmt.Errorf("no storage left in the data node PVCs to create the Disk Data file %q", fileName)
+		}
+		return size, nil
+	}
+
+	var queries []string
+
+	// Create the logfile group and add the undo log files
+	logfileGroup := diskDataSpec.LogfileGroup
+	logfileGroupExists := objectExists("UNDO LOG", func(file diskDataFile) bool {
+		return file.logfileGroupName == logfileGroup.Name
+	})
+	for _, undoFile := range logfileGroup.UndoFiles {
+		if _, exists := files[undoFile.Name]; exists {
+			continue
+		}
+
+		size, err := getFileSize(undoFile.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		var query string
+		if !logfileGroupExists {
+			query = fmt.Sprintf("CREATE LOGFILE GROUP `%s` ADD UNDOFILE '%s' INITIAL_SIZE = %d",
+				logfileGroup.Name, undoFile.Name, size)
+			if logfileGroup.UndoBufferSize != nil {
+				query += fmt.Sprintf(" UNDO_BUFFER_SIZE = %d", logfileGroup.UndoBufferSize.Value())
+			}
+		} else {
+			query = fmt.Sprintf("ALTER LOGFILE GROUP `%s` ADD UNDOFILE '%s' INITIAL_SIZE = %d",
+				logfileGroup.Name, undoFile.Name, size)
+		}
+
+		queries = append(queries, query+" ENGINE NDBCLUSTER")
+		logfileGroupExists = true
+	}
+
+	// Create the tablespaces and add the data files
+	for _, tablespace := range diskDataSpec.Tablespaces {
+		tablespaceExists := objectExists("DATAFILE", func(file diskDataFile) bool {
+			return file.tablespaceName == tablespace.Name
+		})
+		for _, dataFile := range tablespace.DataFiles {
+			if _, exists := files[dataFile.Name]; exists {
+				continue
+			}
+
+			size, err := getFileSize(dataFile.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			var query string
+			if !tablespaceExists {
+				query = fmt.Sprintf("CREATE TABLESPACE `%s` ADD DATAFILE '%s' USE LOGFILE GROUP `%s` INITIAL_SIZE = %d",
+					tablespace.Name, dataFile.Name, logfileGroup.Name, size)
+			} else {
+				query = fmt.Sprintf("ALTER TABLESPACE `%s` ADD DATAFILE '%s' INITIAL_SIZE = %d",
+					tablespace.Name, dataFile.Name, size)
+			}
+
+			queries = append(queries, query+" ENGINE NDBCLUSTER")
+			tablespaceExists = true
+		}
+	}
+
+	return queries, nil
+}
+
+// EnsureDiskDataObjects creates the logfile group and the tablespaces
+// specified in the NdbCluster spec, and adds any missing files to them.
+// The files without a size in the spec are sized automatically, taking
+// into account the sizes of the existing files. It returns true if any
+// of the objects were created or altered.
+func EnsureDiskDataObjects(ctx context.Context, mysqldSfset *appsv1.StatefulSet,
+	nc *v1.NdbCluster, ndbOperatorPassword string) (updated bool, err error) {
+
+	db, err := ConnectToStatefulSet(ctx, mysqldSfset, "", ndbOperatorPassword)
+	if err != nil {
+		return false, err
+	}
+
+	files, err := getDiskDataFiles(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	existingFileSizes := make(map[string]int64, len(files))
+	for fileName, file := range files {
+		existingFileSizes[fileName] = file.initialSize
+	}
+
+	queries, err := getDiskDataQueries(nc.Spec.DataNode.DiskData, nc.GetDiskDataFileSizes(existingFileSizes), files)
+	if err != nil {
+		return false, err
+	}
+
+	for _, query := range queries {
+		klog.Infof("Running '%s'", query)
+		if _, err = db.ExecContext(ctx, query); err != nil {
+			klog.Errorf("Query '%s' failed : %s", query, err)
+			return updated, err
+		}
+		updated = true
+	}
+
+	return updated, nil
+}
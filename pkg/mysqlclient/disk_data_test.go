@@ -0,0 +1,76 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func Test_getDiskDataQueries(t *testing.T) {
+	undoBufferSize := resource.MustParse("64Mi")
+	spec := &v1.NdbDiskDataSpec{
+		LogfileGroup: v1.NdbLogfileGroupSpec{
+			Name:           "lg_1",
+			UndoBufferSize: &undoBufferSize,
+			UndoFiles:      []v1.NdbDiskDataFileSpec{{Name: "undo_1.log"}, {Name: "undo_2.log"}},
+		},
+		Tablespaces: []v1.NdbTablespaceSpec{
+			{Name: "ts_1", DataFiles: []v1.NdbDiskDataFileSpec{{Name: "data_1.dat"}, {Name: "data_2.dat"}}},
+			{Name: "ts_2", DataFiles: []v1.NdbDiskDataFileSpec{{Name: "data_3.dat"}}},
+		},
+	}
+	fileSizes := map[string]int64{
+		"undo_1.log": 1024,
+		"undo_2.log": 2048,
+		"data_1.dat": 4096,
+		"data_2.dat": 8192,
+		"data_3.dat": 16384,
+	}
+
+	// All the objects should be created in a new MySQL Cluster
+	queries, err := getDiskDataQueries(spec, fileSizes, nil)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	expectedQueries := []string{
+		"CREATE LOGFILE GROUP `lg_1` ADD UNDOFILE 'undo_1.log' INITIAL_SIZE = 1024 UNDO_BUFFER_SIZE = 67108864 ENGINE NDBCLUSTER",
+		"ALTER LOGFILE GROUP `lg_1` ADD UNDOFILE 'undo_2.log' INITIAL_SIZE = 2048 ENGINE NDBCLUSTER",
+		"CREATE TABLESPACE `ts_1` ADD DATAFILE 'data_1.dat' USE LOGFILE GROUP `lg_1` INITIAL_SIZE = 4096 ENGINE NDBCLUSTER",
+		"ALTER TABLESPACE `ts_1` ADD DATAFILE 'data_2.dat' INITIAL_SIZE = 8192 ENGINE NDBCLUSTER",
+		"CREATE TABLESPACE `ts_2` ADD DATAFILE 'data_3.dat' USE LOGFILE GROUP `lg_1` INITIAL_SIZE = 16384 ENGINE NDBCLUSTER",
+	}
+	if !reflect.DeepEqual(queries, expectedQueries) {
+		t.Errorf("Expected queries %v but got %v", expectedQueries, queries)
+	}
+
+	// Only the missing files should be added to the existing objects
+	existingFiles := map[string]diskDataFile{
+		"undo_1.log": {fileType: "UNDO LOG", logfileGroupName: "lg_1", initialSize: 1024},
+		"data_1.dat": {fileType: "DATAFILE", tablespaceName: "ts_1", logfileGroupName: "lg_1", initialSize: 4096},
+	}
+	queries, err = getDiskDataQueries(spec, fileSizes, existingFiles)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	expectedQueries = []string{
+		"ALTER LOGFILE GROUP `lg_1` ADD UNDOFILE 'undo_2.log' INITIAL_SIZE = 2048 ENGINE NDBCLUSTER",
+		"ALTER TABLESPACE `ts_1` ADD DATAFILE 'data_2.dat' INITIAL_SIZE = 8192 ENGINE NDBCLUSTER",
+		"CREATE TABLESPACE `ts_2` ADD DATAFILE 'data_3.dat' USE LOGFILE GROUP `lg_1` INITIAL_SIZE = 16384 ENGINE NDBCLUSTER",
+	}
+	if !reflect.DeepEqual(queries, expectedQueries) {
+		t.Errorf("Expected queries %v but got %v", expectedQueries, queries)
+	}
+
+	// A file that could not be sized should not be created
+	fileSizes["data_3.dat"] = 0
+	if _, err = getDiskDataQueries(spec, fileSizes, existingFiles); err == nil {
+		t.Error("Expected an error for a file without storage left")
+	}
+}
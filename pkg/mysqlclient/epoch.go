@@ -0,0 +1,37 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// lastCommitEpochQuery retrieves the latest epoch committed in the
+// MySQL Cluster, as observed by the NDB engine of the MySQL Server
+const lastCommitEpochQuery = "SELECT VARIABLE_VALUE FROM performance_schema.global_status " +
+	"WHERE VARIABLE_NAME = 'Ndb_last_commit_epoch_server'"
+
+// GetLastCommitEpoch returns the latest epoch committed in the MySQL
+// Cluster, as observed by the first MySQL Server pod of the given StatefulSet.
+func GetLastCommitEpoch(ctx context.Context,
+	mysqldSfset *appsv1.StatefulSet, ndbOperatorPassword string) (uint64, error) {
+
+	db, err := ConnectToStatefulSet(ctx, mysqldSfset, "", ndbOperatorPassword)
+	if err != nil {
+		return 0, err
+	}
+
+	var epoch uint64
+	if err = db.QueryRowContext(ctx, lastCommitEpochQuery).Scan(&epoch); err != nil {
+		klog.Errorf("Error executing %s: %s", lastCommitEpochQuery, err)
+		return 0, fmt.Errorf("failed to retrieve the last commit epoch : %s", err)
+	}
+
+	return epoch, nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	appsv1 "k8s.io/api/apps/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// defaultHealthCheckQuery verifies that the MySQL Server is
+// connected to the ready data nodes of the MySQL Cluster
+const defaultHealthCheckQuery = "SELECT 1 FROM performance_schema.global_status " +
+	"WHERE VARIABLE_NAME = 'Ndb_number_of_ready_data_nodes' AND VARIABLE_VALUE > 0"
+
+// ndbEngineHealthCheckQuery verifies that the MySQL Server
+// can reach the NDB engine and see the started data nodes
+const ndbEngineHealthCheckQuery = "SELECT 1 FROM ndbinfo.nodes WHERE status = 'STARTED'"
+
+// HealthCheckError is returned by RunHealthCheck when the MySQL Server
+// was reachable but one of the health check queries failed or returned
+// no rows, i.e. when the MySQL Server is not healthy.
+type HealthCheckError struct {
+	Query  string
+	Reason string
+}
+
+func (hce *HealthCheckError) Error() string {
+	return fmt.Sprintf("health check query %q %s", hce.Query, hce.Reason)
+}
+
+// IsHealthCheckFailure returns true if the given error was returned as the
+// MySQL Server failed the health check, rather than due to a failure in
+// connecting to it or in authenticating the ndb operator user.
+func IsHealthCheckFailure(err error) bool {
+	var healthCheckErr *HealthCheckError
+	return errors.As(err, &healthCheckErr)
+}
+
+// newHealthCheckQueryError returns the error to be returned when the given
+// health check query fails with the given error. Only the errors returned by
+// the MySQL Server for the query are health check failures. The connection
+// errors and the rejected credentials are returned as such.
+func newHealthCheckQueryError(query string, err error) error {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || IsAccessDeniedError(err) {
+		return fmt.Errorf("failed to execute the health check query %q : %w", query, err)
+	}
+
+	return &HealthCheckError{Query: query, Reason: fmt.Sprintf("failed : %s", err)}
+}
+
+// CheckNdbEngineConnectivity verifies that the MySQL Server pod with the
+// given ordinal index can reach the NDB engine via the ndbinfo database.
+func CheckNdbEngineConnectivity(ctx context.Context,
+	mysqldSfset *appsv1.StatefulSet, ordinal int32, ndbOperatorPassword string) error {
+	return RunHealthCheck(ctx, mysqldSfset, ordinal, []string{ndbEngineHealthCheckQuery}, ndbOperatorPassword)
+}
+
+// RunHealthCheck executes the given queries on the MySQL Server pod with the
+// given ordinal index, and returns a HealthCheckError if any of them fails
+// or returns no rows. Any other error implies that the MySQL Server could
+// not be checked. If no queries are given, the MySQL Server is checked to
+// be connected to the ready data nodes of the MySQL Cluster.
+func RunHealthCheck(ctx context.Context, mysqldSfset *appsv1.StatefulSet,
+	ordinal int32, queries []string, ndbOperatorPassword string) error {
+
+	db, err := ConnectToStatefulSetPod(ctx, mysqldSfset, ordinal, "", ndbOperatorPassword)
+	if err != nil {
+		return err
+	}
+
+	if len(queries) == 0 {
+		queries = []string{defaultHealthCheckQuery}
+	}
+
+	for _, query := range queries {
+		rows, err := db.QueryContext(ctx, query)
+		if err != nil {
+			klog.Errorf("Error executing %s: %s", query, err)
+			return newHealthCheckQueryError(query, err)
+		}
+
+		hasRows := rows.Next()
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return newHealthCheckQueryError(query, err)
+		}
+
+		if !hasRows {
+			return &HealthCheckError{Query: query, Reason: "returned no rows"}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func Test_IsHealthCheckFailure(t *testing.T) {
+	query := "SELECT 1"
+	for _, tc := range []struct {
+		desc     string
+		err      error
+		expected bool
+	}{
+		{
+			desc:     "query returned no rows",
+			err:      &HealthCheckError{Query: query, Reason: "returned no rows"},
+			expected: true,
+		},
+		{
+			desc:     "query failed in the MySQL Server",
+			err:      newHealthCheckQueryError(query, &mysql.MySQLError{Number: 1146, Message: "Table doesn't exist"}),
+			expected: true,
+		},
+		{
+			desc:     "wrapped health check error",
+			err:      fmt.Errorf("canary : %w", &HealthCheckError{Query: query, Reason: "returned no rows"}),
+			expected: true,
+		},
+		{
+			desc: "connection lost during the query",
+			err:  newHealthCheckQueryError(query, mysql.ErrInvalidConn),
+		},
+		{
+			desc: "credentials rejected",
+			err:  newHealthCheckQueryError(query, &mysql.MySQLError{Number: errAccessDenied}),
+		},
+		{
+			desc: "connection refused",
+			err:  errors.New("dial tcp 10.0.0.1:3306: connect: connection refused"),
+		},
+	} {
+		if IsHealthCheckFailure(tc.err) != tc.expected {
+			t.Errorf("Testcase %q failed : IsHealthCheckFailure(%v) returned %v, expected %v",
+				tc.desc, tc.err, !tc.expected, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// NdbInfoSnapshot is a condensed snapshot of the health
+// of the data nodes, sampled from the ndbinfo database.
+type NdbInfoSnapshot struct {
+	// DataMemoryUsagePercent is the highest percentage
+	// of the DataMemory used by any of the data nodes
+	DataMemoryUsagePercent int32
+	// RedoLogSpaceUsagePercent is the highest percentage
+	// of the redo log space used by any of the data nodes
+	RedoLogSpaceUsagePercent int32
+	// DisconnectedTransporters is the number of transporters
+	// between the data nodes that are not connected
+	DisconnectedTransporters int32
+	// LockWaits is the number of operations waiting for a row lock
+	LockWaits int32
+}
+
+// GetNdbInfoSnapshot samples the memory usage, the redo log space usage,
+// the transporters and the row locks of the data nodes from the ndbinfo
+// database via the first MySQL Server pod of the given StatefulSet.
+func GetNdbInfoSnapshot(ctx context.Context,
+	mysqldSfset *appsv1.StatefulSet, ndbOperatorPassword string) (*NdbInfoSnapshot, error) {
+
+	db, err := ConnectToStatefulSet(ctx, mysqldSfset, DbNdbInfo, ndbOperatorPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &NdbInfoSnapshot{}
+	for _, q := range []struct {
+		query string
+		value *int32
+	}{
+		{
+			query: "SELECT IFNULL(MAX(used * 100 DIV total), 0) FROM memoryusage " +
+				"WHERE memory_type = 'Data memory' AND total > 0",
+			value: &snapshot.DataMemoryUsagePercent,
+		},
+		{
+			query: "SELECT IFNULL(MAX(used * 100 DIV total), 0) FROM logspaces " +
+				"WHERE log_type = 'REDO' AND total > 0",
+			value: &snapshot.RedoLogSpaceUsagePercent,
+		},
+		{
+			// Count only the transporters between the data nodes, as the ones
+			// to the unused API and Management node slots are never connected.
+			query: "SELECT COUNT(*) FROM transporters WHERE status != 'CONNECTED' AND " +
+				"remote_node_id IN (SELECT node_id FROM config_nodes WHERE node_type = 'NDB')",
+			value: &snapshot.DisconnectedTransporters,
+		},
+		{
+			query: "SELECT COUNT(*) FROM cluster_locks WHERE state = 'W'",
+			value: &snapshot.LockWaits,
+		},
+	} {
+		if err = db.QueryRowContext(ctx, q.query).Scan(q.value); err != nil {
+			klog.Errorf("Error executing %s: %s", q.query, err)
+			return nil, fmt.Errorf("failed to query ndbinfo : %s", err)
+		}
+	}
+
+	return snapshot, nil
+}
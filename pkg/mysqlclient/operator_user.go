@@ -0,0 +1,97 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// getOperatorUserHosts returns the hosts of all the ndb operator user
+// accounts in the MySQL Server, and the host of the account used by the
+// current session.
+func getOperatorUserHosts(ctx context.Context, db *sql.DB) (hosts []string, sessionHost string, err error) {
+	var currentUser string
+	if err = db.QueryRowContext(ctx, "select current_user()").Scan(&currentUser); err != nil {
+		klog.Errorf("Error retrieving the current user : %s", err)
+		return nil, "", err
+	}
+	sessionHost = currentUser[strings.LastIndex(currentUser, "@")+1:]
+
+	rows, err := db.QueryContext(ctx, "select host from mysql.user where user = ?", ndbOperatorUser)
+	if err != nil {
+		klog.Errorf("Error retrieving the hosts of the ndb operator user : %s", err)
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var host string
+		if err = rows.Scan(&host); err != nil {
+			return nil, "", err
+		}
+		hosts = append(hosts, host)
+	}
+
+	return hosts, sessionHost, rows.Err()
+}
+
+// alterOperatorUserPassword sets the given password to the ndb operator user account with the given host
+func alterOperatorUserPassword(ctx context.Context, db *sql.DB, host, password string) error {
+	query := fmt.Sprintf("alter user '%s'@'%s' %s", ndbOperatorUser, host, getIdentifiedClause("", password))
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		klog.Errorf("Error executing alter user for %s with host %s : %s", ndbOperatorUser, host, err)
+		return err
+	}
+	return nil
+}
+
+// UpdateNdbOperatorUserPassword changes the password of the ndb operator user
+// accounts in the MySQL Servers of the given StatefulSet from the oldPassword
+// to the newPassword. The accounts local to every MySQL Server, used by the
+// Data nodes, are updated first. The account used by the operator, which is
+// distributed to all the MySQL Servers via the MySQL Cluster, is updated last
+// via the first MySQL Server, so that a failed attempt can be retried with
+// the oldPassword.
+func UpdateNdbOperatorUserPassword(ctx context.Context,
+	mysqldSfset *appsv1.StatefulSet, oldPassword, newPassword string) error {
+
+	var operatorHost string
+	for ordinal := int32(0); ordinal < *mysqldSfset.Spec.Replicas; ordinal++ {
+		db, err := ConnectToStatefulSetPod(ctx, mysqldSfset, ordinal, DbMySQL, oldPassword)
+		if err != nil {
+			return err
+		}
+
+		hosts, sessionHost, err := getOperatorUserHosts(ctx, db)
+		if err != nil {
+			return err
+		}
+		operatorHost = sessionHost
+
+		for _, host := range hosts {
+			if host == sessionHost {
+				// Distributed account - updated at the end
+				continue
+			}
+			if err = alterOperatorUserPassword(ctx, db, host, newPassword); err != nil {
+				return err
+			}
+		}
+	}
+
+	db, err := ConnectToStatefulSet(ctx, mysqldSfset, DbMySQL, oldPassword)
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("Updating the password of the %s with host %s", ndbOperatorUser, operatorHost)
+	return alterOperatorUserPassword(ctx, db, operatorHost, newPassword)
+}
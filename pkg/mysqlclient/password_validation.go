@@ -0,0 +1,65 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// validatePasswordComponent is the URN of the validate_password component
+const validatePasswordComponent = "file://component_validate_password"
+
+// getPasswordValidationQueries returns the queries that persist
+// the given settings of the validate_password component.
+func getPasswordValidationQueries(spec *v1.NdbMysqldPasswordValidationSpec) []string {
+	return []string{
+		fmt.Sprintf("set persist validate_password.policy = '%s'", spec.Policy),
+		fmt.Sprintf("set persist validate_password.length = %d", spec.Length),
+		fmt.Sprintf("set persist validate_password.mixed_case_count = %d", spec.MixedCaseCount),
+		fmt.Sprintf("set persist validate_password.number_count = %d", spec.NumberCount),
+		fmt.Sprintf("set persist validate_password.special_char_count = %d", spec.SpecialCharCount),
+	}
+}
+
+// ConfigurePasswordValidation installs the validate_password component,
+// if it is not installed already, in the MySQL Server pod with the given
+// ordinal index and persists the given settings of the component.
+func ConfigurePasswordValidation(ctx context.Context, mysqldSfset *appsv1.StatefulSet,
+	ordinal int32, spec *v1.NdbMysqldPasswordValidationSpec, ndbOperatorPassword string) error {
+
+	db, err := ConnectToStatefulSetPod(ctx, mysqldSfset, ordinal, DbMySQL, ndbOperatorPassword)
+	if err != nil {
+		return err
+	}
+
+	var count int
+	query := fmt.Sprintf("select count(*) from component where component_urn = '%s'", validatePasswordComponent)
+	if err = db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		klog.Infof("Error executing %s: %s", query, err.Error())
+		return err
+	}
+
+	queries := getPasswordValidationQueries(spec)
+	if count == 0 {
+		// Install the component before configuring it
+		klog.Infof("Installing the validate_password component in the MySQL Server %s-%d", mysqldSfset.Name, ordinal)
+		queries = append([]string{fmt.Sprintf("install component '%s'", validatePasswordComponent)}, queries...)
+	}
+
+	for _, query = range queries {
+		if _, err = db.ExecContext(ctx, query); err != nil {
+			klog.Infof("Error executing %s: %s", query, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+)
+
+func Test_getPasswordValidationQueries(t *testing.T) {
+	queries := getPasswordValidationQueries(&v1.NdbMysqldPasswordValidationSpec{
+		Policy:           "STRONG",
+		Length:           12,
+		MixedCaseCount:   2,
+		NumberCount:      1,
+		SpecialCharCount: 0,
+	})
+
+	expectedQueries := []string{
+		"set persist validate_password.policy = 'STRONG'",
+		"set persist validate_password.length = 12",
+		"set persist validate_password.mixed_case_count = 2",
+		"set persist validate_password.number_count = 1",
+		"set persist validate_password.special_char_count = 0",
+	}
+	if !reflect.DeepEqual(queries, expectedQueries) {
+		t.Errorf("Expected queries %v but got %v", expectedQueries, queries)
+	}
+}
+
+func Test_getIdentifiedClause(t *testing.T) {
+	if clause := getIdentifiedClause("", "secret"); clause != "identified by 'secret'" {
+		t.Errorf("Unexpected clause for the default plugin : %s", clause)
+	}
+	if clause := getIdentifiedClause("mysql_native_password", "secret"); clause !=
+		"identified with mysql_native_password by 'secret'" {
+		t.Errorf("Unexpected clause for mysql_native_password : %s", clause)
+	}
+}
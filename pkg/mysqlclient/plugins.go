@@ -0,0 +1,90 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// getInstalledNames returns the values returned by the given
+// single column query, converted to lower case if required.
+func getInstalledNames(ctx context.Context, db *sql.DB, query string, toLower bool) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		klog.Infof("Error executing %s: %s", query, err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if toLower {
+			name = strings.ToLower(name)
+		}
+		names[name] = true
+	}
+	return names, rows.Err()
+}
+
+// getPluginInstallQueries returns the queries that install the plugins
+// and the components of the given spec that are not installed already.
+func getPluginInstallQueries(
+	spec *v1.NdbMysqldPluginsSpec, installedPlugins, installedComponents map[string]bool) []string {
+	var queries []string
+	for _, plugin := range spec.Plugins {
+		if !installedPlugins[strings.ToLower(plugin.Name)] {
+			queries = append(queries, fmt.Sprintf("install plugin %s soname '%s'", plugin.Name, plugin.Library))
+		}
+	}
+	for _, component := range spec.Components {
+		if !installedComponents[component] {
+			queries = append(queries, fmt.Sprintf("install component '%s'", component))
+		}
+	}
+	return queries
+}
+
+// InstallPlugins installs the plugins and the components specified
+// in the given spec, that are not installed already, in the MySQL
+// Server pod with the given ordinal index.
+func InstallPlugins(ctx context.Context, mysqldSfset *appsv1.StatefulSet,
+	ordinal int32, spec *v1.NdbMysqldPluginsSpec, ndbOperatorPassword string) error {
+
+	db, err := ConnectToStatefulSetPod(ctx, mysqldSfset, ordinal, DbMySQL, ndbOperatorPassword)
+	if err != nil {
+		return err
+	}
+
+	installedPlugins, err := getInstalledNames(ctx, db, "select plugin_name from information_schema.plugins", true)
+	if err != nil {
+		return err
+	}
+	installedComponents, err := getInstalledNames(ctx, db, "select component_urn from component", false)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range getPluginInstallQueries(spec, installedPlugins, installedComponents) {
+		klog.Infof("Executing '%s' in the MySQL Server %s-%d", query, mysqldSfset.Name, ordinal)
+		if _, err = db.ExecContext(ctx, query); err != nil {
+			klog.Infof("Error executing %s: %s", query, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+)
+
+func Test_getPluginInstallQueries(t *testing.T) {
+	spec := &v1.NdbMysqldPluginsSpec{
+		Plugins: []v1.NdbMysqldPlugin{
+			{Name: "audit_log", Library: "audit_log.so"},
+			{Name: "CONNECTION_CONTROL", Library: "connection_control.so"},
+		},
+		Components: []string{
+			"file://component_validate_password",
+			"file://component_audit_api_message_emit",
+		},
+	}
+
+	// Only the plugins and components not installed already should be installed
+	queries := getPluginInstallQueries(spec,
+		map[string]bool{"connection_control": true},
+		map[string]bool{"file://component_validate_password": true})
+
+	expectedQueries := []string{
+		"install plugin audit_log soname 'audit_log.so'",
+		"install component 'file://component_audit_api_message_emit'",
+	}
+	if !reflect.DeepEqual(queries, expectedQueries) {
+		t.Errorf("Expected queries %v but got %v", expectedQueries, queries)
+	}
+}
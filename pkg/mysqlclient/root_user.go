@@ -5,6 +5,7 @@
 package mysqlclient
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -14,10 +15,10 @@ import (
 )
 
 // rootUserExists returns true if the root user with given rootHost exists
-func rootUserExists(db *sql.DB, rootHost string) (bool, error) {
+func rootUserExists(ctx context.Context, db *sql.DB, rootHost string) (bool, error) {
 	var count int
 	query := fmt.Sprintf("select count(*) from user where host='%s' and user='root'", rootHost)
-	err := db.QueryRow(query).Scan(&count)
+	err := db.QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
 		klog.Infof("Error executing %s: %s", query, err.Error())
 		return false, err
@@ -26,15 +27,27 @@ func rootUserExists(db *sql.DB, rootHost string) (bool, error) {
 	return count != 0, nil
 }
 
-// CreateRootUserIfNotExist creates root user if it does not exist already
-func CreateRootUserIfNotExist(mysqldSfset *appsv1.StatefulSet, rootHost, rootPassword string, ndbOperatorPassword string) error {
+// getIdentifiedClause returns the IDENTIFIED clause that sets the given
+// password, and the given authentication plugin if it is not empty.
+func getIdentifiedClause(authPlugin, password string) string {
+	if authPlugin == "" {
+		return fmt.Sprintf("identified by '%s'", password)
+	}
+	return fmt.Sprintf("identified with %s by '%s'", authPlugin, password)
+}
 
-	db, err := ConnectToStatefulSet(mysqldSfset, DbMySQL, ndbOperatorPassword)
+// CreateRootUserIfNotExist creates root user if it does not exist already.
+// The user is created with the given authentication plugin, or with the
+// default authentication plugin of the MySQL Server if it is empty.
+func CreateRootUserIfNotExist(ctx context.Context, mysqldSfset *appsv1.StatefulSet,
+	rootHost, rootPassword, authPlugin string, ndbOperatorPassword string) error {
+
+	db, err := ConnectToStatefulSet(ctx, mysqldSfset, DbMySQL, ndbOperatorPassword)
 	if err != nil {
 		return err
 	}
 
-	if exists, err := rootUserExists(db, rootHost); err != nil {
+	if exists, err := rootUserExists(ctx, db, rootHost); err != nil {
 		return err
 	} else if exists {
 		return nil
@@ -43,21 +56,21 @@ func CreateRootUserIfNotExist(mysqldSfset *appsv1.StatefulSet, rootHost, rootPas
 	// The root user with given host does not exist.
 	// So, create user in database
 	klog.Infof("Creating the root user with host = %s", rootHost)
-	query := fmt.Sprintf("create user 'root'@'%s' identified by '%s'", rootHost, rootPassword)
-	_, err = db.Exec(query)
+	query := fmt.Sprintf("create user 'root'@'%s' %s", rootHost, getIdentifiedClause(authPlugin, rootPassword))
+	_, err = db.ExecContext(ctx, query)
 	if err != nil {
-		klog.Infof("Error executing %s: %s", query, err.Error())
+		klog.Infof("Error executing create user for root with host %s: %s", rootHost, err.Error())
 		return err
 	}
 
 	query = fmt.Sprintf("grant all on *.* to 'root'@'%s' with grant option", rootHost)
-	_, err = db.Exec(query)
+	_, err = db.ExecContext(ctx, query)
 	if err != nil {
 		klog.Infof("Error executing %s: %s", query, err.Error())
 		return err
 	}
 
-	_, err = db.Exec("flush privileges")
+	_, err = db.ExecContext(ctx, "flush privileges")
 	if err != nil {
 		klog.Infof("Error executing flush privileges: %s", err.Error())
 		return err
@@ -67,14 +80,14 @@ func CreateRootUserIfNotExist(mysqldSfset *appsv1.StatefulSet, rootHost, rootPas
 }
 
 // DeleteRootUserIfExists deletes the root user from the database.
-func DeleteRootUserIfExists(mysqldSfset *appsv1.StatefulSet, rootHost string, ndbOperatorPassword string) error {
+func DeleteRootUserIfExists(ctx context.Context, mysqldSfset *appsv1.StatefulSet, rootHost string, ndbOperatorPassword string) error {
 
-	db, err := ConnectToStatefulSet(mysqldSfset, DbMySQL, ndbOperatorPassword)
+	db, err := ConnectToStatefulSet(ctx, mysqldSfset, DbMySQL, ndbOperatorPassword)
 	if err != nil {
 		return err
 	}
 
-	if exists, err := rootUserExists(db, rootHost); err != nil {
+	if exists, err := rootUserExists(ctx, db, rootHost); err != nil {
 		return err
 	} else if !exists {
 		// No need to delete
@@ -83,7 +96,7 @@ func DeleteRootUserIfExists(mysqldSfset *appsv1.StatefulSet, rootHost string, nd
 
 	klog.Infof("Deleting the root user with host = %s", rootHost)
 	query := fmt.Sprintf("drop user 'root'@'%s'", rootHost)
-	_, err = db.Exec(query)
+	_, err = db.ExecContext(ctx, query)
 	if err != nil {
 		klog.Infof("Error executing %s: %s", query, err.Error())
 		return err
@@ -93,24 +106,65 @@ func DeleteRootUserIfExists(mysqldSfset *appsv1.StatefulSet, rootHost string, nd
 }
 
 // UpdateRootUser updates the host name of an existing root user in the database.
-func UpdateRootUser(mysqldSfset *appsv1.StatefulSet, oldRootHost, newRootHost string, ndbOperatorPassword string) error {
-	db, err := ConnectToStatefulSet(mysqldSfset, DbMySQL, ndbOperatorPassword)
+func UpdateRootUser(ctx context.Context, mysqldSfset *appsv1.StatefulSet, oldRootHost, newRootHost string, ndbOperatorPassword string) error {
+	db, err := ConnectToStatefulSet(ctx, mysqldSfset, DbMySQL, ndbOperatorPassword)
 	if err != nil {
 		return err
 	}
 
 	klog.Infof("Updating the host name of root user from %s to %s", oldRootHost, newRootHost)
 	query := fmt.Sprintf("rename user 'root'@'%s' to 'root'@'%s'", oldRootHost, newRootHost)
-	_, err = db.Exec(query)
+	_, err = db.ExecContext(ctx, query)
 	if err != nil {
 		klog.Infof("Error executing %s: %s", query, err.Error())
 		return err
 	}
 
-	_, err = db.Exec("flush privileges")
+	_, err = db.ExecContext(ctx, "flush privileges")
 	if err != nil {
 		klog.Infof("Error executing flush privileges: %s", err.Error())
 		return err
 	}
 	return nil
 }
+
+// UpdateRootUserAuthenticationPlugin updates the authentication plugin of an
+// existing root user in the database. The password has to be set again, as
+// the credentials of the user are stored in the format of the plugin.
+func UpdateRootUserAuthenticationPlugin(ctx context.Context, mysqldSfset *appsv1.StatefulSet,
+	rootHost, rootPassword, authPlugin string, ndbOperatorPassword string) error {
+	db, err := ConnectToStatefulSet(ctx, mysqldSfset, DbMySQL, ndbOperatorPassword)
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("Updating the authentication plugin of root user with host %s to %s", rootHost, authPlugin)
+	query := fmt.Sprintf("alter user 'root'@'%s' %s", rootHost, getIdentifiedClause(authPlugin, rootPassword))
+	_, err = db.ExecContext(ctx, query)
+	if err != nil {
+		klog.Infof("Error executing alter user for root with host %s: %s", rootHost, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// UpdateRootUserPassword updates the password of an existing root
+// user in the database, retaining its authentication plugin.
+func UpdateRootUserPassword(ctx context.Context, mysqldSfset *appsv1.StatefulSet,
+	rootHost, rootPassword string, ndbOperatorPassword string) error {
+	db, err := ConnectToStatefulSet(ctx, mysqldSfset, DbMySQL, ndbOperatorPassword)
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("Updating the password of root user with host %s", rootHost)
+	query := fmt.Sprintf("alter user 'root'@'%s' %s", rootHost, getIdentifiedClause("", rootPassword))
+	_, err = db.ExecContext(ctx, query)
+	if err != nil {
+		klog.Infof("Error executing alter user for root with host %s: %s", rootHost, err.Error())
+		return err
+	}
+
+	return nil
+}
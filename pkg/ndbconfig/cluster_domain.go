@@ -0,0 +1,63 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package ndbconfig
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/mysql/ndb-operator/config"
+
+	klog "k8s.io/klog/v2"
+)
+
+var (
+	// detectedClusterDomain caches the K8s Cluster domain
+	// once it has been successfully detected from the DNS.
+	detectedClusterDomain     string
+	detectedClusterDomainLock sync.Mutex
+
+	// lookupCNAME is the resolver used to detect the K8s Cluster domain
+	lookupCNAME = net.LookupCNAME
+)
+
+// getK8sClusterDomain returns the DNS domain of the K8s Cluster. The domain
+// specified via the operator's cluster-domain option is returned if it is
+// set. Otherwise, the domain is deduced from the CNAME of the kubernetes
+// server and cached, so that the DNS is looked up only until the first
+// successful detection. An empty string is returned if the lookup fails.
+func getK8sClusterDomain() string {
+	if config.ClusterDomain != "" {
+		return config.ClusterDomain
+	}
+
+	detectedClusterDomainLock.Lock()
+	defer detectedClusterDomainLock.Unlock()
+	if detectedClusterDomain != "" {
+		return detectedClusterDomain
+	}
+
+	// Deduce K8s cluster domain by looking up the kubernetes server's CNAME.
+	k8sCname, err := lookupCNAME("kubernetes.default.svc")
+	if err != nil {
+		klog.Warning("K8s Cluster domain lookup failed :", err.Error())
+		return ""
+	}
+
+	// Found the FQDN of form "kubernetes.default.svc.<k8s-cluster-domain>."
+	// Extract the domain from it.
+	const k8sServicePrefix = "kubernetes.default.svc."
+	k8sClusterDomain := strings.TrimSuffix(strings.TrimPrefix(k8sCname, k8sServicePrefix), ".")
+	if !strings.HasPrefix(k8sCname, k8sServicePrefix) || k8sClusterDomain == "" {
+		// CNAME is not of the expected form
+		klog.Warningf("Unable to extract the K8s Cluster domain from the CNAME %q", k8sCname)
+		return ""
+	}
+
+	klog.Infof("Detected the K8s Cluster domain %q", k8sClusterDomain)
+	detectedClusterDomain = k8sClusterDomain
+	return detectedClusterDomain
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package ndbconfig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mysql/ndb-operator/config"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+)
+
+func Test_getK8sClusterDomain(t *testing.T) {
+	defer func(lookup func(string) (string, error)) {
+		lookupCNAME = lookup
+		detectedClusterDomain = ""
+		config.ClusterDomain = ""
+	}(lookupCNAME)
+
+	var lookups int
+	cname, lookupErr := "", errors.New("no such host")
+	lookupCNAME = func(string) (string, error) {
+		lookups++
+		return cname, lookupErr
+	}
+
+	// Lookup failures are not cached
+	for i := 0; i < 2; i++ {
+		if domain := getK8sClusterDomain(); domain != "" {
+			t.Errorf("Expected no K8s Cluster domain but got %q", domain)
+		}
+	}
+	if lookups != 2 {
+		t.Errorf("Expected 2 lookups but got %d", lookups)
+	}
+
+	// CNAMEs of an unexpected form are ignored
+	cname, lookupErr = "kubernetes.default.", nil
+	if domain := getK8sClusterDomain(); domain != "" {
+		t.Errorf("Expected no K8s Cluster domain but got %q", domain)
+	}
+
+	// A successful detection is cached
+	lookups = 0
+	cname = "kubernetes.default.svc.cluster.local."
+	for i := 0; i < 2; i++ {
+		if domain := getK8sClusterDomain(); domain != "cluster.local" {
+			t.Errorf("Expected the K8s Cluster domain 'cluster.local' but got %q", domain)
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("Expected 1 lookup but got %d", lookups)
+	}
+
+	// The domain specified via the operator option takes precedence
+	config.ClusterDomain = "example.org"
+	if domain := getK8sClusterDomain(); domain != "example.org" {
+		t.Errorf("Expected the K8s Cluster domain 'example.org' but got %q", domain)
+	}
+
+	// The hostnames in the config should use the domain
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	configString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+	if !strings.Contains(configString,
+		"Hostname=example-ndb-mgmd-0.example-ndb-mgmd.default.svc.example.org") {
+		t.Errorf("The generated config string does not use the K8s Cluster domain :\n%s", configString)
+	}
+}
@@ -1,11 +1,17 @@
-// Copyright (c) 2022, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
 package ndbconfig
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mysql/ndb-operator/config"
 	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
 )
 
 // GetNumOfSectionsRequiredForMySQLServers returns the
@@ -13,3 +19,143 @@ import (
 func GetNumOfSectionsRequiredForMySQLServers(nc *v1.NdbCluster) int32 {
 	return nc.GetMySQLServerMaxNodeCount() * nc.GetMySQLServerConnectionPoolSize()
 }
+
+// GetMySQLServerGroupConfigKey returns the config map
+// key of the my.cnf of the given MySQL Server group.
+func GetMySQLServerGroupConfigKey(group string) string {
+	return "my-" + group + ".cnf"
+}
+
+// getMySQLServerGroups returns the MySQL Server groups declared in the
+// NdbCluster spec along with the nodeIds reserved for them, as allocated
+// by the NdbCluster. The [mysqld] sections of every group use a range of
+// successive nodeIds, that is retained across the updates to the spec.
+func getMySQLServerGroups(nc *v1.NdbCluster) []MySQLServerGroup {
+	serverGroupSpecs := nc.GetMySQLServerGroups()
+	if len(serverGroupSpecs) == 0 {
+		return nil
+	}
+
+	serverGroupNodeIds := nc.GetMySQLServerGroupNodeIds()
+	serverGroups := make([]MySQLServerGroup, len(serverGroupSpecs))
+	for i, serverGroupSpec := range serverGroupSpecs {
+		serverGroups[i] = MySQLServerGroup{
+			Name:         serverGroupSpec.Name,
+			NodeCount:    serverGroupSpec.NodeCount,
+			StartNodeId:  serverGroupNodeIds[i].StartNodeId,
+			NumOfNodeIds: serverGroupNodeIds[i].NumOfNodeIds,
+		}
+	}
+
+	return serverGroups
+}
+
+// getNdbAPIApplicationHostnames returns the nodeIds reserved for the
+// NDBAPI applications, mapped to the complete hostnames of the pods
+// of the applications they are reserved for.
+func getNdbAPIApplicationHostnames(nc *v1.NdbCluster) map[int32]string {
+	hostnames := nc.GetNdbAPIApplicationHostnames()
+	if len(hostnames) == 0 {
+		return nil
+	}
+
+	// Use the complete hostnames of form '<pod>.<service>.<namespace>.svc.<k8s-cluster-domain>'
+	// if the K8s Cluster domain is known, like the hostnames of the other nodes.
+	if k8sClusterDomain := getK8sClusterDomain(); k8sClusterDomain != "" {
+		for nodeId, hostname := range hostnames {
+			hostnames[nodeId] = hostname + ".svc." + k8sClusterDomain
+		}
+	}
+
+	return hostnames
+}
+
+// GetMySQLServerGroupsString returns the MySQL Server groups
+// declared in the NdbCluster spec, to be stored in the config map.
+func GetMySQLServerGroupsString(nc *v1.NdbCluster) (string, error) {
+	serverGroups := getMySQLServerGroups(nc)
+	if serverGroups == nil {
+		return "", nil
+	}
+
+	serverGroupsBytes, err := json.Marshal(serverGroups)
+	if err != nil {
+		return "", err
+	}
+
+	return string(serverGroupsBytes), nil
+}
+
+// getDefaultNdbdConfigs returns the configs to be set in the default ndbd
+// section, except the ones set by the operator. These are the configs from
+// spec.dataNode.config and the log levels from spec.dataNode.logLevels.
+func getDefaultNdbdConfigs(nc *v1.NdbCluster) map[string]string {
+	defaultNdbdConfigs := make(map[string]string)
+	for configKey, configValue := range nc.Spec.DataNode.Config {
+		defaultNdbdConfigs[configKey] = configValue.String()
+	}
+	for configKey, level := range nc.GetDataNodeLogLevelConfigs() {
+		defaultNdbdConfigs[configKey] = fmt.Sprint(level)
+	}
+	if separateVolumes := nc.Spec.DataNode.SeparateVolumes; separateVolumes != nil {
+		if separateVolumes.FileSystem != nil {
+			// Retain the data files of the tablespaces in the data directory
+			defaultNdbdConfigs["FileSystemPath"] = constants.DataNodeFileSystemDir
+			defaultNdbdConfigs["FileSystemPathDataFiles"] = constants.DataDir + "/data"
+		}
+		if separateVolumes.Backup != nil {
+			defaultNdbdConfigs["BackupDataDir"] = constants.DataNodeBackupDir
+		}
+		if separateVolumes.UndoFiles != nil {
+			defaultNdbdConfigs["FileSystemPathUndoFiles"] = constants.DataNodeUndoFilesDir
+		}
+	}
+	if backup := nc.Spec.Backup; backup != nil && backup.Compression {
+		defaultNdbdConfigs["CompressedBackup"] = "1"
+	}
+	if threadConfig := nc.Spec.DataNode.ThreadConfig; threadConfig != "" {
+		defaultNdbdConfigs["ThreadConfig"] = threadConfig
+	}
+	if lockPagesInMainMemory := nc.Spec.DataNode.LockPagesInMainMemory; lockPagesInMainMemory != nil {
+		defaultNdbdConfigs["LockPagesInMainMemory"] = fmt.Sprint(*lockPagesInMainMemory)
+	}
+	if numa := nc.Spec.DataNode.Numa; numa != nil {
+		defaultNdbdConfigs["Numa"] = "0"
+		if *numa {
+			defaultNdbdConfigs["Numa"] = "1"
+		}
+	}
+	return defaultNdbdConfigs
+}
+
+// getClusterLogDestination returns the LogDestination to be set
+// in the section of the Management Node with the given nodeId. It
+// returns an empty string if the LogDestination has been specified
+// via the spec.managementNode.config, or if it is not specified in the
+// spec and the operator is not streaming the cluster logs.
+func getClusterLogDestination(nc *v1.NdbCluster, nodeId int) string {
+	mgmdSpec := nc.Spec.ManagementNode
+	if mgmdSpec != nil && mgmdSpec.LogDestination != "" {
+		// LogDestination has been specified in the NdbCluster spec
+		return mgmdSpec.LogDestination
+	}
+
+	if mgmdSpec != nil {
+		for configKey := range mgmdSpec.Config {
+			if strings.EqualFold(configKey, "LogDestination") {
+				// LogDestination will be set in the default mgmd section
+				return ""
+			}
+		}
+	}
+
+	if !config.StreamClusterLog {
+		// Leave the Management Nodes with their default LogDestination
+		return ""
+	}
+
+	// Write the cluster log to the console, i.e. the stdout of the
+	// Management Node pods to be streamed, in addition to the file.
+	return fmt.Sprintf("CONSOLE;FILE:filename=%s/data/ndb_%d_cluster.log,maxsize=1000000,maxfiles=6",
+		constants.DataDir, nodeId)
+}
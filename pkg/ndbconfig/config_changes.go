@@ -0,0 +1,194 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package ndbconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mysql/ndb-operator/config/debug"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig/configparser"
+)
+
+// nodeSectionDescriptions has the descriptions of the config.ini
+// sections that declare the MySQL Cluster nodes, used to report
+// the changes in the number of nodes.
+var nodeSectionDescriptions = []struct {
+	section     string
+	description string
+}{
+	{"ndb_mgmd", "Management nodes"},
+	{"ndbd", "Data nodes"},
+	{"mysqld", "MySQL Server slots"},
+	{"api", "Free API slots"},
+}
+
+// ignoredConfigChanges has the parameters, mapped by their section,
+// that change with every new config and are not worth reporting
+var ignoredConfigChanges = map[string]string{
+	"system": "configgenerationnumber",
+	"header": "configversion",
+}
+
+// getSectionChanges returns the parameters added, changed or removed
+// from the oldSection in the newSection, prefixed with the given name.
+func getSectionChanges(name string, oldSection, newSection configparser.Section) []string {
+	var changes []string
+	for parameter, value := range oldSection {
+		if newValue, exists := newSection[parameter]; !exists {
+			changes = append(changes, fmt.Sprintf("%s %s removed (was %s)", name, parameter, value))
+		} else if newValue != value {
+			changes = append(changes, fmt.Sprintf("%s %s changed from %s to %s", name, parameter, value, newValue))
+		}
+	}
+	for parameter, value := range newSection {
+		if _, exists := oldSection[parameter]; !exists {
+			changes = append(changes, fmt.Sprintf("%s %s added with value %s", name, parameter, value))
+		}
+	}
+	return changes
+}
+
+// getConfigIniChanges returns a human-readable description of the
+// changes from the oldConfig to the newConfig, prefixed with the given
+// name of the config. The sections declaring a node are compared only
+// with the section of the same node, and the sections of the nodes
+// added or removed are not compared, as the node counts are reported
+// separately. Any other section added or removed is compared with an
+// empty section.
+func getConfigIniChanges(prefix string, oldConfig, newConfig configparser.ConfigIni) []string {
+	// mapSections maps the sections of a config by their names and their
+	// NodeIds, if any, and returns them along with the names of the
+	// sections declaring a node. The ignored parameters are dropped.
+	mapSections := func(config configparser.ConfigIni) (map[string]configparser.Section, map[string]bool) {
+		sections := make(map[string]configparser.Section)
+		nodeSections := make(map[string]bool)
+		for sectionName, sectionList := range config {
+			for _, section := range sectionList {
+				name := fmt.Sprintf("%s [%s]", prefix, sectionName)
+				if nodeId, exists := section.GetValue("NodeId"); exists {
+					name = fmt.Sprintf("%s [%s NodeId=%s]", prefix, sectionName, nodeId)
+					nodeSections[name] = true
+				}
+				sectionCopy := make(configparser.Section)
+				for parameter, value := range section {
+					if ignoredConfigChanges[sectionName] != parameter {
+						sectionCopy[parameter] = value
+					}
+				}
+				sections[name] = sectionCopy
+			}
+		}
+		return sections, nodeSections
+	}
+
+	oldSections, oldNodeSections := mapSections(oldConfig)
+	newSections, newNodeSections := mapSections(newConfig)
+	var changes []string
+	for name, oldSection := range oldSections {
+		newSection, exists := newSections[name]
+		if !exists && oldNodeSections[name] {
+			// The node has been removed
+			continue
+		}
+		changes = append(changes, getSectionChanges(name, oldSection, newSection)...)
+	}
+	for name, newSection := range newSections {
+		if _, exists := oldSections[name]; !exists && !newNodeSections[name] {
+			// A new section
+			changes = append(changes, getSectionChanges(name, nil, newSection)...)
+		}
+	}
+
+	sort.Strings(changes)
+	return changes
+}
+
+// GetConfigChanges returns a human-readable summary of the changes made
+// by the newConfigMapData to the MySQL Cluster config (config.ini) and
+// the MySQL Server configs (my.cnf) in the oldConfigMapData. The changes
+// in the number of nodes are reported first, followed by the parameters
+// added, changed or removed.
+func GetConfigChanges(oldConfigMapData, newConfigMapData map[string]string) ([]string, error) {
+	var changes []string
+
+	oldConfig, err := configparser.ParseString(oldConfigMapData[constants.ConfigIniKey])
+	if err != nil {
+		// Should never happen as the operator generated the config.ini
+		return nil, debug.InternalError(err)
+	}
+	newConfig, err := configparser.ParseString(newConfigMapData[constants.ConfigIniKey])
+	if err != nil {
+		return nil, debug.InternalError(err)
+	}
+
+	// Report the changes in the number of nodes
+	for _, nodeSection := range nodeSectionDescriptions {
+		oldCount := oldConfig.GetNumberOfSections(nodeSection.section)
+		newCount := newConfig.GetNumberOfSections(nodeSection.section)
+		if oldCount != newCount {
+			changes = append(changes, fmt.Sprintf("%s changed from %d to %d",
+				nodeSection.description, oldCount, newCount))
+		}
+	}
+	if oldCount, newCount := oldConfigMapData[constants.NumOfMySQLServers],
+		newConfigMapData[constants.NumOfMySQLServers]; oldCount != newCount {
+		changes = append(changes, fmt.Sprintf("MySQL Servers changed from %s to %s", oldCount, newCount))
+	}
+
+	changes = append(changes, getConfigIniChanges("config.ini", oldConfig, newConfig)...)
+
+	// Report the changes to the my.cnf of the MySQL Servers and the MySQL Server groups
+	myCnfKeys := make(map[string]bool)
+	for _, configMapData := range []map[string]string{oldConfigMapData, newConfigMapData} {
+		for key := range configMapData {
+			if key == constants.MySQLConfigKey || strings.HasPrefix(key, "my-") && strings.HasSuffix(key, ".cnf") {
+				myCnfKeys[key] = true
+			}
+		}
+	}
+	var sortedMyCnfKeys []string
+	for key := range myCnfKeys {
+		sortedMyCnfKeys = append(sortedMyCnfKeys, key)
+	}
+	sort.Strings(sortedMyCnfKeys)
+	for _, key := range sortedMyCnfKeys {
+		if oldConfigMapData[key] == newConfigMapData[key] {
+			continue
+		}
+
+		oldMyCnf, err := configparser.ParseString(oldConfigMapData[key])
+		if err != nil {
+			return nil, debug.InternalError(err)
+		}
+		newMyCnf, err := configparser.ParseString(newConfigMapData[key])
+		if err != nil {
+			return nil, debug.InternalError(err)
+		}
+		changes = append(changes, getConfigIniChanges(key, oldMyCnf, newMyCnf)...)
+	}
+
+	return changes, nil
+}
+
+// GetConfigChangesString returns the summary of the changes made
+// to the config, as returned by GetConfigChanges, to be stored
+// in the config map.
+func GetConfigChangesString(oldConfigMapData, newConfigMapData map[string]string) (string, error) {
+	changes, err := GetConfigChanges(oldConfigMapData, newConfigMapData)
+	if err != nil || len(changes) == 0 {
+		return "", err
+	}
+
+	changesBytes, err := json.Marshal(changes)
+	if err != nil {
+		return "", err
+	}
+
+	return string(changesBytes), nil
+}
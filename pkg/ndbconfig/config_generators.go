@@ -6,9 +6,10 @@ package ndbconfig
 
 import (
 	"bytes"
-	"net"
+	"fmt"
 	"text/template"
 
+	corev1 "k8s.io/api/core/v1"
 	klog "k8s.io/klog/v2"
 
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
@@ -35,13 +36,17 @@ Name={{.Name}}
 {{/* update numOfOperatorSetConfigs if a new parameter is added here */ -}}
 NoOfReplicas={{.Spec.RedundancyLevel}}
 # Use a fixed ServerPort for all data nodes
-ServerPort=1186
-{{- range $configKey, $configValue := .Spec.DataNode.Config }}
+ServerPort={{.GetDataNodeServerPort}}
+{{- range $configKey, $configValue := GetDefaultNdbdConfigs }}
 {{$configKey}}={{$configValue}}
 {{- end}}
 
 [tcp default]
 AllowUnresolvedHostnames=1
+{{- if PreferIPv6}}
+# Resolve the hostnames to IPv6 addresses as IPv6 is the primary IP family
+PreferIPVersion=6
+{{- end}}
 
 {{$hostnameSuffix := GetHostnameSuffix -}}
 {{range $idx, $nodeId := GetNodeIds NdbNodeTypeMgmd -}}
@@ -49,17 +54,46 @@ AllowUnresolvedHostnames=1
 NodeId={{$nodeId}}
 Hostname={{$.Name}}-{{NdbNodeTypeMgmd}}-{{$idx}}.{{$.GetServiceName NdbNodeTypeMgmd}}.{{$hostnameSuffix}}
 DataDir={{GetDataDir}}
-
+{{with GetManagementNodePortNumber -}}
+PortNumber={{.}}
+{{end -}}
+{{if $.HasArbitrator -}}
+# Prefer the external arbitrator over this Management node
+ArbitrationRank=2
+{{end -}}
+{{with GetLocationDomainId (printf "%s-%s-%d" $.Name NdbNodeTypeMgmd $idx) -}}
+LocationDomainId={{.}}
+{{end -}}
+{{with GetClusterLogDestination $nodeId -}}
+LogDestination={{.}}
+{{end}}
 {{end -}}
 {{range $idx, $nodeId := GetNodeIds NdbNodeTypeNdbmtd -}}
 [ndbd]
 NodeId={{$nodeId}}
 Hostname={{$.Name}}-{{NdbNodeTypeNdbmtd}}-{{$idx}}.{{$.GetServiceName NdbNodeTypeNdbmtd}}.{{$hostnameSuffix}}
 DataDir={{GetDataDir}}
+{{with GetLocationDomainId (printf "%s-%s-%d" $.Name NdbNodeTypeNdbmtd $idx) -}}
+LocationDomainId={{.}}
+{{end -}}
 {{if IsNewDataNode $nodeId -}}
 NodeGroup=65536
 {{end}}
 {{end -}}
+{{if .HasArbitrator -}}
+# Management node run only to act as the external arbitrator
+[ndb_mgmd]
+NodeId={{ArbitratorNodeId}}
+Hostname={{$.Name}}-{{NdbNodeTypeArbitrator}}-0.{{$.GetServiceName NdbNodeTypeArbitrator}}.{{$hostnameSuffix}}
+DataDir={{GetDataDir}}
+{{with GetManagementNodePortNumber -}}
+PortNumber={{.}}
+{{end -}}
+ArbitrationRank=1
+{{with GetLocationDomainId (printf "%s-%s-0" $.Name NdbNodeTypeArbitrator) -}}
+LocationDomainId={{.}}
+{{end}}
+{{end -}}
 # Dedicated API section to be used by NDB Operator
 [api]
 NodeId={{NdbOperatorDedicatedAPINodeId}}
@@ -70,13 +104,35 @@ Dedicated=1
 [mysqld]
 NodeId={{$nodeId}}
 Hostname={{$.Name}}-{{NdbNodeTypeMySQLD}}-{{$podIdx}}.{{$.GetServiceName NdbNodeTypeMySQLD}}.{{$hostnameSuffix}}
-
+{{with GetLocationDomainId (printf "%s-%s-%d" $.Name NdbNodeTypeMySQLD $podIdx) -}}
+LocationDomainId={{.}}
+{{end}}
 {{end -}}
 # API sections to be used by generic NDBAPI applications
 {{range $nodeId := GetNodeIds NdbNodeTypeAPI -}}
 [api]
 NodeId={{$nodeId}}
 
+{{end -}}
+{{with GetMySQLServerGroupHostnames -}}
+# MySQLD sections to be used exclusively by the MySQL Server groups
+{{range $nodeId, $hostname := . -}}
+[mysqld]
+NodeId={{$nodeId}}
+Hostname={{$hostname}}.{{$hostnameSuffix}}
+{{with GetLocationDomainId $hostname -}}
+LocationDomainId={{.}}
+{{end}}
+{{end -}}
+{{end -}}
+{{with GetNdbAPIApplicationHostnames -}}
+# API sections reserved for the NDBAPI applications
+{{range $nodeId, $hostname := . -}}
+[api]
+NodeId={{$nodeId}}
+Hostname={{$hostname}}
+
+{{end -}}
 {{end -}}
 `
 
@@ -86,6 +142,21 @@ NodeId={{$nodeId}}
 // It is important to note that GetConfigString uses the Spec in its
 // actual and consistent state and does not rely on any Status field.
 func GetConfigString(ndb *v1.NdbCluster, oldConfigSummary *ConfigSummary) (string, error) {
+	var locationDomainZones map[string]string
+	if oldConfigSummary != nil {
+		// Retain the location domains of the existing config
+		locationDomainZones = oldConfigSummary.LocationDomainZones
+	}
+
+	return GetConfigStringWithLocationDomains(ndb, oldConfigSummary, locationDomainZones)
+}
+
+// GetConfigStringWithLocationDomains generates a new configuration for
+// the MySQL Cluster from the given ndb resources Spec, with the nodes
+// assigned to the location domains of the given pod name to zone map.
+// The location domains are ignored if they are not enabled in the Spec.
+func GetConfigStringWithLocationDomains(
+	ndb *v1.NdbCluster, oldConfigSummary *ConfigSummary, locationDomainZones map[string]string) (string, error) {
 
 	var (
 		// Variables that keep track of the first free mgmd/data node id and api nodeId
@@ -102,6 +173,11 @@ func GetConfigString(ndb *v1.NdbCluster, oldConfigSummary *ConfigSummary) (strin
 		newDataNodeStartId = int(ndb.GetManagementNodeCount() + oldConfigSummary.NumOfDataNodes + 1)
 	}
 
+	var locationDomainIds map[string]int32
+	if ndb.Spec.LocationDomainTopologyKey != "" {
+		locationDomainIds = getLocationDomainIds(locationDomainZones)
+	}
+
 	tmpl := template.New("config.ini")
 	tmpl.Funcs(template.FuncMap{
 		// GetNodeIds returns an array of node ids for the given node type
@@ -148,7 +224,47 @@ func GetConfigString(ndb *v1.NdbCluster, oldConfigSummary *ConfigSummary) (strin
 
 			return nodeIdToPodIdx
 		},
+		"GetMySQLServerGroupHostnames": func() map[int32]string {
+			// Map the nodeIds reserved for the MySQL Server groups to the
+			// hostnames of the pods. Like the MySQL Servers, every pod
+			// gets ConnectionPoolSize number of successive nodeIds.
+			nodeIdToHostname := make(map[int32]string)
+			ndbConnectionPoolSize := ndb.GetMySQLServerConnectionPoolSize()
+			for _, serverGroup := range getMySQLServerGroups(ndb) {
+				workloadName := ndb.GetMySQLServerGroupWorkloadName(serverGroup.Name)
+				for podIdx := int32(0); podIdx < serverGroup.NodeCount; podIdx++ {
+					for j := int32(0); j < ndbConnectionPoolSize; j++ {
+						nodeId := serverGroup.StartNodeId + podIdx*ndbConnectionPoolSize + j
+						nodeIdToHostname[nodeId] = fmt.Sprintf("%s-%d.%s", workloadName, podIdx, workloadName)
+					}
+				}
+			}
+
+			return nodeIdToHostname
+		},
+		"GetNdbAPIApplicationHostnames": func() map[int32]string {
+			return getNdbAPIApplicationHostnames(ndb)
+		},
+		"GetLocationDomainId": func(hostname string) int32 {
+			// Returns 0 if the node has not been assigned to any location domain
+			return locationDomainIds[getPodName(hostname)]
+		},
 		"GetDataDir": func() string { return constants.DataDir + "/data" },
+		"GetManagementNodePortNumber": func() int32 {
+			// The PortNumber is set only if it is not the default
+			// to retain the configs of the existing NdbClusters
+			if port := ndb.GetManagementNodePort(); port != constants.ManagementNodePort {
+				return port
+			}
+			return 0
+		},
+		"PreferIPv6": func() bool { return ndb.GetPrimaryIPFamily() == corev1.IPv6Protocol },
+		"GetClusterLogDestination": func(nodeId int) string {
+			return getClusterLogDestination(ndb, nodeId)
+		},
+		"GetDefaultNdbdConfigs": func() map[string]string {
+			return getDefaultNdbdConfigs(ndb)
+		},
 		"IsNewDataNode": func(nodeId int) bool {
 			return newDataNodeStartId != 0 && nodeId >= newDataNodeStartId
 		},
@@ -162,24 +278,22 @@ func GetConfigString(ndb *v1.NdbCluster, oldConfigSummary *ConfigSummary) (strin
 			}
 		},
 		"GetHostnameSuffix": func() string {
-			// If the K8s Cluster domain can be found, generate the hostname suffix of form :
+			// If the K8s Cluster domain is known, generate the hostname suffix of form :
 			// '<namespace>.svc.<k8s-cluster-domain>' or else, simply use the namespace as the suffix.
-			// Deduce K8s cluster domain suffix by looking up the kubernetes server's CNAME.
-			if k8sCname, err := net.LookupCNAME("kubernetes.default.svc"); err != nil {
-				klog.Warning("K8s Cluster domain lookup failed :", err.Error())
+			k8sClusterDomain := getK8sClusterDomain()
+			if k8sClusterDomain == "" {
 				klog.Warning("Using partial subdomain as Hostnames in management configuration")
 				return ndb.Namespace
-			} else {
-				// Found the FQDN of form "kubernetes.default.svc.<k8s-cluster-domain>."
-				// Extract the required parts and append them to the suffix
-				return ndb.Namespace + k8sCname[len("kubernetes.default"):len(k8sCname)-1]
 			}
+			return ndb.Namespace + ".svc." + k8sClusterDomain
 		},
 		"NdbNodeTypeMgmd":               func() string { return constants.NdbNodeTypeMgmd },
 		"NdbNodeTypeNdbmtd":             func() string { return constants.NdbNodeTypeNdbmtd },
 		"NdbNodeTypeMySQLD":             func() string { return constants.NdbNodeTypeMySQLD },
 		"NdbNodeTypeAPI":                func() string { return constants.NdbNodeTypeAPI },
+		"NdbNodeTypeArbitrator":         func() string { return constants.NdbNodeTypeArbitrator },
 		"NdbOperatorDedicatedAPINodeId": func() int { return constants.NdbOperatorDedicatedAPINodeId },
+		"ArbitratorNodeId":              func() int { return constants.ArbitratorNodeId },
 	})
 
 	if _, err := tmpl.Parse(mgmtConfigTmpl); err != nil {
@@ -200,27 +314,49 @@ var myCnfTemplate = `{{- /* Template to generate my.cnf config ini */ -}}
 # Auto generated config.ini - DO NOT EDIT
 # ConfigVersion={{GetConfigVersion}}
 
-{{.GetMySQLCnf}}
+{{.}}
 `
 
 // GetMySQLConfigString returns the MySQL Server config(my.cnf)
 // to be used by the MySQL Server StatefulSet.
 func GetMySQLConfigString(nc *v1.NdbCluster, oldConfigSummary *ConfigSummary) (string, error) {
+	var oldConfigVersion int32
+	if oldConfigSummary != nil {
+		oldConfigVersion = oldConfigSummary.MySQLServerConfigVersion
+	}
+
+	return getMySQLConfigString(nc.GetMySQLCnf(), oldConfigVersion)
+}
+
+// GetMySQLServerGroupConfigString returns the MySQL Server config(my.cnf)
+// to be used by the StatefulSet of the given MySQL Server group.
+func GetMySQLServerGroupConfigString(
+	serverGroupSpec *v1.NdbMysqldServerGroupSpec, oldConfigSummary *ConfigSummary) (string, error) {
+	var oldConfigVersion int32
+	if oldConfigSummary != nil {
+		if serverGroup := oldConfigSummary.GetMySQLServerGroup(serverGroupSpec.Name); serverGroup != nil {
+			oldConfigVersion = serverGroup.MySQLServerConfigVersion
+		}
+	}
+
+	return getMySQLConfigString(serverGroupSpec.GetMySQLCnf(), oldConfigVersion)
+}
 
-	if nc.GetMySQLCnf() == "" {
+// getMySQLConfigString returns the MySQL Server config(my.cnf)
+// generated from the given myCnf, with a version succeeding
+// the given version of the previous config.
+func getMySQLConfigString(myCnf string, oldConfigVersion int32) (string, error) {
+
+	if myCnf == "" {
 		return "", nil
 	}
 
 	tmpl := template.New("my.cnf")
 	tmpl.Funcs(template.FuncMap{
 		"GetConfigVersion": func() int32 {
-			if oldConfigSummary == nil {
-				// First version of the my.cnf config based on newly added NdbCluster spec.
-				return 1
-			} else {
-				// Bump up the config version for every change.
-				return oldConfigSummary.MySQLServerConfigVersion + 1
-			}
+			// Bump up the config version for every change. The first
+			// version of the my.cnf config will have the version 1.
+			return oldConfigVersion + 1
 		},
 	})
 
@@ -229,10 +365,10 @@ func GetMySQLConfigString(nc *v1.NdbCluster, oldConfigSummary *ConfigSummary) (s
 		panic("Failed to parse my.cnf config template")
 	}
 
-	var myCnf bytes.Buffer
-	if err := tmpl.Execute(&myCnf, nc); err != nil {
+	var myCnfBuffer bytes.Buffer
+	if err := tmpl.Execute(&myCnfBuffer, myCnf); err != nil {
 		return "", err
 	}
 
-	return myCnf.String(), nil
+	return myCnfBuffer.String(), nil
 }
@@ -0,0 +1,123 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package ndbconfig
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mysql/ndb-operator/config/debug"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig/configparser"
+)
+
+// mgmdRestartSections are the config.ini sections read by the Management
+// nodes only when they start. Any change to them requires a restart.
+var mgmdRestartSections = []string{"system", "ndb_mgmd default", "ndb_mgmd", "tcp default"}
+
+// nodeSections are the config.ini sections of the data nodes and the API
+// nodes. The nodes declared by them cannot be changed by a config reload,
+// but the rest of their parameters can be.
+var nodeSections = []string{"ndbd", "mysqld", "api"}
+
+// getNodeIdentities returns the sorted NodeId and HostName
+// pairs of the nodes declared by the given sections.
+func getNodeIdentities(sections []configparser.Section) []string {
+	identities := make([]string, 0, len(sections))
+	for _, section := range sections {
+		nodeId, _ := section.GetValue("NodeId")
+		hostname, _ := section.GetValue("HostName")
+		identities = append(identities, nodeId+"/"+hostname)
+	}
+	sort.Strings(identities)
+	return identities
+}
+
+// mgmdRestartRequired returns true if the change from the oldConfig to
+// the newConfig cannot be applied by reloading the config in the running
+// Management nodes. The Management nodes have to be restarted to apply
+// any change to their own sections, to the [system] section other than
+// the ConfigGenerationNumber, to the set of nodes in the MySQL Cluster or
+// to the NoOfReplicas, which recreates the node groups.
+// Changes to the rest of the data node and the API node parameters are
+// distributed to all the Management nodes by the reload, and the other
+// nodes pick them up when they are restarted.
+func mgmdRestartRequired(oldConfig, newConfig configparser.ConfigIni) bool {
+	// Ignore the ConfigGenerationNumber as it changes with every config
+	for _, config := range []configparser.ConfigIni{oldConfig, newConfig} {
+		if systemSection := config.GetSection("system"); systemSection != nil {
+			delete(systemSection, strings.ToLower("ConfigGenerationNumber"))
+		}
+	}
+
+	for _, sectionName := range mgmdRestartSections {
+		oldSections := configparser.ConfigIni{sectionName: oldConfig.GetAllSections(sectionName)}
+		newSections := configparser.ConfigIni{sectionName: newConfig.GetAllSections(sectionName)}
+		if !oldSections.IsEqual(newSections) {
+			return true
+		}
+	}
+
+	if oldConfig.GetValueFromSection("ndbd default", "NoOfReplicas") !=
+		newConfig.GetValueFromSection("ndbd default", "NoOfReplicas") {
+		// The node groups of the MySQL Cluster are being recreated
+		return true
+	}
+
+	for _, sectionName := range nodeSections {
+		if !reflect.DeepEqual(
+			getNodeIdentities(oldConfig.GetAllSections(sectionName)),
+			getNodeIdentities(newConfig.GetAllSections(sectionName))) {
+			// Nodes have been added, removed or moved
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetMgmdRestartConfigVersion returns the MgmdRestartConfigVersion of the
+// newConfigIni that replaces the oldConfigIni. It is the version of the
+// newConfigIni if the change requires a restart of the Management nodes,
+// or else the MgmdRestartConfigVersion of the oldConfigSummary.
+func GetMgmdRestartConfigVersion(
+	oldConfigSummary *ConfigSummary, oldConfigIni, newConfigIni string) (int32, error) {
+
+	newConfig, err := configparser.ParseString(newConfigIni)
+	if err != nil {
+		// Should never happen as the operator generated the config.ini
+		return 0, debug.InternalError(err)
+	}
+	newConfigVersion := parseInt32(newConfig.GetValueFromSection("system", "ConfigGenerationNumber"))
+
+	if oldConfigSummary == nil {
+		// The Management nodes will be started with the first version of the config
+		return newConfigVersion, nil
+	}
+
+	oldConfig, err := configparser.ParseString(oldConfigIni)
+	if err != nil {
+		return 0, debug.InternalError(err)
+	}
+
+	if mgmdRestartRequired(oldConfig, newConfig) {
+		return newConfigVersion, nil
+	}
+
+	return oldConfigSummary.MgmdRestartConfigVersion, nil
+}
+
+// GetMySQLClusterConfigVersion returns the version of the config.ini
+// the pods of the given node type have to be started with. The pods
+// of the Management nodes, including the external arbitrator, are
+// restarted only for the versions that cannot be applied by reloading
+// the config. The other pods are restarted for every version.
+func (cs *ConfigSummary) GetMySQLClusterConfigVersion(nodeType constants.NdbNodeType) int32 {
+	if nodeType == constants.NdbNodeTypeMgmd || nodeType == constants.NdbNodeTypeArbitrator {
+		return cs.MgmdRestartConfigVersion
+	}
+	return cs.MySQLClusterConfigVersion
+}
@@ -1,12 +1,16 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
 package ndbconfig
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/mysql/ndb-operator/config/debug"
 	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
@@ -14,6 +18,33 @@ import (
 	"github.com/mysql/ndb-operator/pkg/ndbconfig/configparser"
 )
 
+// MySQLServerGroup contains the details of a MySQL Server group extracted from the configMap data.
+type MySQLServerGroup struct {
+	// Name is the name of the MySQL Server group.
+	Name string `json:"name"`
+	// NodeCount is the number of MySQL Servers in the group.
+	NodeCount int32 `json:"nodeCount"`
+	// StartNodeId is the nodeId of the first [mysqld] section reserved for the group.
+	StartNodeId int32 `json:"startNodeId"`
+	// NumOfNodeIds is the number of nodeIds, starting from the
+	// StartNodeId, reserved for the group.
+	NumOfNodeIds int32 `json:"numOfNodeIds,omitempty"`
+	// MySQLServerConfigVersion is the version of the my.cnf of the group stored in the config map
+	MySQLServerConfigVersion int32 `json:"-"`
+	// myCnfConfig has the parsed my.cnf of the group
+	myCnfConfig configparser.ConfigIni
+}
+
+// NodeIdentity is the identity of a MySQL Cluster node declared in the config.ini
+type NodeIdentity struct {
+	// NodeId is the nodeId of the node
+	NodeId int
+	// NodeType is the type of the node
+	NodeType constants.NdbNodeType
+	// PodName is the name of the pod running the node
+	PodName string
+}
+
 // ConfigSummary contains a summary of information extracted from the
 // configMap data. It is used during creation and updation of various
 // K8s resources and also to compare any new incoming Ndb spec change.
@@ -22,10 +53,20 @@ type ConfigSummary struct {
 	NdbClusterGeneration int64
 	// MySQLClusterConfigVersion is the version of the config.ini stored in the config map
 	MySQLClusterConfigVersion int32
+	// MgmdRestartConfigVersion is the version of the last config.ini that
+	// had a change requiring a restart of the Management nodes. The changes
+	// made by any later version are applied by reloading the config.
+	MgmdRestartConfigVersion int32
+	// DataNodeRestartType is the type of the restart required
+	// by the data nodes to apply the config in the config map.
+	DataNodeRestartType v1.DataNodeRestartType
 	// MySQLServerConfigVersion is the version of the my.cnf stored in the config map
 	MySQLServerConfigVersion int32
-	// NumOfManagementNodes is number of Management Nodes (1 or 2).
+	// NumOfManagementNodes is number of Management Nodes (1 or 2),
+	// excluding the Management node run as the external arbitrator.
 	NumOfManagementNodes int32
+	// HasArbitrator indicates if the config has the external arbitrator
+	HasArbitrator bool
 	// NumOfDataNodes is the number of Data Nodes.
 	NumOfDataNodes int32
 	// NumOfMySQLServers is the number of MySQL Servers
@@ -35,12 +76,18 @@ type ConfigSummary struct {
 	NumOfMySQLServerSlots int32
 	// NumOfFreeApiSlots is the number of [api] sections declared in the config based on spec.freeApiSlots
 	NumOfFreeApiSlots int32
+	// NdbAPIApplicationHostnames are the hostnames, without the K8s Cluster
+	// domain, of the NDBAPI application pods mapped by the nodeIds of the
+	// [api] sections reserved for them.
+	NdbAPIApplicationHostnames map[int32]string
 	// RedundancyLevel is the number of replicas of the data stored in MySQL Cluster.
 	RedundancyLevel int32
 	// defaultNdbdSection has the values extracted from the default ndbd section of the management config.
 	defaultNdbdSection configparser.Section
 	// defaultMgmdSection has the values extracted from the default ndbd section of the management config.
 	defaultMgmdSection configparser.Section
+	// clusterLogDestination is the LogDestination of the first Management Node
+	clusterLogDestination string
 	// MySQLLoadBalancer indicates if the load balancer service for MySQL servers needs to be enabled
 	MySQLLoadBalancer bool
 	// ManagementLoadBalancer indicates if the load balancer service for management nodes needs to be enabled
@@ -49,6 +96,21 @@ type ConfigSummary struct {
 	myCnfConfig configparser.ConfigIni
 	// The host of the MySQL root user
 	MySQLRootHost string
+	// MySQLServerGroups are the additional MySQL Server groups
+	MySQLServerGroups []MySQLServerGroup
+	// LocationDomainZones are the zones of the worker nodes running
+	// the MySQL Cluster nodes, mapped by the names of their pods.
+	LocationDomainZones map[string]string
+	// RestartRequests are the NdbCluster annotations requesting a
+	// rolling restart of the MySQL Cluster nodes, mapped by their keys.
+	RestartRequests map[string]string
+	// ConfigChanges is a human-readable summary of the changes made to
+	// the configs by the NdbCluster generation this config is based on.
+	ConfigChanges []string
+	// Nodes are the MySQL Cluster nodes declared in the config.ini with
+	// a hostname, i.e. all the nodes except the free API slots, sorted
+	// by their nodeIds.
+	Nodes []NodeIdentity
 }
 
 // parseInt32 parses the given string into an Int32
@@ -96,25 +158,240 @@ func NewConfigSummary(configMapData map[string]string) (*ConfigSummary, error) {
 		MySQLRootHost:          configMapData[constants.MySQLRootHost],
 	}
 
+	// The config maps created by older operator versions do not have the
+	// MgmdRestartConfigVersion, as they restarted the Management nodes for
+	// every config change. Assume the current config requires a restart.
+	cs.MgmdRestartConfigVersion = cs.MySQLClusterConfigVersion
+	if mgmdRestartConfigVersion, exists := configMapData[constants.MgmdRestartConfigVersion]; exists {
+		cs.MgmdRestartConfigVersion = parseInt32(mgmdRestartConfigVersion)
+	}
+
+	// The config maps created by older operator versions do not have the
+	// DataNodeRestartType, as they applied all the changes by node restarts.
+	cs.DataNodeRestartType = v1.DataNodeNodeRestart
+	if dataNodeRestartType, exists := configMapData[constants.DataNodeRestartType]; exists {
+		cs.DataNodeRestartType = v1.DataNodeRestartType(dataNodeRestartType)
+	}
+
+	// Exclude the external arbitrator from the Management Nodes
+	for _, mgmdSection := range config.GetAllSections("ndb_mgmd") {
+		if nodeId, _ := mgmdSection.GetValue("NodeId"); nodeId == strconv.Itoa(constants.ArbitratorNodeId) {
+			cs.HasArbitrator = true
+			cs.NumOfManagementNodes--
+		}
+	}
+
+	// The [api] sections declared with a hostname are reserved for
+	// the NDBAPI applications and are not part of the free API slots
+	for _, apiSection := range config.GetAllSections("api") {
+		hostname, exists := apiSection.GetValue("HostName")
+		if !exists {
+			continue
+		}
+
+		if cs.NdbAPIApplicationHostnames == nil {
+			cs.NdbAPIApplicationHostnames = make(map[int32]string)
+		}
+		// Retain only the '<pod>.<service>.<namespace>' part of the hostname
+		if hostnameParts := strings.SplitN(hostname, ".", 4); len(hostnameParts) == 4 {
+			hostname = strings.Join(hostnameParts[:3], ".")
+		}
+		nodeId, _ := apiSection.GetValue("NodeId")
+		cs.NdbAPIApplicationHostnames[parseInt32(nodeId)] = hostname
+		cs.NumOfFreeApiSlots--
+	}
+
+	// Extract the identities of the nodes
+	cs.Nodes = getNodeIdentitiesFromConfig(config)
+
+	// Extract the cluster log destination from the Management Node sections
+	if mgmdSections := config.GetAllSections("ndb_mgmd"); len(mgmdSections) != 0 {
+		cs.clusterLogDestination, _ = mgmdSections[0].GetValue("LogDestination")
+	}
+
 	// Update MySQL Config details if it exists
-	mysqlConfigString := configMapData[constants.MySQLConfigKey]
-	if mysqlConfigString != "" {
-		cs.myCnfConfig, err = configparser.ParseString(mysqlConfigString)
-		if err != nil {
-			// Should never happen as the operator generated the my.cnf
+	if cs.myCnfConfig, cs.MySQLServerConfigVersion, err =
+		parseMySQLConfig(configMapData[constants.MySQLConfigKey]); err != nil {
+		return nil, err
+	}
+
+	// Extract the MySQL Server groups and their MySQL Configs
+	if serverGroupsString := configMapData[constants.MySQLServerGroups]; serverGroupsString != "" {
+		if err = json.Unmarshal([]byte(serverGroupsString), &cs.MySQLServerGroups); err != nil {
+			// Should never happen as the operator generated the MySQL Server groups
+			return nil, debug.InternalError(err)
+		}
+
+		for i := range cs.MySQLServerGroups {
+			serverGroup := &cs.MySQLServerGroups[i]
+			if serverGroup.myCnfConfig, serverGroup.MySQLServerConfigVersion, err = parseMySQLConfig(
+				configMapData[GetMySQLServerGroupConfigKey(serverGroup.Name)]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Extract the zones of the location domains
+	if locationDomainZonesString := configMapData[constants.LocationDomainZones]; locationDomainZonesString != "" {
+		if err = json.Unmarshal([]byte(locationDomainZonesString), &cs.LocationDomainZones); err != nil {
+			// Should never happen as the operator generated the zones
+			return nil, debug.InternalError(err)
+		}
+	}
+
+	// Extract the restart requests
+	if restartRequestsString := configMapData[constants.RestartRequests]; restartRequestsString != "" {
+		if err = json.Unmarshal([]byte(restartRequestsString), &cs.RestartRequests); err != nil {
+			// Should never happen as the operator generated the restart requests
+			return nil, debug.InternalError(err)
+		}
+	}
+
+	// Extract the summary of the config changes
+	if configChangesString := configMapData[constants.ConfigChanges]; configChangesString != "" {
+		if err = json.Unmarshal([]byte(configChangesString), &cs.ConfigChanges); err != nil {
+			// Should never happen as the operator generated the summary
 			return nil, debug.InternalError(err)
 		}
-		cs.MySQLServerConfigVersion = parseInt32(
-			cs.myCnfConfig.GetValueFromSection("header", "ConfigVersion"))
 	}
 
 	return cs, nil
 }
 
+// nodeSectionTypes maps the config.ini sections declaring the
+// nodes run by the operator to the types of the nodes.
+var nodeSectionTypes = map[string]constants.NdbNodeType{
+	"ndb_mgmd": constants.NdbNodeTypeMgmd,
+	"ndbd":     constants.NdbNodeTypeNdbmtd,
+	"mysqld":   constants.NdbNodeTypeMySQLD,
+}
+
+// getNodeIdentitiesFromConfig extracts the identities of the nodes
+// declared with a hostname in the given config.ini, sorted by nodeIds.
+func getNodeIdentitiesFromConfig(config configparser.ConfigIni) []NodeIdentity {
+	var nodes []NodeIdentity
+	for sectionName, nodeType := range nodeSectionTypes {
+		for _, section := range config.GetAllSections(sectionName) {
+			hostname, exists := section.GetValue("HostName")
+			if !exists {
+				continue
+			}
+
+			nodeId, _ := section.GetValue("NodeId")
+			node := NodeIdentity{
+				NodeId:   int(parseInt32(nodeId)),
+				NodeType: nodeType,
+				PodName:  getPodName(hostname),
+			}
+			if node.NodeId == constants.ArbitratorNodeId {
+				node.NodeType = constants.NdbNodeTypeArbitrator
+			}
+			nodes = append(nodes, node)
+		}
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].NodeId < nodes[j].NodeId
+	})
+	return nodes
+}
+
+// GetNodeIds returns the sorted nodeIds of the nodes of the given type.
+// The MySQL Servers with a connection pool have multiple nodeIds per pod.
+func (cs *ConfigSummary) GetNodeIds(nodeType constants.NdbNodeType) []int {
+	var nodeIds []int
+	for _, node := range cs.Nodes {
+		if node.NodeType == nodeType {
+			nodeIds = append(nodeIds, node.NodeId)
+		}
+	}
+	return nodeIds
+}
+
+// GetFirstDataNodeId returns the nodeId of the first data node declared
+// in the config. The nodeIds of the data nodes are always successive.
+func (cs *ConfigSummary) GetFirstDataNodeId() int {
+	if dataNodeIds := cs.GetNodeIds(constants.NdbNodeTypeNdbmtd); len(dataNodeIds) != 0 {
+		return dataNodeIds[0]
+	}
+	return 0
+}
+
+// GetPodNodeIds returns the nodeIds of the nodes run by the given pod
+func (cs *ConfigSummary) GetPodNodeIds(podName string) []int {
+	var nodeIds []int
+	for _, node := range cs.Nodes {
+		if node.PodName == podName {
+			nodeIds = append(nodeIds, node.NodeId)
+		}
+	}
+	return nodeIds
+}
+
+// GetNodePodName returns the name of the pod running the
+// node with the given nodeId, or an empty string if the
+// config doesn't declare such a node with a hostname.
+func (cs *ConfigSummary) GetNodePodName(nodeId int) string {
+	for _, node := range cs.Nodes {
+		if node.NodeId == nodeId {
+			return node.PodName
+		}
+	}
+	return ""
+}
+
+// parseMySQLConfig parses the given my.cnf generated by the operator
+// and returns the parsed config along with its version.
+func parseMySQLConfig(mysqlConfigString string) (configparser.ConfigIni, int32, error) {
+	if mysqlConfigString == "" {
+		return nil, 0, nil
+	}
+
+	myCnfConfig, err := configparser.ParseString(mysqlConfigString)
+	if err != nil {
+		// Should never happen as the operator generated the my.cnf
+		return nil, 0, debug.InternalError(err)
+	}
+
+	return myCnfConfig, parseInt32(myCnfConfig.GetValueFromSection("header", "ConfigVersion")), nil
+}
+
+// GetMySQLServerGroup returns the MySQL Server group with the
+// given name or nil if the config map doesn't have such a group.
+func (cs *ConfigSummary) GetMySQLServerGroup(name string) *MySQLServerGroup {
+	for i := range cs.MySQLServerGroups {
+		if cs.MySQLServerGroups[i].Name == name {
+			return &cs.MySQLServerGroups[i]
+		}
+	}
+
+	return nil
+}
+
+// mySQLServerGroupsNeedUpdate checks if the MySQL Server groups, or
+// the nodeIds reserved for them, have been changed in the NdbCluster spec.
+func (cs *ConfigSummary) mySQLServerGroupsNeedUpdate(nc *v1.NdbCluster) bool {
+	serverGroups := getMySQLServerGroups(nc)
+	if len(serverGroups) != len(cs.MySQLServerGroups) {
+		return true
+	}
+
+	for i, serverGroup := range serverGroups {
+		existingServerGroup := cs.MySQLServerGroups[i]
+		if serverGroup.Name != existingServerGroup.Name ||
+			serverGroup.NodeCount != existingServerGroup.NodeCount ||
+			serverGroup.StartNodeId != existingServerGroup.StartNodeId {
+			return true
+		}
+	}
+
+	return false
+}
+
 // MySQLClusterConfigNeedsUpdate checks if the config of the MySQL Cluster needs to be updated.
 func (cs *ConfigSummary) MySQLClusterConfigNeedsUpdate(nc *v1.NdbCluster) (needsUpdate bool) {
 	// Check if the default ndbd section has been updated
-	newNdbdConfig := nc.Spec.DataNode.Config
+	newNdbdConfig := getDefaultNdbdConfigs(nc)
 	// Operator sets some default config parameters - take them into account when comparing configs.
 	if len(newNdbdConfig)+numOfOperatorSetConfigs != len(cs.defaultNdbdSection) {
 		// A config has been added (or) removed from default ndbd section
@@ -123,7 +400,7 @@ func (cs *ConfigSummary) MySQLClusterConfigNeedsUpdate(nc *v1.NdbCluster) (needs
 	// Check if all configs exist and their value has not changed
 	// TODO: Compare with actual values from the DataNodes
 	for configKey, configValue := range newNdbdConfig {
-		if value, exists := cs.defaultNdbdSection.GetValue(configKey); !exists || value != configValue.String() {
+		if value, exists := cs.defaultNdbdSection.GetValue(configKey); !exists || value != configValue {
 			// Either the config doesn't exist or the value has been changed
 			return true
 		}
@@ -134,9 +411,18 @@ func (cs *ConfigSummary) MySQLClusterConfigNeedsUpdate(nc *v1.NdbCluster) (needs
 		return true
 	}
 
+	// Check if there is a change in the MySQL Server groups
+	if cs.mySQLServerGroupsNeedUpdate(nc) {
+		return true
+	}
+
 	// Check if there is a change in the number of MySQL server
 	// slots or number of free api slots.
-	if cs.NumOfMySQLServerSlots != GetNumOfSectionsRequiredForMySQLServers(nc) {
+	numOfMySQLServerGroupSlots := int32(0)
+	for _, serverGroup := range nc.GetMySQLServerGroups() {
+		numOfMySQLServerGroupSlots += serverGroup.NodeCount * nc.GetMySQLServerConnectionPoolSize()
+	}
+	if cs.NumOfMySQLServerSlots != GetNumOfSectionsRequiredForMySQLServers(nc)+numOfMySQLServerGroupSlots {
 		return true
 	}
 
@@ -147,6 +433,16 @@ func (cs *ConfigSummary) MySQLClusterConfigNeedsUpdate(nc *v1.NdbCluster) (needs
 		return true
 	}
 
+	// Check if the API sections reserved for the NDBAPI applications have been changed
+	if !reflect.DeepEqual(cs.NdbAPIApplicationHostnames, nc.GetNdbAPIApplicationHostnames()) {
+		return true
+	}
+
+	// Check if the location domains have been disabled in the spec
+	if nc.Spec.LocationDomainTopologyKey == "" && len(cs.LocationDomainZones) != 0 {
+		return true
+	}
+
 	// Check if the default mgmd section has been updated
 	if nc.Spec.ManagementNode != nil {
 		newMgmdConfig := nc.Spec.ManagementNode.Config
@@ -163,6 +459,17 @@ func (cs *ConfigSummary) MySQLClusterConfigNeedsUpdate(nc *v1.NdbCluster) (needs
 		}
 	}
 
+	// Check if the cluster log destination has been updated. A config
+	// generated by an older operator version doesn't have one, which
+	// is left as such until the LogDestination is specified in the spec.
+	if cs.clusterLogDestination != "" ||
+		(nc.Spec.ManagementNode != nil && nc.Spec.ManagementNode.LogDestination != "") {
+		// The first Management Node always has the node id 1
+		if cs.clusterLogDestination != getClusterLogDestination(nc, 1) {
+			return true
+		}
+	}
+
 	// No update required to the MySQL Cluster config.
 	return false
 
@@ -170,18 +477,40 @@ func (cs *ConfigSummary) MySQLClusterConfigNeedsUpdate(nc *v1.NdbCluster) (needs
 
 // MySQLCnfNeedsUpdate checks if the my.cnf config stored in the configMap needs to be updated
 func (cs *ConfigSummary) MySQLCnfNeedsUpdate(nc *v1.NdbCluster) (needsUpdate bool, err error) {
-	myCnf := nc.GetMySQLCnf()
-
 	if cs == nil {
 		// NdbCluster resource created for the first time.
 		// Need to update my.cnf if it is specified in the spec.
-		return myCnf != "", nil
+		return nc.GetMySQLCnf() != "", nil
+	}
+
+	return mySQLCnfNeedsUpdate(cs.myCnfConfig, nc.GetMySQLCnf())
+}
+
+// MySQLServerGroupCnfNeedsUpdate checks if the my.cnf config of the
+// given MySQL Server group stored in the configMap needs to be updated
+func (cs *ConfigSummary) MySQLServerGroupCnfNeedsUpdate(
+	serverGroupSpec *v1.NdbMysqldServerGroupSpec) (needsUpdate bool, err error) {
+	var serverGroup *MySQLServerGroup
+	if cs != nil {
+		serverGroup = cs.GetMySQLServerGroup(serverGroupSpec.Name)
 	}
 
+	if serverGroup == nil {
+		// MySQL Server group is being added.
+		// Need to update my.cnf if it is specified in the spec.
+		return serverGroupSpec.GetMySQLCnf() != "", nil
+	}
+
+	return mySQLCnfNeedsUpdate(serverGroup.myCnfConfig, serverGroupSpec.GetMySQLCnf())
+}
+
+// mySQLCnfNeedsUpdate checks if the given my.cnf from the spec
+// differs from the existing my.cnf config stored in the configMap
+func mySQLCnfNeedsUpdate(existingMyCnfConfig configparser.ConfigIni, myCnf string) (needsUpdate bool, err error) {
 	if myCnf == "" {
 		// myCnf is empty.
 		// Update required if it previously had a value
-		return cs.myCnfConfig != nil, nil
+		return existingMyCnfConfig != nil, nil
 	}
 
 	myCnfConfig, err := configparser.ParseString(myCnf)
@@ -190,6 +519,10 @@ func (cs *ConfigSummary) MySQLCnfNeedsUpdate(nc *v1.NdbCluster) (needsUpdate boo
 		return false, debug.InternalError(err)
 	}
 
-	// Compare the configs and return if the config needs an update
-	return !cs.myCnfConfig.IsEqual(myCnfConfig), nil
+	// Compare the configs, ignoring the header with the ConfigVersion
+	// of the existing config, and return if the config needs an update
+	if header, exists := existingMyCnfConfig["header"]; exists {
+		myCnfConfig["header"] = header
+	}
+	return !existingMyCnfConfig.IsEqual(myCnfConfig), nil
 }
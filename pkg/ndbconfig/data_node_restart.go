@@ -0,0 +1,148 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package ndbconfig
+
+import (
+	"sort"
+
+	"github.com/mysql/ndb-operator/config/debug"
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig/configparser"
+)
+
+// dataNodeParameterRestartTypes has the data node parameters that need
+// more than a node restart to be changed, mapped by their lower case
+// names to the type of the restart they need. All the other parameters
+// are changed by a node restart.
+//
+// More info :
+// https://dev.mysql.com/doc/refman/8.0/en/mysql-cluster-params-ndbd.html
+var dataNodeParameterRestartTypes = map[string]v1.DataNodeRestartType{
+	// The file system and the redo log files
+	// are created only by an initial start.
+	"filesystempath":          v1.DataNodeInitialNodeRestart,
+	"filesystempathdd":        v1.DataNodeInitialNodeRestart,
+	"filesystempathdatafiles": v1.DataNodeInitialNodeRestart,
+	"filesystempathundofiles": v1.DataNodeInitialNodeRestart,
+	"backupdatadir":           v1.DataNodeInitialNodeRestart,
+	"encryptedfilesystem":     v1.DataNodeInitialNodeRestart,
+	"nooffragmentlogfiles":    v1.DataNodeInitialNodeRestart,
+	"fragmentlogfilesize":     v1.DataNodeInitialNodeRestart,
+	"initfragmentlogfiles":    v1.DataNodeInitialNodeRestart,
+	"nooffragmentlogparts":    v1.DataNodeInitialNodeRestart,
+	// These have to be same across all
+	// the data nodes of the MySQL Cluster.
+	"diskless":            v1.DataNodeSystemRestart,
+	"initiallogfilegroup": v1.DataNodeSystemRestart,
+	"initialtablespace":   v1.DataNodeSystemRestart,
+	// The node groups are formed only by an initial system start
+	"noofreplicas": v1.DataNodeInitialSystemRestart,
+}
+
+// dataNodeRestartTypeOrder orders the restart types by their disruption
+var dataNodeRestartTypeOrder = map[v1.DataNodeRestartType]int{
+	v1.DataNodeNodeRestart:          0,
+	v1.DataNodeInitialNodeRestart:   1,
+	v1.DataNodeSystemRestart:        2,
+	v1.DataNodeInitialSystemRestart: 3,
+}
+
+// MoreDisruptiveRestart returns the more disruptive of the given restart types
+func MoreDisruptiveRestart(restartType1, restartType2 v1.DataNodeRestartType) v1.DataNodeRestartType {
+	if dataNodeRestartTypeOrder[restartType2] > dataNodeRestartTypeOrder[restartType1] {
+		return restartType2
+	}
+	return restartType1
+}
+
+// GetSingleReplicaRestartType returns the type of the restart that applies
+// a change of the given restart type to a MySQL Cluster with a single
+// replica. Such a MySQL Cluster cannot stay available while any of its data
+// nodes restarts, and so the data nodes are always restarted together.
+func GetSingleReplicaRestartType(restartType v1.DataNodeRestartType) v1.DataNodeRestartType {
+	switch restartType {
+	case v1.DataNodeNodeRestart:
+		return v1.DataNodeSystemRestart
+	case v1.DataNodeInitialNodeRestart:
+		return v1.DataNodeInitialSystemRestart
+	default:
+		return restartType
+	}
+}
+
+// getDataNodeSections returns the [ndbd default] section and the
+// [ndbd] sections of the given config, mapped by their NodeIds.
+func getDataNodeSections(config configparser.ConfigIni) map[string]configparser.Section {
+	sections := map[string]configparser.Section{
+		"default": config.GetSection("ndbd default"),
+	}
+	for _, section := range config.GetAllSections("ndbd") {
+		nodeId, _ := section.GetValue("NodeId")
+		sections[nodeId] = section
+	}
+	return sections
+}
+
+// GetDataNodeRestartType returns the type of the restart required by the
+// existing data nodes to apply the change from the oldConfigIni to the
+// newConfigIni, along with the changed parameters that require it. A node
+// restart is returned if none of the parameters require anything more.
+func GetDataNodeRestartType(
+	oldConfigIni, newConfigIni string) (v1.DataNodeRestartType, []string, error) {
+
+	oldConfig, err := configparser.ParseString(oldConfigIni)
+	if err != nil {
+		// Should never happen as the operator generated the config.ini
+		return "", nil, debug.InternalError(err)
+	}
+
+	newConfig, err := configparser.ParseString(newConfigIni)
+	if err != nil {
+		return "", nil, debug.InternalError(err)
+	}
+
+	// Collect the parameters changed for the existing data nodes
+	changedParameters := make(map[string]bool)
+	newSections := getDataNodeSections(newConfig)
+	for nodeId, oldSection := range getDataNodeSections(oldConfig) {
+		newSection := newSections[nodeId]
+		if newSection == nil {
+			// The data node has been removed
+			continue
+		}
+
+		for parameter, value := range oldSection {
+			if newValue, exists := newSection[parameter]; !exists || newValue != value {
+				changedParameters[parameter] = true
+			}
+		}
+		for parameter := range newSection {
+			if _, exists := oldSection[parameter]; !exists {
+				changedParameters[parameter] = true
+			}
+		}
+	}
+
+	// The parameter names in the parsed config are in lower case
+	restartType := v1.DataNodeNodeRestart
+	var parameters []string
+	for parameter := range changedParameters {
+		if parameterRestartType, exists := dataNodeParameterRestartTypes[parameter]; exists {
+			restartType = MoreDisruptiveRestart(restartType, parameterRestartType)
+			parameters = append(parameters, parameter)
+		}
+	}
+
+	// Return only the parameters that require the chosen restart type
+	var restartParameters []string
+	for _, parameter := range parameters {
+		if dataNodeParameterRestartTypes[parameter] == restartType {
+			restartParameters = append(restartParameters, parameter)
+		}
+	}
+	sort.Strings(restartParameters)
+
+	return restartType, restartParameters, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package ndbconfig
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// maxLocationDomainId is the largest LocationDomainId allowed by MySQL Cluster
+const maxLocationDomainId = 16
+
+// getLocationDomainIds maps the given pod name to zone map into a
+// pod name to LocationDomainId map. The distinct zones are assigned the
+// ids starting from 1 in their sorted order. The zones that cannot be
+// assigned an id, as the number of zones exceeds the maximum number of
+// location domains, are left out of the map.
+func getLocationDomainIds(locationDomainZones map[string]string) map[string]int32 {
+	var zones []string
+	seen := make(map[string]bool)
+	for _, zone := range locationDomainZones {
+		if !seen[zone] {
+			seen[zone] = true
+			zones = append(zones, zone)
+		}
+	}
+	sort.Strings(zones)
+
+	zoneIds := make(map[string]int32, len(zones))
+	for i, zone := range zones {
+		if i == maxLocationDomainId {
+			break
+		}
+		zoneIds[zone] = int32(i + 1)
+	}
+
+	locationDomainIds := make(map[string]int32, len(locationDomainZones))
+	for podName, zone := range locationDomainZones {
+		if id, exists := zoneIds[zone]; exists {
+			locationDomainIds[podName] = id
+		}
+	}
+
+	return locationDomainIds
+}
+
+// getPodName extracts the pod name from the given hostname of a MySQL Cluster node
+func getPodName(hostname string) string {
+	podName, _, _ := strings.Cut(hostname, ".")
+	return podName
+}
+
+// GetLocationDomainZonesString returns the given pod
+// name to zone map, to be stored in the config map.
+func GetLocationDomainZonesString(locationDomainZones map[string]string) (string, error) {
+	if len(locationDomainZones) == 0 {
+		return "", nil
+	}
+
+	locationDomainZonesBytes, err := json.Marshal(locationDomainZones)
+	if err != nil {
+		return "", err
+	}
+
+	return string(locationDomainZonesBytes), nil
+}
@@ -1,16 +1,23 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
 package ndbconfig
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	"github.com/mysql/ndb-operator/config"
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
 	"github.com/mysql/ndb-operator/pkg/constants"
 	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig/configparser"
 )
 
 func errorIfNotEqual(t *testing.T, expected, actual int32, desc string) {
@@ -68,6 +75,8 @@ func Test_NewConfigSummary(t *testing.T) {
 
 	errorIfNotEqual(t, 3, int32(cs.NdbClusterGeneration), "cs.MySQLClusterConfigNeedsUpdate")
 	errorIfNotEqual(t, 4711, cs.MySQLClusterConfigVersion, "cs.MySQLClusterConfigVersion")
+	// Config maps without the mgmdRestartConfigVersion key require a restart for every version
+	errorIfNotEqual(t, 4711, cs.MgmdRestartConfigVersion, "cs.MgmdRestartConfigVersion")
 	errorIfNotEqual(t, 2, cs.RedundancyLevel, " cs.RedundancyLevel")
 	errorIfNotEqual(t, 2, cs.NumOfManagementNodes, "cs.NumOfManagementNodes")
 	errorIfNotEqual(t, 2, cs.NumOfDataNodes, "cs.NumOfDataNodes")
@@ -81,6 +90,10 @@ func Test_NewConfigSummary(t *testing.T) {
 }
 
 func Test_GetConfigString(t *testing.T) {
+	// The Management Nodes write the cluster log to
+	// the console when the operator streams them.
+	config.StreamClusterLog = true
+	defer func() { config.StreamClusterLog = false }()
 
 	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
 	ndb.Spec.FreeAPISlots = 3
@@ -129,11 +142,13 @@ AllowUnresolvedHostnames=1
 NodeId=1
 Hostname=example-ndb-mgmd-0.example-ndb-mgmd.default
 DataDir=/var/lib/ndb/data
+LogDestination=CONSOLE;FILE:filename=/var/lib/ndb/data/ndb_1_cluster.log,maxsize=1000000,maxfiles=6
 
 [ndb_mgmd]
 NodeId=2
 Hostname=example-ndb-mgmd-1.example-ndb-mgmd.default
 DataDir=/var/lib/ndb/data
+LogDestination=CONSOLE;FILE:filename=/var/lib/ndb/data/ndb_2_cluster.log,maxsize=1000000,maxfiles=6
 
 [ndbd]
 NodeId=3
@@ -321,3 +336,943 @@ config2=value2
 		t.Errorf("Generated :\n%s\n", configString)
 	}
 }
+
+func Test_MySQLClusterConfigNeedsUpdate_MySQLServerScaling(t *testing.T) {
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.MysqlNode.NodeCount = 1
+	ndb.Spec.MysqlNode.MaxNodeCount = 3
+
+	configString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	cs, err := NewConfigSummary(map[string]string{
+		constants.ConfigIniKey:           configString,
+		constants.NdbClusterGeneration:   "1",
+		constants.NumOfMySQLServers:      "1",
+		constants.ManagementLoadBalancer: "false",
+		constants.MySQLLoadBalancer:      "false",
+	})
+	if err != nil {
+		t.Fatalf("NewConfigSummary failed : %s", err)
+	}
+
+	// The MySQL Server sections for maxNodeCount should be reserved upfront
+	errorIfNotEqual(t, 3, cs.NumOfMySQLServerSlots, "cs.NumOfMySQLServerSlots")
+
+	for _, tc := range []struct {
+		nodeCount    int32
+		maxNodeCount int32
+		needsUpdate  bool
+		desc         string
+	}{
+		{3, 3, false, "scale up within the reserved slots"},
+		{1, 3, false, "no change"},
+		{2, 5, true, "maxNodeCount increased"},
+	} {
+		ndb.Spec.MysqlNode.NodeCount = tc.nodeCount
+		ndb.Spec.MysqlNode.MaxNodeCount = tc.maxNodeCount
+		errorIfNotEqualBool(t, tc.needsUpdate, cs.MySQLClusterConfigNeedsUpdate(ndb), tc.desc)
+	}
+}
+
+func Test_GetMgmdRestartConfigVersion(t *testing.T) {
+
+	getIntStrPtr := func(obj intstr.IntOrString) *intstr.IntOrString {
+		return &obj
+	}
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.MysqlNode.NodeCount = 1
+	ndb.Spec.MysqlNode.MaxNodeCount = 2
+
+	oldConfigString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	// The first version of the config is always applied by a restart
+	mgmdRestartConfigVersion, err := GetMgmdRestartConfigVersion(nil, "", oldConfigString)
+	if err != nil {
+		t.Fatalf("GetMgmdRestartConfigVersion failed : %s", err)
+	}
+	errorIfNotEqual(t, 1, mgmdRestartConfigVersion, "MgmdRestartConfigVersion of the first config")
+
+	// Config summary of a config.ini whose earlier versions have been reloaded
+	oldConfigSummary := &ConfigSummary{
+		MySQLClusterConfigVersion: 1,
+		MgmdRestartConfigVersion:  0,
+		NumOfDataNodes:            2,
+	}
+
+	for _, tc := range []struct {
+		updateNdb       func(nc *v1.NdbCluster)
+		restartRequired bool
+		desc            string
+	}{
+		{
+			updateNdb: func(nc *v1.NdbCluster) {
+				nc.Spec.DataNode.Config = map[string]*intstr.IntOrString{
+					"DataMemory": getIntStrPtr(intstr.FromString("200M")),
+				}
+			},
+			restartRequired: false,
+			desc:            "data node config updated",
+		},
+		{
+			updateNdb: func(nc *v1.NdbCluster) {
+				nc.Spec.MysqlNode.NodeCount = 2
+			},
+			restartRequired: false,
+			desc:            "MySQL Servers scaled up within the reserved slots",
+		},
+		{
+			updateNdb: func(nc *v1.NdbCluster) {
+				nc.Spec.MysqlNode.MaxNodeCount = 4
+			},
+			restartRequired: true,
+			desc:            "MySQL Server slots added",
+		},
+		{
+			updateNdb: func(nc *v1.NdbCluster) {
+				nc.Spec.ManagementNode.Config = map[string]*intstr.IntOrString{
+					"ExtraSendBufferMemory": getIntStrPtr(intstr.FromString("30M")),
+				}
+			},
+			restartRequired: true,
+			desc:            "management node config updated",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			nc := ndb.DeepCopy()
+			tc.updateNdb(nc)
+			newConfigString, err := GetConfigString(nc, oldConfigSummary)
+			if err != nil {
+				t.Fatalf("Failed to generate config string from Ndb : %s", err)
+			}
+
+			mgmdRestartConfigVersion, err := GetMgmdRestartConfigVersion(
+				oldConfigSummary, oldConfigString, newConfigString)
+			if err != nil {
+				t.Fatalf("GetMgmdRestartConfigVersion failed : %s", err)
+			}
+
+			// A restart is required to apply the new config version 2
+			expectedVersion := oldConfigSummary.MgmdRestartConfigVersion
+			if tc.restartRequired {
+				expectedVersion = 2
+			}
+			errorIfNotEqual(t, expectedVersion, mgmdRestartConfigVersion, "MgmdRestartConfigVersion")
+		})
+	}
+}
+
+func Test_GetDataNodeRestartType(t *testing.T) {
+
+	getIntStrPtr := func(obj intstr.IntOrString) *intstr.IntOrString {
+		return &obj
+	}
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	oldConfigString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	oldConfigSummary := &ConfigSummary{
+		MySQLClusterConfigVersion: 1,
+		NumOfDataNodes:            2,
+	}
+
+	for _, tc := range []struct {
+		dataNodeConfig      map[string]*intstr.IntOrString
+		expectedRestartType v1.DataNodeRestartType
+		expectedParameters  string
+		desc                string
+	}{
+		{
+			dataNodeConfig: map[string]*intstr.IntOrString{
+				"DataMemory": getIntStrPtr(intstr.FromString("200M")),
+			},
+			expectedRestartType: v1.DataNodeNodeRestart,
+			desc:                "parameter changed by a node restart",
+		},
+		{
+			dataNodeConfig: map[string]*intstr.IntOrString{
+				"DataMemory":           getIntStrPtr(intstr.FromString("200M")),
+				"NoOfFragmentLogFiles": getIntStrPtr(intstr.FromInt(32)),
+			},
+			expectedRestartType: v1.DataNodeInitialNodeRestart,
+			expectedParameters:  "nooffragmentlogfiles",
+			desc:                "parameter changed by an initial node restart",
+		},
+		{
+			dataNodeConfig: map[string]*intstr.IntOrString{
+				"NoOfFragmentLogFiles": getIntStrPtr(intstr.FromInt(32)),
+				"Diskless":             getIntStrPtr(intstr.FromInt(1)),
+			},
+			expectedRestartType: v1.DataNodeSystemRestart,
+			expectedParameters:  "diskless",
+			desc:                "parameter changed by a system restart",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			nc := ndb.DeepCopy()
+			nc.Spec.DataNode.Config = tc.dataNodeConfig
+			newConfigString, err := GetConfigString(nc, oldConfigSummary)
+			if err != nil {
+				t.Fatalf("Failed to generate config string from Ndb : %s", err)
+			}
+
+			restartType, parameters, err := GetDataNodeRestartType(oldConfigString, newConfigString)
+			if err != nil {
+				t.Fatalf("GetDataNodeRestartType failed : %s", err)
+			}
+
+			if restartType != tc.expectedRestartType {
+				t.Errorf("Expected restart type %q but got %q", tc.expectedRestartType, restartType)
+			}
+			if strings.Join(parameters, ",") != tc.expectedParameters {
+				t.Errorf("Expected parameters %q but got %q", tc.expectedParameters, parameters)
+			}
+		})
+	}
+
+	// Updating the redundancy level requires an initial system restart
+	ndb.Spec.DataNode.NodeCount = 6
+	oldConfigString, err = GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+	nc := ndb.DeepCopy()
+	nc.Spec.RedundancyLevel = 3
+	newConfigString, err := GetConfigString(nc, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+	restartType, parameters, err := GetDataNodeRestartType(oldConfigString, newConfigString)
+	if err != nil {
+		t.Fatalf("GetDataNodeRestartType failed : %s", err)
+	}
+	if restartType != v1.DataNodeInitialSystemRestart || strings.Join(parameters, ",") != "noofreplicas" {
+		t.Errorf("Expected an initial system restart for noofreplicas but got %q for %q", restartType, parameters)
+	}
+}
+
+func Test_MySQLClusterConfigNeedsUpdate_ClusterLog(t *testing.T) {
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	startupLevel := int32(15)
+	ndb.Spec.DataNode.LogLevels = &v1.NdbDataNodeLogLevels{
+		Startup: &startupLevel,
+	}
+
+	configString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	cs, err := NewConfigSummary(map[string]string{
+		constants.ConfigIniKey:           configString,
+		constants.NdbClusterGeneration:   "1",
+		constants.NumOfMySQLServers:      "2",
+		constants.ManagementLoadBalancer: "false",
+		constants.MySQLLoadBalancer:      "false",
+	})
+	if err != nil {
+		t.Fatalf("NewConfigSummary failed : %s", err)
+	}
+
+	errorIfNotEqualBool(t, false, cs.MySQLClusterConfigNeedsUpdate(ndb), "no change")
+
+	// Update the log level
+	startupLevel = 10
+	errorIfNotEqualBool(t, true, cs.MySQLClusterConfigNeedsUpdate(ndb), "log level updated")
+	startupLevel = 15
+
+	// Update the log destination
+	ndb.Spec.ManagementNode.LogDestination = "FILE:maxsize=10000000"
+	errorIfNotEqualBool(t, true, cs.MySQLClusterConfigNeedsUpdate(ndb), "log destination updated")
+}
+
+func Test_MySQLClusterConfigNeedsUpdate_ThreadConfig(t *testing.T) {
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.DataNode.ThreadConfig = "ldm={count=2},tc={count=1},main,recv,send"
+
+	configString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	if !strings.Contains(configString, "ThreadConfig=ldm={count=2},tc={count=1},main,recv,send\n") {
+		t.Errorf("Expected the ThreadConfig in the config string but got :\n%s", configString)
+	}
+
+	cs, err := NewConfigSummary(map[string]string{
+		constants.ConfigIniKey:           configString,
+		constants.NdbClusterGeneration:   "1",
+		constants.NumOfMySQLServers:      "2",
+		constants.ManagementLoadBalancer: "false",
+		constants.MySQLLoadBalancer:      "false",
+	})
+	if err != nil {
+		t.Fatalf("NewConfigSummary failed : %s", err)
+	}
+
+	errorIfNotEqualBool(t, false, cs.MySQLClusterConfigNeedsUpdate(ndb), "no change")
+
+	// Update the thread config
+	ndb.Spec.DataNode.ThreadConfig = "ldm={count=4},tc={count=2},main,recv,send"
+	errorIfNotEqualBool(t, true, cs.MySQLClusterConfigNeedsUpdate(ndb), "thread config updated")
+
+	// Remove the thread config
+	ndb.Spec.DataNode.ThreadConfig = ""
+	errorIfNotEqualBool(t, true, cs.MySQLClusterConfigNeedsUpdate(ndb), "thread config removed")
+}
+
+func Test_MySQLServerGroups(t *testing.T) {
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.MysqlNode.ServerGroups = []v1.NdbMysqldServerGroupSpec{
+		{Name: "oltp", NodeCount: 2},
+		{Name: "olap", NodeCount: 1, MyCnf: "max_connections=50"},
+	}
+
+	configString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	serverGroupsString, err := GetMySQLServerGroupsString(ndb)
+	if err != nil {
+		t.Fatalf("Failed to generate the MySQL Server groups string from Ndb : %s", err)
+	}
+
+	olapCnf, err := GetMySQLServerGroupConfigString(ndb.GetMySQLServerGroup("olap"), nil)
+	if err != nil {
+		t.Fatalf("Failed to generate the my.cnf of the MySQL Server group : %s", err)
+	}
+
+	cs, err := NewConfigSummary(map[string]string{
+		constants.ConfigIniKey:               configString,
+		constants.NdbClusterGeneration:       "1",
+		constants.NumOfMySQLServers:          "2",
+		constants.ManagementLoadBalancer:     "false",
+		constants.MySQLLoadBalancer:          "false",
+		constants.MySQLServerGroups:          serverGroupsString,
+		GetMySQLServerGroupConfigKey("olap"): olapCnf,
+	})
+	if err != nil {
+		t.Fatalf("NewConfigSummary failed : %s", err)
+	}
+
+	// The groups should follow the [mysqld] sections of the spec.mysqlNode
+	if len(cs.MySQLServerGroups) != 2 {
+		t.Fatalf("Expected 2 MySQL Server groups in the config summary but got %d", len(cs.MySQLServerGroups))
+	}
+	oltp := cs.GetMySQLServerGroup("oltp")
+	olap := cs.GetMySQLServerGroup("olap")
+	errorIfNotEqual(t, 2, oltp.NodeCount, "oltp.NodeCount")
+	errorIfNotEqual(t, 152, oltp.StartNodeId, "oltp.StartNodeId")
+	errorIfNotEqual(t, 1, olap.NodeCount, "olap.NodeCount")
+	errorIfNotEqual(t, 154, olap.StartNodeId, "olap.StartNodeId")
+	errorIfNotEqual(t, 0, oltp.MySQLServerConfigVersion, "oltp.MySQLServerConfigVersion")
+	errorIfNotEqual(t, 1, olap.MySQLServerConfigVersion, "olap.MySQLServerConfigVersion")
+	errorIfNotEqual(t, 7, cs.NumOfMySQLServerSlots, "cs.NumOfMySQLServerSlots")
+
+	// Every MySQL Server of the groups should have a [mysqld] section
+	for _, section := range []string{
+		"NodeId=152\nHostname=example-ndb-oltp-mysqld-0.example-ndb-oltp-mysqld.default",
+		"NodeId=153\nHostname=example-ndb-oltp-mysqld-1.example-ndb-oltp-mysqld.default",
+		"NodeId=154\nHostname=example-ndb-olap-mysqld-0.example-ndb-olap-mysqld.default",
+	} {
+		if !strings.Contains(configString, section) {
+			t.Errorf("The generated config string does not have the section :\n%s\n", section)
+		}
+	}
+
+	errorIfNotEqualBool(t, false, cs.MySQLClusterConfigNeedsUpdate(ndb), "no change")
+	needsUpdate, err := cs.MySQLServerGroupCnfNeedsUpdate(ndb.GetMySQLServerGroup("olap"))
+	if err != nil {
+		t.Fatalf("MySQLServerGroupCnfNeedsUpdate failed : %s", err)
+	}
+	errorIfNotEqualBool(t, false, needsUpdate, "my.cnf of the group not changed")
+
+	// Update the my.cnf of a group
+	ndb.Spec.MysqlNode.ServerGroups[1].MyCnf = "max_connections=100"
+	needsUpdate, err = cs.MySQLServerGroupCnfNeedsUpdate(ndb.GetMySQLServerGroup("olap"))
+	if err != nil {
+		t.Fatalf("MySQLServerGroupCnfNeedsUpdate failed : %s", err)
+	}
+	errorIfNotEqualBool(t, true, needsUpdate, "my.cnf of the group updated")
+
+	// Scale up a group
+	ndb.Spec.MysqlNode.ServerGroups[0].NodeCount = 3
+	errorIfNotEqualBool(t, true, cs.MySQLClusterConfigNeedsUpdate(ndb), "group scaled up")
+	ndb.Spec.MysqlNode.ServerGroups[0].NodeCount = 2
+
+	// Remove a group
+	ndb.Spec.MysqlNode.ServerGroups = ndb.Spec.MysqlNode.ServerGroups[:1]
+	errorIfNotEqualBool(t, true, cs.MySQLClusterConfigNeedsUpdate(ndb), "group removed")
+}
+
+func Test_GetConfigString_MemoryConfigs(t *testing.T) {
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	lockPagesInMainMemory := int32(1)
+	numa := false
+	ndb.Spec.DataNode.LockPagesInMainMemory = &lockPagesInMainMemory
+	ndb.Spec.DataNode.Numa = &numa
+
+	configString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	for _, config := range []string{"LockPagesInMainMemory=1\n", "Numa=0\n"} {
+		if !strings.Contains(configString, config) {
+			t.Errorf("Expected %q in the config string but got :\n%s", config, configString)
+		}
+	}
+}
+
+func Test_GetConfigString_SeparateVolumes(t *testing.T) {
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.DataNode.SeparateVolumes = &v1.NdbDataNodeVolumesSpec{
+		FileSystem: &corev1.PersistentVolumeClaimSpec{},
+		Backup:     &corev1.PersistentVolumeClaimSpec{},
+		UndoFiles:  &corev1.PersistentVolumeClaimSpec{},
+	}
+
+	configString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	for _, config := range []string{
+		"FileSystemPath=/var/lib/ndb/filesystem\n",
+		"FileSystemPathDataFiles=/var/lib/ndb/data\n",
+		"BackupDataDir=/var/lib/ndb/backup\n",
+		"FileSystemPathUndoFiles=/var/lib/ndb/undo\n",
+	} {
+		if !strings.Contains(configString, config) {
+			t.Errorf("Expected %q in the config string but got :\n%s", config, configString)
+		}
+	}
+}
+
+func Test_GetConfigString_CompressedBackup(t *testing.T) {
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	configString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+	if strings.Contains(configString, "CompressedBackup") {
+		t.Errorf("Unexpected CompressedBackup in the config string :\n%s", configString)
+	}
+
+	ndb.Spec.Backup = &v1.NdbBackupSpec{Compression: true}
+	configString, err = GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+	if !strings.Contains(configString, "CompressedBackup=1\n") {
+		t.Errorf("Expected CompressedBackup=1 in the config string but got :\n%s", configString)
+	}
+}
+
+func Test_GetConfigString_IPv6(t *testing.T) {
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	configString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+	if strings.Contains(configString, "PreferIPVersion") {
+		t.Error("PreferIPVersion should not be set when no IP families are specified")
+	}
+
+	// IPv6 as the primary IP family
+	ndb.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}
+	configString, err = GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+	if !strings.Contains(configString, "[tcp default]\nAllowUnresolvedHostnames=1\n"+
+		"# Resolve the hostnames to IPv6 addresses as IPv6 is the primary IP family\nPreferIPVersion=6\n") {
+		t.Errorf("PreferIPVersion is not set in the [tcp default] section :\n%s", configString)
+	}
+}
+
+func Test_GetConfigString_HostNetwork(t *testing.T) {
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.DataNode.HostNetwork = true
+	configString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	// The data nodes should use a ServerPort that will
+	// not conflict with a management node on the same host
+	expectedServerPort := fmt.Sprintf("ServerPort=%d\n", constants.DataNodeHostNetworkServerPort)
+	if !strings.Contains(configString, expectedServerPort) {
+		t.Errorf("Expected %q in the config string :\n%s", expectedServerPort, configString)
+	}
+}
+
+func Test_GetConfigString_Ports(t *testing.T) {
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	configString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+	// PortNumber should not be set for the default port
+	if strings.Contains(configString, "PortNumber=") {
+		t.Errorf("Unexpected PortNumber in the config string :\n%s", configString)
+	}
+
+	ndb.Spec.ManagementNode.Port = 1187
+	ndb.Spec.DataNode.ServerPort = 1188
+	ndb.Spec.Arbitrator = &v1.NdbArbitratorSpec{}
+	configString, err = GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	config, err := configparser.ParseString(configString)
+	if err != nil {
+		t.Fatalf("Failed to parse the config string : %s", err)
+	}
+
+	// All the Management nodes, including the arbitrator, should use the given port
+	for i, section := range config.GetAllSections("ndb_mgmd") {
+		if portNumber, _ := section.GetValue("PortNumber"); portNumber != "1187" {
+			t.Errorf("Expected PortNumber 1187 in [ndb_mgmd] section %d but got %q", i, portNumber)
+		}
+	}
+	if !strings.Contains(configString, "ServerPort=1188\n") {
+		t.Errorf("Expected ServerPort=1188 in the config string :\n%s", configString)
+	}
+}
+
+func Test_GetConfigString_LocationDomains(t *testing.T) {
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.LocationDomainTopologyKey = corev1.LabelTopologyZone
+	locationDomainZones := map[string]string{
+		"example-ndb-mgmd-0":   "zone-b",
+		"example-ndb-ndbmtd-0": "zone-a",
+		"example-ndb-ndbmtd-1": "zone-b",
+		"example-ndb-mysqld-1": "zone-a",
+	}
+
+	configString, err := GetConfigStringWithLocationDomains(ndb, nil, locationDomainZones)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	config, err := configparser.ParseString(configString)
+	if err != nil {
+		t.Fatalf("Failed to parse the config string : %s", err)
+	}
+
+	// The zones should be assigned the ids in their sorted order
+	for _, tc := range []struct {
+		sectionName              string
+		index                    int
+		expectedLocationDomainId string
+	}{
+		{"ndb_mgmd", 0, "2"},
+		{"ndbd", 0, "1"},
+		{"ndbd", 1, "2"},
+		{"mysqld", 0, ""},
+		{"mysqld", 1, "1"},
+	} {
+		section := config.GetAllSections(tc.sectionName)[tc.index]
+		locationDomainId, _ := section.GetValue("LocationDomainId")
+		if locationDomainId != tc.expectedLocationDomainId {
+			t.Errorf("Expected LocationDomainId of [%s] section %d to be %q but got %q",
+				tc.sectionName, tc.index, tc.expectedLocationDomainId, locationDomainId)
+		}
+	}
+
+	cs, err := NewConfigSummary(map[string]string{
+		constants.ConfigIniKey:           configString,
+		constants.NdbClusterGeneration:   "1",
+		constants.NumOfMySQLServers:      "2",
+		constants.ManagementLoadBalancer: "false",
+		constants.MySQLLoadBalancer:      "false",
+		constants.LocationDomainZones:    `{"example-ndb-mgmd-0":"zone-b","example-ndb-ndbmtd-0":"zone-a"}`,
+	})
+	if err != nil {
+		t.Fatalf("NewConfigSummary failed : %s", err)
+	}
+	errorIfNotEqual(t, 2, int32(len(cs.LocationDomainZones)), "len(cs.LocationDomainZones)")
+	errorIfNotEqualBool(t, false, cs.MySQLClusterConfigNeedsUpdate(ndb), "location domains enabled")
+
+	// The config should be updated once the location domains are disabled
+	ndb.Spec.LocationDomainTopologyKey = ""
+	errorIfNotEqualBool(t, true, cs.MySQLClusterConfigNeedsUpdate(ndb), "location domains disabled")
+	if configString, err = GetConfigString(ndb, cs); err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+	if strings.Contains(configString, "LocationDomainId") {
+		t.Errorf("LocationDomainId should not be set when the location domains are disabled :\n%s", configString)
+	}
+}
+
+func Test_GetConfigString_Arbitrator(t *testing.T) {
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.Arbitrator = &v1.NdbArbitratorSpec{Zone: "zone-c"}
+	configString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	config, err := configparser.ParseString(configString)
+	if err != nil {
+		t.Fatalf("Failed to parse the config string : %s", err)
+	}
+
+	// The arbitrator should have a higher ArbitrationRank than the other Management nodes
+	mgmdSections := config.GetAllSections("ndb_mgmd")
+	if len(mgmdSections) != 3 {
+		t.Fatalf("Expected 3 [ndb_mgmd] sections but got %d :\n%s", len(mgmdSections), configString)
+	}
+	for i, tc := range []struct {
+		expectedNodeId          string
+		expectedArbitrationRank string
+	}{
+		{"1", "2"},
+		{"2", "2"},
+		{fmt.Sprintf("%d", constants.ArbitratorNodeId), "1"},
+	} {
+		nodeId, _ := mgmdSections[i].GetValue("NodeId")
+		arbitrationRank, _ := mgmdSections[i].GetValue("ArbitrationRank")
+		if nodeId != tc.expectedNodeId || arbitrationRank != tc.expectedArbitrationRank {
+			t.Errorf("Expected [ndb_mgmd] section %d to have NodeId %s and ArbitrationRank %s but got %s and %s",
+				i, tc.expectedNodeId, tc.expectedArbitrationRank, nodeId, arbitrationRank)
+		}
+	}
+
+	// The arbitrator should not be counted as a Management node
+	cs, err := NewConfigSummary(map[string]string{
+		constants.ConfigIniKey:           configString,
+		constants.NdbClusterGeneration:   "1",
+		constants.NumOfMySQLServers:      "2",
+		constants.ManagementLoadBalancer: "false",
+		constants.MySQLLoadBalancer:      "false",
+	})
+	if err != nil {
+		t.Fatalf("NewConfigSummary failed : %s", err)
+	}
+	errorIfNotEqual(t, 2, cs.NumOfManagementNodes, "cs.NumOfManagementNodes")
+	errorIfNotEqualBool(t, true, cs.HasArbitrator, "cs.HasArbitrator")
+}
+
+func Test_GetConfigString_NdbAPIApplications(t *testing.T) {
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.FreeAPISlots = 2
+	ndb.Spec.NdbAPIApplications = []v1.NdbAPIApplicationSpec{
+		{Name: "loader", Namespace: "apps", NodeCount: 1, ConnectionPoolSize: 2},
+		{Name: "reader", Namespace: "apps", NodeCount: 2},
+	}
+	configString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	config, err := configparser.ParseString(configString)
+	if err != nil {
+		t.Fatalf("Failed to parse the config string : %s", err)
+	}
+
+	// The sections of the applications should be reserved
+	// from the highest nodeId downwards with the pod hostnames
+	expectedHostnames := map[string]string{
+		"255": "loader-0.loader.apps",
+		"254": "loader-0.loader.apps",
+		"253": "reader-0.reader.apps",
+		"252": "reader-1.reader.apps",
+	}
+	hostnames := make(map[string]string)
+	for _, apiSection := range config.GetAllSections("api") {
+		if hostname, exists := apiSection.GetValue("HostName"); exists {
+			nodeId, _ := apiSection.GetValue("NodeId")
+			hostnames[nodeId] = strings.SplitN(hostname, ".svc.", 2)[0]
+		}
+	}
+	if !reflect.DeepEqual(hostnames, expectedHostnames) {
+		t.Errorf("Expected the application [api] sections %v but got %v", expectedHostnames, hostnames)
+	}
+
+	// The application sections should not be counted as free API slots
+	// and the config should not need an update until they are changed
+	cs, err := NewConfigSummary(map[string]string{
+		constants.ConfigIniKey:           configString,
+		constants.NdbClusterGeneration:   "1",
+		constants.NumOfMySQLServers:      "2",
+		constants.ManagementLoadBalancer: "false",
+		constants.MySQLLoadBalancer:      "false",
+	})
+	if err != nil {
+		t.Fatalf("NewConfigSummary failed : %s", err)
+	}
+	errorIfNotEqual(t, 3, cs.NumOfFreeApiSlots, "cs.NumOfFreeApiSlots")
+	if cs.MySQLClusterConfigNeedsUpdate(ndb) {
+		t.Error("MySQLClusterConfigNeedsUpdate returned true for an unchanged spec")
+	}
+
+	ndb.Spec.NdbAPIApplications[1].NodeCount = 3
+	if !cs.MySQLClusterConfigNeedsUpdate(ndb) {
+		t.Error("MySQLClusterConfigNeedsUpdate returned false when an application was scaled")
+	}
+}
+
+func Test_RestartRequests(t *testing.T) {
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	configString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	newConfigSummary := func(restartRequestsString string) *ConfigSummary {
+		t.Helper()
+		cs, err := NewConfigSummary(map[string]string{
+			constants.ConfigIniKey:           configString,
+			constants.NdbClusterGeneration:   "1",
+			constants.NumOfMySQLServers:      "2",
+			constants.ManagementLoadBalancer: "false",
+			constants.MySQLLoadBalancer:      "false",
+			constants.RestartRequests:        restartRequestsString,
+		})
+		if err != nil {
+			t.Fatalf("NewConfigSummary failed : %s", err)
+		}
+		return cs
+	}
+
+	// No restarts have been requested yet
+	cs := newConfigSummary("")
+	errorIfNotEqualBool(t, false, cs.RestartRequestsNeedUpdate(ndb), "no restart requests")
+
+	// Request a restart of the Management nodes and an initial restart of the data nodes
+	ndb.Annotations = map[string]string{
+		v1.RestartAnnotationPrefix + constants.NdbNodeTypeMgmd: "1",
+		v1.InitialRestartAnnotation:                            "1",
+		"unrelated-annotation":                                 "1",
+	}
+	errorIfNotEqualBool(t, true, cs.RestartRequestsNeedUpdate(ndb), "new restart requests")
+
+	restartRequestsString, err := GetRestartRequestsString(ndb, cs)
+	if err != nil {
+		t.Fatalf("GetRestartRequestsString failed : %s", err)
+	}
+	cs = newConfigSummary(restartRequestsString)
+	errorIfNotEqualBool(t, false, cs.RestartRequestsNeedUpdate(ndb), "applied restart requests")
+
+	for _, tc := range []struct {
+		nodeType                      constants.NdbNodeType
+		expectedRestartRequest        string
+		expectedInitialRestartRequest string
+	}{
+		{constants.NdbNodeTypeMgmd, "1", ""},
+		{constants.NdbNodeTypeArbitrator, "1", ""},
+		{constants.NdbNodeTypeNdbmtd, "", "1"},
+		{constants.NdbNodeTypeMySQLD, "", ""},
+	} {
+		if restartRequest := cs.GetRestartRequest(tc.nodeType); restartRequest != tc.expectedRestartRequest {
+			t.Errorf("Expected restart request of %s to be %q but got %q",
+				tc.nodeType, tc.expectedRestartRequest, restartRequest)
+		}
+		if restartRequest := cs.GetInitialRestartRequest(tc.nodeType); restartRequest != tc.expectedInitialRestartRequest {
+			t.Errorf("Expected initial restart request of %s to be %q but got %q",
+				tc.nodeType, tc.expectedInitialRestartRequest, restartRequest)
+		}
+	}
+
+	// Removing an annotation should retain the applied request
+	delete(ndb.Annotations, v1.InitialRestartAnnotation)
+	errorIfNotEqualBool(t, false, cs.RestartRequestsNeedUpdate(ndb), "removed restart request")
+	if restartRequestsString, err = GetRestartRequestsString(ndb, cs); err != nil {
+		t.Fatalf("GetRestartRequestsString failed : %s", err)
+	}
+	cs = newConfigSummary(restartRequestsString)
+	if restartRequest := cs.GetInitialRestartRequest(constants.NdbNodeTypeNdbmtd); restartRequest != "1" {
+		t.Errorf("Expected the removed initial restart request to be retained but got %q", restartRequest)
+	}
+
+	// A new value is a new request
+	ndb.Annotations[v1.RestartAnnotationPrefix+constants.NdbNodeTypeMgmd] = "2"
+	errorIfNotEqualBool(t, true, cs.RestartRequestsNeedUpdate(ndb), "repeated restart request")
+
+	// Request restarts of single data nodes, including an invalid one
+	firstDataNodeId := int(cs.NumOfManagementNodes) + 1
+	ndb.Annotations = map[string]string{
+		fmt.Sprintf("%s%d", v1.DataNodeRestartAnnotationPrefix, firstDataNodeId+1): "1",
+		fmt.Sprintf("%s%d", v1.DataNodeRestartAnnotationPrefix, firstDataNodeId):   "1",
+		v1.DataNodeRestartAnnotationPrefix + "invalid":                             "1",
+	}
+	nodeId, restartRequest := cs.GetPendingDataNodeRestartRequest(ndb)
+	if nodeId != firstDataNodeId || restartRequest != "1" {
+		t.Fatalf("Expected a pending restart request of data node %d but got %d, %q", firstDataNodeId, nodeId, restartRequest)
+	}
+
+	// Apply the requests one at a time
+	for _, expectedNodeId := range []int{firstDataNodeId + 1, 0} {
+		if restartRequestsString, err = GetDataNodeRestartRequestString(cs, nodeId, restartRequest); err != nil {
+			t.Fatalf("GetDataNodeRestartRequestString failed : %s", err)
+		}
+		cs = newConfigSummary(restartRequestsString)
+		if nodeId, restartRequest = cs.GetPendingDataNodeRestartRequest(ndb); nodeId != expectedNodeId {
+			t.Fatalf("Expected a pending restart request of data node %d but got %d", expectedNodeId, nodeId)
+		}
+	}
+	if cs.GetRestartRequest(constants.NdbNodeTypeMgmd) != "1" {
+		t.Errorf("Expected the applied restart requests to be retained")
+	}
+}
+
+func Test_GetConfigChanges(t *testing.T) {
+	oldData := map[string]string{
+		constants.ConfigIniKey: `
+[system]
+ConfigGenerationNumber=1
+[ndbd default]
+NoOfReplicas=2
+DataMemory=98M
+[ndbd]
+NodeId=3
+Hostname=ndbmtd-0
+[ndbd]
+NodeId=4
+Hostname=ndbmtd-1
+`,
+		constants.NumOfMySQLServers: "2",
+		constants.MySQLConfigKey: `# ConfigVersion=1
+[mysqld]
+max-user-connections=42
+`,
+	}
+	newData := map[string]string{
+		constants.ConfigIniKey: `
+[system]
+ConfigGenerationNumber=2
+[ndbd default]
+NoOfReplicas=2
+DataMemory=200M
+MaxNoOfTables=1024
+[ndbd]
+NodeId=3
+Hostname=ndbmtd-0
+[ndbd]
+NodeId=4
+Hostname=ndbmtd-1
+[ndbd]
+NodeId=5
+Hostname=ndbmtd-2
+[ndbd]
+NodeId=6
+Hostname=ndbmtd-3
+`,
+		constants.NumOfMySQLServers: "3",
+		constants.MySQLConfigKey: `# ConfigVersion=2
+[mysqld]
+`,
+		GetMySQLServerGroupConfigKey("analytics"): `# ConfigVersion=1
+[mysqld]
+max-user-connections=10
+`,
+	}
+
+	changes, err := GetConfigChanges(oldData, newData)
+	if err != nil {
+		t.Fatalf("GetConfigChanges failed : %s", err)
+	}
+
+	expectedChanges := []string{
+		"Data nodes changed from 2 to 4",
+		"MySQL Servers changed from 2 to 3",
+		"config.ini [ndbd default] datamemory changed from 98M to 200M",
+		"config.ini [ndbd default] maxnooftables added with value 1024",
+		"my-analytics.cnf [mysqld] max-user-connections added with value 10",
+		"my.cnf [mysqld] max-user-connections removed (was 42)",
+	}
+	if !reflect.DeepEqual(changes, expectedChanges) {
+		t.Errorf("Expected changes :\n%s\nbut got :\n%s",
+			strings.Join(expectedChanges, "\n"), strings.Join(changes, "\n"))
+	}
+
+	// No changes
+	if changes, err = GetConfigChanges(newData, newData); err != nil || len(changes) != 0 {
+		t.Errorf("Expected no changes but got %v, %v", changes, err)
+	}
+}
+
+func Test_ConfigSummary_Nodes(t *testing.T) {
+
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.MysqlNode.ConnectionPoolSize = 2
+	ndb.Spec.Arbitrator = &v1.NdbArbitratorSpec{}
+	configString, err := GetConfigString(ndb, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	cs, err := NewConfigSummary(map[string]string{
+		constants.ConfigIniKey:           configString,
+		constants.NdbClusterGeneration:   "1",
+		constants.NumOfMySQLServers:      "2",
+		constants.ManagementLoadBalancer: "false",
+		constants.MySQLLoadBalancer:      "false",
+	})
+	if err != nil {
+		t.Fatalf("NewConfigSummary failed : %s", err)
+	}
+
+	for _, tc := range []struct {
+		nodeType        constants.NdbNodeType
+		expectedNodeIds []int
+	}{
+		{constants.NdbNodeTypeMgmd, []int{1, 2}},
+		{constants.NdbNodeTypeNdbmtd, []int{3, 4}},
+		{constants.NdbNodeTypeArbitrator, []int{constants.ArbitratorNodeId}},
+	} {
+		if nodeIds := cs.GetNodeIds(tc.nodeType); !reflect.DeepEqual(nodeIds, tc.expectedNodeIds) {
+			t.Errorf("Expected the %s nodeIds to be %v but got %v", tc.nodeType, tc.expectedNodeIds, nodeIds)
+		}
+	}
+
+	// The free API slots should not be included as they are not run by any pod
+	if nodeIds := cs.GetNodeIds(constants.NdbNodeTypeAPI); len(nodeIds) != 0 {
+		t.Errorf("Expected no API nodeIds but got %v", nodeIds)
+	}
+
+	// Every MySQL Server pod should get connectionPoolSize number of successive nodeIds
+	firstMySQLServerNodeId := constants.NdbNodeTypeAPIStartNodeId
+	expectedNodeIds := []int{firstMySQLServerNodeId, firstMySQLServerNodeId + 1}
+	if nodeIds := cs.GetPodNodeIds("example-ndb-mysqld-0"); !reflect.DeepEqual(nodeIds, expectedNodeIds) {
+		t.Errorf("Expected the nodeIds of the first MySQL Server to be %v but got %v", expectedNodeIds, nodeIds)
+	}
+
+	if firstDataNodeId := cs.GetFirstDataNodeId(); firstDataNodeId != 3 {
+		t.Errorf("Expected the first data node to have the nodeId 3 but got %d", firstDataNodeId)
+	}
+
+	if podName := cs.GetNodePodName(4); podName != "example-ndb-ndbmtd-1" {
+		t.Errorf("Expected the nodeId 4 to be run by the pod %q but got %q", "example-ndb-ndbmtd-1", podName)
+	}
+	if podName := cs.GetNodePodName(constants.NdbOperatorDedicatedAPINodeId); podName != "" {
+		t.Errorf("Expected the operator's nodeId not to be run by any pod but got %q", podName)
+	}
+}
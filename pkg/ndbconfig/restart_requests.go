@@ -0,0 +1,123 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package ndbconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+)
+
+// GetRestartRequestsString returns the restart requests made via the
+// annotations of the given NdbCluster, along with the ones applied to
+// the old config, to be stored in the config map. The requests whose
+// annotations have been removed are retained, so that removing an
+// annotation does not restart the nodes again.
+func GetRestartRequestsString(nc *v1.NdbCluster, oldConfigSummary *ConfigSummary) (string, error) {
+	restartRequests := make(map[string]string)
+	if oldConfigSummary != nil {
+		for key, value := range oldConfigSummary.RestartRequests {
+			restartRequests[key] = value
+		}
+	}
+	for key, value := range nc.GetRestartRequests() {
+		restartRequests[key] = value
+	}
+
+	return marshalRestartRequests(restartRequests)
+}
+
+// GetDataNodeRestartRequestString returns the restart requests applied
+// to the old config along with the given request for a restart of the
+// data node with the given node id, to be stored in the config map.
+func GetDataNodeRestartRequestString(
+	oldConfigSummary *ConfigSummary, nodeId int, restartRequest string) (string, error) {
+	restartRequests := make(map[string]string)
+	for key, value := range oldConfigSummary.RestartRequests {
+		restartRequests[key] = value
+	}
+	restartRequests[fmt.Sprintf("%s%d", v1.DataNodeRestartAnnotationPrefix, nodeId)] = restartRequest
+
+	return marshalRestartRequests(restartRequests)
+}
+
+// marshalRestartRequests returns the given restart requests as a JSON string
+func marshalRestartRequests(restartRequests map[string]string) (string, error) {
+	if len(restartRequests) == 0 {
+		return "", nil
+	}
+
+	restartRequestsBytes, err := json.Marshal(restartRequests)
+	if err != nil {
+		return "", err
+	}
+
+	return string(restartRequestsBytes), nil
+}
+
+// RestartRequestsNeedUpdate returns true if the NdbCluster has
+// restart requests that have not been applied to the config yet.
+func (cs *ConfigSummary) RestartRequestsNeedUpdate(nc *v1.NdbCluster) bool {
+	for key, value := range nc.GetRestartRequests() {
+		if cs.RestartRequests[key] != value {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPendingDataNodeRestartRequest returns the node id of the data node
+// with the lowest node id that is yet to be restarted for a request made
+// via the NdbCluster annotations, along with the request. The node id is
+// 0 if there are no pending requests. Requests made for node ids that do
+// not belong to a data node are ignored.
+func (cs *ConfigSummary) GetPendingDataNodeRestartRequest(nc *v1.NdbCluster) (int, string) {
+	firstDataNodeId := cs.GetFirstDataNodeId()
+	var pendingNodeIds []int
+	for key, value := range nc.GetRestartRequests() {
+		if !strings.HasPrefix(key, v1.DataNodeRestartAnnotationPrefix) || cs.RestartRequests[key] == value {
+			continue
+		}
+
+		nodeId, err := strconv.Atoi(strings.TrimPrefix(key, v1.DataNodeRestartAnnotationPrefix))
+		if err != nil || nodeId < firstDataNodeId || nodeId >= firstDataNodeId+int(cs.NumOfDataNodes) {
+			continue
+		}
+		pendingNodeIds = append(pendingNodeIds, nodeId)
+	}
+
+	if len(pendingNodeIds) == 0 {
+		return 0, ""
+	}
+
+	sort.Ints(pendingNodeIds)
+	nodeId := pendingNodeIds[0]
+	return nodeId, nc.Annotations[fmt.Sprintf("%s%d", v1.DataNodeRestartAnnotationPrefix, nodeId)]
+}
+
+// GetRestartRequest returns the last applied request for a rolling
+// restart of the nodes of the given type, or an empty string if there
+// is none. The external arbitrator is restarted with the Management nodes.
+func (cs *ConfigSummary) GetRestartRequest(nodeType constants.NdbNodeType) string {
+	if nodeType == constants.NdbNodeTypeArbitrator {
+		nodeType = constants.NdbNodeTypeMgmd
+	}
+	return cs.RestartRequests[v1.RestartAnnotationPrefix+nodeType]
+}
+
+// GetInitialRestartRequest returns the last applied request for a
+// rolling initial restart of the nodes of the given type, or an empty
+// string if there is none. Only the data nodes can be initially restarted.
+func (cs *ConfigSummary) GetInitialRestartRequest(nodeType constants.NdbNodeType) string {
+	if nodeType != constants.NdbNodeTypeNdbmtd {
+		return ""
+	}
+	return cs.RestartRequests[v1.InitialRestartAnnotation]
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Package placement plans the placement of the MySQL Cluster
+// data nodes across the zones of a K8s Cluster and verifies
+// the actual placement of the data node pods against it.
+package placement
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// DataNodeAssignment is the node group and the zone
+// assigned to the data node with the given ordinal.
+type DataNodeAssignment struct {
+	// Ordinal is the ordinal index of the data node pod
+	Ordinal int32
+	// NodeGroup is the node group of the data node
+	NodeGroup int32
+	// Zone is the zone in which the data node is to be placed
+	Zone string
+}
+
+// Plan is the placement plan of the data nodes of a MySQL Cluster
+type Plan struct {
+	// Assignments are the assignments of the data nodes, in the order of their ordinals
+	Assignments []DataNodeAssignment
+	// zones are the zones used by the plan
+	zones map[string]bool
+}
+
+// NewPlan plans the placement of the given number of data nodes across
+// the given zones. Like the operator does in the MySQL Cluster config,
+// the data nodes are assigned to the node groups in the order of their
+// ordinals, redundancyLevel data nodes at a time. The data nodes are
+// assigned to the zones in a round-robin order of their ordinals, so
+// that the data nodes of every node group are placed in distinct zones.
+// An error is returned if such a plan is not possible.
+func NewPlan(numOfDataNodes, redundancyLevel int32, zones []string) (*Plan, error) {
+	if redundancyLevel <= 0 {
+		return nil, errors.New("redundancy level should be greater than 0")
+	}
+
+	if numOfDataNodes%redundancyLevel != 0 {
+		return nil, fmt.Errorf(
+			"number of data nodes(=%d) should be a multiple of the redundancy level(=%d)",
+			numOfDataNodes, redundancyLevel)
+	}
+
+	plan := &Plan{
+		zones: make(map[string]bool, len(zones)),
+	}
+	for _, zone := range zones {
+		if zone == "" {
+			return nil, errors.New("zone cannot be empty")
+		}
+		if plan.zones[zone] {
+			return nil, fmt.Errorf("zone %q is specified more than once", zone)
+		}
+		plan.zones[zone] = true
+	}
+
+	if int32(len(zones)) < redundancyLevel {
+		return nil, fmt.Errorf(
+			"the data nodes of a node group cannot be placed in distinct zones "+
+				"as the number of zones(=%d) is less than the redundancy level(=%d)",
+			len(zones), redundancyLevel)
+	}
+
+	plan.Assignments = make([]DataNodeAssignment, numOfDataNodes)
+	for ordinal := int32(0); ordinal < numOfDataNodes; ordinal++ {
+		plan.Assignments[ordinal] = DataNodeAssignment{
+			Ordinal:   ordinal,
+			NodeGroup: ordinal / redundancyLevel,
+			Zone:      zones[int(ordinal)%len(zones)],
+		}
+	}
+
+	return plan, nil
+}
+
+// Verify verifies the actual placement of the data nodes, given as
+// a map of their ordinals to their zones, and returns the violations
+// found. A data node placed outside the planned zones or a node group
+// with more than one data node in a zone are violations. Data nodes
+// missing from the map are yet to be placed and are not verified.
+func (p *Plan) Verify(dataNodeZones map[int32]string) (violations []string) {
+	// zones of every node group mapped to the ordinals placed in them
+	nodeGroupZones := make(map[int32]map[string][]int32)
+	for _, assignment := range p.Assignments {
+		zone, placed := dataNodeZones[assignment.Ordinal]
+		if !placed {
+			continue
+		}
+
+		if !p.zones[zone] {
+			violations = append(violations, fmt.Sprintf(
+				"data node %d is placed in zone %q, which is not one of the planned zones",
+				assignment.Ordinal, zone))
+		}
+
+		if nodeGroupZones[assignment.NodeGroup] == nil {
+			nodeGroupZones[assignment.NodeGroup] = make(map[string][]int32)
+		}
+		nodeGroupZones[assignment.NodeGroup][zone] = append(
+			nodeGroupZones[assignment.NodeGroup][zone], assignment.Ordinal)
+	}
+
+	// Report the node groups in the order of their ids
+	nodeGroups := make([]int32, 0, len(nodeGroupZones))
+	for nodeGroup := range nodeGroupZones {
+		nodeGroups = append(nodeGroups, nodeGroup)
+	}
+	sort.Slice(nodeGroups, func(i, j int) bool { return nodeGroups[i] < nodeGroups[j] })
+
+	for _, nodeGroup := range nodeGroups {
+		zones := make([]string, 0, len(nodeGroupZones[nodeGroup]))
+		for zone := range nodeGroupZones[nodeGroup] {
+			zones = append(zones, zone)
+		}
+		sort.Strings(zones)
+
+		for _, zone := range zones {
+			if ordinals := nodeGroupZones[nodeGroup][zone]; len(ordinals) > 1 {
+				violations = append(violations, fmt.Sprintf(
+					"data nodes %v of node group %d are placed in the same zone %q",
+					ordinals, nodeGroup, zone))
+			}
+		}
+	}
+
+	return violations
+}
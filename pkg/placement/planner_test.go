@@ -0,0 +1,142 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package placement
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_NewPlan(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		numOfDataNodes  int32
+		redundancyLevel int32
+		zones           []string
+		expectedZones   []string
+		shouldFail      bool
+	}{
+		{
+			desc:            "two zones with redundancy 2",
+			numOfDataNodes:  4,
+			redundancyLevel: 2,
+			zones:           []string{"zone-a", "zone-b"},
+			expectedZones:   []string{"zone-a", "zone-b", "zone-a", "zone-b"},
+		},
+		{
+			desc:            "three zones with redundancy 2",
+			numOfDataNodes:  4,
+			redundancyLevel: 2,
+			zones:           []string{"zone-a", "zone-b", "zone-c"},
+			expectedZones:   []string{"zone-a", "zone-b", "zone-c", "zone-a"},
+		},
+		{
+			desc:            "fewer zones than redundancy",
+			numOfDataNodes:  3,
+			redundancyLevel: 3,
+			zones:           []string{"zone-a", "zone-b"},
+			shouldFail:      true,
+		},
+		{
+			desc:            "duplicate zones",
+			numOfDataNodes:  2,
+			redundancyLevel: 2,
+			zones:           []string{"zone-a", "zone-a"},
+			shouldFail:      true,
+		},
+		{
+			desc:            "data nodes not a multiple of redundancy",
+			numOfDataNodes:  3,
+			redundancyLevel: 2,
+			zones:           []string{"zone-a", "zone-b"},
+			shouldFail:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		plan, err := NewPlan(tc.numOfDataNodes, tc.redundancyLevel, tc.zones)
+		if tc.shouldFail {
+			if err == nil {
+				t.Errorf("Case %q : expected an error but got none", tc.desc)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Case %q : unexpected error : %s", tc.desc, err)
+			continue
+		}
+
+		zones := make([]string, len(plan.Assignments))
+		for i, assignment := range plan.Assignments {
+			zones[i] = assignment.Zone
+			if assignment.NodeGroup != assignment.Ordinal/tc.redundancyLevel {
+				t.Errorf("Case %q : data node %d is assigned to the wrong node group %d",
+					tc.desc, assignment.Ordinal, assignment.NodeGroup)
+			}
+		}
+		if !reflect.DeepEqual(zones, tc.expectedZones) {
+			t.Errorf("Case %q : expected the zones %v but got %v", tc.desc, tc.expectedZones, zones)
+		}
+	}
+}
+
+func Test_Plan_Verify(t *testing.T) {
+	plan, err := NewPlan(4, 2, []string{"zone-a", "zone-b", "zone-c"})
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	testCases := []struct {
+		desc               string
+		dataNodeZones      map[int32]string
+		expectedViolations []string
+	}{
+		{
+			desc: "placed as planned",
+			dataNodeZones: map[int32]string{
+				0: "zone-a", 1: "zone-b", 2: "zone-c", 3: "zone-a",
+			},
+		},
+		{
+			desc: "placed in other planned zones",
+			dataNodeZones: map[int32]string{
+				0: "zone-c", 1: "zone-a", 2: "zone-b", 3: "zone-c",
+			},
+		},
+		{
+			desc: "some data nodes yet to be placed",
+			dataNodeZones: map[int32]string{
+				0: "zone-a", 3: "zone-a",
+			},
+		},
+		{
+			desc: "node group in the same zone",
+			dataNodeZones: map[int32]string{
+				0: "zone-a", 1: "zone-b", 2: "zone-b", 3: "zone-b",
+			},
+			expectedViolations: []string{
+				`data nodes [2 3] of node group 1 are placed in the same zone "zone-b"`,
+			},
+		},
+		{
+			desc: "data node outside the planned zones",
+			dataNodeZones: map[int32]string{
+				0: "zone-d", 1: "zone-b",
+			},
+			expectedViolations: []string{
+				`data node 0 is placed in zone "zone-d", which is not one of the planned zones`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		violations := plan.Verify(tc.dataNodeZones)
+		if !reflect.DeepEqual(violations, tc.expectedViolations) {
+			t.Errorf("Case %q : expected the violations %v but got %v",
+				tc.desc, tc.expectedViolations, violations)
+		}
+	}
+}
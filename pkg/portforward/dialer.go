@@ -0,0 +1,199 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package portforward
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	klog "k8s.io/klog/v2"
+)
+
+// DialContextFunc is the signature of the functions
+// used to open connections to the MySQL Cluster nodes
+type DialContextFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// forwarder is a port forwarder running in the
+// background from a local port to a port of a pod
+type forwarder struct {
+	localAddress string
+	stopChan     chan struct{}
+}
+
+// Dialer opens connections to the MySQL Cluster nodes from outside
+// the K8s Cluster. The nodes are addressed by the DNS names of their
+// pods, which cannot be resolved outside the K8s Cluster, and, when
+// the nodes cannot be reached directly (e.g. in a kind or a minikube
+// cluster without a load balancer), the Dialer falls back to
+// forwarding a local port to the pod via the K8s API Server.
+type Dialer struct {
+	config *rest.Config
+	client kubernetes.Interface
+	// directDial opens a connection without port-forwarding
+	directDial DialContextFunc
+
+	lock sync.Mutex
+	// forwarders are the running port forwarders mapped by the pod address
+	forwarders map[string]*forwarder
+}
+
+// NewDialer returns a new Dialer that port-forwards
+// using the given K8s client configuration.
+func NewDialer(config *rest.Config, client kubernetes.Interface) *Dialer {
+	return &Dialer{
+		config:     config,
+		client:     client,
+		directDial: (&net.Dialer{}).DialContext,
+		forwarders: make(map[string]*forwarder),
+	}
+}
+
+// parsePodAddress extracts the namespace, the pod name and the port from
+// the given address if it is of form <pod>.<service>.<namespace>[.svc...]:<port>.
+// isPodAddress is false if the address doesn't refer to a pod.
+func parsePodAddress(address string) (namespace, podName string, port int, isPodAddress bool) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil || net.ParseIP(host) != nil {
+		return "", "", 0, false
+	}
+
+	if port, err = strconv.Atoi(portStr); err != nil {
+		return "", "", 0, false
+	}
+
+	labels := strings.Split(host, ".")
+	if len(labels) < 3 || labels[0] == "" || labels[2] == "" {
+		return "", "", 0, false
+	}
+
+	return labels[2], labels[0], port, true
+}
+
+// DialContext connects to the given address. If the address refers to a pod
+// and cannot be dialed directly, the connection is opened through a port
+// forwarded to the pod. The port forwarders are kept running and reused
+// by the subsequent connections to the same address until they fail.
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	namespace, podName, port, isPodAddress := parsePodAddress(address)
+	if !isPodAddress {
+		return d.directDial(ctx, network, address)
+	}
+
+	if localAddress := d.getForwardedAddress(address); localAddress != "" {
+		// A port is already being forwarded to the pod
+		return d.directDial(ctx, network, localAddress)
+	}
+
+	conn, err := d.directDial(ctx, network, address)
+	if err == nil {
+		return conn, nil
+	}
+
+	klog.V(2).Infof("Failed to dial %q directly, falling back to port-forwarding : %s", address, err)
+	localAddress, fwdErr := d.startForwarder(ctx, address, namespace, podName, port)
+	if fwdErr != nil {
+		return nil, fmt.Errorf("failed to dial %q directly (%s) and via port-forwarding (%s)", address, err, fwdErr)
+	}
+
+	return d.directDial(ctx, network, localAddress)
+}
+
+// getForwardedAddress returns the local address forwarded to
+// the given pod address, or an empty string if there is none.
+func (d *Dialer) getForwardedAddress(address string) string {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if fwd, exists := d.forwarders[address]; exists {
+		return fwd.localAddress
+	}
+	return ""
+}
+
+// startForwarder starts forwarding a local port to the given port of the
+// pod in the background and returns the local address. The forwarder is
+// forgotten once it stops so that the next dial can start a new one.
+func (d *Dialer) startForwarder(
+	ctx context.Context, address, namespace, podName string, port int) (string, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if fwd, exists := d.forwarders[address]; exists {
+		// Started by a concurrent dial
+		return fwd.localAddress, nil
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(d.config)
+	if err != nil {
+		return "", err
+	}
+
+	url := d.client.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(namespace).Name(podName).SubResource("portforward").URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	stopChan, readyChan := make(chan struct{}), make(chan struct{})
+	pf, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"},
+		[]string{fmt.Sprintf("0:%d", port)}, stopChan, readyChan, io.Discard, io.Discard)
+	if err != nil {
+		return "", err
+	}
+
+	fwd := &forwarder{stopChan: stopChan}
+	errChan := make(chan error, 1)
+	go func() {
+		err := pf.ForwardPorts()
+		if err != nil {
+			klog.V(2).Infof("Stopped port-forwarding to %q : %s", address, err)
+		}
+		// Notify the startForwarder, if it is still waiting, before
+		// acquiring the lock as it holds the lock while waiting.
+		errChan <- err
+		d.lock.Lock()
+		if d.forwarders[address] == fwd {
+			delete(d.forwarders, address)
+		}
+		d.lock.Unlock()
+	}()
+
+	select {
+	case <-readyChan:
+	case err = <-errChan:
+		return "", fmt.Errorf("port-forwarding to pod %s/%s failed : %s", namespace, podName, err)
+	case <-ctx.Done():
+		close(stopChan)
+		return "", ctx.Err()
+	}
+
+	forwardedPorts, err := pf.GetPorts()
+	if err != nil || len(forwardedPorts) == 0 {
+		close(stopChan)
+		return "", fmt.Errorf("failed to retrieve the port forwarded to pod %s/%s : %v", namespace, podName, err)
+	}
+
+	fwd.localAddress = net.JoinHostPort("127.0.0.1", strconv.Itoa(int(forwardedPorts[0].Local)))
+	d.forwarders[address] = fwd
+	klog.Infof("Forwarding %s to %q", fwd.localAddress, address)
+	return fwd.localAddress, nil
+}
+
+// Stop stops all the running port forwarders
+func (d *Dialer) Stop() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for address, fwd := range d.forwarders {
+		close(fwd.stopChan)
+		delete(d.forwarders, address)
+	}
+}
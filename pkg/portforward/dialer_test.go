@@ -0,0 +1,68 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package portforward
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func Test_parsePodAddress(t *testing.T) {
+	tests := []struct {
+		address      string
+		namespace    string
+		podName      string
+		port         int
+		isPodAddress bool
+	}{
+		{"example-ndb-mgmd-0.example-ndb-mgmd.default.svc:1186", "default", "example-ndb-mgmd-0", 1186, true},
+		{"example-ndb-mysqld-1.example-ndb-mysqld.ns1:3306", "ns1", "example-ndb-mysqld-1", 3306, true},
+		{"example-ndb-mgmd:1186", "", "", 0, false},
+		{"10.0.0.1:1186", "", "", 0, false},
+		{"example-ndb-mgmd-0.example-ndb-mgmd.default.svc", "", "", 0, false},
+	}
+
+	for _, tc := range tests {
+		namespace, podName, port, isPodAddress := parsePodAddress(tc.address)
+		if namespace != tc.namespace || podName != tc.podName ||
+			port != tc.port || isPodAddress != tc.isPodAddress {
+			t.Errorf("Unexpected result for %q : %q, %q, %d, %v",
+				tc.address, namespace, podName, port, isPodAddress)
+		}
+	}
+}
+
+func Test_DialerUsesForwardedPort(t *testing.T) {
+	const podAddress = "example-ndb-mgmd-0.example-ndb-mgmd.default.svc:1186"
+	const localAddress = "127.0.0.1:40000"
+
+	var dialedAddresses []string
+	d := NewDialer(&rest.Config{}, nil)
+	d.directDial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialedAddresses = append(dialedAddresses, address)
+		return nil, errors.New("connection refused")
+	}
+
+	// Addresses not referring to a pod are always dialed directly
+	if _, err := d.DialContext(context.Background(), "tcp", "10.0.0.1:1186"); err == nil {
+		t.Error("Expected the direct dial error")
+	}
+
+	// A running forwarder should be used instead of dialing the pod directly
+	d.forwarders[podAddress] = &forwarder{localAddress: localAddress, stopChan: make(chan struct{})}
+	_, _ = d.DialContext(context.Background(), "tcp", podAddress)
+	if len(dialedAddresses) != 2 || dialedAddresses[0] != "10.0.0.1:1186" || dialedAddresses[1] != localAddress {
+		t.Errorf("Unexpected addresses dialed : %v", dialedAddresses)
+	}
+
+	d.Stop()
+	if len(d.forwarders) != 0 {
+		t.Error("Forwarders were not stopped")
+	}
+}
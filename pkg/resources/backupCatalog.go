@@ -0,0 +1,150 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// backupCatalogKeyPrefix is the prefix of the keys
+	// of the backups in the backup catalog ConfigMap
+	backupCatalogKeyPrefix = "backup-"
+	// MaxBackupCatalogEntries is the maximum number of backups retained in
+	// the backup catalog ConfigMap. Every entry takes less than 1KiB, which
+	// keeps the ConfigMap well within the 1MiB limit of the K8s objects.
+	MaxBackupCatalogEntries = 100
+)
+
+// BackupVerificationState is the result of the verification of a backup
+type BackupVerificationState string
+
+const (
+	// BackupVerified implies that the backup has been verified successfully
+	BackupVerified BackupVerificationState = "Verified"
+	// BackupVerificationFailed implies that the backup failed the verification
+	BackupVerificationFailed BackupVerificationState = "Failed"
+)
+
+// BackupCatalogEntry describes a completed backup of the MySQL Cluster. The
+// backups cataloged from the backup directory of the data nodes, rather
+// than from the cluster log, only have their id, completion time and
+// location filled in, and their other details are left as 0.
+type BackupCatalogEntry struct {
+	// BackupID is the id of the backup
+	BackupID int32 `json:"backupId"`
+	// CompletionTime is the time at which the backup
+	// completed, as logged by the Management Server
+	CompletionTime string `json:"completionTime"`
+
+	// StartGCP is the global checkpoint at which the backup started
+	StartGCP uint32 `json:"startGCP"`
+	// StopGCP is the global checkpoint at which the backup completed.
+	// The restored data is consistent as of this global checkpoint.
+	StopGCP uint32 `json:"stopGCP"`
+	// Records is the number of records in the backup
+	Records uint64 `json:"records"`
+	// LogRecords is the number of log records in the backup
+	LogRecords uint64 `json:"logRecords"`
+	// DataBytes is the size of the data in the backup, in bytes
+	DataBytes uint64 `json:"dataBytes"`
+	// LogBytes is the size of the log in the backup, in bytes
+	LogBytes uint64 `json:"logBytes"`
+	// Location is the directory, inside every data node pod,
+	// holding the part of the backup taken by that data node
+	Location string `json:"location"`
+	// NdbClusterGeneration is the generation of the
+	// NdbCluster when the backup was completed
+	NdbClusterGeneration int64 `json:"ndbClusterGeneration"`
+	// Verification is the result of the verification of the backup.
+	// It is empty if the backup has not been verified.
+	Verification BackupVerificationState `json:"verification,omitempty"`
+	// VerificationMessage has the details of a failed verification
+	VerificationMessage string `json:"verificationMessage,omitempty"`
+}
+
+// GetBackupLocation returns the directory inside the data node
+// pods into which the data nodes write the backup with the given id
+func GetBackupLocation(nc *v1.NdbCluster, backupId int32) string {
+	return path.Join(nc.GetDataNodeBackupDataDir(), "BACKUP", fmt.Sprintf("BACKUP-%d", backupId))
+}
+
+// GetBackupCatalogKey returns the key of the
+// backup with the given id in the backup catalog
+func GetBackupCatalogKey(backupId int32) string {
+	return fmt.Sprintf("%s%d", backupCatalogKeyPrefix, backupId)
+}
+
+// NewBackupCatalogConfigMap returns an empty backup catalog ConfigMap for the given NdbCluster
+func NewBackupCatalogConfigMap(nc *v1.NdbCluster) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nc.GetBackupCatalogConfigMapName(),
+			Namespace: nc.Namespace,
+			// The cluster label is not set, so that
+			// updating the catalog doesn't trigger a sync
+			Labels: map[string]string{
+				constants.ClusterResourceTypeLabel: "ndb-backup-catalog",
+			},
+			OwnerReferences: nc.GetOwnerReferences(),
+		},
+		Data: map[string]string{},
+	}
+}
+
+// ParseBackupCatalog returns the backups cataloged in
+// the given ConfigMap, sorted by their backup ids
+func ParseBackupCatalog(cm *corev1.ConfigMap) ([]BackupCatalogEntry, error) {
+	backups := make([]BackupCatalogEntry, 0, len(cm.Data))
+	for key, value := range cm.Data {
+		if !strings.HasPrefix(key, backupCatalogKeyPrefix) {
+			continue
+		}
+
+		var backup BackupCatalogEntry
+		if err := json.Unmarshal([]byte(value), &backup); err != nil {
+			return nil, fmt.Errorf("failed to parse the backup catalog entry %q : %s", key, err)
+		}
+		backups = append(backups, backup)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].BackupID < backups[j].BackupID
+	})
+	return backups, nil
+}
+
+// GetExcessBackupCatalogKeys returns the keys of the oldest backups in
+// the given catalog data beyond the MaxBackupCatalogEntries latest ones
+func GetExcessBackupCatalogKeys(data map[string]string) []string {
+	var backupIds []int
+	for key := range data {
+		if backupId, err := strconv.Atoi(strings.TrimPrefix(key, backupCatalogKeyPrefix)); err == nil &&
+			strings.HasPrefix(key, backupCatalogKeyPrefix) {
+			backupIds = append(backupIds, backupId)
+		}
+	}
+	if len(backupIds) <= MaxBackupCatalogEntries {
+		return nil
+	}
+
+	sort.Ints(backupIds)
+	var keys []string
+	for _, backupId := range backupIds[:len(backupIds)-MaxBackupCatalogEntries] {
+		keys = append(keys, GetBackupCatalogKey(int32(backupId)))
+	}
+	return keys
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package resources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// backupListingScript lists the backups found in the BACKUP directory of a
+// data node, inside BACKUP_DATA_DIR, as <backup id>:<completion time> and
+// writes the list as the termination message of the container. The
+// completion time is the last modification time of the control file of the
+// backup, in seconds since the epoch, which is in a PART subdirectory for
+// the backups taken by multiple threads. The backups whose control file was
+// modified within the last five minutes are left out, as they might still
+// be running, and only the latest MAX_BACKUPS backups are listed.
+const backupListingScript = `
+: > /dev/termination-log
+cd "${BACKUP_DATA_DIR}/BACKUP" 2> /dev/null || exit 0
+
+now=$(date +%s)
+backups=""
+for ctl in BACKUP-*/BACKUP-*.ctl BACKUP-*/BACKUP-*-PART-1-OF-*/BACKUP-*.ctl; do
+  [ -f "${ctl}" ] || continue
+  mtime=$(stat -c %Y "${ctl}")
+  [ $((now - mtime)) -gt 300 ] || continue
+  backup_id=$(basename "${ctl}" | sed -e 's/^BACKUP-\([0-9]*\)\..*$/\1/')
+  backups="${backups}${backup_id}:${mtime}\n"
+done
+
+printf "${backups}" | sort -u -n -t: -k1,1 | tail -n ${MAX_BACKUPS} | tr '\n' ' ' > /dev/termination-log
+`
+
+// NewBackupListingJob creates a Job that lists the backups stored in the
+// given volumes, which should be the PersistentVolumeClaims of a data node.
+// The Job pod runs on the given worker node, as the PersistentVolumeClaims
+// of the data node might only be mounted by the pods on the same worker
+// node.
+func NewBackupListingJob(nc *v1.NdbCluster,
+	volumes []corev1.Volume, volumeMounts []corev1.VolumeMount, workerNodeName string) *batchv1.Job {
+
+	// Labels for the resource
+	jobLabels := nc.GetCompleteLabels(map[string]string{
+		constants.ClusterResourceTypeLabel: "list-backups-job",
+	})
+
+	backoffLimit := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            nc.GetBackupListingJobName(),
+			Namespace:       nc.Namespace,
+			Labels:          jobLabels,
+			OwnerReferences: nc.GetOwnerReferences(),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: corev1.PodSpec{
+					NodeName: workerNodeName,
+					Containers: []corev1.Container{
+						{
+							Name:            "list-backups",
+							Image:           nc.GetImage(constants.NdbNodeTypeNdbmtd),
+							ImagePullPolicy: nc.Spec.ImagePullPolicy,
+							Command:         []string{"/bin/bash", "-ec", backupListingScript},
+							Env: []corev1.EnvVar{
+								{
+									Name:  "BACKUP_DATA_DIR",
+									Value: nc.GetDataNodeBackupDataDir(),
+								},
+								{
+									Name:  "MAX_BACKUPS",
+									Value: strconv.Itoa(MaxBackupCatalogEntries),
+								},
+							},
+							VolumeMounts: volumeMounts,
+						},
+					},
+					Volumes:          volumes,
+					RestartPolicy:    corev1.RestartPolicyNever,
+					ImagePullSecrets: nc.GetImagePullSecrets(),
+				},
+			},
+		},
+	}
+}
+
+// ParseBackupListing parses the list of backups written by the backup
+// listing Job and returns the completion times of the backups, formatted
+// like the timestamps of the cluster log, keyed by their backup ids.
+func ParseBackupListing(listing string) (map[int32]string, error) {
+	backups := make(map[int32]string)
+	for _, backup := range strings.Fields(listing) {
+		backupId, mtime, found := strings.Cut(backup, ":")
+		if !found {
+			return nil, fmt.Errorf("failed to parse the listed backup %q", backup)
+		}
+
+		id, err := strconv.ParseInt(backupId, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the id of the listed backup %q : %s", backup, err)
+		}
+		seconds, err := strconv.ParseInt(mtime, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the completion time of the listed backup %q : %s", backup, err)
+		}
+		backups[int32(id)] = time.Unix(seconds, 0).UTC().Format("2006-01-02 15:04:05")
+	}
+	return backups, nil
+}
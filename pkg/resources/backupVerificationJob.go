@@ -0,0 +1,105 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package resources
+
+import (
+	"strconv"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ndbRestoreVerifyScript verifies the part of the backup in the BACKUP_PATH
+// directory taken by a data node, by reading its metadata, data and log
+// with ndb_restore. The control files are looked up in the PART
+// subdirectories as well, for the backups taken by multiple threads. The
+// data read is discarded, nothing is restored, and ndb_restore doesn't
+// connect to the MySQL Cluster. An encrypted backup is decrypted with the
+// BACKUP_PASSWORD, which is passed via the stdin to keep it out of the
+// traced commands.
+const ndbRestoreVerifyScript = `
+cd "${BACKUP_PATH}"
+node_ids=$(ls BACKUP-${BACKUP_ID}.*.ctl BACKUP-${BACKUP_ID}-PART-*/BACKUP-${BACKUP_ID}.*.ctl 2> /dev/null |
+  sed -e "s/^.*BACKUP-${BACKUP_ID}\.\([0-9]*\)\.ctl$/\1/" | sort -u)
+if [ -z "${node_ids}" ]; then
+  echo "Backup files of backup ${BACKUP_ID} not found in ${BACKUP_PATH}"
+  exit 1
+fi
+
+decrypt_options=""
+if [ -n "${BACKUP_PASSWORD:-}" ]; then
+  decrypt_options="--decrypt --backup-password-from-stdin"
+fi
+
+for node_id in ${node_ids}; do
+  ndb_restore --backupid=${BACKUP_ID} --nodeid=${node_id} --backup-path=${BACKUP_PATH} \
+    --print-meta --print-data --print-log ${decrypt_options} <<< "${BACKUP_PASSWORD:-}" > /dev/null
+done
+`
+
+// NewBackupVerificationJob creates a Job that verifies the part of the given
+// backup taken by the data node running in the pod with the given ordinal,
+// by reading it with ndb_restore from the given volumes, which should be
+// the PersistentVolumeClaims of that data node. The Job pod runs on the
+// given worker node, as the PersistentVolumeClaims of the data node might
+// only be mounted by the pods on the same worker node. It is not retried
+// on failure, as a failure implies a corrupt or an incomplete backup.
+func NewBackupVerificationJob(nc *v1.NdbCluster, backup *BackupCatalogEntry, dataNodeOrdinal int,
+	volumes []corev1.Volume, volumeMounts []corev1.VolumeMount, workerNodeName string) *batchv1.Job {
+
+	// Labels for the resource
+	jobLabels := nc.GetCompleteLabels(map[string]string{
+		constants.ClusterResourceTypeLabel: "verify-backup-job",
+	})
+
+	backoffLimit := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            nc.GetBackupVerificationJobName(backup.BackupID, dataNodeOrdinal),
+			Namespace:       nc.Namespace,
+			Labels:          jobLabels,
+			OwnerReferences: nc.GetOwnerReferences(),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: corev1.PodSpec{
+					NodeName: workerNodeName,
+					Containers: []corev1.Container{
+						{
+							Name: "ndb-restore",
+							// Use the image of the data nodes to read
+							// the backup with a matching ndb_restore version
+							Image:           nc.GetImage(constants.NdbNodeTypeNdbmtd),
+							ImagePullPolicy: nc.Spec.ImagePullPolicy,
+							Command:         []string{"/bin/bash", "-ecx", ndbRestoreVerifyScript},
+							Env: append([]corev1.EnvVar{
+								{
+									Name:  "BACKUP_ID",
+									Value: strconv.Itoa(int(backup.BackupID)),
+								},
+								{
+									Name:  "BACKUP_PATH",
+									Value: backup.Location,
+								},
+							}, newBackupPasswordEnv(nc.GetBackupEncryptionPasswordSecretName())...),
+							VolumeMounts: volumeMounts,
+						},
+					},
+					Volumes:          volumes,
+					RestartPolicy:    corev1.RestartPolicyNever,
+					ImagePullSecrets: nc.GetImagePullSecrets(),
+				},
+			},
+		},
+	}
+}
@@ -22,6 +22,52 @@ import (
 //go:embed statefulset/scripts
 var scriptsFS embed.FS
 
+// updateDataNodeRestartType updates the Data map with the type of the
+// restart required by the data nodes to apply the new config. If the
+// previous config has not been applied to all the data nodes yet, the
+// more disruptive of the previous and the new restarts is retained.
+// The data nodes of a MySQL Cluster with a single replica are always
+// restarted together.
+func updateDataNodeRestartType(ndb *v1.NdbCluster, data map[string]string,
+	restartType v1.DataNodeRestartType, oldConfigSummary *ndbconfig.ConfigSummary) {
+	if oldConfigSummary != nil && ndb.Status.ProcessedGeneration != oldConfigSummary.NdbClusterGeneration {
+		restartType = ndbconfig.MoreDisruptiveRestart(restartType, oldConfigSummary.DataNodeRestartType)
+	}
+	if oldConfigSummary != nil && ndb.Spec.RedundancyLevel == 1 {
+		restartType = ndbconfig.GetSingleReplicaRestartType(restartType)
+	}
+	data[constants.DataNodeRestartType] = string(restartType)
+}
+
+// updateConfigIni updates the Data map with the given config.ini, along
+// with the version of the last config.ini that requires the Management
+// nodes to be restarted and the type of the restart required by the data
+// nodes. The changes that do not require a Management node restart are
+// applied by the operator by reloading the config in the Management nodes.
+func updateConfigIni(ndb *v1.NdbCluster, data map[string]string,
+	configString string, oldConfigSummary *ndbconfig.ConfigSummary) error {
+	mgmdRestartConfigVersion, err := ndbconfig.GetMgmdRestartConfigVersion(
+		oldConfigSummary, data[constants.ConfigIniKey], configString)
+	if err != nil {
+		klog.Errorf("Failed to check if the config change requires a Management node restart : %v", err)
+		return err
+	}
+
+	dataNodeRestartType := v1.DataNodeNodeRestart
+	if oldConfigSummary != nil {
+		if dataNodeRestartType, _, err = ndbconfig.GetDataNodeRestartType(
+			data[constants.ConfigIniKey], configString); err != nil {
+			klog.Errorf("Failed to check the restart required by the data nodes : %v", err)
+			return err
+		}
+	}
+
+	data[constants.ConfigIniKey] = configString
+	data[constants.MgmdRestartConfigVersion] = fmt.Sprintf("%d", mgmdRestartConfigVersion)
+	updateDataNodeRestartType(ndb, data, dataNodeRestartType, oldConfigSummary)
+	return nil
+}
+
 // updateManagementConfig updates the Data map with a new config.ini
 // if there is any change to the MySQL Cluster configuration.
 func updateManagementConfig(
@@ -40,16 +86,34 @@ func updateManagementConfig(
 		}
 
 		// add/update that to the data map
-		data[constants.ConfigIniKey] = configString
+		if err = updateConfigIni(ndb, data, configString, oldConfigSummary); err != nil {
+			return err
+		}
+	} else {
+		// The data nodes will be restarted only to apply a new pod definition, if any
+		updateDataNodeRestartType(ndb, data, v1.DataNodeNodeRestart, oldConfigSummary)
 	}
 
 	// add/update the API slot information
 	data[constants.NumOfMySQLServers] = fmt.Sprintf("%d", ndb.GetMySQLServerNodeCount())
 
+	// add/update the MySQL Server groups and the nodeIds reserved for them
+	serverGroupsString, err := ndbconfig.GetMySQLServerGroupsString(ndb)
+	if err != nil {
+		klog.Errorf("Failed to get the MySQL Server groups string : %v", err)
+		return err
+	}
+	data[constants.MySQLServerGroups] = serverGroupsString
+
 	// add/update service type info for management nodes
 	data[constants.ManagementLoadBalancer] = fmt.Sprintf("%v",
 		ndb.Spec.ManagementNode != nil && ndb.Spec.ManagementNode.EnableLoadBalancer)
 
+	// remove the zones of the location domains if they have been disabled
+	if ndb.Spec.LocationDomainTopologyKey == "" {
+		delete(data, constants.LocationDomainZones)
+	}
+
 	return nil
 }
 
@@ -68,6 +132,32 @@ func updateMySQLConfig(
 		}
 	}
 
+	// Update the my.cnf keys of the MySQL Server groups
+	for i := range nc.GetMySQLServerGroups() {
+		serverGroupSpec := &nc.Spec.MysqlNode.ServerGroups[i]
+		if needsUpdate, err := oldConfigSummary.MySQLServerGroupCnfNeedsUpdate(serverGroupSpec); err != nil {
+			klog.Errorf("Failed to check if the my.cnf of the MySQL Server group %q needs to be updated : %s",
+				serverGroupSpec.Name, err)
+			return err
+		} else if needsUpdate {
+			configKey := ndbconfig.GetMySQLServerGroupConfigKey(serverGroupSpec.Name)
+			if data[configKey], err = ndbconfig.GetMySQLServerGroupConfigString(serverGroupSpec, oldConfigSummary); err != nil {
+				klog.Errorf("Failed to get the my.cnf config string of the MySQL Server group %q : %s",
+					serverGroupSpec.Name, err)
+				return err
+			}
+		}
+	}
+
+	// Remove the my.cnf keys of the MySQL Server groups removed from the spec
+	if oldConfigSummary != nil {
+		for _, serverGroup := range oldConfigSummary.MySQLServerGroups {
+			if nc.GetMySQLServerGroup(serverGroup.Name) == nil {
+				delete(data, ndbconfig.GetMySQLServerGroupConfigKey(serverGroup.Name))
+			}
+		}
+	}
+
 	// Add/update service type info and root host for MySQL servers
 	if nc.Spec.MysqlNode != nil {
 		data[constants.MySQLRootHost] = nc.Spec.MysqlNode.RootHost
@@ -80,16 +170,77 @@ func updateMySQLConfig(
 	return nil
 }
 
+// helperScripts maps the file names of the helper scripts,
+// stored in the config map, to their descriptions
+var helperScripts = map[string]string{
+	constants.MysqldInitScript:             "MySQL Server init",
+	constants.MysqldHealthCheckScript:      "MySQL Server Healthcheck",
+	constants.DataNodeStartupProbeScript:   "Data Node Startup Probe",
+	constants.DataNodeReadinessProbeScript: "Data Node Readiness Probe",
+	constants.DataNodePreStopHookScript:    "Data Node PreStop Hook",
+	constants.MgmdStartupProbeScript:       "Mgmd Startup Probe",
+	constants.MgmdReadinessProbeScript:     "Mgmd Readiness Probe",
+}
+
+// operatorConfigMapKeys are the keys of the config map data,
+// other than the helper scripts, that are owned by the operator
+var operatorConfigMapKeys = []string{
+	constants.ConfigIniKey,
+	constants.ConfigChanges,
+	constants.DataNodeRestartType,
+	constants.LocationDomainZones,
+	constants.ManagementLoadBalancer,
+	constants.MgmdRestartConfigVersion,
+	constants.MySQLConfigKey,
+	constants.MySQLLoadBalancer,
+	constants.MySQLRootHost,
+	constants.MySQLServerGroups,
+	constants.NdbClusterGeneration,
+	constants.NumOfMySQLServers,
+	constants.RestartRequests,
+}
+
+// getConfigMapLabels returns the labels of the config map
+func getConfigMapLabels(ndb *v1.NdbCluster) map[string]string {
+	return ndb.GetCompleteLabels(map[string]string{
+		constants.ClusterResourceTypeLabel: "ndb-configmap",
+	})
+}
+
+// NewConfigMapApplyConfiguration returns a config map, to be applied via
+// server side apply, that has only the fields of the given config map
+// owned by the operator - the labels, the owner references and the data
+// keys generated by the operator. The fields set by the other managers
+// are left out so that the operator doesn't take over their ownership.
+func NewConfigMapApplyConfiguration(ndb *v1.NdbCluster, cm *corev1.ConfigMap) *corev1.ConfigMap {
+	data := make(map[string]string)
+	for _, key := range operatorConfigMapKeys {
+		if value, exists := cm.Data[key]; exists {
+			data[key] = value
+		}
+	}
+	for fileName := range helperScripts {
+		if value, exists := cm.Data[fileName]; exists {
+			data[fileName] = value
+		}
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            cm.Name,
+			Namespace:       cm.Namespace,
+			Labels:          getConfigMapLabels(ndb),
+			OwnerReferences: ndb.GetOwnerReferences(),
+		},
+		Data: data,
+	}
+}
+
 // updateHelperScripts updates the data map with the helper
 // scripts used for the MySQL Server initialisation & health
-// probes and Data node health probe.
+// probes and the Management and Data node health probes.
 func updateHelperScripts(data map[string]string) error {
-	for fileName, desc := range map[string]string{
-		constants.MysqldInitScript:           "MySQL Server init",
-		constants.MysqldHealthCheckScript:    "MySQL Server Healthcheck",
-		constants.DataNodeStartupProbeScript: "Data Node Startup Probe",
-		constants.MgmdStartupProbeScript:     "Mgmd Startup Probe",
-	} {
+	for fileName, desc := range helperScripts {
 		fileBytes, err := scriptsFS.ReadFile("statefulset/scripts/" + fileName)
 		if err != nil {
 			klog.Errorf("Failed to read %s script at %q : %v",
@@ -120,12 +271,101 @@ func GetUpdatedConfigMap(
 		return nil
 	}
 
+	// Update the helper scripts, so that the configmaps created by
+	// an older operator version also have all the latest scripts
+	if err := updateHelperScripts(updatedCm.Data); err != nil {
+		klog.Errorf("Failed to update the config map : %v", err)
+		return nil
+	}
+
+	// Summarise the changes made to the configs by the new spec
+	configChangesString, err := ndbconfig.GetConfigChangesString(cm.Data, updatedCm.Data)
+	if err != nil {
+		klog.Errorf("Failed to summarise the config changes : %v", err)
+		return nil
+	}
+	updatedCm.Data[constants.ConfigChanges] = configChangesString
+
 	// Update the generation the config map is based on
 	updatedCm.Data[constants.NdbClusterGeneration] = fmt.Sprintf("%d", ndb.Generation)
 
 	return updatedCm
 }
 
+// GetConfigMapWithLocationDomains creates and returns a new config map
+// with a new config.ini that assigns the MySQL Cluster nodes to the
+// location domains of the given pod name to zone map.
+func GetConfigMapWithLocationDomains(
+	ndb *v1.NdbCluster, cm *corev1.ConfigMap,
+	oldConfigSummary *ndbconfig.ConfigSummary, locationDomainZones map[string]string) *corev1.ConfigMap {
+	// create a deep copy of the original ConfigMap
+	updatedCm := cm.DeepCopy()
+
+	// Update the config.ini
+	configString, err := ndbconfig.GetConfigStringWithLocationDomains(ndb, oldConfigSummary, locationDomainZones)
+	if err != nil {
+		klog.Errorf("Failed to get the config string : %v", err)
+		return nil
+	}
+	if err = updateConfigIni(ndb, updatedCm.Data, configString, oldConfigSummary); err != nil {
+		return nil
+	}
+
+	// Update the zones of the location domains
+	if updatedCm.Data[constants.LocationDomainZones], err =
+		ndbconfig.GetLocationDomainZonesString(locationDomainZones); err != nil {
+		klog.Errorf("Failed to get the location domain zones string : %v", err)
+		return nil
+	}
+
+	return updatedCm
+}
+
+// updateRestartRequests updates the Data map with the annotations
+// of the NdbCluster that request a rolling restart of the nodes.
+func updateRestartRequests(
+	ndb *v1.NdbCluster, data map[string]string, oldConfigSummary *ndbconfig.ConfigSummary) error {
+	restartRequestsString, err := ndbconfig.GetRestartRequestsString(ndb, oldConfigSummary)
+	if err != nil {
+		klog.Errorf("Failed to get the restart requests string : %v", err)
+		return err
+	}
+	data[constants.RestartRequests] = restartRequestsString
+	return nil
+}
+
+// GetConfigMapWithRestartRequests creates and returns a new config map
+// with the latest requests for a rolling restart of the MySQL Cluster
+// nodes, made via the annotations of the NdbCluster resource.
+func GetConfigMapWithRestartRequests(
+	ndb *v1.NdbCluster, cm *corev1.ConfigMap, oldConfigSummary *ndbconfig.ConfigSummary) *corev1.ConfigMap {
+	// create a deep copy of the original ConfigMap
+	updatedCm := cm.DeepCopy()
+
+	if err := updateRestartRequests(ndb, updatedCm.Data, oldConfigSummary); err != nil {
+		return nil
+	}
+
+	return updatedCm
+}
+
+// GetConfigMapWithDataNodeRestartRequest creates and returns a new config
+// map with the given request for a restart of the data node with the given
+// node id, made via the annotations of the NdbCluster resource.
+func GetConfigMapWithDataNodeRestartRequest(cm *corev1.ConfigMap,
+	oldConfigSummary *ndbconfig.ConfigSummary, nodeId int, restartRequest string) *corev1.ConfigMap {
+	restartRequestsString, err := ndbconfig.GetDataNodeRestartRequestString(oldConfigSummary, nodeId, restartRequest)
+	if err != nil {
+		klog.Errorf("Failed to get the restart requests string : %v", err)
+		return nil
+	}
+
+	// create a deep copy of the original ConfigMap
+	updatedCm := cm.DeepCopy()
+	updatedCm.Data[constants.RestartRequests] = restartRequestsString
+	return updatedCm
+}
+
 // CreateConfigMap creates a config map object with the
 // information available in the ndb object
 func CreateConfigMap(ndb *v1.NdbCluster) *corev1.ConfigMap {
@@ -143,9 +383,7 @@ func CreateConfigMap(ndb *v1.NdbCluster) *corev1.ConfigMap {
 	*/
 
 	// Labels for the configmap
-	cmLabels := ndb.GetCompleteLabels(map[string]string{
-		constants.ClusterResourceTypeLabel: "ndb-configmap",
-	})
+	cmLabels := getConfigMapLabels(ndb)
 
 	// Data for the config map
 	data := make(map[string]string)
@@ -165,6 +403,12 @@ func CreateConfigMap(ndb *v1.NdbCluster) *corev1.ConfigMap {
 		return nil
 	}
 
+	// Add the restart requests, so that the nodes
+	// are not restarted again once they are started
+	if updateRestartRequests(ndb, data, nil) != nil {
+		return nil
+	}
+
 	// Update the generation the config map is based on
 	data[constants.NdbClusterGeneration] = fmt.Sprintf("%d", ndb.Generation)
 
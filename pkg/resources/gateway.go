@@ -0,0 +1,124 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package resources
+
+import (
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// gatewayListenerName is the name of the listener of
+	// the Gateway created to expose the MySQL Servers
+	gatewayListenerName = "mysql"
+	// defaultGatewayPort is the default port of the listener
+	defaultGatewayPort = constants.MySQLServerPort
+)
+
+// The Gateway API resources are not part of the K8s API, and they are
+// handled as unstructured objects to avoid depending on their clientset.
+var (
+	// GatewayGVK is the GroupVersionKind of the Gateways created by the operator
+	GatewayGVK = schema.GroupVersionKind{
+		Group: "gateway.networking.k8s.io", Version: "v1beta1", Kind: "Gateway"}
+	// TCPRouteGVK is the GroupVersionKind of the TCPRoutes created by the operator
+	TCPRouteGVK = schema.GroupVersionKind{
+		Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: "TCPRoute"}
+)
+
+// newGatewayAPIObject returns an unstructured object of the given
+// kind with the metadata common to all the Gateway API resources
+// created by the operator for the given NdbCluster.
+func newGatewayAPIObject(
+	nc *v1.NdbCluster, gvk schema.GroupVersionKind, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": spec,
+	}}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName(name)
+	obj.SetNamespace(nc.Namespace)
+	obj.SetLabels(nc.GetCompleteLabels(map[string]string{
+		constants.ClusterResourceTypeLabel: "gateway-" + constants.NdbNodeTypeMySQLD,
+	}))
+	obj.SetOwnerReferences(nc.GetOwnerReferences())
+	return obj
+}
+
+// NewMySQLServerGateway creates a Gateway with a TCP listener that accepts
+// the TCPRoutes from the namespace of the NdbCluster. It should be created
+// only if the spec.mysqlNode.gateway doesn't specify an existing Gateway.
+func NewMySQLServerGateway(nc *v1.NdbCluster) *unstructured.Unstructured {
+	gatewaySpec := nc.Spec.MysqlNode.Gateway
+
+	port := gatewaySpec.Port
+	if port == 0 {
+		port = defaultGatewayPort
+	}
+
+	gateway := newGatewayAPIObject(nc, GatewayGVK, nc.GetGatewayName(), map[string]interface{}{
+		"gatewayClassName": gatewaySpec.GatewayClassName,
+		"listeners": []interface{}{
+			map[string]interface{}{
+				"name":     gatewayListenerName,
+				"protocol": "TCP",
+				"port":     int64(port),
+				"allowedRoutes": map[string]interface{}{
+					"namespaces": map[string]interface{}{
+						"from": "Same",
+					},
+					"kinds": []interface{}{
+						map[string]interface{}{
+							"kind": TCPRouteGVK.Kind,
+						},
+					},
+				},
+			},
+		},
+	})
+	if len(gatewaySpec.Annotations) != 0 {
+		gateway.SetAnnotations(gatewaySpec.Annotations)
+	}
+	return gateway
+}
+
+// NewMySQLServerTCPRoute creates a TCPRoute that routes the traffic
+// received by the Gateway to the Service of the MySQL Servers.
+func NewMySQLServerTCPRoute(nc *v1.NdbCluster) *unstructured.Unstructured {
+	gatewaySpec := nc.Spec.MysqlNode.Gateway
+
+	var parentRef map[string]interface{}
+	if gatewaySpec.GatewayName == "" {
+		// Attach to the listener of the Gateway created by the operator
+		parentRef = map[string]interface{}{
+			"name":        nc.GetGatewayName(),
+			"sectionName": gatewayListenerName,
+		}
+	} else {
+		// Attach to the existing Gateway
+		parentRef = map[string]interface{}{
+			"name": gatewaySpec.GatewayName,
+		}
+		if gatewaySpec.ListenerName != "" {
+			parentRef["sectionName"] = gatewaySpec.ListenerName
+		}
+	}
+
+	return newGatewayAPIObject(nc, TCPRouteGVK, nc.GetTCPRouteName(), map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"backendRefs": []interface{}{
+					map[string]interface{}{
+						"name": nc.GetServiceName(constants.NdbNodeTypeMySQLD),
+						"port": int64(nc.GetMySQLServerPort()),
+					},
+				},
+			},
+		},
+	})
+}
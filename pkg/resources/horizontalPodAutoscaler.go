@@ -0,0 +1,109 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package resources
+
+import (
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultTargetCPUUtilizationPercentage is the CPU utilization
+	// targeted by the autoscaler when no target has been specified
+	defaultTargetCPUUtilizationPercentage = 80
+	// defaultConnectionsMetricName is the default name of the pod
+	// metric that holds the number of connections to the MySQL Server
+	defaultConnectionsMetricName = "mysql_global_status_threads_connected"
+)
+
+// NewHorizontalPodAutoscaler creates a HorizontalPodAutoscaler that
+// scales the MySQL Servers via the NdbCluster scale subresource.
+func NewHorizontalPodAutoscaler(nc *v1.NdbCluster) *autoscalingv2.HorizontalPodAutoscaler {
+	autoscalingSpec := nc.Spec.MysqlNode.Autoscaling
+
+	// Labels for the resource
+	hpaLabels := nc.GetCompleteLabels(map[string]string{
+		constants.ClusterResourceTypeLabel: "hpa-" + constants.NdbNodeTypeMySQLD,
+	})
+
+	// The autoscaler should never scale beyond the API slots
+	// reserved for the MySQL Servers in the MySQL Cluster config.
+	maxReplicas := nc.GetMySQLServerMaxNodeCount()
+	if autoscalingSpec.MaxNodeCount != 0 && autoscalingSpec.MaxNodeCount < maxReplicas {
+		maxReplicas = autoscalingSpec.MaxNodeCount
+	}
+
+	minReplicas := autoscalingSpec.MinNodeCount
+	if minReplicas == 0 {
+		minReplicas = 1
+	}
+
+	// Generate the metrics
+	var metrics []autoscalingv2.MetricSpec
+	targetCPUUtilization := autoscalingSpec.TargetCPUUtilizationPercentage
+	if targetCPUUtilization == nil && autoscalingSpec.TargetConnectionsPerServer == nil {
+		// No targets specified - use the default CPU target
+		defaultTarget := int32(defaultTargetCPUUtilizationPercentage)
+		targetCPUUtilization = &defaultTarget
+	}
+
+	if targetCPUUtilization != nil {
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: targetCPUUtilization,
+				},
+			},
+		})
+	}
+
+	if autoscalingSpec.TargetConnectionsPerServer != nil {
+		metricName := autoscalingSpec.ConnectionsMetricName
+		if metricName == "" {
+			metricName = defaultConnectionsMetricName
+		}
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{
+					Name: metricName,
+				},
+				Target: autoscalingv2.MetricTarget{
+					Type: autoscalingv2.AverageValueMetricType,
+					AverageValue: resource.NewQuantity(
+						int64(*autoscalingSpec.TargetConnectionsPerServer), resource.DecimalSI),
+				},
+			},
+		})
+	}
+
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            nc.GetHorizontalPodAutoscalerName(),
+			Namespace:       nc.Namespace,
+			Labels:          hpaLabels,
+			OwnerReferences: nc.GetOwnerReferences(),
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			// Scale the NdbCluster resource via its scale subresource
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: v1.SchemeGroupVersion.String(),
+				Kind:       "NdbCluster",
+				Name:       nc.Name,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: maxReplicas,
+			Metrics:     metrics,
+		},
+	}
+}
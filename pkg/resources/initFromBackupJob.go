@@ -0,0 +1,218 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package resources
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// backupVolumeName is the name of the volume holding the backup files
+	backupVolumeName = "backup-volume"
+	// backupVolumeMountPath is the path at which the backup volume is mounted
+	backupVolumeMountPath = "/backup"
+)
+
+// ndbRestoreScript restores the backup in the BACKUP_PATH directory
+// into the MySQL Cluster. The metadata is restored once and the data is
+// restored from the backup files of every data node that took the backup.
+// The indexes are disabled during the data restore and rebuilt at the end,
+// as restoring the data into tables with indexes is considerably slower.
+// The NDB_RESTORE_OPTIONS, which are shell-quoted as they can have names
+// with any characters, are passed to every ndb_restore run, and the
+// RESTORE_EPOCH_OPTION only to the data restore of the first data node. An
+// encrypted backup is decrypted with the BACKUP_PASSWORD, which is passed
+// via the stdin to keep it out of the traced commands.
+const ndbRestoreScript = `
+cd "${BACKUP_PATH}"
+node_ids=$(ls BACKUP-${BACKUP_ID}.*.ctl | sed -e "s/^BACKUP-${BACKUP_ID}\.\([0-9]*\)\.ctl$/\1/")
+if [ -z "${node_ids}" ]; then
+  echo "Backup files of backup ${BACKUP_ID} not found in ${BACKUP_PATH}"
+  exit 1
+fi
+
+eval "restore_options=(${NDB_RESTORE_OPTIONS})"
+restore=(ndb_restore "--ndb-connectstring=${NDB_CONNECTSTRING}" "--backupid=${BACKUP_ID}" "--backup-path=${BACKUP_PATH}" "${restore_options[@]}")
+if [ -n "${BACKUP_PASSWORD:-}" ]; then
+  restore+=(--decrypt --backup-password-from-stdin)
+fi
+first_node_id=$(echo ${node_ids} | cut -d' ' -f1)
+"${restore[@]}" --nodeid=${first_node_id} --restore-meta --disable-indexes <<< "${BACKUP_PASSWORD:-}"
+for node_id in ${node_ids}; do
+  epoch_option=""
+  if [ "${node_id}" = "${first_node_id}" ]; then
+    epoch_option="${RESTORE_EPOCH_OPTION}"
+  fi
+  "${restore[@]}" --nodeid=${node_id} --restore-data --disable-indexes ${epoch_option} <<< "${BACKUP_PASSWORD:-}"
+done
+"${restore[@]}" --nodeid=${first_node_id} --rebuild-indexes <<< "${BACKUP_PASSWORD:-}"
+`
+
+// newBackupPasswordEnv returns the BACKUP_PASSWORD env var, holding the
+// password from the given Secret with which a backup is decrypted, or
+// nil if the Secret is not specified, i.e. the backup is not encrypted.
+func newBackupPasswordEnv(secretName string) []corev1.EnvVar {
+	if secretName == "" {
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		{
+			Name: "BACKUP_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secretName,
+					},
+					Key: corev1.BasicAuthPasswordKey,
+				},
+			},
+		},
+	}
+}
+
+// getInitFromBackupPath returns the path, inside the
+// restore container, of the directory holding the backup files
+func getInitFromBackupPath(initFromBackup *v1.NdbClusterInitFromBackupSpec) string {
+	backupPath := initFromBackup.Path
+	if backupPath == "" {
+		// Use the directory the data nodes write the backup into
+		backupPath = fmt.Sprintf("BACKUP/BACKUP-%d", initFromBackup.BackupID)
+	}
+	return path.Join(backupVolumeMountPath, backupPath)
+}
+
+// getNdbRestoreOptions returns the shell-quoted ndb_restore options that
+// tune the restore and filter the databases and tables to be restored
+func getNdbRestoreOptions(initFromBackup *v1.NdbClusterInitFromBackupSpec) string {
+	var options []string
+	if initFromBackup.Parallelism != 0 {
+		options = append(options, fmt.Sprintf("--parallelism=%d", initFromBackup.Parallelism))
+	}
+
+	for _, filter := range []struct {
+		option string
+		names  []string
+	}{
+		{"--include-databases", initFromBackup.IncludeDatabases},
+		{"--exclude-databases", initFromBackup.ExcludeDatabases},
+		{"--include-tables", initFromBackup.IncludeTables},
+		{"--exclude-tables", initFromBackup.ExcludeTables},
+	} {
+		if len(filter.names) != 0 {
+			options = append(options, helpers.ShellQuote(
+				fmt.Sprintf("%s=%s", filter.option, strings.Join(filter.names, ","))))
+		}
+	}
+
+	for _, rewrite := range initFromBackup.RewriteDatabases {
+		options = append(options, helpers.ShellQuote(
+			fmt.Sprintf("--rewrite-database=%s,%s", rewrite.From, rewrite.To)))
+	}
+
+	return strings.Join(options, " ")
+}
+
+// NewInitFromBackupJob creates a Job that restores the backup specified
+// in spec.initFromBackup into the MySQL Cluster using ndb_restore. The
+// Job connects to the MySQL Cluster via one of the free API slots and is
+// not retried on failure, as the metadata restore is not idempotent.
+func NewInitFromBackupJob(nc *v1.NdbCluster) *batchv1.Job {
+	initFromBackup := nc.Spec.InitFromBackup
+
+	// Labels for the resource
+	jobLabels := nc.GetCompleteLabels(map[string]string{
+		constants.ClusterResourceTypeLabel: "init-from-backup-job",
+	})
+
+	// Record the epoch of the backup, if requested
+	restoreEpochOption := ""
+	if initFromBackup.RestoreEpoch {
+		restoreEpochOption = "--restore-epoch"
+	}
+
+	backoffLimit := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            nc.GetInitFromBackupJobName(),
+			Namespace:       nc.Namespace,
+			Labels:          jobLabels,
+			OwnerReferences: nc.GetOwnerReferences(),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "ndb-restore",
+							// Use the image of the data nodes to restore
+							// the backup with a matching ndb_restore version
+							Image:           nc.GetImage(constants.NdbNodeTypeNdbmtd),
+							ImagePullPolicy: nc.Spec.ImagePullPolicy,
+							Command:         []string{"/bin/bash", "-ecx", ndbRestoreScript},
+							Env: append([]corev1.EnvVar{
+								{
+									Name:  "NDB_CONNECTSTRING",
+									Value: nc.GetConnectstring(),
+								},
+								{
+									Name:  "BACKUP_ID",
+									Value: strconv.Itoa(int(initFromBackup.BackupID)),
+								},
+								{
+									Name:  "BACKUP_PATH",
+									Value: getInitFromBackupPath(initFromBackup),
+								},
+								{
+									Name:  "NDB_RESTORE_OPTIONS",
+									Value: getNdbRestoreOptions(initFromBackup),
+								},
+								{
+									Name:  "RESTORE_EPOCH_OPTION",
+									Value: restoreEpochOption,
+								},
+							}, newBackupPasswordEnv(initFromBackup.EncryptionPasswordSecretName)...),
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      backupVolumeName,
+									MountPath: backupVolumeMountPath,
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: backupVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: initFromBackup.PersistentVolumeClaimName,
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					RestartPolicy:    corev1.RestartPolicyNever,
+					ImagePullSecrets: nc.GetImagePullSecrets(),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package resources
+
+import (
+	"path"
+	"strconv"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// dumpVolumeName is the name of the volume holding the SQL dump
+	dumpVolumeName = "dump-volume"
+	// dumpVolumeMountPath is the path at which the dump volume is mounted
+	dumpVolumeMountPath = "/dump"
+)
+
+// mysqlLoadDumpScript loads the SQL dump in DUMP_FILE into the MySQL
+// Cluster via the MySQL Server at MYSQL_HOST and MYSQL_PORT. The password
+// of the root user is passed via the MYSQL_PWD env variable to keep it out
+// of the command line. Tables without an explicit ENGINE clause are
+// created in NDB.
+const mysqlLoadDumpScript = `
+mysql --host=${MYSQL_HOST} --port=${MYSQL_PORT} --user=root \
+  --init-command="SET default_storage_engine=NDBCLUSTER" < "${DUMP_FILE}"
+`
+
+// NewInitFromDumpJob creates a Job that loads the SQL dump specified in
+// spec.initFromDump into the MySQL Cluster using the mysql client. The
+// Job connects to the MySQL Servers via their Service and is not retried
+// on failure, as the dump may have been partially loaded.
+func NewInitFromDumpJob(nc *v1.NdbCluster) *batchv1.Job {
+	initFromDump := nc.Spec.InitFromDump
+
+	// Labels for the resource
+	jobLabels := nc.GetCompleteLabels(map[string]string{
+		constants.ClusterResourceTypeLabel: "init-from-dump-job",
+	})
+
+	rootPasswordSecretName, _ := GetMySQLRootPasswordSecretName(nc)
+	backoffLimit := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            nc.GetInitFromDumpJobName(),
+			Namespace:       nc.Namespace,
+			Labels:          jobLabels,
+			OwnerReferences: nc.GetOwnerReferences(),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:            "mysql-load-dump",
+							Image:           nc.GetImage(constants.NdbNodeTypeMySQLD),
+							ImagePullPolicy: nc.Spec.ImagePullPolicy,
+							Command:         []string{"/bin/bash", "-ecx", mysqlLoadDumpScript},
+							Env: []corev1.EnvVar{
+								{
+									Name: "MYSQL_HOST",
+									Value: nc.GetServiceName(constants.NdbNodeTypeMySQLD) +
+										"." + nc.Namespace + ".svc",
+								},
+								{
+									Name:  "MYSQL_PORT",
+									Value: strconv.Itoa(int(nc.GetMySQLServerPort())),
+								},
+								{
+									// Password of the root user
+									Name: "MYSQL_PWD",
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: rootPasswordSecretName,
+											},
+											Key: corev1.BasicAuthPasswordKey,
+										},
+									},
+								},
+								{
+									Name:  "DUMP_FILE",
+									Value: path.Join(dumpVolumeMountPath, initFromDump.Path),
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      dumpVolumeName,
+									MountPath: dumpVolumeMountPath,
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: dumpVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: initFromDump.PersistentVolumeClaimName,
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					RestartPolicy:    corev1.RestartPolicyNever,
+					ImagePullSecrets: nc.GetImagePullSecrets(),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package resources
+
+import (
+	"fmt"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// ndbOperatorAppLabelValue is the value of the 'app'
+	// label set on the NDB Operator pods by the helm chart
+	ndbOperatorAppLabelValue = "ndb-operator"
+)
+
+// networkPolicyPorts returns the NetworkPolicyPorts for the given port numbers
+func networkPolicyPorts(portNumbers ...int32) []networkingv1.NetworkPolicyPort {
+	var ports []networkingv1.NetworkPolicyPort
+	for _, portNumber := range portNumbers {
+		protocol := corev1.ProtocolTCP
+		port := intstr.FromInt(int(portNumber))
+		ports = append(ports, networkingv1.NetworkPolicyPort{
+			Protocol: &protocol,
+			Port:     &port,
+		})
+	}
+	return ports
+}
+
+// ndbAPIApplicationNetworkPolicyPeers returns the NetworkPolicyPeers that
+// select the pods of the NDBAPI applications specified in the NdbCluster
+// spec, i.e. the StatefulSet pods for which the API sections are reserved.
+func ndbAPIApplicationNetworkPolicyPeers(nc *v1.NdbCluster) []networkingv1.NetworkPolicyPeer {
+	var peers []networkingv1.NetworkPolicyPeer
+	for _, app := range nc.Spec.NdbAPIApplications {
+		var podNames []string
+		for podIdx := int32(0); podIdx < app.NodeCount; podIdx++ {
+			podNames = append(podNames, fmt.Sprintf("%s-%d", app.Name, podIdx))
+		}
+
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					corev1.LabelMetadataName: app.Namespace,
+				},
+			},
+			PodSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      appsv1.StatefulSetPodNameLabel,
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   podNames,
+					},
+				},
+			},
+		})
+	}
+	return peers
+}
+
+// NewNetworkPolicy creates a NetworkPolicy that allows only the traffic
+// between the MySQL Cluster nodes, the traffic from the NDB Operator pods
+// running in the operatorNamespace and the traffic from the clients and
+// the NDBAPI applications specified in the NdbCluster spec. If the
+// operatorNamespace is empty, i.e. the operator is running outside the
+// K8s Cluster, the traffic from the operatorCIDR is allowed instead, and
+// no traffic from the operator is allowed if it is empty as well.
+func NewNetworkPolicy(nc *v1.NdbCluster, operatorNamespace, operatorCIDR string) *networkingv1.NetworkPolicy {
+	networkPolicySpec := nc.Spec.NetworkPolicy
+
+	// Ports used by the Management and the Data nodes
+	ndbPorts := []int32{nc.GetManagementNodePort()}
+	if dataNodePort := nc.GetDataNodeServerPort(); dataNodePort != ndbPorts[0] {
+		ndbPorts = append(ndbPorts, dataNodePort)
+	}
+	mysqldPort := nc.GetMySQLServerPort()
+	// Ports used by all the MySQL Cluster nodes
+	clusterPorts := append(append([]int32{}, ndbPorts...), mysqldPort)
+
+	// Labels for the resource
+	networkPolicyLabels := nc.GetCompleteLabels(map[string]string{
+		constants.ClusterResourceTypeLabel: "network-policy",
+	})
+
+	// Allow the traffic between the MySQL Cluster nodes
+	ingressRules := []networkingv1.NetworkPolicyIngressRule{
+		{
+			From: []networkingv1.NetworkPolicyPeer{
+				{
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: nc.GetLabels(),
+					},
+				},
+			},
+			Ports: networkPolicyPorts(clusterPorts...),
+		},
+	}
+
+	// Allow the traffic from the NDB Operator
+	if operatorNamespace != "" {
+		ingressRules = append(ingressRules, networkingv1.NetworkPolicyIngressRule{
+			From: []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							corev1.LabelMetadataName: operatorNamespace,
+						},
+					},
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"app": ndbOperatorAppLabelValue,
+						},
+					},
+				},
+			},
+			Ports: networkPolicyPorts(clusterPorts...),
+		})
+	} else if operatorCIDR != "" {
+		ingressRules = append(ingressRules, networkingv1.NetworkPolicyIngressRule{
+			From: []networkingv1.NetworkPolicyPeer{
+				{
+					IPBlock: &networkingv1.IPBlock{
+						CIDR: operatorCIDR,
+					},
+				},
+			},
+			Ports: networkPolicyPorts(clusterPorts...),
+		})
+	}
+
+	// Allow the traffic from the MySQL and NDBAPI clients
+	if len(networkPolicySpec.MySQLClients) != 0 {
+		ingressRules = append(ingressRules, networkingv1.NetworkPolicyIngressRule{
+			From:  networkPolicySpec.MySQLClients,
+			Ports: networkPolicyPorts(mysqldPort),
+		})
+	}
+	if len(networkPolicySpec.NdbAPIClients) != 0 {
+		ingressRules = append(ingressRules, networkingv1.NetworkPolicyIngressRule{
+			From:  networkPolicySpec.NdbAPIClients,
+			Ports: networkPolicyPorts(ndbPorts...),
+		})
+	}
+
+	// Allow the traffic from the pods of the NDBAPI applications
+	if ndbAPIApplicationPeers := ndbAPIApplicationNetworkPolicyPeers(nc); len(ndbAPIApplicationPeers) != 0 {
+		ingressRules = append(ingressRules, networkingv1.NetworkPolicyIngressRule{
+			From:  ndbAPIApplicationPeers,
+			Ports: networkPolicyPorts(ndbPorts...),
+		})
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            nc.GetNetworkPolicyName(),
+			Namespace:       nc.Namespace,
+			Labels:          networkPolicyLabels,
+			OwnerReferences: nc.GetOwnerReferences(),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			// Select all the pods of the MySQL Cluster
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: nc.GetLabels(),
+			},
+			Ingress: ingressRules,
+			// Only the incoming traffic is restricted
+			PolicyTypes: []networkingv1.PolicyType{
+				networkingv1.PolicyTypeIngress,
+			},
+		},
+	}
+}
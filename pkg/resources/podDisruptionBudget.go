@@ -8,11 +8,53 @@ import (
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
 	"github.com/mysql/ndb-operator/pkg/constants"
 	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-// NewPodDisruptionBudget creates a PodDisruptionBudget allowing maximum 1 data node to be unavailable
+// setPodDisruptionBudgetLimits sets the MinAvailable/MaxUnavailable
+// values of the PDB spec based on the node type and the NdbCluster spec.
+func setPodDisruptionBudgetLimits(
+	ndb *v1.NdbCluster, nodeType string, pdbSpec *policyv1.PodDisruptionBudgetSpec) {
+
+	var ndbPdbSpec *v1.NdbPodDisruptionBudgetSpec
+	switch nodeType {
+	case constants.NdbNodeTypeMgmd:
+		if ndb.Spec.ManagementNode != nil {
+			ndbPdbSpec = ndb.Spec.ManagementNode.PodDisruptionBudget
+		}
+	case constants.NdbNodeTypeMySQLD:
+		if ndb.Spec.MysqlNode != nil {
+			ndbPdbSpec = ndb.Spec.MysqlNode.PodDisruptionBudget
+		}
+	}
+
+	if ndbPdbSpec != nil && ndbPdbSpec.MinAvailable != nil {
+		// Use the minAvailable specified in the NdbCluster spec
+		minAvailable := *ndbPdbSpec.MinAvailable
+		pdbSpec.MinAvailable = &minAvailable
+		return
+	}
+
+	switch nodeType {
+	case constants.NdbNodeTypeNdbmtd:
+		// Allow maximum 1 data node to be unavailable
+		minAvailable := intstr.FromInt(int(ndb.Spec.DataNode.NodeCount - 1))
+		pdbSpec.MinAvailable = &minAvailable
+	case constants.NdbNodeTypeMgmd:
+		// With a single Management node, do not allow it to be
+		// evicted. With two, allow only one to be unavailable.
+		maxUnavailable := intstr.FromInt(int(ndb.GetManagementNodeCount() - 1))
+		pdbSpec.MaxUnavailable = &maxUnavailable
+	case constants.NdbNodeTypeMySQLD:
+		// Allow maximum 1 MySQL Server to be unavailable
+		maxUnavailable := intstr.FromInt(1)
+		pdbSpec.MaxUnavailable = &maxUnavailable
+	}
+}
+
+// NewPodDisruptionBudget creates a PodDisruptionBudget for the given node type
 func NewPodDisruptionBudget(ndb *v1.NdbCluster, nodeTypeSelector string) *policyv1.PodDisruptionBudget {
 
 	// Labels for the resource
@@ -25,7 +67,6 @@ func NewPodDisruptionBudget(ndb *v1.NdbCluster, nodeTypeSelector string) *policy
 		constants.ClusterNodeTypeLabel: nodeTypeSelector,
 	})
 
-	minAvailable := intstr.FromInt(int(ndb.Spec.DataNode.NodeCount - 1))
 	pdb := &policyv1.PodDisruptionBudget{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            ndb.GetPodDisruptionBudgetName(nodeTypeSelector),
@@ -37,9 +78,25 @@ func NewPodDisruptionBudget(ndb *v1.NdbCluster, nodeTypeSelector string) *policy
 			Selector: &metav1.LabelSelector{
 				MatchLabels: selectorLabels,
 			},
-			MinAvailable: &minAvailable,
 		},
 	}
+	setPodDisruptionBudgetLimits(ndb, nodeTypeSelector, &pdb.Spec)
 
 	return pdb
 }
+
+// NewPodDisruptionBudgetV1beta1 creates a policy/v1beta1 PodDisruptionBudget
+// for the given node type. It is used with the K8s Servers that do not
+// support the policy/v1 API yet.
+func NewPodDisruptionBudgetV1beta1(
+	ndb *v1.NdbCluster, nodeTypeSelector string) *policyv1beta1.PodDisruptionBudget {
+	pdb := NewPodDisruptionBudget(ndb, nodeTypeSelector)
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: pdb.ObjectMeta,
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			Selector:       pdb.Spec.Selector,
+			MinAvailable:   pdb.Spec.MinAvailable,
+			MaxUnavailable: pdb.Spec.MaxUnavailable,
+		},
+	}
+}
@@ -22,6 +22,19 @@ const (
 	ndbOperatorPassword = "ndb-operator-password"
 )
 
+// NDBOperatorUserRecoveryKey is the key, in the ndb operator password
+// secret, that is set by the operator to request the first MySQL Server
+// to recreate the ndb operator user when it is started next. The value
+// holds the time at which the recovery was requested.
+const NDBOperatorUserRecoveryKey = "recover-ndb-operator-user"
+
+// MySQLServersResyncKey is the key, in the ndb operator password secret,
+// that is set by the operator when an initial system restart wipes the
+// schemas and the users stored in the MySQL Cluster, to restart all the
+// MySQL Servers once the data nodes have restarted. The value holds the
+// time at which the resync was requested.
+const MySQLServersResyncKey = "resync-mysql-servers"
+
 // generateRandomPassword generates a random alpha numeric password of length n
 func generateRandomPassword(n int) string {
 	b := make([]byte, n)
@@ -63,14 +76,24 @@ func GetMySQLRootPasswordSecretName(ndb *v1.NdbCluster) (secretName string, cust
 	return ndb.Name + "-" + mysqldRootPassword, false
 }
 
-// NewMySQLRootPasswordSecret creates and returns a new root password secret
+// NewMySQLRootPasswordSecret creates and returns a new root password secret.
+// The secret is owned by the NdbCluster unless it has to be retained, as
+// specified by the RootPasswordSecretPolicy.
 func NewMySQLRootPasswordSecret(ndb *v1.NdbCluster) *corev1.Secret {
 	secretName, _ := GetMySQLRootPasswordSecretName(ndb)
-	return newBasicAuthSecretWithRandomPassword(ndb, secretName, mysqldRootPassword)
+	secret := newBasicAuthSecretWithRandomPassword(ndb, secretName, mysqldRootPassword)
+	if ndb.Spec.MysqlNode.RootPasswordSecretPolicy == v1.NdbRootPasswordSecretRetain {
+		// Do not let the secret be garbage collected with the NdbCluster
+		secret.OwnerReferences = nil
+	}
+	return secret
 }
 
 // GetMySQLNDBOperatorPasswordSecretName returns the name of the ndb operator password secret
 func GetMySQLNDBOperatorPasswordSecretName(nc *v1.NdbCluster) (secretName string) {
+	if nc.Spec.MysqlNode != nil && nc.Spec.MysqlNode.NdbOperatorPasswordSecretName != "" {
+		return nc.Spec.MysqlNode.NdbOperatorPasswordSecretName
+	}
 	return nc.Name + "-" + ndbOperatorPassword
 }
 
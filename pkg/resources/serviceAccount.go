@@ -0,0 +1,87 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package resources
+
+import (
+	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller"
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// agentServiceAccountObjectMeta returns the ObjectMeta of the ServiceAccount,
+// the Role and the RoleBinding created for the agents of the MySQL Cluster pods
+func agentServiceAccountObjectMeta(nc *v1.NdbCluster) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      nc.GetAgentServiceAccountName(),
+		Namespace: nc.Namespace,
+		Labels: nc.GetCompleteLabels(map[string]string{
+			constants.ClusterResourceTypeLabel: "agent-service-account",
+		}),
+		OwnerReferences: nc.GetOwnerReferences(),
+	}
+}
+
+// getAgentPolicyRules returns the permissions to be granted to the
+// agents, which default to read access to the pods and the NdbCluster
+func getAgentPolicyRules(nc *v1.NdbCluster) []rbacv1.PolicyRule {
+	if serviceAccount := nc.Spec.ServiceAccount; serviceAccount != nil && len(serviceAccount.Rules) != 0 {
+		return serviceAccount.Rules
+	}
+
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{corev1.GroupName},
+			Resources: []string{"pods"},
+			Verbs:     []string{"list", "watch"},
+		},
+		{
+			APIGroups:     []string{ndbcontroller.GroupName},
+			Resources:     []string{"ndbclusters"},
+			ResourceNames: []string{nc.Name},
+			Verbs:         []string{"get", "watch"},
+		},
+	}
+}
+
+// NewAgentServiceAccount returns the ServiceAccount
+// the MySQL Cluster pods run as
+func NewAgentServiceAccount(nc *v1.NdbCluster) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: agentServiceAccountObjectMeta(nc),
+	}
+}
+
+// NewAgentRole returns the Role that grants the permissions
+// specified in the NdbCluster spec to the agents
+func NewAgentRole(nc *v1.NdbCluster) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: agentServiceAccountObjectMeta(nc),
+		Rules:      getAgentPolicyRules(nc),
+	}
+}
+
+// NewAgentRoleBinding returns the RoleBinding that binds
+// the Role of the agents to their ServiceAccount
+func NewAgentRoleBinding(nc *v1.NdbCluster) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: agentServiceAccountObjectMeta(nc),
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      nc.GetAgentServiceAccountName(),
+				Namespace: nc.Namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     nc.GetAgentServiceAccountName(),
+		},
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package statefulset
+
+import (
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// arbitratorStatefulSet implements the NdbStatefulSetInterface to control
+// the Management node run only to act as the external arbitrator. It runs
+// the Management node exactly like the mgmdStatefulSet, with a single
+// replica and the pod spec specified for the arbitrator.
+type arbitratorStatefulSet struct {
+	mgmdStatefulSet
+}
+
+func (ass *arbitratorStatefulSet) NewGoverningService(nc *v1.NdbCluster) *corev1.Service {
+	// The arbitrator is never exposed outside the K8s Cluster
+	return newService(nc, getMgmdPorts(nc), ass.nodeType, false, false)
+}
+
+// NewStatefulSet returns the StatefulSet specification to start and manage the arbitrator.
+func (ass *arbitratorStatefulSet) NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1.NdbCluster) (*appsv1.StatefulSet, error) {
+	statefulSet := ass.newStatefulSet(nc, cs)
+	statefulSetSpec := &statefulSet.Spec
+
+	// Fill in arbitrator specific values
+	replicas := int32(1)
+	statefulSetSpec.Replicas = &replicas
+
+	// Update template pod spec
+	podSpec := &statefulSetSpec.Template.Spec
+	podSpec.Containers = ass.getContainers(nc)
+	podSpec.Volumes = append(podSpec.Volumes, ass.getPodVolumes(nc)...)
+	// Set default AntiAffinity rules
+	podSpec.Affinity = &corev1.Affinity{
+		PodAntiAffinity: ass.getPodAntiAffinity(),
+	}
+
+	arbitratorSpec := nc.Spec.Arbitrator
+	if arbitratorSpec != nil {
+		// Copy down any podSpec specified via CRD
+		CopyPodSpecFromNdbPodSpec(podSpec, arbitratorSpec.NdbPodSpec)
+
+		// Run the arbitrator in the zone specified in the spec
+		if arbitratorSpec.Zone != "" {
+			podSpec.NodeSelector = labels.Merge(podSpec.NodeSelector, map[string]string{
+				corev1.LabelTopologyZone: arbitratorSpec.Zone,
+			})
+		}
+	}
+
+	return statefulSet, nil
+}
+
+// NewArbitratorStatefulSet returns a new NdbStatefulSetInterface for the external arbitrator
+func NewArbitratorStatefulSet() NdbStatefulSetInterface {
+	return &arbitratorStatefulSet{
+		mgmdStatefulSet{
+			baseStatefulSet{
+				nodeType: constants.NdbNodeTypeArbitrator,
+			},
+		},
+	}
+}
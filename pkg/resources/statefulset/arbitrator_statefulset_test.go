@@ -0,0 +1,72 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package statefulset
+
+import (
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_arbitratorStatefulSet(t *testing.T) {
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.ManagementNode = &v1.NdbManagementNodeSpec{
+		Image: "mysql/community-cluster:custom",
+	}
+	ndb.Spec.Arbitrator = &v1.NdbArbitratorSpec{
+		Zone: "zone-c",
+		NdbPodSpec: &v1.NdbClusterPodSpec{
+			NodeSelector: map[string]string{"example.com/arbitrator": "true"},
+		},
+	}
+	cs := &ndbconfig.ConfigSummary{
+		NdbClusterGeneration: 1,
+		NumOfManagementNodes: 2,
+		HasArbitrator:        true,
+	}
+
+	arbitratorSfset := NewArbitratorStatefulSet()
+	sfset, err := arbitratorSfset.NewStatefulSet(cs, ndb)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	if sfset.Name != "example-ndb-arbitrator" || sfset.Spec.ServiceName != "example-ndb-arbitrator" {
+		t.Errorf("Unexpected StatefulSet name %q or service name %q", sfset.Name, sfset.Spec.ServiceName)
+	}
+
+	if *sfset.Spec.Replicas != 1 {
+		t.Errorf("Expected 1 replica but got %d", *sfset.Spec.Replicas)
+	}
+
+	podTemplate := sfset.Spec.Template
+	if podTemplate.Labels[constants.ClusterNodeTypeLabel] != constants.NdbNodeTypeArbitrator {
+		t.Errorf("Expected the pods to have the node type %q", constants.NdbNodeTypeArbitrator)
+	}
+
+	// The arbitrator should run a Management node using the Management node image
+	arbitratorContainer := podTemplate.Spec.Containers[0]
+	if arbitratorContainer.Image != ndb.Spec.ManagementNode.Image {
+		t.Errorf("Expected the image %q but got %q", ndb.Spec.ManagementNode.Image, arbitratorContainer.Image)
+	}
+
+	// The arbitrator should be restricted to the zone,
+	// in addition to the node selector from the spec
+	nodeSelector := podTemplate.Spec.NodeSelector
+	if nodeSelector[corev1.LabelTopologyZone] != "zone-c" || nodeSelector["example.com/arbitrator"] != "true" {
+		t.Errorf("Unexpected node selector %v", nodeSelector)
+	}
+
+	// The governing service should never be a LoadBalancer
+	ndb.Spec.ManagementNode.EnableLoadBalancer = true
+	if svc := arbitratorSfset.NewGoverningService(ndb); svc.Spec.Type != corev1.ServiceTypeClusterIP {
+		t.Errorf("Expected a ClusterIP service but got %q", svc.Spec.Type)
+	}
+}
@@ -20,6 +20,9 @@ const (
 	// NodeIdFilePath is the location of the file that has the node's nodeId
 	NodeIdFilePath = workDirVolMount + "/nodeId.val"
 
+	// Name of the init container that runs the ndb-pod-initializer
+	ndbPodInitContainerName = "ndb-pod-init-container"
+
 	// Common volume name and mount path for data node and mgmd node helper scripts
 	helperScriptsVolName   = "helper-scripts-vol"
 	helperScriptsMountPath = constants.DataDir + "/scripts"
@@ -28,6 +31,10 @@ const (
 	LastAppliedConfigGeneration = ndbcontroller.GroupName + "/last-applied-config-generation"
 	// LastAppliedMySQLClusterConfigVersion is the annotation key that holds the last applied version of MySQL Cluster config
 	LastAppliedMySQLClusterConfigVersion = ndbcontroller.GroupName + "/last-applied-mysql-cluster-config-version"
+	// LastAppliedRestartRequest is the annotation key that holds the last applied rolling restart request
+	LastAppliedRestartRequest = ndbcontroller.GroupName + "/last-applied-restart-request"
+	// LastAppliedInitialRestartRequest is the annotation key that holds the last applied initial restart request
+	LastAppliedInitialRestartRequest = ndbcontroller.GroupName + "/last-applied-initial-restart-request"
 )
 
 // Permissions to be set to the helper scripts loaded through configmap
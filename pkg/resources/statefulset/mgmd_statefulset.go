@@ -5,6 +5,8 @@
 package statefulset
 
 import (
+	"strconv"
+
 	"github.com/mysql/ndb-operator/config/debug"
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
 	"github.com/mysql/ndb-operator/pkg/constants"
@@ -12,7 +14,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -21,10 +23,16 @@ const (
 	mgmdConfigIniMountPath  = constants.DataDir + "/config"
 )
 
-var (
-	// Ports to be exposed by the container and service
-	mgmdPorts = []int32{1186}
-)
+// getMgmdPorts returns the ports to be exposed by the
+// Management node container and service
+func getMgmdPorts(nc *v1.NdbCluster) []int32 {
+	return []int32{nc.GetManagementNodePort()}
+}
+
+// GetManagementNodeContainerName returns the name of the container running the Management Node
+func GetManagementNodeContainerName() string {
+	return constants.NdbNodeTypeMgmd + "-container"
+}
 
 // mgmdStatefulSet implements the NdbStatefulSetInterface to control a set of management nodes
 type mgmdStatefulSet struct {
@@ -32,7 +40,7 @@ type mgmdStatefulSet struct {
 }
 
 func (mss *mgmdStatefulSet) NewGoverningService(nc *v1.NdbCluster) *corev1.Service {
-	return newService(nc, mgmdPorts, mss.nodeType, false,
+	return newService(nc, getMgmdPorts(nc), mss.nodeType, false,
 		nc.Spec.ManagementNode != nil && nc.Spec.ManagementNode.EnableLoadBalancer)
 }
 
@@ -79,6 +87,11 @@ func (mss *mgmdStatefulSet) getPodVolumes(nc *v1.NdbCluster) []corev1.Volume {
 							Key:  constants.MgmdStartupProbeScript,
 							Path: constants.MgmdStartupProbeScript,
 						},
+						{
+							// Load the readiness probe
+							Key:  constants.MgmdReadinessProbeScript,
+							Path: constants.MgmdReadinessProbeScript,
+						},
 					},
 				},
 			},
@@ -106,6 +119,20 @@ func (mss *mgmdStatefulSet) getVolumeMounts() []corev1.VolumeMount {
 	}
 }
 
+// getMgmdProbeCommand returns the command to run the given probe script.
+// The port of the Management node is passed to the script only if it is
+// not the default, to retain the pod template of the existing NdbClusters.
+func getMgmdProbeCommand(nc *v1.NdbCluster, probeScript string) []string {
+	command := []string{
+		"/bin/bash",
+		helperScriptsMountPath + "/" + probeScript,
+	}
+	if port := nc.GetManagementNodePort(); port != constants.ManagementNodePort {
+		command = append(command, strconv.Itoa(int(port)))
+	}
+	return command
+}
+
 // getContainers returns the containers to run a Management Node
 func (mss *mgmdStatefulSet) getContainers(nc *v1.NdbCluster) []corev1.Container {
 
@@ -126,16 +153,13 @@ func (mss *mgmdStatefulSet) getContainers(nc *v1.NdbCluster) []corev1.Container
 
 	mgmdContainer := mss.createContainer(nc,
 		mss.getContainerName(false),
-		cmdAndArgs, mss.getVolumeMounts(), mgmdPorts)
+		cmdAndArgs, mss.getVolumeMounts(), getMgmdPorts(nc))
 
 	// Startup probe for the mgmd container
 	mgmdContainer.StartupProbe = &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
 			Exec: &corev1.ExecAction{
-				Command: []string{
-					"/bin/bash",
-					helperScriptsMountPath + "/" + constants.MgmdStartupProbeScript,
-				},
+				Command: getMgmdProbeCommand(nc, constants.MgmdStartupProbeScript),
 			},
 		},
 		// Startup probe - expects mgmd to get ready within a minute
@@ -144,13 +168,16 @@ func (mss *mgmdStatefulSet) getContainers(nc *v1.NdbCluster) []corev1.Container
 		FailureThreshold: 60,
 	}
 
-	// Readiness probe checks if the port 1186 is open
+	// Readiness probe checks if the mgmd is able to serve the clients
 	mgmdContainer.ReadinessProbe = &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
-			TCPSocket: &corev1.TCPSocketAction{
-				Port: intstr.FromInt(1186),
+			Exec: &corev1.ExecAction{
+				Command: getMgmdProbeCommand(nc, constants.MgmdReadinessProbeScript),
 			},
 		},
+		PeriodSeconds:    5,
+		TimeoutSeconds:   10,
+		FailureThreshold: 3,
 	}
 
 	return []corev1.Container{mgmdContainer}
@@ -163,6 +190,30 @@ func (mss *mgmdStatefulSet) getPodAntiAffinity() *corev1.PodAntiAffinity {
 	})
 }
 
+// getSpreadPodAffinityTerms returns the WeightedPodAffinityTerms that spread
+// the Management nodes of the MySQL Cluster across the worker nodes and the
+// zones. Two Management nodes act as the arbitrator for each other, and a
+// single failure taking down both would leave the Data nodes without an
+// arbitrator. The terms have a weight larger than the sum of the weights
+// of the default rules, so that spreading them takes precedence.
+func (mss *mgmdStatefulSet) getSpreadPodAffinityTerms(nc *v1.NdbCluster) []corev1.WeightedPodAffinityTerm {
+	mgmdPodSelector := &metav1.LabelSelector{
+		MatchLabels: mss.getPodLabels(nc),
+	}
+
+	var terms []corev1.WeightedPodAffinityTerm
+	for _, topologyKey := range []string{corev1.LabelHostname, corev1.LabelTopologyZone} {
+		terms = append(terms, corev1.WeightedPodAffinityTerm{
+			Weight: 100,
+			PodAffinityTerm: corev1.PodAffinityTerm{
+				LabelSelector: mgmdPodSelector,
+				TopologyKey:   topologyKey,
+			},
+		})
+	}
+	return terms
+}
+
 // NewStatefulSet returns the StatefulSet specification to start and manage the Management nodes.
 func (mss *mgmdStatefulSet) NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1.NdbCluster) (*appsv1.StatefulSet, error) {
 	statefulSet := mss.newStatefulSet(nc, cs)
@@ -171,8 +222,14 @@ func (mss *mgmdStatefulSet) NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1.N
 	// Fill in mgmd specific values
 	replicas := cs.NumOfManagementNodes
 	statefulSetSpec.Replicas = &replicas
-	// Set pod management policy to start Management nodes one by one
-	statefulSetSpec.PodManagementPolicy = appsv1.OrderedReadyPodManagement
+	// Set pod management policy to start Management nodes one
+	// by one, unless a different policy is specified in the spec
+	statefulSetSpec.PodManagementPolicy = nc.GetPodManagementPolicy(mss.nodeType)
+
+	// Use the update strategy specified in the spec, if any
+	if updateStrategy := nc.GetUpdateStrategy(mss.nodeType); updateStrategy != nil {
+		statefulSetSpec.UpdateStrategy = *updateStrategy.DeepCopy()
+	}
 
 	// Update template pod spec
 	podSpec := &statefulSetSpec.Template.Spec
@@ -182,11 +239,19 @@ func (mss *mgmdStatefulSet) NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1.N
 	podSpec.Affinity = &corev1.Affinity{
 		PodAntiAffinity: mss.getPodAntiAffinity(),
 	}
+	if replicas > 1 {
+		podAntiAffinity := podSpec.Affinity.PodAntiAffinity
+		podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, mss.getSpreadPodAffinityTerms(nc)...)
+	}
 	// Copy down any podSpec specified via CRD
 	if nc.Spec.ManagementNode != nil {
 		CopyPodSpecFromNdbPodSpec(podSpec, nc.Spec.ManagementNode.NdbPodSpec)
 	}
 
+	// Run the pods in the host network if required
+	mss.setHostNetwork(nc, &statefulSetSpec.Template)
+
 	return statefulSet, nil
 }
 
@@ -0,0 +1,64 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package statefulset
+
+import (
+	"testing"
+
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_mgmdStatefulSet_PodAntiAffinity(t *testing.T) {
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+
+	for _, tc := range []struct {
+		nodeCount int32
+		// expectedSpreadKeys are the topology keys across
+		// which the Management nodes should be spread
+		expectedSpreadKeys []string
+	}{
+		{nodeCount: 1},
+		{nodeCount: 2, expectedSpreadKeys: []string{corev1.LabelHostname, corev1.LabelTopologyZone}},
+	} {
+		cs := &ndbconfig.ConfigSummary{
+			NdbClusterGeneration: 1,
+			NumOfManagementNodes: tc.nodeCount,
+		}
+		sfset, err := NewMgmdStatefulSet().NewStatefulSet(cs, ndb)
+		if err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+
+		var spreadKeys []string
+		for _, term := range sfset.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			matchLabels := term.PodAffinityTerm.LabelSelector.MatchLabels
+			if matchLabels[constants.ClusterLabel] != ndb.Name {
+				// The default rule matching all the MySQL Cluster nodes of a type
+				continue
+			}
+			if matchLabels[constants.ClusterNodeTypeLabel] != constants.NdbNodeTypeMgmd || term.Weight != 100 {
+				t.Errorf("%d Management nodes : unexpected pod anti affinity term %v", tc.nodeCount, term)
+			}
+			spreadKeys = append(spreadKeys, term.PodAffinityTerm.TopologyKey)
+		}
+
+		if len(spreadKeys) != len(tc.expectedSpreadKeys) {
+			t.Errorf("%d Management nodes : expected to be spread across %v but got %v",
+				tc.nodeCount, tc.expectedSpreadKeys, spreadKeys)
+			continue
+		}
+		for i := range spreadKeys {
+			if spreadKeys[i] != tc.expectedSpreadKeys[i] {
+				t.Errorf("%d Management nodes : expected to be spread across %v but got %v",
+					tc.nodeCount, tc.expectedSpreadKeys, spreadKeys)
+				break
+			}
+		}
+	}
+}
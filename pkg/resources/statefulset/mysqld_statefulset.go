@@ -5,6 +5,7 @@
 package statefulset
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/mysql/ndb-operator/config/debug"
@@ -17,6 +18,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	listerscorev1 "k8s.io/client-go/listers/core/v1"
 	klog "k8s.io/klog/v2"
 )
@@ -34,26 +36,99 @@ const (
 	mysqldCnfVolName   = mysqldClientName + "-cnf-vol"
 	mysqldCnfMountPath = mysqldDir + "/cnf"
 
+	// Plugin directory volume and mount path, used when custom plugins are specified
+	mysqldPluginDirVolName   = mysqldClientName + "-plugin-dir-vol"
+	mysqldPluginDirMountPath = mysqldDir + "/plugins"
+	// Name of the init container that populates the plugin directory
+	mysqldPluginDirInitContainerName = "mysqld-plugin-dir-init"
+
+	// Audit log volume and mount path, used when the audit log is enabled
+	mysqldAuditLogVolName   = mysqldClientName + "-audit-log-vol"
+	mysqldAuditLogMountPath = mysqldDir + "/audit-log"
+
+	// Secrets Store CSI volume and mount path, used when a SecretProviderClass is specified
+	mysqldSecretsStoreVolName   = mysqldClientName + "-secrets-store-vol"
+	mysqldSecretsStoreMountPath = mysqldDir + "/secrets-store"
+	// Name of the Secrets Store CSI Driver
+	secretsStoreCSIDriver = "secrets-store.csi.k8s.io"
+
 	// LastAppliedMySQLServerConfigVersion is the annotation key that holds the last applied version of MySQL Server config (my.cnf version)
 	LastAppliedMySQLServerConfigVersion = ndbcontroller.GroupName + "/last-applied-my-cnf-config-version"
 	// RootPasswordSecret is the name of the secret that holds the password for the root account
 	RootPasswordSecret = ndbcontroller.GroupName + "/root-password-secret"
 )
 
-var (
-	// Ports to be exposed by the container and service
-	mysqldPorts = []int32{3306}
-)
+// getMysqldPorts returns the ports to be exposed by
+// the MySQL Server container and service
+func getMysqldPorts(nc *v1.NdbCluster) []int32 {
+	return []int32{nc.GetMySQLServerPort()}
+}
 
 // mysqldStatefulSet implements the NdbStatefulSetInterface
 // to control a set of MySQL Servers
 type mysqldStatefulSet struct {
 	baseStatefulSet
 	configMapLister listerscorev1.ConfigMapLister
+	// serverGroup is the name of the MySQL Server group
+	// controlled by the StatefulSet, if any.
+	serverGroup string
+}
+
+// GetName returns the name of the MySQL Server StatefulSet
+func (mss *mysqldStatefulSet) GetName(nc *v1.NdbCluster) string {
+	if mss.serverGroup != "" {
+		return nc.GetMySQLServerGroupWorkloadName(mss.serverGroup)
+	}
+	return mss.baseStatefulSet.GetName(nc)
+}
+
+// GetServiceName returns the name of the governing Service of the MySQL Server StatefulSet
+func (mss *mysqldStatefulSet) GetServiceName(nc *v1.NdbCluster) string {
+	if mss.serverGroup != "" {
+		return nc.GetMySQLServerGroupWorkloadName(mss.serverGroup)
+	}
+	return mss.baseStatefulSet.GetServiceName(nc)
+}
+
+// getServerGroupPodLabels returns the labels of the pods of the MySQL Server group.
+// The ClusterNodeTypeLabel is not set to keep the pods out of the selectors
+// of the Service, the PodDisruptionBudget and the scale subresource of the
+// MySQL Servers specified by the spec.mysqlNode.
+func (mss *mysqldStatefulSet) getServerGroupPodLabels(nc *v1.NdbCluster) map[string]string {
+	return nc.GetCompleteLabels(map[string]string{
+		constants.MySQLServerGroupLabel: mss.serverGroup,
+	})
 }
 
 func (mss *mysqldStatefulSet) NewGoverningService(nc *v1.NdbCluster) *corev1.Service {
-	return newService(nc, mysqldPorts, mss.nodeType, false, nc.Spec.MysqlNode.EnableLoadBalancer)
+	if mss.serverGroup == "" {
+		return newService(nc, getMysqldPorts(nc), mss.nodeType, false, nc.Spec.MysqlNode.EnableLoadBalancer)
+	}
+
+	// Service of a MySQL Server group
+	var enableLoadBalancer bool
+	if serverGroupSpec := nc.GetMySQLServerGroup(mss.serverGroup); serverGroupSpec != nil {
+		enableLoadBalancer = serverGroupSpec.EnableLoadBalancer
+	}
+	svc := newService(nc, getMysqldPorts(nc), mss.nodeType, false, enableLoadBalancer)
+	svc.Name = mss.GetServiceName(nc)
+	svc.Labels[constants.MySQLServerGroupLabel] = mss.serverGroup
+	svc.Spec.Selector = mss.getServerGroupPodLabels(nc)
+	return svc
+}
+
+// getMySQLCnfKey returns the configmap key of the my.cnf
+// of the MySQL Servers and whether it has a value.
+func (mss *mysqldStatefulSet) getMySQLCnfKey(nc *v1.NdbCluster) (configKey string, exists bool) {
+	if mss.serverGroup == "" {
+		return constants.MySQLConfigKey, len(nc.GetMySQLCnf()) > 0
+	}
+
+	configKey = ndbconfig.GetMySQLServerGroupConfigKey(mss.serverGroup)
+	if serverGroupSpec := nc.GetMySQLServerGroup(mss.serverGroup); serverGroupSpec != nil {
+		exists = len(serverGroupSpec.GetMySQLCnf()) > 0
+	}
+	return configKey, exists
 }
 
 // getPodVolumes returns the volumes to be used by the pod
@@ -134,7 +209,7 @@ func (mss *mysqldStatefulSet) getPodVolumes(ndb *v1.NdbCluster) ([]corev1.Volume
 		},
 	})
 
-	if len(ndb.GetMySQLCnf()) > 0 {
+	if myCnfKey, exists := mss.getMySQLCnfKey(ndb); exists {
 		// Load the cnf configmap key as a volume
 		podVolumes = append(podVolumes, corev1.Volume{
 			Name: mysqldCnfVolName,
@@ -145,7 +220,7 @@ func (mss *mysqldStatefulSet) getPodVolumes(ndb *v1.NdbCluster) ([]corev1.Volume
 					},
 					Items: []corev1.KeyToPath{
 						{
-							Key:  constants.MySQLConfigKey,
+							Key:  myCnfKey,
 							Path: constants.MySQLConfigKey,
 						},
 					},
@@ -161,6 +236,35 @@ func (mss *mysqldStatefulSet) getPodVolumes(ndb *v1.NdbCluster) ([]corev1.Volume
 		podVolumes = append(podVolumes, *mss.getEmptyDirPodVolume(mss.getDataDirVolumeName()))
 	}
 
+	if ndb.Spec.MysqlNode.Plugins != nil {
+		// The plugin directory is populated by the init containers
+		podVolumes = append(podVolumes, *mss.getEmptyDirPodVolume(mysqldPluginDirVolName))
+	}
+
+	if auditLog := ndb.Spec.MysqlNode.AuditLog; auditLog != nil && auditLog.PVCSpec == nil {
+		// Store the audit log files in an empty directory
+		// volume as no PVC has been specified for them
+		podVolumes = append(podVolumes, *mss.getEmptyDirPodVolume(mysqldAuditLogVolName))
+	}
+
+	if secretProviderClass := ndb.Spec.MysqlNode.SecretProviderClass; secretProviderClass != "" {
+		// Mount the secrets from the external secret store via the Secrets Store
+		// CSI Driver, which also syncs them into the Secrets of the namespace
+		readOnly := true
+		podVolumes = append(podVolumes, corev1.Volume{
+			Name: mysqldSecretsStoreVolName,
+			VolumeSource: corev1.VolumeSource{
+				CSI: &corev1.CSIVolumeSource{
+					Driver:   secretsStoreCSIDriver,
+					ReadOnly: &readOnly,
+					VolumeAttributes: map[string]string{
+						"secretProviderClass": secretProviderClass,
+					},
+				},
+			},
+		})
+	}
+
 	return podVolumes, nil
 }
 
@@ -183,7 +287,7 @@ func (mss *mysqldStatefulSet) getVolumeMounts(nc *v1.NdbCluster) []corev1.Volume
 		mss.getWorkDirVolumeMount(),
 	}
 
-	if len(nc.GetMySQLCnf()) > 0 {
+	if _, exists := mss.getMySQLCnfKey(nc); exists {
 		// Mount the cnf volume
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
 			Name:      mysqldCnfVolName,
@@ -191,9 +295,114 @@ func (mss *mysqldStatefulSet) getVolumeMounts(nc *v1.NdbCluster) []corev1.Volume
 		})
 	}
 
+	if nc.Spec.MysqlNode.Plugins != nil {
+		// Mount the plugin directory volume
+		volumeMounts = append(volumeMounts, mss.getPluginDirVolumeMount())
+	}
+
+	if nc.Spec.MysqlNode.AuditLog != nil {
+		// Mount the audit log volume
+		volumeMounts = append(volumeMounts, mss.getAuditLogVolumeMount())
+	}
+
+	if nc.Spec.MysqlNode.SecretProviderClass != "" {
+		// Mount the Secrets Store CSI volume
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      mysqldSecretsStoreVolName,
+			MountPath: mysqldSecretsStoreMountPath,
+			ReadOnly:  true,
+		})
+	}
+
 	return volumeMounts
 }
 
+// getAuditLogVolumeMount returns the volume mount of the audit log volume
+func (mss *mysqldStatefulSet) getAuditLogVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      mysqldAuditLogVolName,
+		MountPath: mysqldAuditLogMountPath,
+	}
+}
+
+// getAuditLogSidecarContainers returns the sidecar containers specified
+// in the spec to ship the audit log files, with the audit log volume
+// mounted into them.
+func (mss *mysqldStatefulSet) getAuditLogSidecarContainers(nc *v1.NdbCluster) []corev1.Container {
+	auditLog := nc.Spec.MysqlNode.AuditLog
+	if auditLog == nil {
+		return nil
+	}
+
+	var sidecarContainers []corev1.Container
+	for i := range auditLog.SidecarContainers {
+		sidecarContainer := auditLog.SidecarContainers[i].DeepCopy()
+		sidecarContainer.VolumeMounts = append(sidecarContainer.VolumeMounts, mss.getAuditLogVolumeMount())
+		sidecarContainers = append(sidecarContainers, *sidecarContainer)
+	}
+	return sidecarContainers
+}
+
+// getAuditLogArgs returns the MySQL Server arguments that
+// enable the audit log with the settings from the spec
+func getAuditLogArgs(auditLog *v1.NdbMysqldAuditLogSpec) []string {
+	args := []string{
+		// Load the plugin and prevent it from being uninstalled at runtime
+		"--plugin-load-add=audit_log.so",
+		"--audit-log=FORCE_PLUS_PERMANENT",
+		"--audit-log-file=" + mysqldAuditLogMountPath + "/audit.log",
+	}
+
+	if auditLog.Format != "" {
+		args = append(args, "--audit-log-format="+auditLog.Format)
+	}
+	if auditLog.Policy != "" {
+		args = append(args, "--audit-log-policy="+auditLog.Policy)
+	}
+	if auditLog.RotateOnSize != nil {
+		args = append(args, "--audit-log-rotate-on-size="+strconv.FormatInt(auditLog.RotateOnSize.Value(), 10))
+	}
+
+	return args
+}
+
+// getPluginDirVolumeMount returns the volume mount of the plugin directory
+func (mss *mysqldStatefulSet) getPluginDirVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      mysqldPluginDirVolName,
+		MountPath: mysqldPluginDirMountPath,
+	}
+}
+
+// getPluginInitContainers returns the init containers that populate the
+// plugin directory of the MySQL Server. The first one copies the plugins
+// and components shipped with the MySQL Server image, and the rest are the
+// init containers specified in the spec to install the custom plugins.
+func (mss *mysqldStatefulSet) getPluginInitContainers(nc *v1.NdbCluster) []corev1.Container {
+	pluginsSpec := nc.Spec.MysqlNode.Plugins
+	if pluginsSpec == nil {
+		return nil
+	}
+
+	// Copy the contents of the default plugin directory of the image
+	copyDefaultPluginsCmd := fmt.Sprintf(
+		`cp -a "$(mysqld --no-defaults --verbose --help 2>/dev/null | `+
+			`awk '$1 == "plugin-dir" { print $2; exit }')"/. %s/`, mysqldPluginDirMountPath)
+	initContainers := []corev1.Container{
+		mss.createContainer(nc, mysqldPluginDirInitContainerName,
+			[]string{"/bin/bash", "-c", copyDefaultPluginsCmd},
+			[]corev1.VolumeMount{mss.getPluginDirVolumeMount()}, nil),
+	}
+
+	for i := range pluginsSpec.InitContainers {
+		initContainer := pluginsSpec.InitContainers[i].DeepCopy()
+		initContainer.VolumeMounts = append(initContainer.VolumeMounts, mss.getPluginDirVolumeMount())
+		initContainers = append(initContainers, *initContainer)
+	}
+
+	return initContainers
+}
+
 // getMySQLServerCmd returns the command and arguments to start the MySQL Server
 func (mss *mysqldStatefulSet) getMySQLServerCmd(nc *v1.NdbCluster) []string {
 	cmdAndArgs := []string{
@@ -202,7 +411,7 @@ func (mss *mysqldStatefulSet) getMySQLServerCmd(nc *v1.NdbCluster) []string {
 
 	// Add the arguments to the command
 	// first, pass any provided cnf options via defaults-file
-	if len(nc.GetMySQLCnf()) > 0 {
+	if _, exists := mss.getMySQLCnfKey(nc); exists {
 		cmdAndArgs = append(cmdAndArgs,
 			"--defaults-file="+mysqldCnfMountPath+"/"+constants.MySQLConfigKey)
 	}
@@ -218,6 +427,21 @@ func (mss *mysqldStatefulSet) getMySQLServerCmd(nc *v1.NdbCluster) []string {
 		"--ndb-cluster-connection-pool-nodeids=$(cat "+NodeIdFilePath+")",
 	)
 
+	if port := nc.GetMySQLServerPort(); port != constants.MySQLServerPort {
+		// Listen on the port specified in the spec
+		cmdAndArgs = append(cmdAndArgs, "--port="+strconv.Itoa(int(port)))
+	}
+
+	if nc.Spec.MysqlNode.Plugins != nil {
+		// Load the plugins from the plugin directory populated by the init containers
+		cmdAndArgs = append(cmdAndArgs, "--plugin-dir="+mysqldPluginDirMountPath)
+	}
+
+	if auditLog := nc.Spec.MysqlNode.AuditLog; auditLog != nil {
+		// Enable the audit log
+		cmdAndArgs = append(cmdAndArgs, getAuditLogArgs(auditLog)...)
+	}
+
 	if debug.Enabled {
 		cmdAndArgs = append(cmdAndArgs,
 			// Enable maximum verbosity for development debugging
@@ -226,6 +450,13 @@ func (mss *mysqldStatefulSet) getMySQLServerCmd(nc *v1.NdbCluster) []string {
 		)
 	}
 
+	// Finally, append any extra arguments specified in the spec. They are
+	// quoted as the command is run by a shell, so that every argument reaches
+	// the MySQL Server as it is, without being split or expanded by the shell.
+	for _, arg := range nc.Spec.MysqlNode.ExtraArgs {
+		cmdAndArgs = append(cmdAndArgs, helpers.ShellQuote(arg))
+	}
+
 	return cmdAndArgs
 }
 
@@ -238,11 +469,12 @@ func (mss *mysqldStatefulSet) getInitDBContainer(nc *v1.NdbCluster) corev1.Conta
 
 	mysqlInitContainer := mss.createContainer(nc,
 		mss.getContainerName(true),
-		cmdAndArgs, mss.getVolumeMounts(nc), mysqldPorts)
+		cmdAndArgs, mss.getVolumeMounts(nc), getMysqldPorts(nc))
 
 	// Add Env variables required by init script
 	ndbOperatorPodNamespace, _ := helpers.GetCurrentNamespace()
 	rootPasswordSecretName, _ := resources.GetMySQLRootPasswordSecretName(nc)
+	optional := true
 	mysqlInitContainer.Env = append(mysqlInitContainer.Env, corev1.EnvVar{
 		// Password of the root user
 		Name: "MYSQL_ROOT_PASSWORD",
@@ -270,8 +502,29 @@ func (mss *mysqldStatefulSet) getInitDBContainer(nc *v1.NdbCluster) corev1.Conta
 				Key: corev1.BasicAuthPasswordKey,
 			},
 		},
+	}, corev1.EnvVar{
+		// Set only when the operator has requested the
+		// recovery of the NDB operator user
+		Name: "NDB_OPERATOR_USER_RECOVERY",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: resources.GetMySQLNDBOperatorPasswordSecretName(nc),
+				},
+				Key:      resources.NDBOperatorUserRecoveryKey,
+				Optional: &optional,
+			},
+		},
 	})
 
+	if mss.serverGroup != "" {
+		mysqlInitContainer.Env = append(mysqlInitContainer.Env, corev1.EnvVar{
+			// Name of the MySQL Server group
+			Name:  "NDB_MYSQLD_SERVER_GROUP",
+			Value: mss.serverGroup,
+		})
+	}
+
 	return mysqlInitContainer
 }
 
@@ -279,7 +532,7 @@ func (mss *mysqldStatefulSet) getInitDBContainer(nc *v1.NdbCluster) corev1.Conta
 func (mss *mysqldStatefulSet) getContainers(nc *v1.NdbCluster) []corev1.Container {
 	mysqldContainer := mss.createContainer(nc,
 		mss.getContainerName(false),
-		mss.getMySQLServerCmd(nc), mss.getVolumeMounts(nc), mysqldPorts)
+		mss.getMySQLServerCmd(nc), mss.getVolumeMounts(nc), getMysqldPorts(nc))
 
 	// Create an exec handler that runs the MysqldHealthCheckScript to be used in health probes
 	healthProbeHandler := corev1.ProbeHandler{
@@ -324,8 +577,14 @@ func (mss *mysqldStatefulSet) NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1
 	// Fill in MySQL Server specific details
 	replicas := nc.GetMySQLServerNodeCount()
 	statefulSetSpec.Replicas = &replicas
-	// Set pod management policy to start MySQL Servers in parallel
-	statefulSetSpec.PodManagementPolicy = appsv1.ParallelPodManagement
+	// Set pod management policy to start MySQL Servers in
+	// parallel, unless a different policy is specified in the spec
+	statefulSetSpec.PodManagementPolicy = nc.GetPodManagementPolicy(mss.nodeType)
+
+	// Use the update strategy specified in the spec, if any
+	if updateStrategy := nc.GetUpdateStrategy(mss.nodeType); updateStrategy != nil {
+		statefulSetSpec.UpdateStrategy = *updateStrategy.DeepCopy()
+	}
 
 	// Update statefulset annotation
 	statefulSetAnnotations := statefulSet.GetAnnotations()
@@ -340,10 +599,18 @@ func (mss *mysqldStatefulSet) NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1
 		}
 	}
 
+	// Add VolumeClaimTemplate if audit log PVC Spec exists
+	if auditLog := nc.Spec.MysqlNode.AuditLog; auditLog != nil && auditLog.PVCSpec != nil {
+		statefulSetSpec.VolumeClaimTemplates = append(statefulSetSpec.VolumeClaimTemplates,
+			*newPVC(nc, mysqldAuditLogVolName, auditLog.PVCSpec))
+	}
+
 	// Update template pod spec
 	podSpec := &statefulSetSpec.Template.Spec
+	podSpec.InitContainers = append(podSpec.InitContainers, mss.getPluginInitContainers(nc)...)
 	podSpec.InitContainers = append(podSpec.InitContainers, mss.getInitDBContainer(nc))
 	podSpec.Containers = mss.getContainers(nc)
+	podSpec.Containers = append(podSpec.Containers, mss.getAuditLogSidecarContainers(nc)...)
 
 	podVolumes, err := mss.getPodVolumes(nc)
 	if err != nil {
@@ -364,15 +631,80 @@ func (mss *mysqldStatefulSet) NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1
 	podAnnotations := statefulSetSpec.Template.GetAnnotations()
 	podAnnotations[LastAppliedMySQLServerConfigVersion] = strconv.FormatInt(int64(cs.MySQLServerConfigVersion), 10)
 
+	if mss.serverGroup != "" {
+		if err = mss.updateServerGroupStatefulSet(statefulSet, cs, nc); err != nil {
+			return nil, err
+		}
+	}
+
+	// Run the pods in the host network if required
+	mss.setHostNetwork(nc, &statefulSetSpec.Template)
+
 	return statefulSet, nil
 }
 
+// updateServerGroupStatefulSet updates the given MySQL Server
+// StatefulSet to run the MySQL Servers of the MySQL Server group.
+func (mss *mysqldStatefulSet) updateServerGroupStatefulSet(
+	statefulSet *appsv1.StatefulSet, cs *ndbconfig.ConfigSummary, nc *v1.NdbCluster) error {
+
+	// The number of MySQL Servers and the nodeIds
+	// reserved for the group are taken from the config
+	serverGroup := cs.GetMySQLServerGroup(mss.serverGroup)
+	if serverGroup == nil {
+		err := fmt.Errorf("MySQL Server group %q not found in the config", mss.serverGroup)
+		klog.Errorf("Failed to create the StatefulSet for the MySQL Server group : %s", err)
+		return err
+	}
+
+	// Update the name, the labels and the selector
+	podLabels := mss.getServerGroupPodLabels(nc)
+	statefulSet.Name = mss.GetName(nc)
+	statefulSet.Labels[constants.MySQLServerGroupLabel] = mss.serverGroup
+	statefulSetSpec := &statefulSet.Spec
+	statefulSetSpec.Selector.MatchLabels = podLabels
+	statefulSetSpec.Template.Labels = labels.Merge(nc.GetCustomPodLabels(mss.nodeType), podLabels)
+	statefulSetSpec.ServiceName = mss.GetServiceName(nc)
+	replicas := serverGroup.NodeCount
+	statefulSetSpec.Replicas = &replicas
+
+	// Pass the first nodeId reserved for the group to the ndb-pod-initializer
+	podSpec := &statefulSetSpec.Template.Spec
+	for i := range podSpec.InitContainers {
+		if podSpec.InitContainers[i].Name == ndbPodInitContainerName {
+			podSpec.InitContainers[i].Env = append(podSpec.InitContainers[i].Env, corev1.EnvVar{
+				Name:  "NDB_MYSQLD_START_NODE_ID",
+				Value: strconv.Itoa(int(serverGroup.StartNodeId)),
+			})
+		}
+	}
+
+	// Annotate the spec template with the version of the my.cnf of the group
+	statefulSetSpec.Template.Annotations[LastAppliedMySQLServerConfigVersion] =
+		strconv.FormatInt(int64(serverGroup.MySQLServerConfigVersion), 10)
+
+	return nil
+}
+
 // NewMySQLdStatefulSet returns a new mysqldStatefulSet
 func NewMySQLdStatefulSet(configMapLister listerscorev1.ConfigMapLister) NdbStatefulSetInterface {
 	return &mysqldStatefulSet{
-		baseStatefulSet{
+		baseStatefulSet: baseStatefulSet{
+			nodeType: constants.NdbNodeTypeMySQLD,
+		},
+		configMapLister: configMapLister,
+	}
+}
+
+// NewMySQLdServerGroupStatefulSet returns a new mysqldStatefulSet
+// that controls the MySQL Servers of the given MySQL Server group
+func NewMySQLdServerGroupStatefulSet(
+	configMapLister listerscorev1.ConfigMapLister, serverGroup string) NdbStatefulSetInterface {
+	return &mysqldStatefulSet{
+		baseStatefulSet: baseStatefulSet{
 			nodeType: constants.NdbNodeTypeMySQLD,
 		},
-		configMapLister,
+		configMapLister: configMapLister,
+		serverGroup:     serverGroup,
 	}
 }
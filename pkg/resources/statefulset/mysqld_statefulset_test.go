@@ -0,0 +1,243 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package statefulset
+
+import (
+	"strings"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func Test_mysqldStatefulSet_ExtraArgsAndPlugins(t *testing.T) {
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.MysqlNode = &v1.NdbMysqldSpec{
+		NodeCount: 2,
+		ExtraArgs: []string{"--log-bin=binlog", "--init-file=$(id) --ndb-connectstring=x"},
+		Plugins: &v1.NdbMysqldPluginsSpec{
+			InitContainers: []corev1.Container{{Name: "install-audit-plugin", Image: "example.com/audit-plugin"}},
+			Plugins:        []v1.NdbMysqldPlugin{{Name: "audit_log", Library: "audit_log.so"}},
+		},
+	}
+	cs := &ndbconfig.ConfigSummary{
+		NdbClusterGeneration: 1,
+		NumOfMySQLServers:    2,
+	}
+
+	mysqldSfset := NewMySQLdStatefulSet(nil)
+	sfset, err := mysqldSfset.NewStatefulSet(cs, ndb)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	podSpec := sfset.Spec.Template.Spec
+
+	// The plugin directory should be populated by the init containers before the data directory is initialised
+	var initContainerNames []string
+	for _, initContainer := range podSpec.InitContainers {
+		initContainerNames = append(initContainerNames, initContainer.Name)
+	}
+	expectedInitContainerNames := []string{
+		ndbPodInitContainerName, mysqldPluginDirInitContainerName,
+		"install-audit-plugin", mysqldSfset.(*mysqldStatefulSet).getContainerName(true),
+	}
+	if strings.Join(initContainerNames, ",") != strings.Join(expectedInitContainerNames, ",") {
+		t.Errorf("Expected the init containers %v but got %v", expectedInitContainerNames, initContainerNames)
+	}
+	for _, initContainer := range podSpec.InitContainers[1:] {
+		if !hasPluginDirVolumeMount(initContainer) {
+			t.Errorf("Plugin directory is not mounted in the init container %q", initContainer.Name)
+		}
+	}
+
+	// The plugin directory and the extra args should be passed to the MySQL Server
+	mysqldContainer := podSpec.Containers[0]
+	if !hasPluginDirVolumeMount(mysqldContainer) {
+		t.Error("Plugin directory is not mounted in the MySQL Server container")
+	}
+	cmd := strings.Join(mysqldContainer.Command, " ")
+	if !strings.Contains(cmd, "--plugin-dir="+mysqldPluginDirMountPath) {
+		t.Errorf("Plugin directory is not passed to the MySQL Server : %s", cmd)
+	}
+	if !strings.HasSuffix(cmd, `'--log-bin=binlog' '--init-file=$(id) --ndb-connectstring=x'`) {
+		t.Errorf("Extra args are not quoted and appended to the MySQL Server command : %s", cmd)
+	}
+}
+
+// hasPluginDirVolumeMount returns true if the plugin directory is mounted in the given container
+func hasPluginDirVolumeMount(container corev1.Container) bool {
+	for _, volumeMount := range container.VolumeMounts {
+		if volumeMount.Name == mysqldPluginDirVolName && volumeMount.MountPath == mysqldPluginDirMountPath {
+			return true
+		}
+	}
+	return false
+}
+
+func Test_mysqldStatefulSet_AuditLog(t *testing.T) {
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	rotateOnSize := resource.MustParse("100Mi")
+	ndb.Spec.MysqlNode = &v1.NdbMysqldSpec{
+		NodeCount: 2,
+		AuditLog: &v1.NdbMysqldAuditLogSpec{
+			Format:       "JSON",
+			RotateOnSize: &rotateOnSize,
+			PVCSpec: &corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			},
+			SidecarContainers: []corev1.Container{{Name: "audit-log-shipper", Image: "example.com/log-shipper"}},
+		},
+	}
+	cs := &ndbconfig.ConfigSummary{
+		NdbClusterGeneration: 1,
+		NumOfMySQLServers:    2,
+	}
+
+	sfset, err := NewMySQLdStatefulSet(nil).NewStatefulSet(cs, ndb)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	// The audit log files should be stored in the PVCs
+	if len(sfset.Spec.VolumeClaimTemplates) != 1 || sfset.Spec.VolumeClaimTemplates[0].Name != mysqldAuditLogVolName {
+		t.Errorf("Expected a VolumeClaimTemplate for the audit log but got %v", sfset.Spec.VolumeClaimTemplates)
+	}
+
+	// The audit log should be enabled in the MySQL Server
+	containers := sfset.Spec.Template.Spec.Containers
+	cmd := strings.Join(containers[0].Command, " ")
+	for _, arg := range []string{
+		"--plugin-load-add=audit_log.so",
+		"--audit-log-file=" + mysqldAuditLogMountPath + "/audit.log",
+		"--audit-log-format=JSON",
+		"--audit-log-rotate-on-size=104857600",
+	} {
+		if !strings.Contains(cmd, arg) {
+			t.Errorf("Expected the MySQL Server command to have %q : %s", arg, cmd)
+		}
+	}
+
+	// The sidecar should be able to read the audit log files
+	if len(containers) != 2 || containers[1].Name != "audit-log-shipper" {
+		t.Fatalf("Expected the audit log sidecar to be run in the pod")
+	}
+	for _, container := range containers {
+		var mounted bool
+		for _, volumeMount := range container.VolumeMounts {
+			mounted = mounted || volumeMount.Name == mysqldAuditLogVolName
+		}
+		if !mounted {
+			t.Errorf("Audit log volume is not mounted in the container %q", container.Name)
+		}
+	}
+}
+
+func Test_mysqldStatefulSet_ExternalSecrets(t *testing.T) {
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.MysqlNode = &v1.NdbMysqldSpec{
+		NodeCount:                     2,
+		RootPasswordSecretName:        "vault-root-password",
+		NdbOperatorPasswordSecretName: "vault-operator-password",
+		SecretProviderClass:           "vault-root-password",
+	}
+	cs := &ndbconfig.ConfigSummary{
+		NdbClusterGeneration: 1,
+		NumOfMySQLServers:    2,
+	}
+
+	sfset, err := NewMySQLdStatefulSet(nil).NewStatefulSet(cs, ndb)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+	podSpec := sfset.Spec.Template.Spec
+
+	// The SecretProviderClass should be mounted via the Secrets Store CSI Driver
+	var csiVolume *corev1.CSIVolumeSource
+	for _, volume := range podSpec.Volumes {
+		if volume.Name == mysqldSecretsStoreVolName {
+			csiVolume = volume.CSI
+		}
+	}
+	if csiVolume == nil || csiVolume.Driver != secretsStoreCSIDriver ||
+		csiVolume.VolumeAttributes["secretProviderClass"] != "vault-root-password" {
+		t.Fatalf("Expected a Secrets Store CSI volume but got %v", csiVolume)
+	}
+
+	// The passwords should be read from the custom secrets
+	secretNames := make(map[string]string)
+	for _, initContainer := range podSpec.InitContainers {
+		for _, env := range initContainer.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				secretNames[env.Name] = env.ValueFrom.SecretKeyRef.Name
+			}
+		}
+	}
+	if secretNames["MYSQL_ROOT_PASSWORD"] != "vault-root-password" ||
+		secretNames["NDB_OPERATOR_PASSWORD"] != "vault-operator-password" {
+		t.Errorf("Passwords are not read from the custom secrets : %v", secretNames)
+	}
+}
+
+func Test_mysqldStatefulSet_LoadBalancer(t *testing.T) {
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	lbClass := "example.com/internal"
+	ndb.Spec.ServiceAnnotations = map[string]string{"example.com/owner": "team-a"}
+	ndb.Spec.MysqlNode = &v1.NdbMysqldSpec{
+		NodeCount: 2,
+		LoadBalancer: &v1.NdbLoadBalancerSpec{
+			LoadBalancerClass:        &lbClass,
+			LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+			ExternalTrafficPolicy:    corev1.ServiceExternalTrafficPolicyTypeLocal,
+			Annotations:              map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"},
+		},
+	}
+
+	// The load balancer configuration should be ignored by the ClusterIP Service
+	svc := NewMySQLdStatefulSet(nil).NewGoverningService(ndb)
+	if svc.Spec.LoadBalancerClass != nil || len(svc.Spec.LoadBalancerSourceRanges) != 0 ||
+		svc.Annotations["service.beta.kubernetes.io/aws-load-balancer-type"] != "" {
+		t.Errorf("Load balancer configuration was passed to the ClusterIP Service : %v", svc)
+	}
+
+	ndb.Spec.MysqlNode.EnableLoadBalancer = true
+	svc = NewMySQLdStatefulSet(nil).NewGoverningService(ndb)
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		t.Fatalf("Expected a LoadBalancer Service but got %q", svc.Spec.Type)
+	}
+	if svc.Spec.LoadBalancerClass == nil || *svc.Spec.LoadBalancerClass != lbClass ||
+		len(svc.Spec.LoadBalancerSourceRanges) != 1 ||
+		svc.Spec.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyTypeLocal {
+		t.Errorf("Load balancer configuration was not passed to the Service : %v", svc.Spec)
+	}
+	if svc.Annotations["service.beta.kubernetes.io/aws-load-balancer-type"] != "nlb" ||
+		svc.Annotations["example.com/owner"] != "team-a" {
+		t.Errorf("Unexpected Service annotations : %v", svc.Annotations)
+	}
+}
+
+func Test_mysqldStatefulSet_Port(t *testing.T) {
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.MysqlNode = &v1.NdbMysqldSpec{NodeCount: 2, Port: 3307}
+
+	svc := NewMySQLdStatefulSet(nil).NewGoverningService(ndb)
+	if len(svc.Spec.Ports) != 1 || svc.Spec.Ports[0].Port != 3307 {
+		t.Errorf("Expected the Service to expose the port 3307 : %v", svc.Spec.Ports)
+	}
+
+	mss := NewMySQLdStatefulSet(nil).(*mysqldStatefulSet)
+	mysqldContainer := mss.getContainers(ndb)[0]
+	if len(mysqldContainer.Ports) != 1 || mysqldContainer.Ports[0].ContainerPort != 3307 {
+		t.Errorf("Expected the container to expose the port 3307 : %v", mysqldContainer.Ports)
+	}
+	if !strings.Contains(mysqldContainer.Command[2], "--port=3307") {
+		t.Errorf("Expected the MySQL Server to be started with the port 3307 : %s", mysqldContainer.Command[2])
+	}
+}
@@ -18,6 +18,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	klog "k8s.io/klog/v2"
 )
 
@@ -25,6 +26,7 @@ import (
 type NdbStatefulSetInterface interface {
 	GetTypeName() constants.NdbNodeType
 	GetName(nc *v1.NdbCluster) string
+	GetServiceName(nc *v1.NdbCluster) string
 	NewGoverningService(nc *v1.NdbCluster) *corev1.Service
 	NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1.NdbCluster) (*appsv1.StatefulSet, error)
 }
@@ -93,11 +95,12 @@ func (bss *baseStatefulSet) createContainer(
 		})
 	}
 
-	klog.Infof("Creating container %q from image %s", containerName, nc.Spec.Image)
+	image := nc.GetImage(bss.nodeType)
+	klog.Infof("Creating container %q from image %s", containerName, image)
 	return corev1.Container{
 		Name: containerName,
 		// Use the image provided in spec
-		Image:           nc.Spec.Image,
+		Image:           image,
 		ImagePullPolicy: nc.Spec.ImagePullPolicy,
 		Ports:           ports,
 		// Export the Pod IP, Namespace and connectstring to Pod env
@@ -150,7 +153,7 @@ func (bss *baseStatefulSet) getDefaultInitContainers(nc *v1.NdbCluster) []corev1
 		bss.getWorkDirVolumeMount(),
 	}
 
-	container := bss.createContainer(nc, "ndb-pod-init-container", cmdAndArgs, volumeMounts, nil)
+	container := bss.createContainer(nc, ndbPodInitContainerName, cmdAndArgs, volumeMounts, nil)
 
 	// Export connection pool size to env for MySQL type pods
 	if bss.GetTypeName() == constants.NdbNodeTypeMySQLD {
@@ -160,6 +163,26 @@ func (bss *baseStatefulSet) getDefaultInitContainers(nc *v1.NdbCluster) []corev1
 		})
 	}
 
+	// Export the MySQL Server port, if it is not the default one, to the env
+	// for the data node pods, which connect to the MySQL Servers on restart.
+	if bss.GetTypeName() == constants.NdbNodeTypeNdbmtd {
+		if port := nc.GetMySQLServerPort(); port != constants.MySQLServerPort {
+			container.Env = append(container.Env, corev1.EnvVar{
+				Name:  "NDB_MYSQLD_PORT",
+				Value: strconv.Itoa(int(port)),
+			})
+		}
+	}
+
+	// Export the DNS stabilization period, if specified, to the env.
+	// The pod initializer uses a default period otherwise.
+	if nc.Spec.PodDNS != nil && nc.Spec.PodDNS.StabilizationSeconds != 0 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "NDB_POD_DNS_STABILIZATION_SECONDS",
+			Value: strconv.Itoa(int(nc.Spec.PodDNS.StabilizationSeconds)),
+		})
+	}
+
 	// Append the NDB operator password to the env variable of the ndb-pod-init-container
 	container.Env = append(container.Env, corev1.EnvVar{
 		Name: "NDB_OPERATOR_PASSWORD",
@@ -187,15 +210,10 @@ func (bss *baseStatefulSet) newStatefulSet(
 	nc *v1.NdbCluster, cs *ndbconfig.ConfigSummary) *appsv1.StatefulSet {
 
 	// Fill in the podSpec with any provided ImagePullSecrets
+	// and the ServiceAccount the pods have to run as
 	var podSpec corev1.PodSpec
-	imagePullSecretName := nc.Spec.ImagePullSecretName
-	if imagePullSecretName != "" {
-		podSpec.ImagePullSecrets = []corev1.LocalObjectReference{
-			{
-				Name: imagePullSecretName,
-			},
-		}
-	}
+	podSpec.ImagePullSecrets = nc.GetImagePullSecrets()
+	podSpec.ServiceAccountName = nc.GetServiceAccountName()
 
 	// add the default init container and the empty dir volume
 	podSpec.InitContainers = bss.getDefaultInitContainers(nc)
@@ -204,6 +222,20 @@ func (bss *baseStatefulSet) newStatefulSet(
 	// Labels to be used for the statefulset pods
 	podLabels := bss.getPodLabels(nc)
 
+	// Annotate the spec template with the config.ini version and the
+	// restart requests. A change in the config or a new restart request
+	// will create a new version of the spec template.
+	podAnnotations := map[string]string{
+		LastAppliedMySQLClusterConfigVersion: strconv.FormatInt(
+			int64(cs.GetMySQLClusterConfigVersion(bss.nodeType)), 10),
+	}
+	if restartRequest := cs.GetRestartRequest(bss.nodeType); restartRequest != "" {
+		podAnnotations[LastAppliedRestartRequest] = restartRequest
+	}
+	if initialRestartRequest := cs.GetInitialRestartRequest(bss.nodeType); initialRestartRequest != "" {
+		podAnnotations[LastAppliedInitialRestartRequest] = initialRestartRequest
+	}
+
 	return &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   bss.GetName(nc),
@@ -222,12 +254,11 @@ func (bss *baseStatefulSet) newStatefulSet(
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: podLabels,
-					// Annotate the spec template with the config.ini version.
-					// A change in the config will create a new version of the spec template.
-					Annotations: map[string]string{
-						LastAppliedMySQLClusterConfigVersion: strconv.FormatInt(int64(cs.MySQLClusterConfigVersion), 10),
-					},
+					// Add the custom labels to the pods. The labels set by
+					// the operator are not overridden as they are used by
+					// the selector.
+					Labels:      labels.Merge(nc.GetCustomPodLabels(bss.nodeType), podLabels),
+					Annotations: labels.Merge(nc.GetCustomPodAnnotations(bss.nodeType), podAnnotations),
 				},
 				Spec: podSpec,
 			},
@@ -238,11 +269,61 @@ func (bss *baseStatefulSet) newStatefulSet(
 	}
 }
 
+// setHostNetwork updates the given pod template to run the pods in the
+// host network, if it is enabled in the spec for the baseStatefulSet's
+// node type. It should be called after the pod template is complete.
+func (bss *baseStatefulSet) setHostNetwork(nc *v1.NdbCluster, podTemplate *corev1.PodTemplateSpec) {
+	if !nc.UsesHostNetwork(bss.nodeType) {
+		return
+	}
+
+	podSpec := &podTemplate.Spec
+	podSpec.HostNetwork = true
+	// Resolve the hostnames of the other MySQL Cluster nodes via the K8s Cluster DNS
+	podSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+
+	// A pod in the host network has the hostname of the worker node.
+	// Export the pod name as the HOSTNAME, as the nodeId of the MySQL
+	// Cluster node and the names of the other resources are deduced from it.
+	hostnameEnv := corev1.EnvVar{
+		Name: "HOSTNAME",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "metadata.name",
+			},
+		},
+	}
+	for i := range podSpec.InitContainers {
+		podSpec.InitContainers[i].Env = append(podSpec.InitContainers[i].Env, hostnameEnv)
+	}
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].Env = append(podSpec.Containers[i].Env, hostnameEnv)
+	}
+
+	// Label the pods and disallow scheduling more than one pod of
+	// the node type onto a worker node, as they use the same ports.
+	podTemplate.Labels[constants.HostNetworkLabel] = bss.nodeType
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = new(corev1.Affinity)
+	}
+	if podSpec.Affinity.PodAntiAffinity == nil {
+		podSpec.Affinity.PodAntiAffinity = new(corev1.PodAntiAffinity)
+	}
+	podAntiAffinity := podSpec.Affinity.PodAntiAffinity
+	podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+		podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, getHostNetworkPodAffinityTerm(bss.nodeType))
+}
+
 // GetName returns the name of the baseStatefulSet
 func (bss *baseStatefulSet) GetName(nc *v1.NdbCluster) string {
 	return nc.GetWorkloadName(bss.nodeType)
 }
 
+// GetServiceName returns the name of the governing Service of the baseStatefulSet
+func (bss *baseStatefulSet) GetServiceName(nc *v1.NdbCluster) string {
+	return nc.GetServiceName(bss.nodeType)
+}
+
 // GetTypeName returns the type name of baseStatefulSet
 func (bss *baseStatefulSet) GetTypeName() constants.NdbNodeType {
 	return bss.nodeType
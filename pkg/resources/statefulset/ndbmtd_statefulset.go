@@ -5,7 +5,9 @@
 package statefulset
 
 import (
+	"context"
 	"strconv"
+	"time"
 
 	"github.com/mysql/ndb-operator/config/debug"
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
@@ -19,18 +21,121 @@ import (
 	klog "k8s.io/klog/v2"
 )
 
-var (
-	// Ports to be exposed by the container and service
-	ndbmtdPorts = []int32{1186}
+const (
+	// dataNodeTerminationGracePeriodSeconds is the time allowed for a data
+	// node to be stopped gracefully by its preStop hook, before it is killed
+	dataNodeTerminationGracePeriodSeconds = 300
+
+	// huge pages volume and mount path for the data node pods
+	hugePagesVolumeName = constants.NdbNodeTypeNdbmtd + "-hugepages"
+	hugePagesMountPath  = "/dev/hugepages"
+
+	// names of the separate volumes of the data node pods
+	fileSystemVolumeName = constants.NdbNodeTypeNdbmtd + "-filesystem-vol"
+	backupVolumeName     = constants.NdbNodeTypeNdbmtd + "-backup-vol"
+	undoFilesVolumeName  = constants.NdbNodeTypeNdbmtd + "-undo-vol"
+
+	// resourceRequestConfigTimeout is the maximum time allowed to retrieve
+	// the config values required to compute the data node memory request
+	resourceRequestConfigTimeout = 30 * time.Second
 )
 
+// GetDataNodeContainerName returns the name of the container running the data node
+func GetDataNodeContainerName() string {
+	return constants.NdbNodeTypeNdbmtd + "-container"
+}
+
+// GetDataNodePVCName returns the name of the PersistentVolumeClaim created
+// by the StatefulSet controller for the data node running in the given pod
+func GetDataNodePVCName(podName string) string {
+	return constants.NdbNodeTypeNdbmtd + "-data-vol-" + podName
+}
+
+// separateVolume is a volume, separate from the data directory,
+// used by the data nodes to store a specific kind of files
+type separateVolume struct {
+	name      string
+	mountPath string
+	pvcSpec   *corev1.PersistentVolumeClaimSpec
+}
+
+// getSeparateVolumes returns the separate volumes specified for the data nodes
+func getSeparateVolumes(nc *v1.NdbCluster) (volumes []separateVolume) {
+	separateVolumes := nc.Spec.DataNode.SeparateVolumes
+	if separateVolumes == nil {
+		return nil
+	}
+
+	for _, volume := range []separateVolume{
+		{fileSystemVolumeName, constants.DataNodeFileSystemDir, separateVolumes.FileSystem},
+		{backupVolumeName, constants.DataNodeBackupDir, separateVolumes.Backup},
+		{undoFilesVolumeName, constants.DataNodeUndoFilesDir, separateVolumes.UndoFiles},
+	} {
+		if volume.pvcSpec != nil {
+			volumes = append(volumes, volume)
+		}
+	}
+	return volumes
+}
+
+// GetDataNodeFileSystemPVCNames returns the names of the PVCs created for
+// the separate FileSystemPath and undo files volumes of the data node
+// running in the given pod. Along with the data directory PVC, they hold
+// the file system of the data node. The backup PVC is not included.
+func GetDataNodeFileSystemPVCNames(nc *v1.NdbCluster, podName string) (pvcNames []string) {
+	for _, volume := range getSeparateVolumes(nc) {
+		if volume.name != backupVolumeName {
+			pvcNames = append(pvcNames, volume.name+"-"+podName)
+		}
+	}
+	return pvcNames
+}
+
+// GetDataNodePVCVolumes returns the volumes, and their mounts, of the
+// PersistentVolumeClaims of the data node running in the given pod. The
+// volumes are mounted read-only at the same directories as in the data
+// node container, so that another pod, running on the same worker node,
+// can read the files of the data node.
+func GetDataNodePVCVolumes(nc *v1.NdbCluster, podName string) (volumes []corev1.Volume, mounts []corev1.VolumeMount) {
+	addVolume := func(volumeName, pvcName, mountPath string) {
+		volumes = append(volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: pvcName,
+					ReadOnly:  true,
+				},
+			},
+		})
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: mountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	if nc.Spec.DataNode.PVCSpec != nil {
+		addVolume(constants.NdbNodeTypeNdbmtd+"-data-vol", GetDataNodePVCName(podName), dataDirectoryMountPath)
+	}
+	for _, volume := range getSeparateVolumes(nc) {
+		addVolume(volume.name, volume.name+"-"+podName, volume.mountPath)
+	}
+
+	return volumes, mounts
+}
+
 // ndbmtdStatefulSet implements the NdbStatefulSetInterface to control a set of data nodes
 type ndbmtdStatefulSet struct {
 	baseStatefulSet
 }
 
 func (nss *ndbmtdStatefulSet) NewGoverningService(nc *v1.NdbCluster) *corev1.Service {
-	return newService(nc, ndbmtdPorts, nss.nodeType, true, false)
+	return newService(nc, nss.getPorts(nc), nss.nodeType, true, false)
+}
+
+// getPorts returns the ports to be exposed by the container and service
+func (nss *ndbmtdStatefulSet) getPorts(nc *v1.NdbCluster) []int32 {
+	return []int32{nc.GetDataNodeServerPort()}
 }
 
 // getPodVolumes returns a slice of volumes to be
@@ -53,6 +158,14 @@ func (nss *ndbmtdStatefulSet) getPodVolumes(nc *v1.NdbCluster) []corev1.Volume {
 							Key:  constants.DataNodeStartupProbeScript,
 							Path: constants.DataNodeStartupProbeScript,
 						},
+						{
+							Key:  constants.DataNodeReadinessProbeScript,
+							Path: constants.DataNodeReadinessProbeScript,
+						},
+						{
+							Key:  constants.DataNodePreStopHookScript,
+							Path: constants.DataNodePreStopHookScript,
+						},
 					},
 				},
 			},
@@ -90,12 +203,17 @@ func (nss *ndbmtdStatefulSet) getVolumeMounts() []corev1.VolumeMount {
 // from the MySQL Cluster config and returns the ResourceList with the calculated memory
 func (nss *ndbmtdStatefulSet) getResourceRequestRequirements(nc *v1.NdbCluster) (corev1.ResourceList, error) {
 
-	// Connect to the Management Server
-	mgmClient, err := mgmapi.NewMgmClient(nc.GetConnectstring())
+	// Connect to the Management Server. The client holds one of the
+	// operator's limited Management Server connection slots until it
+	// is disconnected, so always disconnect it once done.
+	ctx, cancel := context.WithTimeout(context.Background(), resourceRequestConfigTimeout)
+	defer cancel()
+	mgmClient, err := mgmapi.NewMgmClientWithContext(ctx, nc.GetConnectstring())
 	if err != nil {
 		klog.Errorf("Failed to connect to Management Server : %s", err)
 		return nil, err
 	}
+	defer mgmClient.Disconnect()
 
 	// Retrieve all the config values required to compute the memory requirements
 	dataMemory, err := mgmClient.GetDataMemory(0)
@@ -213,9 +331,15 @@ func (nss *ndbmtdStatefulSet) getResourceRequestRequirements(nc *v1.NdbCluster)
 // getContainers returns the containers to run a data Node
 func (nss *ndbmtdStatefulSet) getContainers(nc *v1.NdbCluster) []corev1.Container {
 
+	// Run the single threaded ndbd if it has been chosen in the spec
+	dataNodeBinary := "/usr/sbin/ndbmtd"
+	if nc.Spec.DataNode.UseNdbd {
+		dataNodeBinary = "/usr/sbin/ndbd"
+	}
+
 	// Command and args to run the Data node
 	cmdAndArgs := []string{
-		"/usr/sbin/ndbmtd",
+		dataNodeBinary,
 		"-c", nc.GetConnectstring(),
 		"--foreground",
 		// Pass the nodeId to be used to prevent invalid
@@ -230,7 +354,7 @@ func (nss *ndbmtdStatefulSet) getContainers(nc *v1.NdbCluster) []corev1.Containe
 
 	ndbmtdContainer := nss.createContainer(
 		nc, nss.getContainerName(false), cmdAndArgs,
-		nss.getVolumeMounts(), ndbmtdPorts)
+		nss.getVolumeMounts(), nss.getPorts(nc))
 
 	// Setup startup probe for data nodes.
 	// The probe uses a script that checks if a data node has started, by
@@ -257,6 +381,62 @@ func (nss *ndbmtdStatefulSet) getContainers(nc *v1.NdbCluster) []corev1.Containe
 		FailureThreshold: 450,
 	}
 
+	// Override the startup probe thresholds with the ones in the spec
+	if startupProbeSpec := nc.Spec.DataNode.StartupProbe; startupProbeSpec != nil {
+		if startupProbeSpec.PeriodSeconds != 0 {
+			ndbmtdContainer.StartupProbe.PeriodSeconds = startupProbeSpec.PeriodSeconds
+		}
+		if startupProbeSpec.TimeoutSeconds != 0 {
+			ndbmtdContainer.StartupProbe.TimeoutSeconds = startupProbeSpec.TimeoutSeconds
+		}
+		if startupProbeSpec.FailureThreshold != 0 {
+			ndbmtdContainer.StartupProbe.FailureThreshold = startupProbeSpec.FailureThreshold
+		}
+	}
+
+	// Readiness probe reports the data node as ready only when it is in
+	// the started state. The probe is run only after the startup probe
+	// succeeds, and it marks the data node as not ready whenever it goes
+	// through the start phases again after an internal restart.
+	ndbmtdContainer.ReadinessProbe = &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{
+				// ndbmtd-readiness-probe.sh
+				Command: []string{
+					"/bin/bash",
+					helperScriptsMountPath + "/" + constants.DataNodeReadinessProbeScript,
+				},
+			},
+		},
+		PeriodSeconds:    10,
+		TimeoutSeconds:   10,
+		FailureThreshold: 3,
+	}
+
+	// Stop the data node gracefully via the Management server before the
+	// container is terminated during pod deletions and evictions
+	ndbmtdContainer.Lifecycle = &corev1.Lifecycle{
+		PreStop: &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{
+				// ndbmtd-prestop-hook.sh
+				Command: []string{
+					"/bin/bash",
+					helperScriptsMountPath + "/" + constants.DataNodePreStopHookScript,
+				},
+			},
+		},
+	}
+
+	// Allow the data node to lock its memory, if required by the spec
+	if lockPagesInMainMemory := nc.Spec.DataNode.LockPagesInMainMemory; lockPagesInMainMemory != nil &&
+		*lockPagesInMainMemory != 0 {
+		ndbmtdContainer.SecurityContext = &corev1.SecurityContext{
+			Capabilities: &corev1.Capabilities{
+				Add: []corev1.Capability{"IPC_LOCK"},
+			},
+		}
+	}
+
 	// Set resource request to data node container
 	resList, err := nss.getResourceRequestRequirements(nc)
 	if err == nil {
@@ -277,6 +457,102 @@ func (nss *ndbmtdStatefulSet) getPodAntiAffinity() *corev1.PodAntiAffinity {
 	})
 }
 
+// setZonePlacement restricts the Data node pods to the zones specified in
+// the spec. A StatefulSet cannot place its pods by their ordinals, so the
+// pods are created with a scheduling gate, and the operator pins every pod
+// to the zone planned for its ordinal, by the placement planner, before
+// removing the gate. This ensures that the Data nodes of every node group
+// are placed in distinct zones.
+func (nss *ndbmtdStatefulSet) setZonePlacement(nc *v1.NdbCluster, podSpec *corev1.PodSpec) {
+	zones := nc.Spec.DataNode.Zones
+	if len(zones) == 0 {
+		// Zones not specified
+		return
+	}
+
+	// Restrict the pods to the zones. The NodeSelectorTerms are ORed,
+	// so the zone requirement has to be added to all of them.
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = new(corev1.Affinity)
+	}
+	if podSpec.Affinity.NodeAffinity == nil {
+		podSpec.Affinity.NodeAffinity = new(corev1.NodeAffinity)
+	}
+	nodeAffinity := podSpec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = new(corev1.NodeSelector)
+	}
+	nodeSelector := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(nodeSelector.NodeSelectorTerms) == 0 {
+		nodeSelector.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	for i := range nodeSelector.NodeSelectorTerms {
+		nodeSelector.NodeSelectorTerms[i].MatchExpressions = append(
+			nodeSelector.NodeSelectorTerms[i].MatchExpressions, corev1.NodeSelectorRequirement{
+				Key:      corev1.LabelTopologyZone,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   zones,
+			})
+	}
+
+	// Hold the pods until they are pinned to their planned zones
+	podSpec.SchedulingGates = append(podSpec.SchedulingGates, corev1.PodSchedulingGate{
+		Name: constants.DataNodeZonePlacementGate,
+	})
+}
+
+// setHugePages requests the huge pages specified in the spec for the Data
+// node container and mounts them at /dev/hugepages via an emptyDir volume.
+// K8s requires the huge page requests to be equal to their limits.
+func (nss *ndbmtdStatefulSet) setHugePages(nc *v1.NdbCluster, podSpec *corev1.PodSpec) {
+	hugePages := nc.Spec.DataNode.HugePages
+	if hugePages == nil {
+		// Huge pages not requested
+		return
+	}
+
+	// Request the huge pages for the Data node container
+	hugePagesResourceName := corev1.ResourceName(corev1.ResourceHugePagesPrefix + hugePages.PageSize)
+	containerResources := &podSpec.Containers[0].Resources
+	if containerResources.Limits == nil {
+		containerResources.Limits = make(corev1.ResourceList)
+	}
+	if containerResources.Requests == nil {
+		containerResources.Requests = make(corev1.ResourceList)
+	}
+	containerResources.Limits[hugePagesResourceName] = hugePages.Size
+	containerResources.Requests[hugePagesResourceName] = hugePages.Size
+
+	// Mount the huge pages into the Data node container
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: hugePagesVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				Medium: corev1.StorageMedium(string(corev1.StorageMediumHugePagesPrefix) + hugePages.PageSize),
+			},
+		},
+	})
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      hugePagesVolumeName,
+		MountPath: hugePagesMountPath,
+	})
+}
+
+// setSeparateVolumes adds a VolumeClaimTemplate for every separate volume
+// specified in the spec and mounts them into the Data node container at
+// the directories set as the respective config params of the Data nodes.
+func (nss *ndbmtdStatefulSet) setSeparateVolumes(nc *v1.NdbCluster, statefulSetSpec *appsv1.StatefulSetSpec) {
+	ndbmtdContainer := &statefulSetSpec.Template.Spec.Containers[0]
+	for _, volume := range getSeparateVolumes(nc) {
+		statefulSetSpec.VolumeClaimTemplates = append(
+			statefulSetSpec.VolumeClaimTemplates, *newPVC(nc, volume.name, volume.pvcSpec))
+		ndbmtdContainer.VolumeMounts = append(ndbmtdContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      volume.name,
+			MountPath: volume.mountPath,
+		})
+	}
+}
+
 // NewStatefulSet returns the StatefulSet specification to start and manage the Data nodes.
 func (nss *ndbmtdStatefulSet) NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1.NdbCluster) (*appsv1.StatefulSet, error) {
 	statefulSet := nss.newStatefulSet(nc, cs)
@@ -285,8 +561,9 @@ func (nss *ndbmtdStatefulSet) NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1
 	// Fill in ndbmtd specific values
 	replicas := cs.NumOfDataNodes
 	statefulSetSpec.Replicas = &replicas
-	// Set pod management policy to start Data nodes in parallel
-	statefulSetSpec.PodManagementPolicy = appsv1.ParallelPodManagement
+	// Set pod management policy to start Data nodes in
+	// parallel, unless a different policy is specified in the spec
+	statefulSetSpec.PodManagementPolicy = nc.GetPodManagementPolicy(nss.nodeType)
 
 	// Use the legacy OnDelete update strategy to get more
 	// control over how the update is rolled out to data nodes
@@ -306,6 +583,9 @@ func (nss *ndbmtdStatefulSet) NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1
 	// Update template pod spec
 	podSpec := &statefulSetSpec.Template.Spec
 	podSpec.Containers = nss.getContainers(nc)
+	// Allow enough time for the preStop hook to stop the data node gracefully
+	terminationGracePeriod := int64(dataNodeTerminationGracePeriodSeconds)
+	podSpec.TerminationGracePeriodSeconds = &terminationGracePeriod
 	podSpec.Volumes = append(podSpec.Volumes, nss.getPodVolumes(nc)...)
 	// Set default AntiAffinity rules
 	podSpec.Affinity = &corev1.Affinity{
@@ -314,6 +594,18 @@ func (nss *ndbmtdStatefulSet) NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1
 	// Copy down any podSpec specified via CRD
 	CopyPodSpecFromNdbPodSpec(podSpec, nc.Spec.DataNode.NdbPodSpec)
 
+	// Request the huge pages specified in the spec
+	nss.setHugePages(nc, podSpec)
+
+	// Add the separate volumes specified in the spec
+	nss.setSeparateVolumes(nc, statefulSetSpec)
+
+	// Place the pods in the zones specified in the spec
+	nss.setZonePlacement(nc, podSpec)
+
+	// Run the pods in the host network if required
+	nss.setHostNetwork(nc, &statefulSetSpec.Template)
+
 	return statefulSet, nil
 }
 
@@ -0,0 +1,333 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package statefulset
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func Test_ndbmtdStatefulSet_HostNetwork(t *testing.T) {
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.DataNode.HostNetwork = true
+	cs := &ndbconfig.ConfigSummary{
+		NdbClusterGeneration: 1,
+		NumOfDataNodes:       2,
+	}
+
+	sfset, err := NewNdbmtdStatefulSet().NewStatefulSet(cs, ndb)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	podTemplate := sfset.Spec.Template
+	podSpec := podTemplate.Spec
+	if !podSpec.HostNetwork || podSpec.DNSPolicy != corev1.DNSClusterFirstWithHostNet {
+		t.Errorf("Expected the pods to run in the host network with the %q DNS policy",
+			corev1.DNSClusterFirstWithHostNet)
+	}
+
+	if podTemplate.Labels[constants.HostNetworkLabel] != constants.NdbNodeTypeNdbmtd {
+		t.Errorf("Expected the pods to have the label %q", constants.HostNetworkLabel)
+	}
+
+	// The data node container should expose the host network port
+	ndbmtdContainer := podSpec.Containers[0]
+	if len(ndbmtdContainer.Ports) != 1 ||
+		ndbmtdContainer.Ports[0].ContainerPort != constants.DataNodeHostNetworkServerPort {
+		t.Errorf("Expected the container to expose only the port %d but has %v",
+			constants.DataNodeHostNetworkServerPort, ndbmtdContainer.Ports)
+	}
+
+	// All containers should have the pod name as the HOSTNAME
+	for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+		hasHostnameEnv := false
+		for _, env := range container.Env {
+			if env.Name == "HOSTNAME" && env.ValueFrom != nil &&
+				env.ValueFrom.FieldRef != nil && env.ValueFrom.FieldRef.FieldPath == "metadata.name" {
+				hasHostnameEnv = true
+			}
+		}
+		if !hasHostnameEnv {
+			t.Errorf("Container %q does not have the HOSTNAME env variable", container.Name)
+		}
+	}
+
+	// Not more than one data node should be scheduled onto a worker node
+	requiredTerms := podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(requiredTerms) != 1 ||
+		requiredTerms[0].LabelSelector.MatchLabels[constants.HostNetworkLabel] != constants.NdbNodeTypeNdbmtd {
+		t.Errorf("Expected a required pod anti affinity term for the host network pods but got %v", requiredTerms)
+	}
+}
+
+func Test_ndbmtdStatefulSet_ZonePlacement(t *testing.T) {
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.DataNode.Zones = []string{"zone-a", "zone-b"}
+	cs := &ndbconfig.ConfigSummary{
+		NdbClusterGeneration: 1,
+		NumOfDataNodes:       2,
+	}
+
+	sfset, err := NewNdbmtdStatefulSet().NewStatefulSet(cs, ndb)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	// The pods should be restricted to the zones
+	podSpec := sfset.Spec.Template.Spec
+	nodeSelector := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(nodeSelector.NodeSelectorTerms) != 1 {
+		t.Fatalf("Expected 1 node selector term but got %d", len(nodeSelector.NodeSelectorTerms))
+	}
+	matchExpressions := nodeSelector.NodeSelectorTerms[0].MatchExpressions
+	if len(matchExpressions) != 1 ||
+		matchExpressions[0].Key != corev1.LabelTopologyZone ||
+		matchExpressions[0].Operator != corev1.NodeSelectorOpIn ||
+		!reflect.DeepEqual(matchExpressions[0].Values, ndb.Spec.DataNode.Zones) {
+		t.Errorf("Expected the pods to be restricted to the zones %v but got %v",
+			ndb.Spec.DataNode.Zones, matchExpressions)
+	}
+
+	// The pods should be held until they are pinned to their zones
+	if len(podSpec.SchedulingGates) != 1 ||
+		podSpec.SchedulingGates[0].Name != constants.DataNodeZonePlacementGate {
+		t.Errorf("Expected the zone placement scheduling gate but got %v", podSpec.SchedulingGates)
+	}
+}
+
+func Test_ndbmtdStatefulSet_UseNdbd(t *testing.T) {
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	cs := &ndbconfig.ConfigSummary{
+		NdbClusterGeneration: 1,
+		NumOfDataNodes:       2,
+	}
+
+	getDataNodeCommand := func() string {
+		sfset, err := NewNdbmtdStatefulSet().NewStatefulSet(cs, ndb)
+		if err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+		command := sfset.Spec.Template.Spec.Containers[0].Command
+		return command[len(command)-1]
+	}
+
+	// The multi-threaded ndbmtd should be run by default
+	if command := getDataNodeCommand(); !strings.HasPrefix(command, "/usr/sbin/ndbmtd ") {
+		t.Errorf("Expected the data nodes to run ndbmtd but the command is %q", command)
+	}
+
+	// The single threaded ndbd should be run when chosen in the spec
+	ndb.Spec.DataNode.UseNdbd = true
+	if command := getDataNodeCommand(); !strings.HasPrefix(command, "/usr/sbin/ndbd ") {
+		t.Errorf("Expected the data nodes to run ndbd but the command is %q", command)
+	}
+}
+
+func Test_ndbmtdStatefulSet_HugePages(t *testing.T) {
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	ndb.Spec.DataNode.HugePages = &v1.NdbHugePagesSpec{
+		PageSize: "2Mi",
+		Size:     resource.MustParse("1Gi"),
+	}
+	lockPagesInMainMemory := int32(2)
+	ndb.Spec.DataNode.LockPagesInMainMemory = &lockPagesInMainMemory
+	cs := &ndbconfig.ConfigSummary{
+		NdbClusterGeneration: 1,
+		NumOfDataNodes:       2,
+	}
+
+	sfset, err := NewNdbmtdStatefulSet().NewStatefulSet(cs, ndb)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	podSpec := sfset.Spec.Template.Spec
+	ndbmtdContainer := podSpec.Containers[0]
+
+	// The huge pages should be requested with equal requests and limits
+	hugePagesResourceName := corev1.ResourceName("hugepages-2Mi")
+	for _, resourceList := range []corev1.ResourceList{
+		ndbmtdContainer.Resources.Requests, ndbmtdContainer.Resources.Limits} {
+		if quantity := resourceList[hugePagesResourceName]; quantity.Cmp(resource.MustParse("1Gi")) != 0 {
+			t.Errorf("Expected 1Gi of %q but got %v", hugePagesResourceName, resourceList)
+		}
+	}
+
+	// The huge pages should be mounted into the container
+	hasHugePagesVolume := false
+	for _, volume := range podSpec.Volumes {
+		if volume.Name == hugePagesVolumeName && volume.EmptyDir != nil &&
+			volume.EmptyDir.Medium == corev1.StorageMedium("HugePages-2Mi") {
+			hasHugePagesVolume = true
+		}
+	}
+	hasHugePagesMount := false
+	for _, volumeMount := range ndbmtdContainer.VolumeMounts {
+		if volumeMount.Name == hugePagesVolumeName && volumeMount.MountPath == "/dev/hugepages" {
+			hasHugePagesMount = true
+		}
+	}
+	if !hasHugePagesVolume || !hasHugePagesMount {
+		t.Errorf("Expected the huge pages to be mounted into the data node container")
+	}
+
+	// The container should be allowed to lock its memory
+	securityContext := ndbmtdContainer.SecurityContext
+	if securityContext == nil || securityContext.Capabilities == nil ||
+		!reflect.DeepEqual(securityContext.Capabilities.Add, []corev1.Capability{"IPC_LOCK"}) {
+		t.Errorf("Expected the container to have the IPC_LOCK capability but got %v", securityContext)
+	}
+}
+
+func Test_ndbmtdStatefulSet_SeparateVolumes(t *testing.T) {
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	pvcSpec := &corev1.PersistentVolumeClaimSpec{
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+		},
+	}
+	ndb.Spec.DataNode.PVCSpec = pvcSpec
+	ndb.Spec.DataNode.SeparateVolumes = &v1.NdbDataNodeVolumesSpec{
+		FileSystem: pvcSpec,
+		Backup:     pvcSpec,
+	}
+	cs := &ndbconfig.ConfigSummary{
+		NdbClusterGeneration: 1,
+		NumOfDataNodes:       2,
+	}
+
+	sfset, err := NewNdbmtdStatefulSet().NewStatefulSet(cs, ndb)
+	if err != nil {
+		t.Fatal("Unexpected error :", err)
+	}
+
+	// A VolumeClaimTemplate should exist for the data directory and every separate volume
+	var volumeClaimTemplateNames []string
+	for _, volumeClaimTemplate := range sfset.Spec.VolumeClaimTemplates {
+		volumeClaimTemplateNames = append(volumeClaimTemplateNames, volumeClaimTemplate.Name)
+	}
+	expectedNames := []string{"ndbmtd-data-vol", fileSystemVolumeName, backupVolumeName}
+	if !reflect.DeepEqual(volumeClaimTemplateNames, expectedNames) {
+		t.Errorf("Expected the VolumeClaimTemplates %v but got %v", expectedNames, volumeClaimTemplateNames)
+	}
+
+	// The separate volumes should be mounted at the directories set in the config
+	volumeMounts := make(map[string]string)
+	for _, volumeMount := range sfset.Spec.Template.Spec.Containers[0].VolumeMounts {
+		volumeMounts[volumeMount.Name] = volumeMount.MountPath
+	}
+	if volumeMounts[fileSystemVolumeName] != constants.DataNodeFileSystemDir ||
+		volumeMounts[backupVolumeName] != constants.DataNodeBackupDir {
+		t.Errorf("Unexpected volume mounts %v", volumeMounts)
+	}
+	if _, exists := volumeMounts[undoFilesVolumeName]; exists {
+		t.Errorf("Expected no undo files volume to be mounted")
+	}
+
+	// Only the file system PVCs should be returned
+	pvcNames := GetDataNodeFileSystemPVCNames(ndb, "example-ndb-ndbmtd-0")
+	if !reflect.DeepEqual(pvcNames, []string{"ndbmtd-filesystem-vol-example-ndb-ndbmtd-0"}) {
+		t.Errorf("Unexpected file system PVC names %v", pvcNames)
+	}
+}
+
+func Test_ndbmtdStatefulSet_PodDNS(t *testing.T) {
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	cs := &ndbconfig.ConfigSummary{
+		NdbClusterGeneration: 1,
+		NumOfDataNodes:       2,
+	}
+
+	// The governing Service should publish the addresses of the pods that are not ready
+	if svc := NewNdbmtdStatefulSet().NewGoverningService(ndb); !svc.Spec.PublishNotReadyAddresses {
+		t.Error("Expected the Service to publish the addresses of the pods that are not ready")
+	}
+
+	getInitContainerEnv := func(name string) (string, bool) {
+		sfset, err := NewNdbmtdStatefulSet().NewStatefulSet(cs, ndb)
+		if err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+		for _, env := range sfset.Spec.Template.Spec.InitContainers[0].Env {
+			if env.Name == name {
+				return env.Value, true
+			}
+		}
+		return "", false
+	}
+	getStabilizationEnv := func() (string, bool) {
+		return getInitContainerEnv("NDB_POD_DNS_STABILIZATION_SECONDS")
+	}
+
+	// The pod initializer should use its default period if none is specified
+	if value, exists := getStabilizationEnv(); exists {
+		t.Errorf("Expected no DNS stabilization period in the env but got %q", value)
+	}
+
+	// The specified period should be passed to the pod initializer
+	ndb.Spec.PodDNS = &v1.NdbPodDNSSpec{StabilizationSeconds: 20}
+	if value, _ := getStabilizationEnv(); value != "20" {
+		t.Errorf("Expected the DNS stabilization period to be \"20\" but got %q", value)
+	}
+
+	// The pod initializer should use the default MySQL Server port if none is specified
+	if value, exists := getInitContainerEnv("NDB_MYSQLD_PORT"); exists {
+		t.Errorf("Expected no MySQL Server port in the env but got %q", value)
+	}
+
+	// The specified port should be passed to the pod initializer
+	ndb.Spec.MysqlNode.Port = 3307
+	if value, _ := getInitContainerEnv("NDB_MYSQLD_PORT"); value != "3307" {
+		t.Errorf("Expected the MySQL Server port to be \"3307\" but got %q", value)
+	}
+}
+
+func Test_ndbmtdStatefulSet_ServiceAccount(t *testing.T) {
+	ndb := testutils.NewTestNdb("default", "example-ndb", 2)
+	cs := &ndbconfig.ConfigSummary{
+		NdbClusterGeneration: 1,
+		NumOfDataNodes:       2,
+	}
+
+	for _, tc := range []struct {
+		desc                       string
+		serviceAccount             *v1.NdbServiceAccountSpec
+		expectedServiceAccountName string
+	}{
+		{
+			desc: "default ServiceAccount of the namespace",
+		},
+		{
+			desc:                       "ServiceAccount created by the operator",
+			serviceAccount:             &v1.NdbServiceAccountSpec{},
+			expectedServiceAccountName: "example-ndb-ndb-agent",
+		},
+		{
+			desc:                       "existing ServiceAccount",
+			serviceAccount:             &v1.NdbServiceAccountSpec{Name: "mysql-agent"},
+			expectedServiceAccountName: "mysql-agent",
+		},
+	} {
+		ndb.Spec.ServiceAccount = tc.serviceAccount
+		sfset, err := NewNdbmtdStatefulSet().NewStatefulSet(cs, ndb)
+		if err != nil {
+			t.Fatal("Unexpected error :", err)
+		}
+		if serviceAccountName := sfset.Spec.Template.Spec.ServiceAccountName; serviceAccountName != tc.expectedServiceAccountName {
+			t.Errorf("%s : expected the pods to run as %q but got %q",
+				tc.desc, tc.expectedServiceAccountName, serviceAccountName)
+		}
+	}
+}
@@ -11,6 +11,21 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// getHostNetworkPodAffinityTerm returns the PodAffinityTerm that matches
+// all the pods of the given node type running in the host network of a
+// worker node, across all the namespaces and MySQL Clusters.
+func getHostNetworkPodAffinityTerm(nodeType constants.NdbNodeType) corev1.PodAffinityTerm {
+	return corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				constants.HostNetworkLabel: nodeType,
+			},
+		},
+		NamespaceSelector: &metav1.LabelSelector{},
+		TopologyKey:       "kubernetes.io/hostname",
+	}
+}
+
 // GetPodAntiAffinityRules returns the PodAntiAffinity definition
 // with the given mySQLClusterNodeTypes. The mySQLClusterNodeTypes
 // should be in the order of most to least preferred node type for
@@ -83,4 +83,25 @@ func CopyPodSpecFromNdbPodSpec(podSpec *corev1.PodSpec, ndbPodSpec *v1.NdbCluste
 
 	// Copy all the Tolerations
 	podSpec.Tolerations = append(podSpec.Tolerations, ndbPodSpec.Tolerations...)
+
+	// Append the user defined init containers after the ones added by the operator
+	for i := range ndbPodSpec.InitContainers {
+		podSpec.InitContainers = append(podSpec.InitContainers, *ndbPodSpec.InitContainers[i].DeepCopy())
+	}
+
+	// Append the sidecar containers and the extra volumes
+	for i := range ndbPodSpec.SidecarContainers {
+		podSpec.Containers = append(podSpec.Containers, *ndbPodSpec.SidecarContainers[i].DeepCopy())
+	}
+	for i := range ndbPodSpec.Volumes {
+		podSpec.Volumes = append(podSpec.Volumes, *ndbPodSpec.Volumes[i].DeepCopy())
+	}
+
+	// Mount the extra volumes into the MySQL Cluster node container
+	if len(ndbPodSpec.VolumeMounts) != 0 {
+		if len(podSpec.Containers) == 0 {
+			panic("CopyPodSpecFromNdbPodSpec should be called only after the containers are set")
+		}
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, ndbPodSpec.VolumeMounts...)
+	}
 }
@@ -401,3 +401,37 @@ func Test_setPodSpecFromNdbPodSpec_Misc(t *testing.T) {
 		t.Errorf("Unexpected value in Scheduler name. Expected : %q, Actual %q", ndbPodSpec.SchedulerName, podSpec.SchedulerName)
 	}
 }
+
+func Test_setPodSpecFromNdbPodSpec_ExtraContainersAndVolumes(t *testing.T) {
+	ndbPodSpec := &v1.NdbClusterPodSpec{
+		InitContainers: []corev1.Container{
+			{Name: "setup", Image: "busybox"},
+		},
+		SidecarContainers: []corev1.Container{
+			{Name: "log-shipper", Image: "fluent-bit"},
+		},
+		Volumes: []corev1.Volume{
+			{Name: "shared-logs"},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "shared-logs", MountPath: "/var/log/ndb"},
+		},
+	}
+
+	podSpec := corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "ndb-pod-init-container"}},
+		Containers:     []corev1.Container{{Name: "ndbmtd-container"}},
+		Volumes:        []corev1.Volume{{Name: "ndb-work-dir-vol"}},
+	}
+
+	// Set and verify that the values are appended after the existing ones
+	CopyPodSpecFromNdbPodSpec(&podSpec, ndbPodSpec)
+	errorIfNotEqual(t, podSpec.InitContainers,
+		`[{"name":"ndb-pod-init-container","resources":{}},{"name":"setup","image":"busybox","resources":{}}]`,
+		"InitContainers")
+	errorIfNotEqual(t, podSpec.Containers,
+		`[{"name":"ndbmtd-container","resources":{},"volumeMounts":[{"name":"shared-logs","mountPath":"/var/log/ndb"}]},`+
+			`{"name":"log-shipper","image":"fluent-bit","resources":{}}]`,
+		"Containers")
+	errorIfNotEqual(t, podSpec.Volumes, `[{"name":"ndb-work-dir-vol"},{"name":"shared-logs"}]`, "Volumes")
+}
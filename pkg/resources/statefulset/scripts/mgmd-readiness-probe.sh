@@ -0,0 +1,27 @@
+#!/bin/bash
+
+# Copyright (c) 2023, Oracle and/or its affiliates.
+#
+# Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+# Readiness probe of the MySQL Cluster management nodes
+
+# Note : The management node is checked via a connection to itself rather
+#        than by checking if its port is open, as the port is opened
+#        well before the management node is able to serve the clients.
+
+# Port of the local mgmd is passed as the first arg to the script
+mgmdPort=${1:-1186}
+
+# Extract the nodeId written by the init container
+nodeId=$(cat /var/lib/ndb/run/nodeId.val)
+
+# Get local mgmd status using `ndb_mgm -e "<nodeId> status"` command
+nodeStatus=$(ndb_mgm -c "localhost:${mgmdPort}" -e "${nodeId} status" --connect-retries=1)
+# If nodeStatus has "Node ${nodeId}: connected", the management node is ready
+if ! [[ "${nodeStatus}" =~ .*Node\ "${nodeId}":\ connected.* ]]; then
+  echo "Management node readiness check failed."
+  echo "Node status output : "
+  echo "${nodeStatus}"
+  exit 1
+fi
@@ -1,6 +1,6 @@
 #!/bin/bash
 
-# Copyright (c) 2022, Oracle and/or its affiliates.
+# Copyright (c) 2022, 2023, Oracle and/or its affiliates.
 #
 # Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -8,11 +8,14 @@
 
 set -e
 
+# Port of the local mgmd is passed as the first arg to the script
+mgmdPort=${1:-1186}
+
 # Extract the nodeId written by the init container
 nodeId=$(cat /var/lib/ndb/run/nodeId.val)
 
 # Get local mgmd status using `ndb_mgm -e "<nodeId> status"` command
-nodeStatus=$(ndb_mgm -c "localhost" -e "${nodeId} status" --connect-retries=1)
+nodeStatus=$(ndb_mgm -c "localhost:${mgmdPort}" -e "${nodeId} status" --connect-retries=1)
 # If nodeStatus has "Node ${nodeId}: connected", the management node can be considered ready
 if ! [[ "${nodeStatus}" =~ .*Node\ "${nodeId}":\ connected.* ]]; then
   echo "Management node health check failed."
@@ -33,16 +36,14 @@ if ((${#connectstrings[@]} == 1)); then
   exit 0
 fi
 
-# Deduce the nodeId and connectstring of the "other" mgmd
-otherMgmdNodeId=0
+# Deduce the connectstring of the "other" mgmd from the hostnames
+# rather than the nodeIds, as the nodeIds are assigned by the config.
 otherMgmdConnectstring=""
-if ((nodeId == 1)); then
-  otherMgmdNodeId=2
-  otherMgmdConnectstring=${connectstrings[1]}
-else
-  otherMgmdNodeId=1
-  otherMgmdConnectstring=${connectstrings[0]}
-fi
+for connectstring in "${connectstrings[@]}"; do
+  if [[ "${connectstring}" != "${HOSTNAME}."* ]]; then
+    otherMgmdConnectstring=${connectstring}
+  fi
+done
 
 # Check if the other mgmd is already running.
 if [[ $(getent hosts "${otherMgmdConnectstring%:*}" | awk '{print $1}') == "" ]]; then
@@ -54,7 +55,7 @@ fi
 # Other mgmd is running. Local mgmd is ready when it reports the exact
 # same status about the connected mgmd and data nodes as the other mgmd.
 # Note : SQL/API node status is not compared and that seems to be okay for now.
-clusterStatusFromLocalMgmd=$(ndb_mgm -c localhost:1186 --connect-retries=1 -e show)
+clusterStatusFromLocalMgmd=$(ndb_mgm -c "localhost:${mgmdPort}" --connect-retries=1 -e show)
 clusterStatusFromOtherMgmd=$(ndb_mgm -c "${otherMgmdConnectstring}" --connect-retries=1 -e show)
 # Compare Management nodes' status first
 mgmdStatusFromLocalMgmd=$(echo "${clusterStatusFromLocalMgmd}" | sed -n '/ndb_mgmd(MGM)/,+2p')
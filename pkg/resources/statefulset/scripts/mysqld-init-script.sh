@@ -23,6 +23,63 @@ _get_config() {
 # Operator runs the image as root use, but the MySQL Server will be run as mysql user.
 install_devnull="install /dev/null -m0600 -omysql -gmysql"
 MYSQLD_USER=mysql
+NDB_OPERATOR_USER="ndb-operator-user"
+
+# Wait for the MySQL Server started for the initialisations
+wait_for_server() {
+  for i in {30..0}; do
+    if mysqladmin --socket="$SOCKET" ping &>/dev/null; then
+      return
+    fi
+    echo '[Entrypoint] Waiting for server...'
+    sleep 1
+  done
+  echo >&2 '[Entrypoint] Timeout during MySQL init.'
+  exit 1
+}
+
+# Recreate the NDB Operator user, and reset its password and privileges,
+# through the local root user. The NDB Operator user is distributed to all
+# the MySQL Servers via the MySQL Cluster and can be lost or overwritten by
+# a restore, but the local root user is not affected by the restores.
+recover_ndb_operator_user() {
+  echo '[Entrypoint] Recovering the NDB Operator user'
+  "$@" --user=$MYSQLD_USER --daemonize --skip-networking --socket="$SOCKET"
+  wait_for_server
+
+  # To avoid using password on commandline, put it in a temporary file.
+  local passfile
+  passfile=$(mktemp -u /var/lib/mysql-files/XXXXXXXXXX)
+  $install_devnull "$passfile"
+  cat >"$passfile" <<EOF
+[client]
+password="${MYSQL_ROOT_PASSWORD}"
+EOF
+  local mysql=( mysql --defaults-extra-file="$passfile" --protocol=socket -uroot -hlocalhost --socket="$SOCKET" --init-command="SET @@SESSION.SQL_LOG_BIN=0;")
+
+  # Wait until ndbcluster is ready, so that the user is distributed
+  # to all the MySQL Servers, and then recreate the user.
+  local result=0
+  "${mysql[@]}" -e "CALL mysql.WaitUntilNdbclusterSetupCompletes($(_get_config 'ndb-wait-setup' "$@"));" && \
+  "${mysql[@]}" <<-EOSQL || result=$?
+  CREATE USER IF NOT EXISTS '${NDB_OPERATOR_USER}'@'${NDB_OPERATOR_HOST}' IDENTIFIED BY '${NDB_OPERATOR_PASSWORD}';
+  ALTER USER '${NDB_OPERATOR_USER}'@'${NDB_OPERATOR_HOST}' IDENTIFIED BY '${NDB_OPERATOR_PASSWORD}';
+  GRANT ALL ON *.* TO '${NDB_OPERATOR_USER}'@'${NDB_OPERATOR_HOST}' WITH GRANT OPTION;
+  FLUSH PRIVILEGES ;
+EOSQL
+  rm -f "$passfile"
+
+  # Stop the server via its pid, as the root user might not be accessible
+  local pid
+  pid=$(cat "$(_get_config 'pid-file' "$@")")
+  kill "$pid"
+  while kill -0 "$pid" &>/dev/null; do
+    sleep 1
+  done
+  echo "[Entrypoint] Server shut down"
+
+  return $result
+}
 
 # Validate the config passed to the script.
 # We redirect stdout to /dev/null so only the error messages are left.
@@ -41,6 +98,13 @@ SOCKET="$(_get_config 'socket' "$@")"
 if [ -f "${DATADIR}/mysql-init-complete" ]; then
   # data directory initialisation is complete
   echo "[Entrypoint] Data directory already exists and is initialized"
+  if [[ -n "${NDB_OPERATOR_USER_RECOVERY:-}" && -z "${NDB_MYSQLD_SERVER_GROUP:-}" && "$HOSTNAME" == *-mysqld-0 ]]; then
+    # NDB Operator has requested the recovery of its user.
+    # A failed recovery is reported by the NDB Operator.
+    if ! recover_ndb_operator_user "$@"; then
+      echo >&2 '[Entrypoint] Failed to recover the NDB Operator user'
+    fi
+  fi
   exit 0
 fi
 
@@ -64,17 +128,7 @@ $install_devnull "$PASSFILE"
 mysql=( mysql --defaults-extra-file="$PASSFILE" --protocol=socket -uroot -hlocalhost --socket="$SOCKET" --init-command="SET @@SESSION.SQL_LOG_BIN=0;")
 
 # Wait for MySQL Server to start
-for i in {30..0}; do
-  if mysqladmin --socket="$SOCKET" ping &>/dev/null; then
-    break
-  fi
-  echo '[Entrypoint] Waiting for server...'
-  sleep 1
-done
-if [ "$i" = 0 ]; then
-  echo >&2 '[Entrypoint] Timeout during MySQL init.'
-  exit 1
-fi
+wait_for_server
 
 # Install time zones
 mysql_tzinfo_to_sql /usr/share/zoneinfo | "${mysql[@]}" mysql
@@ -126,17 +180,21 @@ NDB_WAIT_SETUP="$(_get_config 'ndb-wait-setup' "$@")"
 # Wait until ndbcluster is ready
 "${mysql[@]}" -e "CALL mysql.WaitUntilNdbclusterSetupCompletes(${NDB_WAIT_SETUP});"
 
-# The NDB Operator user needs to be created only once from the 0th MySQL pod if it doesn't exist already
-NDB_OPERATOR_USER="ndb-operator-user"
+# The NDB Operator user needs to be created only once from the 0th MySQL pod if it doesn't exist already.
+# The MySQL Servers of the MySQL Server groups do not create it.
 OPERATOR_USER_CREATE=""
-if [[ "$HOSTNAME" == *-mysqld-0 && \
+if [[ "$HOSTNAME" == *-mysqld-0 && -z "${NDB_MYSQLD_SERVER_GROUP:-}" && \
       $("${mysql[@]}" -LNB -e "SELECT COUNT(*) FROM mysql.user WHERE user='${NDB_OPERATOR_USER}' and host='${NDB_OPERATOR_HOST}';") == "0" ]]; then
   OPERATOR_USER_CREATE="CREATE USER '${NDB_OPERATOR_USER}'@'${NDB_OPERATOR_HOST}' IDENTIFIED BY '${NDB_OPERATOR_PASSWORD}'; \
   GRANT ALL ON *.* TO '${NDB_OPERATOR_USER}'@'${NDB_OPERATOR_HOST}' WITH GRANT OPTION;"
 fi
 
-# Deduce allowed data node pod hostnames by extracting NdbCluster name from current hostname
+# Deduce allowed data node pod hostnames by extracting NdbCluster name from current hostname.
+# The hostnames of the MySQL Servers of a MySQL Server group also have the group name.
 NDBCLUSTER_NAME=${HOSTNAME%-mysqld-*}
+if [[ -n "${NDB_MYSQLD_SERVER_GROUP:-}" ]]; then
+  NDBCLUSTER_NAME=${NDBCLUSTER_NAME%-"${NDB_MYSQLD_SERVER_GROUP}"}
+fi
 ALLOWED_DATANODE_HOSTS=${NDBCLUSTER_NAME}-ndbmtd-%.${NDBCLUSTER_NAME}-ndbmtd.${NDB_POD_NAMESPACE}.svc.%
 
 # Create/update the required users
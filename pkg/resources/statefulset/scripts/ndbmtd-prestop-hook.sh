@@ -0,0 +1,22 @@
+#!/bin/bash
+
+# Copyright (c) 2023, Oracle and/or its affiliates.
+#
+# Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+# PreStop hook of the MySQL Cluster data nodes
+
+# Note : This script gracefully stops the data node via the Management
+#        server before the container is terminated, so that the other data
+#        nodes of the node group take over its responsibilities. If none of
+#        the Management nodes are available or if the Management node
+#        refuses to stop the data node, as stopping it will shut down the
+#        MySQL Cluster, the data node is left to be terminated by the kubelet.
+
+# Extract the nodeId written by the init container
+nodeId=$(cat /var/lib/ndb/run/nodeId.val)
+
+# Stop the data node using `ndb_mgm -e "<nodeId> stop"` command.
+# The command returns only after the data node has been stopped.
+echo "Stopping data node ${nodeId}"
+ndb_mgm -c "${NDB_CONNECTSTRING}" -e "${nodeId} stop" --connect-retries=2
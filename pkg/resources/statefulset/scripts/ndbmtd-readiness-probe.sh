@@ -0,0 +1,37 @@
+#!/bin/bash
+
+# Copyright (c) 2023, Oracle and/or its affiliates.
+#
+# Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+# Readiness probe of the MySQL Cluster data nodes
+
+# Note : This script uses ndb_mgm to check if a data node is in the started
+#        state. A data node that is restarted by its angel process, without
+#        a container restart, goes through the start phases again and will
+#        be reported as not ready until it is started. If none of the
+#        Management nodes are available, the status of the data node cannot
+#        be retrieved. Since this probe is run only after the startup probe
+#        has already seen the data node as started, the data node is
+#        considered to be still ready in that case.
+
+# Extract the nodeId written by the init container
+nodeId=$(cat /var/lib/ndb/run/nodeId.val)
+
+# Get node status using `ndb_mgm -e "<nodeId> status"` command
+nodeStatus=$(ndb_mgm -c "${NDB_CONNECTSTRING}" -e "${nodeId} status" --connect-retries=1 2>&1)
+# If nodeStatus has "Node ${nodeId}: started", the data node is ready
+if [[ "${nodeStatus}" =~ .*Node\ "${nodeId}":\ started.* ]]; then
+  exit 0
+fi
+
+if [[ "${nodeStatus}" =~ .*Unable\ to\ connect.* ]]; then
+  # None of the Management nodes are available
+  echo "Management nodes are not available. Retaining the data node's ready status."
+  exit 0
+fi
+
+echo "Datanode readiness check failed."
+echo "Node status output : "
+echo "${nodeStatus}"
+exit 1
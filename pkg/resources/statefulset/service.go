@@ -11,6 +11,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // newService builds and returns a new Service for the nodes with the given nodeTypeSelector
@@ -47,22 +48,44 @@ func newService(ndb *v1.NdbCluster, ports []int32, nodeType string, headLess, lo
 		})
 	}
 
+	// Custom annotations for the Service Resource
+	var serviceAnnotations map[string]string
+	if customAnnotations := ndb.GetCustomServiceAnnotations(nodeType); len(customAnnotations) != 0 {
+		serviceAnnotations = customAnnotations
+	}
+
 	// build a Service
 	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels:          serviceLabel,
+			Annotations:     serviceAnnotations,
 			Name:            ndb.GetServiceName(nodeType),
 			Namespace:       ndb.GetNamespace(),
 			OwnerReferences: ndb.GetOwnerReferences(),
 		},
 		Spec: corev1.ServiceSpec{
+			// The MySQL Cluster nodes resolve each other via the per-pod
+			// DNS records published by this Service before they can become
+			// ready, and so the addresses of the pods are always published.
 			PublishNotReadyAddresses: true,
 			Ports:                    servicePorts,
 			Selector:                 selectorLabel,
 			ClusterIP:                clusterIP,
 			Type:                     serviceType,
+			IPFamilyPolicy:           ndb.Spec.IPFamilyPolicy,
+			IPFamilies:               ndb.Spec.IPFamilies,
 		},
 	}
 
+	if lbSpec := ndb.GetLoadBalancerSpec(nodeType); loadBalancer && lbSpec != nil {
+		// Pass through the additional load balancer configuration
+		svc.Spec.LoadBalancerClass = lbSpec.LoadBalancerClass
+		svc.Spec.LoadBalancerSourceRanges = lbSpec.LoadBalancerSourceRanges
+		svc.Spec.ExternalTrafficPolicy = lbSpec.ExternalTrafficPolicy
+		if len(lbSpec.Annotations) != 0 {
+			svc.Annotations = labels.Merge(svc.Annotations, lbSpec.Annotations)
+		}
+	}
+
 	return svc
 }
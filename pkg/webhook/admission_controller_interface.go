@@ -9,6 +9,7 @@ import (
 	"regexp"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -24,6 +25,8 @@ type admissionController interface {
 	// validate functions should validate the request and return a AdmissionResponse
 	validateCreate(reqUID types.UID, obj runtime.Object) *admissionv1.AdmissionResponse
 	validateUpdate(reqUID types.UID, obj runtime.Object, oldObj runtime.Object) *admissionv1.AdmissionResponse
+	// validateScaleUpdate should validate an update made via the scale subresource
+	validateScaleUpdate(reqUID types.UID, scale *autoscalingv1.Scale) *admissionv1.AdmissionResponse
 	// mutate function should return the JSONPatch that needs to be applied to the resource
 	mutate(obj runtime.Object) *jsonPatchOperations
 }
@@ -65,6 +68,16 @@ func validate(req *admissionv1.AdmissionRequest, ac admissionController) *admiss
 			return requestAllowed(req.UID)
 		}
 
+		if req.SubResource == "scale" {
+			// The request carries a Scale object rather than the resource
+			scale := &autoscalingv1.Scale{}
+			if _, _, err := decoder.Decode(req.Object.Raw, nil, scale); err != nil {
+				return requestDeniedBad(req.UID, err.Error())
+			}
+			klog.V(5).Info(fmt.Sprintf("Retrieved new scale object : %v", scale))
+			return ac.validateScaleUpdate(req.UID, scale)
+		}
+
 		// retrieve new and old objects
 		obj, _, err := decoder.Decode(req.Object.Raw, defaultGVK, ac.newObject())
 		if err != nil {
@@ -7,6 +7,9 @@ package webhook
 import (
 	"flag"
 
+	ndbconfig "github.com/mysql/ndb-operator/config"
+	ndbclientset "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
+
 	k8s "k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -34,8 +37,9 @@ func validateCommandLineArgs() {
 }
 
 func init() {
-	// klog arguments
+	// klog and log format arguments
 	klog.InitFlags(nil)
+	ndbconfig.InitLoggingFlags()
 
 	// argument to get the k8s service name
 	flag.StringVar(&config.serviceName, "service", "",
@@ -49,28 +53,46 @@ func init() {
 		"Path to a kubeconfig. Only required if out-of-cluster.")
 }
 
-// The clientset to the k8s cluster. Do not use this directly,
-// rather use getK8sClientset to ensure that this is created properly
-var _clientset *k8s.Clientset
+// The config and the clientsets to the k8s cluster. Do not use these
+// directly, rather use the getter methods to ensure that they are created properly
+var (
+	_k8sConfig    *restclient.Config
+	_clientset    *k8s.Clientset
+	_ndbClientset *ndbclientset.Clientset
+)
 
-// getK8sClientset creates a clientset using the given config
-func getK8sClientset() *k8s.Clientset {
-	if _clientset != nil {
-		return _clientset
+// getK8sConfig builds the config to connect to the k8s cluster
+func getK8sConfig() *restclient.Config {
+	if _k8sConfig != nil {
+		return _k8sConfig
 	}
-	// Create the clientset and return
-	var cfg *restclient.Config
+
 	var err error
 	if len(config.masterURL) == 0 && len(config.kubeconfig) == 0 {
-		cfg, err = restclient.InClusterConfig()
+		_k8sConfig, err = restclient.InClusterConfig()
 	} else {
-		cfg, err = clientcmd.BuildConfigFromFlags(config.masterURL, config.kubeconfig)
+		_k8sConfig, err = clientcmd.BuildConfigFromFlags(config.masterURL, config.kubeconfig)
 	}
 	if err != nil {
 		klog.Error("Error building kubeconfig: ", err)
 		return nil
 	}
 
+	return _k8sConfig
+}
+
+// getK8sClientset creates a clientset using the given config
+func getK8sClientset() *k8s.Clientset {
+	if _clientset != nil {
+		return _clientset
+	}
+	// Create the clientset and return
+	cfg := getK8sConfig()
+	if cfg == nil {
+		return nil
+	}
+
+	var err error
 	_clientset, err = k8s.NewForConfig(cfg)
 	if err != nil {
 		klog.Error("Error building kubernetes clientset: ", err)
@@ -79,3 +101,25 @@ func getK8sClientset() *k8s.Clientset {
 
 	return _clientset
 }
+
+// getNdbClientset creates a clientset for the NdbCluster
+// and NdbClusterPolicy resources using the given config
+func getNdbClientset() *ndbclientset.Clientset {
+	if _ndbClientset != nil {
+		return _ndbClientset
+	}
+	// Create the clientset and return
+	cfg := getK8sConfig()
+	if cfg == nil {
+		return nil
+	}
+
+	var err error
+	_ndbClientset, err = ndbclientset.NewForConfig(cfg)
+	if err != nil {
+		klog.Error("Error building ndb clientset: ", err)
+		return nil
+	}
+
+	return _ndbClientset
+}
@@ -1,10 +1,12 @@
-// Copyright (c) 2021, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2021, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
 package webhook
 
 import (
+	"fmt"
+
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
 	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -15,10 +17,61 @@ import (
 )
 
 // ndbAdmissionController implements admissionController for Ndb resource
-type ndbAdmissionController struct{}
+type ndbAdmissionController struct {
+	// policyGetter retrieves the NdbClusterPolicies that apply
+	// to the NdbCluster. No policies are applied if it is nil.
+	policyGetter ndbClusterPolicyGetter
+	// namespaceGetter retrieves the labels of the namespace of the
+	// NdbCluster, to check if it is a production namespace. The
+	// namespaces are treated as non-production if it is nil.
+	namespaceGetter namespaceLabelsGetter
+	// ndbClusterGetter retrieves the existing NdbCluster to validate
+	// the updates made via the scale subresource. Such updates are
+	// denied if it is nil.
+	ndbClusterGetter ndbClusterGetter
+}
+
+func newNdbAdmissionController(policyGetter ndbClusterPolicyGetter,
+	namespaceGetter namespaceLabelsGetter, clusterGetter ndbClusterGetter) admissionController {
+	return &ndbAdmissionController{
+		policyGetter:     policyGetter,
+		namespaceGetter:  namespaceGetter,
+		ndbClusterGetter: clusterGetter,
+	}
+}
+
+// getPolicies returns the NdbClusterPolicies that apply to the given NdbCluster
+func (nv *ndbAdmissionController) getPolicies(nc *v1.NdbCluster) ([]*v1.NdbClusterPolicy, error) {
+	if nv.policyGetter == nil {
+		return nil, nil
+	}
+	return nv.policyGetter.getPolicies(nc.Namespace)
+}
+
+// validatePolicies verifies that the NdbCluster satisfies the constraints of the
+// NdbClusterPolicies that apply to it. The request is denied if the policies
+// cannot be retrieved, so that an NdbCluster never bypasses the constraints.
+// On an update, oldNC is the existing NdbCluster and only the violations
+// introduced by the update are denied.
+func (nv *ndbAdmissionController) validatePolicies(
+	reqUID types.UID, nc, oldNC *v1.NdbCluster) *admissionv1.AdmissionResponse {
+	policies, err := nv.getPolicies(nc)
+	if err != nil {
+		return requestDenied(reqUID,
+			errors.NewInternalError(fmt.Errorf("failed to retrieve the NdbClusterPolicies : %s", err)))
+	}
 
-func newNdbAdmissionController() admissionController {
-	return &ndbAdmissionController{}
+	errList := validatePolicyConstraints(nc, policies)
+	if oldNC != nil && len(errList) != 0 {
+		errList = excludeExistingViolations(errList, validatePolicyConstraints(oldNC, policies))
+	}
+
+	if len(errList) != 0 {
+		// NdbCluster violates the policy constraints
+		return requestDeniedNdbInvalid(reqUID, nc, errList)
+	}
+
+	return nil
 }
 
 func (nv *ndbAdmissionController) getGVR() *metav1.GroupVersionResource {
@@ -48,7 +101,11 @@ func (nv *ndbAdmissionController) validateCreate(reqUID types.UID, obj runtime.O
 		return requestDeniedNdbInvalid(reqUID, nc, errList)
 	}
 
-	return requestAllowed(reqUID)
+	if response := nv.validatePolicies(reqUID, nc, nil); response != nil {
+		return response
+	}
+
+	return requestAllowedWithWarnings(reqUID, nv.getWarnings(nc))
 }
 
 func (nv *ndbAdmissionController) validateUpdate(
@@ -72,7 +129,11 @@ func (nv *ndbAdmissionController) validateUpdate(
 		return requestDeniedNdbInvalid(reqUID, newNC, errList)
 	}
 
-	return requestAllowed(reqUID)
+	if response := nv.validatePolicies(reqUID, newNC, oldNC); response != nil {
+		return response
+	}
+
+	return requestAllowedWithWarnings(reqUID, nv.getWarnings(newNC))
 }
 
 func (nv *ndbAdmissionController) mutate(obj runtime.Object) *jsonPatchOperations {
@@ -97,5 +158,11 @@ func (nv *ndbAdmissionController) mutate(obj runtime.Object) *jsonPatchOperation
 		patchOps.replace("/spec/mysqlNode/maxNodeCount", nc.Spec.MysqlNode.NodeCount+2)
 	}
 
+	// Apply the defaults from the NdbClusterPolicies. Any failure in
+	// retrieving the policies is caught by the validating webhook.
+	if policies, err := nv.getPolicies(nc); err == nil {
+		addPolicyDefaults(nc, policies, &patchOps)
+	}
+
 	return &patchOps
 }
@@ -1,14 +1,26 @@
-// Copyright (c) 2022, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
 package webhook
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
 	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func Test_ndbAdmissionController_mutate(t *testing.T) {
@@ -56,7 +68,7 @@ func Test_ndbAdmissionController_mutate(t *testing.T) {
 		},
 	}
 
-	ndbAc := newNdbAdmissionController()
+	ndbAc := newNdbAdmissionController(nil, nil, nil)
 	nc := testutils.NewTestNdb("default", "test", 1)
 	for _, tc := range testcases {
 		nc.Spec = *tc.ncSpec
@@ -71,3 +83,397 @@ func Test_ndbAdmissionController_mutate(t *testing.T) {
 		}
 	}
 }
+
+// fakePolicyGetter implements ndbClusterPolicyGetter for the tests
+type fakePolicyGetter []*v1.NdbClusterPolicy
+
+func (fpg fakePolicyGetter) getPolicies(_ string) ([]*v1.NdbClusterPolicy, error) {
+	return fpg, nil
+}
+
+func Test_ndbAdmissionController_mutateWithPolicyDefaults(t *testing.T) {
+	policies := fakePolicyGetter{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy-1"},
+			Spec: v1.NdbClusterPolicySpec{
+				Defaults: &v1.NdbClusterPolicyDefaults{
+					ImagePullSecrets: []corev1.LocalObjectReference{{Name: "regcred"}},
+					PodLabels:        map[string]string{"team": "b", "env": "prod"},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy-2"},
+			Spec: v1.NdbClusterPolicySpec{
+				Defaults: &v1.NdbClusterPolicyDefaults{
+					ImagePullSecrets: []corev1.LocalObjectReference{{Name: "other-regcred"}},
+					PodLabels:        map[string]string{"env": "dev", "tier": "db"},
+				},
+			},
+		},
+	}
+
+	ndbAc := newNdbAdmissionController(policies, nil, nil)
+	nc := testutils.NewTestNdb("default", "test", 2)
+	nc.Spec.PodLabels = map[string]string{"team": "a"}
+
+	patch, err := ndbAc.mutate(nc).getPatch()
+	if err != nil {
+		t.Fatalf("mutate failed with error %q", err)
+	}
+
+	// The values in the NdbCluster and the first policy take precedence
+	expectedPatch := `[{"op":"add","path":"/spec/imagePullSecrets","value":[{"name":"regcred"}]},` +
+		`{"op":"add","path":"/spec/podLabels","value":{"env":"prod","team":"a","tier":"db"}}]`
+	if string(patch) != expectedPatch {
+		t.Errorf("Expected patch `%s` but got `%s`", expectedPatch, string(patch))
+	}
+}
+
+func Test_ndbAdmissionController_validateWithPolicyConstraints(t *testing.T) {
+	policies := fakePolicyGetter{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+			Spec: v1.NdbClusterPolicySpec{
+				Constraints: &v1.NdbClusterPolicyConstraints{
+					AllowedImages:    []string{"container-registry.oracle.com/mysql/*"},
+					RequireResources: true,
+				},
+			},
+		},
+	}
+
+	resources := &v1.NdbClusterPodSpec{
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		},
+	}
+
+	testcases := []struct {
+		desc     string
+		updateNc func(nc *v1.NdbCluster)
+		allowed  bool
+	}{
+		{
+			desc:    "constraints satisfied",
+			allowed: true,
+		},
+		{
+			desc: "image not allowed",
+			updateNc: func(nc *v1.NdbCluster) {
+				nc.Spec.DataNode.Image = "docker.io/mysql/mysql-cluster:latest"
+			},
+		},
+		{
+			desc: "resources not specified",
+			updateNc: func(nc *v1.NdbCluster) {
+				nc.Spec.MysqlNode.NdbPodSpec = nil
+			},
+		},
+		{
+			desc: "sidecar container image not allowed",
+			updateNc: func(nc *v1.NdbCluster) {
+				nc.Spec.DataNode.NdbPodSpec = resources.DeepCopy()
+				nc.Spec.DataNode.NdbPodSpec.SidecarContainers = []corev1.Container{
+					{Name: "sidecar", Image: "docker.io/library/busybox:latest"},
+				}
+			},
+		},
+		{
+			desc: "plugin init container image not allowed",
+			updateNc: func(nc *v1.NdbCluster) {
+				nc.Spec.MysqlNode.Plugins = &v1.NdbMysqldPluginsSpec{
+					InitContainers: []corev1.Container{
+						{Name: "install-plugins", Image: "docker.io/library/busybox:latest"},
+					},
+				}
+			},
+		},
+		{
+			desc: "audit log sidecar container image allowed",
+			updateNc: func(nc *v1.NdbCluster) {
+				nc.Spec.MysqlNode.AuditLog = &v1.NdbMysqldAuditLogSpec{
+					SidecarContainers: []corev1.Container{
+						{Name: "ship-audit-log", Image: "container-registry.oracle.com/mysql/shipper:1.0"},
+					},
+				}
+			},
+			allowed: true,
+		},
+		{
+			desc: "arbitrator resources not specified",
+			updateNc: func(nc *v1.NdbCluster) {
+				nc.Spec.Arbitrator = &v1.NdbArbitratorSpec{}
+			},
+		},
+	}
+
+	ndbAc := newNdbAdmissionController(policies, nil, nil)
+	for _, tc := range testcases {
+		nc := testutils.NewTestNdb("default", "test", 2)
+		nc.Spec.Image = "container-registry.oracle.com/mysql/community-cluster:8.1.0"
+		nc.Spec.ManagementNode.NdbPodSpec = resources
+		nc.Spec.DataNode.NdbPodSpec = resources
+		nc.Spec.MysqlNode.NdbPodSpec = resources
+		if tc.updateNc != nil {
+			tc.updateNc(nc)
+		}
+
+		response := ndbAc.validateCreate("", nc)
+		if response.Allowed != tc.allowed {
+			t.Errorf("Testcase %q failed : expected allowed to be %v but got %v : %v",
+				tc.desc, tc.allowed, response.Allowed, response.Result)
+		}
+	}
+}
+
+func Test_ndbAdmissionController_validateUpdateWithPolicyConstraints(t *testing.T) {
+	policies := fakePolicyGetter{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+			Spec: v1.NdbClusterPolicySpec{
+				Constraints: &v1.NdbClusterPolicyConstraints{
+					AllowedImages:    []string{"container-registry.oracle.com/mysql/*"},
+					RequireResources: true,
+				},
+			},
+		},
+	}
+
+	resources := &v1.NdbClusterPodSpec{
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		},
+	}
+
+	testcases := []struct {
+		desc     string
+		updateNc func(nc *v1.NdbCluster)
+		allowed  bool
+	}{
+		{
+			desc: "existing violations retained",
+			updateNc: func(nc *v1.NdbCluster) {
+				nc.Spec.PodLabels = map[string]string{"team": "db"}
+			},
+			allowed: true,
+		},
+		{
+			desc: "existing image violation fixed",
+			updateNc: func(nc *v1.NdbCluster) {
+				nc.Spec.DataNode.Image = "container-registry.oracle.com/mysql/community-cluster:8.1.0"
+			},
+			allowed: true,
+		},
+		{
+			desc: "disallowed image replaced by another disallowed image",
+			updateNc: func(nc *v1.NdbCluster) {
+				nc.Spec.DataNode.Image = "docker.io/mysql/mysql-cluster:8.1.0"
+			},
+		},
+		{
+			desc: "new violation introduced",
+			updateNc: func(nc *v1.NdbCluster) {
+				nc.Spec.ManagementNode.Image = "docker.io/mysql/mysql-cluster:latest"
+			},
+		},
+	}
+
+	ndbAc := newNdbAdmissionController(policies, nil, nil)
+	for _, tc := range testcases {
+		// NdbCluster created before the policy, violating
+		// both the image and the resources constraints
+		oldNc := testutils.NewTestNdb("default", "test", 2)
+		oldNc.Spec.Image = "container-registry.oracle.com/mysql/community-cluster:8.1.0"
+		oldNc.Spec.DataNode.Image = "docker.io/mysql/mysql-cluster:latest"
+		oldNc.Spec.ManagementNode.NdbPodSpec = resources
+		oldNc.Spec.DataNode.NdbPodSpec = resources
+		// The previous update has been processed by the operator
+		oldNc.Generation = 1
+		oldNc.Status.ProcessedGeneration = 1
+
+		newNc := oldNc.DeepCopy()
+		tc.updateNc(newNc)
+
+		response := ndbAc.validateUpdate("", newNc, oldNc)
+		if response.Allowed != tc.allowed {
+			t.Errorf("Testcase %q failed : expected allowed to be %v but got %v : %v",
+				tc.desc, tc.allowed, response.Allowed, response.Result)
+		} else if !response.Allowed && !strings.Contains(response.Result.Message, "NdbClusterPolicy") {
+			t.Errorf("Testcase %q failed : request denied for an unexpected reason : %s",
+				tc.desc, response.Result.Message)
+		}
+	}
+}
+
+// fakeNamespaceGetter implements namespaceLabelsGetter for the tests
+type fakeNamespaceGetter map[string]map[string]string
+
+func (fng fakeNamespaceGetter) getNamespaceLabels(namespace string) (map[string]string, error) {
+	return fng[namespace], nil
+}
+
+func Test_ndbAdmissionController_validateWithWarnings(t *testing.T) {
+	namespaces := fakeNamespaceGetter{
+		"prod": {constants.ProductionNamespaceLabel: "true"},
+	}
+
+	dataMemory, indexMemory := intstr.FromString("32M"), intstr.FromString("16M")
+	testcases := []struct {
+		desc             string
+		namespace        string
+		redundancyLevel  int32
+		dataNodeConfig   map[string]*intstr.IntOrString
+		expectedWarnings int
+	}{
+		{
+			desc:            "no risky settings",
+			namespace:       "prod",
+			redundancyLevel: 2,
+		},
+		{
+			desc:             "single replica in a production namespace",
+			namespace:        "prod",
+			redundancyLevel:  1,
+			expectedWarnings: 1,
+		},
+		{
+			desc:            "single replica in a non-production namespace",
+			namespace:       "default",
+			redundancyLevel: 1,
+		},
+		{
+			desc:            "small DataMemory and a deprecated config param",
+			namespace:       "default",
+			redundancyLevel: 2,
+			dataNodeConfig: map[string]*intstr.IntOrString{
+				"DataMemory":  &dataMemory,
+				"IndexMemory": &indexMemory,
+			},
+			expectedWarnings: 2,
+		},
+	}
+
+	ndbAc := newNdbAdmissionController(nil, namespaces, nil)
+	for _, tc := range testcases {
+		nc := testutils.NewTestNdb(tc.namespace, "test", 2)
+		nc.Spec.RedundancyLevel = tc.redundancyLevel
+		nc.Spec.DataNode.Config = tc.dataNodeConfig
+
+		response := ndbAc.validateCreate("", nc)
+		if !response.Allowed {
+			t.Errorf("Testcase %q failed : request was denied : %v", tc.desc, response.Result)
+		} else if len(response.Warnings) != tc.expectedWarnings {
+			t.Errorf("Testcase %q failed : expected %d warnings but got %v",
+				tc.desc, tc.expectedWarnings, response.Warnings)
+		}
+	}
+}
+
+// fakeNdbClusterGetter implements ndbClusterGetter for the tests
+type fakeNdbClusterGetter map[string]*v1.NdbCluster
+
+func (fng fakeNdbClusterGetter) getNdbCluster(namespace, name string) (*v1.NdbCluster, error) {
+	nc, exists := fng[namespace+"/"+name]
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("ndbcluster"), name)
+	}
+	return nc, nil
+}
+
+func Test_ndbAdmissionController_validateScaleUpdate(t *testing.T) {
+	nc := testutils.NewTestNdb("default", "test", 2)
+	nc.Generation = 1
+	nc.Status.ProcessedGeneration = 1
+	updatingNc := testutils.NewTestNdb("default", "updating-test", 2)
+	updatingNc.Generation = 2
+	updatingNc.Status.ProcessedGeneration = 1
+	clusters := fakeNdbClusterGetter{
+		"default/test":          nc,
+		"default/updating-test": updatingNc,
+	}
+
+	testcases := []struct {
+		desc            string
+		name            string
+		replicas        int32
+		getter          ndbClusterGetter
+		allowed         bool
+		messageContains string
+	}{
+		{
+			desc:     "scale within maxNodeCount",
+			name:     "test",
+			replicas: 4,
+			getter:   clusters,
+			allowed:  true,
+		},
+		{
+			desc:            "scale beyond maxNodeCount",
+			name:            "test",
+			replicas:        5,
+			getter:          clusters,
+			messageContains: "spec.mysqlNode.maxNodeCount",
+		},
+		{
+			desc:            "previous update is still being applied",
+			name:            "updating-test",
+			replicas:        3,
+			getter:          clusters,
+			messageContains: "previous update",
+		},
+		{
+			desc:            "NdbCluster does not exist",
+			name:            "missing-test",
+			replicas:        3,
+			getter:          clusters,
+			messageContains: "not found",
+		},
+		{
+			desc:     "NdbCluster cannot be retrieved",
+			name:     "test",
+			replicas: 3,
+		},
+	}
+
+	for _, tc := range testcases {
+		scale := &autoscalingv1.Scale{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "autoscaling/v1", Kind: "Scale"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: tc.name},
+			Spec:       autoscalingv1.ScaleSpec{Replicas: tc.replicas},
+		}
+		raw, err := json.Marshal(scale)
+		if err != nil {
+			t.Fatalf("Failed to encode the Scale object : %s", err)
+		}
+
+		ndbAc := newNdbAdmissionController(nil, nil, tc.getter)
+		response := validate(&admissionv1.AdmissionRequest{
+			Resource:    *ndbAc.getGVR(),
+			SubResource: "scale",
+			Operation:   admissionv1.Update,
+			Namespace:   "default",
+			Name:        tc.name,
+			Object:      runtime.RawExtension{Raw: raw},
+			OldObject:   runtime.RawExtension{Raw: raw},
+		}, ndbAc)
+		if response.Allowed != tc.allowed {
+			t.Errorf("Testcase %q failed : expected allowed to be %v but got %v : %v",
+				tc.desc, tc.allowed, response.Allowed, response.Result)
+		} else if !response.Allowed && !strings.Contains(response.Result.Message, tc.messageContains) {
+			t.Errorf("Testcase %q failed : request denied for an unexpected reason : %s",
+				tc.desc, response.Result.Message)
+		}
+	}
+
+	// The existing NdbCluster should not have been modified
+	if nc.Spec.MysqlNode.NodeCount != 2 {
+		t.Errorf("Existing NdbCluster was modified : nodeCount is %d", nc.Spec.MysqlNode.NodeCount)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	ndbclientset "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
+	ndbinformers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
+	ndblisters "github.com/mysql/ndb-operator/pkg/generated/listers/ndbcontroller/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+// ndbClusterPolicyGetter retrieves the NdbClusterPolicies
+// that apply to the NdbClusters of a namespace
+type ndbClusterPolicyGetter interface {
+	getPolicies(namespace string) ([]*v1.NdbClusterPolicy, error)
+}
+
+// ndbClusterPolicyClient implements ndbClusterPolicyGetter by
+// retrieving the policies from the cache of an informer
+type ndbClusterPolicyClient struct {
+	k8sClient    kubernetes.Interface
+	policyLister ndblisters.NdbClusterPolicyLister
+}
+
+func newNdbClusterPolicyClient(
+	k8sClient kubernetes.Interface, policyLister ndblisters.NdbClusterPolicyLister) ndbClusterPolicyGetter {
+	return &ndbClusterPolicyClient{
+		k8sClient:    k8sClient,
+		policyLister: policyLister,
+	}
+}
+
+// startNdbClusterPolicyInformer starts an informer that caches the
+// NdbClusterPolicies until the stopCh is closed, and returns the
+// lister of the informer once its cache has been synced.
+func startNdbClusterPolicyInformer(
+	ndbClient ndbclientset.Interface, stopCh <-chan struct{}) (ndblisters.NdbClusterPolicyLister, error) {
+	informerFactory := ndbinformers.NewSharedInformerFactory(ndbClient, 0)
+	policyInformer := informerFactory.Mysql().V1().NdbClusterPolicies()
+	// Retrieve the lister before starting the factory
+	// so that the policy informer is registered with it
+	policyLister := policyInformer.Lister()
+	informerFactory.Start(stopCh)
+
+	if !cache.WaitForNamedCacheSync("ndb-operator-webhook", stopCh, policyInformer.Informer().HasSynced) {
+		return nil, fmt.Errorf("failed to sync the NdbClusterPolicy informer cache")
+	}
+
+	return policyLister, nil
+}
+
+// getPolicies returns the NdbClusterPolicies whose namespaceSelector
+// selects the given namespace, sorted by their names.
+func (npc *ndbClusterPolicyClient) getPolicies(namespace string) ([]*v1.NdbClusterPolicy, error) {
+	allPolicies, err := npc.policyLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list the NdbClusterPolicies : %s", err)
+		return nil, err
+	}
+
+	if len(allPolicies) == 0 {
+		// No policies defined
+		return nil, nil
+	}
+
+	ns, err := npc.k8sClient.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
+	if err != nil {
+		klog.Errorf("Failed to retrieve the namespace %q : %s", namespace, err)
+		return nil, err
+	}
+
+	var policies []*v1.NdbClusterPolicy
+	for _, policy := range allPolicies {
+		applies, err := policy.AppliesToNamespace(ns.Labels)
+		if err != nil {
+			klog.Errorf("Invalid namespaceSelector in NdbClusterPolicy %q : %s", policy.Name, err)
+			return nil, err
+		}
+
+		if applies {
+			policies = append(policies, policy)
+		}
+	}
+
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+	return policies, nil
+}
+
+// mergeDefaultMaps returns the map specified in the NdbCluster merged with
+// the defaults specified by the policies. The values already present in
+// the NdbCluster are retained, and when more than one policy defines a
+// default for the same key, the one from the policy that comes first wins.
+// It returns nil if the policies do not add any new key.
+func mergeDefaultMaps(ncMap map[string]string, policyMaps []map[string]string) map[string]string {
+	var mergedMap map[string]string
+	for _, policyMap := range policyMaps {
+		for key, value := range policyMap {
+			if _, exists := ncMap[key]; exists {
+				continue
+			}
+			if _, exists := mergedMap[key]; exists {
+				continue
+			}
+
+			if mergedMap == nil {
+				// Start with a copy of the NdbCluster's map
+				mergedMap = make(map[string]string, len(ncMap))
+				for k, v := range ncMap {
+					mergedMap[k] = v
+				}
+			}
+			mergedMap[key] = value
+		}
+	}
+
+	return mergedMap
+}
+
+// addPolicyDefaults adds the patch operations required
+// to apply the defaults of the policies to the NdbCluster.
+func addPolicyDefaults(nc *v1.NdbCluster, policies []*v1.NdbClusterPolicy, patchOps *jsonPatchOperations) {
+	var podLabels, podAnnotations, serviceAnnotations []map[string]string
+	for _, policy := range policies {
+		defaults := policy.Spec.Defaults
+		if defaults == nil {
+			continue
+		}
+
+		if len(defaults.ImagePullSecrets) != 0 &&
+			nc.Spec.ImagePullSecretName == "" && len(nc.Spec.ImagePullSecrets) == 0 {
+			// Use the ImagePullSecrets of the first policy that specifies them
+			patchOps.add("/spec/imagePullSecrets", defaults.ImagePullSecrets)
+			nc.Spec.ImagePullSecrets = defaults.ImagePullSecrets
+		}
+
+		podLabels = append(podLabels, defaults.PodLabels)
+		podAnnotations = append(podAnnotations, defaults.PodAnnotations)
+		serviceAnnotations = append(serviceAnnotations, defaults.ServiceAnnotations)
+	}
+
+	// An 'add' operation on an existing map replaces it entirely,
+	// so the merged maps retain the values from the NdbCluster.
+	if mergedMap := mergeDefaultMaps(nc.Spec.PodLabels, podLabels); mergedMap != nil {
+		patchOps.add("/spec/podLabels", mergedMap)
+	}
+	if mergedMap := mergeDefaultMaps(nc.Spec.PodAnnotations, podAnnotations); mergedMap != nil {
+		patchOps.add("/spec/podAnnotations", mergedMap)
+	}
+	if mergedMap := mergeDefaultMaps(nc.Spec.ServiceAnnotations, serviceAnnotations); mergedMap != nil {
+		patchOps.add("/spec/serviceAnnotations", mergedMap)
+	}
+}
+
+// validatePolicyConstraints verifies that the NdbCluster
+// satisfies the constraints of all the given policies.
+func validatePolicyConstraints(nc *v1.NdbCluster, policies []*v1.NdbClusterPolicy) (errList field.ErrorList) {
+	for _, policy := range policies {
+		errList = append(errList, policy.ValidateNdbCluster(nc)...)
+	}
+	return errList
+}
+
+// excludeExistingViolations returns the errors from errList that are not
+// present in existingErrList, i.e. the policy violations introduced by an
+// update. The violations that existed before the update, for example in an
+// NdbCluster created before the policy, do not block the update.
+func excludeExistingViolations(errList, existingErrList field.ErrorList) (newErrList field.ErrorList) {
+	existing := make(map[string]bool, len(existingErrList))
+	for _, err := range existingErrList {
+		existing[err.Error()] = true
+	}
+
+	for _, err := range errList {
+		if !existing[err.Error()] {
+			newErrList = append(newErrList, err)
+		}
+	}
+	return newErrList
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	ndbclientset "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ndbClusterGetter retrieves an NdbCluster
+type ndbClusterGetter interface {
+	getNdbCluster(namespace, name string) (*v1.NdbCluster, error)
+}
+
+// ndbClusterClient implements ndbClusterGetter by
+// retrieving the NdbClusters from the K8s API Server
+type ndbClusterClient struct {
+	ndbClient ndbclientset.Interface
+}
+
+func newNdbClusterClient(ndbClient ndbclientset.Interface) ndbClusterGetter {
+	return &ndbClusterClient{
+		ndbClient: ndbClient,
+	}
+}
+
+// getNdbCluster returns the NdbCluster with the given namespace and name
+func (ncc *ndbClusterClient) getNdbCluster(namespace, name string) (*v1.NdbCluster, error) {
+	return ncc.ndbClient.MysqlV1().NdbClusters(namespace).Get(context.Background(), name, metav1.GetOptions{})
+}
+
+// validateScaleUpdate validates an update made to the NdbCluster via the
+// scale subresource. The replicas are applied to the spec.mysqlNode.nodeCount
+// of the existing NdbCluster, and the result is validated like any other
+// update to the NdbCluster, so that the scale subresource cannot be used to
+// bypass the validations, e.g. to scale beyond spec.mysqlNode.maxNodeCount.
+func (nv *ndbAdmissionController) validateScaleUpdate(
+	reqUID types.UID, scale *autoscalingv1.Scale) *admissionv1.AdmissionResponse {
+	if nv.ndbClusterGetter == nil {
+		return requestDenied(reqUID, errors.NewInternalError(
+			fmt.Errorf("cannot validate the scale subresource update of NdbCluster %q", scale.Name)))
+	}
+
+	oldNC, err := nv.ndbClusterGetter.getNdbCluster(scale.Namespace, scale.Name)
+	if err != nil {
+		return requestDenied(reqUID, errors.NewInternalError(
+			fmt.Errorf("failed to retrieve the NdbCluster %q : %s", scale.Name, err)))
+	}
+
+	newNC := oldNC.DeepCopy()
+	if newNC.Spec.MysqlNode == nil {
+		newNC.Spec.MysqlNode = &v1.NdbMysqldSpec{}
+	}
+	newNC.Spec.MysqlNode.NodeCount = scale.Spec.Replicas
+
+	return nv.validateUpdate(reqUID, newNC, oldNC)
+}
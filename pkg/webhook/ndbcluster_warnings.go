@@ -0,0 +1,66 @@
+// Copyright (c) 2023, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package webhook
+
+import (
+	"context"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	klog "k8s.io/klog/v2"
+)
+
+// namespaceLabelsGetter retrieves the labels of a namespace
+type namespaceLabelsGetter interface {
+	getNamespaceLabels(namespace string) (map[string]string, error)
+}
+
+// namespaceLabelsClient implements namespaceLabelsGetter by
+// retrieving the namespaces from the K8s API Server
+type namespaceLabelsClient struct {
+	k8sClient kubernetes.Interface
+}
+
+func newNamespaceLabelsClient(k8sClient kubernetes.Interface) namespaceLabelsGetter {
+	return &namespaceLabelsClient{
+		k8sClient: k8sClient,
+	}
+}
+
+// getNamespaceLabels returns the labels of the given namespace
+func (nlc *namespaceLabelsClient) getNamespaceLabels(namespace string) (map[string]string, error) {
+	ns, err := nlc.k8sClient.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return ns.Labels, nil
+}
+
+// isProductionNamespace returns true if the namespace of the
+// NdbCluster is marked as a production namespace. The warnings are
+// only advisory, so a namespace that cannot be retrieved is treated
+// as a non-production namespace rather than failing the request.
+func (nv *ndbAdmissionController) isProductionNamespace(nc *v1.NdbCluster) bool {
+	if nv.namespaceGetter == nil {
+		return false
+	}
+
+	nsLabels, err := nv.namespaceGetter.getNamespaceLabels(nc.Namespace)
+	if err != nil {
+		klog.Errorf("Failed to retrieve the namespace %q : %s", nc.Namespace, err)
+		return false
+	}
+
+	return nsLabels[constants.ProductionNamespaceLabel] == "true"
+}
+
+// getWarnings returns the warnings about the deprecated
+// and the risky settings in the spec of the NdbCluster
+func (nv *ndbAdmissionController) getWarnings(nc *v1.NdbCluster) []string {
+	return nc.GetSpecWarnings(nv.isProductionNamespace(nc))
+}
@@ -48,6 +48,17 @@ func requestAllowed(reqUID types.UID) *admissionv1.AdmissionResponse {
 	}
 }
 
+// requestAllowedWithWarnings returns a AdmissionResponse with the request
+// allowed and the given warnings to be displayed to the client
+func requestAllowedWithWarnings(reqUID types.UID, warnings []string) *admissionv1.AdmissionResponse {
+	for _, warning := range warnings {
+		klog.Infof("Warning : %s", warning)
+	}
+	response := requestAllowed(reqUID)
+	response.Warnings = warnings
+	return response
+}
+
 // requestAllowedWithPatch returns a AdmissionResponse with the
 // request allowed response and a patch to be applied to the object
 func requestAllowedWithPatch(reqUID types.UID, patch []byte) *admissionv1.AdmissionResponse {
@@ -15,11 +15,13 @@ import (
 	"os"
 	"strings"
 
+	ndbconfig "github.com/mysql/ndb-operator/config"
 	"github.com/mysql/ndb-operator/pkg/controllers"
 	"github.com/mysql/ndb-operator/pkg/helpers"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes/scheme"
 	klog "k8s.io/klog/v2"
 )
@@ -114,8 +116,13 @@ func serve(w http.ResponseWriter, r *http.Request, ac admissionController, execu
 	sendAdmissionResponse(w, response)
 }
 
-// initWebhookServer sets up the handler and initializes the server
-func initWebhookServer(ws *http.Server) {
+// initWebhookServer sets up the handler and initializes the server.
+// The policyGetter is used to retrieve the NdbClusterPolicies to be
+// applied to the NdbClusters, the namespaceGetter to check if the
+// NdbClusters are in production namespaces, and the clusterGetter
+// to validate the updates made via the scale subresource.
+func initWebhookServer(ws *http.Server, policyGetter ndbClusterPolicyGetter,
+	namespaceGetter namespaceLabelsGetter, clusterGetter ndbClusterGetter) {
 	// set server address
 	ws.Addr = webHookServerAddr
 
@@ -128,7 +135,7 @@ func initWebhookServer(ws *http.Server) {
 
 	// pattern to admissionController mapping
 	admissionControllers := map[string]admissionController{
-		"ndb": newNdbAdmissionController(),
+		"ndb": newNdbAdmissionController(policyGetter, namespaceGetter, clusterGetter),
 	}
 
 	// allowed admissionController requestTypes
@@ -219,11 +226,25 @@ func setWebhookServerTLSCerts(ctx context.Context, ws *http.Server) {
 func Run() {
 	// Parse the arguments
 	flag.Parse()
+	ndbconfig.SetupLogging()
 	validateCommandLineArgs()
 
+	// Get the clientsets required to retrieve the NdbClusterPolicies, the namespaces and the NdbClusters
+	k8sClientset, ndbClientset := getK8sClientset(), getNdbClientset()
+	if k8sClientset == nil || ndbClientset == nil {
+		klog.Fatal("Failed to create the clientsets")
+	}
+
+	// Cache the NdbClusterPolicies to avoid listing them on every request
+	policyLister, err := startNdbClusterPolicyInformer(ndbClientset, wait.NeverStop)
+	if err != nil {
+		klog.Fatal(err)
+	}
+
 	// init the server
 	ws := &http.Server{}
-	initWebhookServer(ws)
+	initWebhookServer(ws, newNdbClusterPolicyClient(k8sClientset, policyLister),
+		newNamespaceLabelsClient(k8sClientset), newNdbClusterClient(ndbClientset))
 
 	// Setup TLS certificates
 	setWebhookServerTLSCerts(context.Background(), ws)
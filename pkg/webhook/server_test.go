@@ -1,4 +1,4 @@
-// Copyright (c) 2021, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2021, 2023, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -253,7 +253,7 @@ func Test_probes(t *testing.T) {
 func TestMain(m *testing.M) {
 	// Create and init a webhook server
 	server := &http.Server{}
-	initWebhookServer(server)
+	initWebhookServer(server, nil, nil, nil)
 
 	// Use a channel to wait for server shutdown in the end
 	listenAndServeErr := make(chan error, 1)